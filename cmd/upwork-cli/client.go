@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"gopkg.in/yaml.v3"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/auth"
+)
+
+// clientFlags holds the flags shared by subcommands that talk to the API
+type clientFlags struct {
+	clientID     string
+	clientSecret string
+	orgID        string
+	token        string
+	apiURL       string
+	tokenURL     string
+
+	// stored is the token read from the credentials file and client the
+	// client created with it, so a token refreshed while the command runs
+	// can be saved back
+	stored *oauth2.Token
+	client *upwork.Client
+}
+
+// register adds the client flags to fs
+func (f *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.clientID, "client-id", os.Getenv("UPWORK_CLIENT_ID"), "OAuth2 Client ID")
+	fs.StringVar(&f.clientSecret, "client-secret", os.Getenv("UPWORK_CLIENT_SECRET"), "OAuth2 Client Secret")
+	fs.StringVar(&f.orgID, "org-id", os.Getenv("UPWORK_ORG_ID"), "Organization ID")
+	fs.StringVar(&f.token, "token", os.Getenv("UPWORK_ACCESS_TOKEN"), "OAuth2 access token")
+	fs.StringVar(&f.apiURL, "api-url", upwork.DefaultAPIURL, "GraphQL endpoint")
+	fs.StringVar(&f.tokenURL, "token-url", auth.TokenURL, "OAuth2 token endpoint")
+}
+
+// newClient creates an API client from the flags. Values not given on the
+// command line or in the environment are read from the credentials file
+// written by "upwork-cli login". A stored token is renewed as it nears
+// expiry, and an expired one is refreshed before use; saveRefreshedToken
+// writes the renewed token back.
+func (f *clientFlags) newClient(ctx context.Context) (*upwork.Client, error) {
+	creds, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	useStored := f.token == "" && creds != nil && creds.Token != nil
+	if creds != nil {
+		if f.clientID == "" {
+			f.clientID = creds.ClientID
+		}
+		if f.clientSecret == "" {
+			f.clientSecret = creds.ClientSecret
+		}
+		if f.orgID == "" {
+			f.orgID = creds.OrganizationID
+		}
+	}
+
+	if f.clientID == "" || f.clientSecret == "" {
+		return nil, fmt.Errorf("client ID and secret are required (run \"upwork-cli login\", set UPWORK_CLIENT_ID and UPWORK_CLIENT_SECRET, or use flags)")
+	}
+
+	config := &upwork.Config{
+		ClientID:       f.clientID,
+		ClientSecret:   f.clientSecret,
+		OrganizationID: f.orgID,
+		APIURL:         f.apiURL,
+		TokenURL:       f.tokenURL,
+	}
+	var opts []upwork.Option
+	switch {
+	case f.token != "":
+		config.Token = &oauth2.Token{AccessToken: f.token, TokenType: "Bearer"}
+	case useStored:
+		config.Token = creds.Token
+		// Renewed tokens show up in GetToken, so they can be saved
+		opts = append(opts, upwork.WithAutoRefresh(upwork.DefaultRefreshLeeway))
+	}
+
+	client, err := upwork.NewClient(ctx, config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if useStored {
+		f.stored = creds.Token
+		f.client = client
+
+		if client.IsTokenExpired() && creds.Token.RefreshToken != "" {
+			if _, err := client.RefreshToken(ctx); err != nil {
+				return nil, fmt.Errorf("refreshing stored token (run \"upwork-cli login\" again): %w", err)
+			}
+			if err := f.saveRefreshedToken(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return client, nil
+}
+
+// saveRefreshedToken writes the client's token to the credentials file if
+// it has changed since it was read. Other fields are re-read so changes
+// made by the command, such as "org use", are kept.
+func (f *clientFlags) saveRefreshedToken() error {
+	if f.client == nil {
+		return nil
+	}
+
+	token := f.client.GetToken()
+	if token == nil || token.AccessToken == f.stored.AccessToken {
+		return nil
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	if creds == nil {
+		creds = &credentials{ClientID: f.clientID, ClientSecret: f.clientSecret}
+	}
+	creds.Token = token
+
+	if err := saveCredentials(creds); err != nil {
+		return fmt.Errorf("saving refreshed token: %w", err)
+	}
+	f.stored = token
+	return nil
+}
+
+// parseArgs parses flags that may appear before or after positional
+// arguments and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// writeJSON writes v to w as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeYAML writes v to w as YAML. Values are converted through JSON first
+// so field names match the JSON output.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// upperEnum normalizes a CLI value such as "fixed-price" to the API enum form
+func upperEnum(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+)
+
+// command is a node in the CLI command tree. Leaf commands set Run; group
+// commands set Subcommands.
+type command struct {
+	// Name is the word that selects the command
+	Name string
+
+	// Args describes the positional arguments, e.g. "<id>"
+	Args string
+
+	// Short is a one-line description shown in help output
+	Short string
+
+	// Flags registers command-specific flags
+	Flags func(fs *flag.FlagSet)
+
+	// Run executes the command with the remaining positional arguments
+	Run func(ctx context.Context, e *env, args []string) error
+
+	// Subcommands are the children of a group command
+	Subcommands []*command
+}
+
+// find returns the subcommand with the given name
+func (c *command) find(name string) *command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// flagSet builds the flag set for the command, including the global flags
+func (c *command) flagSet(path string, e *env) *flag.FlagSet {
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	e.register(fs)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	return fs
+}
+
+// Output formats accepted by --output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// env carries the global flags and I/O shared by every command
+type env struct {
+	clientFlags
+
+	// output is the --output format
+	output string
+
+	// jsonOutput is the legacy --json flag, equivalent to --output json
+	jsonOutput bool
+
+	stdout io.Writer
+	stderr io.Writer
+
+	// client, when set, is used by commands instead of a client created
+	// from the flags
+	client *upwork.Client
+}
+
+// register adds the global flags to fs
+func (e *env) register(fs *flag.FlagSet) {
+	e.clientFlags.register(fs)
+	fs.StringVar(&e.output, "output", outputTable, "Output format (table, json, yaml)")
+	fs.BoolVar(&e.jsonOutput, "json", false, "Shorthand for --output json")
+}
+
+// newClient returns the preset client, if any, or creates one from the flags
+func (e *env) newClient(ctx context.Context) (*upwork.Client, error) {
+	if e.client != nil {
+		return e.client, nil
+	}
+	return e.clientFlags.newClient(ctx)
+}
+
+// validate checks the global flags after parsing
+func (e *env) validate() error {
+	if e.jsonOutput {
+		e.output = outputJSON
+	}
+
+	switch e.output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return usageErrorf("unknown output format %q (want table, json or yaml)", e.output)
+	}
+}
+
+// render writes v in the selected output format. table writes the
+// human-readable form to a tabwriter; when nil, tables fall back to YAML.
+func (e *env) render(v interface{}, table func(w io.Writer)) error {
+	switch e.output {
+	case outputJSON:
+		return writeJSON(e.stdout, v)
+	case outputYAML:
+		return writeYAML(e.stdout, v)
+	}
+
+	if table == nil {
+		return writeYAML(e.stdout, v)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// usageError reports an invalid invocation; the command's help is printed
+// and the process exits with status 2
+type usageError struct {
+	msg string
+}
+
+// Error returns the error message
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usageErrorf creates a usageError
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// execute resolves args against the command tree, parses flags into e and
+// runs the selected command, returning the process exit code
+func execute(root *command, args []string, e *env) int {
+
+	cmd := root
+	path := []string{root.Name}
+	for len(args) > 0 && len(cmd.Subcommands) > 0 {
+		sub := cmd.find(args[0])
+		if sub == nil {
+			break
+		}
+		cmd = sub
+		path = append(path, sub.Name)
+		args = args[1:]
+	}
+
+	name := strings.Join(path, " ")
+	fs := cmd.flagSet(name, e)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() { printHelp(e.stderr, cmd, name, fs) }
+
+	if cmd.Run == nil {
+		if len(args) > 0 && args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			fmt.Fprintf(e.stderr, "Error: unknown command %q\n\n", args[0])
+			fs.Usage()
+			return 2
+		}
+		printHelp(e.stdout, cmd, name, fs)
+		return 0
+	}
+
+	positional, err := parseArgs(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	if err := e.validate(); err != nil {
+		return reportError(e, fs, err)
+	}
+
+	err = cmd.Run(context.Background(), e, positional)
+	if saveErr := e.saveRefreshedToken(); err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return reportError(e, fs, err)
+	}
+
+	return 0
+}
+
+// reportError prints err and returns the matching exit code
+func reportError(e *env, fs *flag.FlagSet, err error) int {
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(e.stderr, "Error: %v\n\n", err)
+		fs.Usage()
+		return 2
+	}
+
+	fmt.Fprintf(e.stderr, "Error: %v\n", err)
+	return 1
+}
+
+// printHelp writes usage for cmd
+func printHelp(w io.Writer, cmd *command, name string, fs *flag.FlagSet) {
+	synopsis := name
+	if len(cmd.Subcommands) > 0 {
+		synopsis += " <command>"
+	} else {
+		synopsis += " [flags]"
+		if cmd.Args != "" {
+			synopsis += " " + cmd.Args
+		}
+	}
+	fmt.Fprintf(w, "Usage: %s\n", synopsis)
+
+	if cmd.Short != "" {
+		fmt.Fprintf(w, "\n%s\n", cmd.Short)
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range cmd.Subcommands {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Short)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "\nRun \"%s <command> --help\" for more information.\n", name)
+		return
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
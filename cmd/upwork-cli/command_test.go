@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// greeting is the value rendered by the test command
+type greeting struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// testCommand returns a small command tree exercising the framework
+func testCommand() *command {
+	var loud bool
+	return &command{
+		Name:  "tool",
+		Short: "A test tool",
+		Subcommands: []*command{
+			{
+				Name:  "greet",
+				Args:  "<name>",
+				Short: "Greet someone",
+				Flags: func(fs *flag.FlagSet) {
+					fs.BoolVar(&loud, "loud", false, "Shout the greeting")
+				},
+				Run: func(ctx context.Context, e *env, args []string) error {
+					if len(args) != 1 {
+						return usageErrorf("expected a name")
+					}
+					g := greeting{Name: args[0], Message: "hello " + args[0]}
+					if loud {
+						g.Message = strings.ToUpper(g.Message)
+					}
+					return e.render(g, func(w io.Writer) {
+						fmt.Fprintln(w, "NAME\tMESSAGE")
+						fmt.Fprintf(w, "%s\t%s\n", g.Name, g.Message)
+					})
+				},
+			},
+			{
+				Name:  "group",
+				Short: "A command group",
+				Subcommands: []*command{
+					{
+						Name:  "fail",
+						Short: "Always fails",
+						Run: func(ctx context.Context, e *env, args []string) error {
+							return errors.New("boom")
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr []string
+	}{
+		{
+			name:       "table output",
+			args:       []string{"greet", "ada"},
+			wantStdout: "NAME  MESSAGE\nada   hello ada\n",
+		},
+		{
+			name:       "flags after positional arguments",
+			args:       []string{"greet", "ada", "--loud"},
+			wantStdout: "NAME  MESSAGE\nada   HELLO ADA\n",
+		},
+		{
+			name:       "json output",
+			args:       []string{"greet", "--output", "json", "ada"},
+			wantStdout: "{\n  \"name\": \"ada\",\n  \"message\": \"hello ada\"\n}\n",
+		},
+		{
+			name:       "legacy json flag",
+			args:       []string{"greet", "--json", "ada"},
+			wantStdout: "{\n  \"name\": \"ada\",\n  \"message\": \"hello ada\"\n}\n",
+		},
+		{
+			name:       "yaml output",
+			args:       []string{"greet", "--output=yaml", "ada"},
+			wantStdout: "message: hello ada\nname: ada\n",
+		},
+		{
+			name:       "group help",
+			args:       nil,
+			wantStdout: "Usage: tool <command>\n\nA test tool\n\nCommands:\n  greet  Greet someone\n  group  A command group\n\nRun \"tool <command> --help\" for more information.\n",
+		},
+		{
+			name:       "nested group help",
+			args:       []string{"group", "help"},
+			wantStdout: "Usage: tool group <command>\n\nA command group\n\nCommands:\n  fail  Always fails\n\nRun \"tool group <command> --help\" for more information.\n",
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"wave"},
+			wantCode:   2,
+			wantStderr: []string{`Error: unknown command "wave"`, "Usage: tool <command>"},
+		},
+		{
+			name:       "unknown flag",
+			args:       []string{"greet", "--quiet", "ada"},
+			wantCode:   2,
+			wantStderr: []string{"flag provided but not defined: -quiet", "Usage: tool greet [flags] <name>"},
+		},
+		{
+			name:       "unknown output format",
+			args:       []string{"greet", "--output", "xml", "ada"},
+			wantCode:   2,
+			wantStderr: []string{`Error: unknown output format "xml"`, "Usage: tool greet [flags] <name>", "-loud"},
+		},
+		{
+			name:       "usage error from the command",
+			args:       []string{"greet"},
+			wantCode:   2,
+			wantStderr: []string{"Error: expected a name", "Usage: tool greet [flags] <name>"},
+		},
+		{
+			name:       "command error",
+			args:       []string{"group", "fail"},
+			wantCode:   1,
+			wantStderr: []string{"Error: boom\n"},
+		},
+		{
+			name:     "command help",
+			args:     []string{"greet", "--help"},
+			wantCode: 0,
+			wantStderr: []string{
+				"Usage: tool greet [flags] <name>",
+				"Greet someone",
+				"-loud",
+				"Output format (table, json, yaml)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := execute(testCommand(), tt.args, &env{stdout: &stdout, stderr: &stderr})
+
+			assert.Equal(t, tt.wantCode, code, "stderr: %s", stderr.String())
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+			if tt.wantStderr == nil {
+				assert.Empty(t, stderr.String())
+			}
+			if code == 1 {
+				// Failures that are not usage errors skip the help text
+				assert.NotContains(t, stderr.String(), "Usage:")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// completionCommand returns the "completion" command. root is called lazily
+// so the generated script covers the full command tree.
+func completionCommand(root func() *command) *command {
+	return &command{
+		Name:  "completion",
+		Args:  "<bash|zsh>",
+		Short: "Generate a shell completion script",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected a shell (bash or zsh)")
+			}
+
+			switch args[0] {
+			case "bash":
+				return writeBashCompletion(e.stdout, root())
+			case "zsh":
+				// zsh runs the bash script through its compatibility layer
+				fmt.Fprintln(e.stdout, "autoload -U +X bashcompinit && bashcompinit")
+				return writeBashCompletion(e.stdout, root())
+			default:
+				return usageErrorf("unsupported shell %q (want bash or zsh)", args[0])
+			}
+		},
+	}
+}
+
+// completionEntry holds the completions offered after a command path
+type completionEntry struct {
+	path  string
+	words []string
+}
+
+// completionEntries walks the command tree and returns one entry per command
+func completionEntries(cmd *command, path []string) []completionEntry {
+	var words []string
+	for _, sub := range cmd.Subcommands {
+		words = append(words, sub.Name)
+	}
+	if cmd.Run != nil {
+		cmd.flagSet(cmd.Name, &env{}).VisitAll(func(f *flag.Flag) {
+			words = append(words, "--"+f.Name)
+		})
+	}
+
+	entries := []completionEntry{{path: strings.Join(path, " "), words: words}}
+	for _, sub := range cmd.Subcommands {
+		entries = append(entries, completionEntries(sub, append(path[:len(path):len(path)], sub.Name))...)
+	}
+	return entries
+}
+
+// writeBashCompletion writes a bash completion function for root
+func writeBashCompletion(w io.Writer, root *command) error {
+	entries := completionEntries(root, nil)
+
+	// Match the most specific command path first
+	sort.SliceStable(entries, func(i, j int) bool {
+		return len(strings.Fields(entries[i].path)) > len(strings.Fields(entries[j].path))
+	})
+
+	fn := "_" + strings.ReplaceAll(root.Name, "-", "_")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", root.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur prev words path i\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	b.WriteString("    if [[ \"$prev\" == \"--output\" ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s %s %s\" -- \"$cur\"))\n", outputTable, outputJSON, outputYAML)
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n\n")
+	b.WriteString("    # Build the command path from the words before the cursor\n")
+	b.WriteString("    path=\"\"\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) path=\"${path:+$path }${COMP_WORDS[i]}\" ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n\n")
+	b.WriteString("    case \"$path\" in\n")
+	for _, entry := range entries {
+		pattern := "*"
+		if entry.path != "" {
+			pattern = "\"" + entry.path + "\"*"
+		}
+		fmt.Fprintf(&b, "        %s)\n", pattern)
+		fmt.Fprintf(&b, "            words=\"%s\" ;;\n", strings.Join(entry.words, " "))
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, root.Name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
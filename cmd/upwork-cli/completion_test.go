@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBashCompletion(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeBashCompletion(&buf, testCommand()))
+
+	want, err := os.ReadFile("testdata/completion.bash")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), buf.String())
+}
+
+func TestCompletionCommand(t *testing.T) {
+	var bash, zsh, stderr bytes.Buffer
+	require.Equal(t, 0, execute(rootCommand(), []string{"completion", "bash"}, &env{stdout: &bash, stderr: &stderr}))
+	assert.Contains(t, bash.String(), `"contracts list"*)`)
+	assert.Contains(t, bash.String(), "complete -F _upwork_cli upwork-cli\n")
+
+	require.Equal(t, 0, execute(rootCommand(), []string{"completion", "zsh"}, &env{stdout: &zsh, stderr: &stderr}))
+	assert.Equal(t, "autoload -U +X bashcompinit && bashcompinit\n"+bash.String(), zsh.String())
+
+	assert.Equal(t, 2, execute(rootCommand(), []string{"completion", "fish"}, &env{stdout: &bash, stderr: &stderr}))
+	assert.Contains(t, stderr.String(), `unsupported shell "fish"`)
+}
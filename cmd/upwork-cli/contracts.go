@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// contractsCommand returns the "contracts" command group
+func contractsCommand() *command {
+	return &command{
+		Name:  "contracts",
+		Short: "Manage contracts",
+		Subcommands: []*command{
+			contractsListCommand(),
+			contractsShowCommand(),
+			contractActionCommand("pause", "Pause a contract", "paused"),
+			contractActionCommand("restart", "Restart a paused contract", "restarted"),
+			contractsEndCommand(),
+		},
+	}
+}
+
+func contractsListCommand() *command {
+	var (
+		status string
+		typ    string
+		limit  int
+	)
+
+	return &command{
+		Name:  "list",
+		Short: "List contracts",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&status, "status", "", "Filter by status (active, paused, ended, suspended)")
+			fs.StringVar(&typ, "type", "", "Filter by contract type (hourly, fixed-price)")
+			fs.IntVar(&limit, "limit", 50, "Maximum number of contracts")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 0 {
+				return usageErrorf("unexpected arguments")
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			input := services.ListContractsInput{
+				Pagination: &models.PaginationInput{First: limit},
+			}
+			if status != "" || typ != "" {
+				input.Filter = &services.ContractFilter{}
+				if status != "" {
+					input.Filter.Status = []services.ContractStatus{services.ContractStatus(upperEnum(status))}
+				}
+				if typ != "" {
+					input.Filter.ContractType = []services.ContractType{services.ContractType(upperEnum(typ))}
+				}
+			}
+
+			list, err := client.Contracts.ListContracts(ctx, input)
+			if err != nil {
+				return fmt.Errorf("listing contracts: %w", err)
+			}
+
+			contracts := make([]services.Contract, 0, len(list.Edges))
+			for _, edge := range list.Edges {
+				contracts = append(contracts, edge.Node)
+			}
+
+			return e.render(contracts, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tTITLE\tTYPE\tSTATUS\tRATE\tFREELANCER")
+				for _, c := range contracts {
+					rate := "-"
+					if c.HourlyChargeRate != nil {
+						rate = c.HourlyChargeRate.String()
+					}
+					freelancer := "-"
+					if c.Freelancer != nil {
+						freelancer = c.Freelancer.User.Name
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.ID, c.Title, c.ContractType, c.Status, rate, freelancer)
+				}
+				if list.TotalCount > len(contracts) {
+					fmt.Fprintf(w, "\nShowing %d of %d contracts\n", len(contracts), list.TotalCount)
+				}
+			})
+		},
+	}
+}
+
+func contractsShowCommand() *command {
+	return &command{
+		Name:  "show",
+		Args:  "<id>",
+		Short: "Show contract details",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected a contract ID")
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			c, err := client.Contracts.GetContract(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("getting contract: %w", err)
+			}
+
+			return e.render(c, func(w io.Writer) {
+				fmt.Fprintf(w, "ID:\t%s\n", c.ID)
+				fmt.Fprintf(w, "Title:\t%s\n", c.Title)
+				fmt.Fprintf(w, "Type:\t%s\n", c.ContractType)
+				fmt.Fprintf(w, "Status:\t%s\n", c.Status)
+				fmt.Fprintf(w, "Started:\t%s\n", displayDateTime(c.StartDateTime))
+				if c.EndDateTime != nil {
+					fmt.Fprintf(w, "Ended:\t%s\n", displayDateTime(*c.EndDateTime))
+				}
+				if c.HourlyChargeRate != nil {
+					fmt.Fprintf(w, "Hourly rate:\t%s\n", c.HourlyChargeRate)
+				}
+				if c.WeeklyHoursLimit != nil {
+					fmt.Fprintf(w, "Weekly limit:\t%d hours\n", *c.WeeklyHoursLimit)
+				}
+				if c.Freelancer != nil {
+					fmt.Fprintf(w, "Freelancer:\t%s (%s)\n", c.Freelancer.User.Name, c.Freelancer.CountryDetails.Name)
+				}
+				if c.Job != nil {
+					fmt.Fprintf(w, "Job:\t%s\n", c.Job.Content.Title)
+				}
+			})
+		},
+	}
+}
+
+// contractAction is the result printed by contract state changes
+type contractAction struct {
+	ContractID string `json:"contractId"`
+	Status     string `json:"status"`
+}
+
+// contractActionCommand builds the pause and restart commands
+func contractActionCommand(name, short, done string) *command {
+	var reason, message, effectiveDate string
+
+	return &command{
+		Name:  name,
+		Args:  "<id>",
+		Short: short,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&reason, "reason", "", "Reason shown on the contract")
+			fs.StringVar(&message, "message", "", "Message to the other party")
+			fs.StringVar(&effectiveDate, "effective-date", "", "Apply on this date (YYYY-MM-DD) instead of now")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected a contract ID")
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			opts := []services.ContractActionOption{
+				services.WithActionMessage(message),
+				services.WithEffectiveDate(effectiveDate),
+			}
+			switch name {
+			case "pause":
+				err = client.Contracts.PauseContract(ctx, args[0], reason, opts...)
+			case "restart":
+				err = client.Contracts.RestartContract(ctx, args[0], append(opts, services.WithActionReason(reason))...)
+			}
+			if err != nil {
+				return fmt.Errorf("%s contract: %w", name, err)
+			}
+
+			result := contractAction{ContractID: args[0], Status: done}
+			return e.render(result, func(w io.Writer) {
+				fmt.Fprintf(w, "Contract %s %s\n", result.ContractID, result.Status)
+			})
+		},
+	}
+}
+
+func contractsEndCommand() *command {
+	var (
+		reason   string
+		message  string
+		endingAs string
+	)
+
+	return &command{
+		Name:  "end",
+		Args:  "<id>",
+		Short: "End a contract",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&reason, "reason", "", "Reason ID or alias, e.g. API_REAS_JOB_COMPLETED_SUCCESSFULLY (required)")
+			fs.StringVar(&message, "message", "", "Message to the other party")
+			fs.StringVar(&endingAs, "as", "client", "End the contract as client or freelancer")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected a contract ID")
+			}
+			if reason == "" {
+				return usageErrorf("--reason is required")
+			}
+			if endingAs != "client" && endingAs != "freelancer" {
+				return usageErrorf("--as must be client or freelancer")
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			input := services.EndContractInput{
+				ContractID: args[0],
+				Reason:     reason,
+				Message:    message,
+			}
+			if endingAs == "client" {
+				err = client.Contracts.EndContractAsClientWithReason(ctx, input)
+			} else {
+				err = client.Contracts.EndContractAsFreelancerWithReason(ctx, input)
+			}
+			if err != nil {
+				return fmt.Errorf("ending contract: %w", err)
+			}
+
+			result := contractAction{ContractID: args[0], Status: "ended"}
+			return e.render(result, func(w io.Writer) {
+				fmt.Fprintf(w, "Contract %s %s\n", result.ContractID, result.Status)
+			})
+		},
+	}
+}
+
+// displayDateTime prefers the API's display form of a date/time
+func displayDateTime(d models.DateTime) string {
+	if d.DisplayValue != "" {
+		return d.DisplayValue
+	}
+	return d.RawValue
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// contractSummary is the part of a rendered contract checked by the tests
+type contractSummary struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// contractStatus returns the fake API's status for contract id
+func contractStatus(t *testing.T, srv *upworktest.Server, id string) services.ContractStatus {
+	t.Helper()
+
+	for _, c := range srv.Fixtures().Contracts {
+		if string(c.ID) == id {
+			return c.Status
+		}
+	}
+	t.Fatalf("no contract %s", id)
+	return ""
+}
+
+func TestContractsCommands(t *testing.T) {
+	runCLITests(t, []cliTest{
+		{
+			name: "list",
+			args: []string{"contracts", "list"},
+			table: "ID          TITLE                TYPE         STATUS  RATE       FREELANCER\n" +
+				"contract-1  Backend development  HOURLY       ACTIVE  50.00 USD  Fiona Freelancer\n" +
+				"contract-2  Logo design          FIXED_PRICE  PAUSED  -          Fiona Freelancer\n",
+			want: []contractSummary{
+				{ID: "contract-1", Title: "Backend development", Status: "ACTIVE"},
+				{ID: "contract-2", Title: "Logo design", Status: "PAUSED"},
+			},
+		},
+		{
+			name: "list filtered and limited",
+			args: []string{"contracts", "list", "--status", "paused", "--limit", "1"},
+			table: "ID          TITLE        TYPE         STATUS  RATE  FREELANCER\n" +
+				"contract-2  Logo design  FIXED_PRICE  PAUSED  -     Fiona Freelancer\n",
+			want: []contractSummary{
+				{ID: "contract-2", Title: "Logo design", Status: "PAUSED"},
+			},
+		},
+		{
+			name: "show",
+			args: []string{"contracts", "show", "contract-1"},
+			table: "ID:            contract-1\n" +
+				"Title:         Backend development\n" +
+				"Type:          HOURLY\n" +
+				"Status:        ACTIVE\n" +
+				"Started:       2024-01-02T10:00:00Z\n" +
+				"Hourly rate:   50.00 USD\n" +
+				"Weekly limit:  40 hours\n" +
+				"Freelancer:    Fiona Freelancer ()\n",
+			want: contractSummary{ID: "contract-1", Title: "Backend development", Status: "ACTIVE"},
+		},
+		{
+			name:  "pause",
+			args:  []string{"contracts", "pause", "contract-1", "--reason", "Waiting on assets"},
+			table: "Contract contract-1 paused\n",
+			want:  contractAction{ContractID: "contract-1", Status: "paused"},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				assert.Equal(t, services.ContractStatusPaused, contractStatus(t, srv, "contract-1"))
+				requests := srv.Requests()
+				require.Len(t, requests, 1)
+				assert.Equal(t, "Waiting on assets", requests[0].Variables["reason"])
+			},
+		},
+		{
+			name:  "restart",
+			args:  []string{"contracts", "restart", "contract-2"},
+			table: "Contract contract-2 restarted\n",
+			want:  contractAction{ContractID: "contract-2", Status: "restarted"},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				assert.Equal(t, services.ContractStatusActive, contractStatus(t, srv, "contract-2"))
+			},
+		},
+		{
+			name: "end by reason alias",
+			args: []string{"contracts", "end", "contract-1", "--reason", "API_REAS_JOB_COMPLETED_SUCCESSFULLY"},
+			setup: func(t *testing.T, srv *upworktest.Server) {
+				srv.Handle("reasons", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+					return []services.Reason{
+						{ID: "reason-1", Reason: "Job completed successfully", Alias: "API_REAS_JOB_COMPLETED_SUCCESSFULLY"},
+					}, nil
+				})
+			},
+			table: "Contract contract-1 ended\n",
+			want:  contractAction{ContractID: "contract-1", Status: "ended"},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				assert.Equal(t, services.ContractStatusEnded, contractStatus(t, srv, "contract-1"))
+				requests := srv.Requests()
+				require.Len(t, requests, 2)
+				assert.Equal(t, "reason-1", requests[1].Variables["input"].(map[string]interface{})["reason"])
+			},
+		},
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// useCredentialsFile points the CLI at a credentials file in a temporary
+// directory and clears the environment that would override it
+func useCredentialsFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "upwork", "credentials.json")
+	t.Setenv("UPWORK_CREDENTIALS", path)
+	for _, name := range []string{"UPWORK_CLIENT_ID", "UPWORK_CLIENT_SECRET", "UPWORK_ORG_ID", "UPWORK_ACCESS_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	return path
+}
+
+func assertCredentialsMode(t *testing.T, path string) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestSaveCredentials(t *testing.T) {
+	path := useCredentialsFile(t)
+
+	creds, err := loadCredentials()
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	want := &credentials{
+		ClientID:       "client-id",
+		ClientSecret:   "client-secret",
+		Token:          &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)},
+		OrganizationID: "org-1",
+	}
+	require.NoError(t, saveCredentials(want))
+	assertCredentialsMode(t, path)
+
+	creds, err = loadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, want.ClientID, creds.ClientID)
+	assert.Equal(t, want.OrganizationID, creds.OrganizationID)
+	assert.Equal(t, want.Token.AccessToken, creds.Token.AccessToken)
+	assert.Equal(t, want.Token.RefreshToken, creds.Token.RefreshToken)
+	assert.True(t, want.Token.Expiry.Equal(creds.Token.Expiry))
+
+	// Overwriting a file with looser permissions tightens them
+	require.NoError(t, os.Chmod(path, 0o644))
+	require.NoError(t, saveCredentials(want))
+	assertCredentialsMode(t, path)
+}
+
+// storeLogin writes a login for the fake API with the given token
+func storeLogin(t *testing.T, token *oauth2.Token) {
+	t.Helper()
+	require.NoError(t, saveCredentials(&credentials{
+		ClientID:     upworktest.TestClientID,
+		ClientSecret: upworktest.TestClientSecret,
+		Token:        token,
+	}))
+}
+
+func TestExpiredStoredTokenIsRefreshedAndSaved(t *testing.T) {
+	path := useCredentialsFile(t)
+	srv := upworktest.NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	storeLogin(t, &oauth2.Token{
+		AccessToken:  "expired",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Hour),
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := execute(rootCommand(), []string{"org", "list", "--api-url", srv.URL, "--token-url", srv.TokenURL()},
+		&env{stdout: &stdout, stderr: &stderr})
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Test Organization")
+
+	creds, err := loadCredentials()
+	require.NoError(t, err)
+	assert.NotEqual(t, "expired", creds.Token.AccessToken)
+	assert.True(t, creds.Token.Expiry.After(time.Now()))
+	assert.Equal(t, upworktest.TestClientID, creds.ClientID)
+	assertCredentialsMode(t, path)
+}
+
+func TestTokenRefreshedDuringRunIsSaved(t *testing.T) {
+	useCredentialsFile(t)
+	srv := upworktest.NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	storeLogin(t, &oauth2.Token{
+		AccessToken:  "stored",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(time.Hour),
+	})
+
+	// The command changes the credentials file and the client renews its
+	// token before the command finishes
+	var refreshed *oauth2.Token
+	root := &command{
+		Name: "tool",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			creds, err := loadCredentials()
+			if err != nil {
+				return err
+			}
+			creds.OrganizationID = "org-2"
+			if err := saveCredentials(creds); err != nil {
+				return err
+			}
+
+			refreshed, err = client.RefreshToken(ctx)
+			return err
+		},
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := execute(root, []string{"--api-url", srv.URL, "--token-url", srv.TokenURL()}, &env{stdout: &stdout, stderr: &stderr})
+	require.Equal(t, 0, code, stderr.String())
+	require.NotNil(t, refreshed)
+
+	creds, err := loadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, refreshed.AccessToken, creds.Token.AccessToken)
+	assert.Equal(t, "org-2", creds.OrganizationID)
+	assert.Equal(t, 1, srv.TokensIssued())
+}
+
+func TestUnchangedTokenIsNotSaved(t *testing.T) {
+	path := useCredentialsFile(t)
+	srv := upworktest.NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	storeLogin(t, &oauth2.Token{AccessToken: upworktest.TestAccessToken, Expiry: time.Now().Add(time.Hour)})
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	code := execute(rootCommand(), []string{"org", "list", "--api-url", srv.URL, "--token-url", srv.TokenURL()},
+		&env{stdout: &stdout, stderr: &stderr})
+	require.Equal(t, 0, code, stderr.String())
+
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, before.ModTime(), after.ModTime())
+	assert.Zero(t, srv.TokensIssued())
+}
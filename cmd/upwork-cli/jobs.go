@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// jobsCommand returns the "jobs" command group
+func jobsCommand() *command {
+	return &command{
+		Name:  "jobs",
+		Short: "Search, view, post and apply jobs",
+		Subcommands: []*command{
+			jobsSearchCommand(),
+			jobsShowCommand(),
+			jobsPostCommand(),
+			jobsApplyCommand(),
+		},
+	}
+}
+
+func jobsSearchCommand() *command {
+	var (
+		typ       string
+		days      int
+		limit     int
+		sort      string
+		asc       bool
+		verified  bool
+		minHires  int
+		minBudget int
+		maxBudget int
+	)
+
+	return &command{
+		Name:  "search",
+		Args:  "<query>",
+		Short: "Search marketplace jobs",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&typ, "type", "", "Job type (hourly, fixed-price)")
+			fs.IntVar(&days, "days", 0, "Only jobs posted within this many days")
+			fs.IntVar(&limit, "limit", 20, "Maximum number of jobs")
+			fs.StringVar(&sort, "sort", "", "Sort by recency, relevance or client-total-charge")
+			fs.BoolVar(&asc, "asc", false, "Sort in ascending order")
+			fs.BoolVar(&verified, "verified-payment", false, "Only clients with a verified payment method")
+			fs.IntVar(&minHires, "min-hires", 0, "Only clients with at least this many hires")
+			fs.IntVar(&minBudget, "min-budget", 0, "Minimum fixed-price budget")
+			fs.IntVar(&maxBudget, "max-budget", 0, "Maximum fixed-price budget")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) == 0 {
+				return usageErrorf("expected a search query")
+			}
+
+			filter := services.MarketplaceJobFilter{
+				SearchExpression:    strings.Join(args, " "),
+				DaysPosted:          days,
+				Pagination:          &models.PaginationInput{First: limit},
+				SortAttribute:       services.JobSortField(upperEnum(sort)),
+				Ascending:           asc,
+				VerifiedPaymentOnly: verified,
+			}
+			if typ != "" {
+				filter.JobType = services.ContractType(upperEnum(typ))
+			}
+			if minBudget > 0 || maxBudget > 0 {
+				filter.BudgetRange = &services.IntRange{RangeStart: minBudget, RangeEnd: maxBudget}
+			}
+			if minHires > 0 {
+				filter.ClientHires = &services.IntRange{RangeStart: minHires}
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			result, err := client.Jobs.SearchJobs(ctx, filter)
+			if err != nil {
+				return fmt.Errorf("searching jobs: %w", err)
+			}
+
+			jobs := result.Jobs()
+			return e.render(jobs, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tTITLE\tCLIENT COUNTRY\tPOSTED")
+				for _, job := range jobs {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.ID, job.Title, job.Client.Location.Country, displayDateTime(job.CreatedDateTime))
+				}
+				if result.TotalCount > len(jobs) {
+					fmt.Fprintf(w, "\nShowing %d of %d jobs\n", len(jobs), result.TotalCount)
+				}
+			})
+		},
+	}
+}
+
+func jobsShowCommand() *command {
+	return &command{
+		Name:  "show",
+		Args:  "<id>",
+		Short: "Show a job posting",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected a job ID")
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			job, err := client.Jobs.GetJobPosting(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("getting job: %w", err)
+			}
+
+			return e.render(job, func(w io.Writer) { printJob(w, job) })
+		},
+	}
+}
+
+func jobsPostCommand() *command {
+	var file string
+
+	return &command{
+		Name:  "post",
+		Short: "Create a job posting from a YAML or JSON definition",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&file, "file", "", "Job definition in YAML or JSON (- for stdin)")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if file == "" {
+				return usageErrorf("--file is required")
+			}
+
+			input, err := loadJobPosting(file)
+			if err != nil {
+				return fmt.Errorf("loading job definition: %w", err)
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			job, err := client.Jobs.CreateJobPosting(ctx, *input)
+			if err != nil {
+				return fmt.Errorf("creating job: %w", err)
+			}
+
+			return e.render(job, func(w io.Writer) {
+				fmt.Fprintf(w, "Job %s created (%s)\n", job.ID, job.Info.Status)
+			})
+		},
+	}
+}
+
+// loadJobPosting reads a job definition from a YAML or JSON file. Keys use
+// the same names as the JSON form of CreateJobPostingInput.
+func loadJobPosting(path string) (*services.CreateJobPostingInput, error) {
+	var input services.CreateJobPostingInput
+	if err := loadDefinition(path, &input); err != nil {
+		return nil, err
+	}
+
+	normalizeJobPosting(&input)
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &input, nil
+}
+
+// loadDefinition decodes a YAML or JSON file (- for stdin) into v,
+// rejecting keys v does not have
+func loadDefinition(path string, v interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// YAML is a superset of JSON, so both formats decode here. Round-trip
+	// through JSON so the struct's json tags define the accepted keys.
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(encoded)))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// normalizeJobPosting converts CLI-style values such as "fixed-price" to
+// API enums
+func normalizeJobPosting(input *services.CreateJobPostingInput) {
+	if input.ContractType != "" {
+		input.ContractType = services.ContractType(upperEnum(string(input.ContractType)))
+	}
+}
+
+// printJob writes the details of a job posting
+func printJob(w io.Writer, job *services.JobPosting) {
+	fmt.Fprintf(w, "ID:\t%s\n", job.ID)
+	fmt.Fprintf(w, "Title:\t%s\n", job.Content.Title)
+	fmt.Fprintf(w, "Status:\t%s\n", job.Info.Status)
+	fmt.Fprintf(w, "Type:\t%s\n", job.ContractTerms.ContractType)
+	if job.Info.HourlyBudgetMin != nil && job.Info.HourlyBudgetMax != nil {
+		fmt.Fprintf(w, "Hourly budget:\t%s - %s\n", job.Info.HourlyBudgetMin, job.Info.HourlyBudgetMax)
+	}
+	fmt.Fprintf(w, "Category:\t%s / %s\n", job.Classification.Category.Name, job.Classification.SubCategory.Name)
+	if len(job.Classification.Skills) > 0 {
+		skills := make([]string, 0, len(job.Classification.Skills))
+		for _, skill := range job.Classification.Skills {
+			skills = append(skills, skill.PrettyName)
+		}
+		fmt.Fprintf(w, "Skills:\t%s\n", strings.Join(skills, ", "))
+	}
+	fmt.Fprintf(w, "Posted:\t%s\n", displayDateTime(job.Info.AuditTime.CreatedDateTime))
+
+	if description := job.Content.Description(); description != "" {
+		fmt.Fprintf(w, "\n%s\n", description)
+	}
+}
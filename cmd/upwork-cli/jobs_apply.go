@@ -0,0 +1,445 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// jobSpec is a desired job posting, identified across runs by ExternalID
+type jobSpec struct {
+	ExternalID string `json:"externalId"`
+	services.CreateJobPostingInput
+}
+
+// jobsFile is the file read by "jobs apply"
+type jobsFile struct {
+	Jobs []jobSpec `json:"jobs"`
+}
+
+// refStyle is where a posting keeps its external ID
+type refStyle string
+
+const (
+	// refDescription ends the description with a "[ref:<id>]" line
+	refDescription refStyle = "description"
+	// refTitle starts the title with "[<id>] "
+	refTitle refStyle = "title"
+)
+
+var (
+	externalIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	descriptionRef    = regexp.MustCompile(`\[ref:([A-Za-z0-9][A-Za-z0-9._-]*)\]\s*$`)
+	titleRef          = regexp.MustCompile(`^\[([A-Za-z0-9][A-Za-z0-9._-]*)\] `)
+)
+
+// posting returns the posting of spec with its external ID embedded
+func (r refStyle) posting(spec jobSpec) services.CreateJobPostingInput {
+	input := spec.CreateJobPostingInput
+	if r == refTitle {
+		input.Title = "[" + spec.ExternalID + "] " + input.Title
+	} else {
+		input.Description = strings.TrimRight(input.Description, "\n") + "\n\n[ref:" + spec.ExternalID + "]"
+	}
+	return input
+}
+
+// externalID returns the external ID embedded in a posting, or "" if it is
+// not managed
+func (r refStyle) externalID(job services.JobPosting) string {
+	var m []string
+	if r == refTitle {
+		m = titleRef.FindStringSubmatch(job.Content.Title)
+	} else {
+		m = descriptionRef.FindStringSubmatch(job.Content.Description())
+	}
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// jobAction is what apply does to bring one job in line
+type jobAction string
+
+const (
+	jobCreate  jobAction = "create"
+	jobUpdate  jobAction = "update"
+	jobReplace jobAction = "replace"
+	jobClose   jobAction = "close"
+	jobNoop    jobAction = "none"
+)
+
+// jobChange is one step of a plan
+type jobChange struct {
+	Action     jobAction `json:"action"`
+	ExternalID string    `json:"externalId"`
+	Title      string    `json:"title"`
+	// JobID is the live posting being updated, replaced or closed
+	JobID string `json:"jobId,omitempty"`
+	// Fields are the fields being updated, or that force a replacement
+	Fields []string `json:"fields,omitempty"`
+	// NewJobID is the posting created by an applied create or replace
+	NewJobID string `json:"newJobId,omitempty"`
+	Applied  bool   `json:"applied"`
+
+	create *services.CreateJobPostingInput
+	update *services.UpdateJobPostingInput
+}
+
+func jobsApplyCommand() *command {
+	var (
+		file  string
+		plan  bool
+		ref   string
+		keep  bool
+		limit int
+	)
+
+	return &command{
+		Name:  "apply",
+		Short: "Create, update and close job postings to match a YAML or JSON file",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&file, "file", "", "Desired job postings in YAML or JSON (- for stdin)")
+			fs.StringVar(&file, "f", "", "Shorthand for --file")
+			fs.BoolVar(&plan, "plan", false, "Show the changes without making them")
+			fs.StringVar(&ref, "ref", string(refDescription), "Where postings keep their external ID (description, title)")
+			fs.BoolVar(&keep, "keep-removed", false, "Leave postings removed from the file open instead of closing them")
+			fs.IntVar(&limit, "page-size", 50, "Postings listed per request")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if file == "" {
+				return usageErrorf("--file is required")
+			}
+			style := refStyle(ref)
+			if style != refDescription && style != refTitle {
+				return usageErrorf("unknown --ref %q (want description or title)", ref)
+			}
+
+			specs, err := loadJobSpecs(file)
+			if err != nil {
+				return fmt.Errorf("loading job definitions: %w", err)
+			}
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			live, err := managedJobs(ctx, client, style, limit)
+			if err != nil {
+				return err
+			}
+
+			changes, err := planJobs(specs, live, style, !keep)
+			if err != nil {
+				return err
+			}
+
+			var applyErr error
+			if !plan {
+				applyErr = applyJobs(ctx, client, changes)
+			}
+			if err := e.render(changes, func(w io.Writer) { printJobChanges(w, changes, !plan) }); err != nil {
+				return err
+			}
+			return applyErr
+		},
+	}
+}
+
+// loadJobSpecs reads and validates the desired postings
+func loadJobSpecs(path string) ([]jobSpec, error) {
+	var f jobsFile
+	if err := loadDefinition(path, &f); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(f.Jobs))
+	for i := range f.Jobs {
+		spec := &f.Jobs[i]
+		if !externalIDPattern.MatchString(spec.ExternalID) {
+			return nil, fmt.Errorf("jobs[%d]: externalId %q must be letters, digits, '.', '_' or '-'", i, spec.ExternalID)
+		}
+		if seen[spec.ExternalID] {
+			return nil, fmt.Errorf("jobs[%d]: externalId %q is used twice", i, spec.ExternalID)
+		}
+		seen[spec.ExternalID] = true
+
+		normalizeJobPosting(&spec.CreateJobPostingInput)
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("jobs[%d] (%s): %w", i, spec.ExternalID, err)
+		}
+	}
+	return f.Jobs, nil
+}
+
+// managedJobs returns the open and draft postings of the organization that
+// carry an external ID, by ID. Filled and closed postings are no longer
+// managed.
+func managedJobs(ctx context.Context, client *upwork.Client, style refStyle, pageSize int) (map[string][]services.JobPosting, error) {
+	managed := make(map[string][]services.JobPosting)
+	input := services.ListJobsInput{Pagination: &models.PaginationInput{First: pageSize}}
+	for {
+		list, err := client.Jobs.ListJobs(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("listing jobs: %w", err)
+		}
+
+		for _, edge := range list.Edges {
+			job := edge.Node
+			if job.Info.Status != services.JobStatusOpen && job.Info.Status != services.JobStatusDraft {
+				continue
+			}
+			if style == refTitle && style.externalID(job) == "" {
+				continue
+			}
+
+			// Listings hold only the title; the rest is needed to
+			// match by description and to diff
+			full, err := client.Jobs.GetJobPosting(ctx, string(job.ID))
+			if err != nil {
+				return nil, fmt.Errorf("getting job %s: %w", job.ID, err)
+			}
+			if id := style.externalID(*full); id != "" {
+				managed[id] = append(managed[id], *full)
+			}
+		}
+
+		next := list.PageInfo.EndCursor
+		if !list.PageInfo.HasNextPage || next == "" || next == input.Pagination.After {
+			return managed, nil
+		}
+		input.Pagination = &models.PaginationInput{First: pageSize, After: next}
+	}
+}
+
+// planJobs returns the changes that bring the live postings in line with
+// specs, in file order followed by the postings to close
+func planJobs(specs []jobSpec, live map[string][]services.JobPosting, style refStyle, prune bool) ([]jobChange, error) {
+	var changes []jobChange
+	for _, spec := range specs {
+		desired := style.posting(spec)
+		change := jobChange{ExternalID: spec.ExternalID, Title: spec.Title}
+
+		switch jobs := live[spec.ExternalID]; len(jobs) {
+		case 0:
+			change.Action = jobCreate
+			change.create = &desired
+		case 1:
+			change.JobID = string(jobs[0].ID)
+			update, fields, replace := diffJob(desired, jobs[0])
+			switch {
+			case len(replace) > 0:
+				change.Action = jobReplace
+				change.Fields = replace
+				change.create = &desired
+			case update != nil:
+				change.Action = jobUpdate
+				change.Fields = fields
+				change.update = update
+			default:
+				change.Action = jobNoop
+			}
+		default:
+			return nil, fmt.Errorf("external ID %q is on %d open postings (%s); close all but one", spec.ExternalID, len(jobs), jobIDs(jobs))
+		}
+		changes = append(changes, change)
+	}
+
+	if !prune {
+		return changes, nil
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.ExternalID] = true
+	}
+	var removed []string
+	for id := range live {
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		for _, job := range live[id] {
+			changes = append(changes, jobChange{Action: jobClose, ExternalID: id, Title: job.Content.Title, JobID: string(job.ID)})
+		}
+	}
+	return changes, nil
+}
+
+// diffJob compares a desired posting with the live one. It returns the
+// update and the fields it changes, or the fields that cannot be updated
+// and force the posting to be replaced. Duration, workload, contractor
+// type, team and fixed-price budget cannot be read back, so they only take
+// effect when a posting is created.
+func diffJob(desired services.CreateJobPostingInput, job services.JobPosting) (*services.UpdateJobPostingInput, []string, []string) {
+	var replace []string
+	if live := string(job.Classification.Category.ID); live != "" && desired.CategoryID != live {
+		replace = append(replace, "categoryId")
+	}
+	if live := string(job.Classification.SubCategory.ID); desired.SubCategoryID != "" && desired.SubCategoryID != live {
+		replace = append(replace, "subCategoryId")
+	}
+	if live := job.ContractTerms.ContractType; live != "" && desired.ContractType != live {
+		replace = append(replace, "contractType")
+	}
+	if len(replace) > 0 {
+		return nil, nil, replace
+	}
+
+	update := &services.UpdateJobPostingInput{ID: string(job.ID)}
+	var fields []string
+	if desired.Title != job.Content.Title {
+		update.SetTitle(desired.Title)
+		fields = append(fields, "title")
+	}
+	if normalizeSpace(desired.Description) != normalizeSpace(job.Content.Description()) {
+		update.SetDescription(desired.Description)
+		fields = append(fields, "description")
+	}
+	if desired.Skills != nil && !sameSkills(desired.Skills, job.Classification.Skills) {
+		update.SetSkills(desired.Skills...)
+		fields = append(fields, "skills")
+	}
+	if desired.HourlyBudgetMin != nil && !sameAmount(*desired.HourlyBudgetMin, job.Info.HourlyBudgetMin) {
+		update.HourlyBudgetMin = desired.HourlyBudgetMin
+		fields = append(fields, "hourlyBudgetMin")
+	}
+	if desired.HourlyBudgetMax != nil && !sameAmount(*desired.HourlyBudgetMax, job.Info.HourlyBudgetMax) {
+		update.HourlyBudgetMax = desired.HourlyBudgetMax
+		fields = append(fields, "hourlyBudgetMax")
+	}
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+	return update, fields, nil
+}
+
+// sameSkills reports whether the desired skills, given by ID or name, are
+// exactly the live skills
+func sameSkills(desired []string, live []models.Skill) bool {
+	if len(desired) != len(live) {
+		return false
+	}
+	for _, want := range desired {
+		found := false
+		for _, skill := range live {
+			if want == string(skill.ID) || strings.EqualFold(want, skill.PrettyName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sameAmount reports whether live is set to want, to the cent
+func sameAmount(want float64, live *models.Money) bool {
+	return live != nil && math.Abs(live.Float64()-want) < 0.005
+}
+
+// normalizeSpace collapses runs of whitespace, which the API does not
+// preserve, to single spaces
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// jobIDs returns the IDs of jobs separated by commas
+func jobIDs(jobs []services.JobPosting) string {
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = string(job.ID)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// applyJobs makes the changes in order, stopping at the first failure. A
+// replacement posts the new job before closing the old one.
+func applyJobs(ctx context.Context, client *upwork.Client, changes []jobChange) error {
+	for i := range changes {
+		c := &changes[i]
+		var err error
+		switch c.Action {
+		case jobCreate, jobReplace:
+			var job *services.JobPosting
+			job, err = client.Jobs.CreateJobPosting(ctx, *c.create)
+			if err == nil {
+				c.NewJobID = string(job.ID)
+			}
+			if err == nil && c.Action == jobReplace {
+				_, err = client.Jobs.CloseJobPosting(ctx, c.JobID)
+			}
+		case jobUpdate:
+			_, err = client.Jobs.UpdateJobPosting(ctx, *c.update)
+		case jobClose:
+			_, err = client.Jobs.CloseJobPosting(ctx, c.JobID)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", c.Action, c.ExternalID, err)
+		}
+		c.Applied = true
+	}
+	return nil
+}
+
+// printJobChanges writes the changes other than no-ops and a summary
+func printJobChanges(w io.Writer, changes []jobChange, applying bool) {
+	counts := make(map[jobAction]int)
+	pending := 0
+	for _, c := range changes {
+		counts[c.Action]++
+		if c.Action != jobNoop && !c.Applied {
+			pending++
+		}
+	}
+	if counts[jobNoop] == len(changes) {
+		fmt.Fprintln(w, "No changes. Job postings match the file.")
+		return
+	}
+
+	fmt.Fprintln(w, "ACTION\tEXTERNAL ID\tJOB\tTITLE\tFIELDS")
+	for _, c := range changes {
+		if c.Action == jobNoop {
+			continue
+		}
+		job := c.JobID
+		switch {
+		case c.NewJobID != "" && job != "":
+			job += " -> " + c.NewJobID
+		case c.NewJobID != "":
+			job = c.NewJobID
+		case job == "":
+			job = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Action, c.ExternalID, job, c.Title, strings.Join(c.Fields, ", "))
+	}
+
+	summary := fmt.Sprintf("%d to create, %d to update, %d to replace, %d to close",
+		counts[jobCreate], counts[jobUpdate], counts[jobReplace], counts[jobClose])
+	switch {
+	case !applying:
+		fmt.Fprintf(w, "\nPlan: %s.\n", summary)
+	case pending > 0:
+		fmt.Fprintf(w, "\nApply stopped with %d of %d changes not made. Plan: %s.\n", pending, len(changes)-counts[jobNoop], summary)
+	default:
+		fmt.Fprintf(w, "\nApply complete: %d created, %d updated, %d replaced, %d closed.\n",
+			counts[jobCreate], counts[jobUpdate], counts[jobReplace], counts[jobClose])
+	}
+}
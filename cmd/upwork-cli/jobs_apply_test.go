@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+const testJobsFile = `
+jobs:
+  - externalId: go-backend
+    title: Senior Go developer
+    description: Build our API.
+    categoryId: "531770282580668418"
+    contractType: hourly
+    skills: [Go, PostgreSQL]
+    hourlyBudgetMin: 40
+    hourlyBudgetMax: 80
+  - externalId: logo
+    title: Logo design
+    description: A new logo.
+    categoryId: "531770282580668419"
+    contractType: fixed-price
+    fixedPriceBudget: 500
+`
+
+// livePosting returns the live form of a posting created from spec
+func livePosting(id string, spec jobSpec, style refStyle) services.JobPosting {
+	input := style.posting(spec)
+	job := services.JobPosting{
+		ID:             models.ID(id),
+		Content:        services.JobContent{Title: input.Title, DescriptionHTML: "<p>" + input.Description + "</p>"},
+		Info:           services.JobInfo{Status: services.JobStatusOpen},
+		ContractTerms:  services.ContractTerms{ContractType: input.ContractType},
+		Classification: services.JobClassification{Category: models.Category{ID: models.ID(input.CategoryID)}},
+	}
+	for _, skill := range input.Skills {
+		job.Classification.Skills = append(job.Classification.Skills, models.Skill{ID: models.ID("skill-" + skill), PrettyName: skill})
+	}
+	if input.HourlyBudgetMin != nil {
+		job.Info.HourlyBudgetMin = models.Ptr(models.MoneyFromFloat(*input.HourlyBudgetMin, "USD"))
+		job.Info.HourlyBudgetMax = models.Ptr(models.MoneyFromFloat(*input.HourlyBudgetMax, "USD"))
+	}
+	return job
+}
+
+func loadTestSpecs(t *testing.T, content string) []jobSpec {
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	specs, err := loadJobSpecs(path)
+	require.NoError(t, err)
+	return specs
+}
+
+func TestPlanJobs(t *testing.T) {
+	specs := loadTestSpecs(t, testJobsFile)
+	require.Len(t, specs, 2)
+	assert.Equal(t, services.ContractTypeFixedPrice, specs[1].ContractType)
+
+	for _, style := range []refStyle{refDescription, refTitle} {
+		t.Run(string(style), func(t *testing.T) {
+			// Nothing is live yet
+			changes, err := planJobs(specs, nil, style, true)
+			require.NoError(t, err)
+			require.Len(t, changes, 2)
+			assert.Equal(t, jobCreate, changes[0].Action)
+			assert.Equal(t, "go-backend", style.externalID(livePosting("job-1", specs[0], style)))
+
+			// Postings made from the file match it
+			backend := livePosting("job-1", specs[0], style)
+			live := map[string][]services.JobPosting{
+				"go-backend": {backend},
+				"logo":       {livePosting("job-2", specs[1], style)},
+			}
+			changes, err = planJobs(specs, live, style, true)
+			require.NoError(t, err)
+			assert.Equal(t, jobNoop, changes[0].Action)
+			assert.Equal(t, jobNoop, changes[1].Action)
+
+			// Edited fields are updated, unchangeable ones replace the
+			// posting, and postings removed from the file are closed
+			backend.Info.HourlyBudgetMax = models.Ptr(models.MoneyFromFloat(60, "USD"))
+			backend.Classification.Skills = backend.Classification.Skills[:1]
+			live["go-backend"] = []services.JobPosting{backend}
+			live["logo"][0].ContractTerms.ContractType = services.ContractTypeHourly
+			live["old"] = []services.JobPosting{{ID: "job-3", Content: services.JobContent{Title: "Old"}}}
+
+			changes, err = planJobs(specs, live, style, true)
+			require.NoError(t, err)
+			require.Len(t, changes, 3)
+			assert.Equal(t, jobUpdate, changes[0].Action)
+			assert.Equal(t, []string{"skills", "hourlyBudgetMax"}, changes[0].Fields)
+			assert.Equal(t, 80.0, *changes[0].update.HourlyBudgetMax)
+			assert.Nil(t, changes[0].update.Title)
+			assert.Equal(t, jobReplace, changes[1].Action)
+			assert.Equal(t, []string{"contractType"}, changes[1].Fields)
+			assert.Equal(t, jobChange{Action: jobClose, ExternalID: "old", Title: "Old", JobID: "job-3"}, changes[2])
+
+			changes, err = planJobs(specs, live, style, false)
+			require.NoError(t, err)
+			assert.Len(t, changes, 2)
+		})
+	}
+}
+
+func TestPlanJobsDuplicates(t *testing.T) {
+	specs := loadTestSpecs(t, testJobsFile)
+	job := livePosting("job-1", specs[0], refDescription)
+	_, err := planJobs(specs, map[string][]services.JobPosting{"go-backend": {job, job}}, refDescription, true)
+	assert.ErrorContains(t, err, "job-1, job-1")
+
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("jobs:\n  - {externalId: a, title: A, description: A, categoryId: '1', contractType: hourly}\n  - {externalId: a, title: B, description: B, categoryId: '1', contractType: hourly}\n"), 0o600))
+	_, err = loadJobSpecs(path)
+	assert.ErrorContains(t, err, "used twice")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// jobSummary is the part of a rendered job posting checked by the tests
+type jobSummary struct {
+	ID      string `json:"id"`
+	Content struct {
+		Title string `json:"title"`
+	} `json:"content"`
+	Info struct {
+		Status string `json:"status"`
+	} `json:"info"`
+}
+
+func newJobSummary(id, title, status string) jobSummary {
+	s := jobSummary{ID: id}
+	s.Content.Title = title
+	s.Info.Status = status
+	return s
+}
+
+// searchResult is the part of a rendered search result checked by the tests
+type searchResult struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Client struct {
+		Location struct {
+			Country string `json:"country"`
+		} `json:"location"`
+	} `json:"client"`
+}
+
+func newSearchResult(id, title, country string) searchResult {
+	r := searchResult{ID: id, Title: title}
+	r.Client.Location.Country = country
+	return r
+}
+
+func TestJobsCommands(t *testing.T) {
+	definition := filepath.Join(t.TempDir(), "job.yaml")
+	require.NoError(t, os.WriteFile(definition, []byte(`
+title: Logo design
+description: A new logo.
+categoryId: "531770282580668419"
+contractType: fixed-price
+fixedPriceBudget: 500
+`), 0o600))
+
+	runCLITests(t, []cliTest{
+		{
+			name: "search",
+			args: []string{"jobs", "search", "go", "developer"},
+			table: "ID     TITLE                        CLIENT COUNTRY  POSTED\n" +
+				"job-1  Go developer for API client  United States   2024-03-01T12:00:00Z\n",
+			want: []searchResult{newSearchResult("job-1", "Go developer for API client", "United States")},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				requests := srv.Requests()
+				require.Len(t, requests, 1)
+				filter := requests[0].Variables["filter"].(map[string]interface{})
+				assert.Equal(t, "go developer", filter["searchExpression_eq"])
+			},
+		},
+		{
+			name:  "search without matches",
+			args:  []string{"jobs", "search", "--type", "fixed-price", "logo"},
+			table: "ID  TITLE  CLIENT COUNTRY  POSTED\n",
+			want:  []searchResult{},
+		},
+		{
+			name: "show",
+			args: []string{"jobs", "show", "job-1"},
+			table: "ID:        job-1\n" +
+				"Title:     Go developer for API client\n" +
+				"Status:    OPEN\n" +
+				"Type:      HOURLY\n" +
+				"Category:   / \n" +
+				"Posted:    2024-03-01T12:00:00Z\n" +
+				"\n" +
+				"Build and maintain a GraphQL client.\n",
+			want: newJobSummary("job-1", "Go developer for API client", "OPEN"),
+		},
+		{
+			name:  "post",
+			args:  []string{"jobs", "post", "--file", definition},
+			table: "Job job-new-1 created (OPEN)\n",
+			want:  newJobSummary("job-new-1", "Logo design", "OPEN"),
+			check: func(t *testing.T, srv *upworktest.Server) {
+				jobs := srv.Fixtures().Jobs
+				require.Len(t, jobs, 2)
+				assert.Equal(t, services.ContractTypeFixedPrice, jobs[1].ContractTerms.ContractType)
+			},
+		},
+	})
+}
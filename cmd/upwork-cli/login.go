@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+)
+
+// loginTimeout bounds how long login waits for the browser redirect
+const loginTimeout = 5 * time.Minute
+
+func loginCommand() *command {
+	var (
+		port      int
+		noBrowser bool
+	)
+
+	return &command{
+		Name:  "login",
+		Short: "Authorize in the browser and store credentials",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&port, "port", 8765, "Local port for the OAuth2 redirect")
+			fs.BoolVar(&noBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			return login(ctx, e, port, noBrowser)
+		},
+	}
+}
+
+func login(ctx context.Context, e *env, port int, noBrowser bool) error {
+	// Reuse stored client credentials when logging in again
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	if creds == nil {
+		creds = &credentials{}
+	}
+	if e.clientID == "" {
+		e.clientID = creds.ClientID
+	}
+	if e.clientSecret == "" {
+		e.clientSecret = creds.ClientSecret
+	}
+	if e.clientID == "" || e.clientSecret == "" {
+		return usageErrorf("client ID and secret are required (set UPWORK_CLIENT_ID and UPWORK_CLIENT_SECRET or use flags)")
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("listening for OAuth2 redirect: %w", err)
+	}
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:     e.clientID,
+		ClientSecret: e.clientSecret,
+		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", port),
+		APIURL:       e.apiURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return fmt.Errorf("generating state: %w", err)
+	}
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			report(errs, fmt.Errorf("state mismatch in OAuth2 redirect"))
+		case query.Get("error") != "":
+			http.Error(w, "Authorization failed", http.StatusBadRequest)
+			report(errs, fmt.Errorf("authorization failed: %s", query.Get("error")))
+		default:
+			fmt.Fprintln(w, "Login complete. You can close this window.")
+			report(codes, query.Get("code"))
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authURL := client.GetAuthURL(state)
+	if noBrowser || openBrowser(authURL) != nil {
+		fmt.Fprintf(e.stderr, "Open this URL in your browser to log in:\n\n  %s\n\n", authURL)
+	} else {
+		fmt.Fprintln(e.stderr, "Opened your browser to complete login...")
+	}
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for login")
+	}
+
+	token, err := client.ExchangeCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	// Keep the selected organization across logins
+	creds.ClientID = e.clientID
+	creds.ClientSecret = e.clientSecret
+	creds.Token = token
+
+	if err := saveCredentials(creds); err != nil {
+		return err
+	}
+
+	user, err := client.Users.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("logged in, but fetching the current user failed: %w", err)
+	}
+
+	return e.render(user, func(w io.Writer) {
+		fmt.Fprintf(w, "Logged in as %s (%s)\n", user.FullName(), user.Email)
+	})
+}
+
+func whoamiCommand() *command {
+	return &command{
+		Name:  "whoami",
+		Short: "Show the authenticated user, token expiry and granted scopes",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			info, err := client.WhoAmI(ctx)
+			if err != nil {
+				return fmt.Errorf("getting token details: %w", err)
+			}
+
+			return e.render(info, func(w io.Writer) {
+				user := info.User
+				fmt.Fprintf(w, "Name:\t%s\n", user.FullName())
+				fmt.Fprintf(w, "Email:\t%s\n", user.Email)
+				fmt.Fprintf(w, "ID:\t%s\n", user.ID)
+				if orgID := client.GetOrganizationID(); orgID != "" {
+					fmt.Fprintf(w, "Organization:\t%s\n", orgID)
+				}
+				fmt.Fprintf(w, "Organizations:\t%d\n", len(info.Organizations))
+				if !info.Expiry.IsZero() {
+					fmt.Fprintf(w, "Token expires:\t%s\n", info.Expiry.Local().Format(time.RFC1123))
+				}
+				scopes := make([]string, len(info.Scopes))
+				for i, s := range info.Scopes {
+					scopes[i] = string(s)
+				}
+				fmt.Fprintf(w, "Scopes:\t%s\n", strings.Join(scopes, " "))
+			})
+		},
+	}
+}
+
+// report delivers v without blocking if a result was already delivered
+func report[T any](ch chan<- T, v T) {
+	select {
+	case ch <- v:
+	default:
+	}
+}
+
+// randomState returns an unguessable OAuth2 state value
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// openBrowser opens url in the user's default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
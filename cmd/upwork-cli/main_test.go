@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// runCLI runs the command line against client and returns its output,
+// failing the test unless the command succeeds
+func runCLI(t *testing.T, client *upwork.Client, args ...string) string {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := execute(rootCommand(), args, &env{stdout: &stdout, stderr: &stderr, client: client})
+	require.Equal(t, 0, code, "upwork-cli %v: %s", args, stderr.String())
+
+	return stdout.String()
+}
+
+// cliTest is a command line run once per output format, each time against
+// a fresh fake API
+type cliTest struct {
+	name string
+	args []string
+	// setup, if set, adjusts the fake API before the command runs
+	setup func(t *testing.T, srv *upworktest.Server)
+	// table is the expected table output
+	table string
+	// want is the expected json and yaml output, decoded into a value of
+	// the same type; fields missing from the type are ignored
+	want interface{}
+	// check, if set, inspects the fake API after the command ran
+	check func(t *testing.T, srv *upworktest.Server)
+}
+
+func runCLITests(t *testing.T, tests []cliTest) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, format := range []string{outputTable, outputJSON, outputYAML} {
+				t.Run(format, func(t *testing.T) {
+					client, srv := upworktest.NewFakeClient(t, nil)
+					if tt.setup != nil {
+						tt.setup(t, srv)
+					}
+
+					out := runCLI(t, client, append(tt.args, "--output", format)...)
+					if format == outputTable {
+						assert.Equal(t, tt.table, out)
+					} else {
+						got := reflect.New(reflect.TypeOf(tt.want))
+						decodeOutput(t, format, out, got.Interface())
+						assert.Equal(t, tt.want, got.Elem().Interface())
+					}
+
+					if tt.check != nil {
+						tt.check(t, srv)
+					}
+				})
+			}
+		})
+	}
+}
+
+// decodeOutput decodes json or yaml output into v using v's json field names
+func decodeOutput(t *testing.T, format, out string, v interface{}) {
+	t.Helper()
+
+	data := []byte(out)
+	if format == outputYAML {
+		var doc interface{}
+		require.NoError(t, yaml.Unmarshal(data, &doc), out)
+		var err error
+		data, err = json.Marshal(doc)
+		require.NoError(t, err)
+	}
+	require.NoError(t, json.Unmarshal(data, v), out)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// orgCommand returns the "org" command group
+func orgCommand() *command {
+	return &command{
+		Name:  "org",
+		Short: "List and select organizations",
+		Subcommands: []*command{
+			orgListCommand(),
+			orgUseCommand(),
+		},
+	}
+}
+
+func orgListCommand() *command {
+	return &command{
+		Name:  "list",
+		Short: "List organizations available to the user",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			companies, err := client.Users.GetCompanySelector(ctx)
+			if err != nil {
+				return fmt.Errorf("listing organizations: %w", err)
+			}
+
+			current := client.GetOrganizationID()
+			return e.render(companies, func(w io.Writer) {
+				fmt.Fprintln(w, "\tID\tNAME")
+				for _, c := range companies {
+					marker := ""
+					if c.OrganizationID == current {
+						marker = "*"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", marker, c.OrganizationID, c.Title)
+				}
+			})
+		},
+	}
+}
+
+// orgSelection is the result printed by "org use"
+type orgSelection struct {
+	OrganizationID string `json:"organizationId"`
+	Title          string `json:"title"`
+}
+
+func orgUseCommand() *command {
+	return &command{
+		Name:  "use",
+		Args:  "<orgID>",
+		Short: "Select the organization used by every command",
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected an organization ID")
+			}
+			orgID := args[0]
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			companies, err := client.Users.GetCompanySelector(ctx)
+			if err != nil {
+				return fmt.Errorf("listing organizations: %w", err)
+			}
+
+			var selected *orgSelection
+			for _, c := range companies {
+				if c.OrganizationID == orgID {
+					selected = &orgSelection{OrganizationID: orgID, Title: c.Title}
+					break
+				}
+			}
+			if selected == nil {
+				return fmt.Errorf("organization %s is not accessible to this user (see \"upwork-cli org list\")", orgID)
+			}
+
+			creds, err := loadCredentials()
+			if err != nil {
+				return err
+			}
+			if creds == nil {
+				creds = &credentials{}
+			}
+			creds.OrganizationID = orgID
+
+			if err := saveCredentials(creds); err != nil {
+				return err
+			}
+
+			return e.render(selected, func(w io.Writer) {
+				fmt.Fprintf(w, "Using organization %s (%s)\n", selected.OrganizationID, selected.Title)
+			})
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// addOrganization makes a second organization available to the user and
+// points the credentials file at a fresh login
+func addOrganization(t *testing.T, srv *upworktest.Server) {
+	fixtures := srv.Fixtures()
+	fixtures.Companies = append(fixtures.Companies, services.CompanySelector{Title: "Side Project", OrganizationID: "org-2"})
+
+	t.Setenv("UPWORK_CREDENTIALS", filepath.Join(t.TempDir(), "credentials.json"))
+	require.NoError(t, saveCredentials(&credentials{
+		ClientID: "client-id",
+		Token:    &oauth2.Token{AccessToken: "stored-token"},
+	}))
+}
+
+func TestOrgCommands(t *testing.T) {
+	runCLITests(t, []cliTest{
+		{
+			name:  "list",
+			args:  []string{"org", "list"},
+			setup: addOrganization,
+			table: "   ID     NAME\n" +
+				"*  org-1  Test Organization\n" +
+				"   org-2  Side Project\n",
+			want: []services.CompanySelector{
+				{Title: "Test Organization", OrganizationID: "org-1"},
+				{Title: "Side Project", OrganizationID: "org-2"},
+			},
+		},
+		{
+			name:  "use",
+			args:  []string{"org", "use", "org-2"},
+			setup: addOrganization,
+			table: "Using organization org-2 (Side Project)\n",
+			want:  orgSelection{OrganizationID: "org-2", Title: "Side Project"},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				creds, err := loadCredentials()
+				require.NoError(t, err)
+				assert.Equal(t, "org-2", creds.OrganizationID)
+				assert.Equal(t, "client-id", creds.ClientID)
+				assert.Equal(t, "stored-token", creds.Token.AccessToken)
+			},
+		},
+	})
+}
+
+func TestOrgUseInaccessible(t *testing.T) {
+	client, srv := upworktest.NewFakeClient(t, nil)
+	addOrganization(t, srv)
+
+	var stdout, stderr bytes.Buffer
+	code := execute(rootCommand(), []string{"org", "use", "org-3"}, &env{stdout: &stdout, stderr: &stderr, client: client})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "organization org-3 is not accessible")
+
+	creds, err := loadCredentials()
+	require.NoError(t, err)
+	assert.Empty(t, creds.OrganizationID)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// reportPageSize is the page size used when paginating time reports
+const reportPageSize = 100
+
+// reportsCommand returns the "reports" command group
+func reportsCommand() *command {
+	return &command{
+		Name:  "reports",
+		Short: "Export financial and time reports",
+		Subcommands: []*command{
+			reportsTransactionsCommand(),
+			reportsTimeCommand(),
+		},
+	}
+}
+
+// reportFlags holds the flags shared by report exports
+type reportFlags struct {
+	from   string
+	to     string
+	format string
+	file   string
+}
+
+// register adds the report flags to fs
+func (f *reportFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.from, "from", "", "Start date (YYYY-MM-DD)")
+	fs.StringVar(&f.to, "to", "", "End date (YYYY-MM-DD), inclusive")
+	fs.StringVar(&f.format, "format", "csv", "Export format (csv, json, yaml); --output json|yaml takes precedence")
+	fs.StringVar(&f.file, "o", "", "Output file (defaults to stdout)")
+}
+
+// prepare validates the flags, resolves the export format into e.output and
+// redirects output to the requested file. Reports render their table form
+// as CSV. The returned function closes the output file.
+func (f *reportFlags) prepare(e *env) (models.DateRange, func() error, error) {
+	noop := func() error { return nil }
+
+	if e.output == outputTable {
+		switch f.format {
+		case "csv":
+		case outputJSON, outputYAML:
+			e.output = f.format
+		default:
+			return models.DateRange{}, noop, usageErrorf("unknown format %q (want csv, json or yaml)", f.format)
+		}
+	}
+
+	dateRange, err := parseDateRange(f.from, f.to)
+	if err != nil {
+		return models.DateRange{}, noop, err
+	}
+
+	if f.file == "" {
+		return dateRange, noop, nil
+	}
+
+	out, err := os.Create(f.file)
+	if err != nil {
+		return models.DateRange{}, noop, fmt.Errorf("creating output file: %w", err)
+	}
+	e.stdout = out
+
+	return dateRange, out.Close, nil
+}
+
+func reportsTransactionsCommand() *command {
+	var (
+		rf     reportFlags
+		aceIDs string
+	)
+
+	return &command{
+		Name:  "transactions",
+		Short: "Export transaction history",
+		Flags: func(fs *flag.FlagSet) {
+			rf.register(fs)
+			fs.StringVar(&aceIDs, "ace", "", "Comma-separated accounting entity IDs (defaults to all accessible entities)")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			dateRange, closeOutput, err := rf.prepare(e)
+			if err != nil {
+				return err
+			}
+			defer closeOutput()
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			var ids []string
+			if aceIDs != "" {
+				ids = strings.Split(aceIDs, ",")
+			} else {
+				entities, err := client.Reports.ListAccountingEntities(ctx)
+				if err != nil {
+					return fmt.Errorf("listing accounting entities: %w", err)
+				}
+				if len(entities) == 0 {
+					return fmt.Errorf("no accounting entities found; pass --ace")
+				}
+				ids = services.AccountingEntityIDs(entities)
+			}
+
+			if err := exportTransactions(ctx, client, e, ids, dateRange); err != nil {
+				return err
+			}
+			return closeOutput()
+		},
+	}
+}
+
+func reportsTimeCommand() *command {
+	var (
+		rf    reportFlags
+		orgID string
+	)
+
+	return &command{
+		Name:  "time",
+		Short: "Export the contract time report",
+		Flags: func(fs *flag.FlagSet) {
+			rf.register(fs)
+			fs.StringVar(&orgID, "org", "", "Organization ID (defaults to the selected organization)")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			dateRange, closeOutput, err := rf.prepare(e)
+			if err != nil {
+				return err
+			}
+			defer closeOutput()
+
+			client, err := e.newClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			if orgID == "" {
+				orgID = client.GetOrganizationID()
+			}
+			if orgID == "" {
+				return usageErrorf("--org is required when no organization is selected")
+			}
+
+			if err := exportTimeReport(ctx, client, e, orgID, dateRange); err != nil {
+				return err
+			}
+			return closeOutput()
+		},
+	}
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD range
+func parseDateRange(from, to string) (models.DateRange, error) {
+	if from == "" || to == "" {
+		return models.DateRange{}, usageErrorf("--from and --to are required")
+	}
+
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return models.DateRange{}, fmt.Errorf("invalid --from date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return models.DateRange{}, fmt.Errorf("invalid --to date: %w", err)
+	}
+	if end.Before(start) {
+		return models.DateRange{}, usageErrorf("--to must not be before --from")
+	}
+
+	// Include the whole of the final day
+	return models.DateRange{Start: start, End: end.Add(24*time.Hour - time.Nanosecond)}, nil
+}
+
+func exportTransactions(ctx context.Context, client *upwork.Client, e *env, aceIDs []string, dateRange models.DateRange) error {
+	input := services.TransactionHistoryInput{
+		AccountingEntityIDs: aceIDs,
+		DateRange:           dateRange,
+	}
+
+	if e.output != outputTable {
+		history, err := client.Reports.GetTransactionHistory(ctx, input)
+		if err != nil {
+			return fmt.Errorf("getting transaction history: %w", err)
+		}
+		return e.render(history.TransactionDetail.TransactionHistoryRows, nil)
+	}
+
+	// Stream rows straight to CSV so large exports use constant memory
+	cw := csv.NewWriter(e.stdout)
+	cw.Write([]string{
+		"date", "type", "subtype", "description", "amount", "currency",
+		"payment_status", "assignment", "company", "freelancer", "invoice_id",
+	})
+	err := client.Reports.GetTransactionHistoryStream(ctx, input, func(row services.TransactionHistoryRow) error {
+		return cw.Write([]string{
+			displayDateTime(row.TransactionCreationDate),
+			row.Type,
+			row.AccountingSubtype,
+			row.Description,
+			row.TransactionAmount.Amount(),
+			row.TransactionAmount.Currency,
+			row.PaymentStatus,
+			row.RelatedAssignment,
+			row.AssignmentCompanyName,
+			row.AssignmentDeveloperName,
+			row.RelatedInvoiceID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("getting transaction history: %w", err)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func exportTimeReport(ctx context.Context, client *upwork.Client, e *env, orgID string, dateRange models.DateRange) error {
+	var reports []services.TimeReport
+
+	// Page through the full report
+	pagination := &models.PaginationInput{First: reportPageSize}
+	for {
+		list, err := client.Reports.GetTimeReport(ctx, services.TimeReportInput{
+			OrganizationID: orgID,
+			DateRange:      dateRange,
+			Pagination:     pagination,
+		})
+		if err != nil {
+			return fmt.Errorf("getting time report: %w", err)
+		}
+
+		for _, edge := range list.Edges {
+			reports = append(reports, edge.Node)
+		}
+
+		if !list.PageInfo.HasNextPage || list.PageInfo.EndCursor == "" {
+			break
+		}
+		pagination = &models.PaginationInput{First: reportPageSize, After: list.PageInfo.EndCursor}
+	}
+
+	if e.output != outputTable {
+		return e.render(reports, nil)
+	}
+
+	cw := csv.NewWriter(e.stdout)
+	cw.Write([]string{
+		"date", "freelancer", "team", "contract_id", "task", "memo",
+		"hours", "charges", "currency",
+	})
+	for _, r := range reports {
+		cw.Write([]string{
+			displayDateTime(r.DateWorkedOn),
+			r.Freelancer.FullName(),
+			r.Team.Name,
+			string(r.Contract.ID),
+			r.Task,
+			r.Memo,
+			strconv.FormatFloat(r.TotalHoursWorked, 'f', 2, 64),
+			r.TotalCharges.Amount(),
+			r.TotalCharges.Currency,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
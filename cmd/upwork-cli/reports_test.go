@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// handleReports registers report resolvers serving a fixed accounting
+// entity, two transactions and one page of time report rows
+func handleReports(t *testing.T, srv *upworktest.Server) {
+	srv.Handle("accountingEntities", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return []services.AccountingEntity{{ID: "ace-1", Name: "Test Organization"}}, nil
+	})
+	srv.Handle("transactionHistory", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return services.TransactionHistory{TransactionDetail: services.TransactionDetail{
+			TransactionHistoryRows: []services.TransactionHistoryRow{
+				{
+					TransactionCreationDate: models.DateTime{RawValue: "2024-03-04"},
+					Type:                    "Payment",
+					AccountingSubtype:       "Hourly",
+					Description:             "Invoice for 2024-02-26 - 2024-03-03",
+					TransactionAmount:       models.MustMoney("-400.00", "USD"),
+					PaymentStatus:           "Paid",
+					RelatedAssignment:       "contract-1",
+					AssignmentCompanyName:   "Test Organization",
+					AssignmentDeveloperName: "Fiona Freelancer",
+					RelatedInvoiceID:        "inv-1",
+				},
+				{
+					TransactionCreationDate: models.DateTime{RawValue: "2024-03-05"},
+					Type:                    "Fee",
+					Description:             "Marketplace fee, \"hourly\"",
+					TransactionAmount:       models.MustMoney("-20.00", "USD"),
+					PaymentStatus:           "Paid",
+				},
+			},
+		}}, nil
+	})
+	srv.Handle("contractTimeReport", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return services.TimeReportList{
+			TotalCount: 1,
+			Edges: []services.TimeReportEdge{{Node: services.TimeReport{
+				DateWorkedOn:     models.DateTime{RawValue: "2024-03-04"},
+				Freelancer:       models.User{ID: "user-2", Name: "Fiona Freelancer"},
+				Team:             models.Team{ID: "team-1", Name: "Backend"},
+				Contract:         services.Contract{ID: "contract-1"},
+				Task:             "API",
+				Memo:             "Pagination",
+				TotalHoursWorked: 2.5,
+				TotalCharges:     models.MustMoney("125.00", "USD"),
+			}}},
+		}, nil
+	})
+}
+
+// transactionSummary is the part of a rendered transaction checked by the
+// tests
+type transactionSummary struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Amount      struct {
+		RawValue string `json:"rawValue"`
+	} `json:"transactionAmount"`
+}
+
+func newTransactionSummary(typ, description, amount string) transactionSummary {
+	s := transactionSummary{Type: typ, Description: description}
+	s.Amount.RawValue = amount
+	return s
+}
+
+// timeReportSummary is the part of a rendered time report row checked by
+// the tests
+type timeReportSummary struct {
+	Contract struct {
+		ID string `json:"id"`
+	} `json:"contract"`
+	Memo  string  `json:"memo"`
+	Hours float64 `json:"totalHoursWorked"`
+}
+
+func TestReportsCommands(t *testing.T) {
+	timeRow := timeReportSummary{Memo: "Pagination", Hours: 2.5}
+	timeRow.Contract.ID = "contract-1"
+
+	runCLITests(t, []cliTest{
+		{
+			name:  "transactions",
+			args:  []string{"reports", "transactions", "--from", "2024-03-01", "--to", "2024-03-31"},
+			setup: handleReports,
+			table: "date,type,subtype,description,amount,currency,payment_status,assignment,company,freelancer,invoice_id\n" +
+				"2024-03-04,Payment,Hourly,Invoice for 2024-02-26 - 2024-03-03,-400.00,USD,Paid,contract-1,Test Organization,Fiona Freelancer,inv-1\n" +
+				"2024-03-05,Fee,,\"Marketplace fee, \"\"hourly\"\"\",-20.00,USD,Paid,,,,\n",
+			want: []transactionSummary{
+				newTransactionSummary("Payment", "Invoice for 2024-02-26 - 2024-03-03", "-400.00"),
+				newTransactionSummary("Fee", "Marketplace fee, \"hourly\"", "-20.00"),
+			},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				requests := srv.Requests()
+				require.Len(t, requests, 2)
+				assert.Equal(t, []interface{}{"ace-1"}, requests[1].Variables["aceIds_any"])
+			},
+		},
+		{
+			name:  "time",
+			args:  []string{"reports", "time", "--org", "org-1", "--from", "2024-03-01", "--to", "2024-03-31"},
+			setup: handleReports,
+			table: "date,freelancer,team,contract_id,task,memo,hours,charges,currency\n" +
+				"2024-03-04,Fiona Freelancer,Backend,contract-1,API,Pagination,2.50,125.00,USD\n",
+			want: []timeReportSummary{timeRow},
+			check: func(t *testing.T, srv *upworktest.Server) {
+				requests := srv.Requests()
+				require.Len(t, requests, 1)
+				assert.Equal(t, "org-1", requests[0].Variables["orgId"])
+			},
+		},
+	})
+}
+
+func TestReportsExportFormatAndFile(t *testing.T) {
+	client, srv := upworktest.NewFakeClient(t, nil)
+	handleReports(t, srv)
+	path := filepath.Join(t.TempDir(), "time.yaml")
+
+	out := runCLI(t, client, "reports", "time", "--org", "org-1", "--from", "2024-03-01", "--to", "2024-03-31",
+		"--format", "yaml", "-o", path)
+	assert.Empty(t, out)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var rows []timeReportSummary
+	decodeOutput(t, outputYAML, string(data), &rows)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "contract-1", rows[0].Contract.ID)
+}
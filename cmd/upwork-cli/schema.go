@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rizome-dev/go-upwork/internal/graphql"
+)
+
+// schemaCommand returns the "schema" command group
+func schemaCommand() *command {
+	return &command{
+		Name:  "schema",
+		Short: "Work with the GraphQL schema",
+		Subcommands: []*command{
+			schemaPullCommand(),
+		},
+	}
+}
+
+func schemaPullCommand() *command {
+	var (
+		file   string
+		format string
+	)
+
+	return &command{
+		Name:  "pull",
+		Short: "Introspect the live schema",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&file, "o", "", "Output file (defaults to stdout)")
+			fs.StringVar(&format, "format", "sdl", "Schema format (sdl, json); --output json|yaml takes precedence")
+		},
+		Run: func(ctx context.Context, e *env, args []string) error {
+			if e.output == outputTable {
+				switch format {
+				case "sdl":
+				case outputJSON:
+					e.output = outputJSON
+				default:
+					return usageErrorf("unknown format %q (want sdl or json)", format)
+				}
+			}
+
+			// Fall back to the stored login, refreshing it if needed
+			token := e.token
+			if token == "" {
+				client, err := e.newClient(ctx)
+				if err != nil {
+					return err
+				}
+				if t := client.GetToken(); t != nil {
+					token = t.AccessToken
+				}
+			}
+			if token == "" {
+				return fmt.Errorf("an access token is required (run \"upwork-cli login\" or set UPWORK_ACCESS_TOKEN)")
+			}
+
+			client := graphql.NewClient(nil, e.apiURL)
+			client.SetHeader("Authorization", "Bearer "+token)
+			if e.orgID != "" {
+				client.SetHeader("X-Upwork-API-TenantId", e.orgID)
+			}
+
+			schema, err := client.Introspect(ctx)
+			if err != nil {
+				return fmt.Errorf("introspecting schema: %w", err)
+			}
+
+			closeOutput := func() error { return nil }
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("creating output file: %w", err)
+				}
+				defer f.Close()
+				e.stdout = f
+				closeOutput = f.Close
+			}
+
+			// The table form of a schema is its SDL
+			if e.output == outputTable {
+				_, err = io.WriteString(e.stdout, schema.SDL())
+			} else {
+				err = e.render(schema, nil)
+			}
+			if err != nil {
+				return fmt.Errorf("writing schema: %w", err)
+			}
+			return closeOutput()
+		},
+	}
+}
@@ -0,0 +1,34 @@
+# bash completion for tool
+_tool() {
+    local cur prev words path i
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    if [[ "$prev" == "--output" ]]; then
+        COMPREPLY=($(compgen -W "table json yaml" -- "$cur"))
+        return
+    fi
+
+    # Build the command path from the words before the cursor
+    path=""
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            -*) ;;
+            *) path="${path:+$path }${COMP_WORDS[i]}" ;;
+        esac
+    done
+
+    case "$path" in
+        "group fail"*)
+            words="--api-url --client-id --client-secret --json --org-id --output --token --token-url" ;;
+        "greet"*)
+            words="--api-url --client-id --client-secret --json --loud --org-id --output --token --token-url" ;;
+        "group"*)
+            words="fail" ;;
+        *)
+            words="greet group" ;;
+    esac
+
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _tool tool
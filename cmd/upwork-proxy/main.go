@@ -0,0 +1,188 @@
+// Package main provides a REST gateway to the Upwork API for teams that do
+// not use Go. It serves contracts, jobs and messages as plain JSON from one
+// SDK client, which holds the Upwork credentials, renews tokens, rate limits
+// and retries requests, and serves recent responses while the API is down.
+// Callers authenticate with API keys and are rate limited per key.
+//
+// Configuration comes from flags and the environment:
+//
+//	UPWORK_CLIENT_ID, UPWORK_CLIENT_SECRET  OAuth2 client credentials
+//	UPWORK_SERVICE_ACCOUNT=true             authenticate as a service account, or
+//	UPWORK_ACCESS_TOKEN, UPWORK_REFRESH_TOKEN  act as an authorized user
+//	UPWORK_ORG_ID                           organization the gateway acts in
+//	UPWORK_PROXY_API_KEYS                   caller keys, as name:secret,name:secret
+//
+// Keys are read only from the environment so secrets stay out of process
+// listings.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+)
+
+// shutdownTimeout bounds how long in-flight requests may finish on exit
+const shutdownTimeout = 30 * time.Second
+
+// config holds the gateway's settings
+type config struct {
+	addr            string
+	tlsCert         string
+	tlsKey          string
+	rate            int
+	staleWhileError time.Duration
+
+	clientID       string
+	clientSecret   string
+	serviceAccount bool
+	accessToken    string
+	refreshToken   string
+	orgID          string
+	apiURL         string
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	if err := run(logger, os.Args[1:]); err != nil {
+		logger.Error("upwork-proxy stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until interrupted
+func run(logger *slog.Logger, args []string) error {
+	var cfg config
+	serviceAccount, _ := strconv.ParseBool(os.Getenv("UPWORK_SERVICE_ACCOUNT"))
+
+	fs := flag.NewFlagSet("upwork-proxy", flag.ContinueOnError)
+	fs.StringVar(&cfg.addr, "addr", ":8080", "Address to listen on")
+	fs.StringVar(&cfg.tlsCert, "tls-cert", "", "TLS certificate file; serve plain HTTP if empty")
+	fs.StringVar(&cfg.tlsKey, "tls-key", "", "TLS key file")
+	fs.IntVar(&cfg.rate, "rate", 60, "Requests per minute allowed per API key, 0 for no limit")
+	fs.DurationVar(&cfg.staleWhileError, "stale-while-error", 5*time.Minute, "Serve responses up to this old when the API fails, 0 to disable")
+	fs.StringVar(&cfg.clientID, "client-id", os.Getenv("UPWORK_CLIENT_ID"), "OAuth2 Client ID")
+	fs.StringVar(&cfg.clientSecret, "client-secret", os.Getenv("UPWORK_CLIENT_SECRET"), "OAuth2 Client Secret")
+	fs.BoolVar(&cfg.serviceAccount, "service-account", serviceAccount, "Authenticate as a service account")
+	fs.StringVar(&cfg.orgID, "org-id", os.Getenv("UPWORK_ORG_ID"), "Organization ID")
+	fs.StringVar(&cfg.apiURL, "api-url", upwork.DefaultAPIURL, "GraphQL endpoint")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg.accessToken = os.Getenv("UPWORK_ACCESS_TOKEN")
+	cfg.refreshToken = os.Getenv("UPWORK_REFRESH_TOKEN")
+
+	keys, err := parseKeys(os.Getenv("UPWORK_PROXY_API_KEYS"))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := newClient(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	srv := &http.Server{
+		Addr:              cfg.addr,
+		Handler:           newServer(client, keys, cfg.rate, logger).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      2 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", cfg.addr, "keys", len(keys), "tls", cfg.tlsCert != "")
+		if cfg.tlsCert != "" {
+			errc <- srv.ListenAndServeTLS(cfg.tlsCert, cfg.tlsKey)
+		} else {
+			errc <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newClient creates the SDK client the gateway serves from
+func newClient(ctx context.Context, cfg config, logger *slog.Logger) (*upwork.Client, error) {
+	if cfg.clientID == "" || cfg.clientSecret == "" {
+		return nil, fmt.Errorf("client ID and secret are required (set UPWORK_CLIENT_ID and UPWORK_CLIENT_SECRET)")
+	}
+
+	config := &upwork.Config{
+		ClientID:           cfg.clientID,
+		ClientSecret:       cfg.clientSecret,
+		OrganizationID:     cfg.orgID,
+		APIURL:             cfg.apiURL,
+		ServiceAccount:     cfg.serviceAccount,
+		StaleWhileError:    cfg.staleWhileError,
+		DeduplicateQueries: true,
+	}
+	if !cfg.serviceAccount {
+		if cfg.accessToken == "" && cfg.refreshToken == "" {
+			return nil, fmt.Errorf("set UPWORK_SERVICE_ACCOUNT=true or provide UPWORK_ACCESS_TOKEN or UPWORK_REFRESH_TOKEN")
+		}
+		config.Token = &oauth2.Token{AccessToken: cfg.accessToken, RefreshToken: cfg.refreshToken, TokenType: "Bearer"}
+	}
+
+	return upwork.NewClient(ctx, config, upwork.WithAutoRefresh(0), upwork.WithLogger(logger))
+}
+
+// parseKeys parses API keys given as name:secret pairs separated by commas
+func parseKeys(s string) ([]apiKey, error) {
+	var keys []apiKey
+	names := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, secret, ok := strings.Cut(pair, ":")
+		if !ok || name == "" || len(secret) < 16 {
+			return nil, fmt.Errorf("API key %q must be name:secret with a secret of at least 16 characters", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("API key %q is given twice", name)
+		}
+		names[name] = true
+		keys = append(keys, apiKey{name: name, secret: secret})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no API keys configured (set UPWORK_PROXY_API_KEYS to name:secret pairs)")
+	}
+	return keys, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// requestLog collects what the access log reports about a request
+type requestLog struct {
+	http.ResponseWriter
+	status int
+	caller string
+}
+
+// WriteHeader records the status
+func (l *requestLog) WriteHeader(status int) {
+	if l.status == 0 {
+		l.status = status
+	}
+	l.ResponseWriter.WriteHeader(status)
+}
+
+// Write records an implicit 200
+func (l *requestLog) Write(b []byte) (int, error) {
+	if l.status == 0 {
+		l.status = http.StatusOK
+	}
+	return l.ResponseWriter.Write(b)
+}
+
+// requestLogKey is the context key of the request's log
+type requestLogKey struct{}
+
+// logRequests logs each request once it was served, and recovers from
+// panics in handlers so one bad request does not bring the gateway down
+func (s *server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		l := &requestLog{ResponseWriter: w}
+		r = r.WithContext(context.WithValue(r.Context(), requestLogKey{}, l))
+
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				s.logger.Error("handler panicked", "method", r.Method, "path", r.URL.Path, "panic", err)
+				if l.status == 0 {
+					writeError(l, http.StatusInternalServerError, "internal_error", "internal error")
+				}
+			}
+			s.logger.Info("request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", l.status,
+				"caller", l.caller,
+				"duration", time.Since(start),
+			)
+		}()
+		next.ServeHTTP(l, r)
+	})
+}
+
+// setCaller records the name of the API key r was made with
+func setCaller(r *http.Request, name string) {
+	if l, ok := r.Context().Value(requestLogKey{}).(*requestLog); ok {
+		l.caller = name
+	}
+}
+
+// caller returns the name of the API key r was made with
+func caller(r *http.Request) string {
+	if l, ok := r.Context().Value(requestLogKey{}).(*requestLog); ok {
+		return l.caller
+	}
+	return ""
+}
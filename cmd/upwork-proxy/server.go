@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/internal/ratelimit"
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// maxPageSize bounds the page size callers may request
+const maxPageSize = 100
+
+// maxBodySize bounds request bodies
+const maxBodySize = 1 << 20
+
+// maxQueueWait is how long a caller over its rate limit is held before
+// being turned away with 429
+const maxQueueWait = 2 * time.Second
+
+// apiKey is a credential callers present as a bearer token. The name
+// identifies the caller in logs so the secret never is.
+type apiKey struct {
+	name   string
+	secret string
+}
+
+// server serves the REST API from an SDK client
+type server struct {
+	client *upwork.Client
+	keys   []apiKey
+	logger *slog.Logger
+
+	// rate is the requests per minute allowed per key, zero for no limit
+	rate int
+
+	mu       sync.Mutex
+	limiters map[string]*ratelimit.Limiter
+}
+
+// newServer returns a server of client for the given keys
+func newServer(client *upwork.Client, keys []apiKey, rate int, logger *slog.Logger) *server {
+	return &server{
+		client:   client,
+		keys:     keys,
+		logger:   logger,
+		rate:     rate,
+		limiters: make(map[string]*ratelimit.Limiter),
+	}
+}
+
+// handler returns the routes behind authentication, rate limiting and
+// access logging
+func (s *server) handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("/v1/contracts", s.listContracts)
+	api.HandleFunc("/v1/contracts/", s.getContract)
+	api.HandleFunc("/v1/jobs", s.listJobs)
+	api.HandleFunc("/v1/jobs/search", s.searchJobs)
+	api.HandleFunc("/v1/jobs/", s.getJob)
+	api.HandleFunc("/v1/rooms", s.listRooms)
+	api.HandleFunc("/v1/rooms/", s.roomMessages)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("/v1/", s.authenticate(api))
+	return s.logRequests(mux)
+}
+
+// authenticate admits requests bearing a known API key within the key's
+// rate limit
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.lookupKey(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="upwork-proxy"`)
+			writeError(w, http.StatusUnauthorized, "unauthorized", "a valid API key is required")
+			return
+		}
+		setCaller(r, key.name)
+
+		if limiter := s.limiter(key.name); limiter != nil {
+			ctx, cancel := context.WithTimeout(r.Context(), maxQueueWait)
+			err := limiter.Wait(ctx)
+			cancel()
+			if err != nil {
+				w.Header().Set("Retry-After", strconv.Itoa(int(maxQueueWait/time.Second)))
+				writeError(w, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lookupKey returns the key presented by r. Every key is compared in
+// constant time so timing does not reveal which prefix matched.
+func (s *server) lookupKey(r *http.Request) (apiKey, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return apiKey{}, false
+	}
+
+	var found apiKey
+	var match int
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key.secret)) == 1 {
+			found = key
+			match = 1
+		}
+	}
+	return found, match == 1
+}
+
+// limiter returns the rate limiter of the named key, or nil if callers are
+// not limited
+func (s *server) limiter(name string) *ratelimit.Limiter {
+	if s.rate <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.limiters[name]
+	if !ok {
+		l = ratelimit.New(s.rate, time.Minute)
+		s.limiters[name] = l
+	}
+	return l
+}
+
+// GET /v1/contracts?status=active&type=hourly&first=20&after=...&format=flat
+func (s *server) listContracts(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	pagination, err := parsePagination(r)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	input := services.ListContractsInput{Pagination: pagination}
+	q := r.URL.Query()
+	if status := q.Get("status"); status != "" {
+		input.Filter = &services.ContractFilter{Status: []services.ContractStatus{services.ContractStatus(upperEnum(status))}}
+	}
+	if typ := q.Get("type"); typ != "" {
+		if input.Filter == nil {
+			input.Filter = &services.ContractFilter{}
+		}
+		input.Filter.ContractType = []services.ContractType{services.ContractType(upperEnum(typ))}
+	}
+
+	list, err := s.client.Contracts.ListContracts(r.Context(), input)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	var items interface{}
+	switch q.Get("format") {
+	case "", "nested":
+		nodes := make([]services.Contract, len(list.Edges))
+		for i, edge := range list.Edges {
+			nodes[i] = edge.Node
+		}
+		items = nodes
+	case "flat":
+		items = list.Flatten()
+	default:
+		s.fail(w, r, &errors.ValidationError{Field: "format", Message: "must be nested or flat", Value: q.Get("format")})
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Items: items, TotalCount: list.TotalCount, PageInfo: list.PageInfo})
+}
+
+// GET /v1/contracts/{id}
+func (s *server) getContract(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "/v1/contracts/")
+	if !ok || !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	contract, err := s.client.Contracts.GetContract(r.Context(), id)
+	if err == nil && contract == nil {
+		err = errors.ErrNotFound
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	if r.URL.Query().Get("format") == "flat" {
+		writeJSON(w, http.StatusOK, contract.Flat())
+		return
+	}
+	writeJSON(w, http.StatusOK, contract)
+}
+
+// GET /v1/jobs?status=open&first=20&after=...
+func (s *server) listJobs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	pagination, err := parsePagination(r)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	input := services.ListJobsInput{Pagination: pagination}
+	if status := r.URL.Query().Get("status"); status != "" {
+		input.Status = []services.JobStatus{services.JobStatus(upperEnum(status))}
+	}
+
+	list, err := s.client.Jobs.ListJobs(r.Context(), input)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	jobs := make([]services.JobPosting, len(list.Edges))
+	for i, edge := range list.Edges {
+		jobs[i] = edge.Node
+	}
+	writeJSON(w, http.StatusOK, page{Items: jobs, TotalCount: list.TotalCount, PageInfo: list.PageInfo})
+}
+
+// GET /v1/jobs/search?q=golang&type=hourly&first=20&after=...
+func (s *server) searchJobs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	pagination, err := parsePagination(r)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := services.MarketplaceJobFilter{SearchExpression: q.Get("q"), Pagination: pagination}
+	if typ := q.Get("type"); typ != "" {
+		filter.JobType = services.ContractType(upperEnum(typ))
+	}
+
+	result, err := s.client.Jobs.SearchJobs(r.Context(), filter)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GET /v1/jobs/{id}
+func (s *server) getJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "/v1/jobs/")
+	if !ok || !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	job, err := s.client.Jobs.GetJobPosting(r.Context(), id)
+	if err == nil && job == nil {
+		err = errors.ErrNotFound
+	}
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// GET /v1/rooms?unread=true&first=20&after=...
+func (s *server) listRooms(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	pagination, err := parsePagination(r)
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+
+	var filter *services.RoomFilter
+	if unread, _ := strconv.ParseBool(r.URL.Query().Get("unread")); unread {
+		filter = &services.RoomFilter{UnreadRoomsOnly: true}
+	}
+
+	list, err := s.client.Messages.ListRooms(r.Context(), filter, pagination, "")
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	rooms := make([]services.Room, len(list.Edges))
+	for i, edge := range list.Edges {
+		rooms[i] = edge.Node
+	}
+	writeJSON(w, http.StatusOK, page{Items: rooms, TotalCount: list.TotalCount, PageInfo: list.PageInfo})
+}
+
+// GET /v1/rooms/{id}/messages?first=20
+// POST /v1/rooms/{id}/messages {"message": "..."}
+func (s *server) roomMessages(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/rooms/")
+	roomID, ok := strings.CutSuffix(rest, "/messages")
+	if !ok || roomID == "" || strings.Contains(roomID, "/") {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+		return
+	}
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		pagination, err := parsePagination(r)
+		if err != nil {
+			s.fail(w, r, err)
+			return
+		}
+		stories, err := s.client.Messages.GetRoomStories(r.Context(), roomID, pagination)
+		if err != nil {
+			s.fail(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, page{Items: stories, TotalCount: len(stories)})
+		return
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		s.fail(w, r, &errors.ValidationError{Message: "body must be a JSON object with a message"})
+		return
+	}
+
+	story, err := s.client.Messages.SendMessage(r.Context(), services.CreateStoryInput{RoomID: roomID, Message: body.Message})
+	if err != nil {
+		s.fail(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, story)
+}
+
+// page is the body of list responses
+type page struct {
+	Items      interface{}     `json:"items"`
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+}
+
+// fail writes the response for err. Upstream failures are logged in full
+// but reported to callers without detail, which may include the gateway's
+// own credentials or tenant.
+func (s *server) fail(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *errors.ValidationError
+	var rateLimitErr *errors.RateLimitError
+	var apiErr *errors.APIError
+
+	switch {
+	case stderrors.As(err, &validationErr):
+		writeError(w, http.StatusBadRequest, "invalid_request", validationErr.Error())
+		return
+	case stderrors.Is(err, errors.ErrNotFound), stderrors.As(err, &apiErr) && apiErr.IsNotFound():
+		writeError(w, http.StatusNotFound, "not_found", "resource not found")
+		return
+	case stderrors.As(err, &rateLimitErr):
+		if rateLimitErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(rateLimitErr.RetryAfter.Round(time.Second)/time.Second)))
+		}
+		writeError(w, http.StatusTooManyRequests, "rate_limited", "upstream rate limit exceeded")
+	case stderrors.Is(err, errors.ErrRateLimitExceeded), stderrors.Is(err, errors.ErrWouldExceedDeadline):
+		writeError(w, http.StatusTooManyRequests, "rate_limited", "upstream rate limit exceeded")
+	case stderrors.Is(err, context.DeadlineExceeded), stderrors.Is(err, errors.ErrRequestTimeout):
+		writeError(w, http.StatusGatewayTimeout, "timeout", "upstream request timed out")
+	case stderrors.Is(err, context.Canceled):
+		// The caller went away; there is no one to answer
+	default:
+		writeError(w, http.StatusBadGateway, "upstream_error", "upstream request failed")
+	}
+	s.logger.Warn("upstream request failed", "method", r.Method, "path", r.URL.Path, "caller", caller(r), "error", err)
+}
+
+// allowMethods writes 405 and returns false unless r uses one of methods
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" is not allowed")
+	return false
+}
+
+// pathID returns the single path segment after prefix, writing 404 if
+// there is none or more than one
+func pathID(w http.ResponseWriter, r *http.Request, prefix string) (string, bool) {
+	id := strings.TrimPrefix(r.URL.Path, prefix)
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+		return "", false
+	}
+	return id, true
+}
+
+// parsePagination reads the first and after query parameters
+func parsePagination(r *http.Request) (*models.PaginationInput, error) {
+	q := r.URL.Query()
+	if q.Get("first") == "" && q.Get("after") == "" {
+		return nil, nil
+	}
+
+	pagination := &models.PaginationInput{After: q.Get("after"), First: maxPageSize}
+	if first := q.Get("first"); first != "" {
+		n, err := strconv.Atoi(first)
+		if err != nil || n < 1 || n > maxPageSize {
+			return nil, &errors.ValidationError{Field: "first", Message: fmt.Sprintf("must be between 1 and %d", maxPageSize), Value: first}
+		}
+		pagination.First = n
+	}
+	return pagination, nil
+}
+
+// upperEnum normalizes a query value such as "fixed-price" to the API enum
+// form
+func upperEnum(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+}
+
+// writeJSON writes v as the response body with status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body with status
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	type body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	writeJSON(w, status, map[string]body{"error": {Code: code, Message: message}})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+const testSecret = "0123456789abcdef"
+
+// newTestGateway returns a gateway backed by the fake API
+func newTestGateway(t *testing.T, rate int) (*httptest.Server, *upworktest.Server) {
+	client, api := upworktest.NewFakeClient(t, nil)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	gw := httptest.NewServer(newServer(client, []apiKey{{name: "bi", secret: testSecret}}, rate, logger).handler())
+	t.Cleanup(gw.Close)
+	return gw, api
+}
+
+// call makes a request to the gateway and decodes the JSON response
+func call(t *testing.T, gw *httptest.Server, method, path, secret, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, gw.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+	resp, err := gw.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+func TestAuthentication(t *testing.T) {
+	gw, _ := newTestGateway(t, 0)
+
+	status, _ := call(t, gw, http.MethodGet, "/healthz", "", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := call(t, gw, http.MethodGet, "/v1/contracts", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "unauthorized", body["error"].(map[string]interface{})["code"])
+
+	status, _ = call(t, gw, http.MethodGet, "/v1/contracts", "wrong-secret-0000", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestContracts(t *testing.T) {
+	gw, _ := newTestGateway(t, 0)
+
+	status, body := call(t, gw, http.MethodGet, "/v1/contracts?status=active&format=flat&first=10", testSecret, "")
+	require.Equal(t, http.StatusOK, status)
+	items := body["items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "contract-1", items[0].(map[string]interface{})["id"])
+	assert.Contains(t, items[0], "hourly_rate")
+
+	status, body = call(t, gw, http.MethodGet, "/v1/contracts/contract-2", testSecret, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "contract-2", body["id"])
+
+	status, body = call(t, gw, http.MethodGet, "/v1/contracts?first=500", testSecret, "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "invalid_request", body["error"].(map[string]interface{})["code"])
+
+	status, _ = call(t, gw, http.MethodDelete, "/v1/contracts/contract-1", testSecret, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+func TestRoomMessages(t *testing.T) {
+	gw, api := newTestGateway(t, 0)
+
+	status, body := call(t, gw, http.MethodPost, "/v1/rooms/room-1/messages", testSecret, `{"message":"Shipped"}`)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "Shipped", body["message"])
+	assert.Len(t, api.Fixtures().Stories["room-1"], 2)
+
+	status, body = call(t, gw, http.MethodGet, "/v1/rooms/room-1/messages", testSecret, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body["items"], 2)
+
+	status, _ = call(t, gw, http.MethodPost, "/v1/rooms/room-1/messages", testSecret, `{"text":"Shipped"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Upstream errors are reported without their detail
+	status, body = call(t, gw, http.MethodGet, "/v1/rooms/room-9/messages", testSecret, "")
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Equal(t, "upstream request failed", body["error"].(map[string]interface{})["message"])
+}
+
+func TestRateLimit(t *testing.T) {
+	gw, _ := newTestGateway(t, 1)
+
+	status, _ := call(t, gw, http.MethodGet, "/v1/rooms", testSecret, "")
+	assert.Equal(t, http.StatusOK, status)
+	status, body := call(t, gw, http.MethodGet, "/v1/rooms", testSecret, "")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, "rate_limited", body["error"].(map[string]interface{})["code"])
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := parseKeys("bi:" + testSecret + ", ops:" + testSecret + "ops")
+	require.NoError(t, err)
+	assert.Equal(t, []apiKey{{"bi", testSecret}, {"ops", testSecret + "ops"}}, keys)
+
+	for _, s := range []string{"", "bi", "bi:short", "bi:" + testSecret + ",bi:" + testSecret} {
+		_, err := parseKeys(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"log"
 	"os"
 	
-	"github.com/rizome-dev/go-upwork/pkg"
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
 )
 
 func main() {
 	// Create a new client configuration
-	config := &pkg.Config{
+	config := &upwork.Config{
 		ClientID:     os.Getenv("UPWORK_CLIENT_ID"),
 		ClientSecret: os.Getenv("UPWORK_CLIENT_SECRET"),
 		RedirectURL:  os.Getenv("UPWORK_REDIRECT_URL"),
@@ -20,10 +22,11 @@ func main() {
 	
 	// Create a new client
 	ctx := context.Background()
-	client, err := pkg.NewClient(ctx, config)
+	client, err := upwork.NewClient(ctx, config)
 	if err != nil {
 		log.Fatal("Failed to create client:", err)
 	}
+	defer client.Close()
 	
 	// Example 1: OAuth2 Authentication Flow
 	// Get the authorization URL
@@ -73,7 +76,7 @@ func main() {
 	
 	// Example 4: List Contracts
 	contractsResp, err := client.Contracts.ListContracts(ctx, services.ListContractsInput{
-		Pagination: &services.PaginationInput{
+		Pagination: &models.PaginationInput{
 			First: 10,
 		},
 		Filter: &services.ContractFilter{
@@ -97,7 +100,7 @@ func main() {
 		SearchExpression: "golang developer",
 		JobType:         services.ContractTypeHourly,
 		DaysPosted:      7,
-		Pagination: &services.PaginationInput{
+		Pagination: &models.PaginationInput{
 			First: 5,
 		},
 	})
@@ -107,9 +110,9 @@ func main() {
 	
 	fmt.Printf("\n=== Job Search Results ===\n")
 	fmt.Printf("Found %d jobs matching 'golang developer'\n", jobSearchResp.TotalCount)
-	for _, edge := range jobSearchResp.Edges {
-		job := edge.Node
-		fmt.Printf("- %s\n", job.Title)
+	for _, job := range jobSearchResp.Jobs() {
+		fmt.Printf("- %s (client in %s, %d hires)\n",
+			job.Title, job.Client.Location.Country, job.Client.TotalHires)
 	}
 	
 	// Example 6: List Chat Rooms
@@ -117,10 +120,10 @@ func main() {
 		&services.RoomFilter{
 			UnreadRoomsOnly: true,
 		},
-		&services.PaginationInput{
+		&models.PaginationInput{
 			First: 10,
 		},
-		services.SortOrderDesc,
+		models.SortOrderDesc,
 	)
 	if err != nil {
 		log.Fatal("Failed to list rooms:", err)
@@ -162,11 +165,15 @@ func main() {
 	
 	// Example 9: Create a Job Posting (commented out to avoid creating real jobs)
 	/*
+	category, subcategory, err := client.Metadata.FindSubcategory(ctx, "web-development")
+	if err != nil {
+		log.Fatal("Failed to look up category:", err)
+	}
 	newJob, err := client.Jobs.CreateJobPosting(ctx, services.CreateJobPostingInput{
 		Title:           "Go Developer Needed for SDK Development",
 		Description:     "Looking for an experienced Go developer to help build SDK features.",
-		CategoryID:      "531770282580668419", // Web Development
-		SubCategoryID:   "531770282589057033", // Web Development
+		CategoryID:      string(category.ID),
+		SubCategoryID:   string(subcategory.ID),
 		Skills:          []string{"golang", "api-development", "sdk"},
 		ContractType:    services.ContractTypeHourly,
 		HourlyBudgetMin: floatPtr(50),
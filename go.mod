@@ -4,18 +4,21 @@ go 1.21
 
 require (
 	github.com/Khan/genqlient v0.6.0
+	github.com/goccy/go-json v0.9.11
 	github.com/gorilla/websocket v1.5.1
 	github.com/stretchr/testify v1.8.4
+	github.com/vektah/gqlparser/v2 v2.5.1
+	golang.org/x/net v0.19.0
 	golang.org/x/oauth2 v0.15.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/agnivade/levenshtein v1.1.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/vektah/gqlparser/v2 v2.5.1 // indirect
-	golang.org/x/net v0.19.0 // indirect
+	github.com/stretchr/objx v0.5.0 // indirect
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
-)
\ No newline at end of file
+)
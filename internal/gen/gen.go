@@ -0,0 +1,6 @@
+// Package gen contains GraphQL operations generated by genqlient from the
+// Upwork schema. Do not edit generated.go by hand; update the operations or
+// schema and run go generate.
+package gen
+
+//go:generate go run github.com/Khan/genqlient@v0.6.0 genqlient.yaml
@@ -0,0 +1,626 @@
+// Code generated by github.com/Khan/genqlient, DO NOT EDIT.
+
+package gen
+
+import (
+	"context"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// GetCountriesCountriesCountry includes the requested fields of the GraphQL type Country.
+type GetCountriesCountriesCountry struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+// GetId returns GetCountriesCountriesCountry.Id, and is useful for accessing the field via an interface.
+func (v *GetCountriesCountriesCountry) GetId() string { return v.Id }
+
+// GetName returns GetCountriesCountriesCountry.Name, and is useful for accessing the field via an interface.
+func (v *GetCountriesCountriesCountry) GetName() string { return v.Name }
+
+// GetCode returns GetCountriesCountriesCountry.Code, and is useful for accessing the field via an interface.
+func (v *GetCountriesCountriesCountry) GetCode() string { return v.Code }
+
+// GetCountriesResponse is returned by GetCountries on success.
+type GetCountriesResponse struct {
+	Countries []GetCountriesCountriesCountry `json:"countries"`
+}
+
+// GetCountries returns GetCountriesResponse.Countries, and is useful for accessing the field via an interface.
+func (v *GetCountriesResponse) GetCountries() []GetCountriesCountriesCountry { return v.Countries }
+
+// GetLanguagesLanguagesLanguage includes the requested fields of the GraphQL type Language.
+type GetLanguagesLanguagesLanguage struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+// GetId returns GetLanguagesLanguagesLanguage.Id, and is useful for accessing the field via an interface.
+func (v *GetLanguagesLanguagesLanguage) GetId() string { return v.Id }
+
+// GetName returns GetLanguagesLanguagesLanguage.Name, and is useful for accessing the field via an interface.
+func (v *GetLanguagesLanguagesLanguage) GetName() string { return v.Name }
+
+// GetCode returns GetLanguagesLanguagesLanguage.Code, and is useful for accessing the field via an interface.
+func (v *GetLanguagesLanguagesLanguage) GetCode() string { return v.Code }
+
+// GetLanguagesResponse is returned by GetLanguages on success.
+type GetLanguagesResponse struct {
+	Languages []GetLanguagesLanguagesLanguage `json:"languages"`
+}
+
+// GetLanguages returns GetLanguagesResponse.Languages, and is useful for accessing the field via an interface.
+func (v *GetLanguagesResponse) GetLanguages() []GetLanguagesLanguagesLanguage { return v.Languages }
+
+// GetOntologyCategoriesOntologyCategoriesOntologyCategory includes the requested fields of the GraphQL type OntologyCategory.
+type GetOntologyCategoriesOntologyCategoriesOntologyCategory struct {
+	Id             string                                                                                    `json:"id"`
+	PreferredLabel string                                                                                    `json:"preferredLabel"`
+	AltLabel       string                                                                                    `json:"altLabel"`
+	Slug           string                                                                                    `json:"slug"`
+	OntologyId     string                                                                                    `json:"ontologyId"`
+	Subcategories  []GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory `json:"subcategories"`
+	Services       []GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService          `json:"services"`
+}
+
+// GetId returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.Id, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetId() string { return v.Id }
+
+// GetPreferredLabel returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.PreferredLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetPreferredLabel() string {
+	return v.PreferredLabel
+}
+
+// GetAltLabel returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.AltLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetAltLabel() string {
+	return v.AltLabel
+}
+
+// GetSlug returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.Slug, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetSlug() string { return v.Slug }
+
+// GetOntologyId returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.OntologyId, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetOntologyId() string {
+	return v.OntologyId
+}
+
+// GetSubcategories returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.Subcategories, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetSubcategories() []GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory {
+	return v.Subcategories
+}
+
+// GetServices returns GetOntologyCategoriesOntologyCategoriesOntologyCategory.Services, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategory) GetServices() []GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService {
+	return v.Services
+}
+
+// GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService includes the requested fields of the GraphQL type OntologyService.
+type GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService struct {
+	Id             string `json:"id"`
+	PreferredLabel string `json:"preferredLabel"`
+}
+
+// GetId returns GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService.Id, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService) GetId() string {
+	return v.Id
+}
+
+// GetPreferredLabel returns GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService.PreferredLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategoryServicesOntologyService) GetPreferredLabel() string {
+	return v.PreferredLabel
+}
+
+// GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory includes the requested fields of the GraphQL type OntologySubcategory.
+type GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory struct {
+	Id             string `json:"id"`
+	PreferredLabel string `json:"preferredLabel"`
+	AltLabel       string `json:"altLabel"`
+	Slug           string `json:"slug"`
+}
+
+// GetId returns GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory.Id, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory) GetId() string {
+	return v.Id
+}
+
+// GetPreferredLabel returns GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory.PreferredLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory) GetPreferredLabel() string {
+	return v.PreferredLabel
+}
+
+// GetAltLabel returns GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory.AltLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory) GetAltLabel() string {
+	return v.AltLabel
+}
+
+// GetSlug returns GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory.Slug, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesOntologyCategoriesOntologyCategorySubcategoriesOntologySubcategory) GetSlug() string {
+	return v.Slug
+}
+
+// GetOntologyCategoriesResponse is returned by GetOntologyCategories on success.
+type GetOntologyCategoriesResponse struct {
+	OntologyCategories []GetOntologyCategoriesOntologyCategoriesOntologyCategory `json:"ontologyCategories"`
+}
+
+// GetOntologyCategories returns GetOntologyCategoriesResponse.OntologyCategories, and is useful for accessing the field via an interface.
+func (v *GetOntologyCategoriesResponse) GetOntologyCategories() []GetOntologyCategoriesOntologyCategoriesOntologyCategory {
+	return v.OntologyCategories
+}
+
+// GetOntologySkillsOntologyBrowserSkillsOntologySkill includes the requested fields of the GraphQL type OntologySkill.
+type GetOntologySkillsOntologyBrowserSkillsOntologySkill struct {
+	Id             string `json:"id"`
+	PreferredLabel string `json:"preferredLabel"`
+}
+
+// GetId returns GetOntologySkillsOntologyBrowserSkillsOntologySkill.Id, and is useful for accessing the field via an interface.
+func (v *GetOntologySkillsOntologyBrowserSkillsOntologySkill) GetId() string { return v.Id }
+
+// GetPreferredLabel returns GetOntologySkillsOntologyBrowserSkillsOntologySkill.PreferredLabel, and is useful for accessing the field via an interface.
+func (v *GetOntologySkillsOntologyBrowserSkillsOntologySkill) GetPreferredLabel() string {
+	return v.PreferredLabel
+}
+
+// GetOntologySkillsResponse is returned by GetOntologySkills on success.
+type GetOntologySkillsResponse struct {
+	OntologyBrowserSkills []GetOntologySkillsOntologyBrowserSkillsOntologySkill `json:"ontologyBrowserSkills"`
+}
+
+// GetOntologyBrowserSkills returns GetOntologySkillsResponse.OntologyBrowserSkills, and is useful for accessing the field via an interface.
+func (v *GetOntologySkillsResponse) GetOntologyBrowserSkills() []GetOntologySkillsOntologyBrowserSkillsOntologySkill {
+	return v.OntologyBrowserSkills
+}
+
+// GetReasonsReasonsReason includes the requested fields of the GraphQL type Reason.
+type GetReasonsReasonsReason struct {
+	Id     string `json:"id"`
+	Reason string `json:"reason"`
+	Alias  string `json:"alias"`
+}
+
+// GetId returns GetReasonsReasonsReason.Id, and is useful for accessing the field via an interface.
+func (v *GetReasonsReasonsReason) GetId() string { return v.Id }
+
+// GetReason returns GetReasonsReasonsReason.Reason, and is useful for accessing the field via an interface.
+func (v *GetReasonsReasonsReason) GetReason() string { return v.Reason }
+
+// GetAlias returns GetReasonsReasonsReason.Alias, and is useful for accessing the field via an interface.
+func (v *GetReasonsReasonsReason) GetAlias() string { return v.Alias }
+
+// GetReasonsResponse is returned by GetReasons on success.
+type GetReasonsResponse struct {
+	Reasons []GetReasonsReasonsReason `json:"reasons"`
+}
+
+// GetReasons returns GetReasonsResponse.Reasons, and is useful for accessing the field via an interface.
+func (v *GetReasonsResponse) GetReasons() []GetReasonsReasonsReason { return v.Reasons }
+
+// GetRegionsRegionsRegion includes the requested fields of the GraphQL type Region.
+type GetRegionsRegionsRegion struct {
+	Id           string                               `json:"id"`
+	Name         string                               `json:"name"`
+	ParentRegion *GetRegionsRegionsRegionParentRegion `json:"parentRegion"`
+}
+
+// GetId returns GetRegionsRegionsRegion.Id, and is useful for accessing the field via an interface.
+func (v *GetRegionsRegionsRegion) GetId() string { return v.Id }
+
+// GetName returns GetRegionsRegionsRegion.Name, and is useful for accessing the field via an interface.
+func (v *GetRegionsRegionsRegion) GetName() string { return v.Name }
+
+// GetParentRegion returns GetRegionsRegionsRegion.ParentRegion, and is useful for accessing the field via an interface.
+func (v *GetRegionsRegionsRegion) GetParentRegion() *GetRegionsRegionsRegionParentRegion {
+	return v.ParentRegion
+}
+
+// GetRegionsRegionsRegionParentRegion includes the requested fields of the GraphQL type Region.
+type GetRegionsRegionsRegionParentRegion struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetId returns GetRegionsRegionsRegionParentRegion.Id, and is useful for accessing the field via an interface.
+func (v *GetRegionsRegionsRegionParentRegion) GetId() string { return v.Id }
+
+// GetName returns GetRegionsRegionsRegionParentRegion.Name, and is useful for accessing the field via an interface.
+func (v *GetRegionsRegionsRegionParentRegion) GetName() string { return v.Name }
+
+// GetRegionsResponse is returned by GetRegions on success.
+type GetRegionsResponse struct {
+	Regions []GetRegionsRegionsRegion `json:"regions"`
+}
+
+// GetRegions returns GetRegionsResponse.Regions, and is useful for accessing the field via an interface.
+func (v *GetRegionsResponse) GetRegions() []GetRegionsRegionsRegion { return v.Regions }
+
+// GetTimeZonesResponse is returned by GetTimeZones on success.
+type GetTimeZonesResponse struct {
+	TimeZones []GetTimeZonesTimeZonesTimeZone `json:"timeZones"`
+}
+
+// GetTimeZones returns GetTimeZonesResponse.TimeZones, and is useful for accessing the field via an interface.
+func (v *GetTimeZonesResponse) GetTimeZones() []GetTimeZonesTimeZonesTimeZone { return v.TimeZones }
+
+// GetTimeZonesTimeZonesTimeZone includes the requested fields of the GraphQL type TimeZone.
+type GetTimeZonesTimeZonesTimeZone struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+}
+
+// GetId returns GetTimeZonesTimeZonesTimeZone.Id, and is useful for accessing the field via an interface.
+func (v *GetTimeZonesTimeZonesTimeZone) GetId() string { return v.Id }
+
+// GetName returns GetTimeZonesTimeZonesTimeZone.Name, and is useful for accessing the field via an interface.
+func (v *GetTimeZonesTimeZonesTimeZone) GetName() string { return v.Name }
+
+// GetOffset returns GetTimeZonesTimeZonesTimeZone.Offset, and is useful for accessing the field via an interface.
+func (v *GetTimeZonesTimeZonesTimeZone) GetOffset() int { return v.Offset }
+
+type ReasonType string
+
+const (
+	ReasonTypeJobPostingClose ReasonType = "JOB_POSTING_CLOSE"
+	ReasonTypeContractEnd     ReasonType = "CONTRACT_END"
+	ReasonTypeProposalDecline ReasonType = "PROPOSAL_DECLINE"
+)
+
+// SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill includes the requested fields of the GraphQL type OntologySkill.
+type SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill struct {
+	Id             string `json:"id"`
+	PreferredLabel string `json:"preferredLabel"`
+}
+
+// GetId returns SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill.Id, and is useful for accessing the field via an interface.
+func (v *SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill) GetId() string { return v.Id }
+
+// GetPreferredLabel returns SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill.PreferredLabel, and is useful for accessing the field via an interface.
+func (v *SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill) GetPreferredLabel() string {
+	return v.PreferredLabel
+}
+
+// SearchSkillsResponse is returned by SearchSkills on success.
+type SearchSkillsResponse struct {
+	OntologyElementsSearchByPrefLabel []SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill `json:"ontologyElementsSearchByPrefLabel"`
+}
+
+// GetOntologyElementsSearchByPrefLabel returns SearchSkillsResponse.OntologyElementsSearchByPrefLabel, and is useful for accessing the field via an interface.
+func (v *SearchSkillsResponse) GetOntologyElementsSearchByPrefLabel() []SearchSkillsOntologyElementsSearchByPrefLabelOntologySkill {
+	return v.OntologyElementsSearchByPrefLabel
+}
+
+// __GetOntologySkillsInput is used internally by genqlient
+type __GetOntologySkillsInput struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// GetLimit returns __GetOntologySkillsInput.Limit, and is useful for accessing the field via an interface.
+func (v *__GetOntologySkillsInput) GetLimit() int { return v.Limit }
+
+// GetOffset returns __GetOntologySkillsInput.Offset, and is useful for accessing the field via an interface.
+func (v *__GetOntologySkillsInput) GetOffset() int { return v.Offset }
+
+// __GetReasonsInput is used internally by genqlient
+type __GetReasonsInput struct {
+	ReasonType ReasonType `json:"reasonType"`
+	All        bool       `json:"all"`
+}
+
+// GetReasonType returns __GetReasonsInput.ReasonType, and is useful for accessing the field via an interface.
+func (v *__GetReasonsInput) GetReasonType() ReasonType { return v.ReasonType }
+
+// GetAll returns __GetReasonsInput.All, and is useful for accessing the field via an interface.
+func (v *__GetReasonsInput) GetAll() bool { return v.All }
+
+// __SearchSkillsInput is used internally by genqlient
+type __SearchSkillsInput struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+// GetQuery returns __SearchSkillsInput.Query, and is useful for accessing the field via an interface.
+func (v *__SearchSkillsInput) GetQuery() string { return v.Query }
+
+// GetLimit returns __SearchSkillsInput.Limit, and is useful for accessing the field via an interface.
+func (v *__SearchSkillsInput) GetLimit() int { return v.Limit }
+
+// The query or mutation executed by GetCountries.
+const GetCountries_Operation = `
+query GetCountries {
+	countries {
+		id
+		name
+		code
+	}
+}
+`
+
+func GetCountries(
+	ctx context.Context,
+	client graphql.Client,
+) (*GetCountriesResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetCountries",
+		Query:  GetCountries_Operation,
+	}
+	var err error
+
+	var data GetCountriesResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetLanguages.
+const GetLanguages_Operation = `
+query GetLanguages {
+	languages {
+		id
+		name
+		code
+	}
+}
+`
+
+func GetLanguages(
+	ctx context.Context,
+	client graphql.Client,
+) (*GetLanguagesResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetLanguages",
+		Query:  GetLanguages_Operation,
+	}
+	var err error
+
+	var data GetLanguagesResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetOntologyCategories.
+const GetOntologyCategories_Operation = `
+query GetOntologyCategories {
+	ontologyCategories {
+		id
+		preferredLabel
+		altLabel
+		slug
+		ontologyId
+		subcategories {
+			id
+			preferredLabel
+			altLabel
+			slug
+		}
+		services {
+			id
+			preferredLabel
+		}
+	}
+}
+`
+
+func GetOntologyCategories(
+	ctx context.Context,
+	client graphql.Client,
+) (*GetOntologyCategoriesResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetOntologyCategories",
+		Query:  GetOntologyCategories_Operation,
+	}
+	var err error
+
+	var data GetOntologyCategoriesResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetOntologySkills.
+const GetOntologySkills_Operation = `
+query GetOntologySkills ($limit: Int!, $offset: Int) {
+	ontologyBrowserSkills(limit: $limit, offset: $offset) {
+		id
+		preferredLabel
+	}
+}
+`
+
+func GetOntologySkills(
+	ctx context.Context,
+	client graphql.Client,
+	limit int,
+	offset int,
+) (*GetOntologySkillsResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetOntologySkills",
+		Query:  GetOntologySkills_Operation,
+		Variables: &__GetOntologySkillsInput{
+			Limit:  limit,
+			Offset: offset,
+		},
+	}
+	var err error
+
+	var data GetOntologySkillsResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetReasons.
+const GetReasons_Operation = `
+query GetReasons ($reasonType: ReasonType!, $all: Boolean) {
+	reasons(reasonType: $reasonType, all: $all) {
+		id
+		reason
+		alias
+	}
+}
+`
+
+func GetReasons(
+	ctx context.Context,
+	client graphql.Client,
+	reasonType ReasonType,
+	all bool,
+) (*GetReasonsResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetReasons",
+		Query:  GetReasons_Operation,
+		Variables: &__GetReasonsInput{
+			ReasonType: reasonType,
+			All:        all,
+		},
+	}
+	var err error
+
+	var data GetReasonsResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetRegions.
+const GetRegions_Operation = `
+query GetRegions {
+	regions {
+		id
+		name
+		parentRegion {
+			id
+			name
+		}
+	}
+}
+`
+
+func GetRegions(
+	ctx context.Context,
+	client graphql.Client,
+) (*GetRegionsResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetRegions",
+		Query:  GetRegions_Operation,
+	}
+	var err error
+
+	var data GetRegionsResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by GetTimeZones.
+const GetTimeZones_Operation = `
+query GetTimeZones {
+	timeZones {
+		id
+		name
+		offset
+	}
+}
+`
+
+func GetTimeZones(
+	ctx context.Context,
+	client graphql.Client,
+) (*GetTimeZonesResponse, error) {
+	req := &graphql.Request{
+		OpName: "GetTimeZones",
+		Query:  GetTimeZones_Operation,
+	}
+	var err error
+
+	var data GetTimeZonesResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+// The query or mutation executed by SearchSkills.
+const SearchSkills_Operation = `
+query SearchSkills ($query: String!, $limit: Int!) {
+	ontologyElementsSearchByPrefLabel(prefLabel: $query, elementType: "skill", limit: $limit) {
+		id
+		preferredLabel
+	}
+}
+`
+
+func SearchSkills(
+	ctx context.Context,
+	client graphql.Client,
+	query string,
+	limit int,
+) (*SearchSkillsResponse, error) {
+	req := &graphql.Request{
+		OpName: "SearchSkills",
+		Query:  SearchSkills_Operation,
+		Variables: &__SearchSkillsInput{
+			Query: query,
+			Limit: limit,
+		},
+	}
+	var err error
+
+	var data SearchSkillsResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/codec"
 )
 
 // Request represents a GraphQL request
@@ -43,6 +45,7 @@ type Client struct {
 	httpClient *http.Client
 	endpoint   string
 	headers    map[string]string
+	codec      codec.Codec
 }
 
 // NewClient creates a new GraphQL client
@@ -57,6 +60,7 @@ func NewClient(httpClient *http.Client, endpoint string) *Client {
 		httpClient: httpClient,
 		endpoint:   endpoint,
 		headers:    make(map[string]string),
+		codec:      codec.Default(),
 	}
 }
 
@@ -65,10 +69,15 @@ func (c *Client) SetHeader(key, value string) {
 	c.headers[key] = value
 }
 
+// SetCodec sets the codec requests and responses are encoded with
+func (c *Client) SetCodec(codec codec.Codec) {
+	c.codec = codec
+}
+
 // Do executes a GraphQL request
 func (c *Client) Do(ctx context.Context, req *Request, result interface{}) error {
 	// Marshal request
-	body, err := json.Marshal(req)
+	body, err := c.codec.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -107,7 +116,7 @@ func (c *Client) Do(ctx context.Context, req *Request, result interface{}) error
 	
 	// Parse GraphQL response
 	var graphqlResp Response
-	if err := json.Unmarshal(respBody, &graphqlResp); err != nil {
+	if err := c.codec.Unmarshal(respBody, &graphqlResp); err != nil {
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 	
@@ -118,7 +127,7 @@ func (c *Client) Do(ctx context.Context, req *Request, result interface{}) error
 	
 	// Unmarshal data if result is provided
 	if result != nil && graphqlResp.Data != nil {
-		if err := json.Unmarshal(graphqlResp.Data, result); err != nil {
+		if err := c.codec.Unmarshal(graphqlResp.Data, result); err != nil {
 			return fmt.Errorf("failed to unmarshal response data: %w", err)
 		}
 	}
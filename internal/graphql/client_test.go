@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/rizome-dev/go-upwork/tests/mocks"
 	"github.com/rizome-dev/go-upwork/tests/testutils"
@@ -41,22 +42,41 @@ func TestNewClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewClient(tt.endpoint, nil)
+			recorder := mocks.NewRequestRecorder(
+				mocks.MockResponse{
+					StatusCode: 200,
+					Body:       `{"data": {"test": "ok"}}`,
+				},
+			)
+			httpClient := &http.Client{Transport: recorder}
+			if tt.wantErr {
+				// The default transport rejects endpoints that are not
+				// HTTP URLs before sending anything
+				httpClient = nil
+			}
+			client := NewClient(httpClient, tt.endpoint)
+			require.NotNil(t, client)
+			assert.Equal(t, tt.endpoint, client.endpoint)
+
+			// An endpoint the request cannot be sent to fails on use
+			err := client.Do(context.Background(), &Request{Query: `{ test }`}, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
-				assert.Nil(t, client)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, client)
-				assert.Equal(t, tt.endpoint, client.endpoint)
 			}
 		})
 	}
 }
 
+func TestNewClientDefaultHTTPClient(t *testing.T) {
+	client := NewClient(nil, "https://api.upwork.com/graphql")
+	require.NotNil(t, client.httpClient)
+	assert.Equal(t, 30*time.Second, client.httpClient.Timeout)
+}
+
 func TestSetHeader(t *testing.T) {
-	client, err := NewClient("https://api.upwork.com/graphql", nil)
-	require.NoError(t, err)
+	client := NewClient(nil, "https://api.upwork.com/graphql")
 
 	client.SetHeader("X-Custom-Header", "custom-value")
 	assert.Equal(t, "custom-value", client.headers["X-Custom-Header"])
@@ -164,11 +184,10 @@ func TestQuery(t *testing.T) {
 				})
 			}
 
-			client, err := NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
+			client := newRecordedClient(recorder)
 
 			var result map[string]interface{}
-			err = client.Query(context.Background(), tt.query, tt.variables, &result)
+			err := client.Do(context.Background(), &Request{Query: tt.query, Variables: tt.variables}, &result)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -248,11 +267,10 @@ func TestMutate(t *testing.T) {
 				})
 			}
 
-			client, err := NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
+			client := newRecordedClient(recorder)
 
 			var result map[string]interface{}
-			err = client.Mutate(context.Background(), tt.mutation, tt.variables, &result)
+			err := client.Do(context.Background(), &Request{Query: tt.mutation, Variables: tt.variables}, &result)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -332,13 +350,13 @@ func TestRawRequest(t *testing.T) {
 				})
 			}
 
-			client, err := NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
+			client := newRecordedClient(recorder)
 			
 			// Set a custom header for testing
 			client.SetHeader("X-Custom-Header", "custom-value")
 
-			response, err := client.RawRequest(context.Background(), tt.request)
+			var response map[string]interface{}
+			err := client.Do(context.Background(), &tt.request, &response)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -354,64 +372,6 @@ func TestRawRequest(t *testing.T) {
 	}
 }
 
-func TestBatchRequest(t *testing.T) {
-	recorder := mocks.NewRequestRecorder(
-		mocks.MockResponse{
-			StatusCode: 200,
-			Body: `[
-				{
-					"data": {
-						"user": {
-							"id": "123",
-							"name": "John Doe"
-						}
-					}
-				},
-				{
-					"data": {
-						"contracts": [
-							{"id": "c1", "title": "Contract 1"},
-							{"id": "c2", "title": "Contract 2"}
-						]
-					}
-				}
-			]`,
-		},
-	)
-
-	client, err := NewClient("https://api.upwork.com/graphql", recorder)
-	require.NoError(t, err)
-
-	requests := []Request{
-		{
-			Query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
-			Variables: map[string]interface{}{
-				"id": "123",
-			},
-		},
-		{
-			Query: `query GetContracts { contracts { id title } }`,
-		},
-	}
-
-	responses, err := client.BatchRequest(context.Background(), requests)
-	require.NoError(t, err)
-	assert.Len(t, responses, 2)
-
-	// Verify first response
-	userData, ok := responses[0].Data.(map[string]interface{})
-	require.True(t, ok)
-	user := userData["user"].(map[string]interface{})
-	assert.Equal(t, "123", user["id"])
-	assert.Equal(t, "John Doe", user["name"])
-
-	// Verify second response
-	contractData, ok := responses[1].Data.(map[string]interface{})
-	require.True(t, ok)
-	contracts := contractData["contracts"].([]interface{})
-	assert.Len(t, contracts, 2)
-}
-
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -475,11 +435,10 @@ func TestErrorHandling(t *testing.T) {
 				},
 			)
 
-			client, err := NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
+			client := newRecordedClient(recorder)
 
 			var result map[string]interface{}
-			err = client.Query(context.Background(), `{ user { id } }`, nil, &result)
+			err := client.Do(context.Background(), &Request{Query: `{ user { id } }`}, &result)
 			
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tt.expectedErr)
@@ -488,26 +447,20 @@ func TestErrorHandling(t *testing.T) {
 }
 
 func TestContextCancellation(t *testing.T) {
-	// Create a recorder that simulates a slow response
-	recorder := &mocks.RequestRecorder{}
-	recorder.Do = func(req *http.Request) (*http.Response, error) {
-		select {
-		case <-req.Context().Done():
-			return nil, req.Context().Err()
-		case <-req.Context().Done():
-			return nil, fmt.Errorf("request cancelled")
-		}
-	}
+	// Create a transport that waits for the request to be cancelled
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
 
-	client, err := NewClient("https://api.upwork.com/graphql", recorder)
-	require.NoError(t, err)
+	client := NewClient(&http.Client{Transport: transport}, "https://api.upwork.com/graphql")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
 	var result map[string]interface{}
-	err = client.Query(ctx, `{ user { id } }`, nil, &result)
-	
+	err := client.Do(ctx, &Request{Query: `{ user { id } }`}, &result)
+
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context canceled")
 }
@@ -520,8 +473,7 @@ func TestHeaderPropagation(t *testing.T) {
 		},
 	)
 
-	client, err := NewClient("https://api.upwork.com/graphql", recorder)
-	require.NoError(t, err)
+	client := newRecordedClient(recorder)
 
 	// Set various headers
 	client.SetHeader("Authorization", "Bearer test-token")
@@ -529,7 +481,7 @@ func TestHeaderPropagation(t *testing.T) {
 	client.SetHeader("X-Request-ID", "req-456")
 
 	var result map[string]interface{}
-	err = client.Query(context.Background(), `{ test }`, nil, &result)
+	err := client.Do(context.Background(), &Request{Query: `{ test }`}, &result)
 	require.NoError(t, err)
 
 	// Verify headers were sent
@@ -540,6 +492,19 @@ func TestHeaderPropagation(t *testing.T) {
 	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
 }
 
+// newRecordedClient returns a client sending its requests to recorder
+func newRecordedClient(recorder *mocks.RequestRecorder) *Client {
+	return NewClient(&http.Client{Transport: recorder}, "https://api.upwork.com/graphql")
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 // Helper function to read response body
 func readResponseBody(t *testing.T, resp *http.Response) string {
 	if resp.Body == nil {
@@ -0,0 +1,472 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// IntrospectionQuery is the standard introspection query, including
+// deprecated fields and enum values
+const IntrospectionQuery = `
+	query IntrospectionQuery {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			types { ...FullType }
+			directives {
+				name
+				description
+				locations
+				args { ...InputValue }
+			}
+		}
+	}
+
+	fragment FullType on __Type {
+		kind
+		name
+		description
+		fields(includeDeprecated: true) {
+			name
+			description
+			args { ...InputValue }
+			type { ...TypeRef }
+			isDeprecated
+			deprecationReason
+		}
+		inputFields { ...InputValue }
+		interfaces { ...TypeRef }
+		enumValues(includeDeprecated: true) {
+			name
+			description
+			isDeprecated
+			deprecationReason
+		}
+		possibleTypes { ...TypeRef }
+	}
+
+	fragment InputValue on __InputValue {
+		name
+		description
+		type { ...TypeRef }
+		defaultValue
+	}
+
+	fragment TypeRef on __Type {
+		kind
+		name
+		ofType {
+			kind
+			name
+			ofType {
+				kind
+				name
+				ofType {
+					kind
+					name
+					ofType {
+						kind
+						name
+						ofType {
+							kind
+							name
+							ofType {
+								kind
+								name
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+`
+
+// IntrospectionSchema represents the result of an introspection query
+type IntrospectionSchema struct {
+	QueryType        *IntrospectionTypeName   `json:"queryType"`
+	MutationType     *IntrospectionTypeName   `json:"mutationType"`
+	SubscriptionType *IntrospectionTypeName   `json:"subscriptionType"`
+	Types            []IntrospectionType      `json:"types"`
+	Directives       []IntrospectionDirective `json:"directives"`
+}
+
+// IntrospectionTypeName references a named type
+type IntrospectionTypeName struct {
+	Name string `json:"name"`
+}
+
+// IntrospectionType represents a full type definition
+type IntrospectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Fields        []IntrospectionField      `json:"fields"`
+	InputFields   []IntrospectionInputValue `json:"inputFields"`
+	Interfaces    []IntrospectionTypeRef    `json:"interfaces"`
+	EnumValues    []IntrospectionEnumValue  `json:"enumValues"`
+	PossibleTypes []IntrospectionTypeRef    `json:"possibleTypes"`
+}
+
+// IntrospectionField represents a field of an object or interface
+type IntrospectionField struct {
+	Name              string                    `json:"name"`
+	Description       string                    `json:"description"`
+	Args              []IntrospectionInputValue `json:"args"`
+	Type              IntrospectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason string                    `json:"deprecationReason"`
+}
+
+// IntrospectionInputValue represents an argument or input field
+type IntrospectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         IntrospectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+// IntrospectionEnumValue represents an enum value
+type IntrospectionEnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// IntrospectionTypeRef represents a possibly wrapped type reference
+type IntrospectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *IntrospectionTypeRef `json:"ofType"`
+}
+
+// IntrospectionDirective represents a directive definition
+type IntrospectionDirective struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Locations   []string                  `json:"locations"`
+	Args        []IntrospectionInputValue `json:"args"`
+}
+
+// Introspect fetches the schema from the endpoint
+func (c *Client) Introspect(ctx context.Context) (*IntrospectionSchema, error) {
+	var resp struct {
+		Schema IntrospectionSchema `json:"__schema"`
+	}
+
+	req := &Request{
+		Query:         IntrospectionQuery,
+		OperationName: "IntrospectionQuery",
+	}
+
+	if err := c.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Schema, nil
+}
+
+// builtinDirectives are defined by the GraphQL prelude and omitted from SDL
+var builtinDirectives = map[string]bool{
+	"include":     true,
+	"skip":        true,
+	"deprecated":  true,
+	"specifiedBy": true,
+}
+
+// builtinScalars are defined by the GraphQL prelude and omitted from SDL
+var builtinScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// SDL renders the introspected schema in the GraphQL schema definition
+// language. Types and directives are sorted by name for stable output.
+func (s *IntrospectionSchema) SDL() string {
+	var b strings.Builder
+
+	if s.QueryType != nil && !s.hasConventionalRoots() {
+		b.WriteString("schema {\n")
+		fmt.Fprintf(&b, "\tquery: %s\n", s.QueryType.Name)
+		if s.MutationType != nil {
+			fmt.Fprintf(&b, "\tmutation: %s\n", s.MutationType.Name)
+		}
+		if s.SubscriptionType != nil {
+			fmt.Fprintf(&b, "\tsubscription: %s\n", s.SubscriptionType.Name)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	directives := append([]IntrospectionDirective(nil), s.Directives...)
+	sort.Slice(directives, func(i, j int) bool { return directives[i].Name < directives[j].Name })
+	for _, d := range directives {
+		if builtinDirectives[d.Name] {
+			continue
+		}
+		writeDescription(&b, d.Description, "")
+		fmt.Fprintf(&b, "directive @%s%s on %s\n\n", d.Name, formatArgs(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	types := append([]IntrospectionType(nil), s.Types...)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") || (t.Kind == "SCALAR" && builtinScalars[t.Name]) {
+			continue
+		}
+		writeType(&b, t)
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// hasConventionalRoots reports whether the root types use the default names,
+// in which case no schema block is needed
+func (s *IntrospectionSchema) hasConventionalRoots() bool {
+	if s.QueryType.Name != "Query" {
+		return false
+	}
+	if s.MutationType != nil && s.MutationType.Name != "Mutation" {
+		return false
+	}
+	if s.SubscriptionType != nil && s.SubscriptionType.Name != "Subscription" {
+		return false
+	}
+	return true
+}
+
+func writeType(b *strings.Builder, t IntrospectionType) {
+	writeDescription(b, t.Description, "")
+
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(b, "scalar %s\n\n", t.Name)
+
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(b, "%s %s", keyword, t.Name)
+		if len(t.Interfaces) > 0 {
+			names := make([]string, 0, len(t.Interfaces))
+			for _, i := range t.Interfaces {
+				names = append(names, i.Name)
+			}
+			fmt.Fprintf(b, " implements %s", strings.Join(names, " & "))
+		}
+		b.WriteString(" {\n")
+		for _, f := range t.Fields {
+			writeDescription(b, f.Description, "\t")
+			fmt.Fprintf(b, "\t%s%s: %s%s\n", f.Name, formatArgs(f.Args), f.Type.String(), deprecation(f.IsDeprecated, f.DeprecationReason))
+		}
+		b.WriteString("}\n\n")
+
+	case "UNION":
+		names := make([]string, 0, len(t.PossibleTypes))
+		for _, p := range t.PossibleTypes {
+			names = append(names, p.Name)
+		}
+		fmt.Fprintf(b, "union %s = %s\n\n", t.Name, strings.Join(names, " | "))
+
+	case "ENUM":
+		fmt.Fprintf(b, "enum %s {\n", t.Name)
+		for _, v := range t.EnumValues {
+			writeDescription(b, v.Description, "\t")
+			fmt.Fprintf(b, "\t%s%s\n", v.Name, deprecation(v.IsDeprecated, v.DeprecationReason))
+		}
+		b.WriteString("}\n\n")
+
+	case "INPUT_OBJECT":
+		fmt.Fprintf(b, "input %s {\n", t.Name)
+		for _, f := range t.InputFields {
+			writeDescription(b, f.Description, "\t")
+			fmt.Fprintf(b, "\t%s\n", formatInputValue(f))
+		}
+		b.WriteString("}\n\n")
+	}
+}
+
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, strings.ReplaceAll(description, `"""`, `\"""`))
+}
+
+func formatArgs(args []IntrospectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(args))
+	for _, a := range args {
+		parts = append(parts, formatInputValue(a))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func formatInputValue(v IntrospectionInputValue) string {
+	s := v.Name + ": " + v.Type.String()
+	if v.DefaultValue != nil {
+		s += " = " + *v.DefaultValue
+	}
+	return s
+}
+
+func deprecation(deprecated bool, reason string) string {
+	if !deprecated {
+		return ""
+	}
+	if reason == "" {
+		return " @deprecated"
+	}
+	return fmt.Sprintf(" @deprecated(reason: %q)", reason)
+}
+
+// String renders the type reference in GraphQL notation, e.g. [ID!]!
+func (t IntrospectionTypeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType == nil {
+			return ""
+		}
+		return t.OfType.String() + "!"
+	case "LIST":
+		if t.OfType == nil {
+			return "[]"
+		}
+		return "[" + t.OfType.String() + "]"
+	default:
+		return t.Name
+	}
+}
+
+// LoadSchema parses and validates a schema in SDL form
+func LoadSchema(sdl string) (*ast.Schema, error) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+	return schema, nil
+}
+
+// ValidateQuery validates a query document against the schema. In addition
+// to the standard validation rules, selecting a deprecated field or using a
+// deprecated enum value is reported as an error so that renamed or retired
+// operations are caught before they fail at runtime.
+func ValidateQuery(schema *ast.Schema, query string) error {
+	doc, errs := gqlparser.LoadQuery(schema, query)
+	if len(errs) > 0 {
+		return toErrorList(errs)
+	}
+
+	var deprecated []Error
+	for _, op := range doc.Operations {
+		deprecated = append(deprecated, deprecatedUsages(op.SelectionSet)...)
+	}
+	for _, frag := range doc.Fragments {
+		deprecated = append(deprecated, deprecatedUsages(frag.SelectionSet)...)
+	}
+
+	if len(deprecated) > 0 {
+		return &ErrorList{Errors: deprecated}
+	}
+
+	return nil
+}
+
+// deprecatedUsages walks a selection set and reports deprecated fields and
+// enum literals
+func deprecatedUsages(set ast.SelectionSet) []Error {
+	var found []Error
+
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.Definition != nil {
+				if reason, ok := deprecationReason(sel.Definition.Directives); ok {
+					found = append(found, Error{
+						Message:   fmt.Sprintf("field %s.%s is deprecated: %s", sel.ObjectDefinition.Name, sel.Name, reason),
+						Locations: locations(sel.Position),
+					})
+				}
+			}
+			for _, arg := range sel.Arguments {
+				found = append(found, deprecatedEnumValues(arg.Value)...)
+			}
+			found = append(found, deprecatedUsages(sel.SelectionSet)...)
+
+		case *ast.InlineFragment:
+			found = append(found, deprecatedUsages(sel.SelectionSet)...)
+		}
+	}
+
+	return found
+}
+
+// deprecatedEnumValues reports deprecated enum literals within a value
+func deprecatedEnumValues(v *ast.Value) []Error {
+	if v == nil {
+		return nil
+	}
+
+	var found []Error
+	if v.Kind == ast.EnumValue && v.Definition != nil {
+		if ev := v.Definition.EnumValues.ForName(v.Raw); ev != nil {
+			if reason, ok := deprecationReason(ev.Directives); ok {
+				found = append(found, Error{
+					Message:   fmt.Sprintf("enum value %s.%s is deprecated: %s", v.Definition.Name, v.Raw, reason),
+					Locations: locations(v.Position),
+				})
+			}
+		}
+	}
+	for _, child := range v.Children {
+		found = append(found, deprecatedEnumValues(child.Value)...)
+	}
+
+	return found
+}
+
+func deprecationReason(directives ast.DirectiveList) (string, bool) {
+	d := directives.ForName("deprecated")
+	if d == nil {
+		return "", false
+	}
+
+	reason := "No longer supported"
+	if arg := d.Arguments.ForName("reason"); arg != nil && arg.Value != nil {
+		reason = arg.Value.Raw
+	}
+	return reason, true
+}
+
+func locations(pos *ast.Position) []Location {
+	if pos == nil {
+		return nil
+	}
+	return []Location{{Line: pos.Line, Column: pos.Column}}
+}
+
+func toErrorList(errs gqlerror.List) *ErrorList {
+	list := &ErrorList{Errors: make([]Error, 0, len(errs))}
+	for _, e := range errs {
+		gqlErr := Error{Message: e.Message}
+		for _, l := range e.Locations {
+			gqlErr.Locations = append(gqlErr.Locations, Location{Line: l.Line, Column: l.Column})
+		}
+		list.Errors = append(list.Errors, gqlErr)
+	}
+	return list
+}
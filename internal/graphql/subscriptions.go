@@ -0,0 +1,501 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// SubProtocol is the WebSocket subprotocol of the graphql-ws protocol
+const SubProtocol = "graphql-transport-ws"
+
+const (
+	// DefaultKeepAlive is the default interval between pings
+	DefaultKeepAlive = 30 * time.Second
+
+	// DefaultConnectTimeout bounds dialing and the connection handshake
+	DefaultConnectTimeout = 10 * time.Second
+
+	// DefaultMaxReconnectDelay caps the backoff between reconnect attempts
+	DefaultMaxReconnectDelay = time.Minute
+
+	// writeTimeout bounds writing a single message
+	writeTimeout = 10 * time.Second
+)
+
+// Message types of the graphql-ws protocol
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+)
+
+// ErrSubscriptionsClosed is returned by a SubscriptionClient after Close
+var ErrSubscriptionsClosed = errors.New("subscription client is closed")
+
+// SubscriptionConfig configures a SubscriptionClient
+type SubscriptionConfig struct {
+	// URL is the ws:// or wss:// endpoint
+	URL string
+
+	// Header, if set, returns the headers sent when connecting, such as
+	// Authorization. It is called for every connection attempt so that
+	// reconnects use a current token.
+	Header func(ctx context.Context) (http.Header, error)
+
+	// Dialer defaults to websocket.DefaultDialer
+	Dialer *websocket.Dialer
+
+	// KeepAlive is the interval between pings. The connection is considered
+	// lost if nothing is received for twice as long.
+	KeepAlive time.Duration
+
+	// ConnectTimeout bounds dialing and the connection handshake
+	ConnectTimeout time.Duration
+
+	// MaxReconnectDelay caps the exponential backoff between reconnects
+	MaxReconnectDelay time.Duration
+
+	// OnError, if set, is called with connection errors before reconnecting
+	// and with errors reported for a subscription
+	OnError func(error)
+}
+
+// SubscriptionClient runs GraphQL subscriptions over a single WebSocket
+// using the graphql-ws protocol. Lost connections are re-established with
+// exponential backoff and active subscriptions are resubscribed. A
+// SubscriptionClient is safe for concurrent use.
+type SubscriptionClient struct {
+	cfg SubscriptionConfig
+
+	// ctx is cancelled by Close
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// writeMu serializes writes, which the websocket package requires
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	running bool
+	subs    map[string]*subscription
+	nextID  uint64
+}
+
+// subscription is an active subscription
+type subscription struct {
+	id string
+	// payload is the encoded Request
+	payload json.RawMessage
+	// in hands results from the reader to the subscription's goroutine,
+	// which owns out
+	in  chan json.RawMessage
+	out chan json.RawMessage
+	// done is closed when the subscription ends
+	done chan struct{}
+}
+
+// wsMessage is a message of the graphql-ws protocol
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewSubscriptionClient creates a subscription client. Call Connect before
+// subscribing.
+func NewSubscriptionClient(cfg SubscriptionConfig) *SubscriptionClient {
+	if cfg.Dialer == nil {
+		cfg.Dialer = websocket.DefaultDialer
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = DefaultKeepAlive
+	}
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
+	if cfg.MaxReconnectDelay <= 0 {
+		cfg.MaxReconnectDelay = DefaultMaxReconnectDelay
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SubscriptionClient{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		subs:   make(map[string]*subscription),
+	}
+}
+
+// Connect opens the connection and waits for the server to acknowledge it.
+// Calling Connect on a connected client does nothing.
+func (c *SubscriptionClient) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ctx.Err() != nil {
+		return ErrSubscriptionsClosed
+	}
+	if c.running {
+		return nil
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	c.running = true
+	go c.run(conn)
+	return nil
+}
+
+// Subscribe starts a subscription and returns a channel of its results.
+// Each result is the raw payload of a graphql-ws "next" message, a GraphQL
+// response with data and errors. Errors reported for the subscription as a
+// whole are delivered as a final response with only errors. The channel is
+// closed when the server completes the subscription, ctx is done or the
+// client is closed.
+func (c *SubscriptionClient) Subscribe(ctx context.Context, query string, variables map[string]interface{}) (<-chan json.RawMessage, error) {
+	payload, err := json.Marshal(Request{Query: query, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal subscription: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.ctx.Err() != nil {
+		c.mu.Unlock()
+		return nil, ErrSubscriptionsClosed
+	}
+	if !c.running {
+		c.mu.Unlock()
+		return nil, errors.New("subscription client is not connected")
+	}
+
+	c.nextID++
+	sub := &subscription{
+		id:      strconv.FormatUint(c.nextID, 10),
+		payload: payload,
+		in:      make(chan json.RawMessage),
+		out:     make(chan json.RawMessage),
+		done:    make(chan struct{}),
+	}
+	c.subs[sub.id] = sub
+	conn := c.conn
+	c.mu.Unlock()
+
+	// If the connection is being replaced, the reconnect resubscribes
+	if conn != nil {
+		if err := c.subscribe(conn, sub); err != nil {
+			// The reader sees the broken connection and reconnects
+			conn.Close()
+		}
+	}
+
+	go c.pump(ctx, sub)
+	return sub.out, nil
+}
+
+// Close ends all subscriptions and closes the connection
+func (c *SubscriptionClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ctx.Err() != nil {
+		return nil
+	}
+	c.cancel()
+
+	for id, sub := range c.subs {
+		delete(c.subs, id)
+		close(sub.done)
+	}
+
+	if c.conn == nil {
+		return nil
+	}
+	c.writeMu.Lock()
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeTimeout))
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
+
+// pump forwards results to the subscriber until the subscription ends,
+// completing it on the server if ctx is done first
+func (c *SubscriptionClient) pump(ctx context.Context, sub *subscription) {
+	defer close(sub.out)
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.unsubscribe(sub)
+			return
+		case <-sub.done:
+			return
+		case msg := <-sub.in:
+			select {
+			case sub.out <- msg:
+			case <-ctx.Done():
+				c.unsubscribe(sub)
+				return
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// unsubscribe ends sub and tells the server to complete it
+func (c *SubscriptionClient) unsubscribe(sub *subscription) {
+	if !c.finish(sub.id) {
+		return
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		c.write(conn, wsMessage{ID: sub.id, Type: msgComplete})
+	}
+}
+
+// finish removes a subscription and closes its done channel, returning
+// false if it had already ended
+func (c *SubscriptionClient) finish(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, ok := c.subs[id]
+	if ok {
+		delete(c.subs, id)
+		close(sub.done)
+	}
+	return ok
+}
+
+// run reads from conn and, whenever the connection is lost, reconnects and
+// resubscribes until the client is closed
+func (c *SubscriptionClient) run(conn *websocket.Conn) {
+	for {
+		err := c.serve(conn)
+		if c.ctx.Err() != nil {
+			return
+		}
+		c.report(fmt.Errorf("subscription connection lost: %w", err))
+
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+
+		if conn = c.reconnect(); conn == nil {
+			return
+		}
+	}
+}
+
+// reconnect dials until it succeeds, backing off exponentially, and
+// resubscribes the active subscriptions. It returns nil if the client is
+// closed first.
+func (c *SubscriptionClient) reconnect() *websocket.Conn {
+	delay := min(time.Second, c.cfg.MaxReconnectDelay)
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		conn, err := c.dial(c.ctx)
+		if err == nil {
+			c.mu.Lock()
+			if c.ctx.Err() != nil {
+				c.mu.Unlock()
+				conn.Close()
+				return nil
+			}
+			c.conn = conn
+			subs := make([]*subscription, 0, len(c.subs))
+			for _, sub := range c.subs {
+				subs = append(subs, sub)
+			}
+			c.mu.Unlock()
+
+			for _, sub := range subs {
+				if err := c.subscribe(conn, sub); err != nil {
+					// The reader sees the broken connection and reconnects
+					conn.Close()
+					break
+				}
+			}
+			return conn
+		}
+		if c.ctx.Err() != nil {
+			return nil
+		}
+		c.report(fmt.Errorf("subscription reconnect failed: %w", err))
+
+		delay *= 2
+		if delay > c.cfg.MaxReconnectDelay {
+			delay = c.cfg.MaxReconnectDelay
+		}
+	}
+}
+
+// dial opens a connection and completes the connection handshake
+func (c *SubscriptionClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ConnectTimeout)
+	defer cancel()
+
+	header := http.Header{}
+	if c.cfg.Header != nil {
+		h, err := c.cfg.Header(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build connection headers: %w", err)
+		}
+		header = h.Clone()
+	}
+
+	dialer := *c.cfg.Dialer
+	dialer.Subprotocols = []string{SubProtocol}
+
+	conn, resp, err := dialer.DialContext(ctx, c.cfg.URL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect: HTTP %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	if err := c.write(conn, wsMessage{Type: msgConnectionInit}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	conn.SetReadDeadline(deadline)
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("connection handshake failed: %w", err)
+		}
+		switch msg.Type {
+		case msgConnectionAck:
+			return conn, nil
+		case msgPing:
+			if err := c.write(conn, wsMessage{Type: msgPong}); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		default:
+			conn.Close()
+			return nil, fmt.Errorf("connection handshake failed: unexpected %q message", msg.Type)
+		}
+	}
+}
+
+// serve reads messages from conn until it fails, pinging the server every
+// KeepAlive
+func (c *SubscriptionClient) serve(conn *websocket.Conn) error {
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.keepAlive(conn, stop)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * c.cfg.KeepAlive))
+
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+
+		switch msg.Type {
+		case msgPing:
+			if err := c.write(conn, wsMessage{Type: msgPong}); err != nil {
+				return err
+			}
+		case msgNext:
+			c.deliver(msg.ID, msg.Payload)
+		case msgError:
+			c.report(fmt.Errorf("subscription %s failed: %s", msg.ID, msg.Payload))
+			c.deliver(msg.ID, json.RawMessage(`{"errors":`+string(msg.Payload)+`}`))
+			c.finish(msg.ID)
+		case msgComplete:
+			c.finish(msg.ID)
+		}
+	}
+}
+
+// deliver hands a result to a subscription, waiting for it to be taken
+func (c *SubscriptionClient) deliver(id string, payload json.RawMessage) {
+	c.mu.Lock()
+	sub, ok := c.subs[id]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case sub.in <- payload:
+	case <-sub.done:
+	}
+}
+
+// keepAlive pings the server every KeepAlive until stop is closed
+func (c *SubscriptionClient) keepAlive(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.cfg.KeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.write(conn, wsMessage{Type: msgPing}); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// subscribe sends the subscribe message for sub
+func (c *SubscriptionClient) subscribe(conn *websocket.Conn, sub *subscription) error {
+	return c.write(conn, wsMessage{ID: sub.id, Type: msgSubscribe, Payload: sub.payload})
+}
+
+// write sends a message on conn
+func (c *SubscriptionClient) write(conn *websocket.Conn, msg wsMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to write %s message: %w", msg.Type, err)
+	}
+	return nil
+}
+
+// report passes err to OnError, if set
+func (c *SubscriptionClient) report(err error) {
+	if c.cfg.OnError != nil {
+		c.cfg.OnError(err)
+	}
+}
@@ -0,0 +1,179 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wsServer is a graphql-ws server. Each connection is handed to handle
+// after the handshake.
+func wsServer(t *testing.T, handle func(n int, conn *websocket.Conn)) *httptest.Server {
+	upgrader := websocket.Upgrader{Subprotocols: []string{SubProtocol}}
+	var conns int32
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		assert.Equal(t, SubProtocol, conn.Subprotocol())
+
+		var init wsMessage
+		assert.NoError(t, conn.ReadJSON(&init))
+		assert.Equal(t, msgConnectionInit, init.Type)
+		assert.NoError(t, conn.WriteJSON(wsMessage{Type: msgConnectionAck}))
+
+		handle(int(atomic.AddInt32(&conns, 1)), conn)
+	}))
+}
+
+// readType reads messages until one of type typ, returning a zero message
+// once the connection is closed
+func readType(conn *websocket.Conn, typ string) wsMessage {
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return wsMessage{}
+		}
+		if msg.Type == typ {
+			return msg
+		}
+	}
+}
+
+func newTestSubscriptionClient(server *httptest.Server) *SubscriptionClient {
+	return NewSubscriptionClient(SubscriptionConfig{
+		URL: "ws" + strings.TrimPrefix(server.URL, "http"),
+		Header: func(ctx context.Context) (http.Header, error) {
+			return http.Header{"Authorization": {"Bearer token"}}, nil
+		},
+		MaxReconnectDelay: 10 * time.Millisecond,
+	})
+}
+
+func TestSubscriptionClientSubscribe(t *testing.T) {
+	server := wsServer(t, func(n int, conn *websocket.Conn) {
+		sub := readType(conn, msgSubscribe)
+		var req Request
+		assert.NoError(t, json.Unmarshal(sub.Payload, &req))
+		assert.Equal(t, "subscription { storyCreated { id } }", req.Query)
+		assert.Equal(t, "room-1", req.Variables["roomId"])
+
+		// Pings from the server are answered with pongs
+		assert.NoError(t, conn.WriteJSON(wsMessage{Type: msgPing}))
+		assert.Equal(t, msgPong, readType(conn, msgPong).Type)
+
+		for _, id := range []string{"1", "2"} {
+			payload := json.RawMessage(`{"data":{"storyCreated":{"id":"` + id + `"}}}`)
+			assert.NoError(t, conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgNext, Payload: payload}))
+		}
+		assert.NoError(t, conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgComplete}))
+		readType(conn, "close")
+	})
+	defer server.Close()
+
+	client := newTestSubscriptionClient(server)
+	defer client.Close()
+
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+
+	results, err := client.Subscribe(ctx, "subscription { storyCreated { id } }", map[string]interface{}{"roomId": "room-1"})
+	require.NoError(t, err)
+
+	var got []string
+	for msg := range results {
+		got = append(got, string(msg))
+	}
+	assert.Equal(t, []string{
+		`{"data":{"storyCreated":{"id":"1"}}}`,
+		`{"data":{"storyCreated":{"id":"2"}}}`,
+	}, got)
+}
+
+func TestSubscriptionClientResubscribesOnReconnect(t *testing.T) {
+	completed := make(chan string, 1)
+	server := wsServer(t, func(n int, conn *websocket.Conn) {
+		sub := readType(conn, msgSubscribe)
+		if n == 1 {
+			// Drop the first connection once subscribed
+			return
+		}
+
+		assert.NoError(t, conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgNext, Payload: json.RawMessage(`{"data":{"n":2}}`)}))
+		completed <- readType(conn, msgComplete).ID
+	})
+	defer server.Close()
+
+	var lost int32
+	client := newTestSubscriptionClient(server)
+	client.cfg.OnError = func(error) { atomic.AddInt32(&lost, 1) }
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, client.Connect(ctx))
+
+	results, err := client.Subscribe(ctx, "subscription { n }", nil)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-results:
+		assert.JSONEq(t, `{"data":{"n":2}}`, string(msg))
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result after reconnecting")
+	}
+	assert.NotZero(t, atomic.LoadInt32(&lost))
+
+	// Cancelling completes the subscription on the server
+	cancel()
+	select {
+	case id := <-completed:
+		assert.Equal(t, "1", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not completed")
+	}
+	_, open := <-results
+	assert.False(t, open)
+}
+
+func TestSubscriptionClientErrors(t *testing.T) {
+	server := wsServer(t, func(n int, conn *websocket.Conn) {
+		sub := readType(conn, msgSubscribe)
+		assert.NoError(t, conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgError, Payload: json.RawMessage(`[{"message":"forbidden"}]`)}))
+		readType(conn, "close")
+	})
+	defer server.Close()
+
+	client := newTestSubscriptionClient(server)
+	ctx := context.Background()
+
+	_, err := client.Subscribe(ctx, "subscription { n }", nil)
+	assert.Error(t, err, "subscribing before connecting")
+
+	require.NoError(t, client.Connect(ctx))
+	results, err := client.Subscribe(ctx, "subscription { n }", nil)
+	require.NoError(t, err)
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(<-results, &resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "forbidden", resp.Errors[0].Message)
+	_, open := <-results
+	assert.False(t, open)
+
+	require.NoError(t, client.Close())
+	_, err = client.Subscribe(ctx, "subscription { n }", nil)
+	assert.ErrorIs(t, err, ErrSubscriptionsClosed)
+}
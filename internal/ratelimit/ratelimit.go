@@ -3,73 +3,149 @@ package ratelimit
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"sync"
 	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
 )
 
-// Limiter implements a token bucket rate limiter
+// Limiter implements a fixed window rate limiter. Waiters reserve tokens
+// in the order they arrive and each sleeps until its own token is due, so
+// they are served FIFO and are not all woken at once when a window opens.
 type Limiter struct {
-	tokens    int
 	maxTokens int
 	interval  time.Duration
-	lastReset time.Time
-	mu        sync.Mutex
+
+	mu sync.Mutex
+	// windowStart is the start of the current window
+	windowStart time.Time
+	// used is the number of tokens reserved from windowStart on. Beyond
+	// maxTokens, tokens are reserved from later windows.
+	used int
 }
 
-// New creates a new rate limiter
+// New creates a new rate limiter allowing maxRequests tokens per interval.
+// A limiter with maxRequests or interval of zero or less is unlimited.
 func New(maxRequests int, interval time.Duration) *Limiter {
 	return &Limiter{
-		tokens:    maxRequests,
-		maxTokens: maxRequests,
-		interval:  interval,
-		lastReset: time.Now(),
+		maxTokens:   maxRequests,
+		interval:    interval,
+		windowStart: time.Now(),
 	}
 }
 
-// Wait blocks until a token is available
+// Wait blocks until a token is available. If ctx has a deadline before the
+// token would be available, Wait returns errors.ErrWouldExceedDeadline
+// straight away instead of waiting.
 func (l *Limiter) Wait(ctx context.Context) error {
-	for {
-		l.mu.Lock()
-		
-		// Reset tokens if interval has passed
-		if time.Since(l.lastReset) >= l.interval {
-			l.tokens = l.maxTokens
-			l.lastReset = time.Now()
-		}
-		
-		// If tokens available, consume one and return
-		if l.tokens > 0 {
-			l.tokens--
-			l.mu.Unlock()
-			return nil
-		}
-		
-		// Calculate wait time until next reset
-		waitTime := l.interval - time.Since(l.lastReset)
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available, for a request that costs
+// more than one. Costs above the tokens of one window are reserved across
+// several windows. n must be positive.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("ratelimit: cannot wait for %d tokens", n)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if l.unlimited() {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.advance(now)
+	// The request may go once its last token is due
+	due := l.windowStart.Add(time.Duration((l.used+n-1)/l.maxTokens) * l.interval)
+	wait := due.Sub(now)
+	if deadline, ok := ctx.Deadline(); ok && wait > 0 && due.After(deadline) {
 		l.mu.Unlock()
-		
-		// Wait with context
-		timer := time.NewTimer(waitTime)
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return ctx.Err()
-		case <-timer.C:
-			// Continue loop to try again
-		}
+		return fmt.Errorf("%w: next token in %v", errors.ErrWouldExceedDeadline, wait)
 	}
+	l.used += n
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// This frees the last tokens reserved rather than these, so tokens
+		// promised to later waiters stay valid; at worst some of an earlier
+		// window go unused
+		l.Debit(-n)
+		return ctx.Err()
+	}
+}
+
+// Debit takes n tokens without waiting, e.g. once a response reports that
+// a request cost more than was reserved for it. Later waiters wait longer
+// to make up for it. A negative n gives tokens back.
+func (l *Limiter) Debit(n int) {
+	if l.unlimited() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.used = max(l.used+n, 0)
 }
 
-// Available returns the number of available tokens
+// Available returns the number of tokens that can be taken without
+// waiting, math.MaxInt if the limiter is unlimited
 func (l *Limiter) Available() int {
+	if l.unlimited() {
+		return math.MaxInt
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	return max(l.maxTokens-l.used, 0)
+}
+
+// Quota returns the tokens per window, the tokens that can be taken
+// without waiting and when the current window ends. An unlimited limiter
+// reports a limit of zero.
+func (l *Limiter) Quota() (limit, remaining int, reset time.Time) {
+	if l.unlimited() {
+		return 0, 0, time.Time{}
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	
-	// Reset tokens if interval has passed
-	if time.Since(l.lastReset) >= l.interval {
-		l.tokens = l.maxTokens
-		l.lastReset = time.Now()
+
+	l.advance(time.Now())
+	return l.maxTokens, max(l.maxTokens-l.used, 0), l.windowStart.Add(l.interval)
+}
+
+// unlimited returns true if the limiter never makes callers wait
+func (l *Limiter) unlimited() bool {
+	return l.maxTokens <= 0 || l.interval <= 0
+}
+
+// advance moves the window forward to the one containing now, releasing
+// the tokens of the windows that have passed. l.mu must be held.
+func (l *Limiter) advance(now time.Time) {
+	elapsed := now.Sub(l.windowStart)
+	if elapsed < l.interval {
+		return
 	}
-	
-	return l.tokens
-}
\ No newline at end of file
+
+	windows := int(elapsed / l.interval)
+	l.windowStart = l.windowStart.Add(time.Duration(windows) * l.interval)
+	l.used = max(l.used-windows*l.maxTokens, 0)
+}
@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestWaitWouldExceedDeadline(t *testing.T) {
+	l := New(1, time.Hour)
+	require.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := l.Wait(ctx)
+	assert.ErrorIs(t, err, errors.ErrWouldExceedDeadline)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The failed wait did not take a token from the next window
+	assert.Equal(t, 1, l.used)
+}
+
+func TestWaitFIFO(t *testing.T) {
+	// All waiters arrive within the first window, so each reservation
+	// shows in l.used
+	const interval = 100 * time.Millisecond
+	l := New(2, interval)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, l.Wait(ctx))
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		}(i)
+
+		// Arrive in order: start the next waiter once this one is queued
+		require.Eventually(t, func() bool {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			return l.used == i+1
+		}, interval/2, 100*time.Microsecond, "waiter %d not queued", i)
+	}
+	wg.Wait()
+
+	// Two waiters are served per window, in arrival order across windows
+	require.Len(t, order, 6)
+	windowOf := func(i int) int { return i / 2 }
+	for pos, i := range order {
+		assert.Equal(t, pos/2, windowOf(i), "order %v", order)
+	}
+}
+
+func TestWaitCancelReleasesToken(t *testing.T) {
+	l := New(1, time.Hour)
+	require.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Wait(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.used == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, 1, l.used)
+}
+
+func TestAvailable(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	l := New(3, interval)
+	assert.Equal(t, 3, l.Available())
+
+	require.NoError(t, l.Wait(context.Background()))
+	require.NoError(t, l.Wait(context.Background()))
+	assert.Equal(t, 1, l.Available())
+
+	time.Sleep(interval)
+	assert.Equal(t, 3, l.Available())
+}
+
+func TestWaitNAndDebit(t *testing.T) {
+	l := New(10, time.Hour)
+	ctx := context.Background()
+
+	require.NoError(t, l.WaitN(ctx, 6))
+	assert.Equal(t, 4, l.Available())
+
+	// A cost the window cannot cover waits for the next one
+	deadline, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	assert.ErrorIs(t, l.WaitN(deadline, 5), errors.ErrWouldExceedDeadline)
+
+	l.Debit(3)
+	assert.Equal(t, 1, l.Available())
+	l.Debit(-5)
+	assert.Equal(t, 6, l.Available())
+	l.Debit(-20)
+	assert.Equal(t, 10, l.Available())
+
+	// Waiting for no tokens is a mistake in the caller
+	assert.Error(t, l.WaitN(ctx, 0))
+	assert.Error(t, l.WaitN(ctx, -1))
+	assert.Equal(t, 10, l.Available())
+}
+
+func TestUnlimited(t *testing.T) {
+	for _, l := range []*Limiter{New(0, time.Minute), New(-1, time.Minute), New(10, 0)} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.WaitN(ctx, 5))
+		}
+		cancel()
+
+		l.Debit(10)
+		assert.Equal(t, math.MaxInt, l.Available())
+		limit, remaining, reset := l.Quota()
+		assert.Zero(t, limit)
+		assert.Zero(t, remaining)
+		assert.True(t, reset.IsZero())
+		assert.Error(t, l.WaitN(context.Background(), 0))
+	}
+}
+
+func TestQuota(t *testing.T) {
+	l := New(5, time.Hour)
+	require.NoError(t, l.WaitN(context.Background(), 2))
+
+	limit, remaining, reset := l.Quota()
+	assert.Equal(t, 5, limit)
+	assert.Equal(t, 3, remaining)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), reset, time.Second)
+}
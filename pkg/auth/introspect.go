@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// APIURL is the GraphQL endpoint queried by Introspect
+const APIURL = "https://api.upwork.com/graphql"
+
+// TokenInfo describes an access token and the identity it belongs to
+type TokenInfo struct {
+	// Scopes are the scopes granted to the token
+	Scopes []Scope `json:"scopes"`
+
+	// Expiry is when the token expires, or zero if unknown
+	Expiry time.Time `json:"expiry"`
+
+	// User is the user the token acts as
+	User models.User `json:"user"`
+
+	// Organizations are the organizations the user can act in
+	Organizations []models.Organization `json:"organizations"`
+}
+
+// HasScopes returns true if every scope in required was granted
+func (i *TokenInfo) HasScopes(required ...Scope) bool {
+	return len(MissingScopes(i.Scopes, required...)) == 0
+}
+
+// introspectQuery fetches the token details in a single request
+const introspectQuery = `
+	query Introspect {
+		oauth2TokenInfo {
+			scopes
+			expiresAt
+		}
+		user {
+			id
+			nid
+			name
+			email
+		}
+		companySelector {
+			items {
+				title
+				organizationId
+			}
+		}
+	}
+`
+
+// Introspector looks up token details from the API
+type Introspector struct {
+	// APIURL is the GraphQL endpoint. Defaults to APIURL.
+	APIURL string
+
+	// HTTPClient sends the request. Defaults to the client in the context
+	// under oauth2.HTTPClient, then http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Introspect returns the scopes, expiry, user and organizations of token
+// using the production API
+func Introspect(ctx context.Context, token *oauth2.Token) (*TokenInfo, error) {
+	return (&Introspector{}).Introspect(ctx, token)
+}
+
+// Introspect returns the scopes, expiry, user and organizations of token
+func (in *Introspector) Introspect(ctx context.Context, token *oauth2.Token) (*TokenInfo, error) {
+	if err := ValidateToken(token); err != nil {
+		return nil, err
+	}
+
+	apiURL := in.APIURL
+	if apiURL == "" {
+		apiURL = APIURL
+	}
+
+	httpClient := in.HTTPClient
+	if httpClient == nil {
+		httpClient, _ = ctx.Value(oauth2.HTTPClient).(*http.Client)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	body, err := json.Marshal(map[string]string{"query": introspectQuery})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	token.SetAuthHeader(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token introspection failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			OAuth2TokenInfo *struct {
+				Scopes    []Scope `json:"scopes"`
+				ExpiresAt string  `json:"expiresAt"`
+			} `json:"oauth2TokenInfo"`
+			User            models.User `json:"user"`
+			CompanySelector struct {
+				Items []struct {
+					Title          string `json:"title"`
+					OrganizationID string `json:"organizationId"`
+				} `json:"items"`
+			} `json:"companySelector"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse introspection response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("token introspection failed: %s", result.Errors[0].Message)
+	}
+
+	info := &TokenInfo{
+		User:   result.Data.User,
+		Expiry: token.Expiry,
+	}
+
+	// The API is authoritative; fall back to the token response
+	if tokenInfo := result.Data.OAuth2TokenInfo; tokenInfo != nil {
+		info.Scopes = tokenInfo.Scopes
+		if expiry, err := time.Parse(time.RFC3339, tokenInfo.ExpiresAt); err == nil {
+			info.Expiry = expiry
+		}
+	} else if scopes, ok := TokenScopes(token); ok {
+		info.Scopes = scopes
+	}
+
+	for _, item := range result.Data.CompanySelector.Items {
+		info.Organizations = append(info.Organizations, models.Organization{
+			ID:   models.ID(item.OrganizationID),
+			Name: item.Title,
+		})
+	}
+
+	return info, nil
+}
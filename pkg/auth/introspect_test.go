@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestIntrospect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"data":{
+			"oauth2TokenInfo":{"scopes":["contracts:read","jobs:read"],"expiresAt":"2030-01-02T03:04:05Z"},
+			"user":{"id":"user-1","name":"Test User","email":"test@example.com"},
+			"companySelector":{"items":[{"title":"Acme","organizationId":"org-1"}]}
+		}}`)
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, server.Client())
+	info, err := (&Introspector{APIURL: server.URL}).Introspect(ctx, &oauth2.Token{AccessToken: "token-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Scope{ScopeContractsRead, ScopeJobsRead}, info.Scopes)
+	assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), info.Expiry)
+	assert.Equal(t, "Test User", info.User.Name)
+	require.Len(t, info.Organizations, 1)
+	assert.Equal(t, "Acme", info.Organizations[0].Name)
+	assert.False(t, info.HasScopes(ScopeJobsWrite))
+}
+
+func TestIntrospectError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := (&Introspector{APIURL: server.URL, HTTPClient: server.Client()}).Introspect(context.Background(), &oauth2.Token{AccessToken: "bad"})
+	assert.EqualError(t, err, "token introspection failed with status: 401")
+
+	_, err = Introspect(context.Background(), nil)
+	assert.Error(t, err)
+}
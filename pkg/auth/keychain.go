@@ -0,0 +1,110 @@
+//go:build keychain
+
+package auth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// KeychainStore is a TokenStore that keeps the token in the OS keychain:
+// the login keychain on macOS, via the security tool, and the Secret
+// Service on Linux, via secret-tool from libsecret
+type KeychainStore struct {
+	service string
+	account string
+}
+
+// NewKeychainStore returns a store that keeps the token in the OS keychain
+// under service and account. It is only available when built with the
+// keychain tag.
+func NewKeychainStore(service, account string) (*KeychainStore, error) {
+	if service == "" || account == "" {
+		return nil, fmt.Errorf("keychain service and account are required")
+	}
+
+	tool := "secret-tool"
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+	default:
+		return nil, ErrKeychainUnavailable
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, fmt.Errorf("%w: %s not found", ErrKeychainUnavailable, tool)
+	}
+
+	return &KeychainStore{service: service, account: account}, nil
+}
+
+// Load returns the stored token, or nil if the keychain has none
+func (s *KeychainStore) Load() (*oauth2.Token, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", s.service, "-a", s.account, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", s.service, "account", s.account)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(out)) == 0 {
+		// Both tools exit non-zero when no item matches
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(bytes.TrimSpace(out), &token); err != nil {
+		return nil, fmt.Errorf("parsing keychain token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Save stores token in the keychain, replacing any previous token. The
+// token is passed on stdin so it never appears in a process listing.
+func (s *KeychainStore) Save(token *oauth2.Token) error {
+	if token == nil {
+		return fmt.Errorf("token is nil")
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// Interactive mode reads the command from stdin; -X takes the
+		// password hex encoded
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+			strconv.Quote(s.service), strconv.Quote(s.account), hex.EncodeToString(data)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", "Upwork token ("+s.account+")",
+			"service", s.service, "account", s.account)
+		cmd.Stdin = bytes.NewReader(data)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("writing keychain: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build !keychain
+
+package auth
+
+import "golang.org/x/oauth2"
+
+// KeychainStore is a TokenStore backed by the OS keychain. Build with the
+// keychain tag to enable it.
+type KeychainStore struct{}
+
+// NewKeychainStore returns ErrKeychainUnavailable unless built with the
+// keychain tag
+func NewKeychainStore(service, account string) (*KeychainStore, error) {
+	return nil, ErrKeychainUnavailable
+}
+
+// Load is not supported without the keychain tag
+func (s *KeychainStore) Load() (*oauth2.Token, error) {
+	return nil, ErrKeychainUnavailable
+}
+
+// Save is not supported without the keychain tag
+func (s *KeychainStore) Save(token *oauth2.Token) error {
+	return ErrKeychainUnavailable
+}
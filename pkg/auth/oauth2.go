@@ -106,6 +106,9 @@ type Config struct {
 	RedirectURL  string
 	Scopes       []string
 	GrantType    GrantType
+
+	// Optional: token endpoint (defaults to TokenURL)
+	TokenURL string
 }
 
 // Client handles OAuth2 authentication
@@ -117,6 +120,10 @@ type Client struct {
 
 // NewClient creates a new OAuth2 client
 func NewClient(config *Config) *Client {
+	if config.TokenURL == "" {
+		config.TokenURL = TokenURL
+	}
+
 	oauth2Config := &oauth2.Config{
 		ClientID:     config.ClientID,
 		ClientSecret: config.ClientSecret,
@@ -124,7 +131,7 @@ func NewClient(config *Config) *Client {
 		Scopes:       config.Scopes,
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  AuthorizationURL,
-			TokenURL: TokenURL,
+			TokenURL: config.TokenURL,
 		},
 	}
 	
@@ -163,14 +170,14 @@ func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*oauth2
 	params.Set("client_secret", c.config.ClientSecret)
 	params.Set("refresh_token", refreshToken)
 	
-	req, err := http.NewRequestWithContext(ctx, "POST", TokenURL, strings.NewReader(params.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.TokenURL, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.client(ctx).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -203,14 +210,14 @@ func (c *Client) ClientCredentials(ctx context.Context) (*oauth2.Token, error) {
 		params.Set("scope", strings.Join(c.config.Scopes, " "))
 	}
 	
-	req, err := http.NewRequestWithContext(ctx, "POST", TokenURL, strings.NewReader(params.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.TokenURL, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.client(ctx).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +231,7 @@ func (c *Client) ClientCredentials(ctx context.Context) (*oauth2.Token, error) {
 		AccessToken string `json:"access_token"`
 		TokenType   string `json:"token_type"`
 		ExpiresIn   int    `json:"expires_in"`
+		Scope       string `json:"scope"`
 	}
 	
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
@@ -235,10 +243,40 @@ func (c *Client) ClientCredentials(ctx context.Context) (*oauth2.Token, error) {
 		TokenType:   tokenResponse.TokenType,
 		Expiry:      time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
 	}
+	if tokenResponse.Scope != "" {
+		token = token.WithExtra(map[string]interface{}{"scope": tokenResponse.Scope})
+	}
 	
 	return token, nil
 }
 
+// ClientCredentialsTokenSource returns an oauth2.TokenSource that obtains a
+// new token with the client credentials grant on every call. Wrap it with
+// oauth2.ReuseTokenSource to cache tokens until they expire.
+func (c *Client) ClientCredentialsTokenSource(ctx context.Context) oauth2.TokenSource {
+	return clientCredentialsSource{ctx: ctx, client: c}
+}
+
+// clientCredentialsSource adapts ClientCredentials to oauth2.TokenSource
+type clientCredentialsSource struct {
+	ctx    context.Context
+	client *Client
+}
+
+// Token obtains a new token
+func (s clientCredentialsSource) Token() (*oauth2.Token, error) {
+	return s.client.ClientCredentials(s.ctx)
+}
+
+// client returns the HTTP client for token requests, preferring one set in
+// ctx under oauth2.HTTPClient as the oauth2 package does
+func (c *Client) client(ctx context.Context) *http.Client {
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && hc != nil {
+		return hc
+	}
+	return c.httpClient
+}
+
 // TokenSource returns an oauth2.TokenSource for the given token
 func (c *Client) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
 	return c.oauth2Config.TokenSource(ctx, token)
@@ -6,114 +6,79 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/rizome-dev/go-upwork/tests/mocks"
 	"github.com/rizome-dev/go-upwork/tests/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 )
 
-func TestNewOAuth2Config(t *testing.T) {
+func TestNewClient(t *testing.T) {
 	tests := []struct {
 		name     string
-		opts     OAuth2Options
-		wantErr  bool
-		validate func(t *testing.T, cfg *OAuth2Config)
+		config   Config
+		validate func(t *testing.T, c *Client)
 	}{
 		{
 			name: "valid configuration",
-			opts: OAuth2Options{
+			config: Config{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
 				RedirectURL:  "http://localhost:8080/callback",
 				Scopes:       []string{"read", "write"},
 			},
-			wantErr: false,
-			validate: func(t *testing.T, cfg *OAuth2Config) {
-				assert.Equal(t, "test-client-id", cfg.config.ClientID)
-				assert.Equal(t, "test-client-secret", cfg.config.ClientSecret)
-				assert.Equal(t, "http://localhost:8080/callback", cfg.config.RedirectURL)
-				assert.Equal(t, []string{"read", "write"}, cfg.config.Scopes)
-			},
-		},
-		{
-			name: "missing client ID",
-			opts: OAuth2Options{
-				ClientSecret: "test-client-secret",
-				RedirectURL:  "http://localhost:8080/callback",
+			validate: func(t *testing.T, c *Client) {
+				assert.Equal(t, "test-client-id", c.oauth2Config.ClientID)
+				assert.Equal(t, "test-client-secret", c.oauth2Config.ClientSecret)
+				assert.Equal(t, "http://localhost:8080/callback", c.oauth2Config.RedirectURL)
+				assert.Equal(t, []string{"read", "write"}, c.oauth2Config.Scopes)
 			},
-			wantErr: true,
-		},
-		{
-			name: "missing client secret",
-			opts: OAuth2Options{
-				ClientID:    "test-client-id",
-				RedirectURL: "http://localhost:8080/callback",
-			},
-			wantErr: true,
 		},
 		{
-			name: "default scopes",
-			opts: OAuth2Options{
+			name: "default endpoints",
+			config: Config{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
-				RedirectURL:  "http://localhost:8080/callback",
 			},
-			wantErr: false,
-			validate: func(t *testing.T, cfg *OAuth2Config) {
-				expectedScopes := []string{
-					"openid", "email", "profile", "offline_access",
-					"contracts:read", "contracts:write",
-					"messages:read", "messages:write",
-					"jobs:read", "jobs:write",
-					"proposals:read", "proposals:write",
-					"organizations:read",
-				}
-				assert.Equal(t, expectedScopes, cfg.config.Scopes)
+			validate: func(t *testing.T, c *Client) {
+				assert.Equal(t, AuthorizationURL, c.oauth2Config.Endpoint.AuthURL)
+				assert.Equal(t, TokenURL, c.oauth2Config.Endpoint.TokenURL)
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg, err := NewOAuth2Config(tt.opts)
-			if tt.wantErr {
-				assert.Error(t, err)
-				return
-			}
-			assert.NoError(t, err)
+			c := NewClient(&tt.config)
+			require.NotNil(t, c)
 			if tt.validate != nil {
-				tt.validate(t, cfg)
+				tt.validate(t, c)
 			}
 		})
 	}
 }
 
 func TestGetAuthorizationURL(t *testing.T) {
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		RedirectURL:  "http://localhost:8080/callback",
 		Scopes:       []string{"read", "write"},
 	})
-	require.NoError(t, err)
 
-	authURL := cfg.GetAuthorizationURL("test-state")
-	
+	authURL := c.GetAuthorizationURL("test-state")
+
 	parsedURL, err := url.Parse(authURL)
 	require.NoError(t, err)
 
 	assert.Equal(t, "https", parsedURL.Scheme)
 	assert.Equal(t, "www.upwork.com", parsedURL.Host)
 	assert.Equal(t, "/ab/account-security/oauth2/authorize", parsedURL.Path)
-	
+
 	query := parsedURL.Query()
 	assert.Equal(t, "test-client-id", query.Get("client_id"))
-	assert.Equal(t, "code", query.Get("response_type"))
 	assert.Equal(t, "http://localhost:8080/callback", query.Get("redirect_uri"))
 	assert.Equal(t, "read write", query.Get("scope"))
 	assert.Equal(t, "test-state", query.Get("state"))
@@ -123,38 +88,36 @@ func TestExchangeAuthorizationCode(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/api/v3/oauth2/token", r.URL.Path)
 		assert.Equal(t, "POST", r.Method)
-		
+
 		err := r.ParseForm()
 		require.NoError(t, err)
-		
+
 		assert.Equal(t, "authorization_code", r.Form.Get("grant_type"))
 		assert.Equal(t, "test-code", r.Form.Get("code"))
 		assert.Equal(t, "http://localhost:8080/callback", r.Form.Get("redirect_uri"))
-		
+
 		// Check basic auth
 		username, password, ok := r.BasicAuth()
 		assert.True(t, ok)
 		assert.Equal(t, "test-client-id", username)
 		assert.Equal(t, "test-client-secret", password)
-		
+
 		response := testutils.MockOAuth2Token("test-access-token", 3600)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
 	}))
 	defer server.Close()
 
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		RedirectURL:  "http://localhost:8080/callback",
-		AuthURL:      server.URL + "/ab/account-security/oauth2/authorize",
 		TokenURL:     server.URL + "/api/v3/oauth2/token",
 	})
-	require.NoError(t, err)
 
-	token, err := cfg.ExchangeAuthorizationCode(context.Background(), "test-code")
+	token, err := c.ExchangeCode(context.Background(), "test-code")
 	require.NoError(t, err)
-	
+
 	assert.Equal(t, "test-access-token", token.AccessToken)
 	assert.Equal(t, "Bearer", token.TokenType)
 	assert.Equal(t, "mock_refresh_token", token.RefreshToken)
@@ -165,13 +128,13 @@ func TestRefreshToken(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/api/v3/oauth2/token", r.URL.Path)
 		assert.Equal(t, "POST", r.Method)
-		
+
 		err := r.ParseForm()
 		require.NoError(t, err)
-		
+
 		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
 		assert.Equal(t, "old-refresh-token", r.Form.Get("refresh_token"))
-		
+
 		response := testutils.MockOAuth2Token("new-access-token", 3600)
 		response["refresh_token"] = "new-refresh-token"
 		w.Header().Set("Content-Type", "application/json")
@@ -179,24 +142,16 @@ func TestRefreshToken(t *testing.T) {
 	}))
 	defer server.Close()
 
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		RedirectURL:  "http://localhost:8080/callback",
 		TokenURL:     server.URL + "/api/v3/oauth2/token",
 	})
-	require.NoError(t, err)
-
-	oldToken := &oauth2.Token{
-		AccessToken:  "old-access-token",
-		RefreshToken: "old-refresh-token",
-		TokenType:    "Bearer",
-		Expiry:       time.Now().Add(-1 * time.Hour), // Expired
-	}
 
-	newToken, err := cfg.RefreshToken(context.Background(), oldToken)
+	newToken, err := c.RefreshToken(context.Background(), "old-refresh-token")
 	require.NoError(t, err)
-	
+
 	assert.Equal(t, "new-access-token", newToken.AccessToken)
 	assert.Equal(t, "new-refresh-token", newToken.RefreshToken)
 	assert.True(t, newToken.Valid())
@@ -206,38 +161,39 @@ func TestClientCredentialsGrant(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/api/v3/oauth2/token", r.URL.Path)
 		assert.Equal(t, "POST", r.Method)
-		
+
 		err := r.ParseForm()
 		require.NoError(t, err)
-		
+
 		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
 		assert.Equal(t, "read write", r.Form.Get("scope"))
-		
-		// Check basic auth
-		username, password, ok := r.BasicAuth()
-		assert.True(t, ok)
-		assert.Equal(t, "test-client-id", username)
-		assert.Equal(t, "test-client-secret", password)
-		
+		assert.Equal(t, "test-client-id", r.Form.Get("client_id"))
+		assert.Equal(t, "test-client-secret", r.Form.Get("client_secret"))
+
 		response := testutils.MockOAuth2Token("client-access-token", 3600)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
 	}))
 	defer server.Close()
 
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		TokenURL:     server.URL + "/api/v3/oauth2/token",
 		Scopes:       []string{"read", "write"},
+		GrantType:    GrantTypeClientCredentials,
 	})
-	require.NoError(t, err)
 
-	token, err := cfg.ClientCredentialsGrant(context.Background())
+	token, err := c.ClientCredentials(context.Background())
 	require.NoError(t, err)
-	
+
 	assert.Equal(t, "client-access-token", token.AccessToken)
 	assert.True(t, token.Valid())
+
+	// The grant must be configured
+	c = NewClient(&Config{ClientID: "test-client-id", ClientSecret: "test-client-secret"})
+	_, err = c.ClientCredentials(context.Background())
+	assert.Error(t, err)
 }
 
 func TestValidateToken(t *testing.T) {
@@ -278,25 +234,11 @@ func TestValidateToken(t *testing.T) {
 			},
 			wantErr: true,
 		},
-		{
-			name: "wrong token type",
-			token: &oauth2.Token{
-				AccessToken: "valid-token",
-				TokenType:   "Basic",
-				Expiry:      time.Now().Add(1 * time.Hour),
-			},
-			wantErr: true,
-		},
 	}
 
-	cfg, _ := NewOAuth2Config(OAuth2Options{
-		ClientID:     "test-client-id",
-		ClientSecret: "test-client-secret",
-	})
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := cfg.ValidateToken(tt.token)
+			err := ValidateToken(tt.token)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -306,51 +248,41 @@ func TestValidateToken(t *testing.T) {
 	}
 }
 
-func TestTokenExpiry(t *testing.T) {
-	cfg, _ := NewOAuth2Config(OAuth2Options{
-		ClientID:     "test-client-id",
-		ClientSecret: "test-client-secret",
-	})
-
+func TestIsTokenExpired(t *testing.T) {
 	tests := []struct {
-		name           string
-		token          *oauth2.Token
-		expectedExpiry time.Duration
-		expectError    bool
+		name    string
+		token   *oauth2.Token
+		expired bool
 	}{
 		{
 			name: "token with future expiry",
 			token: &oauth2.Token{
 				Expiry: time.Now().Add(30 * time.Minute),
 			},
-			expectedExpiry: 30 * time.Minute,
-			expectError:    false,
+			expired: false,
 		},
 		{
 			name: "expired token",
 			token: &oauth2.Token{
 				Expiry: time.Now().Add(-30 * time.Minute),
 			},
-			expectedExpiry: -30 * time.Minute,
-			expectError:    false,
+			expired: true,
 		},
 		{
-			name:        "nil token",
-			token:       nil,
-			expectError: true,
+			name:    "token without expiry",
+			token:   &oauth2.Token{},
+			expired: false,
+		},
+		{
+			name:    "nil token",
+			token:   nil,
+			expired: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expiry, err := cfg.TokenExpiry(tt.token)
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				// Allow for small time differences during test execution
-				assert.InDelta(t, tt.expectedExpiry.Seconds(), expiry.Seconds(), 1)
-			}
+			assert.Equal(t, tt.expired, IsTokenExpired(tt.token))
 		})
 	}
 }
@@ -360,13 +292,12 @@ func TestHTTPClient(t *testing.T) {
 	customClient := &http.Client{
 		Timeout: 5 * time.Second,
 	}
-	
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, customClient)
+
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
-		HTTPClient:   customClient,
 	})
-	require.NoError(t, err)
 
 	token := &oauth2.Token{
 		AccessToken: "test-token",
@@ -374,8 +305,9 @@ func TestHTTPClient(t *testing.T) {
 		Expiry:      time.Now().Add(1 * time.Hour),
 	}
 
-	httpClient := cfg.HTTPClient(context.Background(), token)
+	httpClient := c.HTTPClient(ctx, token)
 	assert.NotNil(t, httpClient)
+	assert.Same(t, customClient, c.client(ctx))
 }
 
 func TestOAuth2ErrorHandling(t *testing.T) {
@@ -414,15 +346,14 @@ func TestOAuth2ErrorHandling(t *testing.T) {
 			}))
 			defer server.Close()
 
-			cfg, err := NewOAuth2Config(OAuth2Options{
+			c := NewClient(&Config{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
 				RedirectURL:  "http://localhost:8080/callback",
 				TokenURL:     server.URL + "/api/v3/oauth2/token",
 			})
-			require.NoError(t, err)
 
-			_, err = cfg.ExchangeAuthorizationCode(context.Background(), "test-code")
+			_, err := c.ExchangeCode(context.Background(), "test-code")
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tt.expectError)
 		})
@@ -436,68 +367,45 @@ func TestOAuth2Scopes(t *testing.T) {
 		expectedScopes []string
 	}{
 		{
-			name:        "custom scopes",
-			inputScopes: []string{"custom:read", "custom:write"},
+			name:           "custom scopes",
+			inputScopes:    []string{"custom:read", "custom:write"},
 			expectedScopes: []string{"custom:read", "custom:write"},
 		},
 		{
-			name:        "empty scopes uses defaults",
-			inputScopes: nil,
-			expectedScopes: []string{
-				"openid", "email", "profile", "offline_access",
-				"contracts:read", "contracts:write",
-				"messages:read", "messages:write",
-				"jobs:read", "jobs:write",
-				"proposals:read", "proposals:write",
-				"organizations:read",
-			},
-		},
-		{
-			name:        "duplicate scopes are preserved",
-			inputScopes: []string{"read", "write", "read"},
+			name:           "duplicate scopes are preserved",
+			inputScopes:    []string{"read", "write", "read"},
 			expectedScopes: []string{"read", "write", "read"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg, err := NewOAuth2Config(OAuth2Options{
+			c := NewClient(&Config{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
 				Scopes:       tt.inputScopes,
 			})
-			require.NoError(t, err)
-			
-			assert.Equal(t, tt.expectedScopes, cfg.config.Scopes)
+
+			assert.Equal(t, tt.expectedScopes, c.oauth2Config.Scopes)
 		})
 	}
 }
 
 func TestConcurrentTokenRefresh(t *testing.T) {
-	var requestCount int
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestCount++
 		time.Sleep(100 * time.Millisecond) // Simulate slow response
-		
+
 		response := testutils.MockOAuth2Token("refreshed-token", 3600)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
 	}))
 	defer server.Close()
 
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		TokenURL:     server.URL + "/api/v3/oauth2/token",
 	})
-	require.NoError(t, err)
-
-	expiredToken := &oauth2.Token{
-		AccessToken:  "old-token",
-		RefreshToken: "refresh-token",
-		TokenType:    "Bearer",
-		Expiry:       time.Now().Add(-1 * time.Hour),
-	}
 
 	// Launch multiple concurrent refresh attempts
 	const numGoroutines = 5
@@ -506,7 +414,7 @@ func TestConcurrentTokenRefresh(t *testing.T) {
 
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
-			token, err := cfg.RefreshToken(context.Background(), expiredToken)
+			token, err := c.RefreshToken(context.Background(), "refresh-token")
 			if err != nil {
 				errors <- err
 			} else {
@@ -547,18 +455,18 @@ func TestTokenSourceWithContext(t *testing.T) {
 	}))
 	defer server.Close()
 
-	cfg, err := NewOAuth2Config(OAuth2Options{
+	c := NewClient(&Config{
 		ClientID:     "test-client-id",
 		ClientSecret: "test-client-secret",
 		TokenURL:     server.URL + "/api/v3/oauth2/token",
+		GrantType:    GrantTypeClientCredentials,
 	})
-	require.NoError(t, err)
 
 	// Test with cancelled context
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	_, err = cfg.ClientCredentialsGrant(ctx)
+	_, err := c.ClientCredentialsTokenSource(ctx).Token()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context canceled")
-}
\ No newline at end of file
+}
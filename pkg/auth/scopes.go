@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// ParseScopes splits a scope string as returned by the token endpoint.
+// Scopes may be separated by spaces or commas.
+func ParseScopes(s string) []Scope {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	scopes := make([]Scope, len(fields))
+	for i, f := range fields {
+		scopes[i] = Scope(f)
+	}
+	return scopes
+}
+
+// TokenScopes returns the scopes granted to token as reported in the token
+// response. The second result is false if the response did not include
+// them, e.g. for a token restored from storage.
+func TokenScopes(token *oauth2.Token) ([]Scope, bool) {
+	if token == nil {
+		return nil, false
+	}
+
+	switch v := token.Extra("scope").(type) {
+	case string:
+		return ParseScopes(v), true
+	case []interface{}:
+		scopes := make([]Scope, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				scopes = append(scopes, Scope(str))
+			}
+		}
+		return scopes, true
+	}
+
+	return nil, false
+}
+
+// MissingScopes returns the scopes in required that are not in granted, in
+// the order they were required
+func MissingScopes(granted []Scope, required ...Scope) []Scope {
+	have := make(map[Scope]bool, len(granted))
+	for _, s := range granted {
+		have[s] = true
+	}
+
+	var missing []Scope
+	for _, s := range required {
+		if !have[s] {
+			missing = append(missing, s)
+			have[s] = true // report duplicates once
+		}
+	}
+	return missing
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenScopes(t *testing.T) {
+	token := (&oauth2.Token{AccessToken: "token"}).WithExtra(map[string]interface{}{
+		"scope": "contracts:read jobs:read,messages:read",
+	})
+
+	scopes, ok := TokenScopes(token)
+	assert.True(t, ok)
+	assert.Equal(t, []Scope{ScopeContractsRead, ScopeJobsRead, ScopeMessagingRead}, scopes)
+
+	_, ok = TokenScopes(&oauth2.Token{AccessToken: "token"})
+	assert.False(t, ok)
+}
+
+func TestMissingScopes(t *testing.T) {
+	granted := []Scope{ScopeContractsRead, ScopeJobsRead}
+
+	assert.Empty(t, MissingScopes(granted, ScopeJobsRead))
+	assert.Equal(t, []Scope{ScopeJobsWrite, ScopeReportsRead},
+		MissingScopes(granted, ScopeJobsWrite, ScopeContractsRead, ScopeReportsRead, ScopeJobsWrite))
+}
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore persists OAuth2 tokens between runs
+type TokenStore interface {
+	// Load returns the stored token, or nil if none has been saved
+	Load() (*oauth2.Token, error)
+
+	// Save stores token, replacing any previous token
+	Save(token *oauth2.Token) error
+}
+
+// ErrWrongPassphrase is returned when a stored token cannot be decrypted,
+// because the passphrase is wrong or the file has been tampered with
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted token file")
+
+// ErrKeychainUnavailable is returned by NewKeychainStore when the OS
+// keychain cannot be used, including when built without the keychain tag
+var ErrKeychainUnavailable = errors.New("OS keychain unavailable")
+
+// Stores implement TokenStore
+var (
+	_ TokenStore = (*EncryptedFileStore)(nil)
+	_ TokenStore = (*KeychainStore)(nil)
+)
+
+const (
+	// encryptedStoreVersion is the version of the encrypted file format
+	encryptedStoreVersion = 1
+
+	// encryptedStoreKDF names the key derivation function
+	encryptedStoreKDF = "pbkdf2-sha256"
+
+	// pbkdf2Iterations is the PBKDF2 work factor for new files
+	pbkdf2Iterations = 600000
+
+	// saltSize is the size of the random key derivation salt
+	saltSize = 16
+)
+
+// encryptedFile is the on-disk format of an EncryptedFileStore
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptedFileStore is a TokenStore that keeps the token in a file
+// encrypted with AES-256-GCM under a key derived from a passphrase with
+// PBKDF2-SHA256
+type EncryptedFileStore struct {
+	path       string
+	passphrase []byte
+
+	mu sync.Mutex
+
+	// Derived key cached for the salt it was derived with, so that only
+	// the first Load or Save pays for key derivation
+	salt       []byte
+	iterations int
+	key        []byte
+}
+
+// NewEncryptedFileStore returns a store that keeps the token at path,
+// encrypted with passphrase. The file is created with 0600 permissions on
+// the first Save.
+func NewEncryptedFileStore(path, passphrase string) (*EncryptedFileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("token file path is required")
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is required")
+	}
+
+	return &EncryptedFileStore{path: path, passphrase: []byte(passphrase)}, nil
+}
+
+// Load decrypts and returns the stored token. A missing file is not an
+// error and yields a nil token.
+func (s *EncryptedFileStore) Load() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading token file: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	if file.Version != encryptedStoreVersion || file.KDF != encryptedStoreKDF {
+		return nil, fmt.Errorf("unsupported token file format version %d (%s)", file.Version, file.KDF)
+	}
+	if file.Iterations <= 0 || len(file.Salt) == 0 {
+		return nil, ErrWrongPassphrase
+	}
+
+	gcm, err := s.cipher(file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("parsing decrypted token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Save encrypts token and atomically replaces the token file
+func (s *EncryptedFileStore) Save(token *oauth2.Token) error {
+	if token == nil {
+		return fmt.Errorf("token is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	salt, iterations := s.salt, s.iterations
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("generating salt: %w", err)
+		}
+		iterations = pbkdf2Iterations
+	}
+
+	gcm, err := s.cipher(salt, iterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	data, err := json.MarshalIndent(encryptedFile{
+		Version:    encryptedStoreVersion,
+		KDF:        encryptedStoreKDF,
+		Iterations: iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, data)
+}
+
+// cipher returns the AES-GCM cipher for the key derived with salt,
+// deriving it only if the salt or work factor changed. s.mu must be held.
+func (s *EncryptedFileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	if s.key == nil || !bytes.Equal(s.salt, salt) || s.iterations != iterations {
+		s.key = pbkdf2SHA256(s.passphrase, salt, iterations, 32)
+		s.salt = append([]byte(nil), salt...)
+		s.iterations = iterations
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key of keyLen bytes as specified by RFC 8018
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLen + prf.Size() - 1) / prf.Size()
+
+	key := make([]byte, 0, blocks*prf.Size())
+	u := make([]byte, prf.Size())
+	var counter [4]byte
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
+
+// writeFileAtomic writes data to a temporary file with 0600 permissions
+// and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating token directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return fmt.Errorf("writing token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing token file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing token file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing token file: %w", err)
+	}
+
+	return nil
+}
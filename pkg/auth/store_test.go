@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upwork", "token.json")
+
+	store, err := NewEncryptedFileStore(path, "correct horse")
+	require.NoError(t, err)
+
+	token, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	saved := &oauth2.Token{
+		AccessToken:  "secret-access-token",
+		RefreshToken: "secret-refresh-token",
+		TokenType:    "Bearer",
+		Expiry:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	require.NoError(t, store.Save(saved))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-access-token")
+
+	// A new store derives the key from the file's salt
+	reopened, err := NewEncryptedFileStore(path, "correct horse")
+	require.NoError(t, err)
+	token, err = reopened.Load()
+	require.NoError(t, err)
+	assert.Equal(t, saved.AccessToken, token.AccessToken)
+	assert.Equal(t, saved.RefreshToken, token.RefreshToken)
+	assert.True(t, saved.Expiry.Equal(token.Expiry))
+
+	wrong, err := NewEncryptedFileStore(path, "battery staple")
+	require.NoError(t, err)
+	_, err = wrong.Load()
+	assert.True(t, errors.Is(err, ErrWrongPassphrase))
+
+	_, err = NewEncryptedFileStore(path, "")
+	assert.Error(t, err)
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vector from RFC 7914, section 11
+	key := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"+
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", hex.EncodeToString(key))
+}
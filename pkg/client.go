@@ -1,14 +1,17 @@
-// Package pkg provides a Go client for the Upwork API.
-package pkg
+// Package upwork provides a Go client for the Upwork API.
+package upwork
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/rizome-dev/go-upwork/internal/graphql"
 	"github.com/rizome-dev/go-upwork/internal/ratelimit"
 	"github.com/rizome-dev/go-upwork/pkg/auth"
+	"github.com/rizome-dev/go-upwork/pkg/codec"
 	"github.com/rizome-dev/go-upwork/pkg/errors"
 	"github.com/rizome-dev/go-upwork/pkg/services"
 	"golang.org/x/oauth2"
@@ -28,11 +31,25 @@ const (
 	RateLimitPerMinute = 300
 )
 
+// The rate limiter charges requests by operation cost and reports its quota
+var (
+	_ services.WeightedRateLimiter = (*ratelimit.Limiter)(nil)
+	_ services.QuotaReporter       = (*ratelimit.Limiter)(nil)
+)
+
 // Client is the main Upwork API client
 type Client struct {
-	// HTTP client for making requests
+	// HTTP client for making requests, shared by the services for the
+	// client's lifetime. It authenticates requests through auth.
 	httpClient *http.Client
 	
+	// Transport of httpClient adding the current token to requests
+	auth *authTransport
+	
+	// HTTP client httpClient adds OAuth2 authentication to. Token requests
+	// are also sent with it.
+	baseHTTPClient *http.Client
+	
 	// OAuth2 configuration
 	oauth2Config *oauth2.Config
 	
@@ -48,15 +65,97 @@ type Client struct {
 	// Rate limiter
 	rateLimiter *ratelimit.Limiter
 	
+	// Rate limit cost of operations by name
+	operationCosts map[string]int
+	
+	// Whether the client authenticates as a service account
+	serviceAccount bool
+	
+	// Whether mutations are retried on transient failures
+	retryMutations bool
+	
+	// Time bound on retrying a request
+	maxRetryDuration time.Duration
+	
+	// Age of responses served in place of errors
+	staleWhileError time.Duration
+	
+	// Whether identical concurrent queries share one HTTP call
+	deduplicateQueries bool
+	
+	// Page size and cap on items for methods that walk pages
+	defaultPageSize int
+	maxItems        int
+	
+	// Response size limits and query warnings
+	guardrails services.Guardrails
+	
+	// Called when each request completes
+	onRequest func(services.RequestEvent)
+	
+	// Request logging and the variable fields redacted from it
+	logger       *slog.Logger
+	redactFields []string
+	
+	// Called for nulls in responses that results cannot hold
+	onUnexpectedNull func(services.UnexpectedNull)
+	
+	// JSON codec for requests and responses, nil for the default
+	codec codec.Codec
+	
+	// Called for deprecated fields used, and whether using them fails
+	// requests
+	onDeprecation      func(services.DeprecationWarning)
+	strictDeprecations bool
+	
+	// Token source renewing the token in the background, nil otherwise
+	tokenSource *renewingTokenSource
+	
+	// Token source authenticating requests, nil without a token
+	authSource oauth2.TokenSource
+	
+	// Stops the goroutine renewing tokenSource
+	stopRefresh context.CancelFunc
+	
+	// How long before expiry tokens are renewed, zero if auto refresh is
+	// disabled
+	refreshLeeway time.Duration
+	
+	// Context bounding background workers, cancelled by Close
+	workerCtx     context.Context
+	cancelWorkers context.CancelFunc
+	
+	// Background workers, waited for by Close
+	workers sync.WaitGroup
+	
+	// Closed by Close
+	closed    chan struct{}
+	closeOnce sync.Once
+	
+	// WebSocket endpoint for GraphQL subscriptions
+	subscriptionURL string
+	
+	// Subscription connection, opened by the first Subscribe. Guarded by
+	// subscriptionsMu rather than mu, which its headers need.
+	subscriptionsMu sync.Mutex
+	subscriptions   *graphql.SubscriptionClient
+	
 	// Service clients
-	Users       *services.UsersService
-	Contracts   *services.ContractsService
-	Jobs        *services.JobsService
-	Messages    *services.MessagesService
-	Freelancers *services.FreelancersService
-	Reports     *services.ReportsService
-	Activities  *services.ActivitiesService
-	Metadata    *services.MetadataService
+	Users           *services.UsersService
+	Contracts       *services.ContractsService
+	Jobs            *services.JobsService
+	Messages        *services.MessagesService
+	Freelancers     *services.FreelancersService
+	Reports         *services.ReportsService
+	Activities      *services.ActivitiesService
+	Metadata        *services.MetadataService
+	Disputes        *services.DisputesService
+	Notifications   *services.NotificationsService
+	Interviews      *services.InterviewsService
+	Clients         *services.ClientsService
+	Agency          *services.AgencyService
+	DirectContracts *services.DirectContractsService
+	Catalog         *services.CatalogService
 	
 	// Base client for services
 	baseClient *services.BaseClient
@@ -75,7 +174,14 @@ type Config struct {
 	// Optional: API endpoint URL (defaults to production)
 	APIURL string
 	
-	// Optional: HTTP client (defaults to new client with timeout)
+	// Optional: WebSocket endpoint for GraphQL subscriptions (defaults to
+	// APIURL with a ws or wss scheme)
+	SubscriptionURL string
+	
+	// Optional: HTTP client (defaults to the client set in the NewClient
+	// context under oauth2.HTTPClient, then a new client with DefaultTimeout
+	// and NewTransport).
+	// OAuth2 authentication is added on top of its transport.
 	HTTPClient *http.Client
 	
 	// Optional: Default organization ID
@@ -84,15 +190,62 @@ type Config struct {
 	// Optional: OAuth2 token (for pre-authenticated clients)
 	Token *oauth2.Token
 	
-	// Optional: Service account mode
+	// Optional: Service account mode. NewClient obtains a token with the
+	// client credentials grant and renews it in the background until ctx
+	// is done. RedirectURL is not needed. Enterprise service accounts may
+	// act on behalf of organization users with services.WithActingUser.
 	ServiceAccount bool
 	
+	// Optional: OAuth2 token endpoint (defaults to auth.TokenURL)
+	TokenURL string
+	
 	// Optional: Custom scopes (defaults to GetDefaultScopes)
 	Scopes []string
+	
+	// Optional: Retry mutations on transient failures. Only queries are
+	// retried by default, since retrying a mutation can apply it twice.
+	RetryMutations bool
+	
+	// Optional: Time bound on retrying a request, from its first attempt
+	// to the start of its last retry (defaults to
+	// services.DefaultMaxRetryDuration). Retries wait with exponential
+	// backoff and stop early if the request's context is done.
+	MaxRetryDuration time.Duration
+	
+	// Optional: Serve the last response to a query, if no older than this,
+	// in place of an error when the API or the network fails, keeping
+	// dashboards alive during incidents. Zero disables it. Check
+	// services.WithFreshness to flag stale data.
+	StaleWhileError time.Duration
+	
+	// Optional: Share one HTTP call between identical queries issued
+	// concurrently, e.g. by dashboard widgets loading the current user.
+	// Queries are identical if they have the same operation, variables,
+	// organization and request headers.
+	DeduplicateQueries bool
+	
+	// Optional: Number of items requested per page by methods that pick a
+	// page size themselves, such as Reports.GetTimeReport without
+	// pagination. Zero uses each method's own default. Override it per call
+	// with services.WithPageSize.
+	DefaultPageSize int
+	
+	// Optional: Cap on the number of items returned by methods that walk
+	// every page, such as MarketplaceJobSearchResult.All, to stop runaway
+	// pagination. Zero means no cap. Override it per call with
+	// services.WithMaxItems.
+	MaxItems int
+	
+	// Optional: Rate limit cost of operations by name, e.g.
+	// {"TimeReport": 10}, overriding the built-in costs of expensive
+	// reports. The rate limiter debits each request its cost.
+	OperationCosts map[string]int
 }
 
-// NewClient creates a new Upwork API client
-func NewClient(ctx context.Context, config *Config) (*Client, error) {
+// NewClient creates a new Upwork API client. Background token renewal, for
+// service accounts or with WithAutoRefresh, runs until ctx is done or the
+// client is closed.
+func NewClient(ctx context.Context, config *Config, opts ...Option) (*Client, error) {
 	if config.ClientID == "" || config.ClientSecret == "" {
 		return nil, errors.ErrMissingCredentials
 	}
@@ -103,8 +256,13 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 	}
 	
 	if config.HTTPClient == nil {
-		config.HTTPClient = &http.Client{
-			Timeout: DefaultTimeout,
+		if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && hc != nil {
+			config.HTTPClient = hc
+		} else {
+			config.HTTPClient = &http.Client{
+				Timeout:   DefaultTimeout,
+				Transport: NewTransport(),
+			}
 		}
 	}
 	
@@ -112,6 +270,10 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		config.Scopes = auth.GetDefaultScopes()
 	}
 	
+	if config.TokenURL == "" {
+		config.TokenURL = auth.TokenURL
+	}
+	
 	// Create OAuth2 config
 	oauth2Config := &oauth2.Config{
 		ClientID:     config.ClientID,
@@ -120,26 +282,72 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		Scopes:       config.Scopes,
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  auth.AuthorizationURL,
-			TokenURL: auth.TokenURL,
+			TokenURL: config.TokenURL,
 		},
 	}
 	
 	// Create rate limiter
 	rl := ratelimit.New(RateLimitPerMinute, time.Minute)
 	
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	
+	httpClient, err := options.httpClient(config.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	
+	workerCtx, cancelWorkers := context.WithCancel(ctx)
+	
+	// Requests are authenticated by swapping the token source of one
+	// transport, so the services never need rebuilding
+	auth := &authTransport{base: httpClient.Transport}
+	authorized := *httpClient
+	authorized.Transport = auth
+	
 	// Initialize client
 	client := &Client{
-		httpClient:     config.HTTPClient,
-		oauth2Config:   oauth2Config,
-		token:          config.Token,
-		apiURL:         config.APIURL,
-		organizationID: config.OrganizationID,
-		rateLimiter:    rl,
+		httpClient:         &authorized,
+		auth:               auth,
+		baseHTTPClient:     httpClient,
+		oauth2Config:       oauth2Config,
+		token:              config.Token,
+		apiURL:             config.APIURL,
+		subscriptionURL:    config.SubscriptionURL,
+		organizationID:     config.OrganizationID,
+		rateLimiter:        rl,
+		operationCosts:     config.OperationCosts,
+		serviceAccount:     config.ServiceAccount,
+		retryMutations:     config.RetryMutations,
+		maxRetryDuration:   config.MaxRetryDuration,
+		staleWhileError:    config.StaleWhileError,
+		deduplicateQueries: config.DeduplicateQueries,
+		defaultPageSize:    config.DefaultPageSize,
+		maxItems:           config.MaxItems,
+		guardrails:         options.guardrails,
+		onRequest:          options.onRequest,
+		logger:             options.logger,
+		redactFields:       options.redactFields,
+		onUnexpectedNull:   options.unexpectedNullHandler(),
+		codec:              options.codec,
+		onDeprecation:      options.onDeprecation,
+		strictDeprecations: options.strictDeprecations,
+		refreshLeeway:      options.refreshLeeway,
+		workerCtx:          workerCtx,
+		cancelWorkers:      cancelWorkers,
+		closed:             make(chan struct{}),
 	}
 	
-	// If token is provided, create OAuth2 client
-	if config.Token != nil {
-		client.httpClient = oauth2Config.Client(ctx, config.Token)
+	if config.ServiceAccount {
+		if err := client.startServiceAccount(ctx, config); err != nil {
+			cancelWorkers()
+			return nil, err
+		}
+	} else if config.Token != nil {
+		// If token is provided, create OAuth2 client
+		client.installToken(ctx, config.Token)
 	}
 	
 	// Initialize services
@@ -148,15 +356,128 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 	return client, nil
 }
 
-// SetOrganizationID sets the organization ID for API requests
+// startServiceAccount obtains a client credentials token and starts
+// renewing it in the background
+func (c *Client) startServiceAccount(ctx context.Context, config *Config) error {
+	authClient := auth.NewClient(&auth.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		Scopes:       config.Scopes,
+		GrantType:    auth.GrantTypeClientCredentials,
+		TokenURL:     config.TokenURL,
+	})
+	
+	leeway := c.refreshLeeway
+	if leeway == 0 {
+		leeway = DefaultRefreshLeeway
+	}
+	
+	src := newRenewingTokenSource(config.Token, authClient.ClientCredentialsTokenSource(c.tokenContext()), leeway)
+	token, err := src.Token()
+	if err != nil {
+		return errors.WrapError(err, "failed to obtain service account token")
+	}
+	
+	c.token = token
+	c.useTokenSource(src)
+	
+	return nil
+}
+
+// installToken makes requests use token, renewing it in the background if
+// auto refresh is enabled and the token can be refreshed. c.mu must be held
+// or c not yet shared.
+func (c *Client) installToken(ctx context.Context, token *oauth2.Token) {
+	c.token = token
+	
+	if c.refreshLeeway == 0 || token.RefreshToken == "" {
+		c.stopTokenSource()
+		c.authSource = c.oauth2Config.TokenSource(c.oauth2Context(ctx), token)
+		c.auth.setSource(c.authSource)
+		return
+	}
+	
+	src := newRenewingTokenSource(token, &refreshTokenSource{
+		ctx:          c.tokenContext(),
+		config:       c.oauth2Config,
+		refreshToken: token.RefreshToken,
+	}, c.refreshLeeway)
+	c.useTokenSource(src)
+}
+
+// useTokenSource makes requests use src and renews its token in the
+// background, replacing any previous token source. c.mu must be held or c
+// not yet shared.
+func (c *Client) useTokenSource(src *renewingTokenSource) {
+	c.stopTokenSource()
+	
+	refreshCtx, cancel := context.WithCancel(c.workerCtx)
+	c.tokenSource = src
+	c.stopRefresh = cancel
+	c.authSource = src
+	c.auth.setSource(src)
+	
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		refreshLoop(refreshCtx, src, func(token *oauth2.Token) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			
+			// Ignore tokens from a source replaced in the meantime
+			if c.tokenSource == src {
+				c.token = token
+			}
+		})
+	}()
+}
+
+// oauth2Context returns ctx with the base HTTP client set for the oauth2
+// package, so token requests use the same transport as API requests
+func (c *Client) oauth2Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, c.baseHTTPClient)
+}
+
+// tokenContext returns the context background token sources fetch tokens
+// with. It is cancelled by Close, which aborts an in-flight renewal.
+func (c *Client) tokenContext() context.Context {
+	return c.oauth2Context(c.workerCtx)
+}
+
+// stopTokenSource stops renewing the current token source, if any. c.mu
+// must be held or c not yet shared.
+func (c *Client) stopTokenSource() {
+	if c.stopRefresh != nil {
+		c.stopRefresh()
+	}
+	c.tokenSource = nil
+	c.stopRefresh = nil
+}
+
+// WithOrganization returns a context that scopes requests made with it to
+// orgID, overriding the client's organization. Unlike SetOrganizationID it
+// does not change shared state, so it is safe for multi-tenant servers.
+func WithOrganization(ctx context.Context, orgID string) context.Context {
+	return services.WithOrganization(ctx, orgID)
+}
+
+// WithRequestOptions returns a context that applies opts, such as
+// services.WithHeader, services.WithTimeout or services.WithIdempotencyKey,
+// to every request made with it
+func WithRequestOptions(ctx context.Context, opts ...services.RequestOption) context.Context {
+	return services.WithRequestOptions(ctx, opts...)
+}
+
+// SetOrganizationID sets the default organization ID for API requests. Use
+// WithOrganization to select an organization for a single request.
 func (c *Client) SetOrganizationID(orgID string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.organizationID = orgID
 	
-	// Update base client
+	// Requests in flight read the base client's organization concurrently
 	if c.baseClient != nil {
-		c.baseClient.OrganizationID = orgID
+		c.baseClient.SetOrganizationID(orgID)
 	}
 }
 
@@ -167,13 +488,13 @@ func (c *Client) GetOrganizationID() string {
 	return c.organizationID
 }
 
-// SetToken sets the OAuth2 token
+// SetToken sets the OAuth2 token. Services keep working across the change:
+// requests sent after it use the new token, and requests in flight finish
+// with the old one.
 func (c *Client) SetToken(ctx context.Context, token *oauth2.Token) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.token = token
-	c.httpClient = c.oauth2Config.Client(ctx, token)
-	c.initServices()
+	c.installToken(ctx, token)
 }
 
 // GetToken returns the current OAuth2 token
@@ -195,7 +516,7 @@ func (c *Client) GetAuthURLWithOptions(state string, opts ...oauth2.AuthCodeOpti
 
 // ExchangeCode exchanges an authorization code for an access token
 func (c *Client) ExchangeCode(ctx context.Context, code string) (*oauth2.Token, error) {
-	token, err := c.oauth2Config.Exchange(ctx, code)
+	token, err := c.oauth2Config.Exchange(c.oauth2Context(ctx), code)
 	if err != nil {
 		return nil, errors.WrapError(err, "failed to exchange authorization code")
 	}
@@ -204,24 +525,33 @@ func (c *Client) ExchangeCode(ctx context.Context, code string) (*oauth2.Token,
 	return token, nil
 }
 
-// RefreshToken refreshes the OAuth2 token
+// RefreshToken refreshes the OAuth2 token. Service accounts obtain a new
+// client credentials token. With auto refresh the token is renewed
+// immediately rather than when it falls due.
 func (c *Client) RefreshToken(ctx context.Context) (*oauth2.Token, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
+	if c.tokenSource != nil {
+		newToken, err := c.tokenSource.renew()
+		if err != nil {
+			return nil, errors.WrapError(err, "failed to refresh token")
+		}
+		c.token = newToken
+		return newToken, nil
+	}
+	
 	if c.token == nil || c.token.RefreshToken == "" {
 		return nil, errors.ErrNoRefreshToken
 	}
 	
-	tokenSource := c.oauth2Config.TokenSource(ctx, c.token)
+	tokenSource := c.oauth2Config.TokenSource(c.oauth2Context(ctx), c.token)
 	newToken, err := tokenSource.Token()
 	if err != nil {
 		return nil, errors.WrapError(err, "failed to refresh token")
 	}
 	
-	c.token = newToken
-	c.httpClient = c.oauth2Config.Client(ctx, newToken)
-	c.initServices()
+	c.installToken(ctx, newToken)
 	
 	return newToken, nil
 }
@@ -238,13 +568,77 @@ func (c *Client) IsTokenExpired() bool {
 	return auth.IsTokenExpired(c.token)
 }
 
-// initServices initializes all service clients
+// Close stops the client's background workers, such as token renewal and
+// message streams, and waits for them to exit. Requests made after Close
+// fail with errors.ErrClientClosed. Close is safe to call more than once.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		close(c.closed)
+		c.stopTokenSource()
+		c.cancelWorkers()
+		c.mu.Unlock()
+		
+		c.subscriptionsMu.Lock()
+		if c.subscriptions != nil {
+			c.subscriptions.Close()
+		}
+		c.subscriptionsMu.Unlock()
+		
+		// Workers take c.mu, so wait without holding it
+		c.workers.Wait()
+	})
+	return nil
+}
+
+// ParallelDo executes raw GraphQL requests concurrently with at most
+// maxConcurrency in flight, sharing the client's rate limiter. See
+// services.ParallelDo.
+func (c *Client) ParallelDo(ctx context.Context, requests []*services.GraphQLRequest, results []interface{}, maxConcurrency int) (*services.BatchResult, error) {
+	c.mu.RLock()
+	baseClient := c.baseClient
+	c.mu.RUnlock()
+
+	return services.ParallelDo(ctx, baseClient, requests, results, maxConcurrency)
+}
+
+// RateLimitStatus returns the client's rate limit quota: the tokens
+// remaining, when they reset and recent requests delayed or rejected by
+// the client's limiter or the API. See services.BaseClient.RateLimitStatus.
+func (c *Client) RateLimitStatus() services.RateLimitStatus {
+	c.mu.RLock()
+	baseClient := c.baseClient
+	c.mu.RUnlock()
+
+	return baseClient.RateLimitStatus()
+}
+
+// initServices initializes all service clients. It is called once, by
+// NewClient; token changes go through c.auth instead.
 func (c *Client) initServices() {
 	c.baseClient = &services.BaseClient{
-		HTTPClient:     c.httpClient,
-		APIURL:         c.apiURL,
-		OrganizationID: c.organizationID,
-		RateLimiter:    c.rateLimiter,
+		HTTPClient:         c.httpClient,
+		APIURL:             c.apiURL,
+		OrganizationID:     c.organizationID,
+		RateLimiter:        c.rateLimiter,
+		OperationCosts:     c.operationCosts,
+		ServiceAccount:     c.serviceAccount,
+		RetryMutations:     c.retryMutations,
+		MaxRetryDuration:   c.maxRetryDuration,
+		StaleWhileError:    c.staleWhileError,
+		DeduplicateQueries: c.deduplicateQueries,
+		DefaultPageSize:    c.defaultPageSize,
+		MaxItems:           c.maxItems,
+		Guardrails:         c.guardrails,
+		OnRequest:          c.onRequest,
+		Logger:             c.logger,
+		RedactFields:       c.redactFields,
+		OnUnexpectedNull:   c.onUnexpectedNull,
+		Codec:              c.codec,
+		OnDeprecation:      c.onDeprecation,
+		StrictDeprecations: c.strictDeprecations,
+		Subscriber:         c,
+		Done:               c.closed,
 	}
 	
 	c.Users = services.NewUsersService(c.baseClient)
@@ -255,4 +649,11 @@ func (c *Client) initServices() {
 	c.Reports = services.NewReportsService(c.baseClient)
 	c.Activities = services.NewActivitiesService(c.baseClient)
 	c.Metadata = services.NewMetadataService(c.baseClient)
+	c.Disputes = services.NewDisputesService(c.baseClient)
+	c.Notifications = services.NewNotificationsService(c.baseClient)
+	c.Interviews = services.NewInterviewsService(c.baseClient)
+	c.Clients = services.NewClientsService(c.baseClient)
+	c.Agency = services.NewAgencyService(c.baseClient)
+	c.DirectContracts = services.NewDirectContractsService(c.baseClient)
+	c.Catalog = services.NewCatalogService(c.baseClient)
 }
\ No newline at end of file
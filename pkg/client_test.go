@@ -5,10 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
-	"github.com/rizome-dev/go-upwork/pkg/auth"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
 	"github.com/rizome-dev/go-upwork/tests/mocks"
 	"github.com/rizome-dev/go-upwork/tests/testutils"
 	"github.com/stretchr/testify/assert"
@@ -16,24 +17,62 @@ import (
 	"golang.org/x/oauth2"
 )
 
+const testTokenURL = "https://api.upwork.com/api/v3/oauth2/token"
+
+// validToken returns a bearer token valid for an hour
+func validToken(accessToken string) *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(1 * time.Hour),
+	}
+}
+
+// tokenResponse returns a token endpoint response issuing accessToken
+func tokenResponse(accessToken, refreshToken string) mocks.MockResponse {
+	return mocks.MockResponse{
+		StatusCode: 200,
+		Body: fmt.Sprintf(`{
+			"access_token": %q,
+			"token_type": "Bearer",
+			"expires_in": 3600,
+			"refresh_token": %q
+		}`, accessToken, refreshToken),
+	}
+}
+
+// newTestClient creates a client with test credentials sending requests
+// through recorder
+func newTestClient(t *testing.T, recorder *mocks.RequestRecorder, token *oauth2.Token, opts ...Option) *Client {
+	t.Helper()
+
+	client, err := NewClient(context.Background(), &Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		TokenURL:     testTokenURL,
+		Token:        token,
+		HTTPClient:   &http.Client{Transport: recorder},
+	}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
 func TestNewClient(t *testing.T) {
 	tests := []struct {
-		name    string
-		config  Config
-		wantErr bool
+		name     string
+		config   Config
+		wantErr  error
 		validate func(t *testing.T, client *Client)
 	}{
 		{
 			name: "valid configuration",
 			config: Config{
-				OAuth2Config: &auth.OAuth2Config{},
-				Token: &oauth2.Token{
-					AccessToken: "test-token",
-					TokenType:   "Bearer",
-					Expiry:      time.Now().Add(1 * time.Hour),
-				},
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				Token:        validToken("test-token"),
 			},
-			wantErr: false,
 			validate: func(t *testing.T, client *Client) {
 				assert.NotNil(t, client)
 				assert.NotNil(t, client.Users)
@@ -44,188 +83,166 @@ func TestNewClient(t *testing.T) {
 				assert.NotNil(t, client.Reports)
 				assert.NotNil(t, client.Activities)
 				assert.NotNil(t, client.Metadata)
+				assert.Equal(t, DefaultAPIURL, client.apiURL)
 			},
 		},
 		{
-			name: "missing OAuth2 config",
+			name: "missing client ID",
 			config: Config{
-				Token: &oauth2.Token{
-					AccessToken: "test-token",
-				},
+				ClientSecret: "test-secret",
+				Token:        validToken("test-token"),
 			},
-			wantErr: true,
+			wantErr: errors.ErrMissingCredentials,
 		},
 		{
-			name: "missing token",
+			name: "missing client secret",
 			config: Config{
-				OAuth2Config: &auth.OAuth2Config{},
+				ClientID: "test-client",
+				Token:    validToken("test-token"),
+			},
+			wantErr: errors.ErrMissingCredentials,
+		},
+		{
+			name: "without token",
+			config: Config{
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				RedirectURL:  "https://example.com/callback",
+			},
+			validate: func(t *testing.T, client *Client) {
+				assert.Nil(t, client.GetToken())
+				assert.True(t, client.IsTokenExpired())
 			},
-			wantErr: true,
 		},
 		{
 			name: "with organization ID",
 			config: Config{
-				OAuth2Config: &auth.OAuth2Config{},
-				Token: &oauth2.Token{
-					AccessToken: "test-token",
-					TokenType:   "Bearer",
-					Expiry:      time.Now().Add(1 * time.Hour),
-				},
+				ClientID:       "test-client",
+				ClientSecret:   "test-secret",
+				Token:          validToken("test-token"),
 				OrganizationID: "org-123",
 			},
-			wantErr: false,
 			validate: func(t *testing.T, client *Client) {
-				assert.Equal(t, "org-123", client.organizationID)
+				assert.Equal(t, "org-123", client.GetOrganizationID())
 			},
 		},
 		{
 			name: "with custom HTTP client",
 			config: Config{
-				OAuth2Config: &auth.OAuth2Config{},
-				Token: &oauth2.Token{
-					AccessToken: "test-token",
-					TokenType:   "Bearer",
-					Expiry:      time.Now().Add(1 * time.Hour),
-				},
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				Token:        validToken("test-token"),
 				HTTPClient: &http.Client{
 					Timeout: 5 * time.Second,
 				},
 			},
-			wantErr: false,
-		},
-		{
-			name: "with custom rate limiter",
-			config: Config{
-				OAuth2Config: &auth.OAuth2Config{},
-				Token: &oauth2.Token{
-					AccessToken: "test-token",
-					TokenType:   "Bearer",
-					Expiry:      time.Now().Add(1 * time.Hour),
-				},
-				RateLimiter: mocks.NewMockRateLimiter(),
+			validate: func(t *testing.T, client *Client) {
+				assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
 			},
-			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewClient(tt.config)
-			if tt.wantErr {
-				assert.Error(t, err)
+			client, err := NewClient(context.Background(), &tt.config)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, client)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, client)
-				if tt.validate != nil {
-					tt.validate(t, client)
-				}
+				return
+			}
+
+			require.NoError(t, err)
+			defer client.Close()
+			if tt.validate != nil {
+				tt.validate(t, client)
 			}
 		})
 	}
 }
 
 func TestClientWithOptions(t *testing.T) {
-	baseConfig := Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token: &oauth2.Token{
-			AccessToken: "test-token",
-			TokenType:   "Bearer",
-			Expiry:      time.Now().Add(1 * time.Hour),
-		},
-	}
+	recorder := mocks.NewRequestRecorder()
 
 	tests := []struct {
 		name     string
-		option   ClientOption
+		option   Option
 		validate func(t *testing.T, client *Client)
 	}{
 		{
-			name:   "with organization ID",
-			option: WithOrganizationID("org-456"),
+			name:   "with auto refresh",
+			option: WithAutoRefresh(2 * time.Minute),
 			validate: func(t *testing.T, client *Client) {
-				assert.Equal(t, "org-456", client.organizationID)
+				assert.Equal(t, 2*time.Minute, client.refreshLeeway)
 			},
 		},
 		{
-			name:   "with rate limiter",
-			option: WithRateLimiter(mocks.NewMockRateLimiter()),
+			name:   "with transport",
+			option: WithTransport(recorder),
 			validate: func(t *testing.T, client *Client) {
-				assert.NotNil(t, client.config.RateLimiter)
+				assert.Equal(t, recorder, client.baseHTTPClient.Transport)
 			},
 		},
 		{
-			name:   "with HTTP client",
-			option: WithHTTPClient(&http.Client{Timeout: 10 * time.Second}),
+			name:   "with strict deprecations",
+			option: WithStrictDeprecations(),
 			validate: func(t *testing.T, client *Client) {
-				assert.NotNil(t, client.config.HTTPClient)
+				assert.True(t, client.strictDeprecations)
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewClient(baseConfig, tt.option)
+			client, err := NewClient(context.Background(), &Config{
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				Token:        validToken("test-token"),
+			}, tt.option)
 			require.NoError(t, err)
+			defer client.Close()
+
 			tt.validate(t, client)
 		})
 	}
 }
 
 func TestRefreshToken(t *testing.T) {
-	mockHTTPClient := mocks.NewRequestRecorder(
-		mocks.MockResponse{
-			StatusCode: 200,
-			Body: `{
-				"access_token": "new-access-token",
-				"token_type": "Bearer",
-				"expires_in": 3600,
-				"refresh_token": "new-refresh-token"
-			}`,
-		},
-	)
+	recorder := mocks.NewRequestRecorder(tokenResponse("new-access-token", "new-refresh-token"))
 
-	oauth2Config, err := auth.NewOAuth2Config(auth.OAuth2Options{
-		ClientID:     "test-client",
-		ClientSecret: "test-secret",
-		TokenURL:     "https://api.upwork.com/api/v3/oauth2/token",
+	client := newTestClient(t, recorder, &oauth2.Token{
+		AccessToken:  "old-token",
+		RefreshToken: "old-refresh-token",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(-1 * time.Hour), // Expired
 	})
-	require.NoError(t, err)
 
-	client, err := NewClient(Config{
-		OAuth2Config: oauth2Config,
-		Token: &oauth2.Token{
-			AccessToken:  "old-token",
-			RefreshToken: "old-refresh-token",
-			TokenType:    "Bearer",
-			Expiry:       time.Now().Add(-1 * time.Hour), // Expired
-		},
-		HTTPClient: mockHTTPClient,
-	})
+	token, err := client.RefreshToken(context.Background())
 	require.NoError(t, err)
-
-	err = client.RefreshToken(context.Background())
-	assert.NoError(t, err)
+	assert.Equal(t, "new-access-token", token.AccessToken)
 
 	// Verify token was updated
-	client.tokenMu.RLock()
+	client.mu.RLock()
 	assert.Equal(t, "new-access-token", client.token.AccessToken)
 	assert.Equal(t, "new-refresh-token", client.token.RefreshToken)
-	client.tokenMu.RUnlock()
+	client.mu.RUnlock()
+
+	require.Equal(t, 1, recorder.CallCount)
+	assert.Equal(t, testTokenURL, recorder.GetLastRequest().URL.String())
+}
+
+func TestRefreshTokenWithoutRefreshToken(t *testing.T) {
+	recorder := mocks.NewRequestRecorder()
+	client := newTestClient(t, recorder, validToken("test-token"))
+
+	_, err := client.RefreshToken(context.Background())
+	assert.ErrorIs(t, err, errors.ErrNoRefreshToken)
+	assert.Equal(t, 0, recorder.CallCount)
 }
 
 func TestGetToken(t *testing.T) {
-	token := &oauth2.Token{
-		AccessToken: "test-token",
-		TokenType:   "Bearer",
-		Expiry:      time.Now().Add(1 * time.Hour),
-	}
+	token := validToken("test-token")
 
-	client, err := NewClient(Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token:        token,
-	})
-	require.NoError(t, err)
+	client := newTestClient(t, mocks.NewRequestRecorder(), token)
 
 	retrievedToken := client.GetToken()
 	assert.Equal(t, token.AccessToken, retrievedToken.AccessToken)
@@ -233,80 +250,43 @@ func TestGetToken(t *testing.T) {
 }
 
 func TestSetOrganizationID(t *testing.T) {
-	client, err := NewClient(Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token: &oauth2.Token{
-			AccessToken: "test-token",
-			TokenType:   "Bearer",
-			Expiry:      time.Now().Add(1 * time.Hour),
-		},
-	})
-	require.NoError(t, err)
+	client := newTestClient(t, mocks.NewRequestRecorder(), validToken("test-token"))
 
 	client.SetOrganizationID("new-org-123")
-	assert.Equal(t, "new-org-123", client.organizationID)
+	assert.Equal(t, "new-org-123", client.GetOrganizationID())
 }
 
 func TestConcurrentTokenAccess(t *testing.T) {
-	client, err := NewClient(Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token: &oauth2.Token{
-			AccessToken: "initial-token",
-			TokenType:   "Bearer",
-			Expiry:      time.Now().Add(1 * time.Hour),
-		},
-	})
-	require.NoError(t, err)
+	client := newTestClient(t, mocks.NewRequestRecorder(), validToken("initial-token"))
+
+	var wg sync.WaitGroup
 
-	// Simulate concurrent access
-	done := make(chan bool)
-	
 	// Writer goroutines
 	for i := 0; i < 5; i++ {
+		wg.Add(1)
 		go func(id int) {
-			newToken := &oauth2.Token{
-				AccessToken: fmt.Sprintf("token-%d", id),
-				TokenType:   "Bearer",
-				Expiry:      time.Now().Add(1 * time.Hour),
-			}
-			client.tokenMu.Lock()
-			client.token = newToken
-			client.tokenMu.Unlock()
-			done <- true
+			defer wg.Done()
+			client.SetToken(context.Background(), validToken(fmt.Sprintf("token-%d", id)))
 		}(i)
 	}
 
 	// Reader goroutines
 	for i := 0; i < 5; i++ {
+		wg.Add(1)
 		go func() {
+			defer wg.Done()
 			token := client.GetToken()
 			assert.NotNil(t, token)
 			assert.Contains(t, token.AccessToken, "token")
-			done <- true
 		}()
 	}
 
-	// Wait for all goroutines to complete
-	for i := 0; i < 10; i++ {
-		<-done
-	}
+	wg.Wait()
 }
 
 func TestClientInitialization(t *testing.T) {
-	// Test that all services are properly initialized with the same GraphQL client
-	client, err := NewClient(Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token: &oauth2.Token{
-			AccessToken: "test-token",
-			TokenType:   "Bearer",
-			Expiry:      time.Now().Add(1 * time.Hour),
-		},
-		OrganizationID: "test-org",
-	})
-	require.NoError(t, err)
+	client := newTestClient(t, mocks.NewRequestRecorder(), validToken("test-token"))
 
-	// Use reflection or type assertions to verify services share the same base
-	// This is a simplified check - in practice you might want more thorough verification
 	assert.NotNil(t, client.Users)
 	assert.NotNil(t, client.Contracts)
 	assert.NotNil(t, client.Jobs)
@@ -315,57 +295,39 @@ func TestClientInitialization(t *testing.T) {
 	assert.NotNil(t, client.Reports)
 	assert.NotNil(t, client.Activities)
 	assert.NotNil(t, client.Metadata)
+	assert.NotNil(t, client.Disputes)
+	assert.NotNil(t, client.Notifications)
+	assert.NotNil(t, client.Interviews)
+	assert.NotNil(t, client.Clients)
+	assert.NotNil(t, client.Agency)
+	assert.NotNil(t, client.DirectContracts)
+	assert.NotNil(t, client.Catalog)
 }
 
 func TestTokenAutoRefresh(t *testing.T) {
-	refreshCount := 0
-	mockHTTPClient := &mocks.MockHTTPClient{
-		Responses: []mocks.MockResponse{
-			{
-				StatusCode: 200,
-				Body: `{
-					"access_token": "refreshed-token",
-					"token_type": "Bearer",
-					"expires_in": 3600,
-					"refresh_token": "new-refresh-token"
-				}`,
-			},
+	recorder := mocks.NewRequestRecorder(
+		tokenResponse("refreshed-token", "new-refresh-token"),
+		mocks.MockResponse{
+			StatusCode: 200,
+			Body:       `{"data": {"user": {"id": "user-123"}}}`,
 		},
-	}
-
-	// Override the Do method to count refreshes
-	originalDo := mockHTTPClient.Do
-	mockHTTPClient.Do = func(req *http.Request) (*http.Response, error) {
-		if req.URL.Path == "/api/v3/oauth2/token" {
-			refreshCount++
-		}
-		return originalDo(req)
-	}
-
-	oauth2Config, err := auth.NewOAuth2Config(auth.OAuth2Options{
-		ClientID:     "test-client",
-		ClientSecret: "test-secret",
-		TokenURL:     "https://api.upwork.com/api/v3/oauth2/token",
-	})
-	require.NoError(t, err)
+	)
 
 	// Create client with an expired token
-	client, err := NewClient(Config{
-		OAuth2Config: oauth2Config,
-		Token: &oauth2.Token{
-			AccessToken:  "expired-token",
-			RefreshToken: "refresh-token",
-			TokenType:    "Bearer",
-			Expiry:       time.Now().Add(-1 * time.Hour), // Already expired
-		},
-		HTTPClient: mockHTTPClient,
+	client := newTestClient(t, recorder, &oauth2.Token{
+		AccessToken:  "expired-token",
+		RefreshToken: "refresh-token",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(-1 * time.Hour), // Already expired
 	})
-	require.NoError(t, err)
 
 	// Token should be automatically refreshed when needed
-	err = client.RefreshToken(context.Background())
-	assert.NoError(t, err)
-	assert.Equal(t, 1, refreshCount)
+	_, err := client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 2, recorder.CallCount)
+	assert.Equal(t, testTokenURL, recorder.Requests[0].URL.String())
+	assert.Equal(t, "Bearer refreshed-token", recorder.Requests[1].Header.Get("Authorization"))
 }
 
 func TestClientWithInvalidToken(t *testing.T) {
@@ -401,24 +363,24 @@ func TestClientWithInvalidToken(t *testing.T) {
 			},
 			wantErr: true,
 		},
-		{
-			name: "wrong token type",
-			token: &oauth2.Token{
-				AccessToken: "token",
-				TokenType:   "Basic",
-			},
-			wantErr: true,
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := NewClient(Config{
-				OAuth2Config: &auth.OAuth2Config{},
-				Token:        tt.token,
-			})
+			recorder := mocks.NewRequestRecorder(
+				tokenResponse("refreshed-token", "new-refresh-token"),
+				mocks.MockResponse{
+					StatusCode: 200,
+					Body:       `{"data": {"user": {"id": "user-123"}}}`,
+				},
+			)
+			client := newTestClient(t, recorder, tt.token)
+
+			// Tokens are checked when a request needs one
+			_, err := client.Users.GetCurrentUser(context.Background())
 			if tt.wantErr {
 				assert.Error(t, err)
+				assert.Equal(t, 0, recorder.CallCount)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -429,17 +391,17 @@ func TestClientWithInvalidToken(t *testing.T) {
 func TestGraphQLClientIntegration(t *testing.T) {
 	// This test verifies that the GraphQL client is properly configured
 	// with authentication headers and organization ID
-	
+
 	mockResponse := testutils.MockGraphQLResponse(
 		map[string]interface{}{
-			"viewer": map[string]interface{}{
+			"user": map[string]interface{}{
 				"id":    "user-123",
 				"email": "test@example.com",
 			},
 		},
 		nil,
 	)
-	
+
 	responseBody, _ := json.Marshal(mockResponse)
 	recorder := mocks.NewRequestRecorder(
 		mocks.MockResponse{
@@ -448,31 +410,16 @@ func TestGraphQLClientIntegration(t *testing.T) {
 		},
 	)
 
-	client, err := NewClient(Config{
-		OAuth2Config: &auth.OAuth2Config{},
-		Token: &oauth2.Token{
-			AccessToken: "test-token",
-			TokenType:   "Bearer",
-			Expiry:      time.Now().Add(1 * time.Hour),
-		},
-		OrganizationID: "org-123",
-		HTTPClient:     recorder,
-	})
-	require.NoError(t, err)
+	client := newTestClient(t, recorder, validToken("test-token"))
+	client.SetOrganizationID("org-123")
 
-	// Make a request through one of the services to verify headers
-	ctx := context.Background()
-	user, err := client.Users.GetCurrentUser(ctx)
-	
-	// Even if the method doesn't exist, we can verify the request was made correctly
-	if err == nil {
-		assert.NotNil(t, user)
-	}
+	user, err := client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", string(user.ID))
 
 	// Verify the request headers
-	if len(recorder.Requests) > 0 {
-		req := recorder.GetLastRequest()
-		assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
-		assert.Equal(t, "org-123", req.Header.Get("X-Organization-UID"))
-	}
-}
\ No newline at end of file
+	require.Equal(t, 1, recorder.CallCount)
+	req := recorder.GetLastRequest()
+	assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "org-123", req.Header.Get("X-Upwork-API-TenantId"))
+}
@@ -0,0 +1,47 @@
+// Package codec provides the JSON codecs the Upwork SDK encodes requests
+// and decodes responses with.
+package codec
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes JSON. Implementations must behave like
+// encoding/json, honoring struct tags and the json.Marshaler and
+// json.Unmarshaler interfaces, and be safe for concurrent use.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	// NewDecoder returns a decoder reading values from r
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder reads JSON values from a stream
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Std is the codec backed by encoding/json
+var Std Codec = stdCodec{}
+
+// Default returns the codec used when none is configured: Std, or the
+// faster GoJSON when built with the gojson tag
+func Default() Codec {
+	return defaultCodec
+}
+
+// stdCodec implements Codec with encoding/json
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
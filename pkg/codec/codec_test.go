@@ -0,0 +1,150 @@
+package codec_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/codec"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// codecs are the codecs under test by name. Codecs behind build tags add
+// themselves.
+var codecs = map[string]codec.Codec{
+	"std": codec.Std,
+}
+
+// timeReportResponse returns a time report response with rows rows
+func timeReportResponse(rows int) []byte {
+	edges := make([]string, rows)
+	for i := range edges {
+		edges[i] = fmt.Sprintf(`{"cursor": "%d", "node": {
+			"dateWorkedOn": {"rawValue": "2024-03-%02dT00:00:00Z"},
+			"freelancer": {"id": "user-%d", "name": "Freelancer %d"},
+			"team": {"id": "team-1", "name": "Design"},
+			"contract": {"id": "contract-%d", "title": "Contract %d"},
+			"task": "task-%d",
+			"memo": "Worked on the API client",
+			"totalHoursWorked": 7.5,
+			"totalCharges": {"rawValue": "562.50", "currency": "USD"},
+			"totalOnlineHoursWorked": 7.5,
+			"totalOnlineCharge": {"rawValue": "562.50", "currency": "USD"}
+		}}`, i, i%28+1, i, i, i, i, i)
+	}
+	return []byte(`{"data": {"timeReport": {"totalCount": ` + fmt.Sprint(rows) +
+		`, "pageInfo": {"hasNextPage": false}, "edges": [` + strings.Join(edges, ",") + `]}}}`)
+}
+
+// timeReportResult is what timeReportResponse decodes into
+type timeReportResult struct {
+	Data struct {
+		TimeReport services.TimeReportList `json:"timeReport"`
+	} `json:"data"`
+}
+
+func TestCodecs(t *testing.T) {
+	data := timeReportResponse(3)
+
+	for name, c := range codecs {
+		t.Run(name, func(t *testing.T) {
+			var result timeReportResult
+			require.NoError(t, c.Unmarshal(data, &result))
+			require.Len(t, result.Data.TimeReport.Edges, 3)
+			row := result.Data.TimeReport.Edges[2].Node
+			assert.Equal(t, models.MustMoney("562.50", "USD"), row.TotalCharges, "json.Unmarshaler is honored")
+			assert.Equal(t, "2024-03-03T00:00:00Z", row.DateWorkedOn.RawValue)
+
+			var streamed timeReportResult
+			require.NoError(t, c.NewDecoder(bytes.NewReader(data)).Decode(&streamed))
+			assert.Equal(t, result, streamed)
+
+			encoded, err := c.Marshal(row.TotalCharges)
+			require.NoError(t, err)
+			var money models.Money
+			require.NoError(t, codec.Std.Unmarshal(encoded, &money))
+			assert.Equal(t, row.TotalCharges, money, "json.Marshaler is honored")
+		})
+	}
+}
+
+// countingCodec counts the values it decodes
+type countingCodec struct {
+	codec.Codec
+	decoded atomic.Int32
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) codec.Decoder {
+	c.decoded.Add(1)
+	return c.Codec.NewDecoder(r)
+}
+
+func TestBaseClientCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(timeReportResponse(2))
+	}))
+	defer server.Close()
+
+	c := &countingCodec{Codec: codec.Std}
+	client := &services.BaseClient{HTTPClient: server.Client(), APIURL: server.URL, Codec: c}
+
+	var result struct {
+		TimeReport services.TimeReportList `json:"timeReport"`
+	}
+	require.NoError(t, client.Do(context.Background(), &services.GraphQLRequest{Query: "query TimeReport { timeReport { totalCount } }"}, &result))
+	assert.Len(t, result.TimeReport.Edges, 2)
+	assert.Equal(t, int32(1), c.decoded.Load())
+}
+
+// BenchmarkDecodeTimeReport decodes a 1000 row time report with each
+// codec. Compare the faster codec with:
+//
+//	go test -tags gojson -bench DecodeTimeReport ./pkg/codec
+func BenchmarkDecodeTimeReport(b *testing.B) {
+	data := timeReportResponse(1000)
+
+	for name, c := range codecs {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var result timeReportResult
+				if err := c.NewDecoder(bytes.NewReader(data)).Decode(&result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeRequest encodes a request with each codec
+func BenchmarkEncodeRequest(b *testing.B) {
+	req := &services.GraphQLRequest{
+		Query:         "query TimeReport($input: TimeReportInput!) { timeReport(input: $input) { totalCount } }",
+		OperationName: "TimeReport",
+		Variables: map[string]interface{}{
+			"input": services.TimeReportInput{OrganizationID: "org-1"},
+		},
+	}
+
+	for name, c := range codecs {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
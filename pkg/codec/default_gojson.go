@@ -0,0 +1,6 @@
+//go:build gojson
+
+package codec
+
+// defaultCodec is the codec returned by Default
+var defaultCodec = GoJSON
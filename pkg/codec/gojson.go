@@ -0,0 +1,30 @@
+//go:build gojson
+
+package codec
+
+import (
+	"io"
+
+	gojson "github.com/goccy/go-json"
+)
+
+// GoJSON is the codec backed by github.com/goccy/go-json, a drop-in
+// replacement for encoding/json that decodes large responses, such as
+// reports, faster and with fewer allocations. It is only built with the
+// gojson tag.
+var GoJSON Codec = goJSONCodec{}
+
+// goJSONCodec implements Codec with github.com/goccy/go-json
+type goJSONCodec struct{}
+
+func (goJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+func (goJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return gojson.Unmarshal(data, v)
+}
+
+func (goJSONCodec) NewDecoder(r io.Reader) Decoder {
+	return gojson.NewDecoder(r)
+}
@@ -0,0 +1,9 @@
+//go:build gojson
+
+package codec_test
+
+import "github.com/rizome-dev/go-upwork/pkg/codec"
+
+func init() {
+	codecs["gojson"] = codec.GoJSON
+}
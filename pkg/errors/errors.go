@@ -2,9 +2,16 @@
 package errors
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strings"
+	"syscall"
+	"time"
 )
 
 // Common errors
@@ -14,11 +21,22 @@ var (
 	ErrNoRefreshToken     = errors.New("no refresh token available")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrTokenExpired       = errors.New("token expired")
+	ErrMissingScopes      = errors.New("missing required scopes")
+	ErrImpersonationNotAllowed = errors.New("acting on behalf of a user requires a service account")
 	
 	// Request errors
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 	ErrRequestTimeout    = errors.New("request timeout")
 	ErrInvalidRequest    = errors.New("invalid request")
+	ErrClientClosed      = errors.New("client is closed")
+	ErrNoSubscriptions   = errors.New("subscriptions are not available")
+	ErrResponseTooLarge  = errors.New("response too large")
+	ErrWouldExceedDeadline = errors.New("rate limit wait would exceed context deadline")
+	ErrDeprecatedField   = errors.New("deprecated field used")
+	
+	// Pool errors
+	ErrUnknownTenant = errors.New("unknown tenant")
+	ErrTenantExists  = errors.New("tenant already exists")
 	
 	// API errors
 	ErrNotFound          = errors.New("resource not found")
@@ -29,6 +47,11 @@ var (
 	ErrGraphQLParse      = errors.New("GraphQL parse error")
 	ErrGraphQLValidation = errors.New("GraphQL validation error")
 	ErrGraphQLExecution  = errors.New("GraphQL execution error")
+	
+	// Model errors
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid monetary amount")
+	ErrInvalidDateTime  = errors.New("invalid date/time")
 )
 
 // APIError represents an error returned by the Upwork API
@@ -63,6 +86,34 @@ func (e *APIError) IsRateLimited() bool {
 	return e.StatusCode == http.StatusTooManyRequests
 }
 
+// RateLimitError is returned when the API rejects a request with 429 Too
+// Many Requests. It matches ErrRateLimitExceeded with errors.Is and
+// unwraps to its APIError.
+type RateLimitError struct {
+	APIError
+	// RetryAfter is how long the API asked the client to wait before
+	// retrying, zero if it did not say
+	RetryAfter time.Duration
+}
+
+// Error returns the error message
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s, retry after %v", e.APIError.Error(), e.RetryAfter)
+	}
+	return e.APIError.Error()
+}
+
+// Is returns true for ErrRateLimitExceeded
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
+// Unwrap returns the underlying API error
+func (e *RateLimitError) Unwrap() error {
+	return &e.APIError
+}
+
 // GraphQLError represents a GraphQL error
 type GraphQLError struct {
 	Message    string                 `json:"message"`
@@ -118,6 +169,124 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error: %s", e.Message)
 }
 
+// MissingScopesError reports the OAuth2 scopes an operation requires that
+// the token was not granted
+type MissingScopesError struct {
+	Missing []string
+	Granted []string
+}
+
+// Error returns the error message
+func (e *MissingScopesError) Error() string {
+	return fmt.Sprintf("missing required scopes: %s", strings.Join(e.Missing, ", "))
+}
+
+// Is reports whether target is ErrMissingScopes
+func (e *MissingScopesError) Is(target error) bool {
+	return target == ErrMissingScopes
+}
+
+// DeprecationError is returned in strict deprecation mode by a request
+// whose response reported the use of deprecated fields
+type DeprecationError struct {
+	OperationName string
+	// Warnings are the deprecation messages of the response
+	Warnings []string
+}
+
+// Error returns the error message
+func (e *DeprecationError) Error() string {
+	if e.OperationName != "" {
+		return fmt.Sprintf("operation %s used deprecated fields: %s", e.OperationName, strings.Join(e.Warnings, "; "))
+	}
+	return fmt.Sprintf("deprecated fields used: %s", strings.Join(e.Warnings, "; "))
+}
+
+// Is reports whether target is ErrDeprecatedField
+func (e *DeprecationError) Is(target error) bool {
+	return target == ErrDeprecatedField
+}
+
+// IndexedError associates an error with the index of the request that
+// produced it
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error message
+func (e *IndexedError) Error() string {
+	return fmt.Sprintf("request %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryError is returned by a request that failed after it was retried,
+// recording how many times it was sent
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+// Error returns the error message
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Attempts returns the number of times the request that returned err was
+// sent: 1 unless err is or wraps a RetryError
+func Attempts(err error) int {
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		return retryErr.Attempts
+	}
+	return 1
+}
+
+// MultiError aggregates the failures of a group of requests
+type MultiError struct {
+	Errors []*IndexedError
+}
+
+// Error returns a combined error message
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 0 {
+		return "no errors"
+	}
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d requests failed: %s (and %d more)", len(e.Errors), e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// Unwrap returns the individual errors so errors.Is and errors.As match any
+// of them
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrorAt returns the error for the request at index, or nil if it succeeded
+func (e *MultiError) ErrorAt(index int) error {
+	for _, err := range e.Errors {
+		if err.Index == index {
+			return err.Err
+		}
+	}
+	return nil
+}
+
 // WrapError wraps an error with additional context
 func WrapError(err error, message string) error {
 	if err == nil {
@@ -153,5 +322,26 @@ func IsRetryable(err error) bool {
 		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
 	}
 	
-	return false
+	return isTransportError(err)
+}
+
+// isTransportError returns true if err is a network failure, such as a
+// reset connection or a response cut short, that a new attempt may not
+// meet. Cancellation, deadlines and rejected certificates are final.
+func isTransportError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	
+	// *url.Error, returned by http.Client for failed requests, is a net.Error
+	var netErr net.Error
+	return errors.As(err, &netErr)
 }
\ No newline at end of file
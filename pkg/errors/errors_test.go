@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiError(t *testing.T) {
+	apiErr := NewAPIError(500, "boom")
+	multi := &MultiError{Errors: []*IndexedError{
+		{Index: 1, Err: ErrNotFound},
+		{Index: 3, Err: apiErr},
+	}}
+
+	assert.Equal(t, "2 requests failed: request 1: resource not found (and 1 more)", multi.Error())
+	assert.True(t, errors.Is(multi, ErrNotFound))
+	assert.False(t, errors.Is(multi, ErrUnauthorized))
+
+	var target *APIError
+	assert.True(t, errors.As(multi, &target))
+	assert.Equal(t, 500, target.StatusCode)
+
+	assert.Nil(t, multi.ErrorAt(0))
+	assert.Equal(t, ErrNotFound, multi.ErrorAt(1))
+	assert.Equal(t, apiErr, multi.ErrorAt(3))
+}
+
+func TestMultiErrorSingle(t *testing.T) {
+	multi := &MultiError{Errors: []*IndexedError{{Index: 2, Err: ErrRequestTimeout}}}
+
+	assert.Equal(t, "request 2: request timeout", multi.Error())
+	assert.True(t, IsRetryable(multi.Errors[0]))
+}
+
+func TestRetryError(t *testing.T) {
+	apiErr := NewAPIError(503, "down")
+	err := WrapError(&RetryError{Attempts: 3, Err: apiErr}, "get user")
+
+	assert.Equal(t, "get user: upwork api error: down (status: 503) (after 3 attempts)", err.Error())
+	assert.Equal(t, 3, Attempts(err))
+	assert.True(t, IsRetryable(err))
+
+	var target *APIError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, 1, Attempts(apiErr))
+}
+
+func TestRateLimitError(t *testing.T) {
+	err := WrapError(&RateLimitError{
+		APIError:   APIError{StatusCode: 429, Message: "Too Many Requests"},
+		RetryAfter: 30 * time.Second,
+	}, "get user")
+
+	assert.Equal(t, "get user: upwork api error: Too Many Requests (status: 429), retry after 30s", err.Error())
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+	assert.True(t, IsRetryable(err))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, apiErr.IsRateLimited())
+}
+
+func TestIsRetryableTransportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"bare connection reset", syscall.ECONNRESET, true},
+		{"truncated response", WrapError(io.ErrUnexpectedEOF, "failed to read response"), true},
+		{"transport error", &url.Error{Op: "Post", URL: "https://api.upwork.com/graphql", Err: io.EOF}, true},
+		{"dial error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"cancelled", &url.Error{Op: "Post", URL: "https://api.upwork.com/graphql", Err: context.Canceled}, false},
+		{"deadline", &url.Error{Op: "Post", URL: "https://api.upwork.com/graphql", Err: context.DeadlineExceeded}, false},
+		{"certificate", &url.Error{Op: "Post", URL: "https://api.upwork.com/graphql", Err: &tls.CertificateVerificationError{Err: errors.New("unknown authority")}}, false},
+		{"other error", errors.New("invalid input"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
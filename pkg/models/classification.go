@@ -0,0 +1,19 @@
+package models
+
+// Category represents a job category
+type Category struct {
+	ID   ID     `json:"id"`
+	Name string `json:"name"`
+}
+
+// SubCategory represents a job subcategory
+type SubCategory struct {
+	ID   ID     `json:"id"`
+	Name string `json:"name"`
+}
+
+// Skill represents a skill
+type Skill struct {
+	ID         ID     `json:"id"`
+	PrettyName string `json:"prettyName"`
+}
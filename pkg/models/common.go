@@ -1,22 +1,12 @@
 // Package models contains shared data models for the Upwork SDK.
 package models
 
-import "time"
-
 // ID represents a GraphQL ID type
 type ID string
 
-// Money represents a monetary value
-type Money struct {
-	RawValue     float64 `json:"rawValue"`
-	Currency     string  `json:"currency"`
-	DisplayValue string  `json:"displayValue"`
-}
-
-// DateTime represents a date/time value
-type DateTime struct {
-	RawValue     string `json:"rawValue"`
-	DisplayValue string `json:"displayValue"`
+// Ptr returns a pointer to v, for setting optional input fields
+func Ptr[T any](v T) *T {
+	return &v
 }
 
 // PageInfo represents pagination information
@@ -43,12 +33,6 @@ const (
 	SortOrderDesc SortOrder = "DESC"
 )
 
-// DateRange represents a date range
-type DateRange struct {
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
-}
-
 // Location represents a location
 type Location struct {
 	Country     string `json:"country"`
@@ -56,4 +40,4 @@ type Location struct {
 	City        string `json:"city"`
 	Timezone    string `json:"timezone"`
 	OffsetToUTC int    `json:"offsetToUTC"`
-}
\ No newline at end of file
+}
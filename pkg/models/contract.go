@@ -0,0 +1,18 @@
+package models
+
+// ContractListOptions selects a page of contracts without building the
+// nested filter and pagination inputs. Empty fields are not filtered on.
+type ContractListOptions struct {
+	// Status is a contract status such as "ACTIVE". Case is ignored.
+	Status string
+
+	// ContractType is "HOURLY" or "FIXED_PRICE". Case is ignored and
+	// hyphens may be used for underscores.
+	ContractType string
+
+	// Limit is the page size; zero uses the API default
+	Limit int
+
+	// After is the EndCursor of the previous page
+	After string
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// DateTime represents a date/time value. The API sends these as plain
+// strings, usually RFC 3339 timestamps; RawValue keeps the string verbatim
+// and Time parses it.
+type DateTime struct {
+	RawValue     string `json:"rawValue"`
+	DisplayValue string `json:"displayValue"`
+}
+
+// NewDateTime creates a DateTime from t in the API's format, an RFC 3339
+// timestamp in UTC
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{RawValue: formatDateTime(t)}
+}
+
+// IsZero returns true if the value is unset
+func (d DateTime) IsZero() bool {
+	return d.RawValue == ""
+}
+
+// Time parses the value. RFC 3339 timestamps, dates as YYYY-MM-DD and
+// Unix timestamps in milliseconds are accepted.
+func (d DateTime) Time() (time.Time, error) {
+	return parseDateTime(d.RawValue)
+}
+
+// String returns the raw value
+func (d DateTime) String() string {
+	return d.RawValue
+}
+
+// MarshalJSON encodes the value as the plain string the API expects, or
+// null if it is unset
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.RawValue)
+}
+
+// UnmarshalJSON decodes a DateTime from a string, a number of milliseconds
+// or an object with rawValue and displayValue
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*d = DateTime{RawValue: s}
+	case '{':
+		// Decode through an alias so this method is not called recursively
+		type dateTime DateTime
+		var raw dateTime
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		*d = DateTime(raw)
+	default:
+		if _, err := strconv.ParseInt(string(data), 10, 64); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrInvalidDateTime, data)
+		}
+		*d = DateTime{RawValue: string(data)}
+	}
+	return nil
+}
+
+// DateRange represents a date range. It is sent to the API as a
+// DateTimeRange whose bounds are RFC 3339 timestamps in UTC; a zero bound
+// is left open.
+type DateRange struct {
+	Start time.Time `json:"rangeStart"`
+	End   time.Time `json:"rangeEnd"`
+}
+
+// ParseDateRange creates a DateRange from two dates or timestamps in any
+// form DateTime.Time accepts. Either may be empty to leave that bound open.
+func ParseDateRange(start, end string) (DateRange, error) {
+	var r DateRange
+	var err error
+	if start != "" {
+		if r.Start, err = parseDateTime(start); err != nil {
+			return DateRange{}, err
+		}
+	}
+	if end != "" {
+		if r.End, err = parseDateTime(end); err != nil {
+			return DateRange{}, err
+		}
+	}
+	return r, nil
+}
+
+// dateRangeJSON is the wire representation of DateRange
+type dateRangeJSON struct {
+	RangeStart string `json:"rangeStart,omitempty"`
+	RangeEnd   string `json:"rangeEnd,omitempty"`
+}
+
+// MarshalJSON encodes the range in the API's DateTimeRange shape
+func (r DateRange) MarshalJSON() ([]byte, error) {
+	var raw dateRangeJSON
+	if !r.Start.IsZero() {
+		raw.RangeStart = formatDateTime(r.Start)
+	}
+	if !r.End.IsZero() {
+		raw.RangeEnd = formatDateTime(r.End)
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes a range in the API's DateTimeRange shape. Bounds
+// may be in any form DateTime.Time accepts, and "start" and "end" are
+// accepted in place of "rangeStart" and "rangeEnd".
+func (r *DateRange) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var raw struct {
+		dateRangeJSON
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	start, end := raw.RangeStart, raw.RangeEnd
+	if start == "" {
+		start = raw.Start
+	}
+	if end == "" {
+		end = raw.End
+	}
+
+	parsed, err := ParseDateRange(start, end)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// formatDateTime formats t as an RFC 3339 timestamp in UTC
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseDateTime parses an RFC 3339 timestamp, a date as YYYY-MM-DD or Unix
+// milliseconds. Dates and timestamps without an offset are taken as UTC.
+func parseDateTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%w: empty value", errors.ErrInvalidDateTime)
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", errors.ErrInvalidDateTime, s)
+}
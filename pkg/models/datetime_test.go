@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	upworkErrors "github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTimeRecordedResponse(t *testing.T) {
+	data, err := os.ReadFile("testdata/contract_response.json")
+	require.NoError(t, err)
+
+	var contract struct {
+		CreatedDateTime  DateTime  `json:"createdDateTime"`
+		StartDateTime    DateTime  `json:"startDateTime"`
+		ModifiedDateTime DateTime  `json:"modifiedDateTime"`
+		EndDateTime      *DateTime `json:"endDateTime"`
+		DueDateTime      DateTime  `json:"dueDateTime"`
+	}
+	require.NoError(t, json.Unmarshal(data, &contract))
+
+	created, err := contract.CreatedDateTime.Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), created)
+
+	started, err := contract.StartDateTime.Time()
+	require.NoError(t, err)
+	assert.True(t, started.Equal(time.Date(2024, 1, 2, 4, 30, 0, 0, time.UTC)))
+
+	modified, err := contract.ModifiedDateTime.Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 10, 15, 0, 0, time.UTC), modified)
+
+	assert.Nil(t, contract.EndDateTime)
+
+	due, err := contract.DueDateTime.Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), due)
+	assert.Equal(t, "Feb 1, 2024", contract.DueDateTime.DisplayValue)
+}
+
+func TestDateTimeJSON(t *testing.T) {
+	d := NewDateTime(time.Date(2024, 3, 1, 7, 0, 0, 0, time.FixedZone("EST", -5*3600)))
+	assert.Equal(t, "2024-03-01T12:00:00Z", d.String())
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"2024-03-01T12:00:00Z"`, string(data))
+
+	var back DateTime
+	require.NoError(t, json.Unmarshal(data, &back))
+	assert.Equal(t, d, back)
+
+	data, err = json.Marshal(struct {
+		Due DateTime `json:"due"`
+	}{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"due":null}`, string(data))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`true`), &back), upworkErrors.ErrInvalidDateTime)
+	_, err = DateTime{RawValue: "next tuesday"}.Time()
+	assert.ErrorIs(t, err, upworkErrors.ErrInvalidDateTime)
+}
+
+func TestDateRangeRecordedVariables(t *testing.T) {
+	want, err := os.ReadFile("testdata/transaction_history_variables.json")
+	require.NoError(t, err)
+
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC).Add(24*time.Hour - time.Nanosecond)
+	got, err := json.Marshal(map[string]interface{}{
+		"aceIds_any":             []string{"ace-1"},
+		"transactionDateTime_bt": DateRange{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: end},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+
+	var variables struct {
+		DateRange DateRange `json:"transactionDateTime_bt"`
+	}
+	require.NoError(t, json.Unmarshal(want, &variables))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), variables.DateRange.Start)
+	assert.Equal(t, time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC), variables.DateRange.End)
+}
+
+func TestParseDateRange(t *testing.T) {
+	r, err := ParseDateRange("2024-01-01", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), r.Start)
+	assert.True(t, r.End.IsZero())
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rangeStart":"2024-01-01T00:00:00Z"}`, string(data))
+
+	var legacy DateRange
+	require.NoError(t, json.Unmarshal([]byte(`{"start":"2024-01-01","end":"2024-01-31T00:00:00Z"}`), &legacy))
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), legacy.End)
+
+	_, err = ParseDateRange("2024-01-01", "31/01/2024")
+	assert.ErrorIs(t, err, upworkErrors.ErrInvalidDateTime)
+}
@@ -0,0 +1,383 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// moneyScale is the number of fractional digits Money keeps internally.
+// Four digits covers every ISO 4217 minor unit with room for rounding.
+const moneyScale = 4
+
+// moneyFactor is 10^moneyScale
+const moneyFactor = 10000
+
+// Money represents a monetary value. The amount is stored as a fixed-point
+// decimal so arithmetic is exact; use the constructors to create values.
+type Money struct {
+	units        int64
+	Currency     string
+	DisplayValue string
+}
+
+// NewMoney creates a Money value from a decimal string such as "50.00"
+func NewMoney(amount string, currency string) (Money, error) {
+	units, err := parseUnits(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: units, Currency: currency}, nil
+}
+
+// MustMoney is like NewMoney but panics if the amount cannot be parsed
+func MustMoney(amount string, currency string) Money {
+	m, err := NewMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat creates a Money value from a float, rounding to the
+// internal precision
+func MoneyFromFloat(amount float64, currency string) Money {
+	return Money{units: int64(math.Round(amount * moneyFactor)), Currency: currency}
+}
+
+// MoneyFromMinorUnits creates a Money value from an amount in the currency's
+// minor unit (e.g. cents for USD)
+func MoneyFromMinorUnits(amount int64, currency string) Money {
+	return Money{units: amount * pow10(moneyScale-CurrencyDecimals(currency)), Currency: currency}
+}
+
+// Float64 returns the amount as a float
+func (m Money) Float64() float64 {
+	return float64(m.units) / moneyFactor
+}
+
+// MinorUnits returns the amount in the currency's minor unit, rounded half
+// away from zero
+func (m Money) MinorUnits() int64 {
+	return roundUnits(m.units, moneyScale-CurrencyDecimals(m.Currency))
+}
+
+// IsZero returns true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsNegative returns true if the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// Add returns m + other. Both values must share a currency.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: m.units + other.units, Currency: currency}, nil
+}
+
+// Sub returns m - other. Both values must share a currency.
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: m.units - other.units, Currency: currency}, nil
+}
+
+// Mul returns m multiplied by factor, e.g. an hourly rate times hours
+func (m Money) Mul(factor float64) Money {
+	return Money{units: int64(math.Round(float64(m.units) * factor)), Currency: m.Currency}
+}
+
+// Neg returns the negated amount
+func (m Money) Neg() Money {
+	return Money{units: -m.units, Currency: m.Currency}
+}
+
+// Equal returns true if both values have the same amount and currency
+func (m Money) Equal(other Money) bool {
+	return m.units == other.units && strings.EqualFold(m.Currency, other.Currency)
+}
+
+// Cmp compares m and other, returning -1, 0 or +1. Both values must share a
+// currency.
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.commonCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.units < other.units:
+		return -1, nil
+	case m.units > other.units:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Amount returns the amount as a decimal string using the currency's number
+// of minor digits, e.g. "50.00"
+func (m Money) Amount() string {
+	return formatUnits(m.units, CurrencyDecimals(m.Currency))
+}
+
+// String returns the amount followed by the currency code, e.g. "50.00 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount()
+	}
+	return m.Amount() + " " + m.Currency
+}
+
+// Display returns the API-provided display value when present, falling back
+// to String
+func (m Money) Display() string {
+	if m.DisplayValue != "" {
+		return m.DisplayValue
+	}
+	return m.String()
+}
+
+// commonCurrency returns the currency shared by m and other. A zero value
+// without a currency is compatible with any currency.
+func (m Money) commonCurrency(other Money) (string, error) {
+	switch {
+	case strings.EqualFold(m.Currency, other.Currency):
+		return m.Currency, nil
+	case m.Currency == "" && m.units == 0:
+		return other.Currency, nil
+	case other.Currency == "" && other.units == 0:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", errors.ErrCurrencyMismatch, m.Currency, other.Currency)
+}
+
+// moneyJSON is the wire representation of Money
+type moneyJSON struct {
+	RawValue     json.RawMessage `json:"rawValue"`
+	Amount       json.RawMessage `json:"amount"`
+	Currency     string          `json:"currency"`
+	DisplayValue string          `json:"displayValue"`
+}
+
+// MarshalJSON encodes Money in the API's shape with a decimal string amount
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RawValue     string `json:"rawValue"`
+		Currency     string `json:"currency,omitempty"`
+		DisplayValue string `json:"displayValue,omitempty"`
+	}{
+		RawValue:     formatUnits(m.units, trimmedScale(m.units)),
+		Currency:     m.Currency,
+		DisplayValue: m.DisplayValue,
+	})
+}
+
+// UnmarshalJSON decodes Money from an object, a decimal string or a number.
+// The API is inconsistent about amounts, so "50.00" and 50 are both accepted.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if data[0] != '{' {
+		units, err := decodeUnits(data)
+		if err != nil {
+			return err
+		}
+		*m = Money{units: units}
+		return nil
+	}
+
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	value := raw.RawValue
+	if len(value) == 0 {
+		value = raw.Amount
+	}
+
+	units, err := decodeUnits(value)
+	if err != nil {
+		return err
+	}
+
+	*m = Money{units: units, Currency: raw.Currency, DisplayValue: raw.DisplayValue}
+	return nil
+}
+
+// decodeUnits parses a JSON string or number into fixed-point units
+func decodeUnits(data json.RawMessage) (int64, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return 0, nil
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, err
+		}
+		if strings.TrimSpace(s) == "" {
+			return 0, nil
+		}
+		return parseUnits(s)
+	}
+
+	return parseUnits(string(data))
+}
+
+// CurrencyDecimals returns the number of minor-unit digits for an ISO 4217
+// currency code. Unknown currencies default to two.
+func CurrencyDecimals(currency string) int {
+	switch strings.ToUpper(currency) {
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG",
+		"RWF", "UGX", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3
+	default:
+		return 2
+	}
+}
+
+// parseUnits parses a decimal string into fixed-point units. Thousands
+// separators and surrounding whitespace are ignored.
+func parseUnits(s string) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty amount", errors.ErrInvalidAmount)
+	}
+
+	// Exponent notation is rare; defer to float parsing for it
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", errors.ErrInvalidAmount, s)
+		}
+		return int64(math.Round(f * moneyFactor)), nil
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("%w: %q", errors.ErrInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", errors.ErrInvalidAmount, s)
+	}
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/moneyFactor-1 {
+		return 0, fmt.Errorf("%w: %q", errors.ErrInvalidAmount, s)
+	}
+
+	var f int64
+	var roundUp bool
+	if frac != "" {
+		if len(frac) > moneyScale {
+			roundUp = frac[moneyScale] >= '5'
+			frac = frac[:moneyScale]
+		}
+		frac += strings.Repeat("0", moneyScale-len(frac))
+		f, _ = strconv.ParseInt(frac, 10, 64)
+	}
+
+	units := w*moneyFactor + f
+	if roundUp {
+		units++
+	}
+	if negative {
+		units = -units
+	}
+	return units, nil
+}
+
+// isDigits reports whether s consists only of ASCII digits
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatUnits renders fixed-point units with the given number of decimals
+func formatUnits(units int64, decimals int) string {
+	rounded := roundUnits(units, moneyScale-decimals)
+
+	sign := ""
+	if rounded < 0 {
+		sign = "-"
+		rounded = -rounded
+	}
+
+	if decimals == 0 {
+		return sign + strconv.FormatInt(rounded, 10)
+	}
+
+	div := pow10(decimals)
+	return fmt.Sprintf("%s%d.%0*d", sign, rounded/div, decimals, rounded%div)
+}
+
+// roundUnits divides units by 10^digits, rounding half away from zero
+func roundUnits(units int64, digits int) int64 {
+	if digits <= 0 {
+		return units
+	}
+	div := pow10(digits)
+	q, r := units/div, units%div
+	if r*2 >= div {
+		q++
+	} else if r*2 <= -div {
+		q--
+	}
+	return q
+}
+
+// trimmedScale returns the fewest decimals (at least two) that represent
+// units without loss
+func trimmedScale(units int64) int {
+	decimals := moneyScale
+	for decimals > 2 && units%10 == 0 {
+		units /= 10
+		decimals--
+	}
+	return decimals
+}
+
+// pow10 returns 10^n for small non-negative n
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	upworkErrors "github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     string
+		wantErr  bool
+	}{
+		{name: "two decimals", amount: "50.00", currency: "USD", want: "50.00 USD"},
+		{name: "no decimals", amount: "50", currency: "USD", want: "50.00 USD"},
+		{name: "negative", amount: "-12.5", currency: "EUR", want: "-12.50 EUR"},
+		{name: "thousands separator", amount: "1,250.75", currency: "USD", want: "1250.75 USD"},
+		{name: "zero decimal currency", amount: "1500", currency: "JPY", want: "1500 JPY"},
+		{name: "three decimal currency", amount: "1.2345", currency: "KWD", want: "1.235 KWD"},
+		{name: "rounds beyond precision", amount: "0.00005", currency: "USD", want: "0.00 USD"},
+		{name: "empty", amount: "", currency: "USD", wantErr: true},
+		{name: "garbage", amount: "12.3x", currency: "USD", wantErr: true},
+		{name: "double sign", amount: "--5", currency: "USD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, upworkErrors.ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.String())
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := MustMoney("10.10", "USD")
+	b := MustMoney("0.20", "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "10.30", sum.Amount())
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, "9.90", diff.Amount())
+
+	assert.Equal(t, "252.50", a.Mul(25).Amount())
+	assert.Equal(t, "-10.10", a.Neg().Amount())
+	assert.Equal(t, int64(1010), a.MinorUnits())
+
+	// Zero values without a currency adopt the other currency
+	total, err := Money{}.Add(a)
+	require.NoError(t, err)
+	assert.True(t, total.Equal(a))
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd := MustMoney("1.00", "USD")
+	eur := MustMoney("1.00", "EUR")
+
+	_, err := usd.Add(eur)
+	assert.ErrorIs(t, err, upworkErrors.ErrCurrencyMismatch)
+
+	_, err = usd.Sub(eur)
+	assert.ErrorIs(t, err, upworkErrors.ErrCurrencyMismatch)
+
+	_, err = usd.Cmp(eur)
+	assert.ErrorIs(t, err, upworkErrors.ErrCurrencyMismatch)
+
+	assert.False(t, usd.Equal(eur))
+}
+
+func TestMoneyCmp(t *testing.T) {
+	small := MustMoney("1.99", "USD")
+	large := MustMoney("2.00", "USD")
+
+	c, err := small.Cmp(large)
+	require.NoError(t, err)
+	assert.Equal(t, -1, c)
+
+	c, err = large.Cmp(small)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c)
+
+	c, err = small.Cmp(MustMoney("1.990", "usd"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, c)
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantAmount  string
+		wantCurr    string
+		wantDisplay string
+	}{
+		{
+			name:        "string raw value",
+			payload:     `{"rawValue":"50.00","currency":"USD","displayValue":"$50.00"}`,
+			wantAmount:  "50.00",
+			wantCurr:    "USD",
+			wantDisplay: "$50.00",
+		},
+		{
+			name:       "numeric raw value",
+			payload:    `{"rawValue":50.5,"currency":"USD"}`,
+			wantAmount: "50.50",
+			wantCurr:   "USD",
+		},
+		{
+			name:       "amount key",
+			payload:    `{"amount":"75.00","currency":"USD"}`,
+			wantAmount: "75.00",
+			wantCurr:   "USD",
+		},
+		{
+			name:       "bare string",
+			payload:    `"12.34"`,
+			wantAmount: "12.34",
+		},
+		{
+			name:       "bare number",
+			payload:    `99`,
+			wantAmount: "99.00",
+		},
+		{
+			name:       "null",
+			payload:    `null`,
+			wantAmount: "0.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			require.NoError(t, json.Unmarshal([]byte(tt.payload), &m))
+			assert.Equal(t, tt.wantAmount, m.Amount())
+			assert.Equal(t, tt.wantCurr, m.Currency)
+			assert.Equal(t, tt.wantDisplay, m.DisplayValue)
+		})
+	}
+
+	var m Money
+	assert.Error(t, json.Unmarshal([]byte(`{"rawValue":"abc"}`), &m))
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	original := MustMoney("1234.5678", "USD")
+	original.DisplayValue = "$1,234.57"
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rawValue":"1234.5678","currency":"USD","displayValue":"$1,234.57"}`, string(data))
+
+	var decoded Money
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, original.Equal(decoded))
+	assert.Equal(t, original.DisplayValue, decoded.DisplayValue)
+}
@@ -0,0 +1,62 @@
+package models
+
+// User represents a user in the Upwork system
+type User struct {
+	ID        ID       `json:"id"`
+	Nid       string   `json:"nid"`
+	Rid       string   `json:"rid"`
+	Name      string   `json:"name"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	Email     string   `json:"email"`
+	PhotoURL  string   `json:"photoUrl"`
+	PublicURL string   `json:"publicUrl"`
+	Location  Location `json:"location"`
+}
+
+// FullName returns the user's display name, building it from the first and
+// last name when the API omits it
+func (u User) FullName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	if u.FirstName == "" {
+		return u.LastName
+	}
+	if u.LastName == "" {
+		return u.FirstName
+	}
+	return u.FirstName + " " + u.LastName
+}
+
+// Company represents a company
+type Company struct {
+	ID          ID     `json:"id"`
+	Name        string `json:"name"`
+	CompanyName string `json:"companyName"`
+}
+
+// Organization represents an organization
+type Organization struct {
+	ID                 ID             `json:"id"`
+	LegacyID           string         `json:"legacyId"`
+	Name               string         `json:"name"`
+	Company            Company        `json:"company"`
+	ChildOrganizations []Organization `json:"childOrganizations"`
+	ParentOrganization *Organization  `json:"parentOrganization"`
+	Staff              []Staff        `json:"staff"`
+}
+
+// Staff represents a staff member
+type Staff struct {
+	User             User   `json:"user"`
+	StaffType        string `json:"staffType"`
+	ActivationStatus string `json:"activationStatus"`
+}
+
+// Team represents a team
+type Team struct {
+	ID   ID     `json:"id"`
+	Rid  string `json:"rid"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,215 @@
+package upwork
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/codec"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// Option configures optional client behavior
+type Option func(*clientOptions)
+
+// clientOptions holds the resolved options for a client
+type clientOptions struct {
+	refreshLeeway      time.Duration
+	transport          http.RoundTripper
+	proxy              func(*http.Request) (*url.URL, error)
+	tlsConfig          *tls.Config
+	pool               *ConnectionPoolOptions
+	guardrails         services.Guardrails
+	onRequest          func(services.RequestEvent)
+	logger             *slog.Logger
+	redactFields       []string
+	validate           bool
+	onNull             func(services.UnexpectedNull)
+	codec              codec.Codec
+	onDeprecation      func(services.DeprecationWarning)
+	strictDeprecations bool
+}
+
+// WithAutoRefresh renews the token in the background leeway before it
+// expires, so the first request after a long idle period does not wait for
+// a refresh. A leeway of zero or less uses DefaultRefreshLeeway. Tokens
+// without a refresh token cannot be renewed and are used as is. Renewal
+// stops when the context passed to NewClient is done.
+func WithAutoRefresh(leeway time.Duration) Option {
+	return func(o *clientOptions) {
+		if leeway <= 0 {
+			leeway = DefaultRefreshLeeway
+		}
+		o.refreshLeeway = leeway
+	}
+}
+
+// WithTransport sends requests, including token requests, through rt
+// instead of the HTTP client's transport. OAuth2 authentication is added
+// on top of rt.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// WithProxy sends requests, including token requests, through the proxy at
+// proxyURL instead of the one configured in the environment. A nil proxyURL
+// disables proxying. The transport must be an *http.Transport.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(o *clientOptions) {
+		o.proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithTLSConfig uses cfg for TLS connections, e.g. to trust a custom CA
+// bundle through RootCAs or to raise MinVersion. The transport must be an
+// *http.Transport.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithConnectionPool tunes connection reuse, e.g. to raise
+// MaxIdleConnsPerHost for high-QPS services or cap MaxConnsPerHost. The
+// transport must be an *http.Transport.
+func WithConnectionPool(opts ConnectionPoolOptions) Option {
+	return func(o *clientOptions) {
+		o.pool = &opts
+	}
+}
+
+// WithGuardrails limits the size of responses and reports queries that
+// select or return more data than expected, protecting the memory of
+// long-running services
+func WithGuardrails(g services.Guardrails) Option {
+	return func(o *clientOptions) {
+		o.guardrails = g
+	}
+}
+
+// WithRequestHook calls fn when each request completes with its operation
+// name, duration and error, e.g. to record metrics per operation. fn may be
+// called concurrently.
+func WithRequestHook(fn func(services.RequestEvent)) Option {
+	return func(o *clientOptions) {
+		o.onRequest = fn
+	}
+}
+
+// WithLogger logs each request at debug level with its operation name,
+// variables and duration. Values of sensitive variables, such as message
+// bodies, emails and tokens, are redacted; see WithRedactFields.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRedactFields sets the variable fields whose values are redacted from
+// request logs, replacing services.DefaultRedactFields. Fields match
+// case-insensitively as substrings of field names at any depth. Extend the
+// defaults with append(services.DefaultRedactFields, ...).
+func WithRedactFields(fields ...string) Option {
+	return func(o *clientOptions) {
+		o.redactFields = append([]string{}, fields...)
+	}
+}
+
+// WithResponseValidation checks each response, in development, for nulls
+// decoded into result fields that cannot hold one and would silently be
+// left as zero values, calling fn with the path of each. If fn is nil,
+// they are logged as warnings with the WithLogger logger, or slog.Default.
+func WithResponseValidation(fn func(services.UnexpectedNull)) Option {
+	return func(o *clientOptions) {
+		o.validate = true
+		o.onNull = fn
+	}
+}
+
+// WithCodec sets the JSON codec requests are encoded and responses decoded
+// with, e.g. a faster one for large reports. The default is codec.Default:
+// encoding/json, or github.com/goccy/go-json when built with the gojson
+// tag.
+func WithCodec(c codec.Codec) Option {
+	return func(o *clientOptions) {
+		o.codec = c
+	}
+}
+
+// WithDeprecationHandler calls fn for each deprecated field a request
+// used, as reported by the API, with the name of the operation. Without
+// it, deprecations are only logged, once each, by the WithLogger logger.
+func WithDeprecationHandler(fn func(services.DeprecationWarning)) Option {
+	return func(o *clientOptions) {
+		o.onDeprecation = fn
+	}
+}
+
+// WithStrictDeprecations fails requests that use deprecated fields with an
+// *errors.DeprecationError, matching errors.ErrDeprecatedField, so tests
+// catch them before the fields are removed. Results are still decoded.
+func WithStrictDeprecations() Option {
+	return func(o *clientOptions) {
+		o.strictDeprecations = true
+	}
+}
+
+// unexpectedNullHandler returns the handler for unexpected nulls in
+// responses, or nil if responses are not validated
+func (o *clientOptions) unexpectedNullHandler() func(services.UnexpectedNull) {
+	if !o.validate || o.onNull != nil {
+		return o.onNull
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(n services.UnexpectedNull) {
+		logger.Warn("unexpected null in response", "operation", n.OperationName, "path", n.Path, "type", n.Type)
+	}
+}
+
+// httpClient returns base with the transport options applied. base is
+// copied rather than modified.
+func (o *clientOptions) httpClient(base *http.Client) (*http.Client, error) {
+	if o.transport == nil && o.proxy == nil && o.tlsConfig == nil && o.pool == nil {
+		return base, nil
+	}
+
+	transport := o.transport
+	if transport == nil {
+		transport = base.Transport
+	}
+	if transport == nil {
+		transport = NewTransport()
+	}
+
+	if o.proxy != nil || o.tlsConfig != nil || o.pool != nil {
+		t, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("WithProxy, WithTLSConfig and WithConnectionPool require an *http.Transport, got %T", transport)
+		}
+
+		t = t.Clone()
+		if o.proxy != nil {
+			t.Proxy = o.proxy
+		}
+		if o.tlsConfig != nil {
+			t.TLSClientConfig = o.tlsConfig.Clone()
+		}
+		if o.pool != nil {
+			o.pool.apply(t)
+		}
+		transport = t
+	}
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
@@ -0,0 +1,136 @@
+package upwork
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Pool manages the Clients of many tenants, such as the Upwork customers
+// of a SaaS platform, keyed by a tenant key of the caller's choosing. Each
+// tenant has its own Client with its own credentials, token, rate limiter
+// and services; nothing is shared between tenants unless the caller shares
+// it, e.g. by passing the same Config.HTTPClient. Tokens are renewed in the
+// background per tenant. A Pool is safe for concurrent use.
+type Pool struct {
+	ctx  context.Context
+	opts []Option
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+	closed  bool
+}
+
+// NewPool creates an empty pool. Background token renewal for its clients
+// runs until ctx is done or the pool is closed. opts apply to every client,
+// after WithAutoRefresh(DefaultRefreshLeeway) and before the options
+// passed to Add.
+func NewPool(ctx context.Context, opts ...Option) *Pool {
+	return &Pool{
+		ctx:     ctx,
+		opts:    opts,
+		clients: make(map[string]*Client),
+	}
+}
+
+// Add creates a client for tenant from config. config is copied, so it
+// may be reused as a template for other tenants. Add returns
+// errors.ErrTenantExists if the tenant already has a client.
+func (p *Pool) Add(tenant string, config *Config, opts ...Option) (*Client, error) {
+	if p.has(tenant) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrTenantExists, tenant)
+	}
+
+	// Create the client outside the lock, since service accounts fetch a
+	// token first
+	tenantConfig := *config
+	clientOpts := append([]Option{WithAutoRefresh(DefaultRefreshLeeway)}, p.opts...)
+	client, err := NewClient(p.ctx, &tenantConfig, append(clientOpts, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		client.Close()
+		return nil, errors.ErrClientClosed
+	}
+	if _, ok := p.clients[tenant]; ok {
+		client.Close()
+		return nil, fmt.Errorf("%w: %s", errors.ErrTenantExists, tenant)
+	}
+	p.clients[tenant] = client
+
+	return client, nil
+}
+
+// Client returns the client of tenant, or errors.ErrUnknownTenant if it
+// has none
+func (p *Pool) Client(tenant string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil, errors.ErrClientClosed
+	}
+	client, ok := p.clients[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownTenant, tenant)
+	}
+	return client, nil
+}
+
+// Remove closes the client of tenant and removes it from the pool
+func (p *Pool) Remove(tenant string) error {
+	p.mu.Lock()
+	client, ok := p.clients[tenant]
+	delete(p.clients, tenant)
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", errors.ErrUnknownTenant, tenant)
+	}
+	return client.Close()
+}
+
+// Tenants returns the tenants in the pool, sorted
+func (p *Pool) Tenants() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tenants := make([]string, 0, len(p.clients))
+	for tenant := range p.clients {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// Close closes every client in the pool. Add and Client fail with
+// errors.ErrClientClosed afterwards. Close is safe to call more than once.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = make(map[string]*Client)
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, client := range clients {
+		client.Close()
+	}
+	return nil
+}
+
+// has returns true if tenant has a client
+func (p *Pool) has(tenant string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.clients[tenant]
+	return ok
+}
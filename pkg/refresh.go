@@ -0,0 +1,158 @@
+package upwork
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultRefreshLeeway is how long before expiry a token is renewed
+const DefaultRefreshLeeway = 5 * time.Minute
+
+// refreshRetryInterval is how long the refresher waits after a failed
+// renewal before trying again
+const refreshRetryInterval = 30 * time.Second
+
+// renewingTokenSource caches a token and renews it from src shortly before
+// it expires rather than when it has expired. The leeway is capped at half
+// the token's lifetime so short-lived tokens are not renewed on every call.
+type renewingTokenSource struct {
+	src    oauth2.TokenSource
+	leeway time.Duration
+
+	mu      sync.Mutex
+	token   *oauth2.Token
+	renewAt time.Time
+}
+
+// newRenewingTokenSource returns a source that starts with token, which may
+// be nil
+func newRenewingTokenSource(token *oauth2.Token, src oauth2.TokenSource, leeway time.Duration) *renewingTokenSource {
+	s := &renewingTokenSource{src: src, leeway: leeway}
+	if token != nil {
+		s.setToken(token)
+	}
+	return s
+}
+
+// Token returns the cached token, renewing it if it is due. If renewal
+// fails while the cached token is still valid, the cached token is
+// returned and renewal is retried on a later call.
+func (s *renewingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && (s.token.Expiry.IsZero() || time.Now().Before(s.renewAt)) {
+		return s.token, nil
+	}
+
+	token, err := s.src.Token()
+	if err != nil {
+		if s.token != nil && s.token.Valid() {
+			return s.token, nil
+		}
+		return nil, err
+	}
+
+	s.setToken(token)
+	return token, nil
+}
+
+// renew obtains a new token from src regardless of the cached token's
+// expiry
+func (s *renewingTokenSource) renew() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.setToken(token)
+	return token, nil
+}
+
+// setToken caches token and schedules its renewal. s.mu must be held or s
+// not yet shared.
+func (s *renewingTokenSource) setToken(token *oauth2.Token) {
+	s.token = token
+	if token.Expiry.IsZero() {
+		s.renewAt = time.Time{}
+		return
+	}
+
+	leeway := s.leeway
+	if half := time.Until(token.Expiry) / 2; half < leeway {
+		leeway = half
+	}
+	s.renewAt = token.Expiry.Add(-leeway)
+}
+
+// next returns the current token and when it is due for renewal. The time
+// is zero if the token never expires.
+func (s *renewingTokenSource) next() (*oauth2.Token, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil || s.token.Expiry.IsZero() {
+		return s.token, time.Time{}
+	}
+	return s.token, s.renewAt
+}
+
+// refreshTokenSource obtains a new token with the refresh token grant on
+// every call, keeping the latest refresh token if the server rotates it.
+// Calls must be serialized, as renewingTokenSource does.
+type refreshTokenSource struct {
+	ctx          context.Context
+	config       *oauth2.Config
+	refreshToken string
+}
+
+// Token refreshes the token
+func (s *refreshTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.config.TokenSource(s.ctx, &oauth2.Token{RefreshToken: s.refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	return token, nil
+}
+
+// refreshLoop renews the token in src when it falls due, so requests after
+// a long idle period do not wait for a renewal, until ctx is done. onToken
+// is called with each new token.
+func refreshLoop(ctx context.Context, src *renewingTokenSource, onToken func(*oauth2.Token)) {
+	var last *oauth2.Token
+	for {
+		wait := refreshRetryInterval
+
+		if token, err := src.Token(); err == nil {
+			if token != last {
+				onToken(token)
+				last = token
+			}
+
+			_, renewAt := src.next()
+			if renewAt.IsZero() {
+				// Tokens without an expiry never need renewing
+				return
+			}
+			if until := time.Until(renewAt); until > 0 {
+				wait = until
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package reports
+
+import (
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// kind is the type of a column's values
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+)
+
+// column is an exported column of rows of T
+type column[T any] struct {
+	name string
+	kind kind
+	// value returns the value of the column in a row: a string, int64 or
+	// float64 as kind says
+	value func(T) interface{}
+}
+
+func stringColumn[T any](name string, value func(T) string) column[T] {
+	return column[T]{name: name, kind: kindString, value: func(row T) interface{} { return value(row) }}
+}
+
+func intColumn[T any](name string, value func(T) int) column[T] {
+	return column[T]{name: name, kind: kindInt, value: func(row T) interface{} { return int64(value(row)) }}
+}
+
+func floatColumn[T any](name string, value func(T) float64) column[T] {
+	return column[T]{name: name, kind: kindFloat, value: func(row T) interface{} { return value(row) }}
+}
+
+// moneyColumns returns a column of the amount, kept exact as a decimal
+// string, and one of its currency
+func moneyColumns[T any](name string, value func(T) models.Money) []column[T] {
+	return []column[T]{
+		stringColumn(name, func(row T) string { return value(row).Amount() }),
+		stringColumn(name+"_currency", func(row T) string { return value(row).Currency }),
+	}
+}
+
+// schema returns the columns of T. The order is part of the export format:
+// add new columns at the end.
+func schema[T Row]() []column[T] {
+	var row T
+	switch any(row).(type) {
+	case services.TransactionHistoryRow:
+		return any(transactionHistoryColumns).([]column[T])
+	case services.TimeReport:
+		return any(timeReportColumns).([]column[T])
+	default:
+		return any(workDiaryColumns).([]column[T])
+	}
+}
+
+type transactionRow = services.TransactionHistoryRow
+
+var transactionHistoryColumns = concat(
+	[]column[transactionRow]{
+		intColumn("row_number", func(r transactionRow) int { return r.RowNumber }),
+		stringColumn("record_id", func(r transactionRow) string { return r.RecordID }),
+		stringColumn("type", func(r transactionRow) string { return r.Type }),
+		stringColumn("accounting_subtype", func(r transactionRow) string { return r.AccountingSubtype }),
+		stringColumn("description", func(r transactionRow) string { return r.Description }),
+		stringColumn("transaction_creation_date", func(r transactionRow) string { return r.TransactionCreationDate.String() }),
+		stringColumn("transaction_review_due_date", func(r transactionRow) string { return r.TransactionReviewDueDate.String() }),
+	},
+	moneyColumns("transaction_amount", func(r transactionRow) models.Money { return r.TransactionAmount }),
+	moneyColumns("amount_credited_to_user", func(r transactionRow) models.Money { return r.AmountCreditedToUser }),
+	moneyColumns("payment", func(r transactionRow) models.Money { return r.Payment }),
+	[]column[transactionRow]{
+		stringColumn("payment_status", func(r transactionRow) string { return r.PaymentStatus }),
+		stringColumn("related_assignment", func(r transactionRow) string { return r.RelatedAssignment }),
+		stringColumn("related_accounting_entity", func(r transactionRow) string { return r.RelatedAccountingEntity }),
+		stringColumn("related_transaction_id", func(r transactionRow) string { return r.RelatedTransactionID }),
+		stringColumn("related_invoice_id", func(r transactionRow) string { return r.RelatedInvoiceID }),
+		stringColumn("purchase_order_number", func(r transactionRow) string { return r.PurchaseOrderNumber }),
+		stringColumn("assignment_team_company_id", func(r transactionRow) string { return r.AssignmentTeamCompanyID }),
+		stringColumn("assignment_team_company_reference", func(r transactionRow) string { return r.AssignmentTeamCompanyReference }),
+		stringColumn("assignment_company_name", func(r transactionRow) string { return r.AssignmentCompanyName }),
+		stringColumn("assignment_developer_name", func(r transactionRow) string { return r.AssignmentDeveloperName }),
+		stringColumn("assignment_team_user_id", func(r transactionRow) string { return r.AssignmentTeamUserID }),
+		stringColumn("assignment_team_user_reference", func(r transactionRow) string { return r.AssignmentTeamUserReference }),
+	},
+)
+
+type timeReport = services.TimeReport
+
+var timeReportColumns = concat(
+	[]column[timeReport]{
+		stringColumn("date_worked_on", func(r timeReport) string { return r.DateWorkedOn.String() }),
+		stringColumn("week_worked_on", func(r timeReport) string { return r.WeekWorkedOn.String() }),
+		intColumn("month_worked_on", func(r timeReport) int { return r.MonthWorkedOn }),
+		intColumn("year_worked_on", func(r timeReport) int { return r.YearWorkedOn }),
+		stringColumn("freelancer_id", func(r timeReport) string { return string(r.Freelancer.ID) }),
+		stringColumn("freelancer_name", func(r timeReport) string { return r.Freelancer.Name }),
+		stringColumn("team_id", func(r timeReport) string { return string(r.Team.ID) }),
+		stringColumn("team_name", func(r timeReport) string { return r.Team.Name }),
+		stringColumn("contract_id", func(r timeReport) string { return string(r.Contract.ID) }),
+		stringColumn("contract_title", func(r timeReport) string { return r.Contract.Title }),
+		stringColumn("task", func(r timeReport) string { return r.Task }),
+		stringColumn("task_description", func(r timeReport) string { return r.TaskDescription }),
+		stringColumn("memo", func(r timeReport) string { return r.Memo }),
+		floatColumn("total_hours_worked", func(r timeReport) float64 { return r.TotalHoursWorked }),
+	},
+	moneyColumns("total_charges", func(r timeReport) models.Money { return r.TotalCharges }),
+	[]column[timeReport]{
+		floatColumn("total_online_hours_worked", func(r timeReport) float64 { return r.TotalOnlineHoursWorked }),
+	},
+	moneyColumns("total_online_charge", func(r timeReport) models.Money { return r.TotalOnlineCharge }),
+	[]column[timeReport]{
+		floatColumn("total_offline_hours_worked", func(r timeReport) float64 { return r.TotalOfflineHoursWorked }),
+	},
+	moneyColumns("total_offline_charge", func(r timeReport) models.Money { return r.TotalOfflineCharge }),
+)
+
+type diarySnapshot = services.WorkDiarySnapshot
+
+var workDiaryColumns = []column[diarySnapshot]{
+	stringColumn("contract_id", func(s diarySnapshot) string { return s.Contract.ID }),
+	stringColumn("contract_title", func(s diarySnapshot) string { return s.Contract.ContractTitle }),
+	stringColumn("user_id", func(s diarySnapshot) string { return diaryUserID(s) }),
+	stringColumn("user_name", func(s diarySnapshot) string { return s.User.Name }),
+	stringColumn("duration", func(s diarySnapshot) string { return s.Duration }),
+	intColumn("duration_minutes", func(s diarySnapshot) int { return s.DurationInt }),
+	stringColumn("task_id", func(s diarySnapshot) string { return s.Task.ID }),
+	stringColumn("task_code", func(s diarySnapshot) string { return s.Task.Code }),
+	stringColumn("task_description", func(s diarySnapshot) string { return s.Task.Description }),
+	stringColumn("memo", func(s diarySnapshot) string { return s.Task.Memo }),
+	stringColumn("tracked_time", func(s diarySnapshot) string { return s.Time.TrackedTime }),
+	stringColumn("manual_time", func(s diarySnapshot) string { return s.Time.ManualTime }),
+	stringColumn("overtime", func(s diarySnapshot) string { return s.Time.Overtime }),
+	stringColumn("first_worked", func(s diarySnapshot) string { return s.Time.FirstWorked }),
+	stringColumn("last_worked", func(s diarySnapshot) string { return s.Time.LastWorked }),
+	intColumn("first_worked_unix", func(s diarySnapshot) int { return s.Time.FirstWorkedInt }),
+	intColumn("last_worked_unix", func(s diarySnapshot) int { return s.Time.LastWorkedInt }),
+	intColumn("screenshots", func(s diarySnapshot) int { return len(s.Screenshots) }),
+	floatColumn("activity_percent", activityPercent),
+}
+
+// diaryUserID returns the ID of the snapshot's freelancer
+func diaryUserID(s diarySnapshot) string {
+	if s.Contract.UserID != "" {
+		return s.Contract.UserID
+	}
+	return string(s.User.ID)
+}
+
+// activityPercent returns the mean activity of the snapshot's screenshots,
+// each the active minutes of a 10 minute segment, as a percentage
+func activityPercent(s diarySnapshot) float64 {
+	if len(s.Screenshots) == 0 {
+		return 0
+	}
+	var sum int
+	for _, shot := range s.Screenshots {
+		sum += min(max(shot.Activity, 0), 10)
+	}
+	return float64(sum) * 10 / float64(len(s.Screenshots))
+}
+
+// concat joins groups of columns
+func concat[T any](groups ...[]column[T]) []column[T] {
+	var columns []column[T]
+	for _, g := range groups {
+		columns = append(columns, g...)
+	}
+	return columns
+}
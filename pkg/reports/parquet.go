@@ -0,0 +1,255 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// parquetMagic starts and ends a Parquet file
+const parquetMagic = "PAR1"
+
+// Parquet physical types, encodings and the like, as numbered by the
+// format's Thrift definitions
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetUTF8     = 0 // converted type
+	parquetPlain    = 0 // encoding
+	parquetRLE      = 3 // encoding
+	parquetDataPage = 0 // page type
+)
+
+// ExportParquet writes rows to w as a Parquet file with one row group.
+// Columns are required and uncompressed; strings are UTF-8 byte arrays,
+// whole numbers 64-bit integers and the rest doubles. Amounts are exported
+// as decimal strings to keep them exact. WithHeader names the columns in
+// the schema.
+func ExportParquet[T Row](w io.Writer, rows []T, opts ...Option) error {
+	o, columns, err := resolve[T](opts)
+	if err != nil {
+		return err
+	}
+
+	file := bytes.NewBufferString(parquetMagic)
+	chunks := make([]parquetChunk, len(columns))
+	for i, c := range columns {
+		page := encodePlain(c, rows)
+
+		var header thriftWriter
+		header.structBegin()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(page)))
+		header.i32Field(3, int32(len(page)))
+		header.fieldBegin(5, thriftStruct)
+		header.structBegin()
+		header.i32Field(1, int32(len(rows)))
+		header.i32Field(2, parquetPlain)
+		header.i32Field(3, parquetRLE)
+		header.i32Field(4, parquetRLE)
+		header.structEnd()
+		header.structEnd()
+
+		chunks[i] = parquetChunk{
+			name:   o.header(c.name),
+			kind:   c.kind,
+			offset: int64(file.Len()),
+			size:   int64(header.buf.Len() + len(page)),
+		}
+		file.Write(header.buf.Bytes())
+		file.Write(page)
+	}
+
+	footer := parquetFooter(chunks, int64(len(rows)))
+	file.Write(footer)
+	binary.Write(file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(parquetMagic)
+
+	_, err = file.WriteTo(w)
+	return err
+}
+
+// parquetChunk is a written column chunk
+type parquetChunk struct {
+	name   string
+	kind   kind
+	offset int64
+	size   int64
+}
+
+// encodePlain returns the values of column c in rows, PLAIN encoded
+func encodePlain[T Row](c column[T], rows []T) []byte {
+	var b bytes.Buffer
+	var scratch [8]byte
+	for _, row := range rows {
+		switch v := c.value(row).(type) {
+		case string:
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(len(v)))
+			b.Write(scratch[:4])
+			b.WriteString(v)
+		case int64:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+			b.Write(scratch[:])
+		case float64:
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v))
+			b.Write(scratch[:])
+		}
+	}
+	return b.Bytes()
+}
+
+// parquetType returns the physical type of a kind
+func parquetType(k kind) int32 {
+	switch k {
+	case kindInt:
+		return parquetInt64
+	case kindFloat:
+		return parquetDouble
+	default:
+		return parquetByteArray
+	}
+}
+
+// parquetFooter returns the FileMetaData of a file of chunks
+func parquetFooter(chunks []parquetChunk, rows int64) []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.i32Field(1, 1) // version
+
+	// The schema is a root with a field per column
+	t.listField(2, thriftStruct, len(chunks)+1)
+	t.structBegin()
+	t.binaryField(4, "schema")
+	t.i32Field(5, int32(len(chunks)))
+	t.structEnd()
+	for _, c := range chunks {
+		t.structBegin()
+		t.i32Field(1, parquetType(c.kind))
+		t.i32Field(3, parquetRequired)
+		t.binaryField(4, c.name)
+		if c.kind == kindString {
+			t.i32Field(6, parquetUTF8)
+		}
+		t.structEnd()
+	}
+
+	t.i64Field(3, rows)
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	t.listField(4, thriftStruct, 1)
+	t.structBegin()
+	t.listField(1, thriftStruct, len(chunks))
+	for _, c := range chunks {
+		t.structBegin()
+		t.i64Field(2, c.offset)
+		t.fieldBegin(3, thriftStruct)
+		t.structBegin()
+		t.i32Field(1, parquetType(c.kind))
+		t.listField(2, thriftI32, 2)
+		t.varint(parquetPlain)
+		t.varint(parquetRLE)
+		t.listField(3, thriftBinary, 1)
+		t.binary(c.name)
+		t.i32Field(4, 0) // uncompressed
+		t.i64Field(5, rows)
+		t.i64Field(6, c.size)
+		t.i64Field(7, c.size)
+		t.i64Field(9, c.offset)
+		t.structEnd()
+		t.structEnd()
+	}
+	t.i64Field(2, total)
+	t.i64Field(3, rows)
+	t.structEnd()
+
+	t.binaryField(6, "github.com/rizome-dev/go-upwork")
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter writes the Thrift compact protocol, which Parquet uses for
+// its page headers and footer
+type thriftWriter struct {
+	buf bytes.Buffer
+	// last holds the ID of the last field written in each open struct
+	last []int16
+}
+
+func (t *thriftWriter) structBegin() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0) // stop
+	t.last = t.last[:len(t.last)-1]
+}
+
+// fieldBegin writes a field header, with the ID as a delta from the last
+// field's where it fits
+func (t *thriftWriter) fieldBegin(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldBegin(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldBegin(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binaryField(id int16, v string) {
+	t.fieldBegin(id, thriftBinary)
+	t.binary(v)
+}
+
+// listField writes the header of a list field of n elements of typ
+func (t *thriftWriter) listField(id int16, typ byte, n int) {
+	t.fieldBegin(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | typ)
+		return
+	}
+	t.buf.WriteByte(0xf0 | typ)
+	t.uvarint(uint64(n))
+}
+
+func (t *thriftWriter) binary(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+// varint writes a zigzag encoded integer
+func (t *thriftWriter) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
@@ -0,0 +1,171 @@
+// Package reports exports report rows, such as transaction history, time
+// reports and work diary snapshots, as CSV or Parquet with a stable column
+// order, so consumers need not map the fields themselves.
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// Row is a report row that can be exported
+type Row interface {
+	services.TransactionHistoryRow | services.TimeReport | services.WorkDiarySnapshot
+}
+
+// Option configures an export
+type Option func(*options)
+
+type options struct {
+	columns  []string
+	headers  map[string]string
+	noHeader bool
+}
+
+// WithColumns exports only the named columns, in the given order. Names
+// are those Columns returns.
+func WithColumns(names ...string) Option {
+	return func(o *options) {
+		o.columns = names
+	}
+}
+
+// WithHeader names column in the header, or in the Parquet schema, instead
+// of its default name
+func WithHeader(column, header string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[column] = header
+	}
+}
+
+// WithoutHeader leaves out the CSV header row
+func WithoutHeader() Option {
+	return func(o *options) {
+		o.noHeader = true
+	}
+}
+
+// Columns returns the default columns of T in their export order
+func Columns[T Row]() []string {
+	cols := schema[T]()
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return names
+}
+
+// ExportCSV writes rows to w as CSV, with a header row unless WithoutHeader
+// is given. Use a CSVWriter to write rows as they are fetched.
+func ExportCSV[T Row](w io.Writer, rows []T, opts ...Option) error {
+	cw, err := NewCSVWriter[T](w, opts...)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// CSVWriter writes rows of T as CSV one at a time, e.g. from
+// GetTransactionHistoryStream
+type CSVWriter[T Row] struct {
+	w       *csv.Writer
+	columns []column[T]
+	record  []string
+}
+
+// NewCSVWriter returns a writer of rows of T to w and writes the header
+// row, unless WithoutHeader is given. Call Flush when done.
+func NewCSVWriter[T Row](w io.Writer, opts ...Option) (*CSVWriter[T], error) {
+	o, columns, err := resolve[T](opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := &CSVWriter[T]{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	if !o.noHeader {
+		for i, c := range columns {
+			cw.record[i] = o.header(c.name)
+		}
+		if err := cw.w.Write(cw.record); err != nil {
+			return nil, err
+		}
+	}
+	return cw, nil
+}
+
+// Write writes a row
+func (cw *CSVWriter[T]) Write(row T) error {
+	for i, c := range cw.columns {
+		cw.record[i] = formatValue(c.value(row))
+	}
+	return cw.w.Write(cw.record)
+}
+
+// Flush writes any buffered rows and returns the first error writing any
+// row
+func (cw *CSVWriter[T]) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// resolve applies opts and returns the columns to export
+func resolve[T Row](opts []Option) (*options, []column[T], error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	all := schema[T]()
+	if len(o.columns) == 0 {
+		return o, all, nil
+	}
+
+	byName := make(map[string]column[T], len(all))
+	for _, c := range all {
+		byName[c.name] = c
+	}
+	columns := make([]column[T], len(o.columns))
+	for i, name := range o.columns {
+		c, ok := byName[name]
+		if !ok {
+			return nil, nil, &errors.ValidationError{Field: "columns", Message: "is not a known column", Value: name}
+		}
+		columns[i] = c
+	}
+	return o, columns, nil
+}
+
+// header returns the header of the named column
+func (o *options) header(name string) string {
+	if h, ok := o.headers[name]; ok {
+		return h
+	}
+	return name
+}
+
+// formatValue formats a column value as CSV text
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,230 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/binary"
+	stderrors "errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+var transactions = []services.TransactionHistoryRow{
+	{
+		RowNumber:               1,
+		RecordID:                "rec-1",
+		Type:                    "Payment",
+		Description:             `Invoice for "API", part 1`,
+		TransactionCreationDate: models.DateTime{RawValue: "2024-03-01T10:00:00Z"},
+		TransactionAmount:       models.MustMoney("-1250.5", "USD"),
+		Payment:                 models.MustMoney("1000", "JPY"),
+	},
+	{RowNumber: 2, RecordID: "rec-2", Type: "Fee"},
+}
+
+func TestColumns(t *testing.T) {
+	assert.Equal(t, []string{"row_number", "record_id", "type"}, Columns[services.TransactionHistoryRow]()[:3])
+	assert.Contains(t, Columns[services.TimeReport](), "total_charges_currency")
+	assert.Equal(t, "activity_percent", Columns[services.WorkDiarySnapshot]()[len(Columns[services.WorkDiarySnapshot]())-1])
+}
+
+func TestExportCSV(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, ExportCSV(&b, transactions,
+		WithColumns("record_id", "description", "transaction_amount", "transaction_amount_currency", "payment", "row_number"),
+		WithHeader("record_id", "Record"),
+	))
+	assert.Equal(t, `Record,description,transaction_amount,transaction_amount_currency,payment,row_number
+rec-1,"Invoice for ""API"", part 1",-1250.50,USD,1000,1
+rec-2,,0.00,,0.00,2
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, ExportCSV(&b, transactions[1:], WithoutHeader()))
+	assert.Equal(t, len(Columns[services.TransactionHistoryRow]()), strings.Count(b.String(), ",")+1)
+	assert.True(t, strings.HasPrefix(b.String(), "2,rec-2,Fee,"))
+
+	err := ExportCSV(&b, transactions, WithColumns("record_id", "nope"))
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "nope", validationErr.Value)
+}
+
+func TestExportCSVWorkDiary(t *testing.T) {
+	snapshots := []services.WorkDiarySnapshot{{
+		Contract:    services.WorkDiaryContract{ID: "c-1", ContractTitle: "API"},
+		User:        models.User{ID: "user-1"},
+		Time:        services.WorkDiaryTime{FirstWorkedInt: 1709542800},
+		Screenshots: []services.Screenshot{{Activity: 10}, {Activity: 5}},
+	}}
+
+	var b bytes.Buffer
+	require.NoError(t, ExportCSV(&b, snapshots, WithColumns("contract_id", "user_id", "first_worked_unix", "screenshots", "activity_percent")))
+	assert.Equal(t, "contract_id,user_id,first_worked_unix,screenshots,activity_percent\nc-1,user-1,1709542800,2,75\n", b.String())
+}
+
+func TestExportParquet(t *testing.T) {
+	reports := []services.TimeReport{
+		{Contract: services.Contract{ID: "c-1", Title: "API"}, YearWorkedOn: 2024, TotalHoursWorked: 7.5},
+		{Contract: services.Contract{ID: "c-2", Title: "Café"}, YearWorkedOn: 2023, TotalHoursWorked: 0.25},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, ExportParquet(&b, reports,
+		WithColumns("contract_id", "contract_title", "year_worked_on", "total_hours_worked"),
+		WithHeader("contract_title", "Title"),
+	))
+	file := b.Bytes()
+
+	require.Equal(t, "PAR1", string(file[:4]))
+	require.Equal(t, "PAR1", string(file[len(file)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := readThrift(t, file[len(file)-8-footerLen:len(file)-8])
+
+	assert.Equal(t, int64(2), footer[3], "num_rows")
+	schema := footer[2].([]interface{})
+	require.Len(t, schema, 5)
+	assert.Equal(t, int64(4), schema[0].(map[int16]interface{})[5], "root has a child per column")
+
+	var names []string
+	var types []int64
+	for _, el := range schema[1:] {
+		el := el.(map[int16]interface{})
+		names = append(names, el[4].(string))
+		types = append(types, el[1].(int64))
+	}
+	assert.Equal(t, []string{"contract_id", "Title", "year_worked_on", "total_hours_worked"}, names)
+	assert.Equal(t, []int64{parquetByteArray, parquetByteArray, parquetInt64, parquetDouble}, types)
+
+	rowGroup := footer[4].([]interface{})[0].(map[int16]interface{})
+	chunks := rowGroup[1].([]interface{})
+	require.Len(t, chunks, 4)
+
+	var values [][]interface{}
+	for i, chunk := range chunks {
+		meta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+		assert.Equal(t, []interface{}{names[i]}, meta[3], "path in schema")
+		assert.Equal(t, int64(2), meta[5], "num_values")
+
+		offset := meta[9].(int64)
+		page := file[offset : offset+meta[6].(int64)]
+		header, n := readThriftStruct(t, page)
+		assert.Equal(t, int64(parquetDataPage), header[1])
+		data := page[n:]
+		require.Len(t, data, int(header[2].(int64)))
+		values = append(values, decodePlain(t, types[i], data, 2))
+	}
+	assert.Equal(t, [][]interface{}{
+		{"c-1", "c-2"},
+		{"API", "Café"},
+		{int64(2024), int64(2023)},
+		{7.5, 0.25},
+	}, values)
+}
+
+// decodePlain decodes n PLAIN encoded values of type typ
+func decodePlain(t *testing.T, typ int64, data []byte, n int) []interface{} {
+	var values []interface{}
+	for i := 0; i < n; i++ {
+		switch typ {
+		case parquetByteArray:
+			size := int(binary.LittleEndian.Uint32(data))
+			values = append(values, string(data[4:4+size]))
+			data = data[4+size:]
+		case parquetInt64:
+			values = append(values, int64(binary.LittleEndian.Uint64(data)))
+			data = data[8:]
+		case parquetDouble:
+			values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+			data = data[8:]
+		}
+	}
+	assert.Empty(t, data, "trailing bytes")
+	return values
+}
+
+// readThrift decodes a Thrift compact struct that makes up all of data
+func readThrift(t *testing.T, data []byte) map[int16]interface{} {
+	s, n := readThriftStruct(t, data)
+	require.Equal(t, len(data), n, "trailing bytes")
+	return s
+}
+
+// readThriftStruct decodes a Thrift compact struct into its fields by ID,
+// with integers as int64, binaries as strings and lists as slices, and
+// returns the number of bytes read
+func readThriftStruct(t *testing.T, data []byte) (map[int16]interface{}, int) {
+	r := &thriftReader{t: t, data: data}
+	return r.readStruct(), r.pos
+}
+
+type thriftReader struct {
+	t    *testing.T
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		b := r.data[r.pos]
+		r.pos++
+		if b == 0 {
+			return fields
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			id = int16(r.varint())
+		}
+		last = id
+		fields[id] = r.readValue(b & 0x0f)
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		b := r.data[r.pos]
+		r.pos++
+		n := int(b >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.readValue(b & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	r.t.Fatalf("unexpected thrift type %d at %d", typ, r.pos)
+	return nil
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	require.Positive(r.t, n)
+	r.pos += n
+	return v
+}
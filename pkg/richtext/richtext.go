@@ -0,0 +1,434 @@
+// Package richtext renders the HTML the API returns in text fields, such
+// as job descriptions, as plain text or Markdown, and sanitizes it for
+// display, so consumers need not parse HTML themselves.
+package richtext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// format is the output format of a renderer
+type format int
+
+const (
+	formatText format = iota
+	formatMarkdown
+)
+
+// tagPattern matches an HTML start or end tag
+var tagPattern = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*(\s[^<>]*)?/?>`)
+
+// IsHTML returns true if s contains HTML tags. Text fields are often plain
+// text with line breaks, which is rendered as is.
+func IsHTML(s string) bool {
+	return tagPattern.MatchString(s)
+}
+
+// PlainText returns s, an HTML fragment or plain text, as plain text.
+// Paragraphs are separated by blank lines, list items are prefixed with
+// "- " or their number, and scripts, styles and tags are dropped.
+func PlainText(s string) string {
+	return render(s, formatText)
+}
+
+// Markdown returns s, an HTML fragment or plain text, as Markdown.
+// Emphasis, links with http, https or mailto URLs, headings, lists, quotes
+// and code are kept; other tags are dropped.
+func Markdown(s string) string {
+	return render(s, formatMarkdown)
+}
+
+// render renders s in f
+func render(s string, f format) string {
+	if !IsHTML(s) {
+		text := strings.ReplaceAll(html.UnescapeString(s), "\r\n", "\n")
+		if f == formatMarkdown {
+			text = escapeMarkdown(text)
+		}
+		return strings.TrimSpace(text)
+	}
+
+	nodes, err := parseFragment(s)
+	if err != nil {
+		return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(s, " ")))
+	}
+
+	r := &renderer{format: f}
+	for _, n := range nodes {
+		r.node(n)
+	}
+	return strings.TrimSpace(r.b.String())
+}
+
+// parseFragment parses s as the content of a body element
+func parseFragment(s string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+}
+
+// renderer writes the text of HTML nodes, collapsing whitespace as a
+// browser would
+type renderer struct {
+	format format
+	b      strings.Builder
+
+	// newlines is the number of line breaks owed before the next text
+	newlines int
+	// space is true if a space is owed before the next text on the line
+	space bool
+	// inline is true for renderers of the content of inline elements,
+	// whose leading and trailing spaces are kept for the parent to place
+	inline bool
+	// literal is true inside code, whose text is not escaped or collapsed
+	literal bool
+
+	// lists holds the lists the renderer is in, innermost last
+	lists []list
+	// quotes is the number of block quotes the renderer is in
+	quotes int
+}
+
+// list is an open list
+type list struct {
+	ordered bool
+	// items is the number of items so far
+	items int
+	// indent is the indentation of the lines of its items
+	indent string
+}
+
+// skipped are the elements whose content is not text
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Head: true, atom.Template: true,
+	atom.Noscript: true, atom.Iframe: true, atom.Object: true, atom.Svg: true,
+}
+
+// node renders n and its children
+func (r *renderer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		r.children(n)
+		return
+	default:
+		return
+	}
+
+	if skipped[n.DataAtom] {
+		return
+	}
+
+	md := r.format == formatMarkdown
+	switch n.DataAtom {
+	case atom.Br:
+		r.newlines++
+	case atom.Hr:
+		r.block(2)
+		r.write("---")
+		r.block(2)
+	case atom.P, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Table:
+		r.block(2)
+		r.children(n)
+		r.block(2)
+	case atom.Div, atom.Tr, atom.Dt, atom.Dd:
+		r.block(1)
+		r.children(n)
+		r.block(1)
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.block(2)
+		if md {
+			level := int(n.Data[1] - '0')
+			r.write(strings.Repeat("#", level) + " ")
+		}
+		r.children(n)
+		r.block(2)
+	case atom.Ul, atom.Ol:
+		r.block(2 - min(len(r.lists), 1))
+		r.lists = append(r.lists, list{ordered: n.DataAtom == atom.Ol, indent: r.itemIndent()})
+		r.children(n)
+		r.lists = r.lists[:len(r.lists)-1]
+		r.block(2 - min(len(r.lists), 1))
+	case atom.Li:
+		r.block(1)
+		r.write(r.nextMarker())
+		r.children(n)
+		r.block(1)
+	case atom.Blockquote:
+		r.block(2)
+		r.quotes++
+		r.children(n)
+		r.quotes--
+		r.block(2)
+	case atom.Pre:
+		r.block(2)
+		if md {
+			r.write("```")
+			r.newlines = 1
+		}
+		r.write(strings.TrimRight(r.inner(n, true), "\n"))
+		if md {
+			r.newlines = 1
+			r.write("```")
+		}
+		r.block(2)
+	case atom.Td, atom.Th:
+		r.space = true
+		r.children(n)
+		r.space = true
+	case atom.B, atom.Strong:
+		r.wrap(n, "**")
+	case atom.I, atom.Em:
+		r.wrap(n, "*")
+	case atom.S, atom.Del, atom.Strike:
+		r.wrap(n, "~~")
+	case atom.Code, atom.Kbd, atom.Samp:
+		if md {
+			r.wrap(n, "`")
+		} else {
+			r.children(n)
+		}
+	case atom.A:
+		r.link(n)
+	case atom.Img:
+		r.image(n)
+	default:
+		r.children(n)
+	}
+}
+
+// children renders the children of n
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+}
+
+// text writes the text of a text node
+func (r *renderer) text(s string) {
+	if r.literal {
+		r.write(s)
+		return
+	}
+
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" {
+			r.space = true
+		}
+		return
+	}
+	if isSpace(s[0]) {
+		r.space = true
+	}
+	for i, word := range words {
+		if i > 0 {
+			r.space = true
+		}
+		if r.format == formatMarkdown {
+			word = escapeMarkdown(word)
+		}
+		r.write(word)
+	}
+	if isSpace(s[len(s)-1]) {
+		r.space = true
+	}
+}
+
+// block ends the current line with at least n line breaks
+func (r *renderer) block(n int) {
+	r.newlines = max(r.newlines, n)
+	r.space = false
+}
+
+// write writes s after the line breaks and space owed, prefixing new lines
+// with the quote and list indentation
+func (r *renderer) write(s string) {
+	if s == "" {
+		return
+	}
+	if r.newlines > 0 {
+		prefix := r.prefix()
+		if r.b.Len() > 0 {
+			// Blank lines carry the quote markers without trailing spaces
+			blank := "\n" + strings.TrimRight(prefix, " ")
+			r.b.WriteString(strings.Repeat(blank, r.newlines-1) + "\n")
+		}
+		r.b.WriteString(prefix)
+		r.newlines = 0
+		r.space = false
+	}
+	if r.space && (r.b.Len() > 0 || r.inline) {
+		r.b.WriteByte(' ')
+	}
+	r.space = false
+
+	if strings.Contains(s, "\n") && r.prefix() != "" {
+		s = strings.ReplaceAll(s, "\n", "\n"+r.prefix())
+	}
+	r.b.WriteString(s)
+}
+
+// prefix returns the prefix of new lines: a "> " per quote in Markdown and
+// the indentation of the innermost list
+func (r *renderer) prefix() string {
+	var prefix string
+	if r.format == formatMarkdown {
+		prefix = strings.Repeat("> ", r.quotes)
+	}
+	if len(r.lists) > 0 {
+		prefix += r.lists[len(r.lists)-1].indent
+	}
+	return prefix
+}
+
+// itemIndent returns the indentation of a list nested in the current item,
+// which lines up with the item's text
+func (r *renderer) itemIndent() string {
+	if len(r.lists) == 0 {
+		return ""
+	}
+	l := r.lists[len(r.lists)-1]
+	return l.indent + strings.Repeat(" ", len(l.marker()))
+}
+
+// marker returns the marker of the current item of l
+func (l list) marker() string {
+	if l.ordered {
+		return strconv.Itoa(max(l.items, 1)) + ". "
+	}
+	return "- "
+}
+
+// nextMarker starts the next item of the innermost list and returns its
+// marker. Items outside a list are bulleted.
+func (r *renderer) nextMarker() string {
+	if len(r.lists) == 0 {
+		return "- "
+	}
+	l := &r.lists[len(r.lists)-1]
+	l.items++
+	return l.marker()
+}
+
+// inner returns the rendered content of n. literal keeps its text as is.
+func (r *renderer) inner(n *html.Node, literal bool) string {
+	sub := &renderer{format: r.format, inline: true, literal: r.literal || literal}
+	sub.children(n)
+	if sub.space {
+		sub.b.WriteByte(' ')
+	}
+	return sub.b.String()
+}
+
+// place writes inner, the content of an inline element, as wrap returns
+// it, keeping the spaces around it outside the wrapping
+func (r *renderer) place(inner string, wrap func(string) string) {
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		r.space = r.space || inner != ""
+		return
+	}
+	if isSpace(inner[0]) {
+		r.space = true
+	}
+	r.write(wrap(trimmed))
+	if isSpace(inner[len(inner)-1]) {
+		r.space = true
+	}
+}
+
+// wrap writes the content of n between markers in Markdown
+func (r *renderer) wrap(n *html.Node, marker string) {
+	if r.format != formatMarkdown {
+		r.children(n)
+		return
+	}
+	r.place(r.inner(n, marker == "`"), func(s string) string {
+		return marker + s + marker
+	})
+}
+
+// link writes an anchor as a Markdown link, or as its text followed by its
+// URL in plain text. Links with unsafe URLs are written as text.
+func (r *renderer) link(n *html.Node) {
+	href := attr(n, "href")
+	if !safeURL(href) {
+		r.children(n)
+		return
+	}
+	r.place(r.inner(n, false), func(text string) string {
+		if r.format == formatMarkdown {
+			return "[" + text + "](" + escapeURL(href) + ")"
+		}
+		if text == href || "mailto:"+text == href {
+			return text
+		}
+		return text + " (" + href + ")"
+	})
+}
+
+// image writes an image as Markdown, or its alternative text in plain text
+func (r *renderer) image(n *html.Node) {
+	alt, src := strings.TrimSpace(attr(n, "alt")), attr(n, "src")
+	if r.format == formatMarkdown && safeURL(src) {
+		r.write("![" + escapeMarkdown(alt) + "](" + escapeURL(src) + ")")
+		return
+	}
+	if alt != "" {
+		if r.format == formatMarkdown {
+			alt = escapeMarkdown(alt)
+		}
+		r.write(alt)
+	}
+}
+
+// attr returns the value of the attribute key of n
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, key) {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// safeURL returns true if u is an http, https or mailto URL
+func safeURL(u string) bool {
+	scheme, _, ok := strings.Cut(u, ":")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// escapeURL escapes the characters of u that would end a Markdown link
+func escapeURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+// markdownEscaper escapes the characters Markdown would read as emphasis,
+// code, links or headings
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+)
+
+// escapeMarkdown escapes the Markdown syntax in plain text
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// isSpace returns true for HTML whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,151 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jobDescription = `<p>We are looking for a <b>senior Go developer</b> to
+build our   API client.</p>
+<h2>Requirements</h2>
+<ul>
+  <li>5+ years of <em>Go</em></li>
+  <li>GraphQL, including:
+    <ol><li>schema design</li><li>codegen</li></ol>
+  </li>
+</ul>
+<p>See <a href="https://example.com/spec">the spec</a> &amp; apply.<br>Thanks!</p>
+<script>alert("x")</script>`
+
+func TestPlainText(t *testing.T) {
+	assert.Equal(t, `We are looking for a senior Go developer to build our API client.
+
+Requirements
+
+- 5+ years of Go
+- GraphQL, including:
+  1. schema design
+  2. codegen
+
+See the spec (https://example.com/spec) & apply.
+Thanks!`, PlainText(jobDescription))
+}
+
+func TestMarkdown(t *testing.T) {
+	assert.Equal(t, `We are looking for a **senior Go developer** to build our API client.
+
+## Requirements
+
+- 5+ years of *Go*
+- GraphQL, including:
+  1. schema design
+  2. codegen
+
+See [the spec](https://example.com/spec) & apply.
+Thanks!`, Markdown(jobDescription))
+}
+
+func TestRenderElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		text, md string
+	}{
+		{
+			name: "plain text keeps line breaks",
+			html: "Need help with *two* things:\r\n1. A &amp; B\n\nThanks",
+			text: "Need help with *two* things:\n1. A & B\n\nThanks",
+			md:   "Need help with \\*two\\* things:\n1. A & B\n\nThanks",
+		},
+		{
+			name: "spaces stay outside emphasis",
+			html: "<p>a<b> bold </b>word and <i></i> none</p>",
+			text: "a bold word and none",
+			md:   "a **bold** word and none",
+		},
+		{
+			name: "preformatted code",
+			html: "<p>Run:</p><pre>go test  ./...\n  -race</pre><p>then <code>make *</code></p>",
+			text: "Run:\n\ngo test  ./...\n  -race\n\nthen make *",
+			md:   "Run:\n\n```\ngo test  ./...\n  -race\n```\n\nthen `make *`",
+		},
+		{
+			name: "quotes",
+			html: "<blockquote><p>first</p><p>second</p></blockquote>",
+			text: "first\n\nsecond",
+			md:   "> first\n>\n> second",
+		},
+		{
+			name: "unsafe links are text",
+			html: `<a href="javascript:alert(1)">click</a> <a href="mailto:hr@example.com">hr@example.com</a>`,
+			text: "click hr@example.com",
+			md:   `click [hr@example.com](mailto:hr@example.com)`,
+		},
+		{
+			name: "images",
+			html: `<img src="https://example.com/a b.png" alt="diagram"> <img src="data:x" alt="inline">`,
+			text: "diagram inline",
+			md:   "![diagram](https://example.com/a%20b.png) inline",
+		},
+		{
+			name: "unbalanced tags",
+			html: "<p>one<p>two <b>bold",
+			text: "one\n\ntwo bold",
+			md:   "one\n\ntwo **bold**",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.text, PlainText(tt.html))
+			assert.Equal(t, tt.md, Markdown(tt.html))
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := map[string]struct {
+		html, want string
+	}{
+		"formatting is kept": {
+			html: `<p class="x" onclick="steal()">Hello <strong>world</strong></p>`,
+			want: `<p>Hello <strong>world</strong></p>`,
+		},
+		"scripts are dropped": {
+			html: `<div>a<script>alert(1)</script><style>p{}</style>b</div>`,
+			want: `<div>ab</div>`,
+		},
+		"unknown elements are unwrapped": {
+			html: `<form><input value="x"><label>Name</label></form>`,
+			want: `Name`,
+		},
+		"unsafe URLs are removed": {
+			html: `<a href="javascript:alert(1)" title="t">x</a><img src="https://example.com/i.png" alt="&quot;i&quot;">`,
+			want: `<a title="t" rel="nofollow noopener">x</a><img src="https://example.com/i.png" alt="&#34;i&#34;">`,
+		},
+		"tags are balanced": {
+			html: `<ul><li>one<li>two</ul><b>bold`,
+			want: `<ul><li>one</li><li>two</li></ul><b>bold</b>`,
+		},
+		"plain text is escaped": {
+			html: "1 < 2 &amp; 3\nnext",
+			want: "1 &lt; 2 &amp; 3<br>next",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Sanitize(tt.html))
+		})
+	}
+}
+
+func TestIsHTML(t *testing.T) {
+	assert.True(t, IsHTML("<p>x</p>"))
+	assert.True(t, IsHTML("a<br/>b"))
+	assert.True(t, IsHTML(`<a href="x">`))
+	assert.False(t, IsHTML("1 < 2 and 3 > 2"))
+	assert.False(t, IsHTML("Tom &amp; Jerry"))
+	assert.False(t, IsHTML("email <> name"))
+}
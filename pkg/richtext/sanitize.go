@@ -0,0 +1,98 @@
+package richtext
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowed are the elements Sanitize keeps, with the attributes kept on each
+var allowed = map[atom.Atom][]string{
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Div: nil, atom.Span: nil,
+	atom.B: nil, atom.Strong: nil, atom.I: nil, atom.Em: nil, atom.U: nil,
+	atom.S: nil, atom.Del: nil, atom.Strike: nil, atom.Sub: nil, atom.Sup: nil,
+	atom.H1: nil, atom.H2: nil, atom.H3: nil, atom.H4: nil, atom.H5: nil, atom.H6: nil,
+	atom.Ul: nil, atom.Ol: nil, atom.Li: nil, atom.Dl: nil, atom.Dt: nil, atom.Dd: nil,
+	atom.Blockquote: nil, atom.Pre: nil, atom.Code: nil, atom.Kbd: nil, atom.Samp: nil,
+	atom.Table: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tr: nil, atom.Th: nil, atom.Td: nil,
+	atom.A:   {"href", "title"},
+	atom.Img: {"src", "alt", "title"},
+}
+
+// urlAttrs are the attributes holding URLs, kept only for safe schemes
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+// Sanitize returns s, an HTML fragment, with only formatting elements and
+// links and images to http, https or mailto URLs kept, so it can be shown
+// in a page. Other elements are replaced by their content, except scripts,
+// styles and the like, which are dropped. The result is balanced even if s
+// is not. Plain text is escaped with its line breaks kept.
+func Sanitize(s string) string {
+	if !IsHTML(s) {
+		text := html.EscapeString(html.UnescapeString(strings.ReplaceAll(s, "\r\n", "\n")))
+		return strings.ReplaceAll(text, "\n", "<br>")
+	}
+
+	nodes, err := parseFragment(s)
+	if err != nil {
+		return html.EscapeString(s)
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		sanitizeNode(&b, n)
+	}
+	return b.String()
+}
+
+// sanitizeNode writes the allowed parts of n and its children to b
+func sanitizeNode(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		sanitizeChildren(b, n)
+		return
+	default:
+		return
+	}
+
+	if skipped[n.DataAtom] {
+		return
+	}
+	attrs, ok := allowed[n.DataAtom]
+	if !ok {
+		sanitizeChildren(b, n)
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(n.Data)
+	for _, key := range attrs {
+		val := attr(n, key)
+		if val == "" || urlAttrs[key] && !safeURL(val) {
+			continue
+		}
+		b.WriteString(" " + key + `="` + html.EscapeString(val) + `"`)
+	}
+	if n.DataAtom == atom.A {
+		b.WriteString(` rel="nofollow noopener"`)
+	}
+	b.WriteByte('>')
+
+	if n.DataAtom == atom.Br || n.DataAtom == atom.Hr || n.DataAtom == atom.Img {
+		return
+	}
+	sanitizeChildren(b, n)
+	b.WriteString("</" + n.Data + ">")
+}
+
+// sanitizeChildren writes the allowed parts of the children of n to b
+func sanitizeChildren(b *strings.Builder, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sanitizeNode(b, c)
+	}
+}
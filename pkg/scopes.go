@@ -0,0 +1,94 @@
+package upwork
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/auth"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// GrantedScopes returns the scopes granted to the current token. They are
+// taken from the token response when available and otherwise looked up
+// from the API.
+func (c *Client) GrantedScopes(ctx context.Context) ([]auth.Scope, error) {
+	c.mu.RLock()
+	token := c.token
+	baseClient := c.baseClient
+	c.mu.RUnlock()
+
+	if scopes, ok := auth.TokenScopes(token); ok {
+		return scopes, nil
+	}
+
+	req := &services.GraphQLRequest{
+		Query: `
+			query TokenScopes {
+				oauth2TokenInfo {
+					scopes
+				}
+			}
+		`,
+	}
+
+	var resp struct {
+		OAuth2TokenInfo struct {
+			Scopes []auth.Scope `json:"scopes"`
+		} `json:"oauth2TokenInfo"`
+	}
+
+	if err := baseClient.Do(ctx, req, &resp); err != nil {
+		return nil, errors.WrapError(err, "failed to get granted scopes")
+	}
+
+	return resp.OAuth2TokenInfo.Scopes, nil
+}
+
+// CheckScopes verifies that the current token was granted every required
+// scope. It returns an *errors.MissingScopesError listing the absent scopes
+// so applications can fail fast with an actionable message instead of a
+// 403 from the first request that needs them.
+func (c *Client) CheckScopes(ctx context.Context, required ...auth.Scope) error {
+	granted, err := c.GrantedScopes(ctx)
+	if err != nil {
+		return err
+	}
+
+	missing := auth.MissingScopes(granted, required...)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &errors.MissingScopesError{
+		Missing: scopeStrings(missing),
+		Granted: scopeStrings(granted),
+	}
+}
+
+// scopeStrings converts scopes to strings
+func scopeStrings(scopes []auth.Scope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
+
+// WhoAmI returns the scopes, expiry, user and organizations of the current
+// token in one request, which is useful when debugging authentication
+func (c *Client) WhoAmI(ctx context.Context) (*auth.TokenInfo, error) {
+	c.mu.RLock()
+	introspector := &auth.Introspector{APIURL: c.apiURL, HTTPClient: c.httpClient}
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == nil {
+		return nil, errors.ErrUnauthorized
+	}
+
+	info, err := introspector.Introspect(ctx, token)
+	if err != nil {
+		return nil, errors.WrapError(err, "failed to introspect token")
+	}
+	return info, nil
+}
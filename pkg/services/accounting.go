@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// AccountingEntityType represents the kind of an accounting entity
+type AccountingEntityType string
+
+const (
+	AccountingEntityTypeCompany    AccountingEntityType = "COMPANY"
+	AccountingEntityTypeTeam       AccountingEntityType = "TEAM"
+	AccountingEntityTypeFreelancer AccountingEntityType = "FREELANCER"
+	AccountingEntityTypeAgency     AccountingEntityType = "AGENCY"
+	AccountingEntityTypeUnknown    AccountingEntityType = "UNKNOWN"
+)
+
+// AccountingEntity is the entity transactions are booked against. Its ID is
+// the accounting entity ID (aceId) used by GetTransactionHistory.
+type AccountingEntity struct {
+	ID             models.ID            `json:"id"`
+	Name           string               `json:"name"`
+	Type           AccountingEntityType `json:"type"`
+	OrganizationID models.ID            `json:"organizationId"`
+	Currency       string               `json:"currency"`
+}
+
+// FinancialAccount represents an account held by an accounting entity
+type FinancialAccount struct {
+	ID                 models.ID    `json:"id"`
+	Name               string       `json:"name"`
+	Type               string       `json:"type"`
+	AccountingEntityID models.ID    `json:"accountingEntityId"`
+	Currency           string       `json:"currency"`
+	Balance            models.Money `json:"balance"`
+	Active             bool         `json:"active"`
+}
+
+// ListAccountingEntities returns the accounting entities the current user
+// can access, across all of their organizations
+func (s *ReportsService) ListAccountingEntities(ctx context.Context) ([]AccountingEntity, error) {
+	query := `
+		query ListAccountingEntities {
+			accountingEntities {
+				id
+				name
+				type
+				organizationId
+				currency
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+	}
+
+	var resp struct {
+		AccountingEntities []AccountingEntity `json:"accountingEntities"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.AccountingEntities, nil
+}
+
+// AccountingEntityIDs returns the IDs of the accounting entities, e.g. for
+// TransactionHistoryInput.AccountingEntityIDs
+func AccountingEntityIDs(entities []AccountingEntity) []string {
+	ids := make([]string, len(entities))
+	for i, e := range entities {
+		ids[i] = string(e.ID)
+	}
+	return ids
+}
+
+// GetFinancialAccounts returns the financial accounts of an accounting
+// entity
+func (s *ReportsService) GetFinancialAccounts(ctx context.Context, accountingEntityID string) ([]FinancialAccount, error) {
+	query := `
+		query GetFinancialAccounts($aceId: ID!) {
+			financialAccounts(aceId: $aceId) {
+				id
+				name
+				type
+				accountingEntityId
+				currency
+				balance {
+					rawValue
+					currency
+					displayValue
+				}
+				active
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"aceId": accountingEntityID,
+		},
+	}
+
+	var resp struct {
+		FinancialAccounts []FinancialAccount `json:"financialAccounts"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.FinancialAccounts, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountingEntities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"accountingEntities":[
+			{"id":"ace-1","name":"Acme","type":"COMPANY","organizationId":"org-1","currency":"USD"},
+			{"id":"ace-2","name":"Acme Design","type":"TEAM","organizationId":"org-1","currency":"USD"}
+		]}}`)
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	entities, err := svc.ListAccountingEntities(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+	assert.Equal(t, AccountingEntityTypeTeam, entities[1].Type)
+	assert.Equal(t, []string{"ace-1", "ace-2"}, AccountingEntityIDs(entities))
+}
+
+func TestGetFinancialAccounts(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		fmt.Fprint(w, `{"data":{"financialAccounts":[
+			{"id":"fa-1","type":"ESCROW","accountingEntityId":"ace-1","currency":"USD","balance":{"rawValue":"250.00","currency":"USD"},"active":true}
+		]}}`)
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	accounts, err := svc.GetFinancialAccounts(context.Background(), "ace-1")
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "250.00 USD", accounts[0].Balance.String())
+	assert.Equal(t, "ace-1", req.Variables["aceId"])
+}
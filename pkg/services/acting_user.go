@@ -0,0 +1,40 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// ActingUserHeader is the header set by WithActingUser
+const ActingUserHeader = "X-Upwork-API-ActingUserId"
+
+// WithActingUser makes an enterprise service account act on behalf of the
+// organization user userID, so the request is authorized and audited as
+// that user. Requests made with it fail with
+// errors.ErrImpersonationNotAllowed unless the client authenticates as a
+// service account.
+func WithActingUser(userID string) RequestOption {
+	return func(o *requestOptions) {
+		o.actingUser = &userID
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(ActingUserHeader, userID)
+	}
+}
+
+// checkActingUser returns an error if the request acts on behalf of a user
+// but cannot
+func (c *BaseClient) checkActingUser(options *requestOptions) error {
+	if options.actingUser == nil {
+		return nil
+	}
+	if *options.actingUser == "" {
+		return &errors.ValidationError{Field: "actingUser", Message: "user ID is required"}
+	}
+	if !c.ServiceAccount {
+		return errors.ErrImpersonationNotAllowed
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestWithActingUser(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if r.Header.Get("X-Batch") != "" {
+			w.Write([]byte(`[{"data":{}}]`))
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+	ctx := context.Background()
+	req := &GraphQLRequest{Query: `query GetUser { user { id } }`}
+
+	// Only service accounts may act on behalf of users
+	err := client.Do(ctx, req, nil, WithActingUser("user-1"))
+	assert.True(t, stderrors.Is(err, errors.ErrImpersonationNotAllowed))
+	_, err = client.DoBatch(WithRequestOptions(ctx, WithActingUser("user-1")), []*GraphQLRequest{req}, []interface{}{nil})
+	assert.True(t, stderrors.Is(err, errors.ErrImpersonationNotAllowed))
+	assert.Empty(t, headers)
+
+	client.ServiceAccount = true
+	assertValidationField(t, client.Do(ctx, req, nil, WithActingUser("")), "actingUser")
+
+	require.NoError(t, client.Do(WithRequestOptions(ctx, WithActingUser("user-1")), req, nil))
+	_, err = client.DoBatch(ctx, []*GraphQLRequest{req}, []interface{}{nil}, WithActingUser("user-2"), WithHeader("X-Batch", "1"))
+	require.NoError(t, err)
+	require.NoError(t, client.Do(ctx, req, nil))
+
+	require.Len(t, headers, 3)
+	assert.Equal(t, "user-1", headers[0].Get(ActingUserHeader))
+	assert.Equal(t, "user-2", headers[1].Get(ActingUserHeader))
+	assert.Empty(t, headers[2].Get(ActingUserHeader))
+}
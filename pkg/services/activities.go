@@ -1,9 +1,11 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
 	"fmt"
+	"sort"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
 )
 
 // ActivitiesService handles activity-related API operations
@@ -91,7 +93,7 @@ func (s *ActivitiesService) ListTeamActivities(ctx context.Context, input ListTe
 			}
 		}
 	`
-	
+
 	variables := map[string]interface{}{
 		"orgId": input.OrgID,
 	}
@@ -104,20 +106,20 @@ func (s *ActivitiesService) ListTeamActivities(ctx context.Context, input ListTe
 	if input.Page != nil {
 		variables["page"] = input.Page
 	}
-	
+
 	req := &GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
-	
+
 	var resp struct {
 		TeamActivities ActivityList `json:"teamActivities"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.TeamActivities, nil
 }
 
@@ -130,8 +132,20 @@ type TeamActivityInput struct {
 	AllInCompany bool     `json:"allInCompany,omitempty"`
 }
 
+// Validate checks that the activity code and description are set
+func (in TeamActivityInput) Validate() error {
+	return firstError(
+		required("code", in.Code),
+		required("description", in.Description),
+	)
+}
+
 // AddTeamActivity creates a new team activity
 func (s *ActivitiesService) AddTeamActivity(ctx context.Context, orgID string, teamID string, input TeamActivityInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation AddTeamActivity(
 			$orgId: ID!,
@@ -148,7 +162,7 @@ func (s *ActivitiesService) AddTeamActivity(ctx context.Context, orgID string, t
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -157,27 +171,31 @@ func (s *ActivitiesService) AddTeamActivity(ctx context.Context, orgID string, t
 			"request": input,
 		},
 	}
-	
+
 	var resp struct {
 		AddTeamActivity struct {
 			ID      string `json:"id"`
 			Success bool   `json:"success"`
 		} `json:"addTeamActivity"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.AddTeamActivity.Success {
 		return fmt.Errorf("failed to add team activity")
 	}
-	
+
 	return nil
 }
 
 // UpdateTeamActivity updates an existing team activity
 func (s *ActivitiesService) UpdateTeamActivity(ctx context.Context, orgID string, teamID string, input TeamActivityInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation UpdateTeamActivity(
 			$orgId: ID!,
@@ -193,7 +211,7 @@ func (s *ActivitiesService) UpdateTeamActivity(ctx context.Context, orgID string
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -202,21 +220,21 @@ func (s *ActivitiesService) UpdateTeamActivity(ctx context.Context, orgID string
 			"request": input,
 		},
 	}
-	
+
 	var resp struct {
 		UpdateTeamActivity struct {
 			Success bool `json:"success"`
 		} `json:"updateTeamActivity"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.UpdateTeamActivity.Success {
 		return fmt.Errorf("failed to update team activity")
 	}
-	
+
 	return nil
 }
 
@@ -237,7 +255,7 @@ func (s *ActivitiesService) ArchiveTeamActivity(ctx context.Context, orgID strin
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -246,21 +264,21 @@ func (s *ActivitiesService) ArchiveTeamActivity(ctx context.Context, orgID strin
 			"codes":  codes,
 		},
 	}
-	
+
 	var resp struct {
 		ArchiveTeamActivity struct {
 			Success bool `json:"success"`
 		} `json:"archiveTeamActivity"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.ArchiveTeamActivity.Success {
 		return fmt.Errorf("failed to archive team activity")
 	}
-	
+
 	return nil
 }
 
@@ -281,7 +299,7 @@ func (s *ActivitiesService) UnarchiveTeamActivity(ctx context.Context, orgID str
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -290,21 +308,21 @@ func (s *ActivitiesService) UnarchiveTeamActivity(ctx context.Context, orgID str
 			"codes":  codes,
 		},
 	}
-	
+
 	var resp struct {
 		UnarchiveTeamActivity struct {
 			Success bool `json:"success"`
 		} `json:"unarchiveTeamActivity"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.UnarchiveTeamActivity.Success {
 		return fmt.Errorf("failed to unarchive team activity")
 	}
-	
+
 	return nil
 }
 
@@ -327,7 +345,7 @@ func (s *ActivitiesService) AssignActivityToContract(ctx context.Context, orgID
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -337,20 +355,160 @@ func (s *ActivitiesService) AssignActivityToContract(ctx context.Context, orgID
 			"codes":      codes,
 		},
 	}
-	
+
 	var resp struct {
 		AssignTeamActivityToTheContract struct {
 			Success bool `json:"success"`
 		} `json:"assignTeamActivityToTheContract"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.AssignTeamActivityToTheContract.Success {
 		return fmt.Errorf("failed to assign activity to contract")
 	}
-	
+
+	return nil
+}
+
+// UnassignActivityFromContract removes activities from a contract
+func (s *ActivitiesService) UnassignActivityFromContract(ctx context.Context, orgID string, teamID string, contractID string, codes []string) error {
+	mutation := `
+		mutation UnassignTeamActivityToTheContract(
+			$orgId: ID!,
+			$teamId: ID!,
+			$contractId: ID!,
+			$codes: [String!]!
+		) {
+			unassignTeamActivityToTheContract(
+				orgId: $orgId,
+				teamId: $teamId,
+				contractId: $contractId,
+				codes: $codes
+			) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"orgId":      orgID,
+			"teamId":     teamID,
+			"contractId": contractID,
+			"codes":      codes,
+		},
+	}
+
+	var resp struct {
+		UnassignTeamActivityToTheContract struct {
+			Success bool `json:"success"`
+		} `json:"unassignTeamActivityToTheContract"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.UnassignTeamActivityToTheContract.Success {
+		return fmt.Errorf("failed to unassign activity from contract")
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// activitiesPageSize is the default page size used when reading every
+// activity assigned to a contract
+const activitiesPageSize = 100
+
+// AssignmentSync reports the changes made by SyncContractAssignments
+type AssignmentSync struct {
+	Assigned   []string
+	Unassigned []string
+}
+
+// Changed returns true if any activity was assigned or unassigned
+func (a *AssignmentSync) Changed() bool {
+	return len(a.Assigned) > 0 || len(a.Unassigned) > 0
+}
+
+// SyncContractAssignments makes the activities assigned to a contract
+// exactly desiredCodes. It reads the current assignments and only assigns
+// the missing codes and unassigns the extra ones, so running it again with
+// the same codes makes no changes. If unassigning fails, the returned
+// AssignmentSync records the assignments that were already made.
+func (s *ActivitiesService) SyncContractAssignments(ctx context.Context, orgID, teamID, contractID string, desiredCodes []string) (*AssignmentSync, error) {
+	if err := firstError(
+		required("orgId", orgID),
+		required("teamId", teamID),
+		required("contractId", contractID),
+	); err != nil {
+		return nil, err
+	}
+	desired := make(map[string]bool, len(desiredCodes))
+	for i, code := range desiredCodes {
+		if err := required(fmt.Sprintf("desiredCodes[%d]", i), code); err != nil {
+			return nil, err
+		}
+		desired[code] = true
+	}
+
+	current, err := s.contractActivityCodes(ctx, orgID, teamID, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssignmentSync{}
+	for code := range desired {
+		if !current[code] {
+			result.Assigned = append(result.Assigned, code)
+		}
+	}
+	for code := range current {
+		if !desired[code] {
+			result.Unassigned = append(result.Unassigned, code)
+		}
+	}
+	sort.Strings(result.Assigned)
+	sort.Strings(result.Unassigned)
+
+	if len(result.Assigned) > 0 {
+		if err := s.AssignActivityToContract(ctx, orgID, teamID, contractID, result.Assigned); err != nil {
+			return nil, errors.WrapError(err, "failed to assign activities")
+		}
+	}
+	if len(result.Unassigned) > 0 {
+		if err := s.UnassignActivityFromContract(ctx, orgID, teamID, contractID, result.Unassigned); err != nil {
+			return &AssignmentSync{Assigned: result.Assigned}, errors.WrapError(err, "failed to unassign activities")
+		}
+	}
+
+	return result, nil
+}
+
+// contractActivityCodes returns the codes of every activity assigned to a
+// contract
+func (s *ActivitiesService) contractActivityCodes(ctx context.Context, orgID, teamID, contractID string) (map[string]bool, error) {
+	size := s.client.pageSize(ctx, activitiesPageSize)
+	codes := make(map[string]bool)
+	for offset := 0; ; offset += size {
+		list, err := s.ListTeamActivities(ctx, ListTeamActivitiesInput{
+			OrgID:  orgID,
+			TeamID: teamID,
+			Filter: &ActivityFilter{ContractID: contractID},
+			Page:   &PageFilter{PageOffset: offset, PageSize: size},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range list.Edges {
+			codes[edge.Node.Code] = true
+		}
+		if len(list.Edges) < size || offset+len(list.Edges) >= list.TotalCount {
+			return codes, nil
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeActivities serves the assignments of one contract and applies
+// assign and unassign mutations to them
+func fakeActivities(t *testing.T, assigned map[string]bool) (*ActivitiesService, *[]GraphQLRequest) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+
+		codes, _ := req.Variables["codes"].([]interface{})
+
+		switch {
+		case strings.Contains(req.Query, "teamActivities("):
+			var edges []string
+			for code := range assigned {
+				edges = append(edges, fmt.Sprintf(`{"node":{"code":%q}}`, code))
+			}
+			fmt.Fprintf(w, `{"data":{"teamActivities":{"totalCount":%d,"edges":[%s]}}}`, len(edges), strings.Join(edges, ","))
+		case strings.Contains(req.Query, "unassignTeamActivityToTheContract("):
+			for _, c := range codes {
+				delete(assigned, c.(string))
+			}
+			w.Write([]byte(`{"data":{"unassignTeamActivityToTheContract":{"success":true}}}`))
+		case strings.Contains(req.Query, "assignTeamActivityToTheContract("):
+			for _, c := range codes {
+				assigned[c.(string)] = true
+			}
+			w.Write([]byte(`{"data":{"assignTeamActivityToTheContract":{"success":true}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewActivitiesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL}), &reqs
+}
+
+func TestSyncContractAssignments(t *testing.T) {
+	assigned := map[string]bool{"design": true, "legacy": true}
+	svc, reqs := fakeActivities(t, assigned)
+	ctx := context.Background()
+
+	result, err := svc.SyncContractAssignments(ctx, "org-1", "team-1", "contract-1", []string{"design", "backend", "api", "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "backend"}, result.Assigned)
+	assert.Equal(t, []string{"legacy"}, result.Unassigned)
+	assert.Equal(t, map[string]bool{"design": true, "backend": true, "api": true}, assigned)
+
+	require.Len(t, *reqs, 3)
+	assert.Equal(t, map[string]interface{}{"contractId": "contract-1"}, (*reqs)[0].Variables["filter"])
+
+	// A second run with the same codes only reads the assignments
+	result, err = svc.SyncContractAssignments(ctx, "org-1", "team-1", "contract-1", []string{"api", "backend", "design"})
+	require.NoError(t, err)
+	assert.False(t, result.Changed())
+	assert.Len(t, *reqs, 4)
+}
+
+func TestSyncContractAssignmentsValidation(t *testing.T) {
+	svc, reqs := fakeActivities(t, map[string]bool{})
+
+	_, err := svc.SyncContractAssignments(context.Background(), "org-1", "team-1", "", nil)
+	assertValidationField(t, err, "contractId")
+
+	_, err = svc.SyncContractAssignments(context.Background(), "org-1", "team-1", "contract-1", []string{"api", ""})
+	assertValidationField(t, err, "desiredCodes[1]")
+
+	assert.Empty(t, *reqs)
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// agencyContractsPageSize is the default page size used when rolling up
+// every contract of an agency
+const agencyContractsPageSize = 100
+
+// AgencyService handles agency API operations
+type AgencyService struct {
+	client *BaseClient
+}
+
+// NewAgencyService creates a new agency service
+func NewAgencyService(client *BaseClient) *AgencyService {
+	return &AgencyService{client: client}
+}
+
+// AgencyRole represents the role of a member in an agency
+type AgencyRole string
+
+const (
+	AgencyRoleOwner   AgencyRole = "OWNER"
+	AgencyRoleAdmin   AgencyRole = "ADMIN"
+	AgencyRoleMember  AgencyRole = "MEMBER"
+	AgencyRoleUnknown AgencyRole = "UNKNOWN"
+)
+
+// AgencyProfile represents the public profile of an agency
+type AgencyProfile struct {
+	ID              models.ID       `json:"id"`
+	Name            string          `json:"name"`
+	Title           string          `json:"title"`
+	Overview        string          `json:"overview"`
+	Location        models.Location `json:"location"`
+	JobSuccessScore float64         `json:"jobSuccessScore"`
+	TopRatedStatus  string          `json:"topRatedStatus"`
+	TotalEarnings   *models.Money   `json:"totalEarnings"`
+	TotalJobs       int             `json:"totalJobs"`
+	MemberCount     int             `json:"memberCount"`
+	Skills          []models.Skill  `json:"skills"`
+}
+
+// AgencyMember represents a member of an agency with the contracts they
+// are assigned to
+type AgencyMember struct {
+	User        models.User        `json:"user"`
+	Role        AgencyRole         `json:"role"`
+	HourlyRate  *models.Money      `json:"hourlyRate"`
+	Active      bool               `json:"active"`
+	Assignments []AgencyAssignment `json:"assignments"`
+}
+
+// AgencyAssignment represents a contract an agency member works on
+type AgencyAssignment struct {
+	ContractID    models.ID       `json:"contractId"`
+	ContractTitle string          `json:"contractTitle"`
+	ClientName    string          `json:"clientName"`
+	Status        ContractStatus  `json:"status"`
+	StartDateTime models.DateTime `json:"startDateTime"`
+}
+
+// AgencyContract represents a contract held by an agency
+type AgencyContract struct {
+	ID           models.ID      `json:"id"`
+	Title        string         `json:"title"`
+	ContractType ContractType   `json:"contractType"`
+	Status       ContractStatus `json:"status"`
+	ClientName   string         `json:"clientName"`
+	// Member is the agency member assigned to the contract
+	Member       models.User   `json:"member"`
+	HourlyRate   *models.Money `json:"hourlyRate"`
+	TotalCharged models.Money  `json:"totalCharged"`
+}
+
+// AgencyContractList represents a paginated list of agency contracts
+type AgencyContractList struct {
+	TotalCount int                  `json:"totalCount"`
+	PageInfo   models.PageInfo      `json:"pageInfo"`
+	Edges      []AgencyContractEdge `json:"edges"`
+}
+
+// AgencyContractEdge represents an agency contract edge in pagination
+type AgencyContractEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   AgencyContract `json:"node"`
+}
+
+// AgencyContractsRollup summarizes an agency's contracts
+type AgencyContractsRollup struct {
+	Total    int                    `json:"total"`
+	ByStatus map[ContractStatus]int `json:"byStatus"`
+	// ByMember has one entry per assigned member, most contracts first
+	ByMember []AgencyMemberRollup `json:"byMember"`
+}
+
+// AgencyMemberRollup summarizes the contracts assigned to an agency member
+type AgencyMemberRollup struct {
+	Member models.User `json:"member"`
+	Total  int         `json:"total"`
+	Active int         `json:"active"`
+	// TotalCharged is the sum charged on the member's contracts, per
+	// currency
+	TotalCharged map[string]models.Money `json:"totalCharged"`
+}
+
+// TransferAgencyContractInput represents input for reassigning an agency
+// contract to another member
+type TransferAgencyContractInput struct {
+	AgencyID   string `json:"agencyId"`
+	ContractID string `json:"contractId"`
+	// ToUserID is the agency member taking over the contract
+	ToUserID string `json:"toUserId"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Validate checks that the agency, contract and member are set
+func (in TransferAgencyContractInput) Validate() error {
+	return firstError(
+		required("agencyId", in.AgencyID),
+		required("contractId", in.ContractID),
+		required("toUserId", in.ToUserID),
+	)
+}
+
+// GetAgencyProfile returns the profile of an agency
+func (s *AgencyService) GetAgencyProfile(ctx context.Context, agencyID string) (*AgencyProfile, error) {
+	if err := required("agencyId", agencyID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetAgencyProfile($id: ID!) {
+			agencyProfile(id: $id) {
+				id
+				name
+				title
+				overview
+				location {
+					country
+					city
+					timezone
+				}
+				jobSuccessScore
+				topRatedStatus
+				totalEarnings {
+					rawValue
+					currency
+					displayValue
+				}
+				totalJobs
+				memberCount
+				skills {
+					id
+					prettyName
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": agencyID,
+		},
+	}
+
+	var resp struct {
+		AgencyProfile AgencyProfile `json:"agencyProfile"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.AgencyProfile, nil
+}
+
+// ListAgencyMembers returns the members of an agency and the contracts
+// each is assigned to
+func (s *AgencyService) ListAgencyMembers(ctx context.Context, agencyID string) ([]AgencyMember, error) {
+	if err := required("agencyId", agencyID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query ListAgencyMembers($agencyId: ID!) {
+			agencyMembers(agencyId: $agencyId) {
+				user {
+					id
+					name
+				}
+				role
+				hourlyRate {
+					rawValue
+					currency
+					displayValue
+				}
+				active
+				assignments {
+					contractId
+					contractTitle
+					clientName
+					status
+					startDateTime
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"agencyId": agencyID,
+		},
+	}
+
+	var resp struct {
+		AgencyMembers []AgencyMember `json:"agencyMembers"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.AgencyMembers, nil
+}
+
+// ListAgencyContracts returns a page of an agency's contracts
+func (s *AgencyService) ListAgencyContracts(ctx context.Context, agencyID string, pagination *models.PaginationInput) (*AgencyContractList, error) {
+	if err := required("agencyId", agencyID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query ListAgencyContracts($agencyId: ID!, $pagination: Pagination) {
+			agencyContracts(agencyId: $agencyId, pagination: $pagination) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {
+						id
+						title
+						contractType
+						status
+						clientName
+						member {
+							id
+							name
+						}
+						hourlyRate {
+							rawValue
+							currency
+							displayValue
+						}
+						totalCharged {
+							rawValue
+							currency
+							displayValue
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"agencyId": agencyID,
+	}
+	if pagination != nil {
+		variables["pagination"] = pagination
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		AgencyContracts AgencyContractList `json:"agencyContracts"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.AgencyContracts, nil
+}
+
+// GetAgencyContractsRollup fetches every contract of an agency and
+// summarizes them with RollupAgencyContracts
+func (s *AgencyService) GetAgencyContractsRollup(ctx context.Context, agencyID string) (*AgencyContractsRollup, error) {
+	size := s.client.pageSize(ctx, agencyContractsPageSize)
+	pagination := &models.PaginationInput{First: size}
+
+	var contracts []AgencyContract
+	for {
+		page, err := s.ListAgencyContracts(ctx, agencyID, pagination)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range page.Edges {
+			contracts = append(contracts, edge.Node)
+		}
+
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		next := page.PageInfo.EndCursor
+		if !page.PageInfo.HasNextPage || next == "" || next == pagination.After {
+			break
+		}
+		pagination = &models.PaginationInput{First: size, After: next}
+	}
+
+	rollup := RollupAgencyContracts(contracts)
+	return &rollup, nil
+}
+
+// RollupAgencyContracts counts contracts by status and by assigned member
+// and sums what was charged on each member's contracts, without making any
+// requests. Contracts without a member are only counted by status.
+func RollupAgencyContracts(contracts []AgencyContract) AgencyContractsRollup {
+	rollup := AgencyContractsRollup{
+		Total:    len(contracts),
+		ByStatus: make(map[ContractStatus]int),
+	}
+
+	members := make(map[models.ID]*AgencyMemberRollup)
+	var order []models.ID
+	for _, c := range contracts {
+		rollup.ByStatus[c.Status.Known()]++
+		if c.Member.ID == "" {
+			continue
+		}
+
+		m, ok := members[c.Member.ID]
+		if !ok {
+			m = &AgencyMemberRollup{Member: c.Member, TotalCharged: make(map[string]models.Money)}
+			members[c.Member.ID] = m
+			order = append(order, c.Member.ID)
+		}
+		m.Total++
+		if c.Status == ContractStatusActive {
+			m.Active++
+		}
+
+		if !c.TotalCharged.IsZero() {
+			currency := strings.ToUpper(c.TotalCharged.Currency)
+			// Amounts in the same currency always add
+			sum, _ := m.TotalCharged[currency].Add(c.TotalCharged)
+			m.TotalCharged[currency] = sum
+		}
+	}
+
+	rollup.ByMember = make([]AgencyMemberRollup, 0, len(order))
+	for _, id := range order {
+		rollup.ByMember = append(rollup.ByMember, *members[id])
+	}
+	sort.SliceStable(rollup.ByMember, func(i, j int) bool {
+		return rollup.ByMember[i].Total > rollup.ByMember[j].Total
+	})
+	return rollup
+}
+
+// TransferAgencyContract reassigns an agency contract to another member of
+// the agency. The client is notified of the change.
+func (s *AgencyService) TransferAgencyContract(ctx context.Context, input TransferAgencyContractInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation TransferAgencyContract($input: TransferAgencyContractInput!) {
+			transferAgencyContract(input: $input) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		TransferAgencyContract struct {
+			Success bool `json:"success"`
+		} `json:"transferAgencyContract"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.TransferAgencyContract.Success {
+		return fmt.Errorf("failed to transfer contract")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestRollupAgencyContracts(t *testing.T) {
+	ada := models.User{ID: "user-1", Name: "Ada"}
+	bob := models.User{ID: "user-2", Name: "Bob"}
+	usd := func(v string) models.Money { return models.MustMoney(v, "USD") }
+
+	rollup := RollupAgencyContracts([]AgencyContract{
+		{ID: "c-1", Status: ContractStatusActive, Member: bob, TotalCharged: usd("100")},
+		{ID: "c-2", Status: ContractStatusActive, Member: ada, TotalCharged: usd("250.50")},
+		{ID: "c-3", Status: ContractStatusEnded, Member: ada, TotalCharged: usd("49.50")},
+		{ID: "c-4", Status: ContractStatusEnded, Member: ada, TotalCharged: models.MustMoney("80", "EUR")},
+		{ID: "c-5", Status: "ARCHIVED"},
+	})
+
+	assert.Equal(t, 5, rollup.Total)
+	assert.Equal(t, map[ContractStatus]int{
+		ContractStatusActive:  2,
+		ContractStatusEnded:   2,
+		ContractStatusUnknown: 1,
+	}, rollup.ByStatus)
+
+	require.Len(t, rollup.ByMember, 2)
+	assert.Equal(t, ada, rollup.ByMember[0].Member)
+	assert.Equal(t, 3, rollup.ByMember[0].Total)
+	assert.Equal(t, 1, rollup.ByMember[0].Active)
+	assert.Equal(t, "300.00 USD", rollup.ByMember[0].TotalCharged["USD"].String())
+	assert.Equal(t, "80.00 EUR", rollup.ByMember[0].TotalCharged["EUR"].String())
+	assert.Equal(t, bob, rollup.ByMember[1].Member)
+	assert.Equal(t, 1, rollup.ByMember[1].Total)
+}
+
+func TestAgencyService(t *testing.T) {
+	var transferred bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "agencyContracts"):
+			assert.Equal(t, "agency-1", req.Variables["agencyId"])
+			pagination, _ := req.Variables["pagination"].(map[string]interface{})
+			if pagination["after"] == nil {
+				w.Write([]byte(`{"data":{"agencyContracts":{"pageInfo":{"hasNextPage":true,"endCursor":"page-2"},"edges":[
+					{"node":{"id":"c-1","status":"ACTIVE","member":{"id":"user-1","name":"Ada"},"totalCharged":{"rawValue":"100","currency":"USD"}}}
+				]}}}`))
+				return
+			}
+			assert.Equal(t, "page-2", pagination["after"])
+			w.Write([]byte(`{"data":{"agencyContracts":{"pageInfo":{"hasNextPage":false},"edges":[
+				{"node":{"id":"c-2","status":"ENDED","member":{"id":"user-1","name":"Ada"},"totalCharged":{"rawValue":"50","currency":"USD"}}}
+			]}}}`))
+		case strings.Contains(req.Query, "transferAgencyContract"):
+			transferred = true
+			assert.Equal(t, map[string]interface{}{"agencyId": "agency-1", "contractId": "c-1", "toUserId": "user-2"}, req.Variables["input"])
+			w.Write([]byte(`{"data":{"transferAgencyContract":{"success":true}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewAgencyService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	rollup, err := svc.GetAgencyContractsRollup(ctx, "agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rollup.Total)
+	require.Len(t, rollup.ByMember, 1)
+	assert.Equal(t, "150.00 USD", rollup.ByMember[0].TotalCharged["USD"].String())
+
+	err = svc.TransferAgencyContract(ctx, TransferAgencyContractInput{AgencyID: "agency-1", ContractID: "c-1"})
+	assertValidationField(t, err, "toUserId")
+	assert.False(t, transferred)
+
+	err = svc.TransferAgencyContract(ctx, TransferAgencyContractInput{AgencyID: "agency-1", ContractID: "c-1", ToUserID: "user-2"})
+	require.NoError(t, err)
+	assert.True(t, transferred)
+}
@@ -2,30 +2,238 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
-	
+
+	"github.com/rizome-dev/go-upwork/pkg/codec"
 	"github.com/rizome-dev/go-upwork/pkg/errors"
 )
 
 // BaseClient provides common functionality for all service clients
 type BaseClient struct {
-	HTTPClient     *http.Client
-	APIURL         string
+	HTTPClient *http.Client
+	APIURL     string
+
+	// OrganizationID is the default organization of requests. It must not
+	// be changed once requests may be in flight; use SetOrganizationID.
 	OrganizationID string
 	RateLimiter    RateLimiter
+
+	// OperationCosts sets the rate limit cost of operations by name,
+	// overriding the built-in costs of expensive reports. A
+	// WeightedRateLimiter debits each request its cost; other limiters
+	// charge one token per request. If a response reports its actual cost
+	// in extensions.cost.actualQueryCost, the difference is settled with
+	// the limiter.
+	OperationCosts map[string]int
+
+	// ServiceAccount is true if the client authenticates as an enterprise
+	// service account, which may act on behalf of organization users with
+	// WithActingUser
+	ServiceAccount bool
+
+	// RetryMutations enables automatic retries of mutations, which are
+	// otherwise sent once. Enable it only if the mutations used are
+	// idempotent.
+	RetryMutations bool
+
+	// StaleWhileError, if set, keeps the responses of queries and returns
+	// one no older than this in place of an error when the same query later
+	// fails because the API or the network does, keeping dashboards alive
+	// during incidents. WithFreshness reports whether a response is stale.
+	StaleWhileError time.Duration
+
+	// MaxRetryDuration bounds the time from the first attempt of a request
+	// to the start of its last retry. A retry that would start later is not
+	// made. Zero uses DefaultMaxRetryDuration.
+	MaxRetryDuration time.Duration
+
+	// Subscriber runs GraphQL subscriptions. Subscription methods return
+	// errors.ErrNoSubscriptions if it is nil.
+	Subscriber Subscriber
+
+	// Done, if set, is closed when the owning client is closed. Requests
+	// then fail with ErrClientClosed and background workers stop.
+	Done <-chan struct{}
+
+	// DeduplicateQueries makes identical queries issued concurrently share
+	// one HTTP call. Mutations are never shared.
+	DeduplicateQueries bool
+
+	// DefaultPageSize is the number of items requested per page by methods
+	// that pick a page size themselves. Zero uses each method's own
+	// default.
+	DefaultPageSize int
+
+	// Guardrails limits response sizes and reports queries that select or
+	// return more data than expected
+	Guardrails Guardrails
+
+	// MaxItems caps the number of items methods that walk every page
+	// return, stopping runaway pagination. Zero means no cap.
+	MaxItems int
+
+	// OnRequest, if set, is called when each request completes, e.g. to
+	// record metrics per operation. Requests in a batch are reported
+	// individually. It may be called concurrently.
+	OnRequest func(RequestEvent)
+
+	// Logger, if set, logs each request at debug level with its operation
+	// name, variables and duration. Values of variables whose field names
+	// match RedactFields are redacted.
+	Logger *slog.Logger
+
+	// RedactFields are the variable fields, matched case-insensitively as
+	// substrings of field names at any depth, whose values are redacted
+	// from logs. Nil uses DefaultRedactFields.
+	RedactFields []string
+
+	// Codec encodes requests and decodes responses, except those streamed
+	// by DoStream. Nil uses codec.Default.
+	Codec codec.Codec
+
+	// OnUnexpectedNull, if set, is called for each null in a successful
+	// response that is decoded into a result field that cannot hold one,
+	// such as a string or a non-pointer struct, pointing at its path in the
+	// response. Responses are buffered to check them, so it is meant for
+	// development.
+	OnUnexpectedNull func(UnexpectedNull)
+
+	// OnDeprecation, if set, is called for each deprecated field a request
+	// used, as reported in the warnings extension of its response. The
+	// Logger, if set, also logs each once as a warning. It may be called
+	// concurrently.
+	OnDeprecation func(DeprecationWarning)
+
+	// StrictDeprecations fails requests whose responses report deprecated
+	// fields with an *errors.DeprecationError, after decoding their
+	// results, so tests catch uses of deprecated fields
+	StrictDeprecations bool
+
+	// Quota reported by the API and recent throttle events
+	quota quotaTracker
+
+	// Responses kept for StaleWhileError, keyed by dedupeKey
+	staleMu sync.Mutex
+	stale   map[string]staleEntry
+
+	// Deprecation warnings already logged
+	deprecationsMu     sync.Mutex
+	deprecationsLogged map[string]bool
+
+	// Queries in flight, keyed by dedupeKey
+	flightsMu sync.Mutex
+	flights   map[string]*flight
+
+	// Organization set by SetOrganizationID, replacing OrganizationID
+	defaultOrg atomic.Pointer[string]
+
+	// Last X-Upwork-API-TenantId header value sent
+	tenantHeader atomic.Pointer[[]string]
 }
 
+// maxAttempts is the number of times a retryable request is sent
+const maxAttempts = 3
+
+// DefaultMaxRetryDuration bounds the time spent retrying a request unless
+// BaseClient.MaxRetryDuration is set
+const DefaultMaxRetryDuration = 30 * time.Second
+
 // RateLimiter interface for rate limiting
 type RateLimiter interface {
 	Wait(ctx context.Context) error
 }
 
+// organizationKey is the context key for a per-request organization ID
+type organizationKey struct{}
+
+// WithOrganization returns a context that sends orgID as the
+// X-Upwork-API-TenantId header for requests made with it, overriding the
+// client's OrganizationID
+func WithOrganization(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, orgID)
+}
+
+// OrganizationFromContext returns the organization ID set by WithOrganization
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	orgID, ok := ctx.Value(organizationKey{}).(string)
+	return orgID, ok
+}
+
+// SetOrganizationID changes the default organization of requests. It is
+// safe to call while requests are in flight; each request uses the
+// organization set when it was built.
+func (c *BaseClient) SetOrganizationID(orgID string) {
+	c.defaultOrg.Store(&orgID)
+}
+
+// GetOrganizationID returns the default organization of requests
+func (c *BaseClient) GetOrganizationID() string {
+	if orgID := c.defaultOrg.Load(); orgID != nil {
+		return *orgID
+	}
+	return c.OrganizationID
+}
+
+// organizationID returns the tenant for a request, preferring the context
+// over the client default
+func (c *BaseClient) organizationID(ctx context.Context) string {
+	if orgID, ok := OrganizationFromContext(ctx); ok {
+		return orgID
+	}
+	return c.GetOrganizationID()
+}
+
+// RequestEvent describes a completed request
+type RequestEvent struct {
+	// OperationName is the name of the operation, if it has one
+	OperationName string
+	// Duration is the time taken, including rate limiting and retries
+	Duration time.Duration
+	// Err is the error the request failed with, if any
+	Err error
+	// Attempts is the number of times the request was sent
+	Attempts int
+	// RateLimited is the number of attempts the API rejected with 429 Too
+	// Many Requests
+	RateLimited int
+}
+
+// observe reports a completed request to OnRequest and Logger
+func (c *BaseClient) observe(ctx context.Context, req *GraphQLRequest, event RequestEvent) {
+	if c.OnRequest != nil {
+		c.OnRequest(event)
+	}
+	if c.Logger != nil {
+		c.logRequest(ctx, req, event)
+	}
+}
+
+// observeBatch reports each request of a batch to OnRequest and Logger.
+// The batch was sent once if status is non-zero.
+func (c *BaseClient) observeBatch(ctx context.Context, requests []*GraphQLRequest, result *BatchResult, err error, duration time.Duration, status int) {
+	for i, req := range requests {
+		event := RequestEvent{OperationName: req.OperationName, Duration: duration, Err: err}
+		if result != nil {
+			event.Err = result.Errors[i]
+		}
+		if status != 0 {
+			event.Attempts = 1
+		}
+		if status == http.StatusTooManyRequests {
+			event.RateLimited = 1
+		}
+		c.observe(ctx, req, event)
+	}
+}
+
 // GraphQLRequest represents a GraphQL request
 type GraphQLRequest struct {
 	Query         string                 `json:"query"`
@@ -35,168 +243,468 @@ type GraphQLRequest struct {
 
 // GraphQLResponse represents a GraphQL response
 type GraphQLResponse struct {
-	Data   json.RawMessage        `json:"data,omitempty"`
-	Errors []errors.GraphQLError  `json:"errors,omitempty"`
+	Data   json.RawMessage       `json:"data,omitempty"`
+	Errors []errors.GraphQLError `json:"errors,omitempty"`
+}
+
+// closed returns true if the owning client has been closed
+func (c *BaseClient) closed() bool {
+	select {
+	case <-c.Done:
+		return true
+	default:
+		return false
+	}
+}
+
+// workerContext returns a context for a background worker that is
+// cancelled when ctx is done or the owning client is closed
+func (c *BaseClient) workerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if c.Done != nil {
+		go func() {
+			select {
+			case <-c.Done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
+// Do executes a GraphQL request. Options apply to this request only.
+// Transient failures are retried for queries but not, by default, for
+// mutations; see WithRetry. The response is decoded as it is read rather
+// than buffered first.
+func (c *BaseClient) Do(ctx context.Context, req *GraphQLRequest, result interface{}, opts ...RequestOption) error {
+	req = withOperationName(req)
+	options := resolveOptions(ctx, opts)
+	if window := c.staleWindow(options); window > 0 {
+		if key := c.dedupeKey(ctx, req, options); key != "" {
+			return c.doStaleWhileError(ctx, key, window, req, result, opts, options)
+		}
+	}
+
+	return c.doFresh(ctx, req, result, opts)
 }
 
-// Do executes a GraphQL request
-func (c *BaseClient) Do(ctx context.Context, req *GraphQLRequest, result interface{}) error {
-	// Rate limiting
-	if c.RateLimiter != nil {
-		if err := c.RateLimiter.Wait(ctx); err != nil {
-			return err
+// doFresh executes a GraphQL request, sharing it with identical queries in
+// flight if DeduplicateQueries is set
+func (c *BaseClient) doFresh(ctx context.Context, req *GraphQLRequest, result interface{}, opts []RequestOption) error {
+	if c.DeduplicateQueries {
+		if key := c.dedupeKey(ctx, req, resolveOptions(ctx, opts)); key != "" {
+			return c.doShared(ctx, key, req, result, opts)
+		}
+	}
+
+	return c.do(ctx, req, opts, func(body io.Reader, ext *responseExtensions) error {
+		if c.OnUnexpectedNull != nil && result != nil {
+			return c.decodeChecked(req, body, result, ext)
 		}
+		return c.decodeResponse(body, result, ext)
+	})
+}
+
+// do executes a GraphQL request and calls decode with the body of a
+// successful HTTP response. decode fills in the extensions of the response
+// it reads.
+func (c *BaseClient) do(ctx context.Context, req *GraphQLRequest, opts []RequestOption, decode func(io.Reader, *responseExtensions) error) (err error) {
+	req = withOperationName(req)
+	// Attempts made and rejected for exceeding the rate limit
+	var sent, rateLimited int
+	if c.OnRequest != nil || c.Logger != nil {
+		start, reqCtx := time.Now(), ctx
+		defer func() {
+			c.observe(reqCtx, req, RequestEvent{
+				OperationName: req.OperationName,
+				Duration:      time.Since(start),
+				Err:           err,
+				Attempts:      sent,
+				RateLimited:   rateLimited,
+			})
+		}()
+	}
+
+	if c.closed() {
+		return errors.ErrClientClosed
+	}
+
+	// Response to the last attempt and the reader of its body
+	var resp *http.Response
+	var respReader *responseReader
+
+	options := resolveOptions(ctx, opts)
+	if options.responseInfo != nil {
+		info, start := options.responseInfo, time.Now()
+		defer func() {
+			info.fill(resp, respReader, sent, time.Since(start))
+		}()
+	}
+	if err := c.checkActingUser(options); err != nil {
+		return err
+	}
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	// Rate limiting, charging the request its cost
+	cost := c.requestCost(req, options)
+	if err := c.waitRateLimit(ctx, req.OperationName, cost); err != nil {
+		return err
 	}
-	
+
+	c.Guardrails.checkComplexity(req)
+
 	// Marshal request
-	body, err := json.Marshal(req)
-	if err != nil {
+	body := newRequestBody()
+	defer body.release()
+	if err := encodeRequest(body.buf, req, c.jsonCodec().Marshal); err != nil {
 		return errors.WrapError(err, "failed to marshal request")
 	}
-	
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.APIURL, bytes.NewReader(body))
-	if err != nil {
-		return errors.WrapError(err, "failed to create request")
+
+	// Execute request, retrying only operations that are safe to repeat
+	attempts := 1
+	if c.retryable(req, options) {
+		attempts = maxAttempts
 	}
-	
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	
-	if c.OrganizationID != "" {
-		httpReq.Header.Set("X-Upwork-API-TenantId", c.OrganizationID)
-	}
-	
-	// Execute request with retry
-	var resp *http.Response
-	for attempt := 0; attempt < 3; attempt++ {
-		resp, err = c.HTTPClient.Do(httpReq)
+
+	var sendErr error
+	attempt := 1
+	for retryStart := time.Now(); ; attempt++ {
+		// Sending a request consumes its body, so each attempt sends a new one
+		httpReq, err := c.newHTTPRequest(ctx, body, options)
 		if err != nil {
-			if attempt < 2 && isRetryableError(err) {
-				time.Sleep(time.Duration(attempt+1) * time.Second)
-				continue
-			}
-			return errors.WrapError(err, "request failed")
+			return withAttempts(err, attempt)
+		}
+
+		resp, sendErr = c.HTTPClient.Do(httpReq)
+		sent = attempt
+		if sendErr == nil && resp.StatusCode == http.StatusTooManyRequests {
+			rateLimited++
+		}
+
+		retry := sendErr != nil && isRetryableError(sendErr) || sendErr == nil && retryableStatus(resp.StatusCode)
+		delay := retryDelay(attempt, resp)
+		if !retry || attempt == attempts || !c.canRetry(ctx, retryStart, delay) {
+			break
+		}
+
+		if sendErr == nil {
+			c.observeQuota(req.OperationName, resp)
+			discardBody(resp)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return withAttempts(err, attempt)
 		}
-		break
+	}
+	if sendErr != nil {
+		return withAttempts(errors.WrapError(sendErr, "request failed"), attempt)
 	}
 	defer resp.Body.Close()
-	
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	c.observeQuota(req.OperationName, resp)
+
+	respReader, err = c.Guardrails.newResponseReader(resp)
 	if err != nil {
-		return errors.WrapError(err, "failed to read response")
+		return err
 	}
-	
+
 	// Check HTTP status
 	if resp.StatusCode != http.StatusOK {
-		return c.handleHTTPError(resp.StatusCode, respBody)
+		respBody, err := io.ReadAll(respReader)
+		if err != nil {
+			return errors.WrapError(err, "failed to read response")
+		}
+		return withAttempts(c.handleHTTPError(resp, respBody), attempt)
+	}
+
+	var ext responseExtensions
+	err = respReader.limitError(decode(respReader, &ext))
+	c.Guardrails.checkResponseSize(req, respReader)
+	c.settleCost(cost, &ext)
+	if deprecated := c.reportDeprecations(ctx, req.OperationName, ext.Warnings); err == nil {
+		err = deprecated
+	}
+	return err
+}
+
+// Header values shared by every request. Their capacity is their length,
+// so a transport adding a value copies them rather than appending in place.
+var (
+	headerValueJSON = []string{"application/json"}[:1:1]
+	headerTenantID  = http.CanonicalHeaderKey("X-Upwork-API-TenantId")
+)
+
+// newHTTPRequest creates the HTTP request sending a GraphQL request body
+// with the client's and the options' headers
+func (c *BaseClient) newHTTPRequest(ctx context.Context, body *requestBody, options *requestOptions) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.APIURL, nil)
+	if err != nil {
+		return nil, errors.WrapError(err, "failed to create request")
+	}
+	if httpReq.Body, err = body.reader(); err != nil {
+		return nil, errors.WrapError(err, "failed to create request")
+	}
+	httpReq.GetBody = body.reader
+	httpReq.ContentLength = int64(body.len())
+
+	// Set headers
+	httpReq.Header["Content-Type"] = headerValueJSON
+	httpReq.Header["Accept"] = headerValueJSON
+
+	if orgID := c.organizationID(ctx); orgID != "" {
+		httpReq.Header[headerTenantID] = c.tenantHeaderValue(orgID)
+	}
+
+	options.apply(httpReq)
+
+	return httpReq, nil
+}
+
+// tenantHeaderValue returns the X-Upwork-API-TenantId header value for
+// orgID, reusing the last one built since requests mostly share a tenant
+func (c *BaseClient) tenantHeaderValue(orgID string) []string {
+	if value := c.tenantHeader.Load(); value != nil && (*value)[0] == orgID {
+		return *value
 	}
-	
-	// Parse GraphQL response
-	var graphqlResp GraphQLResponse
-	if err := json.Unmarshal(respBody, &graphqlResp); err != nil {
+	value := []string{orgID}[:1:1]
+	c.tenantHeader.Store(&value)
+	return value
+}
+
+// jsonCodec returns the codec requests and responses are encoded with
+func (c *BaseClient) jsonCodec() codec.Codec {
+	if c.Codec != nil {
+		return c.Codec
+	}
+	return codec.Default()
+}
+
+// decodeResponse decodes a GraphQL response from body, unmarshaling data
+// straight into result, if provided, instead of buffering it, and
+// extensions into ext
+func (c *BaseClient) decodeResponse(body io.Reader, result interface{}, ext *responseExtensions) error {
+	graphqlResp := struct {
+		Data       interface{}           `json:"data"`
+		Errors     []errors.GraphQLError `json:"errors"`
+		Extensions *responseExtensions   `json:"extensions"`
+	}{Extensions: ext}
+	if result != nil {
+		graphqlResp.Data = result
+	} else {
+		graphqlResp.Data = &json.RawMessage{}
+	}
+
+	if err := c.jsonCodec().NewDecoder(body).Decode(&graphqlResp); err != nil {
 		return errors.WrapError(err, "failed to parse response")
 	}
-	
+
 	// Check for GraphQL errors
 	if len(graphqlResp.Errors) > 0 {
 		return &errors.GraphQLErrors{Errors: graphqlResp.Errors}
 	}
-	
-	// Unmarshal data if result is provided
-	if result != nil && graphqlResp.Data != nil {
-		if err := json.Unmarshal(graphqlResp.Data, result); err != nil {
-			return errors.WrapError(err, "failed to unmarshal response data")
+
+	return nil
+}
+
+// BatchResult holds the outcome of each request in a batch
+type BatchResult struct {
+	// Errors holds the error for each request, nil for requests that succeeded
+	Errors []error
+}
+
+// Succeeded returns true if the request at index succeeded
+func (r *BatchResult) Succeeded(index int) bool {
+	return index >= 0 && index < len(r.Errors) && r.Errors[index] == nil
+}
+
+// Failed returns the indexes of the requests that failed
+func (r *BatchResult) Failed() []int {
+	var failed []int
+	for i, err := range r.Errors {
+		if err != nil {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// Err returns a *errors.MultiError aggregating every failed request, or nil
+// if all requests succeeded
+func (r *BatchResult) Err() error {
+	var errs []*errors.IndexedError
+	for i, err := range r.Errors {
+		if err != nil {
+			errs = append(errs, &errors.IndexedError{Index: i, Err: err})
 		}
 	}
-	
-	return nil
+	if len(errs) == 0 {
+		return nil
+	}
+	return &errors.MultiError{Errors: errs}
 }
 
-// DoBatch executes multiple GraphQL requests in a single HTTP request
-func (c *BaseClient) DoBatch(ctx context.Context, requests []*GraphQLRequest, results []interface{}) error {
+// DoBatch executes multiple GraphQL requests in a single HTTP request.
+// Every successful sub-response is unmarshaled into its result even if other
+// requests in the batch failed. If the HTTP request itself fails, the
+// BatchResult is nil. Otherwise the returned error is BatchResult.Err().
+func (c *BaseClient) DoBatch(ctx context.Context, requests []*GraphQLRequest, results []interface{}, opts ...RequestOption) (result *BatchResult, err error) {
 	if len(requests) != len(results) {
-		return fmt.Errorf("requests and results arrays must have the same length")
+		return nil, fmt.Errorf("requests and results arrays must have the same length")
 	}
-	
-	// Rate limiting
-	if c.RateLimiter != nil {
-		if err := c.RateLimiter.Wait(ctx); err != nil {
-			return err
-		}
+
+	named := make([]*GraphQLRequest, len(requests))
+	for i, req := range requests {
+		named[i] = withOperationName(req)
+	}
+	requests = named
+
+	// HTTP status of the batch, once it has been sent
+	var status int
+	if c.OnRequest != nil || c.Logger != nil {
+		start, reqCtx := time.Now(), ctx
+		defer func() {
+			c.observeBatch(reqCtx, requests, result, err, time.Since(start), status)
+		}()
+	}
+
+	if c.closed() {
+		return nil, errors.ErrClientClosed
+	}
+
+	// Response to the batch and the reader of its body
+	var resp *http.Response
+	var respReader *responseReader
+
+	options := resolveOptions(ctx, opts)
+	if options.responseInfo != nil {
+		info, start := options.responseInfo, time.Now()
+		defer func() {
+			// A batch is sent at most once
+			var attempts int
+			if status != 0 {
+				attempts = 1
+			}
+			info.fill(resp, respReader, attempts, time.Since(start))
+		}()
+	}
+	if err := c.checkActingUser(options); err != nil {
+		return nil, err
 	}
-	
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	// Rate limiting, charging the batch the cost of all its requests
+	var cost int
+	for _, req := range requests {
+		cost += c.requestCost(req, options)
+	}
+	if err := c.waitRateLimit(ctx, "", cost); err != nil {
+		return nil, err
+	}
+
+	for _, req := range requests {
+		c.Guardrails.checkComplexity(req)
+	}
+
 	// Marshal batch request
-	body, err := json.Marshal(requests)
-	if err != nil {
-		return errors.WrapError(err, "failed to marshal batch request")
+	body := newRequestBody()
+	defer body.release()
+	body.buf.WriteByte('[')
+	for i, req := range requests {
+		if i > 0 {
+			body.buf.WriteByte(',')
+		}
+		if err := encodeRequest(body.buf, req, c.jsonCodec().Marshal); err != nil {
+			return nil, errors.WrapError(err, "failed to marshal batch request")
+		}
 	}
-	
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.APIURL, bytes.NewReader(body))
+	body.buf.WriteByte(']')
+
+	httpReq, err := c.newHTTPRequest(ctx, body, options)
 	if err != nil {
-		return errors.WrapError(err, "failed to create request")
-	}
-	
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	
-	if c.OrganizationID != "" {
-		httpReq.Header.Set("X-Upwork-API-TenantId", c.OrganizationID)
+		return nil, err
 	}
-	
+
 	// Execute request
-	resp, err := c.HTTPClient.Do(httpReq)
+	resp, err = c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return errors.WrapError(err, "batch request failed")
+		return nil, errors.WrapError(err, "batch request failed")
 	}
 	defer resp.Body.Close()
-	
+	status = resp.StatusCode
+	c.observeQuota("", resp)
+
+	respReader, err = c.Guardrails.newResponseReader(resp)
+	if err != nil {
+		return nil, err
+	}
+
 	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(respReader)
 	if err != nil {
-		return errors.WrapError(err, "failed to read response")
+		return nil, errors.WrapError(err, "failed to read response")
 	}
-	
+	c.Guardrails.checkResponseSize(nil, respReader)
+
 	// Check HTTP status
 	if resp.StatusCode != http.StatusOK {
-		return c.handleHTTPError(resp.StatusCode, respBody)
+		return nil, c.handleHTTPError(resp, respBody)
 	}
-	
+
 	// Parse batch response
-	var batchResp []GraphQLResponse
-	if err := json.Unmarshal(respBody, &batchResp); err != nil {
-		return errors.WrapError(err, "failed to parse batch response")
+	var batchResp []struct {
+		GraphQLResponse
+		Extensions responseExtensions `json:"extensions"`
+	}
+	if err := c.jsonCodec().Unmarshal(respBody, &batchResp); err != nil {
+		return nil, errors.WrapError(err, "failed to parse batch response")
 	}
-	
-	// Process each response
-	for i, graphqlResp := range batchResp {
+
+	result = &BatchResult{Errors: make([]error, len(requests))}
+
+	// Process each response independently
+	for i := range requests {
+		if i >= len(batchResp) {
+			result.Errors[i] = fmt.Errorf("missing response in batch")
+			continue
+		}
+		graphqlResp := batchResp[i]
+		deprecated := c.reportDeprecations(ctx, requests[i].OperationName, graphqlResp.Extensions.Warnings)
+
 		// Check for GraphQL errors
 		if len(graphqlResp.Errors) > 0 {
-			return fmt.Errorf("request %d failed: %v", i, &errors.GraphQLErrors{Errors: graphqlResp.Errors})
+			result.Errors[i] = &errors.GraphQLErrors{Errors: graphqlResp.Errors}
+			continue
 		}
-		
+
 		// Unmarshal data if result is provided
 		if results[i] != nil && graphqlResp.Data != nil {
-			if err := json.Unmarshal(graphqlResp.Data, results[i]); err != nil {
-				return errors.WrapError(err, fmt.Sprintf("failed to unmarshal response %d", i))
+			c.checkShape(requests[i], graphqlResp.Data, results[i])
+			if err := c.jsonCodec().Unmarshal(graphqlResp.Data, results[i]); err != nil {
+				result.Errors[i] = errors.WrapError(err, "failed to unmarshal response data")
+				continue
 			}
 		}
+		result.Errors[i] = deprecated
 	}
-	
-	return nil
+
+	return result, result.Err()
 }
 
-// handleHTTPError handles HTTP error responses
-func (c *BaseClient) handleHTTPError(statusCode int, body []byte) error {
+// handleHTTPError handles HTTP error responses. 429 Too Many Requests is
+// returned as an errors.RateLimitError.
+func (c *BaseClient) handleHTTPError(resp *http.Response, body []byte) error {
 	apiErr := &errors.APIError{
-		StatusCode: statusCode,
-		Message:    http.StatusText(statusCode),
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
 	}
-	
+
 	// Try to parse error response
 	var errResp struct {
 		Error   string                 `json:"error"`
@@ -204,7 +712,7 @@ func (c *BaseClient) handleHTTPError(statusCode int, body []byte) error {
 		Code    string                 `json:"code"`
 		Details map[string]interface{} `json:"details"`
 	}
-	
+
 	if err := json.Unmarshal(body, &errResp); err == nil {
 		if errResp.Message != "" {
 			apiErr.Message = errResp.Message
@@ -214,7 +722,14 @@ func (c *BaseClient) handleHTTPError(statusCode int, body []byte) error {
 		apiErr.Code = errResp.Code
 		apiErr.Details = errResp.Details
 	}
-	
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &errors.RateLimitError{
+			APIError:   *apiErr,
+			RetryAfter: parseRetryAfter(resp.Header.Get(headerRetryAfter), time.Now()),
+		}
+	}
+
 	return apiErr
 }
 
@@ -223,4 +738,3 @@ func isRetryableError(err error) bool {
 	// Implement retry logic for specific errors
 	return errors.IsRetryable(err)
 }
-
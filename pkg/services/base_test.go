@@ -3,29 +3,43 @@ package services
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	stderrors "errors"
 	"net/http"
 	"testing"
 	"time"
 
-	"github.com/rizome-dev/go-upwork/internal/graphql"
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	upworkErrors "github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 	"github.com/rizome-dev/go-upwork/tests/mocks"
 	"github.com/rizome-dev/go-upwork/tests/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// newRecordedClient returns a client sending its requests to recorder
+func newRecordedClient(recorder *mocks.RequestRecorder, rateLimiter RateLimiter) *BaseClient {
+	return &BaseClient{
+		HTTPClient:  &http.Client{Transport: recorder},
+		APIURL:      "https://api.upwork.com/graphql",
+		RateLimiter: rateLimiter,
+	}
+}
+
+// mockJSON returns a mocked response with body encoded as JSON
+func mockJSON(statusCode int, body interface{}) mocks.MockResponse {
+	b, _ := json.Marshal(body)
+	return mocks.MockResponse{StatusCode: statusCode, Body: string(b)}
+}
+
 func TestNewBaseService(t *testing.T) {
-	mockClient, _ := graphql.NewClient("https://api.upwork.com/graphql", nil)
 	rateLimiter := mocks.NewMockRateLimiter()
+	client := newRecordedClient(mocks.NewRequestRecorder(), rateLimiter)
+
+	service := NewContractsService(client)
 
-	service := NewBaseService(mockClient, rateLimiter)
-	
 	assert.NotNil(t, service)
-	assert.Equal(t, mockClient, service.client)
-	assert.Equal(t, rateLimiter, service.rateLimiter)
+	assert.Same(t, client, service.client)
+	assert.Equal(t, rateLimiter, service.client.RateLimiter)
 }
 
 func TestExecuteQuery(t *testing.T) {
@@ -33,7 +47,7 @@ func TestExecuteQuery(t *testing.T) {
 		name         string
 		query        string
 		variables    map[string]interface{}
-		mockResponse *http.Response
+		mockResponse mocks.MockResponse
 		mockError    error
 		rateLimitErr error
 		wantErr      bool
@@ -45,7 +59,7 @@ func TestExecuteQuery(t *testing.T) {
 			variables: map[string]interface{}{
 				"id": "123",
 			},
-			mockResponse: testutils.MockHTTPResponse(200, testutils.MockGraphQLResponse(
+			mockResponse: mockJSON(200, testutils.MockGraphQLResponse(
 				map[string]interface{}{
 					"user": map[string]interface{}{
 						"id":   "123",
@@ -63,15 +77,15 @@ func TestExecuteQuery(t *testing.T) {
 			},
 		},
 		{
-			name:  "rate limit error",
-			query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
-			rateLimitErr: errors.New("rate limit exceeded"),
-			wantErr: true,
+			name:         "rate limit error",
+			query:        `query GetUser($id: ID!) { user(id: $id) { id name } }`,
+			rateLimitErr: stderrors.New("rate limit exceeded"),
+			wantErr:      true,
 		},
 		{
 			name:  "GraphQL error",
 			query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
-			mockResponse: testutils.MockHTTPResponse(200, testutils.MockGraphQLResponse(
+			mockResponse: mockJSON(200, testutils.MockGraphQLResponse(
 				nil,
 				[]interface{}{
 					testutils.CreateGraphQLError("User not found", "NOT_FOUND"),
@@ -82,7 +96,7 @@ func TestExecuteQuery(t *testing.T) {
 		{
 			name:  "HTTP error with retry",
 			query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
-			mockResponse: testutils.MockHTTPResponse(500, map[string]interface{}{
+			mockResponse: mockJSON(500, map[string]interface{}{
 				"error": "Internal Server Error",
 			}),
 			wantErr: true,
@@ -90,7 +104,7 @@ func TestExecuteQuery(t *testing.T) {
 		{
 			name:      "network error",
 			query:     `query GetUser($id: ID!) { user(id: $id) { id name } }`,
-			mockError: errors.New("network error"),
+			mockError: stderrors.New("network error"),
 			wantErr:   true,
 		},
 	}
@@ -98,12 +112,8 @@ func TestExecuteQuery(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			recorder := mocks.NewRequestRecorder()
-			if tt.mockResponse != nil {
-				body, _ := json.Marshal(tt.mockResponse.Body)
-				recorder.Responses = append(recorder.Responses, mocks.MockResponse{
-					StatusCode: tt.mockResponse.StatusCode,
-					Body:       string(body),
-				})
+			if tt.mockResponse.StatusCode != 0 {
+				recorder.Responses = append(recorder.Responses, tt.mockResponse)
 			}
 			if tt.mockError != nil {
 				recorder.Responses = append(recorder.Responses, mocks.MockResponse{
@@ -111,19 +121,16 @@ func TestExecuteQuery(t *testing.T) {
 				})
 			}
 
-			client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
-
 			rateLimiter := mocks.NewMockRateLimiter()
 			if tt.rateLimitErr != nil {
 				rateLimiter.ShouldError = true
 				rateLimiter.Error = tt.rateLimitErr
 			}
 
-			service := NewBaseService(client, rateLimiter)
+			client := newRecordedClient(recorder, rateLimiter)
 
 			var result map[string]interface{}
-			err = service.ExecuteQuery(context.Background(), tt.query, tt.variables, &result)
+			err := client.Do(context.Background(), &GraphQLRequest{Query: tt.query, Variables: tt.variables}, &result)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -143,7 +150,7 @@ func TestExecuteMutation(t *testing.T) {
 		name         string
 		mutation     string
 		variables    map[string]interface{}
-		mockResponse *http.Response
+		mockResponse mocks.MockResponse
 		wantErr      bool
 		expectedData map[string]interface{}
 	}{
@@ -155,7 +162,7 @@ func TestExecuteMutation(t *testing.T) {
 					"name": "Jane Doe",
 				},
 			},
-			mockResponse: testutils.MockHTTPResponse(200, testutils.MockGraphQLResponse(
+			mockResponse: mockJSON(200, testutils.MockGraphQLResponse(
 				map[string]interface{}{
 					"createUser": map[string]interface{}{
 						"id":   "456",
@@ -178,7 +185,7 @@ func TestExecuteMutation(t *testing.T) {
 			variables: map[string]interface{}{
 				"input": map[string]interface{}{},
 			},
-			mockResponse: testutils.MockHTTPResponse(200, testutils.MockGraphQLResponse(
+			mockResponse: mockJSON(200, testutils.MockGraphQLResponse(
 				nil,
 				[]interface{}{
 					testutils.CreateGraphQLError("Name is required", "VALIDATION_ERROR"),
@@ -190,22 +197,11 @@ func TestExecuteMutation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			recorder := mocks.NewRequestRecorder()
-			if tt.mockResponse != nil {
-				body, _ := json.Marshal(tt.mockResponse.Body)
-				recorder.Responses = append(recorder.Responses, mocks.MockResponse{
-					StatusCode: tt.mockResponse.StatusCode,
-					Body:       string(body),
-				})
-			}
-
-			client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
-
-			service := NewBaseService(client, mocks.NewMockRateLimiter())
+			recorder := mocks.NewRequestRecorder(tt.mockResponse)
+			client := newRecordedClient(recorder, mocks.NewMockRateLimiter())
 
 			var result map[string]interface{}
-			err = service.ExecuteMutation(context.Background(), tt.mutation, tt.variables, &result)
+			err := client.Do(context.Background(), &GraphQLRequest{Query: tt.mutation, Variables: tt.variables}, &result)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -286,6 +282,7 @@ func TestRetryLogic(t *testing.T) {
 				{
 					StatusCode: 429,
 					Body:       `{"error": "Rate limit exceeded"}`,
+					Headers:    http.Header{"Retry-After": []string{"1"}},
 				},
 				{
 					StatusCode: 200,
@@ -300,14 +297,10 @@ func TestRetryLogic(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			recorder := mocks.NewRequestRecorder(tt.responses...)
-			
-			client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
-
-			service := NewBaseService(client, mocks.NewMockRateLimiter())
+			client := newRecordedClient(recorder, mocks.NewMockRateLimiter())
 
 			var result map[string]interface{}
-			err = service.ExecuteQuery(context.Background(), `{ test }`, nil, &result)
+			err := client.Do(context.Background(), &GraphQLRequest{Query: `{ test }`}, &result)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -347,12 +340,9 @@ func TestBatchRequest(t *testing.T) {
 		},
 	)
 
-	client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-	require.NoError(t, err)
-
-	service := NewBaseService(client, mocks.NewMockRateLimiter())
+	client := newRecordedClient(recorder, mocks.NewMockRateLimiter())
 
-	requests := []graphql.Request{
+	requests := []*GraphQLRequest{
 		{
 			Query: `query GetUser($id: ID!) { user(id: $id) { id name } }`,
 			Variables: map[string]interface{}{
@@ -364,19 +354,16 @@ func TestBatchRequest(t *testing.T) {
 		},
 	}
 
-	responses, err := service.ExecuteBatchRequest(context.Background(), requests)
+	var userData, contractData map[string]interface{}
+	result, err := client.DoBatch(context.Background(), requests, []interface{}{&userData, &contractData})
 	require.NoError(t, err)
-	assert.Len(t, responses, 2)
+	assert.Empty(t, result.Failed())
 
 	// Verify first response
-	userData, ok := responses[0].Data.(map[string]interface{})
-	require.True(t, ok)
 	user := userData["user"].(map[string]interface{})
 	assert.Equal(t, "123", user["id"])
 
 	// Verify second response
-	contractData, ok := responses[1].Data.(map[string]interface{})
-	require.True(t, ok)
 	contracts := contractData["contracts"].([]interface{})
 	assert.Len(t, contracts, 2)
 }
@@ -429,24 +416,12 @@ func TestPaginatedQuery(t *testing.T) {
 		nil,
 	)
 
-	body1, _ := json.Marshal(firstPageResponse)
-	body2, _ := json.Marshal(secondPageResponse)
-
 	recorder := mocks.NewRequestRecorder(
-		mocks.MockResponse{
-			StatusCode: 200,
-			Body:       string(body1),
-		},
-		mocks.MockResponse{
-			StatusCode: 200,
-			Body:       string(body2),
-		},
+		mockJSON(200, firstPageResponse),
+		mockJSON(200, secondPageResponse),
 	)
 
-	client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-	require.NoError(t, err)
-
-	service := NewBaseService(client, mocks.NewMockRateLimiter())
+	client := newRecordedClient(recorder, mocks.NewMockRateLimiter())
 
 	// Test paginated query collection
 	query := `
@@ -485,7 +460,7 @@ func TestPaginatedQuery(t *testing.T) {
 			variables["after"] = cursor
 		}
 
-		err := service.ExecuteQuery(context.Background(), query, variables, &result)
+		err := client.Do(context.Background(), &GraphQLRequest{Query: query, Variables: variables}, &result)
 		require.NoError(t, err)
 
 		for _, edge := range result.Contracts.Edges {
@@ -510,102 +485,91 @@ func TestContextCancellation(t *testing.T) {
 	rateLimiter := mocks.NewMockRateLimiter()
 	rateLimiter.WaitDuration = 100 * time.Millisecond
 
-	client, err := graphql.NewClient("https://api.upwork.com/graphql", nil)
-	require.NoError(t, err)
-
-	service := NewBaseService(client, rateLimiter)
+	client := newRecordedClient(mocks.NewRequestRecorder(), rateLimiter)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
 	var result map[string]interface{}
-	err = service.ExecuteQuery(ctx, `{ test }`, nil, &result)
-	
+	err := client.Do(ctx, &GraphQLRequest{Query: `{ test }`}, &result)
+
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context canceled")
 }
 
 func TestErrorWrapping(t *testing.T) {
 	tests := []struct {
-		name          string
-		mockResponse  *http.Response
-		expectedError error
-		checkType     func(error) bool
+		name         string
+		mockResponse mocks.MockResponse
+		checkType    func(error) bool
 	}{
 		{
 			name: "rate limit error",
-			mockResponse: testutils.MockHTTPResponse(429, map[string]interface{}{
+			mockResponse: mockJSON(429, map[string]interface{}{
 				"error": "Rate limit exceeded",
 			}),
 			checkType: func(err error) bool {
 				var rateLimitErr *upworkErrors.RateLimitError
-				return errors.As(err, &rateLimitErr)
+				return stderrors.As(err, &rateLimitErr)
 			},
 		},
 		{
 			name: "authentication error",
-			mockResponse: testutils.MockHTTPResponse(401, map[string]interface{}{
+			mockResponse: mockJSON(401, map[string]interface{}{
 				"error": "Unauthorized",
 			}),
 			checkType: func(err error) bool {
-				var authErr *upworkErrors.AuthenticationError
-				return errors.As(err, &authErr)
+				var apiErr *upworkErrors.APIError
+				return stderrors.As(err, &apiErr) && apiErr.IsUnauthorized()
 			},
 		},
 		{
 			name: "permission error",
-			mockResponse: testutils.MockHTTPResponse(403, map[string]interface{}{
+			mockResponse: mockJSON(403, map[string]interface{}{
 				"error": "Forbidden",
 			}),
 			checkType: func(err error) bool {
-				var permErr *upworkErrors.PermissionError
-				return errors.As(err, &permErr)
+				var apiErr *upworkErrors.APIError
+				return stderrors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
 			},
 		},
 		{
 			name: "validation error",
-			mockResponse: testutils.MockHTTPResponse(400, map[string]interface{}{
+			mockResponse: mockJSON(400, map[string]interface{}{
 				"error": "Bad Request",
 			}),
 			checkType: func(err error) bool {
-				var valErr *upworkErrors.ValidationError
-				return errors.As(err, &valErr)
+				var apiErr *upworkErrors.APIError
+				return stderrors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest
 			},
 		},
 		{
 			name: "server error",
-			mockResponse: testutils.MockHTTPResponse(500, map[string]interface{}{
+			mockResponse: mockJSON(500, map[string]interface{}{
 				"error": "Internal Server Error",
 			}),
 			checkType: func(err error) bool {
-				var serverErr *upworkErrors.ServerError
-				return errors.As(err, &serverErr)
+				var apiErr *upworkErrors.APIError
+				return stderrors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			body, _ := json.Marshal(tt.mockResponse.Body)
-			recorder := mocks.NewRequestRecorder(
-				mocks.MockResponse{
-					StatusCode: tt.mockResponse.StatusCode,
-					Body:       string(body),
-				},
-			)
-
-			client, err := graphql.NewClient("https://api.upwork.com/graphql", recorder)
-			require.NoError(t, err)
+			recorder := mocks.NewRequestRecorder(tt.mockResponse)
 
-			service := NewBaseService(client, mocks.NewMockRateLimiter())
+			client := newRecordedClient(recorder, mocks.NewMockRateLimiter())
+			// Return the error of the first attempt rather than retrying
+			client.MaxRetryDuration = time.Nanosecond
 
 			var result map[string]interface{}
-			err = service.ExecuteQuery(context.Background(), `{ test }`, nil, &result)
-			
+			err := client.Do(context.Background(), &GraphQLRequest{Query: `{ test }`}, &result)
+
 			assert.Error(t, err)
 			if tt.checkType != nil {
 				assert.True(t, tt.checkType(err), "Error should be of expected type")
 			}
 		})
 	}
-}
\ No newline at end of file
+}
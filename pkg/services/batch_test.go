@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestDoBatchPartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"data": {"name": "first"}},
+			{"errors": [{"message": "not allowed"}]},
+			{"data": {"name": "third"}}
+		]`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	type named struct {
+		Name string `json:"name"`
+	}
+	var first, second, third named
+
+	requests := []*GraphQLRequest{{Query: "query { a }"}, {Query: "query { b }"}, {Query: "query { c }"}}
+	result, err := client.DoBatch(context.Background(), requests, []interface{}{&first, &second, &third})
+	require.Error(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, "first", first.Name)
+	assert.Empty(t, second.Name)
+	assert.Equal(t, "third", third.Name)
+
+	assert.True(t, result.Succeeded(0))
+	assert.False(t, result.Succeeded(1))
+	assert.Equal(t, []int{1}, result.Failed())
+
+	var gqlErrs *errors.GraphQLErrors
+	require.True(t, stderrors.As(err, &gqlErrs))
+	assert.Equal(t, "not allowed", gqlErrs.Errors[0].Message)
+
+	var multi *errors.MultiError
+	require.True(t, stderrors.As(err, &multi))
+	assert.Equal(t, 1, multi.Errors[0].Index)
+}
+
+func TestDoBatchMissingResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"data": {}}]`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	requests := []*GraphQLRequest{{Query: "query { a }"}, {Query: "query { b }"}}
+	result, err := client.DoBatch(context.Background(), requests, make([]interface{}, 2))
+	require.Error(t, err)
+	assert.Equal(t, []int{1}, result.Failed())
+}
+
+func TestDoBatchHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	result, err := client.DoBatch(context.Background(), []*GraphQLRequest{{Query: "query { a }"}}, make([]interface{}, 1))
+	require.Error(t, err)
+	assert.Nil(t, result)
+
+	var apiErr *errors.APIError
+	require.True(t, stderrors.As(err, &apiErr))
+	assert.True(t, apiErr.IsUnauthorized())
+}
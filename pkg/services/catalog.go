@@ -0,0 +1,547 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// CatalogService handles Project Catalog API operations: the fixed-scope
+// projects a freelancer lists for clients to buy, and the orders placed on
+// them
+type CatalogService struct {
+	client *BaseClient
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(client *BaseClient) *CatalogService {
+	return &CatalogService{client: client}
+}
+
+// CatalogProjectStatus represents the status of a catalog project
+type CatalogProjectStatus string
+
+const (
+	CatalogProjectStatusDraft       CatalogProjectStatus = "DRAFT"
+	CatalogProjectStatusUnderReview CatalogProjectStatus = "UNDER_REVIEW"
+	CatalogProjectStatusActive      CatalogProjectStatus = "ACTIVE"
+	CatalogProjectStatusPaused      CatalogProjectStatus = "PAUSED"
+	CatalogProjectStatusRejected    CatalogProjectStatus = "REJECTED"
+	CatalogProjectStatusUnknown     CatalogProjectStatus = "UNKNOWN"
+)
+
+// ProjectTierName names one of the pricing tiers of a catalog project
+type ProjectTierName string
+
+const (
+	ProjectTierStarter  ProjectTierName = "STARTER"
+	ProjectTierStandard ProjectTierName = "STANDARD"
+	ProjectTierAdvanced ProjectTierName = "ADVANCED"
+	ProjectTierUnknown  ProjectTierName = "UNKNOWN"
+)
+
+// CatalogOrderStatus represents the status of an order on a catalog project
+type CatalogOrderStatus string
+
+const (
+	CatalogOrderStatusActive    CatalogOrderStatus = "ACTIVE"
+	CatalogOrderStatusDelivered CatalogOrderStatus = "DELIVERED"
+	CatalogOrderStatusCompleted CatalogOrderStatus = "COMPLETED"
+	CatalogOrderStatusCancelled CatalogOrderStatus = "CANCELLED"
+	CatalogOrderStatusUnknown   CatalogOrderStatus = "UNKNOWN"
+)
+
+// CatalogProject represents a project listed in the Project Catalog
+type CatalogProject struct {
+	ID               models.ID            `json:"id"`
+	Title            string               `json:"title"`
+	Description      string               `json:"description"`
+	Status           CatalogProjectStatus `json:"status"`
+	CategoryID       models.ID            `json:"categoryId"`
+	Skills           []models.Skill       `json:"skills"`
+	Tiers            []ProjectTier        `json:"tiers"`
+	OrderCount       int                  `json:"orderCount"`
+	CreatedDateTime  models.DateTime      `json:"createdDateTime"`
+	ModifiedDateTime models.DateTime      `json:"modifiedDateTime"`
+}
+
+// Tier returns the project's tier with the given name, or nil if the
+// project does not offer it
+func (p CatalogProject) Tier(name ProjectTierName) *ProjectTier {
+	for i := range p.Tiers {
+		if p.Tiers[i].Name == name {
+			return &p.Tiers[i]
+		}
+	}
+	return nil
+}
+
+// ProjectTier represents the scope and price of one tier of a catalog
+// project
+type ProjectTier struct {
+	Name        ProjectTierName `json:"name"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Price       models.Money    `json:"price"`
+	// DeliveryDays is how long the freelancer has to deliver an order
+	DeliveryDays int `json:"deliveryDays"`
+	// Revisions is the number of revisions included; -1 means unlimited
+	Revisions int `json:"revisions"`
+}
+
+// validateProjectTiers checks that there are one to three tiers, each named
+// once, with a positive price in a single currency and a delivery time
+func validateProjectTiers(tiers []ProjectTier) error {
+	if len(tiers) == 0 {
+		return &errors.ValidationError{Field: "tiers", Message: "at least one tier is required"}
+	}
+	if len(tiers) > len(ProjectTierName("").Values()) {
+		return &errors.ValidationError{Field: "tiers", Message: "at most three tiers are allowed"}
+	}
+
+	seen := make(map[ProjectTierName]bool, len(tiers))
+	for i, tier := range tiers {
+		field := fmt.Sprintf("tiers[%d]", i)
+		if err := firstError(
+			required(field+".name", string(tier.Name)),
+			validateEnum(field+".name", tier.Name, tier.Name.IsValid()),
+			required(field+".title", tier.Title),
+			validateMoney(field+".price", tier.Price),
+		); err != nil {
+			return err
+		}
+		if seen[tier.Name] {
+			return &errors.ValidationError{Field: field + ".name", Message: "is used by another tier", Value: string(tier.Name)}
+		}
+		seen[tier.Name] = true
+
+		if tier.Price.Currency != tiers[0].Price.Currency {
+			return &errors.ValidationError{
+				Field:   field + ".price.currency",
+				Message: "must match the currency of the first tier",
+				Value:   tier.Price.Currency,
+			}
+		}
+		if tier.DeliveryDays <= 0 {
+			return &errors.ValidationError{Field: field + ".deliveryDays", Message: "must be greater than zero", Value: fmt.Sprint(tier.DeliveryDays)}
+		}
+		if tier.Revisions < -1 {
+			return &errors.ValidationError{Field: field + ".revisions", Message: "must be -1 for unlimited or at least zero", Value: fmt.Sprint(tier.Revisions)}
+		}
+	}
+	return nil
+}
+
+// CreateProjectInput represents a new catalog project. The project is
+// reviewed by Upwork before it is listed.
+type CreateProjectInput struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	CategoryID  string        `json:"categoryId"`
+	SkillIDs    []string      `json:"skillIds,omitempty"`
+	Tiers       []ProjectTier `json:"tiers"`
+}
+
+// Validate checks the title, description, category and tiers
+func (in CreateProjectInput) Validate() error {
+	return firstError(
+		required("title", in.Title),
+		required("description", in.Description),
+		required("categoryId", in.CategoryID),
+		validateProjectTiers(in.Tiers),
+	)
+}
+
+// ListMyProjectsInput represents input for listing the authenticated
+// freelancer's catalog projects
+type ListMyProjectsInput struct {
+	Status     []CatalogProjectStatus  `json:"status,omitempty"`
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+}
+
+// CatalogProjectList represents a paginated list of catalog projects
+type CatalogProjectList struct {
+	TotalCount int                  `json:"totalCount"`
+	PageInfo   models.PageInfo      `json:"pageInfo"`
+	Edges      []CatalogProjectEdge `json:"edges"`
+}
+
+// CatalogProjectEdge represents a catalog project edge in pagination
+type CatalogProjectEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   CatalogProject `json:"node"`
+}
+
+// CatalogOrder represents a client's purchase of a catalog project tier.
+// Each order runs as a fixed-price contract with its own milestones.
+type CatalogOrder struct {
+	ID         models.ID          `json:"id"`
+	Status     CatalogOrderStatus `json:"status"`
+	Project    CatalogProjectRef  `json:"project"`
+	Tier       ProjectTierName    `json:"tier"`
+	Client     models.User        `json:"client"`
+	Price      models.Money       `json:"price"`
+	ContractID models.ID          `json:"contractId"`
+	Milestones []Milestone        `json:"milestones"`
+	// DueDateTime is when delivery is due under the tier's delivery time
+	DueDateTime     *models.DateTime `json:"dueDateTime"`
+	CreatedDateTime models.DateTime  `json:"createdDateTime"`
+}
+
+// CatalogProjectRef identifies a catalog project
+type CatalogProjectRef struct {
+	ID    models.ID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// ListCatalogOrdersInput represents input for listing catalog orders
+type ListCatalogOrdersInput struct {
+	// ProjectID limits the orders to one project
+	ProjectID  string                  `json:"projectId,omitempty"`
+	Status     []CatalogOrderStatus    `json:"status,omitempty"`
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+}
+
+// CatalogOrderList represents a paginated list of catalog orders
+type CatalogOrderList struct {
+	TotalCount int                `json:"totalCount"`
+	PageInfo   models.PageInfo    `json:"pageInfo"`
+	Edges      []CatalogOrderEdge `json:"edges"`
+}
+
+// CatalogOrderEdge represents a catalog order edge in pagination
+type CatalogOrderEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   CatalogOrder `json:"node"`
+}
+
+// catalogProjectFields selects the fields of a CatalogProject
+const catalogProjectFields = `
+	id
+	title
+	description
+	status
+	categoryId
+	skills {
+		id
+		prettyName
+	}
+	tiers {
+		name
+		title
+		description
+		price {
+			rawValue
+			currency
+			displayValue
+		}
+		deliveryDays
+		revisions
+	}
+	orderCount
+	createdDateTime
+	modifiedDateTime
+`
+
+// catalogOrderFields selects the fields of a CatalogOrder
+const catalogOrderFields = `
+	id
+	status
+	project {
+		id
+		title
+	}
+	tier
+	client {
+		id
+		name
+	}
+	price {
+		rawValue
+		currency
+		displayValue
+	}
+	contractId
+	milestones {
+		id
+		description
+		dueDateTime
+		state
+		depositAmount {
+			rawValue
+			currency
+			displayValue
+		}
+		paid {
+			rawValue
+			currency
+			displayValue
+		}
+		sequenceId
+	}
+	dueDateTime
+	createdDateTime
+`
+
+// ListMyProjects returns the authenticated freelancer's catalog projects
+func (s *CatalogService) ListMyProjects(ctx context.Context, input ListMyProjectsInput) (*CatalogProjectList, error) {
+	for _, status := range input.Status {
+		if err := validateEnum("status", status, status.IsValid()); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		query ListMyCatalogProjects($filter: CatalogProjectFilter, $pagination: Pagination) {
+			myCatalogProjects(filter: $filter, pagination: $pagination) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + catalogProjectFields + `}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if len(input.Status) > 0 {
+		variables["filter"] = map[string]interface{}{"status_any": input.Status}
+	}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		MyCatalogProjects CatalogProjectList `json:"myCatalogProjects"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.MyCatalogProjects, nil
+}
+
+// CreateProject submits a new catalog project for review
+func (s *CatalogService) CreateProject(ctx context.Context, input CreateProjectInput) (*CatalogProject, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation CreateCatalogProject($input: CreateCatalogProjectInput!) {
+			createCatalogProject(input: $input) {` + catalogProjectFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		CreateCatalogProject CatalogProject `json:"createCatalogProject"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CreateCatalogProject, nil
+}
+
+// UpdateProjectTiers replaces the tiers, and so the pricing, of a catalog
+// project. Orders already placed keep the tier they were bought at.
+func (s *CatalogService) UpdateProjectTiers(ctx context.Context, projectID string, tiers []ProjectTier) (*CatalogProject, error) {
+	if err := firstError(
+		required("projectId", projectID),
+		validateProjectTiers(tiers),
+	); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation UpdateCatalogProjectTiers($input: UpdateCatalogProjectTiersInput!) {
+			updateCatalogProjectTiers(input: $input) {` + catalogProjectFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": map[string]interface{}{
+				"projectId": projectID,
+				"tiers":     tiers,
+			},
+		},
+	}
+
+	var resp struct {
+		UpdateCatalogProjectTiers CatalogProject `json:"updateCatalogProjectTiers"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.UpdateCatalogProjectTiers, nil
+}
+
+// PauseProject hides a catalog project from clients. Open orders are not
+// affected.
+func (s *CatalogService) PauseProject(ctx context.Context, projectID string) error {
+	return s.setProjectPaused(ctx, projectID, true)
+}
+
+// ResumeProject lists a paused catalog project again
+func (s *CatalogService) ResumeProject(ctx context.Context, projectID string) error {
+	return s.setProjectPaused(ctx, projectID, false)
+}
+
+// setProjectPaused pauses or resumes a catalog project
+func (s *CatalogService) setProjectPaused(ctx context.Context, projectID string, paused bool) error {
+	if err := required("projectId", projectID); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation SetCatalogProjectPaused($input: SetCatalogProjectPausedInput!) {
+			setCatalogProjectPaused(input: $input) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": map[string]interface{}{
+				"projectId": projectID,
+				"paused":    paused,
+			},
+		},
+	}
+
+	var resp struct {
+		SetCatalogProjectPaused struct {
+			Success bool `json:"success"`
+		} `json:"setCatalogProjectPaused"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.SetCatalogProjectPaused.Success {
+		if paused {
+			return fmt.Errorf("failed to pause project")
+		}
+		return fmt.Errorf("failed to resume project")
+	}
+
+	return nil
+}
+
+// ListCatalogOrders returns orders placed on the authenticated freelancer's
+// catalog projects, with their milestones
+func (s *CatalogService) ListCatalogOrders(ctx context.Context, input ListCatalogOrdersInput) (*CatalogOrderList, error) {
+	for _, status := range input.Status {
+		if err := validateEnum("status", status, status.IsValid()); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		query ListCatalogOrders($filter: CatalogOrderFilter, $pagination: Pagination) {
+			catalogOrders(filter: $filter, pagination: $pagination) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + catalogOrderFields + `}
+				}
+			}
+		}
+	`
+
+	filter := map[string]interface{}{}
+	if input.ProjectID != "" {
+		filter["projectId_eq"] = input.ProjectID
+	}
+	if len(input.Status) > 0 {
+		filter["status_any"] = input.Status
+	}
+
+	variables := map[string]interface{}{}
+	if len(filter) > 0 {
+		variables["filter"] = filter
+	}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		CatalogOrders CatalogOrderList `json:"catalogOrders"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CatalogOrders, nil
+}
+
+// GetCatalogOrder returns a catalog order by ID with its milestones
+func (s *CatalogService) GetCatalogOrder(ctx context.Context, orderID string) (*CatalogOrder, error) {
+	if err := required("orderId", orderID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetCatalogOrder($id: ID!) {
+			catalogOrder(id: $id) {` + catalogOrderFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": orderID,
+		},
+	}
+
+	var resp struct {
+		CatalogOrder CatalogOrder `json:"catalogOrder"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CatalogOrder, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestValidateProjectTiers(t *testing.T) {
+	starter := ProjectTier{Name: ProjectTierStarter, Title: "Basic", Price: models.MustMoney("100", "USD"), DeliveryDays: 3}
+	standard := ProjectTier{Name: ProjectTierStandard, Title: "Plus", Price: models.MustMoney("250", "USD"), DeliveryDays: 5, Revisions: -1}
+	assert.NoError(t, validateProjectTiers([]ProjectTier{starter, standard}))
+
+	assertValidationField(t, validateProjectTiers(nil), "tiers")
+	assertValidationField(t, validateProjectTiers([]ProjectTier{starter, starter}), "tiers[1].name")
+
+	euros := standard
+	euros.Price = models.MustMoney("250", "EUR")
+	assertValidationField(t, validateProjectTiers([]ProjectTier{starter, euros}), "tiers[1].price.currency")
+
+	instant := starter
+	instant.DeliveryDays = 0
+	assertValidationField(t, validateProjectTiers([]ProjectTier{instant}), "tiers[0].deliveryDays")
+
+	custom := starter
+	custom.Name = "PREMIUM"
+	assertValidationField(t, validateProjectTiers([]ProjectTier{custom}), "tiers[0].name")
+}
+
+func TestCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "updateCatalogProjectTiers"):
+			input := req.Variables["input"].(map[string]interface{})
+			assert.Equal(t, "project-1", input["projectId"])
+			assert.Len(t, input["tiers"], 1)
+			w.Write([]byte(`{"data":{"updateCatalogProjectTiers":{"id":"project-1","status":"ACTIVE","tiers":[
+				{"name":"STARTER","title":"Basic","price":{"rawValue":"120","currency":"USD"},"deliveryDays":3}
+			]}}}`))
+		case strings.Contains(req.Query, "setCatalogProjectPaused"):
+			assert.Equal(t, map[string]interface{}{"projectId": "project-1", "paused": true}, req.Variables["input"])
+			w.Write([]byte(`{"data":{"setCatalogProjectPaused":{"success":true}}}`))
+		case strings.Contains(req.Query, "catalogOrders"):
+			assert.Equal(t, map[string]interface{}{"projectId_eq": "project-1", "status_any": []interface{}{"ACTIVE"}}, req.Variables["filter"])
+			w.Write([]byte(`{"data":{"catalogOrders":{"totalCount":1,"edges":[{"node":{
+				"id":"order-1","status":"ACTIVE","tier":"STARTER","project":{"id":"project-1","title":"Logo design"},
+				"milestones":[{"id":"m-1","description":"Concepts","state":"ACTIVE","depositAmount":{"rawValue":"120","currency":"USD"}}]
+			}}]}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewCatalogService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	project, err := svc.UpdateProjectTiers(ctx, "project-1", []ProjectTier{
+		{Name: ProjectTierStarter, Title: "Basic", Price: models.MustMoney("120", "USD"), DeliveryDays: 3},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, project.Tier(ProjectTierStarter))
+	assert.Equal(t, "120.00 USD", project.Tier(ProjectTierStarter).Price.String())
+	assert.Nil(t, project.Tier(ProjectTierAdvanced))
+
+	require.NoError(t, svc.PauseProject(ctx, "project-1"))
+
+	orders, err := svc.ListCatalogOrders(ctx, ListCatalogOrdersInput{
+		ProjectID: "project-1",
+		Status:    []CatalogOrderStatus{CatalogOrderStatusActive},
+	})
+	require.NoError(t, err)
+	require.Len(t, orders.Edges, 1)
+	order := orders.Edges[0].Node
+	assert.Equal(t, "Logo design", order.Project.Title)
+	require.Len(t, order.Milestones, 1)
+	assert.Equal(t, "120.00 USD", order.Milestones[0].DepositAmount.String())
+
+	_, err = svc.CreateProject(ctx, CreateProjectInput{Title: "Logo design", Description: "Three concepts"})
+	assertValidationField(t, err, "categoryId")
+}
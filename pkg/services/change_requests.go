@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// ChangeRequestType represents the kind of change proposed for a contract
+type ChangeRequestType string
+
+const (
+	ChangeRequestTypeRateChange  ChangeRequestType = "RATE_CHANGE"
+	ChangeRequestTypeTermsChange ChangeRequestType = "TERMS_CHANGE"
+	ChangeRequestTypeUnknown     ChangeRequestType = "UNKNOWN"
+)
+
+// ChangeRequestStatus represents the status of a change request
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestStatusPending   ChangeRequestStatus = "PENDING"
+	ChangeRequestStatusAccepted  ChangeRequestStatus = "ACCEPTED"
+	ChangeRequestStatusDeclined  ChangeRequestStatus = "DECLINED"
+	ChangeRequestStatusCancelled ChangeRequestStatus = "CANCELLED"
+	ChangeRequestStatusExpired   ChangeRequestStatus = "EXPIRED"
+	ChangeRequestStatusUnknown   ChangeRequestStatus = "UNKNOWN"
+)
+
+// ChangeRequest represents a proposed change to the terms of a contract.
+// Proposed fields are nil when the request leaves them unchanged.
+type ChangeRequest struct {
+	ID                        models.ID           `json:"id"`
+	ContractID                models.ID           `json:"contractId"`
+	Type                      ChangeRequestType   `json:"type"`
+	Status                    ChangeRequestStatus `json:"status"`
+	Message                   string              `json:"message"`
+	RequestedBy               *models.User        `json:"requestedBy"`
+	CurrentHourlyRate         *models.Money       `json:"currentHourlyRate"`
+	ProposedHourlyRate        *models.Money       `json:"proposedHourlyRate"`
+	CurrentWeeklyHoursLimit   *int                `json:"currentWeeklyHoursLimit"`
+	ProposedWeeklyHoursLimit  *int                `json:"proposedWeeklyHoursLimit"`
+	ProposedManualTimeAllowed *bool               `json:"proposedManualTimeAllowed"`
+	EffectiveDateTime         *models.DateTime    `json:"effectiveDateTime"`
+	CreatedDateTime           models.DateTime     `json:"createdDateTime"`
+	RespondedDateTime         *models.DateTime    `json:"respondedDateTime"`
+	DeclineReason             string              `json:"declineReason"`
+}
+
+// RequestRateChangeInput represents input for proposing a new hourly rate
+type RequestRateChangeInput struct {
+	ContractID string       `json:"contractId"`
+	HourlyRate models.Money `json:"hourlyRate"`
+	// EffectiveDate is the date the new rate applies from, as YYYY-MM-DD.
+	// The change applies from the next billing week if empty.
+	EffectiveDate string `json:"effectiveDate,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// Validate checks the contract, that the rate is positive with a valid
+// currency and that any effective date is a date
+func (in RequestRateChangeInput) Validate() error {
+	return firstError(
+		required("contractId", in.ContractID),
+		validateMoney("hourlyRate", in.HourlyRate),
+		validateDate("effectiveDate", in.EffectiveDate),
+	)
+}
+
+// RequestTermsChangeInput represents input for proposing new hourly terms
+// other than the rate
+type RequestTermsChangeInput struct {
+	ContractID        string `json:"contractId"`
+	WeeklyHoursLimit  *int   `json:"weeklyHoursLimit,omitempty"`
+	ManualTimeAllowed *bool  `json:"manualTimeAllowed,omitempty"`
+	EffectiveDate     string `json:"effectiveDate,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+// Validate checks the contract and that at least one term is changed
+func (in RequestTermsChangeInput) Validate() error {
+	if err := required("contractId", in.ContractID); err != nil {
+		return err
+	}
+	if in.WeeklyHoursLimit == nil && in.ManualTimeAllowed == nil {
+		return &errors.ValidationError{Field: "input", Message: "at least one term must be changed"}
+	}
+	return firstError(
+		validateNonNegative("weeklyHoursLimit", in.WeeklyHoursLimit),
+		validateDate("effectiveDate", in.EffectiveDate),
+	)
+}
+
+// changeRequestFields selects the fields of a ChangeRequest
+const changeRequestFields = `
+	id
+	contractId
+	type
+	status
+	message
+	requestedBy {
+		id
+		name
+	}
+	currentHourlyRate {
+		rawValue
+		currency
+		displayValue
+	}
+	proposedHourlyRate {
+		rawValue
+		currency
+		displayValue
+	}
+	currentWeeklyHoursLimit
+	proposedWeeklyHoursLimit
+	proposedManualTimeAllowed
+	effectiveDateTime
+	createdDateTime
+	respondedDateTime
+	declineReason
+`
+
+// RequestRateChange proposes a new hourly rate for a contract. The change
+// takes effect once the other party accepts it.
+func (s *ContractsService) RequestRateChange(ctx context.Context, input RequestRateChangeInput) (*ChangeRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation RequestContractRateChange($input: RequestContractRateChangeInput!) {
+			requestContractRateChange(input: $input) {` + changeRequestFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		RequestContractRateChange ChangeRequest `json:"requestContractRateChange"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.RequestContractRateChange, nil
+}
+
+// RequestTermsChange proposes new weekly limit or manual time terms for an
+// hourly contract
+func (s *ContractsService) RequestTermsChange(ctx context.Context, input RequestTermsChangeInput) (*ChangeRequest, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation RequestContractTermsChange($input: RequestContractTermsChangeInput!) {
+			requestContractTermsChange(input: $input) {` + changeRequestFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		RequestContractTermsChange ChangeRequest `json:"requestContractTermsChange"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.RequestContractTermsChange, nil
+}
+
+// AcceptRateChange accepts a pending change request
+func (s *ContractsService) AcceptRateChange(ctx context.Context, changeRequestID string) error {
+	mutation := `
+		mutation AcceptContractChangeRequest($id: ID!) {
+			acceptContractChangeRequest(id: $id) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"id": changeRequestID,
+		},
+	}
+
+	var resp struct {
+		AcceptContractChangeRequest struct {
+			Success bool `json:"success"`
+		} `json:"acceptContractChangeRequest"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.AcceptContractChangeRequest.Success {
+		return fmt.Errorf("failed to accept change request")
+	}
+
+	return nil
+}
+
+// DeclineRateChange declines a pending change request
+func (s *ContractsService) DeclineRateChange(ctx context.Context, changeRequestID, reason string) error {
+	mutation := `
+		mutation DeclineContractChangeRequest($id: ID!, $reason: String) {
+			declineContractChangeRequest(id: $id, reason: $reason) {
+				success
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"id": changeRequestID,
+	}
+	if reason != "" {
+		variables["reason"] = reason
+	}
+
+	req := &GraphQLRequest{
+		Query:     mutation,
+		Variables: variables,
+	}
+
+	var resp struct {
+		DeclineContractChangeRequest struct {
+			Success bool `json:"success"`
+		} `json:"declineContractChangeRequest"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.DeclineContractChangeRequest.Success {
+		return fmt.Errorf("failed to decline change request")
+	}
+
+	return nil
+}
+
+// ListPendingChangeRequests returns the change requests awaiting a response.
+// If contractID is empty, pending requests for every contract are returned.
+func (s *ContractsService) ListPendingChangeRequests(ctx context.Context, contractID string) ([]ChangeRequest, error) {
+	query := `
+		query ListContractChangeRequests($contractId: ID, $status: [ContractChangeRequestStatus!]) {
+			contractChangeRequests(contractId: $contractId, status: $status) {` + changeRequestFields + `}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"status": []ChangeRequestStatus{ChangeRequestStatusPending},
+	}
+	if contractID != "" {
+		variables["contractId"] = contractID
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		ContractChangeRequests []ChangeRequest `json:"contractChangeRequests"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.ContractChangeRequests, nil
+}
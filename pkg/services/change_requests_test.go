@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestRequestRateChange(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"requestContractRateChange":{
+			"id":"cr-1","contractId":"contract-1","type":"RATE_CHANGE","status":"PENDING",
+			"currentHourlyRate":{"rawValue":"50.00","currency":"USD"},
+			"proposedHourlyRate":{"rawValue":"60.00","currency":"USD"}
+		}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	change, err := svc.RequestRateChange(context.Background(), RequestRateChangeInput{
+		ContractID: "contract-1",
+		HourlyRate: models.MustMoney("60", "USD"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ChangeRequestTypeRateChange, change.Type)
+	assert.Equal(t, ChangeRequestStatusPending, change.Status)
+	require.NotNil(t, change.ProposedHourlyRate)
+	assert.True(t, change.ProposedHourlyRate.Equal(models.MustMoney("60", "USD")))
+
+	input := req.Variables["input"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"rawValue": "60.00", "currency": "USD"}, input["hourlyRate"])
+	assert.NotContains(t, input, "effectiveDate")
+
+	_, err = svc.RequestRateChange(context.Background(), RequestRateChangeInput{ContractID: "contract-1"})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "hourlyRate", validationErr.Field)
+}
+
+func TestRespondToChangeRequest(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		w.Write([]byte(`{"data":{"acceptContractChangeRequest":{"success":true},"declineContractChangeRequest":{"success":false}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	require.NoError(t, svc.AcceptRateChange(context.Background(), "cr-1"))
+	err := svc.DeclineRateChange(context.Background(), "cr-2", "Budget is fixed")
+	assert.EqualError(t, err, "failed to decline change request")
+
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "cr-1", reqs[0].Variables["id"])
+	assert.Equal(t, map[string]interface{}{"id": "cr-2", "reason": "Budget is fixed"}, reqs[1].Variables)
+}
+
+func TestListPendingChangeRequests(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"contractChangeRequests":[
+			{"id":"cr-1","type":"RATE_CHANGE","status":"PENDING"},
+			{"id":"cr-2","type":"TERMS_CHANGE","status":"PENDING","proposedWeeklyHoursLimit":20}
+		]}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	changes, err := svc.ListPendingChangeRequests(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.NotNil(t, changes[1].ProposedWeeklyHoursLimit)
+	assert.Equal(t, 20, *changes[1].ProposedWeeklyHoursLimit)
+
+	assert.Equal(t, []interface{}{"PENDING"}, req.Variables["status"])
+	assert.NotContains(t, req.Variables, "contractId")
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// ClientsService handles queries about clients, the companies that hire
+// on Upwork
+type ClientsService struct {
+	client *BaseClient
+}
+
+// NewClientsService creates a new clients service
+func NewClientsService(client *BaseClient) *ClientsService {
+	return &ClientsService{client: client}
+}
+
+// ClientCompanyProfile represents the public profile of a client company,
+// with the history freelancers use to vet a client before bidding
+type ClientCompanyProfile struct {
+	ID                  models.ID       `json:"id"`
+	Name                string          `json:"name"`
+	Location            models.Location `json:"location"`
+	MemberSinceDateTime models.DateTime `json:"memberSinceDateTime"`
+	PaymentVerified     bool            `json:"paymentVerified"`
+
+	TotalSpent        models.Money  `json:"totalSpent"`
+	AvgHourlyRatePaid *models.Money `json:"avgHourlyRatePaid"`
+	TotalPostedJobs   int           `json:"totalPostedJobs"`
+	TotalHires        int           `json:"totalHires"`
+	ActiveContracts   int           `json:"activeContracts"`
+	// TotalFeedback is the average score freelancers gave the client,
+	// out of MaxFeedbackScore
+	TotalFeedback float64 `json:"totalFeedback"`
+	TotalReviews  int     `json:"totalReviews"`
+
+	// SpendHistory is the amount spent per month, oldest first
+	SpendHistory []ClientSpend           `json:"spendHistory"`
+	Reviews      []ClientReview          `json:"reviews"`
+	OpenJobs     []MarketplaceJobPosting `json:"openJobs"`
+}
+
+// ClientSpend is the amount a client spent in a month
+type ClientSpend struct {
+	// Month is the month as YYYY-MM
+	Month  string       `json:"month"`
+	Amount models.Money `json:"amount"`
+}
+
+// ClientReview is feedback a freelancer left for a client
+type ClientReview struct {
+	ContractTitle   string          `json:"contractTitle"`
+	Freelancer      models.User     `json:"freelancer"`
+	Score           float64         `json:"score"`
+	Comment         string          `json:"comment"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+}
+
+// HireRate returns the fraction of the client's posted jobs that led to a
+// hire, between 0 and 1. It is 0 for a client who has not posted a job.
+func (p ClientCompanyProfile) HireRate() float64 {
+	if p.TotalPostedJobs <= 0 {
+		return 0
+	}
+	return min(float64(p.TotalHires)/float64(p.TotalPostedJobs), 1)
+}
+
+// GetClientCompanyProfile returns the public profile of a client company,
+// including its spend history, reviews and open jobs
+func (s *ClientsService) GetClientCompanyProfile(ctx context.Context, companyID string) (*ClientCompanyProfile, error) {
+	if err := required("companyId", companyID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetClientCompanyProfile($id: ID!) {
+			clientCompanyProfile(id: $id) {
+				id
+				name
+				location {
+					country
+					state
+					city
+					timezone
+				}
+				memberSinceDateTime
+				paymentVerified
+				totalSpent {
+					rawValue
+					currency
+					displayValue
+				}
+				avgHourlyRatePaid {
+					rawValue
+					currency
+					displayValue
+				}
+				totalPostedJobs
+				totalHires
+				activeContracts
+				totalFeedback
+				totalReviews
+				spendHistory {
+					month
+					amount {
+						rawValue
+						currency
+						displayValue
+					}
+				}
+				reviews {
+					contractTitle
+					freelancer {
+						id
+						name
+					}
+					score
+					comment
+					createdDateTime
+				}
+				openJobs {
+					id
+					title
+					description
+					createdDateTime
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": companyID,
+		},
+	}
+
+	var resp struct {
+		ClientCompanyProfile ClientCompanyProfile `json:"clientCompanyProfile"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.ClientCompanyProfile, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestGetClientCompanyProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "clientCompanyProfile")
+		assert.Equal(t, "company-1", req.Variables["id"])
+		w.Write([]byte(`{"data":{"clientCompanyProfile":{
+			"id":"company-1","name":"Acme","location":{"country":"United States"},
+			"paymentVerified":true,
+			"totalSpent":{"rawValue":"12500.00","currency":"USD"},
+			"totalPostedJobs":8,"totalHires":6,"totalFeedback":4.8,"totalReviews":5,
+			"spendHistory":[{"month":"2026-09","amount":{"rawValue":"1500","currency":"USD"}}],
+			"reviews":[{"contractTitle":"API work","freelancer":{"id":"user-1","name":"Ada"},"score":5,"comment":"Great client"}],
+			"openJobs":[{"id":"job-1","title":"Go developer"}]
+		}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewClientsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	_, err := svc.GetClientCompanyProfile(ctx, "")
+	assertValidationField(t, err, "companyId")
+
+	profile, err := svc.GetClientCompanyProfile(ctx, "company-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", profile.Name)
+	assert.True(t, profile.PaymentVerified)
+	assert.Equal(t, "12500.00 USD", profile.TotalSpent.String())
+	assert.InDelta(t, 0.75, profile.HireRate(), 1e-9)
+	require.Len(t, profile.SpendHistory, 1)
+	assert.Equal(t, "1500.00 USD", profile.SpendHistory[0].Amount.String())
+	require.Len(t, profile.Reviews, 1)
+	assert.Equal(t, "Ada", profile.Reviews[0].Freelancer.Name)
+	require.Len(t, profile.OpenJobs, 1)
+	assert.Equal(t, models.ID("job-1"), profile.OpenJobs[0].ID)
+
+	assert.Equal(t, 1.0, ClientCompanyProfile{TotalPostedJobs: 1, TotalHires: 3}.HireRate())
+	assert.Zero(t, ClientCompanyProfile{}.HireRate())
+}
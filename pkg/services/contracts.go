@@ -1,43 +1,49 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
 	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/richtext"
 )
 
 // ContractsService handles contract-related API operations
 type ContractsService struct {
 	client *BaseClient
+
+	// metadata resolves reason aliases from the cached reason catalog
+	metadata *MetadataService
 }
 
 // NewContractsService creates a new contracts service
 func NewContractsService(client *BaseClient) *ContractsService {
-	return &ContractsService{client: client}
+	return &ContractsService{client: client, metadata: NewMetadataService(client)}
 }
 
 // Contract represents a contract
 type Contract struct {
-	ID                      models.ID                `json:"id"`
-	Title                   string            `json:"title"`
-	ContractType            ContractType      `json:"contractType"`
-	Status                  ContractStatus    `json:"status"`
-	CreatedDateTime         models.DateTime          `json:"createdDateTime"`
-	StartDateTime           models.DateTime          `json:"startDateTime"`
-	EndDateTime             *models.DateTime         `json:"endDateTime"`
-	ModifiedDateTime        models.DateTime          `json:"modifiedDateTime"`
-	HourlyChargeRate        *models.Money            `json:"hourlyChargeRate"`
-	WeeklyHoursLimit        *int              `json:"weeklyHoursLimit"`
-	WeeklyChargeAmount      *models.Money            `json:"weeklyChargeAmount"`
-	ManualTimeAllowed       bool              `json:"manualTimeAllowed"`
-	Paused                  bool              `json:"paused"`
-	Suspended               bool              `json:"suspended"`
-	Last                    bool              `json:"last"`
-	Job                     *Job              `json:"job"`
-	Offer                   *Offer            `json:"offer"`
-	Freelancer              *FreelancerInfo   `json:"freelancer"`
-	Client                  *ClientInfo       `json:"client"`
-	Milestones              []Milestone       `json:"milestones"`
+	ID                 models.ID        `json:"id"`
+	Title              string           `json:"title"`
+	ContractType       ContractType     `json:"contractType"`
+	Status             ContractStatus   `json:"status"`
+	CreatedDateTime    models.DateTime  `json:"createdDateTime"`
+	StartDateTime      models.DateTime  `json:"startDateTime"`
+	EndDateTime        *models.DateTime `json:"endDateTime"`
+	ModifiedDateTime   models.DateTime  `json:"modifiedDateTime"`
+	HourlyChargeRate   *models.Money    `json:"hourlyChargeRate"`
+	WeeklyHoursLimit   *int             `json:"weeklyHoursLimit"`
+	WeeklyChargeAmount *models.Money    `json:"weeklyChargeAmount"`
+	ManualTimeAllowed  bool             `json:"manualTimeAllowed"`
+	Paused             bool             `json:"paused"`
+	Suspended          bool             `json:"suspended"`
+	Last               bool             `json:"last"`
+	Job                *Job             `json:"job"`
+	Offer              *Offer           `json:"offer"`
+	Freelancer         *FreelancerInfo  `json:"freelancer"`
+	Client             *ClientInfo      `json:"client"`
+	Milestones         []Milestone      `json:"milestones"`
 }
 
 // ContractType represents the type of contract
@@ -46,6 +52,7 @@ type ContractType string
 const (
 	ContractTypeHourly     ContractType = "HOURLY"
 	ContractTypeFixedPrice ContractType = "FIXED_PRICE"
+	ContractTypeUnknown    ContractType = "UNKNOWN"
 )
 
 // ContractStatus represents the status of a contract
@@ -56,23 +63,44 @@ const (
 	ContractStatusPaused    ContractStatus = "PAUSED"
 	ContractStatusEnded     ContractStatus = "ENDED"
 	ContractStatusSuspended ContractStatus = "SUSPENDED"
+	ContractStatusUnknown   ContractStatus = "UNKNOWN"
 )
 
 // Job represents a job
 type Job struct {
-	ID      ID         `json:"id"`
+	ID      models.ID  `json:"id"`
 	Content JobContent `json:"content"`
 }
 
 // JobContent represents job content
 type JobContent struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title string `json:"title"`
+	// DescriptionHTML is the description as returned by the API, which may
+	// be HTML or plain text. Use Description or DescriptionMarkdown to
+	// display it.
+	DescriptionHTML string `json:"description"`
+}
+
+// Description returns the description as plain text
+func (c JobContent) Description() string {
+	return richtext.PlainText(c.DescriptionHTML)
+}
+
+// DescriptionMarkdown returns the description as Markdown
+func (c JobContent) DescriptionMarkdown() string {
+	return richtext.Markdown(c.DescriptionHTML)
+}
+
+// SanitizedDescription returns the description as HTML safe to show in a
+// page
+func (c JobContent) SanitizedDescription() string {
+	return richtext.Sanitize(c.DescriptionHTML)
 }
 
 // Offer represents an offer
 type Offer struct {
-	ID         ID         `json:"id"`
+	ID         models.ID  `json:"id"`
+	Title      string     `json:"title"`
 	OfferTerms OfferTerms `json:"offerTerms"`
 }
 
@@ -84,34 +112,38 @@ type OfferTerms struct {
 
 // HourlyTerm represents hourly contract terms
 type HourlyTerm struct {
-	HourlyRate       Money `json:"hourlyRate"`
-	WeeklyHoursLimit int   `json:"weeklyHoursLimit"`
+	HourlyRate       models.Money `json:"hourlyRate"`
+	WeeklyHoursLimit int          `json:"weeklyHoursLimit"`
 }
 
 // FixedPriceTerm represents fixed price contract terms
 type FixedPriceTerm struct {
-	Budget Money `json:"budget"`
+	Budget models.Money `json:"budget"`
 }
 
 // FreelancerInfo represents freelancer information
 type FreelancerInfo struct {
-	User            User            `json:"user"`
-	CountryDetails  CountryDetails  `json:"countryDetails"`
+	User           models.User    `json:"user"`
+	CountryDetails CountryDetails `json:"countryDetails"`
 }
 
 // ClientInfo represents client information
 type ClientInfo struct {
-	User User `json:"user"`
+	User models.User `json:"user"`
 }
 
 // CountryDetails represents country information
 type CountryDetails struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
+	ID   models.ID `json:"id"`
+	Name string    `json:"name"`
 }
 
 // GetContract returns a contract by ID
 func (s *ContractsService) GetContract(ctx context.Context, contractID string) (*Contract, error) {
+	if err := required("contractId", contractID); err != nil {
+		return nil, err
+	}
+
 	query := `
 		query GetContract($id: ID!) {
 			contract(id: $id) {
@@ -163,29 +195,29 @@ func (s *ContractsService) GetContract(ctx context.Context, contractID string) (
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"id": contractID,
 		},
 	}
-	
+
 	var resp struct {
 		Contract Contract `json:"contract"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Contract, nil
 }
 
 // ListContractsInput represents input for listing contracts
 type ListContractsInput struct {
-	Pagination *PaginationInput  `json:"pagination,omitempty"`
-	Filter     *ContractFilter   `json:"filter,omitempty"`
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+	Filter     *ContractFilter         `json:"filter,omitempty"`
 }
 
 // ContractFilter represents contract filtering options
@@ -196,9 +228,9 @@ type ContractFilter struct {
 
 // ContractList represents a paginated list of contracts
 type ContractList struct {
-	TotalCount int        `json:"totalCount"`
-	PageInfo   PageInfo   `json:"pageInfo"`
-	Edges      []ContractEdge `json:"edges"`
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+	Edges      []ContractEdge  `json:"edges"`
 }
 
 // ContractEdge represents a contract edge in pagination
@@ -243,7 +275,7 @@ func (s *ContractsService) ListContracts(ctx context.Context, input ListContract
 			}
 		}
 	`
-	
+
 	variables := map[string]interface{}{}
 	if input.Pagination != nil {
 		variables["pagination"] = input.Pagination
@@ -251,34 +283,222 @@ func (s *ContractsService) ListContracts(ctx context.Context, input ListContract
 	if input.Filter != nil {
 		variables["filter"] = input.Filter
 	}
-	
+
 	req := &GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
-	
+
 	var resp struct {
 		ContractList ContractList `json:"contractList"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ContractList, nil
 }
 
+// ListContractsWithOptions returns a page of contracts selected by opts and
+// the page info for fetching the next one
+func (s *ContractsService) ListContractsWithOptions(ctx context.Context, opts models.ContractListOptions) ([]Contract, *models.PageInfo, error) {
+	input, err := contractListInput(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := s.ListContracts(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contracts := make([]Contract, 0, len(list.Edges))
+	for _, edge := range list.Edges {
+		contracts = append(contracts, edge.Node)
+	}
+
+	return contracts, &list.PageInfo, nil
+}
+
+// contractListInput converts opts to the pagination and filter inputs of
+// the contractList query
+func contractListInput(opts models.ContractListOptions) (ListContractsInput, error) {
+	var input ListContractsInput
+
+	if err := validateNonNegative("limit", &opts.Limit); err != nil {
+		return input, err
+	}
+	if opts.Limit > 0 || opts.After != "" {
+		input.Pagination = &models.PaginationInput{First: opts.Limit, After: opts.After}
+	}
+
+	status := ContractStatus(normalizeEnum(opts.Status))
+	contractType := ContractType(normalizeEnum(opts.ContractType))
+	if err := firstError(
+		validateEnum("status", status, status.IsValid()),
+		validateEnum("contractType", contractType, contractType.IsValid()),
+	); err != nil {
+		return input, err
+	}
+
+	if status != "" || contractType != "" {
+		input.Filter = &ContractFilter{}
+		if status != "" {
+			input.Filter.Status = []ContractStatus{status}
+		}
+		if contractType != "" {
+			input.Filter.ContractType = []ContractType{contractType}
+		}
+	}
+
+	return input, nil
+}
+
+// CreateContractInput represents a contract to offer a freelancer. Setting
+// HourlyRate makes an hourly contract; setting Milestones makes a
+// fixed-price one.
+type CreateContractInput struct {
+	FreelancerID string
+	TeamID       string
+	JobPostingID string
+	Title        string
+	Description  string
+	Message      string
+	StartDate    string
+
+	// Hourly terms
+	HourlyRate        *models.Money
+	WeeklyLimit       *int
+	ManualTimeAllowed bool
+
+	// Fixed-price terms
+	Milestones []OfferMilestoneInput
+}
+
+// offer converts the input to the offer that creates the contract
+func (in CreateContractInput) offer() CreateOfferInput {
+	offer := CreateOfferInput{
+		FreelancerID: in.FreelancerID,
+		TeamID:       in.TeamID,
+		JobPostingID: in.JobPostingID,
+		Title:        in.Title,
+		Description:  in.Description,
+		Message:      in.Message,
+		StartDate:    in.StartDate,
+	}
+	if in.HourlyRate != nil {
+		offer.HourlyTerms = &HourlyOfferTerms{
+			HourlyRate:        *in.HourlyRate,
+			WeeklyHoursLimit:  in.WeeklyLimit,
+			ManualTimeAllowed: in.ManualTimeAllowed,
+		}
+	}
+	if len(in.Milestones) > 0 {
+		offer.FixedPriceTerms = &FixedPriceOfferTerms{Milestones: in.Milestones}
+	}
+	return offer
+}
+
+// CreateContract offers the freelancer a contract on the given terms and
+// returns the offer. A fixed-price offer carries its milestones, so they
+// exist as soon as the contract starts. The contract starts once the
+// freelancer accepts.
+func (s *ContractsService) CreateContract(ctx context.Context, input CreateContractInput) (*Offer, error) {
+	return s.SendOffer(ctx, input.offer())
+}
+
+// UpdateContractInput represents changes to an hourly contract. Nil fields
+// are left unchanged.
+type UpdateContractInput struct {
+	// WeeklyLimit takes effect immediately
+	WeeklyLimit *int
+
+	// HourlyRate is proposed to the other party as a rate change request,
+	// which takes effect once accepted
+	HourlyRate *models.Money
+
+	// EffectiveDate and Message apply to the rate change request
+	EffectiveDate string
+	Message       string
+}
+
+// UpdateContract applies the changes in input to a contract and returns
+// the contract as it is afterwards. The weekly limit is applied first and
+// stays applied if proposing the new rate then fails.
+func (s *ContractsService) UpdateContract(ctx context.Context, contractID string, input UpdateContractInput) (*Contract, error) {
+	if input.WeeklyLimit == nil && input.HourlyRate == nil {
+		return nil, &errors.ValidationError{Field: "input", Message: "at least one change is required"}
+	}
+
+	// Validate both changes before applying either
+	var limit UpdateHourlyLimitInput
+	if input.WeeklyLimit != nil {
+		limit = UpdateHourlyLimitInput{ContractID: contractID, WeeklyHoursLimit: *input.WeeklyLimit}
+		if err := limit.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var rate RequestRateChangeInput
+	if input.HourlyRate != nil {
+		rate = RequestRateChangeInput{
+			ContractID:    contractID,
+			HourlyRate:    *input.HourlyRate,
+			EffectiveDate: input.EffectiveDate,
+			Message:       input.Message,
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.WeeklyLimit != nil {
+		if err := s.UpdateContractHourlyLimit(ctx, limit); err != nil {
+			return nil, err
+		}
+	}
+	if input.HourlyRate != nil {
+		if _, err := s.RequestRateChange(ctx, rate); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetContract(ctx, contractID)
+}
+
 // EndContractInput represents input for ending a contract
 type EndContractInput struct {
-	ContractID     string  `json:"contractId"`
-	Reason         string  `json:"reason"`
-	Message        string  `json:"message"`
-	Rating         *int    `json:"rating,omitempty"`
-	Feedback       string  `json:"feedback,omitempty"`
+	ContractID string `json:"contractId"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+	Rating     *int   `json:"rating,omitempty"`
+	Feedback   string `json:"feedback,omitempty"`
+}
+
+// Validate checks the contract and reason and that any rating is in range
+func (in EndContractInput) Validate() error {
+	if err := firstError(
+		required("contractId", in.ContractID),
+		required("reason", in.Reason),
+	); err != nil {
+		return err
+	}
+	if in.Rating != nil && (*in.Rating < MinFeedbackScore || *in.Rating > MaxFeedbackScore) {
+		return &errors.ValidationError{
+			Field:   "rating",
+			Message: fmt.Sprintf("must be between %d and %d", MinFeedbackScore, MaxFeedbackScore),
+			Value:   *in.Rating,
+		}
+	}
+	return nil
 }
 
 // EndContractAsClient ends a contract from the client side
 func (s *ContractsService) EndContractAsClient(ctx context.Context, input EndContractInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation EndContractByClient($input: EndContractByClientInput!) {
 			endContractByClient(input: $input) {
@@ -286,33 +506,37 @@ func (s *ContractsService) EndContractAsClient(ctx context.Context, input EndCon
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		EndContractByClient struct {
 			Success bool `json:"success"`
 		} `json:"endContractByClient"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.EndContractByClient.Success {
 		return fmt.Errorf("failed to end contract")
 	}
-	
+
 	return nil
 }
 
 // EndContractAsFreelancer ends a contract from the freelancer side
 func (s *ContractsService) EndContractAsFreelancer(ctx context.Context, input EndContractInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation EndContractByFreelancer($input: EndContractByFreelancerInput!) {
 			endContractByFreelancer(input: $input) {
@@ -320,96 +544,209 @@ func (s *ContractsService) EndContractAsFreelancer(ctx context.Context, input En
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		EndContractByFreelancer struct {
 			Success bool `json:"success"`
 		} `json:"endContractByFreelancer"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.EndContractByFreelancer.Success {
 		return fmt.Errorf("failed to end contract")
 	}
-	
+
 	return nil
 }
 
-// PauseContract pauses a contract
-func (s *ContractsService) PauseContract(ctx context.Context, contractID string) error {
+// EndContractAsClientWithReason is like EndContractAsClient, but
+// input.Reason may also be the alias of a CONTRACT_END reason, such as
+// "API_REAS_JOB_COMPLETED_SUCCESSFULLY", which is resolved to its ID
+func (s *ContractsService) EndContractAsClientWithReason(ctx context.Context, input EndContractInput) error {
+	input, err := s.resolveEndReason(ctx, input)
+	if err != nil {
+		return err
+	}
+	return s.EndContractAsClient(ctx, input)
+}
+
+// EndContractAsFreelancerWithReason is like EndContractAsFreelancer, but
+// input.Reason may also be the alias of a CONTRACT_END reason, which is
+// resolved to its ID
+func (s *ContractsService) EndContractAsFreelancerWithReason(ctx context.Context, input EndContractInput) error {
+	input, err := s.resolveEndReason(ctx, input)
+	if err != nil {
+		return err
+	}
+	return s.EndContractAsFreelancer(ctx, input)
+}
+
+// resolveEndReason validates input, then replaces its reason alias with the
+// reason ID from the catalog
+func (s *ContractsService) resolveEndReason(ctx context.Context, input EndContractInput) (EndContractInput, error) {
+	if err := input.Validate(); err != nil {
+		return input, err
+	}
+
+	reason, err := s.metadata.FindReason(ctx, ReasonTypeContractEnd, input.Reason)
+	if err != nil {
+		return input, err
+	}
+
+	input.Reason = string(reason.ID)
+	return input, nil
+}
+
+// ContractActionOption sets an optional detail of pausing or restarting a
+// contract
+type ContractActionOption func(*contractAction)
+
+// contractAction holds the optional details of pausing or restarting a
+// contract
+type contractAction struct {
+	reason        string
+	message       string
+	effectiveDate string
+}
+
+// WithActionReason records why the contract is paused or restarted
+func WithActionReason(reason string) ContractActionOption {
+	return func(a *contractAction) {
+		a.reason = reason
+	}
+}
+
+// WithActionMessage sends message to the other party of the contract
+func WithActionMessage(message string) ContractActionOption {
+	return func(a *contractAction) {
+		a.message = message
+	}
+}
+
+// WithEffectiveDate schedules the change for date, as YYYY-MM-DD, instead
+// of applying it immediately
+func WithEffectiveDate(date string) ContractActionOption {
+	return func(a *contractAction) {
+		a.effectiveDate = date
+	}
+}
+
+// variables returns the mutation variables for the contract, leaving out
+// details that were not set
+func (a contractAction) variables(contractID string) (map[string]interface{}, error) {
+	if err := firstError(
+		required("contractId", contractID),
+		validateDate("effectiveDate", a.effectiveDate),
+	); err != nil {
+		return nil, err
+	}
+
+	variables := map[string]interface{}{
+		"contractId": contractID,
+	}
+	if a.reason != "" {
+		variables["reason"] = a.reason
+	}
+	if a.message != "" {
+		variables["message"] = a.message
+	}
+	if a.effectiveDate != "" {
+		variables["effectiveDate"] = a.effectiveDate
+	}
+	return variables, nil
+}
+
+// PauseContract pauses a contract. reason may be empty.
+func (s *ContractsService) PauseContract(ctx context.Context, contractID, reason string, opts ...ContractActionOption) error {
+	action := contractAction{reason: reason}
+	for _, opt := range opts {
+		opt(&action)
+	}
+
+	variables, err := action.variables(contractID)
+	if err != nil {
+		return err
+	}
+
 	mutation := `
-		mutation PauseContract($contractId: ID!) {
-			pauseContract(contractId: $contractId) {
+		mutation PauseContract($contractId: ID!, $reason: String, $message: String, $effectiveDate: String) {
+			pauseContract(contractId: $contractId, reason: $reason, message: $message, effectiveDate: $effectiveDate) {
 				success
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
-		Query: mutation,
-		Variables: map[string]interface{}{
-			"contractId": contractID,
-		},
+		Query:     mutation,
+		Variables: variables,
 	}
-	
+
 	var resp struct {
 		PauseContract struct {
 			Success bool `json:"success"`
 		} `json:"pauseContract"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.PauseContract.Success {
 		return fmt.Errorf("failed to pause contract")
 	}
-	
+
 	return nil
 }
 
 // RestartContract restarts a paused contract
-func (s *ContractsService) RestartContract(ctx context.Context, contractID string) error {
+func (s *ContractsService) RestartContract(ctx context.Context, contractID string, opts ...ContractActionOption) error {
+	var action contractAction
+	for _, opt := range opts {
+		opt(&action)
+	}
+
+	variables, err := action.variables(contractID)
+	if err != nil {
+		return err
+	}
+
 	mutation := `
-		mutation RestartContract($contractId: ID!) {
-			restartContract(contractId: $contractId) {
+		mutation RestartContract($contractId: ID!, $reason: String, $message: String, $effectiveDate: String) {
+			restartContract(contractId: $contractId, reason: $reason, message: $message, effectiveDate: $effectiveDate) {
 				success
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
-		Query: mutation,
-		Variables: map[string]interface{}{
-			"contractId": contractID,
-		},
+		Query:     mutation,
+		Variables: variables,
 	}
-	
+
 	var resp struct {
 		RestartContract struct {
 			Success bool `json:"success"`
 		} `json:"restartContract"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.RestartContract.Success {
 		return fmt.Errorf("failed to restart contract")
 	}
-	
+
 	return nil
 }
 
@@ -419,8 +756,20 @@ type UpdateHourlyLimitInput struct {
 	WeeklyHoursLimit int    `json:"weeklyHoursLimit"`
 }
 
+// Validate checks the contract and that the limit is not negative
+func (in UpdateHourlyLimitInput) Validate() error {
+	return firstError(
+		required("contractId", in.ContractID),
+		validateNonNegative("weeklyHoursLimit", &in.WeeklyHoursLimit),
+	)
+}
+
 // UpdateContractHourlyLimit updates the weekly hours limit for a contract
 func (s *ContractsService) UpdateContractHourlyLimit(ctx context.Context, input UpdateHourlyLimitInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation UpdateContractHourlyLimit($input: UpdateContractHourlyLimitInput!) {
 			updateContractHourlyLimit(input: $input) {
@@ -428,27 +777,27 @@ func (s *ContractsService) UpdateContractHourlyLimit(ctx context.Context, input
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		UpdateContractHourlyLimit struct {
 			Success bool `json:"success"`
 		} `json:"updateContractHourlyLimit"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.UpdateContractHourlyLimit.Success {
 		return fmt.Errorf("failed to update hourly limit")
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
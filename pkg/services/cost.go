@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// WeightedRateLimiter is a RateLimiter that charges each request its cost
+// rather than one token. The client's limiter implements it.
+type WeightedRateLimiter interface {
+	RateLimiter
+
+	// WaitN blocks until n tokens are available
+	WaitN(ctx context.Context, n int) error
+
+	// Debit takes n tokens without waiting, or gives them back if n is
+	// negative
+	Debit(n int)
+}
+
+// operationCosts is the rate limit cost of built-in operations that cost
+// the API more than a plain request, keyed by operation name. Reports scan
+// many rows, so they are charged more to keep them from exhausting the
+// quota.
+var operationCosts = map[string]int{
+	"TimeReport":            5,
+	"ContractWeeklySummary": 5,
+	"GetWorkDiaryCompany":   5,
+	"TransactionHistory":    5,
+	"FreelancerEarnings":    3,
+	"GetAuditLog":           3,
+}
+
+// WithCost sets the rate limit cost of the request, overriding the cost of
+// its operation
+func WithCost(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.cost = n
+	}
+}
+
+// QueryCost is the cost of a query reported in the extensions of its
+// response
+type QueryCost struct {
+	RequestedQueryCost int `json:"requestedQueryCost"`
+	ActualQueryCost    int `json:"actualQueryCost"`
+}
+
+// responseExtensions holds the extensions of a GraphQL response the client
+// acts on
+type responseExtensions struct {
+	Cost     *QueryCost           `json:"cost"`
+	Warnings []DeprecationWarning `json:"warnings"`
+}
+
+// requestCost returns the rate limit cost of req: the WithCost option,
+// else the client's OperationCosts, else the built-in cost of its
+// operation, else one
+func (c *BaseClient) requestCost(req *GraphQLRequest, options *requestOptions) int {
+	if options.cost > 0 {
+		return options.cost
+	}
+	if cost, ok := c.OperationCosts[req.OperationName]; ok && cost > 0 {
+		return cost
+	}
+	if cost, ok := operationCosts[req.OperationName]; ok {
+		return cost
+	}
+	return 1
+}
+
+// waitRateLimit waits for cost tokens, or a single token if the limiter is
+// not weighted, and records a throttle event if the limiter delayed or
+// rejected the request
+func (c *BaseClient) waitRateLimit(ctx context.Context, operationName string, cost int) error {
+	if c.RateLimiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	var err error
+	if weighted, ok := c.RateLimiter.(WeightedRateLimiter); ok {
+		err = weighted.WaitN(ctx, cost)
+	} else {
+		err = c.RateLimiter.Wait(ctx)
+	}
+
+	waited := time.Since(start)
+	rejected := stderrors.Is(err, errors.ErrWouldExceedDeadline)
+	if waited >= minThrottleWait || rejected {
+		c.recordThrottle(ThrottleEvent{
+			Time:          start,
+			Source:        ThrottleSourceLimiter,
+			OperationName: operationName,
+			Wait:          waited,
+			Rejected:      rejected,
+		})
+	}
+	return err
+}
+
+// settleCost charges the limiter the difference between the cost reserved
+// for a request and the actual cost its response reports, if any
+func (c *BaseClient) settleCost(reserved int, ext *responseExtensions) {
+	if ext.Cost == nil || ext.Cost.ActualQueryCost <= 0 {
+		return
+	}
+	if weighted, ok := c.RateLimiter.(WeightedRateLimiter); ok && ext.Cost.ActualQueryCost != reserved {
+		weighted.Debit(ext.Cost.ActualQueryCost - reserved)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLimiter is a WeightedRateLimiter that records what it is
+// charged
+type recordingLimiter struct {
+	mu     sync.Mutex
+	waits  []int
+	debits []int
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+func (l *recordingLimiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waits = append(l.waits, n)
+	return nil
+}
+
+func (l *recordingLimiter) Debit(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debits = append(l.debits, n)
+}
+
+func TestWeightedRateLimiting(t *testing.T) {
+	var extensions string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{}` + extensions + `}`))
+	}))
+	defer server.Close()
+
+	limiter := &recordingLimiter{}
+	client := &BaseClient{
+		HTTPClient:     server.Client(),
+		APIURL:         server.URL,
+		RateLimiter:    limiter,
+		OperationCosts: map[string]int{"GetAuditLog": 8},
+	}
+	ctx := context.Background()
+	do := func(query string, opts ...RequestOption) {
+		require.NoError(t, client.Do(ctx, &GraphQLRequest{Query: query}, nil, opts...))
+	}
+
+	do(`query TimeReport { contractTimeReport { totalCount } }`)
+	do(`query GetUser { user { id } }`)
+	do(`query GetAuditLog { organizationAuditLog { totalCount } }`)
+	do(`query GetUser { user { id } }`, WithCost(4))
+	assert.Equal(t, []int{5, 1, 8, 4}, limiter.waits)
+	assert.Empty(t, limiter.debits)
+
+	// The actual cost reported by the response is settled
+	extensions = `,"extensions":{"cost":{"requestedQueryCost":5,"actualQueryCost":12}}`
+	do(`query TimeReport { contractTimeReport { totalCount } }`)
+	extensions = `,"extensions":{"cost":{"actualQueryCost":2}}`
+	do(`query TimeReport { contractTimeReport { totalCount } }`)
+	assert.Equal(t, []int{7, -3}, limiter.debits)
+}
+
+func TestWeightedRateLimitingStreamAndBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > 0 && r.Header.Get("X-Batch") != "" {
+			w.Write([]byte(`[{"data":{}},{"data":{}}]`))
+			return
+		}
+		w.Write([]byte(`{"data":{"items":[]},"extensions":{"cost":{"actualQueryCost":3}}}`))
+	}))
+	defer server.Close()
+
+	limiter := &recordingLimiter{}
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, RateLimiter: limiter}
+	ctx := context.Background()
+
+	err := client.DoStream(ctx, &GraphQLRequest{Query: `query Items { items { id } }`}, []string{"items"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, limiter.waits)
+	assert.Equal(t, []int{2}, limiter.debits)
+
+	_, err = client.DoBatch(ctx, []*GraphQLRequest{
+		{Query: `query TimeReport { contractTimeReport { totalCount } }`},
+		{Query: `query GetUser { user { id } }`},
+	}, []interface{}{nil, nil}, WithHeader("X-Batch", "1"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 6}, limiter.waits)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// flight is a query in flight shared by every caller that issued it
+type flight struct {
+	done chan struct{}
+	data json.RawMessage
+	err  error
+	// info describes the response, for callers using WithResponseInfo
+	info ResponseInfo
+}
+
+// dedupeKey returns the key under which identical queries are shared, or
+// "" if req must not be shared. Queries are identical if they have the same
+// operation, variables, organization and request headers.
+func (c *BaseClient) dedupeKey(ctx context.Context, req *GraphQLRequest, options *requestOptions) string {
+	op := prepareQuery(req.Query).operation(req.OperationName)
+	if op == nil || op.Operation != ast.Query {
+		return ""
+	}
+
+	// Maps are marshaled with sorted keys, so equal values give equal keys
+	variables, err := json.Marshal(req.Variables)
+	if err != nil {
+		return ""
+	}
+	header, err := json.Marshal(options.header)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join([]string{
+		c.organizationID(ctx), req.OperationName, req.Query, string(variables), string(header),
+	}, "\x00")
+}
+
+// doShared executes req once for all concurrent callers with the same key
+// and unmarshals the shared response into result. The request runs detached
+// from ctx, so one caller giving up does not fail the others; each caller
+// still returns as soon as its own ctx is done.
+func (c *BaseClient) doShared(ctx context.Context, key string, req *GraphQLRequest, result interface{}, opts []RequestOption) error {
+	c.flightsMu.Lock()
+	f, ok := c.flights[key]
+	if !ok {
+		if c.flights == nil {
+			c.flights = make(map[string]*flight)
+		}
+		f = &flight{done: make(chan struct{})}
+		c.flights[key] = f
+
+		// The flight records its response for every caller, so a leader's
+		// WithResponseInfo does not apply to it
+		flightOpts := append(opts[:len(opts):len(opts)], WithResponseInfo(&f.info))
+		go func() {
+			f.err = c.do(context.WithoutCancel(ctx), req, flightOpts, func(body io.Reader, ext *responseExtensions) error {
+				return c.decodeResponse(body, &f.data, ext)
+			})
+
+			c.flightsMu.Lock()
+			delete(c.flights, key)
+			c.flightsMu.Unlock()
+			close(f.done)
+		}()
+	}
+	c.flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if info := resolveOptions(ctx, opts).responseInfo; info != nil {
+		*info = f.info
+		info.Header = f.info.Header.Clone()
+	}
+	if f.err != nil {
+		return f.err
+	}
+
+	if result != nil && len(f.data) > 0 {
+		c.checkShape(req, f.data, result)
+		if err := c.jsonCodec().Unmarshal(f.data, result); err != nil {
+			return errors.WrapError(err, "failed to unmarshal response data")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDedupeTestClient returns a client whose server holds every request
+// until release is closed
+func newDedupeTestClient(t *testing.T) (*BaseClient, *int32, chan struct{}) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		fmt.Fprint(w, `{"data":{"user":{"id":"user-1"}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, DeduplicateQueries: true}, &calls, release
+}
+
+func TestDeduplicateQueries(t *testing.T) {
+	client, calls, release := newDedupeTestClient(t)
+
+	req := &GraphQLRequest{Query: `query { user { id } }`}
+	results := make([]struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}, 10)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = client.Do(context.Background(), req, &results[i])
+		}(i)
+	}
+
+	// Let every caller join the flight before the response arrives
+	require.Eventually(t, func() bool { return atomic.LoadInt32(calls) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	for i := range results {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "user-1", results[i].User.ID)
+	}
+
+	// Later queries are sent again
+	require.NoError(t, client.Do(context.Background(), req, nil))
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestDeduplicateQueriesDistinct(t *testing.T) {
+	client, calls, release := newDedupeTestClient(t)
+	close(release)
+
+	ctx := context.Background()
+	query := `query User($id: ID!) { user(id: $id) { id } }`
+	mutation := `mutation { archive { success } }`
+
+	var wg sync.WaitGroup
+	for _, req := range []*GraphQLRequest{
+		{Query: query, Variables: map[string]interface{}{"id": "1"}},
+		{Query: query, Variables: map[string]interface{}{"id": "2"}},
+		{Query: mutation},
+		{Query: mutation},
+	} {
+		wg.Add(1)
+		go func(req *GraphQLRequest) {
+			defer wg.Done()
+			assert.NoError(t, client.Do(ctx, req, nil))
+		}(req)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+
+	assert.NotEqual(t,
+		client.dedupeKey(WithOrganization(ctx, "org-1"), &GraphQLRequest{Query: query}, resolveOptions(ctx, nil)),
+		client.dedupeKey(WithOrganization(ctx, "org-2"), &GraphQLRequest{Query: query}, resolveOptions(ctx, nil)))
+}
+
+func TestDedupeKey(t *testing.T) {
+	client := &BaseClient{DeduplicateQueries: true}
+	ctx := context.Background()
+	key := func(query, operationName string) string {
+		return client.dedupeKey(ctx, &GraphQLRequest{Query: query, OperationName: operationName}, resolveOptions(ctx, nil))
+	}
+
+	// Queries are classified from the prepared-query cache
+	query := `query DedupeKeyUser { user { id } }`
+	assert.NotEmpty(t, key(query, ""))
+	preparedQueries.RLock()
+	_, cached := preparedQueries.m[query]
+	preparedQueries.RUnlock()
+	assert.True(t, cached)
+
+	assert.Empty(t, key(`mutation { archive { success } }`, ""))
+	assert.Empty(t, key(`query A { a } mutation B { b }`, "B"))
+	assert.NotEmpty(t, key(`query A { a } mutation B { b }`, "A"))
+	assert.Empty(t, key(`query {`, ""))
+}
+
+func TestDeduplicateQueriesCancel(t *testing.T) {
+	client, calls, release := newDedupeTestClient(t)
+	req := &GraphQLRequest{Query: `query { user { id } }`}
+
+	done := make(chan error)
+	go func() {
+		done <- client.Do(context.Background(), req, nil)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(calls) == 1 }, time.Second, time.Millisecond)
+
+	// A caller giving up does not fail the shared request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, client.Do(ctx, req, nil), context.Canceled)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
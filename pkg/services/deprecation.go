@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// maxLoggedDeprecations bounds the number of distinct deprecation warnings
+// remembered so each is logged once
+const maxLoggedDeprecations = 256
+
+// DeprecationWarning is a deprecated field or argument used by a request,
+// as reported in the warnings extension of its response
+type DeprecationWarning struct {
+	// OperationName is the name of the operation that used it, if it has
+	// one
+	OperationName string `json:"-"`
+	// Message describes the deprecation
+	Message string `json:"message"`
+	// Field is the deprecated schema coordinate, such as
+	// "Contract.oldField", if reported
+	Field string `json:"field"`
+	// Reason is the deprecation reason from the schema, if reported
+	Reason string `json:"reason"`
+	// Path is the path of the field in the response, if reported
+	Path []interface{} `json:"path"`
+}
+
+// String returns the message, or the field if there is none
+func (w DeprecationWarning) String() string {
+	if w.Message != "" {
+		return w.Message
+	}
+	if w.Reason != "" {
+		return w.Field + ": " + w.Reason
+	}
+	return w.Field + " is deprecated"
+}
+
+// reportDeprecations passes the deprecation warnings of the response to a
+// request for operationName to OnDeprecation and logs each once. It returns
+// an *errors.DeprecationError if there are any and StrictDeprecations is
+// set.
+func (c *BaseClient) reportDeprecations(ctx context.Context, operationName string, warnings []DeprecationWarning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		w.OperationName = operationName
+		messages = append(messages, w.String())
+		if c.OnDeprecation != nil {
+			c.OnDeprecation(w)
+		}
+		if c.Logger != nil && c.firstDeprecation(w) {
+			c.Logger.WarnContext(ctx, "deprecated field used", "operation", operationName, "field", w.Field, "message", w.String())
+		}
+	}
+
+	if c.StrictDeprecations {
+		return &errors.DeprecationError{OperationName: operationName, Warnings: messages}
+	}
+	return nil
+}
+
+// firstDeprecation returns true the first time w is seen for its
+// operation, so repeated requests do not flood the log
+func (c *BaseClient) firstDeprecation(w DeprecationWarning) bool {
+	key := w.OperationName + "\x00" + w.Field + "\x00" + w.Message
+
+	c.deprecationsMu.Lock()
+	defer c.deprecationsMu.Unlock()
+
+	if c.deprecationsLogged[key] {
+		return false
+	}
+	if c.deprecationsLogged == nil {
+		c.deprecationsLogged = make(map[string]bool)
+	}
+	if len(c.deprecationsLogged) < maxLoggedDeprecations {
+		c.deprecationsLogged[key] = true
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+const deprecatedResponse = `{
+	"data": {"contract": {"id": "c-1", "title": "API client"}},
+	"extensions": {"warnings": [
+		{"message": "Contract.title is deprecated: use Contract.name", "field": "Contract.title", "reason": "use Contract.name", "path": ["contract", "title"]}
+	]}
+}`
+
+func newDeprecationServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Batch") != "" {
+			w.Write([]byte(`[` + deprecatedResponse + `,{"data": {"contract": {"id": "c-2"}}}]`))
+			return
+		}
+		w.Write([]byte(deprecatedResponse))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type deprecatedResult struct {
+	Contract struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"contract"`
+}
+
+var deprecatedQuery = &GraphQLRequest{Query: "query GetContract { contract { id title } }"}
+
+func TestDeprecationWarnings(t *testing.T) {
+	server := newDeprecationServer(t)
+
+	var mu sync.Mutex
+	var warnings []DeprecationWarning
+	var logs bytes.Buffer
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		OnDeprecation: func(w DeprecationWarning) {
+			mu.Lock()
+			defer mu.Unlock()
+			warnings = append(warnings, w)
+		},
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+
+	for i := 0; i < 2; i++ {
+		var result deprecatedResult
+		require.NoError(t, client.Do(context.Background(), deprecatedQuery, &result))
+		assert.Equal(t, "API client", result.Contract.Title)
+	}
+
+	require.Len(t, warnings, 2, "the hook sees every response")
+	assert.Equal(t, DeprecationWarning{
+		OperationName: "GetContract",
+		Message:       "Contract.title is deprecated: use Contract.name",
+		Field:         "Contract.title",
+		Reason:        "use Contract.name",
+		Path:          []interface{}{"contract", "title"},
+	}, warnings[0])
+
+	assert.Equal(t, 1, strings.Count(logs.String(), "deprecated field used"), "each warning is logged once")
+	assert.Contains(t, logs.String(), "operation=GetContract")
+	assert.Contains(t, logs.String(), "field=Contract.title")
+}
+
+func TestStrictDeprecations(t *testing.T) {
+	server := newDeprecationServer(t)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, StrictDeprecations: true}
+
+	var result deprecatedResult
+	err := client.Do(context.Background(), deprecatedQuery, &result)
+	require.ErrorIs(t, err, errors.ErrDeprecatedField)
+	assert.Equal(t, "c-1", result.Contract.ID, "the result is decoded regardless")
+
+	var deprecationErr *errors.DeprecationError
+	require.ErrorAs(t, err, &deprecationErr)
+	assert.Equal(t, "GetContract", deprecationErr.OperationName)
+	assert.Equal(t, []string{"Contract.title is deprecated: use Contract.name"}, deprecationErr.Warnings)
+
+	// Only the request that used a deprecated field fails in a batch
+	results := []interface{}{&deprecatedResult{}, &deprecatedResult{}}
+	batch, err := client.DoBatch(context.Background(), []*GraphQLRequest{deprecatedQuery, {Query: "query GetOther { contract { id } }"}}, results, WithHeader("X-Batch", "1"))
+	require.Error(t, err)
+	assert.ErrorIs(t, batch.Errors[0], errors.ErrDeprecatedField)
+	assert.True(t, batch.Succeeded(1))
+	assert.Equal(t, "c-2", results[1].(*deprecatedResult).Contract.ID)
+}
+
+func TestDeprecationWarningString(t *testing.T) {
+	assert.Equal(t, "gone soon", DeprecationWarning{Message: "gone soon", Field: "Job.budget"}.String())
+	assert.Equal(t, "Job.budget: use Job.amount", DeprecationWarning{Field: "Job.budget", Reason: "use Job.amount"}.String())
+	assert.Equal(t, "Job.budget is deprecated", DeprecationWarning{Field: "Job.budget"}.String())
+}
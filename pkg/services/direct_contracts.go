@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// DirectContractsService handles Direct Contract API operations. Direct
+// Contracts let a freelancer bill a client they found outside Upwork; the
+// client signs the contract by email.
+type DirectContractsService struct {
+	client *BaseClient
+}
+
+// NewDirectContractsService creates a new direct contracts service
+func NewDirectContractsService(client *BaseClient) *DirectContractsService {
+	return &DirectContractsService{client: client}
+}
+
+// DirectContractStatus represents the status of a direct contract
+type DirectContractStatus string
+
+const (
+	DirectContractStatusDraft            DirectContractStatus = "DRAFT"
+	DirectContractStatusPendingSignature DirectContractStatus = "PENDING_SIGNATURE"
+	DirectContractStatusActive           DirectContractStatus = "ACTIVE"
+	DirectContractStatusCompleted        DirectContractStatus = "COMPLETED"
+	DirectContractStatusDeclined         DirectContractStatus = "DECLINED"
+	DirectContractStatusCancelled        DirectContractStatus = "CANCELLED"
+	DirectContractStatusUnknown          DirectContractStatus = "UNKNOWN"
+)
+
+// IsFinal returns true once a direct contract can no longer change status
+func (d DirectContractStatus) IsFinal() bool {
+	switch d {
+	case DirectContractStatusCompleted, DirectContractStatusDeclined, DirectContractStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// DirectContract represents a contract with a client outside Upwork
+type DirectContract struct {
+	ID              models.ID            `json:"id"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description"`
+	Status          DirectContractStatus `json:"status"`
+	Client          DirectContractClient `json:"client"`
+	HourlyTerms     *HourlyOfferTerms    `json:"hourlyTerms"`
+	Milestones      []Milestone          `json:"milestones"`
+	CreatedDateTime models.DateTime      `json:"createdDateTime"`
+	// SentDateTime and SignedDateTime are nil until the contract is sent
+	// for signature and signed
+	SentDateTime   *models.DateTime `json:"sentDateTime"`
+	SignedDateTime *models.DateTime `json:"signedDateTime"`
+	// StatusHistory lists every status the contract has had, oldest first
+	StatusHistory []DirectContractStatusChange `json:"statusHistory"`
+}
+
+// DirectContractClient represents the client party of a direct contract
+type DirectContractClient struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Company string `json:"company,omitempty"`
+}
+
+// DirectContractStatusChange records a direct contract entering a status
+type DirectContractStatusChange struct {
+	Status          DirectContractStatus `json:"status"`
+	ChangedDateTime models.DateTime      `json:"changedDateTime"`
+}
+
+// CreateDirectContractInput represents a draft direct contract. Setting
+// HourlyTerms makes an hourly contract; setting FixedPriceTerms makes a
+// fixed-price one.
+type CreateDirectContractInput struct {
+	Title           string                `json:"title"`
+	Description     string                `json:"description,omitempty"`
+	Client          DirectContractClient  `json:"client"`
+	HourlyTerms     *HourlyOfferTerms     `json:"hourlyTerms,omitempty"`
+	FixedPriceTerms *FixedPriceOfferTerms `json:"fixedPriceTerms,omitempty"`
+}
+
+// Validate checks the title, the client's email address and the terms
+func (in CreateDirectContractInput) Validate() error {
+	if err := firstError(
+		required("title", in.Title),
+		required("client.name", in.Client.Name),
+		required("client.email", in.Client.Email),
+		validateEmail("client.email", in.Client.Email),
+	); err != nil {
+		return err
+	}
+
+	switch {
+	case in.HourlyTerms != nil && in.FixedPriceTerms != nil:
+		return &errors.ValidationError{Field: "input", Message: "a contract cannot have both hourly and fixed-price terms"}
+	case in.HourlyTerms != nil:
+		return firstError(
+			validateMoney("hourlyTerms.hourlyRate", in.HourlyTerms.HourlyRate),
+			validateNonNegative("hourlyTerms.weeklyHoursLimit", in.HourlyTerms.WeeklyHoursLimit),
+		)
+	case in.FixedPriceTerms != nil:
+		return in.FixedPriceTerms.validate()
+	default:
+		return &errors.ValidationError{Field: "input", Message: "hourly or fixed-price terms are required"}
+	}
+}
+
+// DirectContractFilter represents direct contract filtering options
+type DirectContractFilter struct {
+	Status []DirectContractStatus `json:"status,omitempty"`
+}
+
+// ListDirectContractsInput represents input for listing direct contracts
+type ListDirectContractsInput struct {
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+	Filter     *DirectContractFilter   `json:"filter,omitempty"`
+}
+
+// DirectContractList represents a paginated list of direct contracts
+type DirectContractList struct {
+	TotalCount int                  `json:"totalCount"`
+	PageInfo   models.PageInfo      `json:"pageInfo"`
+	Edges      []DirectContractEdge `json:"edges"`
+}
+
+// DirectContractEdge represents a direct contract edge in pagination
+type DirectContractEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   DirectContract `json:"node"`
+}
+
+// directContractFields selects the fields of a DirectContract
+const directContractFields = `
+	id
+	title
+	description
+	status
+	client {
+		name
+		email
+		company
+	}
+	hourlyTerms {
+		hourlyRate {
+			rawValue
+			currency
+			displayValue
+		}
+		weeklyHoursLimit
+		manualTimeAllowed
+	}
+	milestones {
+		id
+		description
+		depositAmount {
+			rawValue
+			currency
+			displayValue
+		}
+		dueDateTime
+		state
+	}
+	createdDateTime
+	sentDateTime
+	signedDateTime
+	statusHistory {
+		status
+		changedDateTime
+	}
+`
+
+// CreateDirectContract creates a draft direct contract. Nothing is sent to
+// the client until SendForSignature is called.
+func (s *DirectContractsService) CreateDirectContract(ctx context.Context, input CreateDirectContractInput) (*DirectContract, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation CreateDirectContract($input: CreateDirectContractInput!) {
+			createDirectContract(input: $input) {` + directContractFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		CreateDirectContract DirectContract `json:"createDirectContract"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CreateDirectContract, nil
+}
+
+// SendForSignature emails a draft direct contract to the client, optionally
+// with a message, and moves it to DirectContractStatusPendingSignature
+func (s *DirectContractsService) SendForSignature(ctx context.Context, contractID, message string) (*DirectContract, error) {
+	if err := required("contractId", contractID); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation SendDirectContractForSignature($input: SendDirectContractInput!) {
+			sendDirectContractForSignature(input: $input) {` + directContractFields + `}
+		}
+	`
+
+	input := map[string]interface{}{
+		"contractId": contractID,
+	}
+	if message != "" {
+		input["message"] = message
+	}
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		SendDirectContractForSignature DirectContract `json:"sendDirectContractForSignature"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.SendDirectContractForSignature, nil
+}
+
+// ListDirectContracts returns a list of direct contracts
+func (s *DirectContractsService) ListDirectContracts(ctx context.Context, input ListDirectContractsInput) (*DirectContractList, error) {
+	if input.Filter != nil {
+		for _, status := range input.Filter.Status {
+			if err := validateEnum("filter.status", status, status.IsValid()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	query := `
+		query ListDirectContracts($pagination: Pagination, $filter: DirectContractFilter) {
+			directContractList(pagination: $pagination, filter: $filter) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + directContractFields + `}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+	if input.Filter != nil {
+		variables["filter"] = input.Filter
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		DirectContractList DirectContractList `json:"directContractList"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.DirectContractList, nil
+}
+
+// GetDirectContract returns a direct contract by ID with its status history
+func (s *DirectContractsService) GetDirectContract(ctx context.Context, contractID string) (*DirectContract, error) {
+	if err := required("contractId", contractID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetDirectContract($id: ID!) {
+			directContract(id: $id) {` + directContractFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": contractID,
+		},
+	}
+
+	var resp struct {
+		DirectContract DirectContract `json:"directContract"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.DirectContract, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestCreateDirectContractInputValidate(t *testing.T) {
+	valid := CreateDirectContractInput{
+		Title:       "Website redesign",
+		Client:      DirectContractClient{Name: "Jane Doe", Email: "jane@example.com"},
+		HourlyTerms: &HourlyOfferTerms{HourlyRate: models.MustMoney("75", "USD")},
+	}
+	assert.NoError(t, valid.Validate())
+
+	badEmail := valid
+	badEmail.Client.Email = "jane at example.com"
+	assertValidationField(t, badEmail.Validate(), "client.email")
+
+	noTerms := valid
+	noTerms.HourlyTerms = nil
+	assertValidationField(t, noTerms.Validate(), "input")
+
+	fixed := valid
+	fixed.HourlyTerms = nil
+	fixed.FixedPriceTerms = &FixedPriceOfferTerms{Milestones: []OfferMilestoneInput{{Description: "Mockups"}}}
+	assertValidationField(t, fixed.Validate(), "fixedPriceTerms.milestones[0].depositAmount")
+
+	assert.True(t, DirectContractStatusDeclined.IsFinal())
+	assert.False(t, DirectContractStatusPendingSignature.IsFinal())
+}
+
+func TestDirectContracts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "createDirectContract"):
+			input := req.Variables["input"].(map[string]interface{})
+			assert.Equal(t, "Website redesign", input["title"])
+			assert.NotContains(t, input, "fixedPriceTerms")
+			w.Write([]byte(`{"data":{"createDirectContract":{"id":"dc-1","status":"DRAFT"}}}`))
+		case strings.Contains(req.Query, "sendDirectContractForSignature"):
+			assert.Equal(t, map[string]interface{}{"contractId": "dc-1"}, req.Variables["input"])
+			w.Write([]byte(`{"data":{"sendDirectContractForSignature":{"id":"dc-1","status":"PENDING_SIGNATURE",
+				"statusHistory":[{"status":"DRAFT"},{"status":"PENDING_SIGNATURE"}]}}}`))
+		case strings.Contains(req.Query, "directContractList"):
+			assert.Equal(t, map[string]interface{}{"status": []interface{}{"ACTIVE"}}, req.Variables["filter"])
+			w.Write([]byte(`{"data":{"directContractList":{"totalCount":1,"edges":[{"node":{"id":"dc-2","status":"ACTIVE"}}]}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewDirectContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	contract, err := svc.CreateDirectContract(ctx, CreateDirectContractInput{
+		Title:       "Website redesign",
+		Client:      DirectContractClient{Name: "Jane Doe", Email: "jane@example.com"},
+		HourlyTerms: &HourlyOfferTerms{HourlyRate: models.MustMoney("75", "USD")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DirectContractStatusDraft, contract.Status)
+
+	contract, err = svc.SendForSignature(ctx, string(contract.ID), "")
+	require.NoError(t, err)
+	assert.Equal(t, DirectContractStatusPendingSignature, contract.Status)
+	assert.Len(t, contract.StatusHistory, 2)
+
+	list, err := svc.ListDirectContracts(ctx, ListDirectContractsInput{
+		Filter: &DirectContractFilter{Status: []DirectContractStatus{DirectContractStatusActive}},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Edges, 1)
+	assert.Equal(t, models.ID("dc-2"), list.Edges[0].Node.ID)
+
+	_, err = svc.SendForSignature(ctx, "", "")
+	assertValidationField(t, err, "contractId")
+}
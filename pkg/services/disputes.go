@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// DisputesService handles dispute and refund API operations
+type DisputesService struct {
+	client *BaseClient
+}
+
+// NewDisputesService creates a new disputes service
+func NewDisputesService(client *BaseClient) *DisputesService {
+	return &DisputesService{client: client}
+}
+
+// DisputeType represents what a dispute contests
+type DisputeType string
+
+const (
+	DisputeTypeRefund    DisputeType = "REFUND"
+	DisputeTypeHourly    DisputeType = "HOURLY"
+	DisputeTypeMilestone DisputeType = "MILESTONE"
+	DisputeTypeUnknown   DisputeType = "UNKNOWN"
+)
+
+// DisputeStatus represents the status of a dispute
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen             DisputeStatus = "OPEN"
+	DisputeStatusAwaitingResponse DisputeStatus = "AWAITING_RESPONSE"
+	DisputeStatusInMediation      DisputeStatus = "IN_MEDIATION"
+	DisputeStatusResolved         DisputeStatus = "RESOLVED"
+	DisputeStatusCancelled        DisputeStatus = "CANCELLED"
+	DisputeStatusUnknown          DisputeStatus = "UNKNOWN"
+)
+
+// DisputeAction represents a response to a dispute
+type DisputeAction string
+
+const (
+	DisputeActionAccept       DisputeAction = "ACCEPT"
+	DisputeActionReject       DisputeAction = "REJECT"
+	DisputeActionCounterOffer DisputeAction = "COUNTER_OFFER"
+	DisputeActionUnknown      DisputeAction = "UNKNOWN"
+)
+
+// Dispute represents a contested transaction on a contract
+type Dispute struct {
+	ID                   models.ID        `json:"id"`
+	ContractID           models.ID        `json:"contractId"`
+	Type                 DisputeType      `json:"type"`
+	Status               DisputeStatus    `json:"status"`
+	Reason               string           `json:"reason"`
+	Amount               models.Money     `json:"amount"`
+	RefundedAmount       *models.Money    `json:"refundedAmount"`
+	RelatedTransactionID string           `json:"relatedTransactionId"`
+	OpenedBy             *models.User     `json:"openedBy"`
+	CreatedDateTime      models.DateTime  `json:"createdDateTime"`
+	ResponseDueDateTime  *models.DateTime `json:"responseDueDateTime"`
+	ResolvedDateTime     *models.DateTime `json:"resolvedDateTime"`
+}
+
+// DisputeFilter represents dispute filtering options
+type DisputeFilter struct {
+	Status     []DisputeStatus `json:"status,omitempty"`
+	ContractID string          `json:"contractId,omitempty"`
+}
+
+// ListDisputesInput represents input for listing disputes
+type ListDisputesInput struct {
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+	Filter     *DisputeFilter          `json:"filter,omitempty"`
+}
+
+// DisputeList represents a paginated list of disputes
+type DisputeList struct {
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+	Edges      []DisputeEdge   `json:"edges"`
+}
+
+// DisputeEdge represents a dispute edge in pagination
+type DisputeEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   Dispute `json:"node"`
+}
+
+// RespondToDisputeInput represents input for responding to a dispute
+type RespondToDisputeInput struct {
+	DisputeID string        `json:"disputeId"`
+	Action    DisputeAction `json:"action"`
+	Message   string        `json:"message,omitempty"`
+	// CounterAmount is the amount offered when Action is
+	// DisputeActionCounterOffer
+	CounterAmount *models.Money `json:"counterAmount,omitempty"`
+}
+
+// Validate checks the dispute and action, and that a counter offer has a
+// positive amount
+func (in RespondToDisputeInput) Validate() error {
+	if err := firstError(
+		required("disputeId", in.DisputeID),
+		required("action", string(in.Action)),
+		validateEnum("action", in.Action, in.Action.IsValid()),
+	); err != nil {
+		return err
+	}
+	if in.Action == DisputeActionCounterOffer {
+		if in.CounterAmount == nil {
+			return &errors.ValidationError{Field: "counterAmount", Message: "is required for a counter offer"}
+		}
+		return validateMoney("counterAmount", *in.CounterAmount)
+	}
+	return nil
+}
+
+// disputeFields selects the fields of a Dispute
+const disputeFields = `
+	id
+	contractId
+	type
+	status
+	reason
+	amount {
+		rawValue
+		currency
+		displayValue
+	}
+	refundedAmount {
+		rawValue
+		currency
+		displayValue
+	}
+	relatedTransactionId
+	openedBy {
+		id
+		name
+	}
+	createdDateTime
+	responseDueDateTime
+	resolvedDateTime
+`
+
+// ListDisputes returns a list of disputes
+func (s *DisputesService) ListDisputes(ctx context.Context, input ListDisputesInput) (*DisputeList, error) {
+	query := `
+		query ListDisputes($pagination: Pagination, $filter: DisputeFilter) {
+			disputeList(pagination: $pagination, filter: $filter) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + disputeFields + `}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+	if input.Filter != nil {
+		variables["filter"] = input.Filter
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		DisputeList DisputeList `json:"disputeList"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.DisputeList, nil
+}
+
+// GetDispute returns a dispute by ID
+func (s *DisputesService) GetDispute(ctx context.Context, disputeID string) (*Dispute, error) {
+	query := `
+		query GetDispute($id: ID!) {
+			dispute(id: $id) {` + disputeFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": disputeID,
+		},
+	}
+
+	var resp struct {
+		Dispute Dispute `json:"dispute"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Dispute, nil
+}
+
+// RequestRefund asks the other party of a contract to refund amount. The
+// request is tracked as a dispute until it is accepted or resolved.
+func (s *DisputesService) RequestRefund(ctx context.Context, contractID string, amount models.Money, reason string) (*Dispute, error) {
+	if err := firstError(
+		required("contractId", contractID),
+		validateMoney("amount", amount),
+	); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation RequestRefund($input: RequestRefundInput!) {
+			requestRefund(input: $input) {` + disputeFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": map[string]interface{}{
+				"contractId": contractID,
+				"amount":     amount,
+				"reason":     reason,
+			},
+		},
+	}
+
+	var resp struct {
+		RequestRefund Dispute `json:"requestRefund"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.RequestRefund, nil
+}
+
+// RespondToDispute accepts, rejects or makes a counter offer on a dispute
+func (s *DisputesService) RespondToDispute(ctx context.Context, input RespondToDisputeInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation RespondToDispute($input: RespondToDisputeInput!) {
+			respondToDispute(input: $input) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		RespondToDispute struct {
+			Success bool `json:"success"`
+		} `json:"respondToDispute"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.RespondToDispute.Success {
+		return fmt.Errorf("failed to respond to dispute")
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestListDisputes(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"disputeList":{"totalCount":1,"edges":[{"cursor":"0","node":{
+			"id":"dispute-1","contractId":"contract-1","type":"REFUND","status":"OPEN",
+			"amount":{"rawValue":"120.50","currency":"USD"},"relatedTransactionId":"tx-1"
+		}}]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewDisputesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	list, err := svc.ListDisputes(context.Background(), ListDisputesInput{
+		Filter: &DisputeFilter{Status: []DisputeStatus{DisputeStatusOpen}},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Edges, 1)
+	dispute := list.Edges[0].Node
+	assert.Equal(t, DisputeTypeRefund, dispute.Type)
+	assert.True(t, dispute.Amount.Equal(models.MustMoney("120.50", "USD")))
+	assert.Equal(t, "tx-1", dispute.RelatedTransactionID)
+
+	assert.Equal(t, map[string]interface{}{"status": []interface{}{"OPEN"}}, req.Variables["filter"])
+	assert.NotContains(t, req.Variables, "pagination")
+}
+
+func TestRequestRefund(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"requestRefund":{"id":"dispute-2","status":"AWAITING_RESPONSE"}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewDisputesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	dispute, err := svc.RequestRefund(context.Background(), "contract-1", models.MustMoney("50", "USD"), "Work not delivered")
+	require.NoError(t, err)
+	assert.Equal(t, DisputeStatusAwaitingResponse, dispute.Status)
+
+	input := req.Variables["input"].(map[string]interface{})
+	assert.Equal(t, "contract-1", input["contractId"])
+	assert.Equal(t, "Work not delivered", input["reason"])
+	assert.Equal(t, map[string]interface{}{"rawValue": "50.00", "currency": "USD"}, input["amount"])
+
+	_, err = svc.RequestRefund(context.Background(), "contract-1", models.MustMoney("0", "USD"), "")
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "amount", validationErr.Field)
+}
+
+func TestRespondToDispute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"respondToDispute":{"success":true}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewDisputesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	require.NoError(t, svc.RespondToDispute(context.Background(), RespondToDisputeInput{
+		DisputeID: "dispute-1",
+		Action:    DisputeActionReject,
+	}))
+
+	err := svc.RespondToDispute(context.Background(), RespondToDisputeInput{
+		DisputeID: "dispute-1",
+		Action:    DisputeActionCounterOffer,
+	})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "counterAmount", validationErr.Field)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// EarningsItemType represents the kind of an earnings line item
+type EarningsItemType string
+
+const (
+	EarningsItemHourly      EarningsItemType = "HOURLY"
+	EarningsItemFixedPrice  EarningsItemType = "FIXED_PRICE"
+	EarningsItemBonus       EarningsItemType = "BONUS"
+	EarningsItemRefund      EarningsItemType = "REFUND"
+	EarningsItemServiceFee  EarningsItemType = "SERVICE_FEE"
+	EarningsItemTaxWithheld EarningsItemType = "TAX_WITHHOLDING"
+	EarningsItemTypeUnknown EarningsItemType = "UNKNOWN"
+)
+
+// EarningsItemStatus represents whether earnings can be withdrawn yet
+type EarningsItemStatus string
+
+const (
+	EarningsItemPending       EarningsItemStatus = "PENDING"
+	EarningsItemAvailable     EarningsItemStatus = "AVAILABLE"
+	EarningsItemStatusUnknown EarningsItemStatus = "UNKNOWN"
+)
+
+// EarningsItem is a single earnings transaction. Amounts are positive;
+// the type determines whether the item adds to or deducts from earnings.
+type EarningsItem struct {
+	ID            models.ID          `json:"id"`
+	Type          EarningsItemType   `json:"type"`
+	Status        EarningsItemStatus `json:"status"`
+	ContractID    models.ID          `json:"contractId"`
+	ContractTitle string             `json:"contractTitle"`
+	Description   string             `json:"description"`
+	Amount        models.Money       `json:"amount"`
+	// DateTime is the RFC 3339 time of the transaction
+	DateTime string `json:"dateTime"`
+}
+
+// EarningsTotals holds aggregated earnings
+type EarningsTotals struct {
+	// Gross is the total earned before fees and taxes, less refunds
+	Gross         models.Money
+	ServiceFees   models.Money
+	TaxesWithheld models.Money
+	// Net is Gross less ServiceFees and TaxesWithheld
+	Net models.Money
+	// Pending is the part of Net that is not yet available for withdrawal
+	Pending models.Money
+}
+
+// ContractEarnings holds the earnings from one contract
+type ContractEarnings struct {
+	ContractID    models.ID
+	ContractTitle string
+	EarningsTotals
+}
+
+// MonthlyEarnings holds the earnings for one calendar month
+type MonthlyEarnings struct {
+	// Month is midnight UTC on the first day of the month
+	Month time.Time
+	EarningsTotals
+}
+
+// Earnings summarizes a freelancer's earnings over a date range
+type Earnings struct {
+	DateRange models.DateRange
+	EarningsTotals
+
+	// ByContract is ordered by the first transaction on each contract
+	ByContract []ContractEarnings
+	// ByMonth is in chronological order
+	ByMonth []MonthlyEarnings
+
+	Items []EarningsItem
+}
+
+// add folds an item into the totals
+func (t *EarningsTotals) add(item EarningsItem) error {
+	var err error
+
+	// signed is the item's effect on net earnings
+	signed := item.Amount
+	switch item.Type {
+	case EarningsItemServiceFee:
+		t.ServiceFees, err = t.ServiceFees.Add(item.Amount)
+		signed = item.Amount.Neg()
+	case EarningsItemTaxWithheld:
+		t.TaxesWithheld, err = t.TaxesWithheld.Add(item.Amount)
+		signed = item.Amount.Neg()
+	case EarningsItemRefund:
+		t.Gross, err = t.Gross.Sub(item.Amount)
+		signed = item.Amount.Neg()
+	default:
+		t.Gross, err = t.Gross.Add(item.Amount)
+	}
+	if err != nil {
+		return errors.WrapError(err, "failed to add earnings")
+	}
+
+	if t.Net, err = t.Net.Add(signed); err != nil {
+		return errors.WrapError(err, "failed to add earnings")
+	}
+	if item.Status == EarningsItemPending {
+		if t.Pending, err = t.Pending.Add(signed); err != nil {
+			return errors.WrapError(err, "failed to add earnings")
+		}
+	}
+
+	return nil
+}
+
+// GetEarnings returns gross earnings, service fees, taxes withheld and
+// pending balances over dateRange, in total and broken down by contract and
+// by month
+func (s *ReportsService) GetEarnings(ctx context.Context, dateRange models.DateRange) (*Earnings, error) {
+	query := `
+		query FreelancerEarnings($dateTime_bt: DateTimeRange!) {
+			freelancerEarnings(filter: {dateTime_bt: $dateTime_bt}) {
+				items {
+					id
+					type
+					status
+					contractId
+					contractTitle
+					description
+					amount {
+						rawValue
+						currency
+						displayValue
+					}
+					dateTime
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"dateTime_bt": dateRange,
+		},
+	}
+
+	var resp struct {
+		FreelancerEarnings struct {
+			Items []EarningsItem `json:"items"`
+		} `json:"freelancerEarnings"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return summarizeEarnings(dateRange, resp.FreelancerEarnings.Items)
+}
+
+// summarizeEarnings aggregates items by contract and by month
+func summarizeEarnings(dateRange models.DateRange, items []EarningsItem) (*Earnings, error) {
+	earnings := &Earnings{DateRange: dateRange, Items: items}
+
+	contracts := map[models.ID]int{}
+	months := map[time.Time]*MonthlyEarnings{}
+
+	for _, item := range items {
+		if err := earnings.add(item); err != nil {
+			return nil, err
+		}
+
+		i, ok := contracts[item.ContractID]
+		if !ok {
+			i = len(earnings.ByContract)
+			contracts[item.ContractID] = i
+			earnings.ByContract = append(earnings.ByContract, ContractEarnings{
+				ContractID:    item.ContractID,
+				ContractTitle: item.ContractTitle,
+			})
+		}
+		if err := earnings.ByContract[i].add(item); err != nil {
+			return nil, err
+		}
+
+		month, err := itemMonth(item)
+		if err != nil {
+			continue
+		}
+		if months[month] == nil {
+			months[month] = &MonthlyEarnings{Month: month}
+		}
+		if err := months[month].add(item); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range months {
+		earnings.ByMonth = append(earnings.ByMonth, *m)
+	}
+	sort.Slice(earnings.ByMonth, func(i, j int) bool {
+		return earnings.ByMonth[i].Month.Before(earnings.ByMonth[j].Month)
+	})
+
+	return earnings, nil
+}
+
+// itemMonth returns the first day of the month of the item's transaction
+func itemMonth(item EarningsItem) (time.Time, error) {
+	return time.Parse("2006-01", firstN(item.DateTime, len("2006-01")))
+}
+
+// WriteCSV writes the line items as CSV with a header row
+func (e *Earnings) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"date", "contract_id", "contract", "type", "status", "description", "amount", "currency",
+	})
+	for _, item := range e.Items {
+		cw.Write([]string{
+			firstN(item.DateTime, len("2006-01-02")),
+			string(item.ContractID),
+			item.ContractTitle,
+			string(item.Type),
+			string(item.Status),
+			item.Description,
+			item.Amount.Amount(),
+			item.Amount.Currency,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+const earningsResponse = `{"data":{"freelancerEarnings":{"items":[
+	{"id":"1","type":"HOURLY","status":"AVAILABLE","contractId":"c-1","contractTitle":"API work","amount":{"rawValue":"1000.00","currency":"USD"},"dateTime":"2024-01-10T12:00:00Z"},
+	{"id":"2","type":"SERVICE_FEE","status":"AVAILABLE","contractId":"c-1","contractTitle":"API work","amount":{"rawValue":"100.00","currency":"USD"},"dateTime":"2024-01-10T12:00:00Z"},
+	{"id":"3","type":"FIXED_PRICE","status":"PENDING","contractId":"c-2","contractTitle":"Logo","description":"Milestone 1","amount":{"rawValue":"500.00","currency":"USD"},"dateTime":"2024-02-03T09:00:00Z"},
+	{"id":"4","type":"SERVICE_FEE","status":"PENDING","contractId":"c-2","contractTitle":"Logo","amount":{"rawValue":"50.00","currency":"USD"},"dateTime":"2024-02-03T09:00:00Z"},
+	{"id":"5","type":"TAX_WITHHOLDING","status":"PENDING","contractId":"c-2","contractTitle":"Logo","amount":{"rawValue":"20.00","currency":"USD"},"dateTime":"2024-02-03T09:00:00Z"},
+	{"id":"6","type":"HOURLY","status":"AVAILABLE","contractId":"c-1","contractTitle":"API work","amount":{"rawValue":"200.00","currency":"USD"},"dateTime":"2024-02-20T12:00:00Z"}
+]}}}`
+
+func usd(amount string) models.Money {
+	return models.MustMoney(amount, "USD")
+}
+
+func TestGetEarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, earningsResponse)
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	earnings, err := svc.GetEarnings(context.Background(), models.DateRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.True(t, earnings.Gross.Equal(usd("1700")), earnings.Gross.String())
+	assert.True(t, earnings.ServiceFees.Equal(usd("150")))
+	assert.True(t, earnings.TaxesWithheld.Equal(usd("20")))
+	assert.True(t, earnings.Net.Equal(usd("1530")))
+	assert.True(t, earnings.Pending.Equal(usd("430")))
+
+	require.Len(t, earnings.ByContract, 2)
+	assert.Equal(t, models.ID("c-1"), earnings.ByContract[0].ContractID)
+	assert.True(t, earnings.ByContract[0].Net.Equal(usd("1100")))
+	assert.Equal(t, "Logo", earnings.ByContract[1].ContractTitle)
+	assert.True(t, earnings.ByContract[1].Pending.Equal(usd("430")))
+
+	require.Len(t, earnings.ByMonth, 2)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), earnings.ByMonth[0].Month)
+	assert.True(t, earnings.ByMonth[0].Gross.Equal(usd("1000")))
+	assert.True(t, earnings.ByMonth[1].Gross.Equal(usd("700")))
+}
+
+func TestEarningsWriteCSV(t *testing.T) {
+	earnings := &Earnings{Items: []EarningsItem{
+		{Type: EarningsItemFixedPrice, Status: EarningsItemPending, ContractID: "c-2", ContractTitle: "Logo, v2",
+			Amount: usd("500"), DateTime: "2024-02-03T09:00:00Z"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, earnings.WriteCSV(&buf))
+	assert.Equal(t, "date,contract_id,contract,type,status,description,amount,currency\n"+
+		"2024-02-03,c-2,\"Logo, v2\",FIXED_PRICE,PENDING,,500.00,USD\n", buf.String())
+}
@@ -0,0 +1,872 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// unmarshalEnum decodes a JSON string into v. Values matching one of
+// values, ignoring case, are stored in canonical form; others are kept
+// verbatim so they survive a round trip. null leaves v unchanged.
+func unmarshalEnum[T ~string](data []byte, v *T, values []T) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	for _, known := range values {
+		if strings.EqualFold(s, string(known)) {
+			*v = known
+			return nil
+		}
+	}
+
+	*v = T(s)
+	return nil
+}
+
+// normalizeEnum converts a user-supplied value such as "fixed-price" to
+// the API's form, "FIXED_PRICE"
+func normalizeEnum(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_"))
+}
+
+// isKnownEnum returns true if v is one of values
+func isKnownEnum[T ~string](v T, values []T) bool {
+	for _, known := range values {
+		if v == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the known contract type values
+func (ContractType) Values() []ContractType {
+	return []ContractType{
+		ContractTypeHourly,
+		ContractTypeFixedPrice,
+	}
+}
+
+// IsValid returns true if c is a known contract type
+func (c ContractType) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or ContractTypeUnknown if it is not a known contract type
+func (c ContractType) Known() ContractType {
+	if !c.IsValid() {
+		return ContractTypeUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any contract type, keeping unknown values verbatim
+func (c *ContractType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known contract status values
+func (ContractStatus) Values() []ContractStatus {
+	return []ContractStatus{
+		ContractStatusActive,
+		ContractStatusPaused,
+		ContractStatusEnded,
+		ContractStatusSuspended,
+	}
+}
+
+// IsValid returns true if c is a known contract status
+func (c ContractStatus) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or ContractStatusUnknown if it is not a known contract status
+func (c ContractStatus) Known() ContractStatus {
+	if !c.IsValid() {
+		return ContractStatusUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any contract status, keeping unknown values verbatim
+func (c *ContractStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known milestone state values
+func (MilestoneState) Values() []MilestoneState {
+	return []MilestoneState{
+		MilestoneStateNotFunded,
+		MilestoneStateActive,
+		MilestoneStateSubmitted,
+		MilestoneStateApproved,
+		MilestoneStateRejected,
+		MilestoneStatePaid,
+		MilestoneStateCancelled,
+	}
+}
+
+// IsValid returns true if m is a known milestone state
+func (m MilestoneState) IsValid() bool {
+	return isKnownEnum(m, m.Values())
+}
+
+// Known returns m, or MilestoneStateUnknown if it is not a known milestone state
+func (m MilestoneState) Known() MilestoneState {
+	if !m.IsValid() {
+		return MilestoneStateUnknown
+	}
+	return m
+}
+
+// UnmarshalJSON accepts any milestone state, keeping unknown values verbatim
+func (m *MilestoneState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, m, m.Values())
+}
+
+// Values returns the known escrow transition types
+func (EscrowTransitionType) Values() []EscrowTransitionType {
+	return []EscrowTransitionType{
+		EscrowTransitionFund,
+		EscrowTransitionRelease,
+		EscrowTransitionRefund,
+	}
+}
+
+// IsValid returns true if t is a known escrow transition type
+func (t EscrowTransitionType) IsValid() bool {
+	return isKnownEnum(t, t.Values())
+}
+
+// Known returns t, or EscrowTransitionUnknown if it is not a known escrow
+// transition type
+func (t EscrowTransitionType) Known() EscrowTransitionType {
+	if !t.IsValid() {
+		return EscrowTransitionUnknown
+	}
+	return t
+}
+
+// UnmarshalJSON accepts any escrow transition type, keeping unknown values
+// verbatim
+func (t *EscrowTransitionType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t, t.Values())
+}
+
+// Values returns the known job status values
+func (JobStatus) Values() []JobStatus {
+	return []JobStatus{
+		JobStatusOpen,
+		JobStatusFilled,
+		JobStatusCancelled,
+		JobStatusDraft,
+	}
+}
+
+// IsValid returns true if j is a known job status
+func (j JobStatus) IsValid() bool {
+	return isKnownEnum(j, j.Values())
+}
+
+// Known returns j, or JobStatusUnknown if it is not a known job status
+func (j JobStatus) Known() JobStatus {
+	if !j.IsValid() {
+		return JobStatusUnknown
+	}
+	return j
+}
+
+// UnmarshalJSON accepts any job status, keeping unknown values verbatim
+func (j *JobStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, j, j.Values())
+}
+
+// Values returns the known engagement type values
+func (EngagementType) Values() []EngagementType {
+	return []EngagementType{
+		EngagementTypeAsNeeded,
+		EngagementTypePartTime,
+		EngagementTypeFullTime,
+	}
+}
+
+// IsValid returns true if e is a known engagement type
+func (e EngagementType) IsValid() bool {
+	return isKnownEnum(e, e.Values())
+}
+
+// Known returns e, or EngagementTypeUnknown if it is not a known engagement type
+func (e EngagementType) Known() EngagementType {
+	if !e.IsValid() {
+		return EngagementTypeUnknown
+	}
+	return e
+}
+
+// UnmarshalJSON accepts any engagement type, keeping unknown values verbatim
+func (e *EngagementType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, e, e.Values())
+}
+
+// Values returns the known room type values
+func (RoomType) Values() []RoomType {
+	return []RoomType{
+		RoomTypeGroup,
+		RoomTypeOneOnOne,
+		RoomTypeInterview,
+		RoomTypeContract,
+		RoomTypePublic,
+	}
+}
+
+// IsValid returns true if r is a known room type
+func (r RoomType) IsValid() bool {
+	return isKnownEnum(r, r.Values())
+}
+
+// Known returns r, or RoomTypeUnknown if it is not a known room type
+func (r RoomType) Known() RoomType {
+	if !r.IsValid() {
+		return RoomTypeUnknown
+	}
+	return r
+}
+
+// UnmarshalJSON accepts any room type, keeping unknown values verbatim
+func (r *RoomType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, r, r.Values())
+}
+
+// Values returns the known presence status values
+func (PresenceStatus) Values() []PresenceStatus {
+	return []PresenceStatus{
+		PresenceStatusOnline,
+		PresenceStatusAway,
+		PresenceStatusOffline,
+	}
+}
+
+// IsValid returns true if p is a known presence status
+func (p PresenceStatus) IsValid() bool {
+	return isKnownEnum(p, p.Values())
+}
+
+// Known returns p, or PresenceStatusUnknown if it is not a known presence status
+func (p PresenceStatus) Known() PresenceStatus {
+	if !p.IsValid() {
+		return PresenceStatusUnknown
+	}
+	return p
+}
+
+// UnmarshalJSON accepts any presence status, keeping unknown values verbatim
+func (p *PresenceStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, p, p.Values())
+}
+
+// Values returns the known reason type values
+func (ReasonType) Values() []ReasonType {
+	return []ReasonType{
+		ReasonTypeJobPostingClose,
+		ReasonTypeContractEnd,
+		ReasonTypeProposalDecline,
+	}
+}
+
+// IsValid returns true if r is a known reason type
+func (r ReasonType) IsValid() bool {
+	return isKnownEnum(r, r.Values())
+}
+
+// Known returns r, or ReasonTypeUnknown if it is not a known reason type
+func (r ReasonType) Known() ReasonType {
+	if !r.IsValid() {
+		return ReasonTypeUnknown
+	}
+	return r
+}
+
+// UnmarshalJSON accepts any reason type, keeping unknown values verbatim
+func (r *ReasonType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, r, r.Values())
+}
+
+// Values returns the known feedback category values
+func (FeedbackCategory) Values() []FeedbackCategory {
+	return []FeedbackCategory{
+		FeedbackCategorySkills,
+		FeedbackCategoryQuality,
+		FeedbackCategoryAvailability,
+		FeedbackCategoryDeadlines,
+		FeedbackCategoryCommunication,
+		FeedbackCategoryCooperation,
+		FeedbackCategoryClarity,
+	}
+}
+
+// IsValid returns true if f is a known feedback category
+func (f FeedbackCategory) IsValid() bool {
+	return isKnownEnum(f, f.Values())
+}
+
+// Known returns f, or FeedbackCategoryUnknown if it is not a known feedback category
+func (f FeedbackCategory) Known() FeedbackCategory {
+	if !f.IsValid() {
+		return FeedbackCategoryUnknown
+	}
+	return f
+}
+
+// UnmarshalJSON accepts any feedback category, keeping unknown values verbatim
+func (f *FeedbackCategory) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, f, f.Values())
+}
+
+// Values returns the known change request type values
+func (ChangeRequestType) Values() []ChangeRequestType {
+	return []ChangeRequestType{
+		ChangeRequestTypeRateChange,
+		ChangeRequestTypeTermsChange,
+	}
+}
+
+// IsValid returns true if c is a known change request type
+func (c ChangeRequestType) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or ChangeRequestTypeUnknown if it is not a known change request type
+func (c ChangeRequestType) Known() ChangeRequestType {
+	if !c.IsValid() {
+		return ChangeRequestTypeUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any change request type, keeping unknown values verbatim
+func (c *ChangeRequestType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known change request status values
+func (ChangeRequestStatus) Values() []ChangeRequestStatus {
+	return []ChangeRequestStatus{
+		ChangeRequestStatusPending,
+		ChangeRequestStatusAccepted,
+		ChangeRequestStatusDeclined,
+		ChangeRequestStatusCancelled,
+		ChangeRequestStatusExpired,
+	}
+}
+
+// IsValid returns true if c is a known change request status
+func (c ChangeRequestStatus) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or ChangeRequestStatusUnknown if it is not a known change request status
+func (c ChangeRequestStatus) Known() ChangeRequestStatus {
+	if !c.IsValid() {
+		return ChangeRequestStatusUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any change request status, keeping unknown values verbatim
+func (c *ChangeRequestStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known dispute type values
+func (DisputeType) Values() []DisputeType {
+	return []DisputeType{
+		DisputeTypeRefund,
+		DisputeTypeHourly,
+		DisputeTypeMilestone,
+	}
+}
+
+// IsValid returns true if d is a known dispute type
+func (d DisputeType) IsValid() bool {
+	return isKnownEnum(d, d.Values())
+}
+
+// Known returns d, or DisputeTypeUnknown if it is not a known dispute type
+func (d DisputeType) Known() DisputeType {
+	if !d.IsValid() {
+		return DisputeTypeUnknown
+	}
+	return d
+}
+
+// UnmarshalJSON accepts any dispute type, keeping unknown values verbatim
+func (d *DisputeType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, d, d.Values())
+}
+
+// Values returns the known dispute status values
+func (DisputeStatus) Values() []DisputeStatus {
+	return []DisputeStatus{
+		DisputeStatusOpen,
+		DisputeStatusAwaitingResponse,
+		DisputeStatusInMediation,
+		DisputeStatusResolved,
+		DisputeStatusCancelled,
+	}
+}
+
+// IsValid returns true if d is a known dispute status
+func (d DisputeStatus) IsValid() bool {
+	return isKnownEnum(d, d.Values())
+}
+
+// Known returns d, or DisputeStatusUnknown if it is not a known dispute status
+func (d DisputeStatus) Known() DisputeStatus {
+	if !d.IsValid() {
+		return DisputeStatusUnknown
+	}
+	return d
+}
+
+// UnmarshalJSON accepts any dispute status, keeping unknown values verbatim
+func (d *DisputeStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, d, d.Values())
+}
+
+// Values returns the known dispute action values
+func (DisputeAction) Values() []DisputeAction {
+	return []DisputeAction{
+		DisputeActionAccept,
+		DisputeActionReject,
+		DisputeActionCounterOffer,
+	}
+}
+
+// IsValid returns true if d is a known dispute action
+func (d DisputeAction) IsValid() bool {
+	return isKnownEnum(d, d.Values())
+}
+
+// Known returns d, or DisputeActionUnknown if it is not a known dispute action
+func (d DisputeAction) Known() DisputeAction {
+	if !d.IsValid() {
+		return DisputeActionUnknown
+	}
+	return d
+}
+
+// UnmarshalJSON accepts any dispute action, keeping unknown values verbatim
+func (d *DisputeAction) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, d, d.Values())
+}
+
+// Values returns the known earnings item type values
+func (EarningsItemType) Values() []EarningsItemType {
+	return []EarningsItemType{
+		EarningsItemHourly,
+		EarningsItemFixedPrice,
+		EarningsItemBonus,
+		EarningsItemRefund,
+		EarningsItemServiceFee,
+		EarningsItemTaxWithheld,
+	}
+}
+
+// IsValid returns true if e is a known earnings item type
+func (e EarningsItemType) IsValid() bool {
+	return isKnownEnum(e, e.Values())
+}
+
+// Known returns e, or EarningsItemTypeUnknown if it is not a known earnings item type
+func (e EarningsItemType) Known() EarningsItemType {
+	if !e.IsValid() {
+		return EarningsItemTypeUnknown
+	}
+	return e
+}
+
+// UnmarshalJSON accepts any earnings item type, keeping unknown values verbatim
+func (e *EarningsItemType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, e, e.Values())
+}
+
+// Values returns the known earnings item status values
+func (EarningsItemStatus) Values() []EarningsItemStatus {
+	return []EarningsItemStatus{
+		EarningsItemPending,
+		EarningsItemAvailable,
+	}
+}
+
+// IsValid returns true if e is a known earnings item status
+func (e EarningsItemStatus) IsValid() bool {
+	return isKnownEnum(e, e.Values())
+}
+
+// Known returns e, or EarningsItemStatusUnknown if it is not a known earnings item status
+func (e EarningsItemStatus) Known() EarningsItemStatus {
+	if !e.IsValid() {
+		return EarningsItemStatusUnknown
+	}
+	return e
+}
+
+// UnmarshalJSON accepts any earnings item status, keeping unknown values verbatim
+func (e *EarningsItemStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, e, e.Values())
+}
+
+// Values returns the known accounting entity type values
+func (AccountingEntityType) Values() []AccountingEntityType {
+	return []AccountingEntityType{
+		AccountingEntityTypeCompany,
+		AccountingEntityTypeTeam,
+		AccountingEntityTypeFreelancer,
+		AccountingEntityTypeAgency,
+	}
+}
+
+// IsValid returns true if a is a known accounting entity type
+func (a AccountingEntityType) IsValid() bool {
+	return isKnownEnum(a, a.Values())
+}
+
+// Known returns a, or AccountingEntityTypeUnknown if it is not a known accounting entity type
+func (a AccountingEntityType) Known() AccountingEntityType {
+	if !a.IsValid() {
+		return AccountingEntityTypeUnknown
+	}
+	return a
+}
+
+// UnmarshalJSON accepts any accounting entity type, keeping unknown values verbatim
+func (a *AccountingEntityType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, a, a.Values())
+}
+
+// Values returns the known staff role values
+func (StaffRole) Values() []StaffRole {
+	return []StaffRole{
+		StaffRoleAdmin,
+		StaffRoleHiringManager,
+		StaffRoleFinancialAdmin,
+		StaffRoleTeamMember,
+	}
+}
+
+// IsValid returns true if s is a known staff role
+func (s StaffRole) IsValid() bool {
+	return isKnownEnum(s, s.Values())
+}
+
+// Known returns s, or StaffRoleUnknown if it is not a known staff role
+func (s StaffRole) Known() StaffRole {
+	if !s.IsValid() {
+		return StaffRoleUnknown
+	}
+	return s
+}
+
+// UnmarshalJSON accepts any staff role, keeping unknown values verbatim
+func (s *StaffRole) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, s.Values())
+}
+
+// Values returns the known staff permission values
+func (StaffPermission) Values() []StaffPermission {
+	return []StaffPermission{
+		StaffPermissionHire,
+		StaffPermissionFinancial,
+		StaffPermissionAdmin,
+	}
+}
+
+// IsValid returns true if p is a known staff permission
+func (p StaffPermission) IsValid() bool {
+	return isKnownEnum(p, p.Values())
+}
+
+// UnmarshalJSON accepts any staff permission, keeping unknown values verbatim
+func (p *StaffPermission) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, p, p.Values())
+}
+
+// Values returns the known job sort fields
+func (JobSortField) Values() []JobSortField {
+	return []JobSortField{
+		JobSortRecency,
+		JobSortRelevance,
+		JobSortClientTotalCharge,
+	}
+}
+
+// IsValid returns true if f is a known job sort field
+func (f JobSortField) IsValid() bool {
+	return isKnownEnum(f, f.Values())
+}
+
+// Values returns the known notification type values
+func (NotificationType) Values() []NotificationType {
+	return []NotificationType{
+		NotificationTypeInterviewInvitation,
+		NotificationTypeOffer,
+		NotificationTypeProposal,
+		NotificationTypeContract,
+		NotificationTypeMilestone,
+		NotificationTypePayment,
+		NotificationTypeMessage,
+	}
+}
+
+// IsValid returns true if n is a known notification type
+func (n NotificationType) IsValid() bool {
+	return isKnownEnum(n, n.Values())
+}
+
+// Known returns n, or NotificationTypeUnknown if it is not a known notification type
+func (n NotificationType) Known() NotificationType {
+	if !n.IsValid() {
+		return NotificationTypeUnknown
+	}
+	return n
+}
+
+// UnmarshalJSON accepts any notification type, keeping unknown values verbatim
+func (n *NotificationType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, n, n.Values())
+}
+
+// Values returns the known interview invitation status values
+func (InterviewInvitationStatus) Values() []InterviewInvitationStatus {
+	return []InterviewInvitationStatus{
+		InterviewInvitationStatusPending,
+		InterviewInvitationStatusAccepted,
+		InterviewInvitationStatusDeclined,
+		InterviewInvitationStatusExpired,
+	}
+}
+
+// IsValid returns true if i is a known interview invitation status
+func (i InterviewInvitationStatus) IsValid() bool {
+	return isKnownEnum(i, i.Values())
+}
+
+// Known returns i, or InterviewInvitationStatusUnknown if it is not a known interview invitation status
+func (i InterviewInvitationStatus) Known() InterviewInvitationStatus {
+	if !i.IsValid() {
+		return InterviewInvitationStatusUnknown
+	}
+	return i
+}
+
+// UnmarshalJSON accepts any interview invitation status, keeping unknown values verbatim
+func (i *InterviewInvitationStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, i, i.Values())
+}
+
+// Values returns the known meeting status values
+func (MeetingStatus) Values() []MeetingStatus {
+	return []MeetingStatus{
+		MeetingStatusScheduled,
+		MeetingStatusCompleted,
+		MeetingStatusCancelled,
+	}
+}
+
+// IsValid returns true if m is a known meeting status
+func (m MeetingStatus) IsValid() bool {
+	return isKnownEnum(m, m.Values())
+}
+
+// Known returns m, or MeetingStatusUnknown if it is not a known meeting status
+func (m MeetingStatus) Known() MeetingStatus {
+	if !m.IsValid() {
+		return MeetingStatusUnknown
+	}
+	return m
+}
+
+// UnmarshalJSON accepts any meeting status, keeping unknown values verbatim
+func (m *MeetingStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, m, m.Values())
+}
+
+// Values returns the known agency role values
+func (AgencyRole) Values() []AgencyRole {
+	return []AgencyRole{
+		AgencyRoleOwner,
+		AgencyRoleAdmin,
+		AgencyRoleMember,
+	}
+}
+
+// IsValid returns true if a is a known agency role
+func (a AgencyRole) IsValid() bool {
+	return isKnownEnum(a, a.Values())
+}
+
+// Known returns a, or AgencyRoleUnknown if it is not a known agency role
+func (a AgencyRole) Known() AgencyRole {
+	if !a.IsValid() {
+		return AgencyRoleUnknown
+	}
+	return a
+}
+
+// UnmarshalJSON accepts any agency role, keeping unknown values verbatim
+func (a *AgencyRole) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, a, a.Values())
+}
+
+// Values returns the known direct contract status values
+func (DirectContractStatus) Values() []DirectContractStatus {
+	return []DirectContractStatus{
+		DirectContractStatusDraft,
+		DirectContractStatusPendingSignature,
+		DirectContractStatusActive,
+		DirectContractStatusCompleted,
+		DirectContractStatusDeclined,
+		DirectContractStatusCancelled,
+	}
+}
+
+// IsValid returns true if d is a known direct contract status
+func (d DirectContractStatus) IsValid() bool {
+	return isKnownEnum(d, d.Values())
+}
+
+// Known returns d, or DirectContractStatusUnknown if it is not a known
+// direct contract status
+func (d DirectContractStatus) Known() DirectContractStatus {
+	if !d.IsValid() {
+		return DirectContractStatusUnknown
+	}
+	return d
+}
+
+// UnmarshalJSON accepts any direct contract status, keeping unknown values
+// verbatim
+func (d *DirectContractStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, d, d.Values())
+}
+
+// Values returns the known catalog project status values
+func (CatalogProjectStatus) Values() []CatalogProjectStatus {
+	return []CatalogProjectStatus{
+		CatalogProjectStatusDraft,
+		CatalogProjectStatusUnderReview,
+		CatalogProjectStatusActive,
+		CatalogProjectStatusPaused,
+		CatalogProjectStatusRejected,
+	}
+}
+
+// IsValid returns true if c is a known catalog project status
+func (c CatalogProjectStatus) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or CatalogProjectStatusUnknown if it is not a known catalog project status
+func (c CatalogProjectStatus) Known() CatalogProjectStatus {
+	if !c.IsValid() {
+		return CatalogProjectStatusUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any catalog project status, keeping unknown values verbatim
+func (c *CatalogProjectStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known project tier name values
+func (ProjectTierName) Values() []ProjectTierName {
+	return []ProjectTierName{
+		ProjectTierStarter,
+		ProjectTierStandard,
+		ProjectTierAdvanced,
+	}
+}
+
+// IsValid returns true if p is a known project tier name
+func (p ProjectTierName) IsValid() bool {
+	return isKnownEnum(p, p.Values())
+}
+
+// Known returns p, or ProjectTierUnknown if it is not a known project tier name
+func (p ProjectTierName) Known() ProjectTierName {
+	if !p.IsValid() {
+		return ProjectTierUnknown
+	}
+	return p
+}
+
+// UnmarshalJSON accepts any project tier name, keeping unknown values verbatim
+func (p *ProjectTierName) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, p, p.Values())
+}
+
+// Values returns the known catalog order status values
+func (CatalogOrderStatus) Values() []CatalogOrderStatus {
+	return []CatalogOrderStatus{
+		CatalogOrderStatusActive,
+		CatalogOrderStatusDelivered,
+		CatalogOrderStatusCompleted,
+		CatalogOrderStatusCancelled,
+	}
+}
+
+// IsValid returns true if c is a known catalog order status
+func (c CatalogOrderStatus) IsValid() bool {
+	return isKnownEnum(c, c.Values())
+}
+
+// Known returns c, or CatalogOrderStatusUnknown if it is not a known catalog order status
+func (c CatalogOrderStatus) Known() CatalogOrderStatus {
+	if !c.IsValid() {
+		return CatalogOrderStatusUnknown
+	}
+	return c
+}
+
+// UnmarshalJSON accepts any catalog order status, keeping unknown values verbatim
+func (c *CatalogOrderStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c, c.Values())
+}
+
+// Values returns the known audit event type values
+func (AuditEventType) Values() []AuditEventType {
+	return []AuditEventType{
+		AuditEventStaffAdded,
+		AuditEventStaffRemoved,
+		AuditEventPermissionChanged,
+		AuditEventAPIKeyCreated,
+		AuditEventAPIKeyRevoked,
+		AuditEventAPIKeyUsed,
+		AuditEventLogin,
+	}
+}
+
+// IsValid returns true if a is a known audit event type
+func (a AuditEventType) IsValid() bool {
+	return isKnownEnum(a, a.Values())
+}
+
+// Known returns a, or AuditEventUnknown if it is not a known audit event type
+func (a AuditEventType) Known() AuditEventType {
+	if !a.IsValid() {
+		return AuditEventUnknown
+	}
+	return a
+}
+
+// UnmarshalJSON accepts any audit event type, keeping unknown values verbatim
+func (a *AuditEventType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, a, a.Values())
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumUnmarshalJSON(t *testing.T) {
+	var contract struct {
+		Status ContractStatus `json:"status"`
+		Type   ContractType   `json:"contractType"`
+	}
+
+	// Known values are canonicalized, unknown ones kept verbatim
+	require.NoError(t, json.Unmarshal([]byte(`{"status":"paused","contractType":"RETAINER"}`), &contract))
+	assert.Equal(t, ContractStatusPaused, contract.Status)
+	assert.True(t, contract.Status.IsValid())
+	assert.Equal(t, ContractType("RETAINER"), contract.Type)
+	assert.False(t, contract.Type.IsValid())
+	assert.Equal(t, ContractTypeUnknown, contract.Type.Known())
+
+	data, err := json.Marshal(contract)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"PAUSED","contractType":"RETAINER"}`, string(data))
+
+	contract.Status = ContractStatusActive
+	require.NoError(t, json.Unmarshal([]byte(`{"status":null}`), &contract))
+	assert.Equal(t, ContractStatusActive, contract.Status)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"status":3}`), &contract))
+}
+
+func TestEnumValues(t *testing.T) {
+	assert.Equal(t, []RoomType{RoomTypeGroup, RoomTypeOneOnOne, RoomTypeInterview, RoomTypeContract, RoomTypePublic}, RoomType("").Values())
+	assert.NotContains(t, JobStatus("").Values(), JobStatusUnknown)
+	assert.False(t, JobStatusUnknown.IsValid())
+	assert.Equal(t, JobStatusDraft, JobStatusDraft.Known())
+	assert.Contains(t, FeedbackCategory("").Values(), FeedbackCategoryClarity)
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// FeedbackCategory is an aspect of the work rated in feedback
+type FeedbackCategory string
+
+// Categories used when a client rates a freelancer
+const (
+	FeedbackCategorySkills        FeedbackCategory = "SKILLS"
+	FeedbackCategoryQuality       FeedbackCategory = "QUALITY"
+	FeedbackCategoryAvailability  FeedbackCategory = "AVAILABILITY"
+	FeedbackCategoryDeadlines     FeedbackCategory = "DEADLINES"
+	FeedbackCategoryCommunication FeedbackCategory = "COMMUNICATION"
+	FeedbackCategoryCooperation   FeedbackCategory = "COOPERATION"
+)
+
+// Categories used when a freelancer rates a client. Availability,
+// communication, cooperation and deadlines are shared with freelancer
+// feedback.
+const (
+	FeedbackCategoryClarity FeedbackCategory = "CLARITY"
+	FeedbackCategoryUnknown FeedbackCategory = "UNKNOWN"
+)
+
+// Feedback scores range from MinFeedbackScore to MaxFeedbackScore
+const (
+	MinFeedbackScore = 1
+	MaxFeedbackScore = 5
+)
+
+// FeedbackScore is the score given for one category
+type FeedbackScore struct {
+	Category FeedbackCategory `json:"category"`
+	Score    float64          `json:"score"`
+}
+
+// Feedback is the feedback left by one party of a contract
+type Feedback struct {
+	ID              models.ID         `json:"id"`
+	Score           float64           `json:"score"`
+	Comment         string            `json:"comment"`
+	Scores          []FeedbackScore   `json:"scores"`
+	CreatedDateTime models.DateTime   `json:"createdDateTime"`
+	Response        *FeedbackResponse `json:"response"`
+}
+
+// FeedbackResponse is the public reply to feedback by the party it rates
+type FeedbackResponse struct {
+	Comment         string          `json:"comment"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+}
+
+// ContractFeedback holds the feedback left on a contract by both parties.
+// Either side is nil until it has been given and published.
+type ContractFeedback struct {
+	ContractID models.ID `json:"contractId"`
+
+	// ClientFeedback is the feedback the client gave the freelancer
+	ClientFeedback *Feedback `json:"clientFeedback"`
+
+	// FreelancerFeedback is the feedback the freelancer gave the client
+	FreelancerFeedback *Feedback `json:"freelancerFeedback"`
+}
+
+// GiveFeedbackInput represents input for leaving feedback on a contract
+type GiveFeedbackInput struct {
+	ContractID string          `json:"contractId"`
+	Scores     []FeedbackScore `json:"scores"`
+	Comment    string          `json:"comment,omitempty"`
+}
+
+// Validate checks the contract and that every score is in range
+func (in GiveFeedbackInput) Validate() error {
+	if err := required("contractId", in.ContractID); err != nil {
+		return err
+	}
+	if len(in.Scores) == 0 {
+		return &errors.ValidationError{Field: "scores", Message: "at least one category score is required"}
+	}
+	for _, s := range in.Scores {
+		if s.Score < MinFeedbackScore || s.Score > MaxFeedbackScore {
+			return &errors.ValidationError{
+				Field:   "scores",
+				Message: fmt.Sprintf("score for %s must be between %d and %d", s.Category, MinFeedbackScore, MaxFeedbackScore),
+				Value:   s.Score,
+			}
+		}
+	}
+	return nil
+}
+
+// RespondToFeedbackInput represents input for replying to feedback
+type RespondToFeedbackInput struct {
+	ContractID string `json:"contractId"`
+	Comment    string `json:"comment"`
+}
+
+// Validate checks that the contract and comment are set
+func (in RespondToFeedbackInput) Validate() error {
+	return firstError(
+		required("contractId", in.ContractID),
+		required("comment", in.Comment),
+	)
+}
+
+// feedbackFields selects the fields of a Feedback
+const feedbackFields = `
+	id
+	score
+	comment
+	scores {
+		category
+		score
+	}
+	createdDateTime
+	response {
+		comment
+		createdDateTime
+	}
+`
+
+// GetFeedback returns the feedback left on a contract by both parties
+func (s *ContractsService) GetFeedback(ctx context.Context, contractID string) (*ContractFeedback, error) {
+	query := `
+		query GetContractFeedback($contractId: ID!) {
+			contractFeedback(contractId: $contractId) {
+				contractId
+				clientFeedback {` + feedbackFields + `}
+				freelancerFeedback {` + feedbackFields + `}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"contractId": contractID,
+		},
+	}
+
+	var resp struct {
+		ContractFeedback ContractFeedback `json:"contractFeedback"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.ContractFeedback, nil
+}
+
+// GiveFeedbackToFreelancer leaves the client's feedback for the freelancer
+// on a contract
+func (s *ContractsService) GiveFeedbackToFreelancer(ctx context.Context, input GiveFeedbackInput) (*Feedback, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation GiveFeedbackToFreelancer($input: GiveFeedbackInput!) {
+			giveFeedbackToFreelancer(input: $input) {` + feedbackFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		GiveFeedbackToFreelancer Feedback `json:"giveFeedbackToFreelancer"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.GiveFeedbackToFreelancer, nil
+}
+
+// GiveFeedbackToClient leaves the freelancer's feedback for the client on a
+// contract
+func (s *ContractsService) GiveFeedbackToClient(ctx context.Context, input GiveFeedbackInput) (*Feedback, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation GiveFeedbackToClient($input: GiveFeedbackInput!) {
+			giveFeedbackToClient(input: $input) {` + feedbackFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		GiveFeedbackToClient Feedback `json:"giveFeedbackToClient"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.GiveFeedbackToClient, nil
+}
+
+// RespondToFeedback publishes a reply to the feedback received on a contract
+func (s *ContractsService) RespondToFeedback(ctx context.Context, input RespondToFeedbackInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation RespondToFeedback($input: RespondToFeedbackInput!) {
+			respondToFeedback(input: $input) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		RespondToFeedback struct {
+			Success bool `json:"success"`
+		} `json:"respondToFeedback"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.RespondToFeedback.Success {
+		return fmt.Errorf("failed to respond to feedback")
+	}
+
+	return nil
+}
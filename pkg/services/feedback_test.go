@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestGetFeedback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "contract-1", req.Variables["contractId"])
+
+		w.Write([]byte(`{"data":{"contractFeedback":{
+			"contractId":"contract-1",
+			"clientFeedback":{
+				"id":"fb-1","score":4.5,"comment":"Great work",
+				"scores":[{"category":"SKILLS","score":5},{"category":"DEADLINES","score":4}],
+				"response":{"comment":"Thank you"}
+			},
+			"freelancerFeedback":null
+		}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	feedback, err := svc.GetFeedback(context.Background(), "contract-1")
+	require.NoError(t, err)
+	require.NotNil(t, feedback.ClientFeedback)
+	assert.Nil(t, feedback.FreelancerFeedback)
+	assert.Equal(t, 4.5, feedback.ClientFeedback.Score)
+	assert.Equal(t, []FeedbackScore{
+		{Category: FeedbackCategorySkills, Score: 5},
+		{Category: FeedbackCategoryDeadlines, Score: 4},
+	}, feedback.ClientFeedback.Scores)
+	require.NotNil(t, feedback.ClientFeedback.Response)
+	assert.Equal(t, "Thank you", feedback.ClientFeedback.Response.Comment)
+}
+
+func TestGiveFeedback(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"giveFeedbackToClient":{"id":"fb-2","score":5}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	feedback, err := svc.GiveFeedbackToClient(context.Background(), GiveFeedbackInput{
+		ContractID: "contract-1",
+		Scores:     []FeedbackScore{{Category: FeedbackCategoryClarity, Score: 5}},
+		Comment:    "Clear requirements",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, feedback.Score)
+
+	input := req.Variables["input"].(map[string]interface{})
+	assert.Equal(t, "contract-1", input["contractId"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"category": "CLARITY", "score": 5.0}}, input["scores"])
+}
+
+func TestGiveFeedbackValidation(t *testing.T) {
+	svc := NewContractsService(&BaseClient{HTTPClient: http.DefaultClient, APIURL: "http://invalid"})
+
+	tests := []struct {
+		name  string
+		input GiveFeedbackInput
+		field string
+	}{
+		{"missing contract", GiveFeedbackInput{Scores: []FeedbackScore{{FeedbackCategorySkills, 5}}}, "contractId"},
+		{"no scores", GiveFeedbackInput{ContractID: "contract-1"}, "scores"},
+		{"score too high", GiveFeedbackInput{ContractID: "contract-1", Scores: []FeedbackScore{{FeedbackCategorySkills, 6}}}, "scores"},
+		{"score too low", GiveFeedbackInput{ContractID: "contract-1", Scores: []FeedbackScore{{FeedbackCategorySkills, 0}}}, "scores"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GiveFeedbackToFreelancer(context.Background(), tt.input)
+			var validationErr *errors.ValidationError
+			require.True(t, stderrors.As(err, &validationErr))
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestRespondToFeedback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"respondToFeedback":{"success":false}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	err := svc.RespondToFeedback(context.Background(), RespondToFeedbackInput{ContractID: "contract-1", Comment: "Thanks"})
+	assert.EqualError(t, err, "failed to respond to feedback")
+
+	err = svc.RespondToFeedback(context.Background(), RespondToFeedbackInput{ContractID: "contract-1"})
+	var validationErr *errors.ValidationError
+	assert.True(t, stderrors.As(err, &validationErr))
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureDir holds scrubbed API responses, one file per operation, named
+// after the SDK method that issues it
+const fixtureDir = "testdata/fixtures"
+
+// fixtureTargets maps each fixture to the response type its SDK method
+// decodes into
+var fixtureTargets = map[string]func() interface{}{
+	"EstimateProposalCost": func() interface{} {
+		return new(struct {
+			ProposalCostEstimate ProposalCostEstimate `json:"proposalCostEstimate"`
+		})
+	},
+	"GetAgencyProfile": func() interface{} {
+		return new(struct {
+			AgencyProfile AgencyProfile `json:"agencyProfile"`
+		})
+	},
+	"GetAuditLog": func() interface{} {
+		return new(struct {
+			OrganizationAuditLog AuditLogPage `json:"organizationAuditLog"`
+		})
+	},
+	"GetClientCompanyProfile": func() interface{} {
+		return new(struct {
+			ClientCompanyProfile ClientCompanyProfile `json:"clientCompanyProfile"`
+		})
+	},
+	"GetContract": func() interface{} {
+		return new(struct {
+			Contract Contract `json:"contract"`
+		})
+	},
+	"ListCatalogOrders": func() interface{} {
+		return new(struct {
+			CatalogOrders CatalogOrderList `json:"catalogOrders"`
+		})
+	},
+	"ListDirectContracts": func() interface{} {
+		return new(struct {
+			DirectContractList DirectContractList `json:"directContractList"`
+		})
+	},
+	"ListInterviewInvitations": func() interface{} {
+		return new(struct {
+			InterviewInvitationList InterviewInvitationList `json:"interviewInvitationList"`
+		})
+	},
+	"ListNotifications": func() interface{} {
+		return new(struct {
+			NotificationList NotificationList `json:"notificationList"`
+		})
+	},
+	"SearchJobs": func() interface{} {
+		return new(struct {
+			MarketplaceJobPostings MarketplaceJobSearchResult `json:"marketplaceJobPostings"`
+		})
+	},
+}
+
+// loadFixtures returns the data member of every fixture, keyed by
+// operation
+func loadFixtures(t testing.TB) map[string]json.RawMessage {
+	paths, err := filepath.Glob(filepath.Join(fixtureDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	fixtures := make(map[string]json.RawMessage, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var resp struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(content, &resp), path)
+		require.NotEmpty(t, resp.Data, "%s has no data", path)
+
+		fixtures[strings.TrimSuffix(filepath.Base(path), ".json")] = resp.Data
+	}
+	return fixtures
+}
+
+// TestFixturesDecode decodes every recorded response into the SDK models
+// and fails if a field the API returned has nowhere to go or an enum value
+// is not one the SDK knows, so model drift does not silently drop data
+func TestFixturesDecode(t *testing.T) {
+	fixtures := loadFixtures(t)
+
+	for name := range fixtureTargets {
+		assert.Contains(t, fixtures, name, "no fixture for %s", name)
+	}
+
+	for name, data := range fixtures {
+		name, data := name, data
+		t.Run(name, func(t *testing.T) {
+			target, ok := fixtureTargets[name]
+			require.True(t, ok, "no target registered for fixture %s", name)
+
+			v := target()
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(v); err != nil {
+				unknown := unknownFields(data, reflect.TypeOf(v), "data")
+				t.Fatalf("strict decode failed: %v\nunknown fields:\n  %s", err, strings.Join(unknown, "\n  "))
+			}
+
+			assert.Empty(t, unknownEnumValues(reflect.ValueOf(v), "data"), "enum values the SDK does not know")
+		})
+	}
+}
+
+// FuzzFixtureDecode feeds mutations of the recorded responses to every
+// response type to make sure decoding never panics
+func FuzzFixtureDecode(f *testing.F) {
+	for _, data := range loadFixtures(f) {
+		f.Add([]byte(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, target := range fixtureTargets {
+			_ = json.Unmarshal(data, target())
+		}
+	})
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields returns the path of every object key in raw that t has no
+// field for. Types with their own UnmarshalJSON are not inspected.
+func unknownFields(raw json.RawMessage, t reflect.Type, path string) []string {
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownFields(raw, t.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return nil
+		}
+		var unknown []string
+		for i, item := range items {
+			unknown = append(unknown, unknownFields(item, t.Elem(), path+"["+strconv.Itoa(i)+"]")...)
+		}
+		return unknown
+	case reflect.Map:
+		var entries map[string]json.RawMessage
+		if json.Unmarshal(raw, &entries) != nil {
+			return nil
+		}
+		var unknown []string
+		for key, value := range entries {
+			unknown = append(unknown, unknownFields(value, t.Elem(), path+"."+key)...)
+		}
+		sort.Strings(unknown)
+		return unknown
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return nil
+		}
+		fields := jsonFields(t)
+		var unknown []string
+		for key, value := range object {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				unknown = append(unknown, path+"."+key)
+				continue
+			}
+			unknown = append(unknown, unknownFields(value, field, path+"."+key)...)
+		}
+		sort.Strings(unknown)
+		return unknown
+	}
+	return nil
+}
+
+// jsonFields returns the type of every field of t that encoding/json
+// decodes into, keyed by lower-cased JSON name
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, v := range jsonFields(ft) {
+				fields[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
+
+// unknownEnumValues returns the path and value of every non-empty string
+// enum in v whose IsValid method rejects it
+func unknownEnumValues(v reflect.Value, path string) []string {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return unknownEnumValues(v.Elem(), path)
+	case reflect.String:
+		valid, ok := v.Interface().(interface{ IsValid() bool })
+		if ok && v.Len() > 0 && !valid.IsValid() {
+			return []string{path + " = " + v.String()}
+		}
+	case reflect.Slice, reflect.Array:
+		var unknown []string
+		for i := 0; i < v.Len(); i++ {
+			unknown = append(unknown, unknownEnumValues(v.Index(i), path+"["+strconv.Itoa(i)+"]")...)
+		}
+		return unknown
+	case reflect.Struct:
+		var unknown []string
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			unknown = append(unknown, unknownEnumValues(v.Field(i), path+"."+v.Type().Field(i).Name)...)
+		}
+		return unknown
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// Flat DTOs hold one record as a single level of columns for BI tools and
+// ETL jobs. Their snake_case JSON names are stable: columns are only ever
+// added. Every column is always present, as null where the record has no
+// value. Times are UTC and amounts are exact decimal numbers, each with a
+// currency column beside it.
+
+// ContractFlat is a contract flattened into one row
+type ContractFlat struct {
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	ContractType string     `json:"contract_type"`
+	Status       string     `json:"status"`
+	CreatedAt    *time.Time `json:"created_at"`
+	StartAt      *time.Time `json:"start_at"`
+	EndAt        *time.Time `json:"end_at"`
+	ModifiedAt   *time.Time `json:"modified_at"`
+
+	HourlyRate           *json.Number `json:"hourly_rate"`
+	HourlyRateCurrency   *string      `json:"hourly_rate_currency"`
+	WeeklyHoursLimit     *int         `json:"weekly_hours_limit"`
+	WeeklyCharge         *json.Number `json:"weekly_charge"`
+	WeeklyChargeCurrency *string      `json:"weekly_charge_currency"`
+
+	ManualTimeAllowed bool `json:"manual_time_allowed"`
+	Paused            bool `json:"paused"`
+	Suspended         bool `json:"suspended"`
+
+	JobID             *string `json:"job_id"`
+	JobTitle          *string `json:"job_title"`
+	OfferID           *string `json:"offer_id"`
+	FreelancerID      *string `json:"freelancer_id"`
+	FreelancerName    *string `json:"freelancer_name"`
+	FreelancerCountry *string `json:"freelancer_country"`
+	ClientID          *string `json:"client_id"`
+	ClientName        *string `json:"client_name"`
+	MilestoneCount    int     `json:"milestone_count"`
+}
+
+// Flat returns the contract as one row
+func (c Contract) Flat() ContractFlat {
+	flat := ContractFlat{
+		ID:                string(c.ID),
+		Title:             c.Title,
+		ContractType:      string(c.ContractType),
+		Status:            string(c.Status),
+		CreatedAt:         flatTime(c.CreatedDateTime),
+		StartAt:           flatTime(c.StartDateTime),
+		ModifiedAt:        flatTime(c.ModifiedDateTime),
+		WeeklyHoursLimit:  c.WeeklyHoursLimit,
+		ManualTimeAllowed: c.ManualTimeAllowed,
+		Paused:            c.Paused,
+		Suspended:         c.Suspended,
+		MilestoneCount:    len(c.Milestones),
+	}
+	if c.EndDateTime != nil {
+		flat.EndAt = flatTime(*c.EndDateTime)
+	}
+	if c.HourlyChargeRate != nil {
+		flat.HourlyRate, flat.HourlyRateCurrency = flatMoney(*c.HourlyChargeRate)
+	}
+	if c.WeeklyChargeAmount != nil {
+		flat.WeeklyCharge, flat.WeeklyChargeCurrency = flatMoney(*c.WeeklyChargeAmount)
+	}
+	if c.Job != nil {
+		flat.JobID = flatString(string(c.Job.ID))
+		flat.JobTitle = flatString(c.Job.Content.Title)
+	}
+	if c.Offer != nil {
+		flat.OfferID = flatString(string(c.Offer.ID))
+	}
+	if c.Freelancer != nil {
+		flat.FreelancerID = flatString(string(c.Freelancer.User.ID))
+		flat.FreelancerName = flatString(c.Freelancer.User.FullName())
+		flat.FreelancerCountry = flatString(c.Freelancer.CountryDetails.Name)
+	}
+	if c.Client != nil {
+		flat.ClientID = flatString(string(c.Client.User.ID))
+		flat.ClientName = flatString(c.Client.User.FullName())
+	}
+	return flat
+}
+
+// Flatten returns the contracts of the page as rows
+func (l *ContractList) Flatten() []ContractFlat {
+	rows := make([]ContractFlat, len(l.Edges))
+	for i, edge := range l.Edges {
+		rows[i] = edge.Node.Flat()
+	}
+	return rows
+}
+
+// TransactionFlat is a transaction history row flattened into one row
+type TransactionFlat struct {
+	RowNumber         int        `json:"row_number"`
+	RecordID          string     `json:"record_id"`
+	Type              string     `json:"type"`
+	AccountingSubtype *string    `json:"accounting_subtype"`
+	Description       *string    `json:"description"`
+	CreatedAt         *time.Time `json:"created_at"`
+	ReviewDueAt       *time.Time `json:"review_due_at"`
+
+	Amount                 *json.Number `json:"amount"`
+	AmountCurrency         *string      `json:"amount_currency"`
+	AmountCredited         *json.Number `json:"amount_credited"`
+	AmountCreditedCurrency *string      `json:"amount_credited_currency"`
+	Payment                *json.Number `json:"payment"`
+	PaymentCurrency        *string      `json:"payment_currency"`
+	PaymentStatus          *string      `json:"payment_status"`
+
+	AssignmentID         *string `json:"assignment_id"`
+	AccountingEntityID   *string `json:"accounting_entity_id"`
+	RelatedTransactionID *string `json:"related_transaction_id"`
+	InvoiceID            *string `json:"invoice_id"`
+	PurchaseOrderNumber  *string `json:"purchase_order_number"`
+	CompanyID            *string `json:"company_id"`
+	CompanyName          *string `json:"company_name"`
+	FreelancerID         *string `json:"freelancer_id"`
+	FreelancerName       *string `json:"freelancer_name"`
+}
+
+// Flat returns the transaction as one row. Amounts the API left out, which
+// decode as zero without a currency, are null.
+func (r TransactionHistoryRow) Flat() TransactionFlat {
+	flat := TransactionFlat{
+		RowNumber:            r.RowNumber,
+		RecordID:             r.RecordID,
+		Type:                 r.Type,
+		AccountingSubtype:    flatString(r.AccountingSubtype),
+		Description:          flatString(r.Description),
+		CreatedAt:            flatTime(r.TransactionCreationDate),
+		ReviewDueAt:          flatTime(r.TransactionReviewDueDate),
+		PaymentStatus:        flatString(r.PaymentStatus),
+		AssignmentID:         flatString(r.RelatedAssignment),
+		AccountingEntityID:   flatString(r.RelatedAccountingEntity),
+		RelatedTransactionID: flatString(r.RelatedTransactionID),
+		InvoiceID:            flatString(r.RelatedInvoiceID),
+		PurchaseOrderNumber:  flatString(r.PurchaseOrderNumber),
+		CompanyID:            flatString(r.AssignmentTeamCompanyID),
+		CompanyName:          flatString(r.AssignmentCompanyName),
+		FreelancerID:         flatString(r.AssignmentTeamUserID),
+		FreelancerName:       flatString(r.AssignmentDeveloperName),
+	}
+	if !r.TransactionAmount.IsZero() || r.TransactionAmount.Currency != "" {
+		flat.Amount, flat.AmountCurrency = flatMoney(r.TransactionAmount)
+	}
+	if !r.AmountCreditedToUser.IsZero() || r.AmountCreditedToUser.Currency != "" {
+		flat.AmountCredited, flat.AmountCreditedCurrency = flatMoney(r.AmountCreditedToUser)
+	}
+	if !r.Payment.IsZero() || r.Payment.Currency != "" {
+		flat.Payment, flat.PaymentCurrency = flatMoney(r.Payment)
+	}
+	return flat
+}
+
+// Flatten returns the transactions as rows
+func (h *TransactionHistory) Flatten() []TransactionFlat {
+	rows := make([]TransactionFlat, len(h.TransactionDetail.TransactionHistoryRows))
+	for i, row := range h.TransactionDetail.TransactionHistoryRows {
+		rows[i] = row.Flat()
+	}
+	return rows
+}
+
+// flatTime returns d in UTC, or nil if it is unset or not a time
+func flatTime(d models.DateTime) *time.Time {
+	t, err := d.Time()
+	if err != nil || t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// flatMoney returns the amount and currency of m, with a nil currency if
+// it has none
+func flatMoney(m models.Money) (*json.Number, *string) {
+	amount := json.Number(m.Amount())
+	return &amount, flatString(m.Currency)
+}
+
+// flatString returns nil for an empty string
+func flatString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractListFlatten(t *testing.T) {
+	var list ContractList
+	require.NoError(t, json.Unmarshal([]byte(`{"edges":[
+		{"node":{
+			"id":"c-1","title":"API","contractType":"HOURLY","status":"ACTIVE",
+			"createdDateTime":"2024-01-02T10:00:00+01:00","startDateTime":"2024-01-02T10:00:00Z",
+			"hourlyChargeRate":{"rawValue":"50","currency":"USD"},"weeklyHoursLimit":20,
+			"job":{"id":"job-1","content":{"title":"Build an API"}},
+			"freelancer":{"user":{"id":"u-2","firstName":"Fiona","lastName":"Freelancer"},"countryDetails":{"name":"Portugal"}},
+			"milestones":[{"id":"m-1"}]
+		}},
+		{"node":{"id":"c-2","contractType":"FIXED_PRICE"}}
+	]}`), &list))
+
+	rows := list.Flatten()
+	require.Len(t, rows, 2)
+
+	data, err := json.Marshal(rows[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id":"c-1","title":"API","contract_type":"HOURLY","status":"ACTIVE",
+		"created_at":"2024-01-02T09:00:00Z","start_at":"2024-01-02T10:00:00Z","end_at":null,"modified_at":null,
+		"hourly_rate":50.00,"hourly_rate_currency":"USD","weekly_hours_limit":20,
+		"weekly_charge":null,"weekly_charge_currency":null,
+		"manual_time_allowed":false,"paused":false,"suspended":false,
+		"job_id":"job-1","job_title":"Build an API","offer_id":null,
+		"freelancer_id":"u-2","freelancer_name":"Fiona Freelancer","freelancer_country":"Portugal",
+		"client_id":null,"client_name":null,"milestone_count":1
+	}`, string(data))
+
+	// Missing values keep their columns
+	data, err = json.Marshal(rows[1])
+	require.NoError(t, err)
+	var columns map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &columns))
+	assert.Len(t, columns, 25)
+	assert.Nil(t, columns["hourly_rate"])
+}
+
+func TestTransactionHistoryFlatten(t *testing.T) {
+	var history TransactionHistory
+	require.NoError(t, json.Unmarshal([]byte(`{"transactionDetail":{"transactionHistoryRow":[{
+		"rowNumber":1,"recordId":"rec-1","type":"Payment",
+		"transactionCreationDate":"2024-03-01",
+		"transactionAmount":{"rawValue":"-1250.5","currency":"USD"},
+		"assignmentTeamUserId":"u-2","assignmentDeveloperName":"Fiona Freelancer"
+	}]}}`), &history))
+
+	rows := history.Flatten()
+	require.Len(t, rows, 1)
+	data, err := json.Marshal(rows[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"row_number":1,"record_id":"rec-1","type":"Payment","accounting_subtype":null,"description":null,
+		"created_at":"2024-03-01T00:00:00Z","review_due_at":null,
+		"amount":-1250.50,"amount_currency":"USD",
+		"amount_credited":null,"amount_credited_currency":null,
+		"payment":null,"payment_currency":null,"payment_status":null,
+		"assignment_id":null,"accounting_entity_id":null,"related_transaction_id":null,
+		"invoice_id":null,"purchase_order_number":null,"company_id":null,"company_name":null,
+		"freelancer_id":"u-2","freelancer_name":"Fiona Freelancer"
+	}`, string(data))
+}
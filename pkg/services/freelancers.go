@@ -1,8 +1,9 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // FreelancersService handles freelancer-related API operations
@@ -17,28 +18,40 @@ func NewFreelancersService(client *BaseClient) *FreelancersService {
 
 // FreelancerProfile represents a freelancer profile
 type FreelancerProfile struct {
-	Identity      ProfileIdentity      `json:"identity"`
-	PersonalData  PersonalData         `json:"personalData"`
-	Aggregates    ProfileAggregates    `json:"aggregates"`
-	Skills        []ProfileSkill       `json:"skills"`
-	JobCategories []JobCategory        `json:"jobCategories"`
-	Preferences   ProfilePreferences   `json:"preferences"`
+	Identity      ProfileIdentity    `json:"identity"`
+	PersonalData  PersonalData       `json:"personalData"`
+	Aggregates    ProfileAggregates  `json:"aggregates"`
+	Skills        []ProfileSkill     `json:"skills"`
+	JobCategories []JobCategory      `json:"jobCategories"`
+	Preferences   ProfilePreferences `json:"preferences"`
+}
+
+// ToUser converts the profile's identity and personal data to the
+// canonical user model
+func (p FreelancerProfile) ToUser() models.User {
+	return models.User{
+		ID:        p.Identity.ID,
+		FirstName: p.PersonalData.FirstName,
+		LastName:  p.PersonalData.LastName,
+		PhotoURL:  p.PersonalData.Portrait.Portrait,
+		Location:  p.PersonalData.Location,
+	}
 }
 
 // ProfileIdentity represents profile identity information
 type ProfileIdentity struct {
-	ID         ID     `json:"id"`
-	Ciphertext string `json:"ciphertext"`
+	ID         models.ID `json:"id"`
+	Ciphertext string    `json:"ciphertext"`
 }
 
 // PersonalData represents personal data
 type PersonalData struct {
-	FirstName   string      `json:"firstName"`
-	LastName    string      `json:"lastName"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Portrait    Portrait    `json:"portrait"`
-	Location    Location    `json:"location"`
+	FirstName   string          `json:"firstName"`
+	LastName    string          `json:"lastName"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Portrait    Portrait        `json:"portrait"`
+	Location    models.Location `json:"location"`
 }
 
 // Portrait represents profile portrait URLs
@@ -51,31 +64,31 @@ type Portrait struct {
 
 // ProfileAggregates represents profile aggregates
 type ProfileAggregates struct {
-	TotalHours              float64   `json:"totalHours"`
-	TotalJobs               int       `json:"totalJobs"`
-	TotalFeedback           int       `json:"totalFeedback"`
-	AdjustedFeedbackScore   float64   `json:"adjustedFeedbackScore"`
-	LastWorkedOn            *DateTime `json:"lastWorkedOn"`
-	TopRatedStatus          bool      `json:"topRatedStatus"`
+	TotalHours            float64          `json:"totalHours"`
+	TotalJobs             int              `json:"totalJobs"`
+	TotalFeedback         int              `json:"totalFeedback"`
+	AdjustedFeedbackScore float64          `json:"adjustedFeedbackScore"`
+	LastWorkedOn          *models.DateTime `json:"lastWorkedOn"`
+	TopRatedStatus        bool             `json:"topRatedStatus"`
 }
 
 // ProfileSkill represents a skill in the profile
 type ProfileSkill struct {
-	Skill       Skill `json:"skill"`
-	SkillUID    string `json:"skillUid"`
+	Skill    models.Skill `json:"skill"`
+	SkillUID string       `json:"skillUid"`
 }
 
 // JobCategory represents a job category
 type JobCategory struct {
-	ID     ID     `json:"id"`
-	Name   string `json:"name"`
+	ID     models.ID       `json:"id"`
+	Name   string          `json:"name"`
 	Groups []CategoryGroup `json:"groups"`
 }
 
 // CategoryGroup represents a category group
 type CategoryGroup struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
+	ID   models.ID `json:"id"`
+	Name string    `json:"name"`
 }
 
 // ProfilePreferences represents profile preferences
@@ -135,36 +148,36 @@ func (s *FreelancersService) GetFreelancerProfile(ctx context.Context, profileKe
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"profileKey": profileKey,
 		},
 	}
-	
+
 	var resp struct {
 		FreelancerProfileByProfileKey FreelancerProfile `json:"freelancerProfileByProfileKey"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.FreelancerProfileByProfileKey, nil
 }
 
 // SearchFreelancersInput represents input for searching freelancers
 type SearchFreelancersInput struct {
-	UserQuery      string           `json:"userQuery,omitempty"`
-	Title          string           `json:"title,omitempty"`
-	Skills         []string         `json:"skillsNames,omitempty"`
-	Countries      []string         `json:"countries,omitempty"`
-	HourlyRate     *RangeFilter     `json:"hourlyRate,omitempty"`
-	JobSuccessScore *RangeFilter     `json:"jobSuccessScore,omitempty"`
-	TotalJobs      *RangeFilter     `json:"totalJobs,omitempty"`
-	TopRated       bool             `json:"topRated,omitempty"`
-	Pagination     *PaginationInput `json:"paging,omitempty"`
+	UserQuery       string                  `json:"userQuery,omitempty"`
+	Title           string                  `json:"title,omitempty"`
+	Skills          []string                `json:"skillsNames,omitempty"`
+	Countries       []string                `json:"countries,omitempty"`
+	HourlyRate      *RangeFilter            `json:"hourlyRate,omitempty"`
+	JobSuccessScore *RangeFilter            `json:"jobSuccessScore,omitempty"`
+	TotalJobs       *RangeFilter            `json:"totalJobs,omitempty"`
+	TopRated        bool                    `json:"topRated,omitempty"`
+	Pagination      *models.PaginationInput `json:"paging,omitempty"`
 }
 
 // RangeFilter represents a range filter
@@ -211,24 +224,24 @@ func (s *FreelancersService) SearchFreelancers(ctx context.Context, input Search
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"request": input,
 		},
 	}
-	
+
 	var resp struct {
 		Search struct {
 			SearchFreelancerPublicProfile FreelancerSearchResult `json:"searchFreelancerPublicProfile"`
 		} `json:"search"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Search.SearchFreelancerPublicProfile, nil
 }
 
@@ -237,8 +250,17 @@ type UpdateAvailabilityInput struct {
 	Availability string `json:"availability"`
 }
 
+// Validate checks that the availability is set
+func (in UpdateAvailabilityInput) Validate() error {
+	return required("availability", in.Availability)
+}
+
 // UpdateFreelancerAvailability updates freelancer availability
 func (s *FreelancersService) UpdateFreelancerAvailability(ctx context.Context, input UpdateAvailabilityInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation UpdateFreelancerAvailability($input: UpdateFreelancerAvailabilityInput!) {
 			updateFreelancerAvailability(input: $input) {
@@ -246,23 +268,23 @@ func (s *FreelancersService) UpdateFreelancerAvailability(ctx context.Context, i
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		UpdateFreelancerAvailability struct {
 			Success bool `json:"success"`
 		} `json:"updateFreelancerAvailability"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// genqlientClient adapts BaseClient to the genqlient graphql.Client
+// interface so generated operations share rate limiting, tenant headers,
+// retries and error handling with hand-written queries
+type genqlientClient struct {
+	client *BaseClient
+}
+
+// MakeRequest implements graphql.Client
+func (g genqlientClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	variables, err := toVariables(req.Variables)
+	if err != nil {
+		return err
+	}
+
+	return g.client.Do(ctx, &GraphQLRequest{
+		Query:         req.Query,
+		Variables:     variables,
+		OperationName: req.OpName,
+	}, resp.Data)
+}
+
+// toVariables converts generated variable structs into the map form used by
+// GraphQLRequest
+func toVariables(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WrapError(err, "failed to marshal variables")
+	}
+
+	var variables map[string]interface{}
+	if err := json.Unmarshal(data, &variables); err != nil {
+		return nil, errors.WrapError(err, "failed to convert variables")
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Guardrails protects long-running services from queries that select or
+// return far more data than intended, such as a work diary with
+// screenshots for a whole team
+type Guardrails struct {
+	// MaxResponseSize fails requests whose response body is larger, in
+	// bytes, with errors.ErrResponseTooLarge. Zero means no limit.
+	MaxResponseSize int64
+
+	// WarnResponseSize is the response size, in bytes, above which
+	// OnWarning is called. Zero disables the warning.
+	WarnResponseSize int64
+
+	// WarnComplexity is the number of fields a query may select, counting
+	// nested fields and fragments, before OnWarning is called. Zero
+	// disables the warning.
+	WarnComplexity int
+
+	// OnWarning is called when a query exceeds WarnComplexity, before it is
+	// sent, or WarnResponseSize, once its response has been read. It may be
+	// called concurrently.
+	OnWarning func(QueryWarning)
+}
+
+// QueryWarning describes a query that exceeded a guardrail threshold
+type QueryWarning struct {
+	// OperationName is the name of the operation, if it has one
+	OperationName string
+	// Complexity is set if the query selects more than WarnComplexity
+	// fields
+	Complexity int
+	// ResponseSize is set if the response was larger than WarnResponseSize
+	// bytes
+	ResponseSize int64
+}
+
+// checkComplexity calls OnWarning if req selects more fields than
+// WarnComplexity. Queries that do not parse are not checked.
+func (g *Guardrails) checkComplexity(req *GraphQLRequest) {
+	if g.WarnComplexity <= 0 || g.OnWarning == nil {
+		return
+	}
+
+	p := prepareQuery(req.Query)
+	op := p.operation(req.OperationName)
+	if op == nil {
+		return
+	}
+
+	if complexity := countFields(op.SelectionSet, p.doc.Fragments, map[string]bool{}); complexity > g.WarnComplexity {
+		g.OnWarning(QueryWarning{OperationName: op.Name, Complexity: complexity})
+	}
+}
+
+// countFields returns the number of fields in set, including nested
+// fields and those of the fragments it spreads. A fragment already being
+// expanded is not expanded again.
+func countFields(set ast.SelectionSet, fragments ast.FragmentDefinitionList, expanding map[string]bool) int {
+	var n int
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			n += 1 + countFields(sel.SelectionSet, fragments, expanding)
+		case *ast.InlineFragment:
+			n += countFields(sel.SelectionSet, fragments, expanding)
+		case *ast.FragmentSpread:
+			def := fragments.ForName(sel.Name)
+			if def == nil || expanding[sel.Name] {
+				continue
+			}
+			expanding[sel.Name] = true
+			n += countFields(def.SelectionSet, fragments, expanding)
+			delete(expanding, sel.Name)
+		}
+	}
+	return n
+}
+
+// responseReader counts the bytes read from a response body and fails once
+// more than MaxResponseSize have been read
+type responseReader struct {
+	body  io.Reader
+	limit int64
+	size  int64
+}
+
+// newResponseReader wraps the body of resp, failing straight away if its
+// declared length is over the limit
+func (g *Guardrails) newResponseReader(resp *http.Response) (*responseReader, error) {
+	if g.MaxResponseSize > 0 && resp.ContentLength > g.MaxResponseSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the limit of %d", errors.ErrResponseTooLarge, resp.ContentLength, g.MaxResponseSize)
+	}
+	return &responseReader{body: resp.Body, limit: g.MaxResponseSize}, nil
+}
+
+func (r *responseReader) Read(p []byte) (int, error) {
+	if r.limit > 0 && r.size > r.limit {
+		return 0, r.tooLarge()
+	}
+
+	n, err := r.body.Read(p)
+	r.size += int64(n)
+	if r.limit > 0 && r.size > r.limit {
+		// Withhold the bytes past the limit so a decoder cannot complete
+		// the value it is reading
+		return n - int(r.size-r.limit), r.tooLarge()
+	}
+	return n, err
+}
+
+// tooLarge returns the error for a response over the limit
+func (r *responseReader) tooLarge() error {
+	return fmt.Errorf("%w: more than the limit of %d bytes", errors.ErrResponseTooLarge, r.limit)
+}
+
+// limitError returns err, or the error for a response over the limit if r
+// exceeded it and err does not say so, as codecs other than encoding/json
+// may not keep the error of the reader
+func (r *responseReader) limitError(err error) error {
+	if err == nil || r.limit <= 0 || r.size <= r.limit || stderrors.Is(err, errors.ErrResponseTooLarge) {
+		return err
+	}
+	return errors.WrapError(r.tooLarge(), "failed to parse response")
+}
+
+// checkResponseSize calls OnWarning if the response to req read through r
+// was larger than WarnResponseSize. req is nil for batches.
+func (g *Guardrails) checkResponseSize(req *GraphQLRequest, r *responseReader) {
+	if g.WarnResponseSize <= 0 || g.OnWarning == nil || r.size <= g.WarnResponseSize {
+		return
+	}
+
+	warning := QueryWarning{ResponseSize: r.size}
+	if req != nil {
+		warning.OperationName = operationName(req)
+	}
+	g.OnWarning(warning)
+}
+
+// operationName returns the name of the operation req executes, or "" if
+// it is anonymous or the query does not parse
+func operationName(req *GraphQLRequest) string {
+	if req.OperationName != "" {
+		return req.OperationName
+	}
+	return prepareQuery(req.Query).name
+}
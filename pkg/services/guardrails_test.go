@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestGuardrailsResponseSize(t *testing.T) {
+	padding := strings.Repeat("x", 4096)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flush first so the body is chunked and has no Content-Length
+		w.(http.Flusher).Flush()
+		w.Write([]byte(`{"data":{"user":{"id":"user-1","name":"` + padding + `"}}}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var warnings []QueryWarning
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		Guardrails: Guardrails{
+			WarnResponseSize: 1024,
+			OnWarning: func(w QueryWarning) {
+				mu.Lock()
+				defer mu.Unlock()
+				warnings = append(warnings, w)
+			},
+		},
+	}
+	ctx := context.Background()
+	req := &GraphQLRequest{Query: `query GetUser { user { id name } }`}
+
+	var resp struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	require.NoError(t, client.Do(ctx, req, &resp))
+	assert.Equal(t, "user-1", resp.User.ID)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "GetUser", warnings[0].OperationName)
+	assert.Greater(t, warnings[0].ResponseSize, int64(4096))
+
+	client.Guardrails.MaxResponseSize = 2048
+	err := client.Do(ctx, req, &resp)
+	assert.ErrorIs(t, err, errors.ErrResponseTooLarge)
+}
+
+func TestGuardrailsContentLength(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data":{"user":{"id":"user-1"}}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		Guardrails: Guardrails{MaxResponseSize: 10},
+	}
+
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `query { user { id } }`}, nil)
+	assert.ErrorIs(t, err, errors.ErrResponseTooLarge)
+	assert.Contains(t, err.Error(), "33 bytes")
+	assert.Equal(t, 1, requests)
+}
+
+func TestGuardrailsComplexity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	var warnings []QueryWarning
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		Guardrails: Guardrails{
+			WarnComplexity: 6,
+			OnWarning:      func(w QueryWarning) { warnings = append(warnings, w) },
+		},
+	}
+	ctx := context.Background()
+
+	// workDiary, cells, screenshots and their fields through a fragment
+	// spread twice: 1 + 1 + 2 * (1 + 2) + 1 = 9
+	query := `
+		query WorkDiary {
+			workDiary {
+				cells {
+					first: screenshots { ...Shot }
+					second: screenshots { ...Shot }
+				}
+				total
+			}
+		}
+		fragment Shot on Screenshot { url size }
+	`
+	require.NoError(t, client.Do(ctx, &GraphQLRequest{Query: query}, nil))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, QueryWarning{OperationName: "WorkDiary", Complexity: 9}, warnings[0])
+
+	require.NoError(t, client.Do(ctx, &GraphQLRequest{Query: `query { user { id name } }`}, nil))
+	assert.Len(t, warnings, 1)
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// InterviewsService handles interview invitation and meeting API
+// operations
+type InterviewsService struct {
+	client *BaseClient
+}
+
+// NewInterviewsService creates a new interviews service
+func NewInterviewsService(client *BaseClient) *InterviewsService {
+	return &InterviewsService{client: client}
+}
+
+// InterviewInvitationStatus represents the status of an interview
+// invitation
+type InterviewInvitationStatus string
+
+const (
+	InterviewInvitationStatusPending  InterviewInvitationStatus = "PENDING"
+	InterviewInvitationStatusAccepted InterviewInvitationStatus = "ACCEPTED"
+	InterviewInvitationStatusDeclined InterviewInvitationStatus = "DECLINED"
+	InterviewInvitationStatusExpired  InterviewInvitationStatus = "EXPIRED"
+	InterviewInvitationStatusUnknown  InterviewInvitationStatus = "UNKNOWN"
+)
+
+// MeetingStatus represents the status of a scheduled meeting
+type MeetingStatus string
+
+const (
+	MeetingStatusScheduled MeetingStatus = "SCHEDULED"
+	MeetingStatusCompleted MeetingStatus = "COMPLETED"
+	MeetingStatusCancelled MeetingStatus = "CANCELLED"
+	MeetingStatusUnknown   MeetingStatus = "UNKNOWN"
+)
+
+// InterviewInvitation represents an invitation to interview for a job
+type InterviewInvitation struct {
+	ID              models.ID                 `json:"id"`
+	Status          InterviewInvitationStatus `json:"status"`
+	Message         string                    `json:"message"`
+	JobPosting      JobPostingRef             `json:"jobPosting"`
+	InvitedBy       models.User               `json:"invitedBy"`
+	Freelancer      models.User               `json:"freelancer"`
+	RoomID          models.ID                 `json:"roomId"`
+	CreatedDateTime models.DateTime           `json:"createdDateTime"`
+}
+
+// JobPostingRef identifies a job posting
+type JobPostingRef struct {
+	ID    models.ID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// InterviewInvitationFilter represents interview invitation filtering
+// options
+type InterviewInvitationFilter struct {
+	Status       []InterviewInvitationStatus `json:"status,omitempty"`
+	JobPostingID string                      `json:"jobPostingId,omitempty"`
+}
+
+// ListInterviewInvitationsInput represents input for listing interview
+// invitations
+type ListInterviewInvitationsInput struct {
+	Pagination *models.PaginationInput    `json:"pagination,omitempty"`
+	Filter     *InterviewInvitationFilter `json:"filter,omitempty"`
+}
+
+// InterviewInvitationList represents a paginated list of interview
+// invitations
+type InterviewInvitationList struct {
+	TotalCount int                       `json:"totalCount"`
+	PageInfo   models.PageInfo           `json:"pageInfo"`
+	Edges      []InterviewInvitationEdge `json:"edges"`
+}
+
+// InterviewInvitationEdge represents an interview invitation edge in
+// pagination
+type InterviewInvitationEdge struct {
+	Cursor string              `json:"cursor"`
+	Node   InterviewInvitation `json:"node"`
+}
+
+// DeclineInterviewInvitationInput represents input for declining an
+// interview invitation
+type DeclineInterviewInvitationInput struct {
+	InvitationID string `json:"invitationId"`
+	// ReasonID is a proposal decline reason from MetadataService.GetReasons
+	ReasonID string `json:"reasonId"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Validate checks that the invitation and reason are set
+func (in DeclineInterviewInvitationInput) Validate() error {
+	return firstError(
+		required("invitationId", in.InvitationID),
+		required("reasonId", in.ReasonID),
+	)
+}
+
+// InterviewSlot represents a time offered for an interview
+type InterviewSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ProposeInterviewTimesInput represents input for offering interview times
+// in a room
+type ProposeInterviewTimesInput struct {
+	RoomID  string          `json:"roomId"`
+	Slots   []InterviewSlot `json:"slots"`
+	Message string          `json:"message,omitempty"`
+}
+
+// Validate checks the room and that each slot ends after it starts
+func (in ProposeInterviewTimesInput) Validate() error {
+	if err := required("roomId", in.RoomID); err != nil {
+		return err
+	}
+	if len(in.Slots) == 0 {
+		return &errors.ValidationError{Field: "slots", Message: "at least one slot is required"}
+	}
+	for i, slot := range in.Slots {
+		field := fmt.Sprintf("slots[%d]", i)
+		if slot.Start.IsZero() {
+			return &errors.ValidationError{Field: field + ".start", Message: "is required"}
+		}
+		if !slot.End.After(slot.Start) {
+			return &errors.ValidationError{Field: field + ".end", Message: "must be after the start", Value: slot.End.Format(time.RFC3339)}
+		}
+	}
+	return nil
+}
+
+// InterviewProposal represents interview times offered in a room
+type InterviewProposal struct {
+	ID              models.ID       `json:"id"`
+	RoomID          models.ID       `json:"roomId"`
+	Slots           []InterviewSlot `json:"slots"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+}
+
+// Meeting represents a scheduled Upwork meeting
+type Meeting struct {
+	ID            models.ID       `json:"id"`
+	Title         string          `json:"title"`
+	Status        MeetingStatus   `json:"status"`
+	StartDateTime models.DateTime `json:"startDateTime"`
+	EndDateTime   models.DateTime `json:"endDateTime"`
+	// Provider is the video provider, such as "ZOOM"
+	Provider     string        `json:"provider"`
+	JoinURL      string        `json:"joinUrl"`
+	RoomID       models.ID     `json:"roomId"`
+	Participants []models.User `json:"participants"`
+}
+
+// ListMeetingsInput represents input for listing scheduled meetings
+type ListMeetingsInput struct {
+	// From and To bound the meeting start dates as YYYY-MM-DD
+	From       string                  `json:"from,omitempty"`
+	To         string                  `json:"to,omitempty"`
+	RoomID     string                  `json:"roomId,omitempty"`
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+}
+
+// Validate checks the dates
+func (in ListMeetingsInput) Validate() error {
+	return firstError(
+		validateDate("from", in.From),
+		validateDate("to", in.To),
+	)
+}
+
+// MeetingList represents a paginated list of meetings
+type MeetingList struct {
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+	Edges      []MeetingEdge   `json:"edges"`
+}
+
+// MeetingEdge represents a meeting edge in pagination
+type MeetingEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   Meeting `json:"node"`
+}
+
+// interviewInvitationFields selects the fields of an InterviewInvitation
+const interviewInvitationFields = `
+	id
+	status
+	message
+	jobPosting {
+		id
+		title
+	}
+	invitedBy {
+		id
+		name
+	}
+	freelancer {
+		id
+		name
+	}
+	roomId
+	createdDateTime
+`
+
+// meetingFields selects the fields of a Meeting
+const meetingFields = `
+	id
+	title
+	status
+	startDateTime
+	endDateTime
+	provider
+	joinUrl
+	roomId
+	participants {
+		id
+		name
+	}
+`
+
+// ListInterviewInvitations returns a list of interview invitations
+func (s *InterviewsService) ListInterviewInvitations(ctx context.Context, input ListInterviewInvitationsInput) (*InterviewInvitationList, error) {
+	if input.Filter != nil {
+		for _, status := range input.Filter.Status {
+			if err := validateEnum("filter.status", status, status.IsValid()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	query := `
+		query ListInterviewInvitations($pagination: Pagination, $filter: InterviewInvitationFilter) {
+			interviewInvitationList(pagination: $pagination, filter: $filter) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + interviewInvitationFields + `}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+	if input.Filter != nil {
+		variables["filter"] = input.Filter
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		InterviewInvitationList InterviewInvitationList `json:"interviewInvitationList"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.InterviewInvitationList, nil
+}
+
+// AcceptInterviewInvitation accepts an interview invitation, optionally
+// with a message to the client
+func (s *InterviewsService) AcceptInterviewInvitation(ctx context.Context, invitationID, message string) (*InterviewInvitation, error) {
+	if err := required("invitationId", invitationID); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation AcceptInterviewInvitation($input: AcceptInterviewInvitationInput!) {
+			acceptInterviewInvitation(input: $input) {` + interviewInvitationFields + `}
+		}
+	`
+
+	input := map[string]interface{}{
+		"invitationId": invitationID,
+	}
+	if message != "" {
+		input["message"] = message
+	}
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		AcceptInterviewInvitation InterviewInvitation `json:"acceptInterviewInvitation"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.AcceptInterviewInvitation, nil
+}
+
+// DeclineInterviewInvitation declines an interview invitation
+func (s *InterviewsService) DeclineInterviewInvitation(ctx context.Context, input DeclineInterviewInvitationInput) (*InterviewInvitation, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation DeclineInterviewInvitation($input: DeclineInterviewInvitationInput!) {
+			declineInterviewInvitation(input: $input) {` + interviewInvitationFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		DeclineInterviewInvitation InterviewInvitation `json:"declineInterviewInvitation"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.DeclineInterviewInvitation, nil
+}
+
+// ProposeInterviewTimes offers interview times in a room. The other party
+// picks one, which schedules a meeting.
+func (s *InterviewsService) ProposeInterviewTimes(ctx context.Context, input ProposeInterviewTimesInput) (*InterviewProposal, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation ProposeInterviewTimes($input: ProposeInterviewTimesInput!) {
+			proposeInterviewTimes(input: $input) {
+				id
+				roomId
+				slots {
+					start
+					end
+				}
+				createdDateTime
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		ProposeInterviewTimes InterviewProposal `json:"proposeInterviewTimes"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.ProposeInterviewTimes, nil
+}
+
+// ListMeetings returns scheduled meetings, including their join links
+func (s *InterviewsService) ListMeetings(ctx context.Context, input ListMeetingsInput) (*MeetingList, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query ListMeetings($filter: MeetingFilter, $pagination: Pagination) {
+			meetingList(filter: $filter, pagination: $pagination) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {` + meetingFields + `}
+				}
+			}
+		}
+	`
+
+	filter := map[string]interface{}{}
+	if input.From != "" {
+		filter["startDate_gte"] = input.From
+	}
+	if input.To != "" {
+		filter["startDate_lte"] = input.To
+	}
+	if input.RoomID != "" {
+		filter["roomId_eq"] = input.RoomID
+	}
+
+	variables := map[string]interface{}{}
+	if len(filter) > 0 {
+		variables["filter"] = filter
+	}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		MeetingList MeetingList `json:"meetingList"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.MeetingList, nil
+}
+
+// GetMeeting returns a scheduled meeting by ID
+func (s *InterviewsService) GetMeeting(ctx context.Context, meetingID string) (*Meeting, error) {
+	if err := required("meetingId", meetingID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetMeeting($id: ID!) {
+			meeting(id: $id) {` + meetingFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": meetingID,
+		},
+	}
+
+	var resp struct {
+		Meeting Meeting `json:"meeting"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Meeting, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeInterviewTimesValidate(t *testing.T) {
+	start := time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)
+	valid := ProposeInterviewTimesInput{
+		RoomID: "room-1",
+		Slots:  []InterviewSlot{{Start: start, End: start.Add(30 * time.Minute)}},
+	}
+	assert.NoError(t, valid.Validate())
+
+	assertValidationField(t, ProposeInterviewTimesInput{RoomID: "room-1"}.Validate(), "slots")
+
+	backwards := valid
+	backwards.Slots = []InterviewSlot{valid.Slots[0], {Start: start, End: start}}
+	assertValidationField(t, backwards.Validate(), "slots[1].end")
+
+	assertValidationField(t, ListMeetingsInput{From: "next week"}.Validate(), "from")
+	assertValidationField(t, DeclineInterviewInvitationInput{InvitationID: "inv-1"}.Validate(), "reasonId")
+}
+
+func TestInterviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "acceptInterviewInvitation"):
+			assert.Equal(t, map[string]interface{}{"invitationId": "inv-1", "message": "Happy to chat"}, req.Variables["input"])
+			w.Write([]byte(`{"data":{"acceptInterviewInvitation":{"id":"inv-1","status":"ACCEPTED","jobPosting":{"id":"job-1","title":"Go developer"}}}}`))
+		case strings.Contains(req.Query, "meetingList"):
+			assert.Equal(t, map[string]interface{}{"startDate_gte": "2026-10-01", "roomId_eq": "room-1"}, req.Variables["filter"])
+			w.Write([]byte(`{"data":{"meetingList":{"totalCount":1,"edges":[{"node":{
+				"id":"meeting-1","status":"SCHEDULED","provider":"ZOOM","joinUrl":"https://zoom.us/j/1"
+			}}]}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewInterviewsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	invitation, err := svc.AcceptInterviewInvitation(ctx, "inv-1", "Happy to chat")
+	require.NoError(t, err)
+	assert.Equal(t, InterviewInvitationStatusAccepted, invitation.Status)
+	assert.Equal(t, "Go developer", invitation.JobPosting.Title)
+
+	meetings, err := svc.ListMeetings(ctx, ListMeetingsInput{From: "2026-10-01", RoomID: "room-1"})
+	require.NoError(t, err)
+	require.Len(t, meetings.Edges, 1)
+	assert.Equal(t, MeetingStatusScheduled, meetings.Edges[0].Node.Status)
+	assert.Equal(t, "https://zoom.us/j/1", meetings.Edges[0].Node.JoinURL)
+
+	_, err = svc.ListInterviewInvitations(ctx, ListInterviewInvitationsInput{
+		Filter: &InterviewInvitationFilter{Status: []InterviewInvitationStatus{"MAYBE"}},
+	})
+	assertValidationField(t, err, "filter.status")
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Iterator walks the items of a paginated result, fetching pages as it
+// goes. It stops after the client's MaxItems items. Call Next until it
+// returns false, then check Err:
+//
+//	it := page.Iter(ctx)
+//	defer it.Close()
+//	for it.Next() {
+//		process(it.Item())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *BaseClient
+	fetch  pageFetcher[T]
+	limit  int
+
+	buf   []T
+	item  T
+	count int
+	more  bool
+	err   error
+
+	// Pages fetched in the background, set by Prefetch
+	pages  chan iteratorPage[T]
+	cancel context.CancelFunc
+}
+
+// pageFetcher fetches the page after the last one it returned, reporting
+// whether there are more after it
+type pageFetcher[T any] func(ctx context.Context) (items []T, more bool, err error)
+
+// iteratorPage is a page fetched in the background
+type iteratorPage[T any] struct {
+	items []T
+	more  bool
+	err   error
+}
+
+// newIterator returns an iterator over first and the pages fetch returns
+// after it. client may be nil.
+func newIterator[T any](ctx context.Context, client *BaseClient, first []T, more bool, fetch pageFetcher[T]) *Iterator[T] {
+	it := &Iterator[T]{
+		ctx:    ctx,
+		client: client,
+		fetch:  fetch,
+		buf:    first,
+		more:   more,
+	}
+	if client != nil {
+		it.limit = client.maxItems(ctx)
+	}
+	return it
+}
+
+// Next advances to the next item, fetching the next page if needed. It
+// returns false when the items are exhausted, the MaxItems cap is reached
+// or a fetch fails.
+func (it *Iterator[T]) Next() bool {
+	if it.limit > 0 && it.count >= it.limit {
+		it.Close()
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if it.err != nil || !it.more {
+			it.Close()
+			return false
+		}
+		it.buf, it.more, it.err = it.nextPage()
+	}
+
+	it.item, it.buf = it.buf[0], it.buf[1:]
+	it.count++
+	return true
+}
+
+// Item returns the item Next advanced to
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Prefetch fetches up to n pages ahead of the consumer in the background,
+// so slow processing of one page overlaps with fetching the next. Fetches
+// still wait on the client's rate limiter. It must be called before the
+// first Next that needs a new page; calling it again has no effect. Close
+// stops the background fetching if the iteration is abandoned early.
+func (it *Iterator[T]) Prefetch(n int) *Iterator[T] {
+	if n <= 0 || it.pages != nil || !it.more || it.err != nil {
+		return it
+	}
+
+	var ctx context.Context
+	if it.client != nil {
+		ctx, it.cancel = it.client.workerContext(it.ctx)
+	} else {
+		ctx, it.cancel = context.WithCancel(it.ctx)
+	}
+
+	// The worker holds one page while waiting to hand it over, so the
+	// channel buffers the other n-1
+	pages := make(chan iteratorPage[T], n-1)
+	it.pages = pages
+
+	fetched := it.count + len(it.buf)
+	go func() {
+		defer close(pages)
+		for {
+			items, more, err := it.fetch(ctx)
+			select {
+			case pages <- iteratorPage[T]{items: items, more: more, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			// Don't fetch pages the consumer will never see
+			fetched += len(items)
+			if err != nil || !more || (it.limit > 0 && fetched >= it.limit) {
+				return
+			}
+		}
+	}()
+
+	return it
+}
+
+// Close stops any background fetching. It is safe to call more than once.
+func (it *Iterator[T]) Close() {
+	if it.cancel != nil {
+		it.cancel()
+	}
+}
+
+// nextPage returns the next page, from the background worker if
+// prefetching
+func (it *Iterator[T]) nextPage() ([]T, bool, error) {
+	if it.pages == nil {
+		return it.fetch(it.ctx)
+	}
+
+	page, ok := <-it.pages
+	if !ok {
+		// The worker stopped without handing over a final page
+		if err := it.ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		if it.client != nil && it.client.closed() {
+			return nil, false, errors.ErrClientClosed
+		}
+		return nil, false, nil
+	}
+	return page.items, page.more, page.err
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// newPagedJobsServer serves pages pages of two job postings each and
+// counts the requests it receives
+func newPagedJobsServer(t *testing.T, pages int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		n := atomic.AddInt32(requests, 1)
+		fmt.Fprintf(w, `{"data":{"marketplaceJobPostings":{"pageInfo":{"hasNextPage":%t,"endCursor":"page-%d"},"edges":[
+			{"node":{"id":"job-%d-0"}},{"node":{"id":"job-%d-1"}}
+		]}}}`, n < pages, n, n, n)
+	}))
+}
+
+func TestIteratorPrefetch(t *testing.T) {
+	var requests int32
+	server := newPagedJobsServer(t, 5, &requests)
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	page, err := svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+
+	it := page.Iter(ctx).Prefetch(2)
+	defer it.Close()
+	require.True(t, it.Next())
+	assert.Equal(t, models.ID("job-1-0"), it.Item().ID)
+
+	// Two pages are fetched ahead while the consumer is busy, and no more
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&requests) == 3 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	var ids []models.ID
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Len(t, ids, 9)
+	assert.Equal(t, models.ID("job-5-1"), ids[8])
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+}
+
+func TestIteratorPrefetchMaxItems(t *testing.T) {
+	var requests int32
+	server := newPagedJobsServer(t, 10, &requests)
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL, MaxItems: 3})
+	ctx := context.Background()
+
+	page, err := svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+
+	it := page.Iter(ctx).Prefetch(5)
+	defer it.Close()
+	var count int
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 3, count)
+
+	// Prefetching stops once the pages hold MaxItems items
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestIteratorPrefetchCancel(t *testing.T) {
+	var requests int32
+	server := newPagedJobsServer(t, 10, &requests)
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	page, err := svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+
+	it := page.Iter(ctx).Prefetch(1)
+	defer it.Close()
+	require.True(t, it.Next())
+	require.True(t, it.Next())
+	cancel()
+
+	for it.Next() {
+	}
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
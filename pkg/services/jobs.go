@@ -1,44 +1,47 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
 	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // JobsService handles job-related API operations
 type JobsService struct {
-	client *BaseClient
+	client      *BaseClient
+	freelancers *FreelancersService
 }
 
 // NewJobsService creates a new jobs service
 func NewJobsService(client *BaseClient) *JobsService {
-	return &JobsService{client: client}
+	return &JobsService{client: client, freelancers: NewFreelancersService(client)}
 }
 
 // JobPosting represents a job posting
 type JobPosting struct {
-	ID               ID                    `json:"id"`
-	Content          JobContent            `json:"content"`
-	Info             JobInfo               `json:"info"`
-	ContractTerms    ContractTerms         `json:"contractTerms"`
-	Classification   JobClassification     `json:"classification"`
-	Ownership        JobOwnership          `json:"ownership"`
-	Visibility       string                `json:"visibility"`
-	Attachment       *Attachment           `json:"attachment"`
+	ID                  models.ID           `json:"id"`
+	Content             JobContent          `json:"content"`
+	Info                JobInfo             `json:"info"`
+	ContractTerms       ContractTerms       `json:"contractTerms"`
+	Classification      JobClassification   `json:"classification"`
+	Ownership           JobOwnership        `json:"ownership"`
+	Visibility          string              `json:"visibility"`
+	Attachment          *Attachment         `json:"attachment"`
 	ContractorSelection ContractorSelection `json:"contractorSelection"`
 }
 
 // JobInfo represents job information
 type JobInfo struct {
-	Status           JobStatus  `json:"status"`
-	HourlyBudgetMin  *Money     `json:"hourlyBudgetMin"`
-	HourlyBudgetMax  *Money     `json:"hourlyBudgetMax"`
-	AuditTime        AuditTime  `json:"auditTime"`
-	FilledDateTime   *DateTime  `json:"filledDateTime"`
-	LegacyCiphertext string     `json:"legacyCiphertext"`
-	KeepOpenOnHire   bool       `json:"keepOpenOnHire"`
-	SiteSource       string     `json:"siteSource"`
+	Status           JobStatus        `json:"status"`
+	HourlyBudgetMin  *models.Money    `json:"hourlyBudgetMin"`
+	HourlyBudgetMax  *models.Money    `json:"hourlyBudgetMax"`
+	AuditTime        AuditTime        `json:"auditTime"`
+	FilledDateTime   *models.DateTime `json:"filledDateTime"`
+	LegacyCiphertext string           `json:"legacyCiphertext"`
+	KeepOpenOnHire   bool             `json:"keepOpenOnHire"`
+	SiteSource       string           `json:"siteSource"`
 }
 
 // JobStatus represents the status of a job
@@ -49,20 +52,21 @@ const (
 	JobStatusFilled    JobStatus = "FILLED"
 	JobStatusCancelled JobStatus = "CANCELLED"
 	JobStatusDraft     JobStatus = "DRAFT"
+	JobStatusUnknown   JobStatus = "UNKNOWN"
 )
 
 // AuditTime represents audit timestamps
 type AuditTime struct {
-	CreatedDateTime  DateTime `json:"createdDateTime"`
-	ModifiedDateTime DateTime `json:"modifiedDateTime"`
+	CreatedDateTime  models.DateTime `json:"createdDateTime"`
+	ModifiedDateTime models.DateTime `json:"modifiedDateTime"`
 }
 
 // ContractTerms represents contract terms for a job
 type ContractTerms struct {
-	ContractType           ContractType            `json:"contractType"`
-	ContractStartDate      *DateTime               `json:"contractStartDate"`
-	ContractEndDate        *DateTime               `json:"contractEndDate"`
-	HourlyContractTerms    *HourlyContractTerms    `json:"hourlyContractTerms"`
+	ContractType            ContractType             `json:"contractType"`
+	ContractStartDate       *models.DateTime         `json:"contractStartDate"`
+	ContractEndDate         *models.DateTime         `json:"contractEndDate"`
+	HourlyContractTerms     *HourlyContractTerms     `json:"hourlyContractTerms"`
 	FixedPriceContractTerms *FixedPriceContractTerms `json:"fixedPriceContractTerms"`
 }
 
@@ -79,9 +83,9 @@ type FixedPriceContractTerms struct {
 
 // EngagementDuration represents engagement duration
 type EngagementDuration struct {
-	ID    ID     `json:"id"`
-	Weeks int    `json:"weeks"`
-	Label string `json:"label"`
+	ID    models.ID `json:"id"`
+	Weeks int       `json:"weeks"`
+	Label string    `json:"label"`
 }
 
 // EngagementType represents engagement type
@@ -91,44 +95,20 @@ const (
 	EngagementTypeAsNeeded EngagementType = "AS_NEEDED"
 	EngagementTypePartTime EngagementType = "PART_TIME"
 	EngagementTypeFullTime EngagementType = "FULL_TIME"
+	EngagementTypeUnknown  EngagementType = "UNKNOWN"
 )
 
 // JobClassification represents job classification
 type JobClassification struct {
-	Category    Category   `json:"category"`
-	SubCategory SubCategory `json:"subCategory"`
-	Skills      []Skill    `json:"skills"`
-}
-
-// Category represents a job category
-type Category struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
-}
-
-// SubCategory represents a job subcategory
-type SubCategory struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
-}
-
-// Skill represents a skill
-type Skill struct {
-	ID         ID     `json:"id"`
-	PrettyName string `json:"prettyName"`
+	Category    models.Category    `json:"category"`
+	SubCategory models.SubCategory `json:"subCategory"`
+	Skills      []models.Skill     `json:"skills"`
 }
 
 // JobOwnership represents job ownership information
 type JobOwnership struct {
-	Company Company `json:"company"`
-	Team    Team    `json:"team"`
-}
-
-// Team represents a team
-type Team struct {
-	ID   ID     `json:"id"`
-	Rid  string `json:"rid"`
-	Name string `json:"name"`
+	Company models.Company `json:"company"`
+	Team    models.Team    `json:"team"`
 }
 
 // Attachment represents an attachment
@@ -148,23 +128,51 @@ type ContractorQualification struct {
 
 // CreateJobPostingInput represents input for creating a job
 type CreateJobPostingInput struct {
-	Title               string              `json:"title"`
-	Description         string              `json:"description"`
-	CategoryID          string              `json:"categoryId"`
-	SubCategoryID       string              `json:"subCategoryId"`
-	Skills              []string            `json:"skills"`
-	ContractType        ContractType        `json:"contractType"`
-	HourlyBudgetMin     *float64            `json:"hourlyBudgetMin,omitempty"`
-	HourlyBudgetMax     *float64            `json:"hourlyBudgetMax,omitempty"`
-	FixedPriceBudget    *float64            `json:"fixedPriceBudget,omitempty"`
-	Duration            string              `json:"duration,omitempty"`
-	Workload            string              `json:"workload,omitempty"`
-	ContractorType      string              `json:"contractorType,omitempty"`
-	TeamID              string              `json:"teamId"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	CategoryID       string       `json:"categoryId"`
+	SubCategoryID    string       `json:"subCategoryId,omitempty"`
+	Skills           []string     `json:"skills,omitempty"`
+	ContractType     ContractType `json:"contractType"`
+	HourlyBudgetMin  *float64     `json:"hourlyBudgetMin,omitempty"`
+	HourlyBudgetMax  *float64     `json:"hourlyBudgetMax,omitempty"`
+	FixedPriceBudget *float64     `json:"fixedPriceBudget,omitempty"`
+	Duration         string       `json:"duration,omitempty"`
+	Workload         string       `json:"workload,omitempty"`
+	ContractorType   string       `json:"contractorType,omitempty"`
+	TeamID           string       `json:"teamId,omitempty"`
+}
+
+// Validate checks the required fields and budgets. A fixed-price job needs
+// a FixedPriceBudget; budgets must be positive and an hourly minimum must
+// not exceed the maximum.
+func (in CreateJobPostingInput) Validate() error {
+	if err := firstError(
+		required("title", in.Title),
+		required("description", in.Description),
+		required("categoryId", in.CategoryID),
+		required("contractType", string(in.ContractType)),
+		validateEnum("contractType", in.ContractType, in.ContractType.IsValid()),
+	); err != nil {
+		return err
+	}
+	if in.ContractType == ContractTypeFixedPrice && in.FixedPriceBudget == nil {
+		return &errors.ValidationError{Field: "fixedPriceBudget", Message: "is required for a fixed-price job"}
+	}
+	return firstError(
+		validateBudget("hourlyBudgetMin", in.HourlyBudgetMin),
+		validateBudget("hourlyBudgetMax", in.HourlyBudgetMax),
+		validateBudget("fixedPriceBudget", in.FixedPriceBudget),
+		validateBudgetRange(in.HourlyBudgetMin, in.HourlyBudgetMax),
+	)
 }
 
 // CreateJobPosting creates a new job posting
 func (s *JobsService) CreateJobPosting(ctx context.Context, input CreateJobPostingInput) (*JobPosting, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation CreateJobPosting($input: CreateJobPostingInput!) {
 			createJobPosting(input: $input) {
@@ -183,38 +191,111 @@ func (s *JobsService) CreateJobPosting(ctx context.Context, input CreateJobPosti
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		CreateJobPosting JobPosting `json:"createJobPosting"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.CreateJobPosting, nil
 }
 
-// UpdateJobPostingInput represents input for updating a job
+// UpdateJobPostingInput represents changes to a job posting. Nil fields
+// are left unchanged and are not sent, so a partial update cannot clear
+// them by accident.
 type UpdateJobPostingInput struct {
-	ID               string   `json:"id"`
-	Title            string   `json:"title,omitempty"`
-	Description      string   `json:"description,omitempty"`
-	Skills           []string `json:"skills,omitempty"`
-	HourlyBudgetMin  *float64 `json:"hourlyBudgetMin,omitempty"`
-	HourlyBudgetMax  *float64 `json:"hourlyBudgetMax,omitempty"`
-	FixedPriceBudget *float64 `json:"fixedPriceBudget,omitempty"`
+	ID               string    `json:"id"`
+	Title            *string   `json:"title,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	Skills           *[]string `json:"skills,omitempty"`
+	HourlyBudgetMin  *float64  `json:"hourlyBudgetMin,omitempty"`
+	HourlyBudgetMax  *float64  `json:"hourlyBudgetMax,omitempty"`
+	FixedPriceBudget *float64  `json:"fixedPriceBudget,omitempty"`
+	// Status moves the posting, e.g. to JobStatusCancelled to close it
+	Status *JobStatus `json:"status,omitempty"`
+}
+
+// SetTitle changes the title
+func (in *UpdateJobPostingInput) SetTitle(title string) *UpdateJobPostingInput {
+	in.Title = &title
+	return in
+}
+
+// SetDescription changes the description
+func (in *UpdateJobPostingInput) SetDescription(description string) *UpdateJobPostingInput {
+	in.Description = &description
+	return in
+}
+
+// SetSkills replaces the skills. Calling it with no skills clears them.
+func (in *UpdateJobPostingInput) SetSkills(skills ...string) *UpdateJobPostingInput {
+	skills = append([]string{}, skills...)
+	in.Skills = &skills
+	return in
+}
+
+// SetHourlyBudget changes the hourly budget range
+func (in *UpdateJobPostingInput) SetHourlyBudget(low, high float64) *UpdateJobPostingInput {
+	in.HourlyBudgetMin = &low
+	in.HourlyBudgetMax = &high
+	return in
+}
+
+// SetFixedPriceBudget changes the fixed-price budget
+func (in *UpdateJobPostingInput) SetFixedPriceBudget(budget float64) *UpdateJobPostingInput {
+	in.FixedPriceBudget = &budget
+	return in
+}
+
+// SetStatus changes the status
+func (in *UpdateJobPostingInput) SetStatus(status JobStatus) *UpdateJobPostingInput {
+	in.Status = &status
+	return in
+}
+
+// Validate checks the job ID, that something is being changed and any
+// title, budgets or status being changed
+func (in UpdateJobPostingInput) Validate() error {
+	if err := required("id", in.ID); err != nil {
+		return err
+	}
+	if in == (UpdateJobPostingInput{ID: in.ID}) {
+		return &errors.ValidationError{Field: "input", Message: "at least one change is required"}
+	}
+	if in.Title != nil {
+		if err := required("title", *in.Title); err != nil {
+			return err
+		}
+	}
+	if in.Status != nil {
+		if err := validateEnum("status", *in.Status, in.Status.IsValid()); err != nil {
+			return err
+		}
+	}
+	return firstError(
+		validateBudget("hourlyBudgetMin", in.HourlyBudgetMin),
+		validateBudget("hourlyBudgetMax", in.HourlyBudgetMax),
+		validateBudget("fixedPriceBudget", in.FixedPriceBudget),
+		validateBudgetRange(in.HourlyBudgetMin, in.HourlyBudgetMax),
+	)
 }
 
 // UpdateJobPosting updates an existing job posting
 func (s *JobsService) UpdateJobPosting(ctx context.Context, input UpdateJobPostingInput) (*JobPosting, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation UpdateJobPosting($input: UpdateJobPostingInput!) {
 			updateJobPosting(input: $input) {
@@ -232,25 +313,31 @@ func (s *JobsService) UpdateJobPosting(ctx context.Context, input UpdateJobPosti
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		UpdateJobPosting JobPosting `json:"updateJobPosting"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.UpdateJobPosting, nil
 }
 
+// CloseJobPosting closes a job posting to new proposals by cancelling it
+func (s *JobsService) CloseJobPosting(ctx context.Context, jobID string) (*JobPosting, error) {
+	input := UpdateJobPostingInput{ID: jobID}
+	return s.UpdateJobPosting(ctx, *input.SetStatus(JobStatusCancelled))
+}
+
 // GetJobPosting retrieves a job posting by ID
 func (s *JobsService) GetJobPosting(ctx context.Context, jobID string) (*JobPosting, error) {
 	query := `
@@ -328,40 +415,40 @@ func (s *JobsService) GetJobPosting(ctx context.Context, jobID string) (*JobPost
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"jobPostingId": jobID,
 		},
 	}
-	
+
 	var resp struct {
 		JobPosting JobPosting `json:"jobPosting"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.JobPosting, nil
 }
 
 // ListJobsInput represents input for listing jobs
 type ListJobsInput struct {
-	TeamIDs      []string         `json:"postByTeamIds,omitempty"`
-	PersonIDs    []string         `json:"postByPersonIds,omitempty"`
-	Status       []JobStatus      `json:"status,omitempty"`
-	ContractType ContractType     `json:"contractType,omitempty"`
-	CreatedFrom  string           `json:"createdDateTimeFrom,omitempty"`
-	CreatedTo    string           `json:"createdDateTimeTo,omitempty"`
-	Pagination   *PaginationInput `json:"pagination,omitempty"`
+	TeamIDs      []string                `json:"postByTeamIds,omitempty"`
+	PersonIDs    []string                `json:"postByPersonIds,omitempty"`
+	Status       []JobStatus             `json:"status,omitempty"`
+	ContractType ContractType            `json:"contractType,omitempty"`
+	CreatedFrom  string                  `json:"createdDateTimeFrom,omitempty"`
+	CreatedTo    string                  `json:"createdDateTimeTo,omitempty"`
+	Pagination   *models.PaginationInput `json:"pagination,omitempty"`
 }
 
 // JobPostingList represents a list of job postings
 type JobPostingList struct {
 	TotalCount int              `json:"totalCount"`
-	PageInfo   PageInfo         `json:"pageInfo"`
+	PageInfo   models.PageInfo  `json:"pageInfo"`
 	Edges      []JobPostingEdge `json:"edges"`
 }
 
@@ -406,7 +493,7 @@ func (s *JobsService) ListJobs(ctx context.Context, input ListJobsInput) (*JobPo
 			}
 		}
 	`
-	
+
 	filter := map[string]interface{}{}
 	if len(input.TeamIDs) > 0 {
 		filter["postByTeamIds_any"] = input.TeamIDs
@@ -423,47 +510,258 @@ func (s *JobsService) ListJobs(ctx context.Context, input ListJobsInput) (*JobPo
 	if input.Pagination != nil {
 		filter["pagination_eq"] = input.Pagination
 	}
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"filter": filter,
 		},
 	}
-	
+
 	var resp struct {
 		Organization struct {
 			JobPosting JobPostingList `json:"jobPosting"`
 		} `json:"organization"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Organization.JobPosting, nil
 }
 
 // MarketplaceJobFilter represents marketplace job search filters
 type MarketplaceJobFilter struct {
-	SearchExpression string           `json:"searchExpression_eq,omitempty"`
-	SkillExpression  string           `json:"skillExpression_eq,omitempty"`
-	TitleExpression  string           `json:"titleExpression_eq,omitempty"`
-	CategoryIDs      []string         `json:"categoryIds_any,omitempty"`
-	SubcategoryIDs   []string         `json:"subcategoryIds_any,omitempty"`
-	JobType          ContractType     `json:"jobType_eq,omitempty"`
-	Duration         string           `json:"duration_eq,omitempty"`
-	Workload         string           `json:"workload_eq,omitempty"`
-	ExperienceLevel  string           `json:"experienceLevel_eq,omitempty"`
-	DaysPosted       int              `json:"daysPosted_eq,omitempty"`
-	Pagination       *PaginationInput `json:"pagination_eq,omitempty"`
-}
-
-// SearchJobs searches for jobs in the marketplace
-func (s *JobsService) SearchJobs(ctx context.Context, filter MarketplaceJobFilter) (*JobPostingList, error) {
+	SearchExpression string       `json:"searchExpression_eq,omitempty"`
+	SkillExpression  string       `json:"skillExpression_eq,omitempty"`
+	TitleExpression  string       `json:"titleExpression_eq,omitempty"`
+	CategoryIDs      []string     `json:"categoryIds_any,omitempty"`
+	SubcategoryIDs   []string     `json:"subcategoryIds_any,omitempty"`
+	JobType          ContractType `json:"jobType_eq,omitempty"`
+	Duration         string       `json:"duration_eq,omitempty"`
+	Workload         string       `json:"workload_eq,omitempty"`
+	ExperienceLevel  string       `json:"experienceLevel_eq,omitempty"`
+	DaysPosted       int          `json:"daysPosted_eq,omitempty"`
+	// BudgetRange limits fixed-price jobs to budgets in this range
+	BudgetRange *IntRange `json:"budgetRange_eq,omitempty"`
+	// HourlyRate limits hourly jobs to rates in this range
+	HourlyRate *IntRange `json:"hourlyRate_eq,omitempty"`
+	// VerifiedPaymentOnly limits results to clients with a verified
+	// payment method
+	VerifiedPaymentOnly bool `json:"verifiedPaymentOnly_eq,omitempty"`
+	// ClientHires limits results to clients with this many past hires.
+	// Set only RangeStart for a minimum.
+	ClientHires *IntRange               `json:"clientHiresRange_eq,omitempty"`
+	Pagination  *models.PaginationInput `json:"pagination_eq,omitempty"`
+
+	// SortAttribute orders the results; the API's default is used if empty.
+	// It is sent alongside the filter rather than in it.
+	SortAttribute JobSortField `json:"-"`
+	// Ascending reverses SortAttribute's default descending order
+	Ascending bool `json:"-"`
+}
+
+// IntRange represents an inclusive range of whole numbers. A zero bound is
+// open.
+type IntRange struct {
+	RangeStart int `json:"rangeStart,omitempty"`
+	RangeEnd   int `json:"rangeEnd,omitempty"`
+}
+
+// validate checks that neither bound is negative and that the start does
+// not exceed the end
+func (r *IntRange) validate(field string) error {
+	if r == nil {
+		return nil
+	}
+	if err := firstError(
+		validateNonNegative(field+".rangeStart", &r.RangeStart),
+		validateNonNegative(field+".rangeEnd", &r.RangeEnd),
+	); err != nil {
+		return err
+	}
+	if r.RangeEnd > 0 && r.RangeStart > r.RangeEnd {
+		return &errors.ValidationError{
+			Field:   field + ".rangeEnd",
+			Message: fmt.Sprintf("must not be less than rangeStart (%d)", r.RangeStart),
+			Value:   r.RangeEnd,
+		}
+	}
+	return nil
+}
+
+// JobSortField represents a field marketplace search results can be
+// sorted by
+type JobSortField string
+
+const (
+	JobSortRecency           JobSortField = "RECENCY"
+	JobSortRelevance         JobSortField = "RELEVANCE"
+	JobSortClientTotalCharge JobSortField = "CLIENT_TOTAL_CHARGE"
+)
+
+// Validate checks the job type, sort attribute and ranges
+func (f MarketplaceJobFilter) Validate() error {
+	if err := firstError(
+		validateEnum("jobType", f.JobType, f.JobType.IsValid()),
+		validateEnum("sortAttribute", f.SortAttribute, f.SortAttribute.IsValid()),
+		f.BudgetRange.validate("budgetRange"),
+		f.HourlyRate.validate("hourlyRate"),
+		f.ClientHires.validate("clientHires"),
+	); err != nil {
+		return err
+	}
+	if f.Ascending && f.SortAttribute == "" {
+		return &errors.ValidationError{Field: "ascending", Message: "requires a sortAttribute"}
+	}
+	return nil
+}
+
+// sortAttributes returns the sortAttributes variable of a search, or nil
+// if no sort was requested
+func (f MarketplaceJobFilter) sortAttributes() []map[string]interface{} {
+	if f.SortAttribute == "" {
+		return nil
+	}
+	order := models.SortOrderDesc
+	if f.Ascending {
+		order = models.SortOrderAsc
+	}
+	return []map[string]interface{}{{"field": f.SortAttribute, "sortOrder": order}}
+}
+
+// MarketplaceJobPosting represents a job posting returned by a
+// marketplace search
+type MarketplaceJobPosting struct {
+	ID              models.ID            `json:"id"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description"`
+	CreatedDateTime models.DateTime      `json:"createdDateTime"`
+	Client          MarketplaceJobClient `json:"client"`
+}
+
+// MarketplaceJobClient represents the client who posted a marketplace job
+type MarketplaceJobClient struct {
+	Location        MarketplaceJobClientLocation `json:"location"`
+	TotalFeedback   float64                      `json:"totalFeedback"`
+	TotalHires      int                          `json:"totalHires"`
+	TotalPostedJobs int                          `json:"totalPostedJobs"`
+}
+
+// MarketplaceJobClientLocation represents the location of a marketplace
+// job's client
+type MarketplaceJobClientLocation struct {
+	Country string `json:"country"`
+}
+
+// MarketplaceJobPostingEdge represents a marketplace job posting edge
+type MarketplaceJobPostingEdge struct {
+	Cursor string                `json:"cursor"`
+	Node   MarketplaceJobPosting `json:"node"`
+}
+
+// MarketplaceJobSearchResult represents a page of marketplace search
+// results
+type MarketplaceJobSearchResult struct {
+	TotalCount int                         `json:"totalCount"`
+	PageInfo   models.PageInfo             `json:"pageInfo"`
+	Edges      []MarketplaceJobPostingEdge `json:"edges"`
+
+	service *JobsService
+	filter  MarketplaceJobFilter
+}
+
+// Jobs returns the job postings on this page
+func (r *MarketplaceJobSearchResult) Jobs() []MarketplaceJobPosting {
+	jobs := make([]MarketplaceJobPosting, 0, len(r.Edges))
+	for _, edge := range r.Edges {
+		jobs = append(jobs, edge.Node)
+	}
+	return jobs
+}
+
+// HasNextPage returns true if there are more results after this page
+func (r *MarketplaceJobSearchResult) HasNextPage() bool {
+	return r.PageInfo.HasNextPage && r.PageInfo.EndCursor != ""
+}
+
+// NextPage fetches the page after this one with the same filter and page
+// size. It returns nil if this is the last page.
+func (r *MarketplaceJobSearchResult) NextPage(ctx context.Context) (*MarketplaceJobSearchResult, error) {
+	if !r.HasNextPage() || r.service == nil {
+		return nil, nil
+	}
+
+	filter := r.filter
+	pagination := models.PaginationInput{After: r.PageInfo.EndCursor}
+	if filter.Pagination != nil {
+		pagination.First = filter.Pagination.First
+	}
+	filter.Pagination = &pagination
+
+	return r.service.SearchJobs(ctx, filter)
+}
+
+// All returns the job postings on this page and every page after it, up to
+// the client's MaxItems
+func (r *MarketplaceJobSearchResult) All(ctx context.Context) ([]MarketplaceJobPosting, error) {
+	var jobs []MarketplaceJobPosting
+	it := r.Iter(ctx)
+	for it.Next() {
+		jobs = append(jobs, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Iter returns an iterator over the job postings on this page and every
+// page after it. Use Prefetch on it to fetch pages ahead.
+func (r *MarketplaceJobSearchResult) Iter(ctx context.Context) *Iterator[MarketplaceJobPosting] {
+	var client *BaseClient
+	if r.service != nil {
+		client = r.service.client
+	}
+
+	page := r
+	return newIterator(ctx, client, r.Jobs(), r.HasNextPage(), func(ctx context.Context) ([]MarketplaceJobPosting, bool, error) {
+		next, err := page.NextPage(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		if next == nil || next.PageInfo.EndCursor == page.PageInfo.EndCursor {
+			return nil, false, nil
+		}
+		page = next
+		return next.Jobs(), next.HasNextPage(), nil
+	})
+}
+
+// SearchJobs searches for jobs in the marketplace. Use NextPage, Iter or All
+// on the result to fetch further pages. Without a page size in
+// filter.Pagination, the client's DefaultPageSize is used if set.
+func (s *JobsService) SearchJobs(ctx context.Context, filter MarketplaceJobFilter) (*MarketplaceJobSearchResult, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if filter.Pagination == nil || filter.Pagination.First <= 0 {
+		if size := s.client.pageSize(ctx, 0); size > 0 {
+			pagination := models.PaginationInput{First: size}
+			if filter.Pagination != nil {
+				pagination.After = filter.Pagination.After
+			}
+			filter.Pagination = &pagination
+		}
+	}
+
 	query := `
-		query SearchJobs($filter: MarketplaceJobFilter) {
-			marketplaceJobPostings(marketPlaceJobFilter: $filter) {
+		query SearchJobs($filter: MarketplaceJobFilter, $sortAttributes: [MarketplaceJobPostingSearchSortAttribute]) {
+			marketplaceJobPostings(marketPlaceJobFilter: $filter, sortAttributes: $sortAttributes) {
 				totalCount
 				pageInfo {
 					hasNextPage
@@ -489,21 +787,29 @@ func (s *JobsService) SearchJobs(ctx context.Context, filter MarketplaceJobFilte
 			}
 		}
 	`
-	
+
+	variables := map[string]interface{}{
+		"filter": filter,
+	}
+	if sort := filter.sortAttributes(); sort != nil {
+		variables["sortAttributes"] = sort
+	}
+
 	req := &GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"filter": filter,
-		},
+		Query:     query,
+		Variables: variables,
 	}
-	
+
 	var resp struct {
-		MarketplaceJobPostings JobPostingList `json:"marketplaceJobPostings"`
+		MarketplaceJobPostings MarketplaceJobSearchResult `json:"marketplaceJobPostings"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
-	return &resp.MarketplaceJobPostings, nil
-}
\ No newline at end of file
+
+	result := &resp.MarketplaceJobPostings
+	result.service = s
+	result.filter = filter
+	return result, nil
+}
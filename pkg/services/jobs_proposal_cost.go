@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// ProposalCostEstimate is what it costs, in Connects, to submit a proposal
+// to a job, and what other freelancers are bidding
+type ProposalCostEstimate struct {
+	JobID models.ID `json:"jobId"`
+	// RequiredConnects is the number of Connects a proposal costs before
+	// any boost
+	RequiredConnects int `json:"requiredConnects"`
+	// ConnectsBalance is the authenticated freelancer's available Connects
+	ConnectsBalance int `json:"connectsBalance"`
+	// BoostOptions are the extra Connects needed to reach each boosted
+	// position, best position first
+	BoostOptions []ProposalBoostOption `json:"boostOptions"`
+	Bids         BidStatistics         `json:"bidStatistics"`
+	// Questions are the screening questions the proposal must answer
+	Questions []string `json:"questions"`
+}
+
+// ProposalBoostOption is the bid needed to place a proposal at a boosted
+// position
+type ProposalBoostOption struct {
+	// Position is the boosted rank, starting at 1
+	Position int `json:"position"`
+	Connects int `json:"connects"`
+}
+
+// BidStatistics summarizes the bids on a job's proposals. The amounts are
+// hourly rates for hourly jobs and totals for fixed-price jobs; they are nil
+// until the job has proposals.
+type BidStatistics struct {
+	Proposals int           `json:"proposals"`
+	Low       *models.Money `json:"low"`
+	Average   *models.Money `json:"average"`
+	High      *models.Money `json:"high"`
+}
+
+// TotalConnects returns the Connects a proposal costs with the given boost
+// position, or without a boost if position is 0 or not offered
+func (e ProposalCostEstimate) TotalConnects(position int) int {
+	for _, option := range e.BoostOptions {
+		if option.Position == position {
+			return e.RequiredConnects + option.Connects
+		}
+	}
+	return e.RequiredConnects
+}
+
+// CanAfford returns true if the freelancer's balance covers a proposal with
+// the given boost position
+func (e ProposalCostEstimate) CanAfford(position int) bool {
+	return e.TotalConnects(position) <= e.ConnectsBalance
+}
+
+// EstimateProposalCost returns the Connects needed to apply to a job, the
+// boost options and the bid range of the proposals so far
+func (s *JobsService) EstimateProposalCost(ctx context.Context, jobID string) (*ProposalCostEstimate, error) {
+	if err := required("jobId", jobID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query EstimateProposalCost($jobPostingId: ID!) {
+			proposalCostEstimate(jobPostingId: $jobPostingId) {
+				jobId
+				requiredConnects
+				connectsBalance
+				boostOptions {
+					position
+					connects
+				}
+				bidStatistics {
+					proposals
+					low {
+						rawValue
+						currency
+						displayValue
+					}
+					average {
+						rawValue
+						currency
+						displayValue
+					}
+					high {
+						rawValue
+						currency
+						displayValue
+					}
+				}
+				questions
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"jobPostingId": jobID,
+		},
+	}
+
+	var resp struct {
+		ProposalCostEstimate ProposalCostEstimate `json:"proposalCostEstimate"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.ProposalCostEstimate, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateProposalCost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "proposalCostEstimate")
+		assert.Equal(t, "job-1", req.Variables["jobPostingId"])
+		w.Write([]byte(`{"data":{"proposalCostEstimate":{
+			"jobId":"job-1","requiredConnects":16,"connectsBalance":40,
+			"boostOptions":[{"position":1,"connects":30},{"position":2,"connects":18}],
+			"bidStatistics":{"proposals":12,
+				"low":{"rawValue":"25","currency":"USD"},
+				"average":{"rawValue":"48.5","currency":"USD"},
+				"high":{"rawValue":"90","currency":"USD"}},
+			"questions":["Describe a similar project"]
+		}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	_, err := svc.EstimateProposalCost(ctx, "")
+	assertValidationField(t, err, "jobId")
+
+	estimate, err := svc.EstimateProposalCost(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, 12, estimate.Bids.Proposals)
+	assert.Equal(t, "48.50 USD", estimate.Bids.Average.String())
+	assert.Equal(t, []string{"Describe a similar project"}, estimate.Questions)
+
+	assert.Equal(t, 16, estimate.TotalConnects(0))
+	assert.Equal(t, 34, estimate.TotalConnects(2))
+	assert.Equal(t, 16, estimate.TotalConnects(5))
+	assert.True(t, estimate.CanAfford(2))
+	assert.False(t, estimate.CanAfford(1))
+}
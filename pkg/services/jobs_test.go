@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchJobsSortAndFilters(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		w.Write([]byte(`{"data":{"marketplaceJobPostings":{"totalCount":0,"edges":[]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	_, err := svc.SearchJobs(ctx, MarketplaceJobFilter{
+		SearchExpression:    "golang",
+		SortAttribute:       JobSortClientTotalCharge,
+		Ascending:           true,
+		VerifiedPaymentOnly: true,
+		ClientHires:         &IntRange{RangeStart: 5},
+		BudgetRange:         &IntRange{RangeStart: 500, RangeEnd: 2000},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 2)
+	assert.Equal(t, map[string]interface{}{
+		"searchExpression_eq":    "golang",
+		"verifiedPaymentOnly_eq": true,
+		"clientHiresRange_eq":    map[string]interface{}{"rangeStart": 5.0},
+		"budgetRange_eq":         map[string]interface{}{"rangeStart": 500.0, "rangeEnd": 2000.0},
+	}, reqs[0].Variables["filter"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"field": "CLIENT_TOTAL_CHARGE", "sortOrder": "ASC"},
+	}, reqs[0].Variables["sortAttributes"])
+	assert.NotContains(t, reqs[1].Variables, "sortAttributes")
+}
+
+func TestMarketplaceJobFilterValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter MarketplaceJobFilter
+		field  string
+	}{
+		{"unknown sort", MarketplaceJobFilter{SortAttribute: "POPULARITY"}, "sortAttribute"},
+		{"ascending without sort", MarketplaceJobFilter{Ascending: true}, "ascending"},
+		{"unknown job type", MarketplaceJobFilter{JobType: "RETAINER"}, "jobType"},
+		{"negative hires", MarketplaceJobFilter{ClientHires: &IntRange{RangeStart: -1}}, "clientHires.rangeStart"},
+		{"inverted budget", MarketplaceJobFilter{BudgetRange: &IntRange{RangeStart: 2000, RangeEnd: 500}}, "budgetRange.rangeEnd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationField(t, tt.filter.Validate(), tt.field)
+		})
+	}
+
+	assert.NoError(t, MarketplaceJobFilter{
+		SortAttribute: JobSortRecency,
+		HourlyRate:    &IntRange{RangeEnd: 80},
+	}.Validate())
+}
+
+func TestJobContentDescription(t *testing.T) {
+	var job JobPosting
+	require.NoError(t, json.Unmarshal([]byte(`{"content":{"title":"Go SDK","description":"<p>Build a <b>Go</b> SDK:</p><ul><li>GraphQL</li><li>OAuth2</li></ul><script>x()</script>"}}`), &job))
+
+	assert.Equal(t, "Build a Go SDK:\n\n- GraphQL\n- OAuth2", job.Content.Description())
+	assert.Equal(t, "Build a **Go** SDK:\n\n- GraphQL\n- OAuth2", job.Content.DescriptionMarkdown())
+	assert.Equal(t, "<p>Build a <b>Go</b> SDK:</p><ul><li>GraphQL</li><li>OAuth2</li></ul>", job.Content.SanitizedDescription())
+
+	// Plain text descriptions keep their line breaks
+	job.Content.DescriptionHTML = "Build a Go SDK.\n\nMust know GraphQL &amp; OAuth2."
+	assert.Equal(t, "Build a Go SDK.\n\nMust know GraphQL & OAuth2.", job.Content.Description())
+}
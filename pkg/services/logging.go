@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+// DefaultRedactFields are the variable fields whose values are redacted
+// from request logs unless BaseClient.RedactFields is set
+var DefaultRedactFields = []string{
+	"body", "message", "email", "phone", "token", "password", "secret", "address",
+}
+
+// redacted replaces the values of redacted fields in logs
+const redacted = "[REDACTED]"
+
+// logRequest logs a completed request at debug level with its operation
+// name, redacted variables, duration and error. The query itself is not
+// logged, since it may contain literal values.
+func (c *BaseClient) logRequest(ctx context.Context, req *GraphQLRequest, event RequestEvent) {
+	if !c.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", req.OperationName),
+		slog.Any("variables", c.redactVariables(req.Variables)),
+		slog.Duration("duration", event.Duration),
+	}
+	if event.Attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", event.Attempts))
+	}
+	if event.RateLimited > 0 {
+		attrs = append(attrs, slog.Int("rate_limited", event.RateLimited))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	c.Logger.LogAttrs(ctx, slog.LevelDebug, "graphql request", attrs...)
+}
+
+// redactVariables returns vars with the values of fields matching
+// RedactFields replaced, at any depth. Variables are converted through
+// JSON so input structs are redacted by their field names on the wire. If
+// that fails, only the variable names are returned.
+func (c *BaseClient) redactVariables(vars map[string]interface{}) interface{} {
+	if len(vars) == 0 {
+		return nil
+	}
+
+	deny := c.RedactFields
+	if deny == nil {
+		deny = DefaultRedactFields
+	}
+
+	var plain interface{}
+	data, err := json.Marshal(vars)
+	if err == nil {
+		err = json.Unmarshal(data, &plain)
+	}
+	if err != nil {
+		keys := make([]string, 0, len(vars))
+		for key := range vars {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+	return redactValue(plain, deny)
+}
+
+// redactValue redacts the fields of v matching deny in place
+func redactValue(v interface{}, deny []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactField(key, deny) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value, deny)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, deny)
+		}
+	}
+	return v
+}
+
+// redactField returns true if the field name contains any of deny, ignoring
+// case, so "email" also redacts "emailAddress"
+func redactField(name string, deny []string) bool {
+	name = strings.ToLower(name)
+	for _, field := range deny {
+		if field != "" && strings.Contains(name, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
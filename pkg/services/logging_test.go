@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		Logger:     slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	type storyInput struct {
+		RoomID  string `json:"roomId"`
+		Message string `json:"message"`
+	}
+	err := client.Do(context.Background(), &GraphQLRequest{
+		Query: `mutation SendMessage($input: StoryInput!, $emails: [String!]) { createStory(input: $input) { id } }`,
+		Variables: map[string]interface{}{
+			"input":  storyInput{RoomID: "room-1", Message: "my salary is confidential"},
+			"emails": []string{"a@example.com"},
+			"people": []interface{}{map[string]interface{}{"name": "Ann", "accessToken": "secret-token"}},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "confidential")
+	assert.NotContains(t, buf.String(), "example.com")
+	assert.NotContains(t, buf.String(), "secret-token")
+
+	var entry struct {
+		Level     string                 `json:"level"`
+		Msg       string                 `json:"msg"`
+		Operation string                 `json:"operation"`
+		Variables map[string]interface{} `json:"variables"`
+		Duration  int64                  `json:"duration"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "DEBUG", entry.Level)
+	assert.Equal(t, "graphql request", entry.Msg)
+	assert.Equal(t, "SendMessage", entry.Operation)
+	assert.Equal(t, map[string]interface{}{
+		"input":  map[string]interface{}{"roomId": "room-1", "message": redacted},
+		"emails": redacted,
+		"people": []interface{}{map[string]interface{}{"name": "Ann", "accessToken": redacted}},
+	}, entry.Variables)
+	assert.Positive(t, entry.Duration)
+
+	// A custom deny list replaces the defaults
+	buf.Reset()
+	client.RedactFields = []string{"roomid"}
+	err = client.Do(context.Background(), &GraphQLRequest{
+		Query:     `query GetRoom($roomId: ID!, $message: String) { room(id: $roomId) { id } }`,
+		Variables: map[string]interface{}{"roomId": "room-1", "message": "hi"},
+	}, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "room-1")
+	assert.Contains(t, buf.String(), `"message":"hi"`)
+
+	// Nothing is logged above debug level
+	buf.Reset()
+	client.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	require.NoError(t, client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, nil))
+	assert.Empty(t, buf.String())
+}
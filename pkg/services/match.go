@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// MatchWeights sets how much each part of a match contributes to its
+// total. Parts that cannot be scored, such as the rate of a fixed-price
+// job, are left out and the remaining weights rescaled.
+type MatchWeights struct {
+	Skills  float64
+	Rate    float64
+	History float64
+}
+
+// DefaultMatchWeights are used when MatchOptions.Weights is zero
+var DefaultMatchWeights = MatchWeights{Skills: 0.5, Rate: 0.3, History: 0.2}
+
+// MatchOptions configures ScoreMatch
+type MatchOptions struct {
+	// HourlyRate is the freelancer's rate, compared against the budget of
+	// hourly jobs. The rate is not scored if nil.
+	HourlyRate *models.Money
+	Weights    MatchWeights
+}
+
+// MatchScore is how well a freelancer fits a job. Scores are between 0
+// and 1.
+type MatchScore struct {
+	Total   float64      `json:"total"`
+	Skills  SkillMatch   `json:"skills"`
+	Rate    RateMatch    `json:"rate"`
+	History HistoryMatch `json:"history"`
+}
+
+// SkillMatch is the overlap between a job's skills and a profile's
+type SkillMatch struct {
+	Score   float64        `json:"score"`
+	Matched []models.Skill `json:"matched"`
+	Missing []models.Skill `json:"missing"`
+}
+
+// RateMatch is how a freelancer's rate compares with a job's hourly
+// budget. Scored is false if the job is not hourly or either side has no
+// rate.
+type RateMatch struct {
+	Scored bool          `json:"scored"`
+	Score  float64       `json:"score"`
+	Rate   *models.Money `json:"rate,omitempty"`
+	Min    *models.Money `json:"min,omitempty"`
+	Max    *models.Money `json:"max,omitempty"`
+}
+
+// HistoryMatch scores a freelancer's feedback and completed jobs
+type HistoryMatch struct {
+	Score         float64 `json:"score"`
+	FeedbackScore float64 `json:"feedbackScore"`
+	TotalJobs     int     `json:"totalJobs"`
+	TopRated      bool    `json:"topRated"`
+}
+
+// matchJobsForFullHistory is the number of completed jobs at which the
+// job count part of the history score saturates
+const matchJobsForFullHistory = 20
+
+// MatchScore fetches a job posting and a freelancer profile and scores how
+// well they fit with ScoreMatch
+func (s *JobsService) MatchScore(ctx context.Context, jobID, profileKey string, opts MatchOptions) (*MatchScore, error) {
+	job, err := s.GetJobPosting(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.freelancers.GetFreelancerProfile(ctx, profileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	score := ScoreMatch(*job, *profile, opts)
+	return &score, nil
+}
+
+// ScoreMatch scores how well a freelancer profile fits a job posting
+// without making any requests. Skills match by ontology ID or, failing
+// that, by name ignoring case.
+func ScoreMatch(job JobPosting, profile FreelancerProfile, opts MatchOptions) MatchScore {
+	weights := opts.Weights
+	if weights == (MatchWeights{}) {
+		weights = DefaultMatchWeights
+	}
+
+	score := MatchScore{
+		Skills:  scoreSkills(job.Classification.Skills, profile.Skills),
+		Rate:    scoreRate(job, opts.HourlyRate),
+		History: scoreHistory(profile.Aggregates),
+	}
+
+	total := weights.Skills*score.Skills.Score + weights.History*score.History.Score
+	sum := weights.Skills + weights.History
+	if score.Rate.Scored {
+		total += weights.Rate * score.Rate.Score
+		sum += weights.Rate
+	}
+	if sum > 0 {
+		score.Total = total / sum
+	}
+	return score
+}
+
+// scoreSkills returns the fraction of the job's skills the profile has. A
+// job without skills matches fully.
+func scoreSkills(jobSkills []models.Skill, profileSkills []ProfileSkill) SkillMatch {
+	have := make(map[string]bool, 2*len(profileSkills))
+	for _, ps := range profileSkills {
+		for _, key := range []string{string(ps.Skill.ID), ps.SkillUID, strings.ToLower(ps.Skill.PrettyName)} {
+			if key != "" {
+				have[key] = true
+			}
+		}
+	}
+
+	match := SkillMatch{Score: 1}
+	for _, skill := range jobSkills {
+		if have[string(skill.ID)] || have[strings.ToLower(skill.PrettyName)] {
+			match.Matched = append(match.Matched, skill)
+		} else {
+			match.Missing = append(match.Missing, skill)
+		}
+	}
+	if len(jobSkills) > 0 {
+		match.Score = float64(len(match.Matched)) / float64(len(jobSkills))
+	}
+	return match
+}
+
+// scoreRate returns 1 for a rate at or below the job's maximum budget,
+// falling off in proportion to how far it exceeds it. Rates in another
+// currency than the budget are not scored.
+func scoreRate(job JobPosting, rate *models.Money) RateMatch {
+	match := RateMatch{Rate: rate, Min: job.Info.HourlyBudgetMin, Max: job.Info.HourlyBudgetMax}
+	if job.ContractTerms.ContractType != ContractTypeHourly || rate == nil || match.Max == nil || match.Max.IsZero() {
+		return match
+	}
+	cmp, err := rate.Cmp(*match.Max)
+	if err != nil {
+		return match
+	}
+
+	match.Scored, match.Score = true, 1
+	if cmp > 0 {
+		match.Score = match.Max.Float64() / rate.Float64()
+	}
+	return match
+}
+
+// scoreHistory weighs the profile's feedback score out of 5 against its
+// number of completed jobs
+func scoreHistory(agg ProfileAggregates) HistoryMatch {
+	feedback := math.Max(0, math.Min(agg.AdjustedFeedbackScore/MaxFeedbackScore, 1))
+	jobs := math.Min(float64(agg.TotalJobs)/matchJobsForFullHistory, 1)
+
+	return HistoryMatch{
+		Score:         0.7*feedback + 0.3*jobs,
+		FeedbackScore: agg.AdjustedFeedbackScore,
+		TotalJobs:     agg.TotalJobs,
+		TopRated:      agg.TopRatedStatus,
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func matchJob() JobPosting {
+	lo, hi := models.MustMoney("40", "USD"), models.MustMoney("80", "USD")
+	return JobPosting{
+		ID:            "job-1",
+		Info:          JobInfo{HourlyBudgetMin: &lo, HourlyBudgetMax: &hi},
+		ContractTerms: ContractTerms{ContractType: ContractTypeHourly},
+		Classification: JobClassification{Skills: []models.Skill{
+			{ID: "skill-go", PrettyName: "Go"},
+			{ID: "skill-graphql", PrettyName: "GraphQL"},
+			{ID: "skill-k8s", PrettyName: "Kubernetes"},
+			{ID: "skill-sql", PrettyName: "SQL"},
+		}},
+	}
+}
+
+func matchProfile() FreelancerProfile {
+	return FreelancerProfile{
+		Aggregates: ProfileAggregates{AdjustedFeedbackScore: 5, TotalJobs: 10},
+		Skills: []ProfileSkill{
+			{Skill: models.Skill{ID: "skill-go", PrettyName: "Go"}},
+			{Skill: models.Skill{PrettyName: "graphql"}},
+		},
+	}
+}
+
+func TestScoreMatch(t *testing.T) {
+	rate := models.MustMoney("100", "USD")
+	score := ScoreMatch(matchJob(), matchProfile(), MatchOptions{HourlyRate: &rate})
+
+	assert.Equal(t, 0.5, score.Skills.Score)
+	require.Len(t, score.Skills.Matched, 2)
+	assert.Equal(t, []models.Skill{{ID: "skill-k8s", PrettyName: "Kubernetes"}, {ID: "skill-sql", PrettyName: "SQL"}}, score.Skills.Missing)
+	assert.True(t, score.Rate.Scored)
+	assert.InDelta(t, 0.8, score.Rate.Score, 1e-9)
+	assert.InDelta(t, 0.85, score.History.Score, 1e-9)
+	assert.InDelta(t, 0.5*0.5+0.3*0.8+0.2*0.85, score.Total, 1e-9)
+}
+
+func TestScoreMatchWithoutRate(t *testing.T) {
+	job := matchJob()
+	job.ContractTerms.ContractType = ContractTypeFixedPrice
+	rate := models.MustMoney("60", "USD")
+
+	score := ScoreMatch(job, matchProfile(), MatchOptions{HourlyRate: &rate, Weights: MatchWeights{Skills: 1, Rate: 1}})
+	assert.False(t, score.Rate.Scored)
+	assert.Equal(t, 0.5, score.Total, "only skills count once the rate is left out")
+
+	eur := models.MustMoney("60", "EUR")
+	score = ScoreMatch(matchJob(), matchProfile(), MatchOptions{HourlyRate: &eur})
+	assert.False(t, score.Rate.Scored, "rates in another currency are not compared")
+}
+
+func TestJobsMatchScore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "jobPosting("):
+			assert.Equal(t, "job-1", req.Variables["jobPostingId"])
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"jobPosting": matchJob()}})
+		case strings.Contains(req.Query, "freelancerProfileByProfileKey"):
+			assert.Equal(t, "~profile", req.Variables["profileKey"])
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"freelancerProfileByProfileKey": matchProfile()}})
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewJobsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	score, err := svc.MatchScore(context.Background(), "job-1", "~profile", MatchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, score.Skills.Score)
+	assert.False(t, score.Rate.Scored)
+	assert.InDelta(t, (0.5*0.5+0.2*0.85)/0.7, score.Total, 1e-9)
+}
@@ -1,14 +1,22 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
 	"fmt"
+	"sync"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // MessagesService handles messaging-related API operations
 type MessagesService struct {
 	client *BaseClient
+
+	// contractRoomsMu guards contractRooms, the rooms found by
+	// FindRoomsForContracts keyed by contract ID
+	contractRoomsMu sync.Mutex
+	contractRooms   map[string]Room
 }
 
 // NewMessagesService creates a new messages service
@@ -18,54 +26,55 @@ func NewMessagesService(client *BaseClient) *MessagesService {
 
 // Room represents a chat room
 type Room struct {
-	ID                 ID           `json:"id"`
-	RoomName           string       `json:"roomName"`
-	RoomType           RoomType     `json:"roomType"`
-	Topic              string       `json:"topic"`
-	NumUnread          int          `json:"numUnread"`
-	NumUnreadMentions  int          `json:"numUnreadMentions"`
-	NumUsers           int          `json:"numUsers"`
-	Favorite           bool         `json:"favorite"`
-	ReadOnly           bool         `json:"readOnly"`
-	Hidden             bool         `json:"hidden"`
-	Public             bool         `json:"public"`
-	LastVisitedDateTime *DateTime    `json:"lastVisitedDateTime"`
-	LastReadDateTime    *DateTime    `json:"lastReadDateTime"`
-	CreatedAtDateTime   DateTime     `json:"createdAtDateTime"`
-	Organization        Organization `json:"organization"`
-	RoomUsers          []RoomUser   `json:"roomUsers"`
-	Creator            *RoomUser    `json:"creator"`
-	Owner              *RoomUser    `json:"owner"`
-	LatestStory        *Story       `json:"latestStory"`
+	ID                  models.ID           `json:"id"`
+	RoomName            string              `json:"roomName"`
+	RoomType            RoomType            `json:"roomType"`
+	Topic               string              `json:"topic"`
+	NumUnread           int                 `json:"numUnread"`
+	NumUnreadMentions   int                 `json:"numUnreadMentions"`
+	NumUsers            int                 `json:"numUsers"`
+	Favorite            bool                `json:"favorite"`
+	ReadOnly            bool                `json:"readOnly"`
+	Hidden              bool                `json:"hidden"`
+	Public              bool                `json:"public"`
+	LastVisitedDateTime *models.DateTime    `json:"lastVisitedDateTime"`
+	LastReadDateTime    *models.DateTime    `json:"lastReadDateTime"`
+	CreatedAtDateTime   models.DateTime     `json:"createdAtDateTime"`
+	Organization        models.Organization `json:"organization"`
+	RoomUsers           []RoomUser          `json:"roomUsers"`
+	Creator             *RoomUser           `json:"creator"`
+	Owner               *RoomUser           `json:"owner"`
+	LatestStory         *Story              `json:"latestStory"`
 }
 
 // RoomType represents the type of room
 type RoomType string
 
 const (
-	RoomTypeGroup       RoomType = "GROUP"
-	RoomTypeOneOnOne    RoomType = "ONE_ON_ONE"
-	RoomTypeInterview   RoomType = "INTERVIEW"
-	RoomTypeContract    RoomType = "CONTRACT"
-	RoomTypePublic      RoomType = "PUBLIC"
+	RoomTypeGroup     RoomType = "GROUP"
+	RoomTypeOneOnOne  RoomType = "ONE_ON_ONE"
+	RoomTypeInterview RoomType = "INTERVIEW"
+	RoomTypeContract  RoomType = "CONTRACT"
+	RoomTypePublic    RoomType = "PUBLIC"
+	RoomTypeUnknown   RoomType = "UNKNOWN"
 )
 
 // RoomUser represents a user in a room
 type RoomUser struct {
-	User         User         `json:"user"`
-	Organization Organization `json:"organization"`
-	Role         string       `json:"role"`
+	User         models.User         `json:"user"`
+	Organization models.Organization `json:"organization"`
+	Role         string              `json:"role"`
 }
 
 // Story represents a message/story in a room
 type Story struct {
-	ID               ID       `json:"id"`
-	CreatedDateTime  DateTime `json:"createdDateTime"`
-	UpdatedDateTime  DateTime `json:"updatedDateTime"`
-	User             User     `json:"user"`
-	Message          string   `json:"message"`
-	Organization     Organization `json:"organization"`
-	RoomStoryNote    *RoomStoryNote `json:"roomStoryNote"`
+	ID              models.ID           `json:"id"`
+	CreatedDateTime models.DateTime     `json:"createdDateTime"`
+	UpdatedDateTime models.DateTime     `json:"updatedDateTime"`
+	User            models.User         `json:"user"`
+	Message         string              `json:"message"`
+	Organization    models.Organization `json:"organization"`
+	RoomStoryNote   *RoomStoryNote      `json:"roomStoryNote"`
 }
 
 // RoomStoryNote represents a note on a story
@@ -76,23 +85,23 @@ type RoomStoryNote struct {
 
 // RoomFilter represents room filtering options
 type RoomFilter struct {
-	RoomType               RoomType `json:"roomType_eq,omitempty"`
-	RoomPrivacy            string   `json:"roomPrivacy_eq,omitempty"`
-	Subscribed             bool     `json:"subscribed_eq,omitempty"`
-	ActiveSince            string   `json:"activeSince_eq,omitempty"`
-	IncludeFavorites       bool     `json:"includeFavorites_eq,omitempty"`
-	IncludeUnreadIfActive  bool     `json:"includeUnreadIfActive_eq,omitempty"`
-	UnreadRoomsOnly        bool     `json:"unreadRoomsOnly_eq,omitempty"`
-	IncludeHidden          bool     `json:"includeHidden_eq,omitempty"`
-	ObjectReferenceID      string   `json:"objectReferenceId_eq,omitempty"`
-	RoomCategory           string   `json:"roomCategory_eq,omitempty"`
+	RoomType              RoomType `json:"roomType_eq,omitempty"`
+	RoomPrivacy           string   `json:"roomPrivacy_eq,omitempty"`
+	Subscribed            bool     `json:"subscribed_eq,omitempty"`
+	ActiveSince           string   `json:"activeSince_eq,omitempty"`
+	IncludeFavorites      bool     `json:"includeFavorites_eq,omitempty"`
+	IncludeUnreadIfActive bool     `json:"includeUnreadIfActive_eq,omitempty"`
+	UnreadRoomsOnly       bool     `json:"unreadRoomsOnly_eq,omitempty"`
+	IncludeHidden         bool     `json:"includeHidden_eq,omitempty"`
+	ObjectReferenceID     string   `json:"objectReferenceId_eq,omitempty"`
+	RoomCategory          string   `json:"roomCategory_eq,omitempty"`
 }
 
 // RoomList represents a paginated list of rooms
 type RoomList struct {
-	TotalCount int        `json:"totalCount"`
-	PageInfo   PageInfo   `json:"pageInfo"`
-	Edges      []RoomEdge `json:"edges"`
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+	Edges      []RoomEdge      `json:"edges"`
 }
 
 // RoomEdge represents a room edge in pagination
@@ -102,7 +111,7 @@ type RoomEdge struct {
 }
 
 // ListRooms returns a list of rooms
-func (s *MessagesService) ListRooms(ctx context.Context, filter *RoomFilter, pagination *PaginationInput, sortOrder SortOrder) (*RoomList, error) {
+func (s *MessagesService) ListRooms(ctx context.Context, filter *RoomFilter, pagination *models.PaginationInput, sortOrder models.SortOrder) (*RoomList, error) {
 	query := `
 		query ListRooms($filter: RoomFilter, $pagination: Pagination, $sortOrder: SortOrder) {
 			roomList(filter: $filter, pagination: $pagination, sortOrder: $sortOrder) {
@@ -145,7 +154,7 @@ func (s *MessagesService) ListRooms(ctx context.Context, filter *RoomFilter, pag
 			}
 		}
 	`
-	
+
 	variables := map[string]interface{}{}
 	if filter != nil {
 		variables["filter"] = filter
@@ -156,20 +165,20 @@ func (s *MessagesService) ListRooms(ctx context.Context, filter *RoomFilter, pag
 	if sortOrder != "" {
 		variables["sortOrder"] = sortOrder
 	}
-	
+
 	req := &GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
-	
+
 	var resp struct {
 		RoomList RoomList `json:"roomList"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.RoomList, nil
 }
 
@@ -215,30 +224,30 @@ func (s *MessagesService) GetRoom(ctx context.Context, roomID string) (*Room, er
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"roomId": roomID,
 		},
 	}
-	
+
 	var resp struct {
 		Room Room `json:"room"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Room, nil
 }
 
 // CreateRoomInput represents input for creating a room
 type CreateRoomInput struct {
-	RoomName string     `json:"roomName"`
-	Topic    string     `json:"topic"`
-	RoomType RoomType   `json:"roomType"`
+	RoomName string          `json:"roomName"`
+	Topic    string          `json:"topic"`
+	RoomType RoomType        `json:"roomType"`
 	Users    []RoomUserInput `json:"users"`
 }
 
@@ -248,8 +257,28 @@ type RoomUserInput struct {
 	OrganizationID string `json:"organizationId"`
 }
 
+// Validate checks the room name and type and that every user has an ID
+func (in CreateRoomInput) Validate() error {
+	if err := firstError(
+		required("roomName", in.RoomName),
+		validateEnum("roomType", in.RoomType, in.RoomType.IsValid()),
+	); err != nil {
+		return err
+	}
+	for i, user := range in.Users {
+		if err := required(fmt.Sprintf("users[%d].userId", i), user.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateRoom creates a new room
 func (s *MessagesService) CreateRoom(ctx context.Context, input CreateRoomInput) (*Room, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation CreateRoom($input: RoomCreateInputV2!) {
 			createRoomV2(input: $input) {
@@ -260,22 +289,22 @@ func (s *MessagesService) CreateRoom(ctx context.Context, input CreateRoomInput)
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		CreateRoomV2 Room `json:"createRoomV2"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.CreateRoomV2, nil
 }
 
@@ -285,8 +314,20 @@ type CreateStoryInput struct {
 	Message string `json:"message"`
 }
 
+// Validate checks that the room and message are set
+func (in CreateStoryInput) Validate() error {
+	return firstError(
+		required("roomId", in.RoomID),
+		required("message", in.Message),
+	)
+}
+
 // SendMessage sends a message to a room
 func (s *MessagesService) SendMessage(ctx context.Context, input CreateStoryInput) (*Story, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation SendMessage($input: RoomStoryCreateInputV2!) {
 			createRoomStoryV2(input: $input) {
@@ -301,27 +342,34 @@ func (s *MessagesService) SendMessage(ctx context.Context, input CreateStoryInpu
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		CreateRoomStoryV2 Story `json:"createRoomStoryV2"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.CreateRoomStoryV2, nil
 }
 
 // GetRoomStories returns stories/messages from a room
-func (s *MessagesService) GetRoomStories(ctx context.Context, roomID string, pagination *PaginationInput) ([]Story, error) {
+func (s *MessagesService) GetRoomStories(ctx context.Context, roomID string, pagination *models.PaginationInput) ([]Story, error) {
+	stories, _, err := s.roomStoriesPage(ctx, roomID, pagination)
+	return stories, err
+}
+
+// roomStoriesPage returns a page of stories from a room along with its
+// page info
+func (s *MessagesService) roomStoriesPage(ctx context.Context, roomID string, pagination *models.PaginationInput) ([]Story, models.PageInfo, error) {
 	query := `
 		query GetRoomStories($roomId: ID!, $pagination: Pagination) {
 			roomStories(filter: {roomId_eq: $roomId}, pagination: $pagination) {
@@ -338,78 +386,114 @@ func (s *MessagesService) GetRoomStories(ctx context.Context, roomID string, pag
 						}
 					}
 				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
 			}
 		}
 	`
-	
+
 	variables := map[string]interface{}{
 		"roomId": roomID,
 	}
 	if pagination != nil {
 		variables["pagination"] = pagination
 	}
-	
+
 	req := &GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
-	
+
 	var resp struct {
 		RoomStories struct {
 			TotalCount int `json:"totalCount"`
 			Edges      []struct {
 				Node Story `json:"node"`
 			} `json:"edges"`
+			PageInfo models.PageInfo `json:"pageInfo"`
 		} `json:"roomStories"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
-		return nil, err
+		return nil, models.PageInfo{}, err
 	}
-	
+
 	stories := make([]Story, 0, len(resp.RoomStories.Edges))
 	for _, edge := range resp.RoomStories.Edges {
 		stories = append(stories, edge.Node)
 	}
-	
-	return stories, nil
+
+	return stories, resp.RoomStories.PageInfo, nil
 }
 
-// UpdateRoomInput represents input for updating a room
+// UpdateRoomInput represents changes to a room. Nil fields are left
+// unchanged and are not sent.
 type UpdateRoomInput struct {
-	RoomID string `json:"roomId"`
-	Topic  string `json:"topic,omitempty"`
-	Name   string `json:"name,omitempty"`
+	RoomID string  `json:"roomId"`
+	Topic  *string `json:"topic,omitempty"`
+	Name   *string `json:"roomName,omitempty"`
+}
+
+// SetTopic changes the topic. An empty topic clears it.
+func (in *UpdateRoomInput) SetTopic(topic string) *UpdateRoomInput {
+	in.Topic = &topic
+	return in
+}
+
+// SetName renames the room
+func (in *UpdateRoomInput) SetName(name string) *UpdateRoomInput {
+	in.Name = &name
+	return in
+}
+
+// Validate checks that the room is set, that something is being changed
+// and that any new name is not blank
+func (in UpdateRoomInput) Validate() error {
+	if err := required("roomId", in.RoomID); err != nil {
+		return err
+	}
+	if in.Topic == nil && in.Name == nil {
+		return &errors.ValidationError{Field: "input", Message: "at least one change is required"}
+	}
+	if in.Name != nil {
+		return required("roomName", *in.Name)
+	}
+	return nil
 }
 
 // UpdateRoom updates room settings
 func (s *MessagesService) UpdateRoom(ctx context.Context, input UpdateRoomInput) (*Room, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
-		mutation UpdateRoom($roomId: ID!, $topic: String!) {
-			updateRoom(input: {roomId: $roomId, topic: $topic}) {
+		mutation UpdateRoom($input: UpdateRoomInput!) {
+			updateRoom(input: $input) {
 				id
 				roomName
 				topic
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
-			"roomId": input.RoomID,
-			"topic":  input.Topic,
+			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		UpdateRoom Room `json:"updateRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.UpdateRoom, nil
 }
 
@@ -423,22 +507,22 @@ func (s *MessagesService) ArchiveRoom(ctx context.Context, roomID string) (*Room
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"roomId": roomID,
 		},
 	}
-	
+
 	var resp struct {
 		ArchiveRoom Room `json:"archiveRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ArchiveRoom, nil
 }
 
@@ -452,51 +536,37 @@ func (s *MessagesService) GetRoomByOfferID(ctx context.Context, offerID string)
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"offerId": offerID,
 		},
 	}
-	
+
 	var resp struct {
 		OfferRoom Room `json:"offerRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.OfferRoom, nil
 }
 
 // GetRoomByContractID returns a room associated with a contract
 func (s *MessagesService) GetRoomByContractID(ctx context.Context, contractID string) (*Room, error) {
-	query := `
-		query GetContractRoom($contractId: ID!) {
-			contractRoom(id: $contractId) {
-				id
-				roomName
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"contractId": contractID,
-		},
-	}
-	
+	req := contractRoomRequest(contractID)
+
 	var resp struct {
 		ContractRoom Room `json:"contractRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ContractRoom, nil
 }
 
@@ -510,22 +580,22 @@ func (s *MessagesService) GetRoomByProposalID(ctx context.Context, proposalID st
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"vendorProposalId": proposalID,
 		},
 	}
-	
+
 	var resp struct {
 		ProposalRoom Room `json:"proposalRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ProposalRoom, nil
 }
 
@@ -538,7 +608,7 @@ func (s *MessagesService) AddUserToRoom(ctx context.Context, roomID string, user
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -546,21 +616,21 @@ func (s *MessagesService) AddUserToRoom(ctx context.Context, roomID string, user
 			"userId": userID,
 		},
 	}
-	
+
 	var resp struct {
 		AddUserToRoom struct {
 			Success bool `json:"success"`
 		} `json:"addUserToRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.AddUserToRoom.Success {
 		return fmt.Errorf("failed to add user to room")
 	}
-	
+
 	return nil
 }
 
@@ -573,7 +643,7 @@ func (s *MessagesService) RemoveUserFromRoom(ctx context.Context, roomID string,
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -581,20 +651,20 @@ func (s *MessagesService) RemoveUserFromRoom(ctx context.Context, roomID string,
 			"userId": userID,
 		},
 	}
-	
+
 	var resp struct {
 		RemoveUserFromRoom struct {
 			Success bool `json:"success"`
 		} `json:"removeUserFromRoom"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.RemoveUserFromRoom.Success {
 		return fmt.Errorf("failed to remove user from room")
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// PresenceStatus represents whether a user is online
+type PresenceStatus string
+
+const (
+	PresenceStatusOnline  PresenceStatus = "ONLINE"
+	PresenceStatusAway    PresenceStatus = "AWAY"
+	PresenceStatusOffline PresenceStatus = "OFFLINE"
+	PresenceStatusUnknown PresenceStatus = "UNKNOWN"
+)
+
+// UserPresence represents the presence of a room participant
+type UserPresence struct {
+	User   models.User    `json:"user"`
+	Status PresenceStatus `json:"status"`
+	// LastSeenDateTime is when the user was last active, nil while online
+	LastSeenDateTime *models.DateTime `json:"lastSeenDateTime"`
+}
+
+// GetRoomPresence returns the presence of each participant in a room
+func (s *MessagesService) GetRoomPresence(ctx context.Context, roomID string) ([]UserPresence, error) {
+	if err := required("roomId", roomID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetRoomPresence($roomId: ID!) {
+			roomPresence(roomId: $roomId) {
+				user {
+					id
+					name
+				}
+				status
+				lastSeenDateTime
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"roomId": roomID,
+		},
+	}
+
+	var resp struct {
+		RoomPresence []UserPresence `json:"roomPresence"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.RoomPresence, nil
+}
+
+// SendTypingIndicator tells the other participants of a room that the user
+// is typing. The indicator expires after a few seconds, so send it again
+// while the user keeps typing.
+func (s *MessagesService) SendTypingIndicator(ctx context.Context, roomID string) error {
+	if err := required("roomId", roomID); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation SendTypingIndicator($roomId: ID!) {
+			sendRoomTypingIndicator(roomId: $roomId) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"roomId": roomID,
+		},
+	}
+
+	var resp struct {
+		SendRoomTypingIndicator struct {
+			Success bool `json:"success"`
+		} `json:"sendRoomTypingIndicator"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.SendRoomTypingIndicator.Success {
+		return fmt.Errorf("failed to send typing indicator")
+	}
+
+	return nil
+}
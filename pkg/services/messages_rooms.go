@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// roomsPageSize is the default page size used when listing every matching
+// room
+const roomsPageSize = 100
+
+// contractRoomRequest returns the request for the room of a contract
+func contractRoomRequest(contractID string) *GraphQLRequest {
+	return &GraphQLRequest{
+		Query: `
+			query GetContractRoom($contractId: ID!) {
+				contractRoom(id: $contractId) {
+					id
+					roomName
+					roomType
+					topic
+					numUnread
+				}
+			}
+		`,
+		Variables: map[string]interface{}{
+			"contractId": contractID,
+		},
+	}
+}
+
+// ListUnreadRooms returns every room with unread messages, most recently
+// active first, up to the client's MaxItems
+func (s *MessagesService) ListUnreadRooms(ctx context.Context) ([]Room, error) {
+	filter := &RoomFilter{UnreadRoomsOnly: true}
+	size := s.client.pageSize(ctx, roomsPageSize)
+	pagination := &models.PaginationInput{First: size}
+	limit := s.client.maxItems(ctx)
+
+	var rooms []Room
+	for {
+		page, err := s.ListRooms(ctx, filter, pagination, models.SortOrderDesc)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range page.Edges {
+			rooms = append(rooms, edge.Node)
+		}
+
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		next := page.PageInfo.EndCursor
+		if !page.PageInfo.HasNextPage || next == "" || next == pagination.After || (limit > 0 && len(rooms) >= limit) {
+			return capItems(rooms, limit), nil
+		}
+		pagination = &models.PaginationInput{First: size, After: next}
+	}
+}
+
+// FindRoomsForContracts returns the rooms of contractIDs keyed by contract
+// ID, looking up all uncached contracts in a single batch request. A
+// contract's room never changes, so rooms are cached for the life of the
+// service. Contracts without a room are left out of the map. If some
+// lookups fail, the rooms that were found are returned with an
+// *errors.MultiError whose indexes refer to contractIDs.
+func (s *MessagesService) FindRoomsForContracts(ctx context.Context, contractIDs []string) (map[string]Room, error) {
+	rooms := make(map[string]Room, len(contractIDs))
+
+	// Index of the first occurrence of each contract that needs a lookup
+	var missing []int
+	seen := make(map[string]bool, len(contractIDs))
+	s.contractRoomsMu.Lock()
+	for i, id := range contractIDs {
+		if room, ok := s.contractRooms[id]; ok {
+			rooms[id] = room
+		} else if !seen[id] {
+			missing = append(missing, i)
+		}
+		seen[id] = true
+	}
+	s.contractRoomsMu.Unlock()
+
+	if len(missing) == 0 {
+		return rooms, nil
+	}
+
+	requests := make([]*GraphQLRequest, len(missing))
+	results := make([]interface{}, len(missing))
+	responses := make([]struct {
+		ContractRoom *Room `json:"contractRoom"`
+	}, len(missing))
+	for i, index := range missing {
+		requests[i] = contractRoomRequest(contractIDs[index])
+		results[i] = &responses[i]
+	}
+
+	batch, err := s.client.DoBatch(ctx, requests, results)
+	if batch == nil {
+		return nil, err
+	}
+
+	var errs []*errors.IndexedError
+	s.contractRoomsMu.Lock()
+	defer s.contractRoomsMu.Unlock()
+	for i, index := range missing {
+		id := contractIDs[index]
+		if !batch.Succeeded(i) {
+			errs = append(errs, &errors.IndexedError{Index: index, Err: batch.Errors[i]})
+			continue
+		}
+		room := responses[i].ContractRoom
+		if room == nil || room.ID == "" {
+			continue
+		}
+		if s.contractRooms == nil {
+			s.contractRooms = make(map[string]Room)
+		}
+		s.contractRooms[id] = *room
+		rooms[id] = *room
+	}
+
+	if len(errs) > 0 {
+		return rooms, &errors.MultiError{Errors: errs}
+	}
+	return rooms, nil
+}
+
+// StorySearchFilter narrows a story search
+type StorySearchFilter struct {
+	RoomIDs []string `json:"roomIds_any,omitempty"`
+	UserIDs []string `json:"userIds_any,omitempty"`
+	// CreatedFrom and CreatedTo bound the story's creation time as
+	// YYYY-MM-DD or RFC 3339
+	CreatedFrom string                  `json:"createdDateTimeFrom_gte,omitempty"`
+	CreatedTo   string                  `json:"createdDateTimeTo_lte,omitempty"`
+	Pagination  *models.PaginationInput `json:"pagination_eq,omitempty"`
+}
+
+// Validate checks the creation time bounds
+func (f StorySearchFilter) Validate() error {
+	return firstError(
+		validateDate("createdDateTimeFrom", f.CreatedFrom),
+		validateDate("createdDateTimeTo", f.CreatedTo),
+	)
+}
+
+// StorySearchResult represents a page of story search results
+type StorySearchResult struct {
+	TotalCount int               `json:"totalCount"`
+	PageInfo   models.PageInfo   `json:"pageInfo"`
+	Edges      []StorySearchEdge `json:"edges"`
+}
+
+// StorySearchEdge represents a story search result edge
+type StorySearchEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   StorySearchHit `json:"node"`
+}
+
+// StorySearchHit is a story matching a search and where it matched
+type StorySearchHit struct {
+	RoomID     models.ID        `json:"roomId"`
+	Story      Story            `json:"story"`
+	Highlights []StoryHighlight `json:"highlights"`
+}
+
+// StoryHighlight is a fragment of a story's message around a match
+type StoryHighlight struct {
+	Fragment string `json:"fragment"`
+	// Matches are the byte ranges of the matched text within Fragment
+	Matches []HighlightRange `json:"matches"`
+}
+
+// HighlightRange is the byte range [Start, End) of a match
+type HighlightRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Hits returns the search hits on this page
+func (r *StorySearchResult) Hits() []StorySearchHit {
+	hits := make([]StorySearchHit, 0, len(r.Edges))
+	for _, edge := range r.Edges {
+		hits = append(hits, edge.Node)
+	}
+	return hits
+}
+
+// SearchStories searches the text of messages in every room the user can
+// see, or those in filter.RoomIDs, without downloading room histories
+func (s *MessagesService) SearchStories(ctx context.Context, query string, filter *StorySearchFilter) (*StorySearchResult, error) {
+	if err := required("query", query); err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	gql := `
+		query SearchStories($query: String!, $filter: RoomStorySearchFilter) {
+			searchRoomStories(searchQuery: $query, filter: $filter) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				edges {
+					cursor
+					node {
+						roomId
+						story {
+							id
+							message
+							createdDateTime
+							updatedDateTime
+							user {
+								id
+								name
+							}
+						}
+						highlights {
+							fragment
+							matches {
+								start
+								end
+							}
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"query": query,
+	}
+	if filter != nil {
+		variables["filter"] = filter
+	}
+
+	req := &GraphQLRequest{
+		Query:     gql,
+		Variables: variables,
+	}
+
+	var resp struct {
+		SearchRoomStories StorySearchResult `json:"searchRoomStories"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.SearchRoomStories, nil
+}
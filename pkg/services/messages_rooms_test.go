@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestFindRoomsForContractsPartialFailure(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`[
+			{"data": {"contractRoom": {"id": "room-1"}}},
+			{"errors": [{"message": "not allowed"}]},
+			{"data": {"contractRoom": null}}
+		]`))
+	}))
+	defer server.Close()
+
+	svc := NewMessagesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	rooms, err := svc.FindRoomsForContracts(context.Background(), []string{"contract-1", "contract-1", "contract-2", "contract-3"})
+	assert.Equal(t, map[string]Room{"contract-1": {ID: "room-1"}}, rooms)
+
+	var multi *errors.MultiError
+	require.True(t, stderrors.As(err, &multi))
+	require.Len(t, multi.Errors, 1)
+	assert.Equal(t, 2, multi.Errors[0].Index, "indexes refer to the contract IDs passed in")
+
+	rooms, err = svc.FindRoomsForContracts(context.Background(), []string{"contract-1"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("room-1"), rooms["contract-1"].ID)
+	assert.Equal(t, 1, calls)
+}
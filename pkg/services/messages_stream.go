@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+const (
+	// DefaultStreamPollInterval is the default interval between room polls
+	DefaultStreamPollInterval = 5 * time.Second
+
+	// DefaultStreamMaxBackoff is the default upper bound for reconnect backoff
+	DefaultStreamMaxBackoff = 2 * time.Minute
+
+	// DefaultStreamPageSize is the number of recent stories fetched per poll
+	DefaultStreamPageSize = 50
+)
+
+// StreamOptions configures StreamStories and NotificationsService.Watch
+type StreamOptions struct {
+	// PollInterval is the delay between polls while the stream is healthy
+	PollInterval time.Duration
+
+	// MaxBackoff caps the delay between retries after failed polls
+	MaxBackoff time.Duration
+
+	// PageSize is the number of recent stories requested per page
+	PageSize int
+
+	// BufferSize is the capacity of the returned channel
+	BufferSize int
+
+	// OnError, if set, is called with every poll error before backing off
+	OnError func(error)
+}
+
+// StreamStories streams new stories posted to a room. Stories that already
+// exist when the stream starts are not emitted. The returned channel is
+// closed when ctx is cancelled or the client is closed.
+func (s *MessagesService) StreamStories(ctx context.Context, roomID string) (<-chan Story, error) {
+	return s.StreamStoriesWithOptions(ctx, roomID, StreamOptions{})
+}
+
+// StreamStoriesWithOptions streams new stories posted to a room using the
+// given options. New stories are emitted oldest first. Transient failures
+// are retried with exponential backoff.
+func (s *MessagesService) StreamStoriesWithOptions(ctx context.Context, roomID string, opts StreamOptions) (<-chan Story, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultStreamPollInterval
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultStreamMaxBackoff
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultStreamPageSize
+	}
+
+	// Establish the baseline so only new stories are emitted
+	initial, err := s.GetRoomStories(ctx, roomID, &models.PaginationInput{First: opts.PageSize})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[models.ID]struct{}, len(initial))
+	for _, story := range initial {
+		seen[story.ID] = struct{}{}
+	}
+
+	out := make(chan Story, opts.BufferSize)
+	ctx, cancel := s.client.workerContext(ctx)
+
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		delay := opts.PollInterval
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			stories, current, err := s.newStories(ctx, roomID, opts.PageSize, seen)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+
+				// Back off exponentially until the room is reachable again
+				delay *= 2
+				if delay > opts.MaxBackoff {
+					delay = opts.MaxBackoff
+				}
+				timer.Reset(delay)
+				continue
+			}
+			delay = opts.PollInterval
+
+			for _, story := range stories {
+				select {
+				case out <- story:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Only remember the stories of this poll to keep memory bounded
+			seen = current
+			timer.Reset(delay)
+		}
+	}()
+
+	return out, nil
+}
+
+// newStories pages back from the most recent story until it reaches one in
+// seen, so bursts larger than a page are not lost. It returns the unseen
+// stories oldest first and the IDs of every story it fetched.
+func (s *MessagesService) newStories(ctx context.Context, roomID string, pageSize int, seen map[models.ID]struct{}) ([]Story, map[models.ID]struct{}, error) {
+	var fresh []Story
+	window := make(map[models.ID]struct{}, pageSize)
+	pagination := &models.PaginationInput{First: pageSize}
+
+	for {
+		page, pageInfo, err := s.roomStoriesPage(ctx, roomID, pagination)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reached := false
+		for _, story := range page {
+			window[story.ID] = struct{}{}
+			if _, ok := seen[story.ID]; ok {
+				reached = true
+				continue
+			}
+			fresh = append(fresh, story)
+		}
+
+		if reached || !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
+		}
+		pagination = &models.PaginationInput{First: pageSize, After: pageInfo.EndCursor}
+	}
+
+	// Pages are newest first
+	for i, j := 0, len(fresh)-1; i < j; i, j = i+1, j-1 {
+		fresh[i], fresh[j] = fresh[j], fresh[i]
+	}
+
+	return fresh, window, nil
+}
+
+// SubscribeStories streams stories posted to a room over a GraphQL
+// subscription instead of polling. Only BufferSize and OnError of opts are
+// used. It fails with errors.ErrNoSubscriptions if the client has no
+// subscription transport.
+func (s *MessagesService) SubscribeStories(ctx context.Context, roomID string, opts StreamOptions) (<-chan Story, error) {
+	if err := required("roomId", roomID); err != nil {
+		return nil, err
+	}
+
+	subscription := `
+		subscription RoomStoryCreated($roomId: ID!) {
+			roomStoryCreated(roomId: $roomId) {
+				id
+				message
+				createdDateTime
+				updatedDateTime
+				user {
+					id
+					name
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: subscription,
+		Variables: map[string]interface{}{
+			"roomId": roomID,
+		},
+	}
+
+	events, err := subscribe[struct {
+		RoomStoryCreated Story `json:"roomStoryCreated"`
+	}](ctx, s.client, req, 0, opts.OnError)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Story, opts.BufferSize)
+	go func() {
+		defer close(out)
+		for event := range events {
+			select {
+			case out <- event.RoomStoryCreated:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// storyRoom is a fake room serving roomStories newest first, one page per
+// request
+type storyRoom struct {
+	mu       sync.Mutex
+	stories  []string // oldest first
+	failures int      // number of polls after the baseline request to fail
+	polls    []time.Time
+	pages    int // requests for pages after the first
+}
+
+func (r *storyRoom) post(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stories = append(r.stories, ids...)
+}
+
+func (r *storyRoom) pollTimes() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.polls...)
+}
+
+func (r *storyRoom) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var gqlReq struct {
+		Variables struct {
+			Pagination models.PaginationInput `json:"pagination"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&gqlReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pagination := gqlReq.Variables.Pagination
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pagination.After != "" {
+		r.pages++
+	} else {
+		r.polls = append(r.polls, time.Now())
+		if len(r.polls) > 1 && r.failures > 0 {
+			r.failures--
+			fmt.Fprint(w, `{"errors":[{"message":"room unavailable"}]}`)
+			return
+		}
+	}
+
+	// Cursors are offsets from the newest story
+	start := 0
+	if pagination.After != "" {
+		fmt.Sscanf(pagination.After, "%d", &start)
+		start++
+	}
+	end := min(start+pagination.First, len(r.stories))
+
+	var edges []string
+	for i := start; i < end; i++ {
+		id := r.stories[len(r.stories)-1-i]
+		edges = append(edges, fmt.Sprintf(`{"node":{"id":%q,"message":"msg %s"}}`, id, id))
+	}
+	fmt.Fprintf(w, `{"data":{"roomStories":{"totalCount":%d,"edges":[%s],"pageInfo":{"hasNextPage":%t,"endCursor":"%d"}}}}`,
+		len(r.stories), strings.Join(edges, ","), end < len(r.stories), end-1)
+}
+
+func newStoryRoomService(t *testing.T, room *storyRoom) *MessagesService {
+	server := httptest.NewServer(room)
+	t.Cleanup(server.Close)
+
+	return NewMessagesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+}
+
+// receiveStories reads n stories from stories, failing after a timeout
+func receiveStories(t *testing.T, stories <-chan Story, n int) []models.ID {
+	t.Helper()
+
+	var ids []models.ID
+	for len(ids) < n {
+		select {
+		case story, ok := <-stories:
+			require.True(t, ok, "stream closed after %v", ids)
+			ids = append(ids, story.ID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %v", ids)
+		}
+	}
+	return ids
+}
+
+func TestStreamStories(t *testing.T) {
+	room := &storyRoom{stories: []string{"s-1", "s-2"}}
+	svc := newStoryRoomService(t, room)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stories, err := svc.StreamStoriesWithOptions(ctx, "room-1", StreamOptions{PollInterval: 5 * time.Millisecond})
+	require.NoError(t, err)
+
+	// Stories present at the start are not emitted; new ones are emitted
+	// once each, oldest first, however many polls see them
+	room.post("s-3")
+	assert.Equal(t, []models.ID{"s-3"}, receiveStories(t, stories, 1))
+
+	room.post("s-4", "s-5")
+	assert.Equal(t, []models.ID{"s-4", "s-5"}, receiveStories(t, stories, 2))
+
+	polls := len(room.pollTimes())
+	require.Eventually(t, func() bool { return len(room.pollTimes()) >= polls+3 }, 2*time.Second, time.Millisecond)
+	select {
+	case story := <-stories:
+		t.Fatalf("story %s emitted twice", story.ID)
+	default:
+	}
+}
+
+func TestStreamStoriesPagesBackToLastSeen(t *testing.T) {
+	room := &storyRoom{stories: []string{"s-1"}}
+	svc := newStoryRoomService(t, room)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stories, err := svc.StreamStoriesWithOptions(ctx, "room-1", StreamOptions{PollInterval: 5 * time.Millisecond, PageSize: 2})
+	require.NoError(t, err)
+
+	// More stories than fit in a page arrive between two polls
+	room.post("s-2", "s-3", "s-4", "s-5", "s-6")
+	assert.Equal(t, []models.ID{"s-2", "s-3", "s-4", "s-5", "s-6"}, receiveStories(t, stories, 5))
+
+	room.mu.Lock()
+	assert.Equal(t, 2, room.pages)
+	room.mu.Unlock()
+}
+
+func TestStreamStoriesBacksOffOnErrors(t *testing.T) {
+	room := &storyRoom{stories: []string{"s-1"}, failures: 2}
+	svc := newStoryRoomService(t, room)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const interval = 10 * time.Millisecond
+	var mu sync.Mutex
+	var errs []error
+
+	stories, err := svc.StreamStoriesWithOptions(ctx, "room-1", StreamOptions{
+		PollInterval: interval,
+		MaxBackoff:   time.Second,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	require.NoError(t, err)
+
+	// The stream reconnects once the room is reachable again
+	room.post("s-2")
+	assert.Equal(t, []models.ID{"s-2"}, receiveStories(t, stories, 1))
+
+	mu.Lock()
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "room unavailable")
+	mu.Unlock()
+
+	// The baseline request, two failed polls and the recovering poll, each
+	// failure doubling the delay
+	polls := room.pollTimes()
+	require.GreaterOrEqual(t, len(polls), 4)
+	assert.GreaterOrEqual(t, polls[2].Sub(polls[1]), 2*interval)
+	assert.GreaterOrEqual(t, polls[3].Sub(polls[2]), 4*interval)
+}
+
+func TestStreamStoriesClosesOnCancel(t *testing.T) {
+	room := &storyRoom{}
+	svc := newStoryRoomService(t, room)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stories, err := svc.StreamStoriesWithOptions(ctx, "room-1", StreamOptions{PollInterval: 5 * time.Millisecond})
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case _, ok := <-stories:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after cancel")
+	}
+}
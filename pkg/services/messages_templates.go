@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// MessageTemplate represents a saved reply. Its body may contain
+// placeholders such as {{name}}, which are filled in on the client by
+// Render.
+type MessageTemplate struct {
+	ID               models.ID       `json:"id"`
+	Name             string          `json:"name"`
+	Body             string          `json:"body"`
+	CreatedDateTime  models.DateTime `json:"createdDateTime"`
+	ModifiedDateTime models.DateTime `json:"modifiedDateTime"`
+}
+
+// templatePlaceholder matches a {{variable}} placeholder, allowing spaces
+// inside the braces
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
+
+// Variables returns the names of the template's placeholders, sorted and
+// without duplicates
+func (t MessageTemplate) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(t.Body, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render replaces the template's placeholders with vars. Every placeholder
+// must have a value; unused vars are ignored.
+func (t MessageTemplate) Render(vars map[string]string) (string, error) {
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			return "", &errors.ValidationError{Field: "vars." + name, Message: "is required by the template"}
+		}
+	}
+
+	return templatePlaceholder.ReplaceAllStringFunc(t.Body, func(p string) string {
+		return vars[templatePlaceholder.FindStringSubmatch(p)[1]]
+	}), nil
+}
+
+// CreateMessageTemplateInput represents input for saving a reply template
+type CreateMessageTemplateInput struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// Validate checks that the name and body are set and that the body's
+// placeholders are well formed
+func (in CreateMessageTemplateInput) Validate() error {
+	if err := firstError(
+		required("name", in.Name),
+		required("body", in.Body),
+	); err != nil {
+		return err
+	}
+
+	// Anything left between braces once valid placeholders are removed is
+	// a malformed placeholder, e.g. {{first name}}
+	rest := templatePlaceholder.ReplaceAllString(in.Body, "")
+	if start := strings.Index(rest, "{{"); start >= 0 && strings.Contains(rest[start:], "}}") {
+		return &errors.ValidationError{Field: "body", Message: "has a malformed placeholder", Value: rest[start:]}
+	}
+	return nil
+}
+
+// messageTemplateFields selects the fields of a MessageTemplate
+const messageTemplateFields = `
+	id
+	name
+	body
+	createdDateTime
+	modifiedDateTime
+`
+
+// CreateMessageTemplate saves a reply template
+func (s *MessagesService) CreateMessageTemplate(ctx context.Context, input CreateMessageTemplateInput) (*MessageTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation CreateMessageTemplate($input: MessageTemplateCreateInput!) {
+			createMessageTemplate(input: $input) {` + messageTemplateFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		CreateMessageTemplate MessageTemplate `json:"createMessageTemplate"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CreateMessageTemplate, nil
+}
+
+// ListMessageTemplates returns the user's reply templates
+func (s *MessagesService) ListMessageTemplates(ctx context.Context) ([]MessageTemplate, error) {
+	query := `
+		query ListMessageTemplates {
+			messageTemplates {` + messageTemplateFields + `}
+		}
+	`
+
+	var resp struct {
+		MessageTemplates []MessageTemplate `json:"messageTemplates"`
+	}
+
+	if err := s.client.Do(ctx, &GraphQLRequest{Query: query}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.MessageTemplates, nil
+}
+
+// GetMessageTemplate returns a reply template by ID
+func (s *MessagesService) GetMessageTemplate(ctx context.Context, templateID string) (*MessageTemplate, error) {
+	if err := required("templateId", templateID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetMessageTemplate($id: ID!) {
+			messageTemplate(id: $id) {` + messageTemplateFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": templateID,
+		},
+	}
+
+	var resp struct {
+		MessageTemplate *MessageTemplate `json:"messageTemplate"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.MessageTemplate == nil {
+		return nil, fmt.Errorf("%w: message template %s", errors.ErrNotFound, templateID)
+	}
+
+	return resp.MessageTemplate, nil
+}
+
+// DeleteMessageTemplate deletes a reply template
+func (s *MessagesService) DeleteMessageTemplate(ctx context.Context, templateID string) error {
+	if err := required("templateId", templateID); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation DeleteMessageTemplate($id: ID!) {
+			deleteMessageTemplate(id: $id)
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"id": templateID,
+		},
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// SendTemplate renders a reply template with vars and sends it to a room.
+// Nothing is sent if a placeholder has no value.
+func (s *MessagesService) SendTemplate(ctx context.Context, roomID, templateID string, vars map[string]string) (*Story, error) {
+	if err := firstError(
+		required("roomId", roomID),
+		required("templateId", templateID),
+	); err != nil {
+		return nil, err
+	}
+
+	template, err := s.GetMessageTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := template.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SendMessage(ctx, CreateStoryInput{RoomID: roomID, Message: message})
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestMessageTemplateRender(t *testing.T) {
+	tmpl := MessageTemplate{Body: "Hi {{name}}, thanks for applying to {{ job.title }}. {{name}}, are you free?"}
+	assert.Equal(t, []string{"job.title", "name"}, tmpl.Variables())
+
+	msg, err := tmpl.Render(map[string]string{"name": "Ada", "job.title": "Go developer", "unused": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Ada, thanks for applying to Go developer. Ada, are you free?", msg)
+
+	_, err = tmpl.Render(map[string]string{"name": "Ada"})
+	assertValidationField(t, err, "vars.job.title")
+
+	assert.NoError(t, CreateMessageTemplateInput{Name: "plain", Body: "No placeholders {here}"}.Validate())
+	assertValidationField(t, CreateMessageTemplateInput{Name: "bad", Body: "Hi {{first name}}"}.Validate(), "body")
+	assertValidationField(t, CreateMessageTemplateInput{Body: "Hi"}.Validate(), "name")
+}
+
+func TestSendTemplate(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "messageTemplate("):
+			if req.Variables["id"] == "missing" {
+				w.Write([]byte(`{"data":{"messageTemplate":null}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"messageTemplate":{"id":"tmpl-1","name":"Follow up","body":"Hi {{name}}!"}}}`))
+		case strings.Contains(req.Query, "createRoomStoryV2"):
+			input := req.Variables["input"].(map[string]interface{})
+			assert.Equal(t, "room-1", input["roomId"])
+			sent = input["message"].(string)
+			w.Write([]byte(`{"data":{"createRoomStoryV2":{"id":"story-1","message":"Hi Ada!"}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewMessagesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	_, err := svc.SendTemplate(ctx, "room-1", "tmpl-1", nil)
+	assertValidationField(t, err, "vars.name")
+	assert.Empty(t, sent, "nothing is sent when a placeholder is missing")
+
+	story, err := svc.SendTemplate(ctx, "room-1", "tmpl-1", map[string]string{"name": "Ada"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi Ada!", sent)
+	assert.Equal(t, "Hi Ada!", story.Message)
+
+	_, err = svc.SendTemplate(ctx, "room-1", "missing", nil)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
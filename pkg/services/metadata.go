@@ -1,77 +1,127 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/rizome-dev/go-upwork/internal/gen"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // MetadataService handles metadata-related API operations
 type MetadataService struct {
 	client *BaseClient
+	gql    graphql.Client
+
+	// Reason catalogs, which rarely change, cached by GetReasons
+	reasonsMu sync.Mutex
+	reasons   map[reasonsKey][]Reason
+
+	// The category tree, cached by GetCategories
+	categoriesMu sync.Mutex
+	categories   []OntologyCategory
+
+	// Skill IDs resolved by ResolveSkillIDs, keyed by lower-case name
+	skillIDsMu sync.Mutex
+	skillIDs   map[string]models.ID
+
+	// The occupation taxonomy, cached by GetOccupations
+	occupationsMu sync.Mutex
+	occupations   []Occupation
+
+	// Supported currencies, cached by GetCurrencies
+	currenciesMu sync.Mutex
+	currencies   []Currency
+}
+
+// reasonsKey identifies a cached reason catalog
+type reasonsKey struct {
+	reasonType ReasonType
+	all        bool
 }
 
 // NewMetadataService creates a new metadata service
 func NewMetadataService(client *BaseClient) *MetadataService {
-	return &MetadataService{client: client}
+	return &MetadataService{client: client, gql: genqlientClient{client: client}}
 }
 
 // OntologyCategory represents an ontology category
 type OntologyCategory struct {
-	ID             ID                   `json:"id"`
-	PreferredLabel string               `json:"preferredLabel"`
-	AltLabel       string               `json:"altLabel"`
-	Slug           string               `json:"slug"`
-	OntologyID     string               `json:"ontologyId"`
+	ID             models.ID             `json:"id"`
+	PreferredLabel string                `json:"preferredLabel"`
+	AltLabel       string                `json:"altLabel"`
+	Slug           string                `json:"slug"`
+	OntologyID     string                `json:"ontologyId"`
 	Subcategories  []OntologySubcategory `json:"subcategories"`
-	Services       []OntologyService    `json:"services"`
+	Services       []OntologyService     `json:"services"`
 }
 
 // OntologySubcategory represents an ontology subcategory
 type OntologySubcategory struct {
-	ID             ID     `json:"id"`
-	PreferredLabel string `json:"preferredLabel"`
-	AltLabel       string `json:"altLabel"`
-	Slug           string `json:"slug"`
+	ID             models.ID `json:"id"`
+	PreferredLabel string    `json:"preferredLabel"`
+	AltLabel       string    `json:"altLabel"`
+	Slug           string    `json:"slug"`
 }
 
 // OntologyService represents an ontology service
 type OntologyService struct {
-	ID             ID     `json:"id"`
-	PreferredLabel string `json:"preferredLabel"`
+	ID             models.ID `json:"id"`
+	PreferredLabel string    `json:"preferredLabel"`
 }
 
 // OntologySkill represents an ontology skill
 type OntologySkill struct {
-	ID             ID     `json:"id"`
-	PreferredLabel string `json:"preferredLabel"`
+	ID             models.ID `json:"id"`
+	PreferredLabel string    `json:"preferredLabel"`
 }
 
 // Region represents a geographical region
 type Region struct {
-	ID           ID      `json:"id"`
-	Name         string  `json:"name"`
-	ParentRegion *Region `json:"parentRegion"`
+	ID           models.ID `json:"id"`
+	Name         string    `json:"name"`
+	ParentRegion *Region   `json:"parentRegion"`
 }
 
 // Country represents a country
 type Country struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
-	Code string `json:"code"`
+	ID   models.ID `json:"id"`
+	Name string    `json:"name"`
+	Code string    `json:"code"`
 }
 
 // Language represents a language
 type Language struct {
-	ID   ID     `json:"id"`
-	Name string `json:"name"`
-	Code string `json:"code"`
+	ID   models.ID `json:"id"`
+	Name string    `json:"name"`
+	Code string    `json:"code"`
 }
 
 // Reason represents a reason (for various actions)
 type Reason struct {
-	ID     ID     `json:"id"`
-	Reason string `json:"reason"`
-	Alias  string `json:"alias"`
+	ID     models.ID `json:"id"`
+	Reason string    `json:"reason"`
+	Alias  string    `json:"alias"`
+}
+
+// ToCategory converts the ontology category to the canonical model
+func (c OntologyCategory) ToCategory() models.Category {
+	return models.Category{ID: c.ID, Name: c.PreferredLabel}
+}
+
+// ToSubCategory converts the ontology subcategory to the canonical model
+func (c OntologySubcategory) ToSubCategory() models.SubCategory {
+	return models.SubCategory{ID: c.ID, Name: c.PreferredLabel}
+}
+
+// ToSkill converts the ontology skill to the canonical model
+func (s OntologySkill) ToSkill() models.Skill {
+	return models.Skill{ID: s.ID, PrettyName: s.PreferredLabel}
 }
 
 // ReasonType represents the type of reason
@@ -81,224 +131,319 @@ const (
 	ReasonTypeJobPostingClose ReasonType = "JOB_POSTING_CLOSE"
 	ReasonTypeContractEnd     ReasonType = "CONTRACT_END"
 	ReasonTypeProposalDecline ReasonType = "PROPOSAL_DECLINE"
+	ReasonTypeUnknown         ReasonType = "UNKNOWN"
 )
 
-// GetCategories returns all ontology categories
+// GetCategories returns all ontology categories. The tree is fetched once
+// and cached for the life of the service.
 func (s *MetadataService) GetCategories(ctx context.Context) ([]OntologyCategory, error) {
-	query := `
-		query GetOntologyCategories {
-			ontologyCategories {
-				id
-				preferredLabel
-				altLabel
-				slug
-				ontologyId
-				subcategories {
-					id
-					preferredLabel
-					altLabel
-					slug
-				}
-				services {
-					id
-					preferredLabel
-				}
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-	}
-	
-	var resp struct {
-		OntologyCategories []OntologyCategory `json:"ontologyCategories"`
+	s.categoriesMu.Lock()
+	cached := s.categories
+	s.categoriesMu.Unlock()
+	if cached != nil {
+		return append([]OntologyCategory(nil), cached...), nil
 	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+
+	resp, err := gen.GetOntologyCategories(ctx, s.gql)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.OntologyCategories, nil
+
+	categories := make([]OntologyCategory, 0, len(resp.OntologyCategories))
+	for _, c := range resp.OntologyCategories {
+		category := OntologyCategory{
+			ID:             models.ID(c.Id),
+			PreferredLabel: c.PreferredLabel,
+			AltLabel:       c.AltLabel,
+			Slug:           c.Slug,
+			OntologyID:     c.OntologyId,
+		}
+		for _, sub := range c.Subcategories {
+			category.Subcategories = append(category.Subcategories, OntologySubcategory{
+				ID:             models.ID(sub.Id),
+				PreferredLabel: sub.PreferredLabel,
+				AltLabel:       sub.AltLabel,
+				Slug:           sub.Slug,
+			})
+		}
+		for _, svc := range c.Services {
+			category.Services = append(category.Services, OntologyService{
+				ID:             models.ID(svc.Id),
+				PreferredLabel: svc.PreferredLabel,
+			})
+		}
+		categories = append(categories, category)
+	}
+
+	s.categoriesMu.Lock()
+	s.categories = categories
+	s.categoriesMu.Unlock()
+
+	return append([]OntologyCategory(nil), categories...), nil
 }
 
 // GetSkills returns ontology skills with pagination
 func (s *MetadataService) GetSkills(ctx context.Context, limit int, offset int) ([]OntologySkill, error) {
-	query := `
-		query GetOntologySkills($limit: Int!, $offset: Int) {
-			ontologyBrowserSkills(limit: $limit, offset: $offset) {
-				id
-				preferredLabel
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"limit":  limit,
-			"offset": offset,
-		},
-	}
-	
-	var resp struct {
-		OntologyBrowserSkills []OntologySkill `json:"ontologyBrowserSkills"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+	resp, err := gen.GetOntologySkills(ctx, s.gql, limit, offset)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.OntologyBrowserSkills, nil
+
+	skills := make([]OntologySkill, 0, len(resp.OntologyBrowserSkills))
+	for _, skill := range resp.OntologyBrowserSkills {
+		skills = append(skills, OntologySkill{
+			ID:             models.ID(skill.Id),
+			PreferredLabel: skill.PreferredLabel,
+		})
+	}
+
+	return skills, nil
 }
 
 // GetRegions returns all regions
 func (s *MetadataService) GetRegions(ctx context.Context) ([]Region, error) {
-	query := `
-		query GetRegions {
-			regions {
-				id
-				name
-				parentRegion {
-					id
-					name
-				}
+	resp, err := gen.GetRegions(ctx, s.gql)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make([]Region, 0, len(resp.Regions))
+	for _, r := range resp.Regions {
+		region := Region{
+			ID:   models.ID(r.Id),
+			Name: r.Name,
+		}
+		if r.ParentRegion != nil {
+			region.ParentRegion = &Region{
+				ID:   models.ID(r.ParentRegion.Id),
+				Name: r.ParentRegion.Name,
 			}
 		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
+		regions = append(regions, region)
 	}
-	
-	var resp struct {
-		Regions []Region `json:"regions"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
-		return nil, err
-	}
-	
-	return resp.Regions, nil
+
+	return regions, nil
 }
 
 // GetCountries returns all countries
 func (s *MetadataService) GetCountries(ctx context.Context) ([]Country, error) {
-	query := `
-		query GetCountries {
-			countries {
-				id
-				name
-				code
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-	}
-	
-	var resp struct {
-		Countries []Country `json:"countries"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+	resp, err := gen.GetCountries(ctx, s.gql)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.Countries, nil
+
+	countries := make([]Country, 0, len(resp.Countries))
+	for _, c := range resp.Countries {
+		countries = append(countries, Country{
+			ID:   models.ID(c.Id),
+			Name: c.Name,
+			Code: c.Code,
+		})
+	}
+
+	return countries, nil
 }
 
 // GetLanguages returns all languages
 func (s *MetadataService) GetLanguages(ctx context.Context) ([]Language, error) {
-	query := `
-		query GetLanguages {
-			languages {
-				id
-				name
-				code
-			}
+	resp, err := gen.GetLanguages(ctx, s.gql)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]Language, 0, len(resp.Languages))
+	for _, l := range resp.Languages {
+		languages = append(languages, Language{
+			ID:   models.ID(l.Id),
+			Name: l.Name,
+			Code: l.Code,
+		})
+	}
+
+	return languages, nil
+}
+
+// GetReasons returns reasons by type. Each catalog is fetched once and
+// cached for the life of the service.
+func (s *MetadataService) GetReasons(ctx context.Context, reasonType ReasonType, all bool) ([]Reason, error) {
+	key := reasonsKey{reasonType: reasonType, all: all}
+
+	s.reasonsMu.Lock()
+	cached, ok := s.reasons[key]
+	s.reasonsMu.Unlock()
+	if ok {
+		return append([]Reason(nil), cached...), nil
+	}
+
+	resp, err := gen.GetReasons(ctx, s.gql, gen.ReasonType(reasonType), all)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make([]Reason, 0, len(resp.Reasons))
+	for _, r := range resp.Reasons {
+		reasons = append(reasons, Reason{
+			ID:     models.ID(r.Id),
+			Reason: r.Reason,
+			Alias:  r.Alias,
+		})
+	}
+
+	s.reasonsMu.Lock()
+	if s.reasons == nil {
+		s.reasons = make(map[reasonsKey][]Reason)
+	}
+	s.reasons[key] = reasons
+	s.reasonsMu.Unlock()
+
+	return append([]Reason(nil), reasons...), nil
+}
+
+// FindReason returns the reason of reasonType whose alias or ID is
+// aliasOrID. Aliases are matched ignoring case.
+func (s *MetadataService) FindReason(ctx context.Context, reasonType ReasonType, aliasOrID string) (*Reason, error) {
+	reasons, err := s.GetReasons(ctx, reasonType, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range reasons {
+		if string(r.ID) == aliasOrID || (r.Alias != "" && strings.EqualFold(r.Alias, aliasOrID)) {
+			return &r, nil
 		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
 	}
-	
-	var resp struct {
-		Languages []Language `json:"languages"`
+
+	return nil, &errors.ValidationError{
+		Field:   "reason",
+		Message: fmt.Sprintf("is not a known %s reason", reasonType),
+		Value:   aliasOrID,
 	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+}
+
+// FindCategory returns the category whose ID, ontology ID, slug or label is
+// key. Slugs and labels are matched ignoring case.
+func (s *MetadataService) FindCategory(ctx context.Context, key string) (*OntologyCategory, error) {
+	categories, err := s.GetCategories(ctx)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.Languages, nil
+
+	for _, c := range categories {
+		if string(c.ID) == key || (c.OntologyID != "" && c.OntologyID == key) || matchesLabel(key, c.Slug, c.PreferredLabel) {
+			return &c, nil
+		}
+	}
+
+	return nil, &errors.ValidationError{Field: "category", Message: "is not a known category", Value: key}
 }
 
-// GetReasons returns reasons by type
-func (s *MetadataService) GetReasons(ctx context.Context, reasonType ReasonType, all bool) ([]Reason, error) {
-	query := `
-		query GetReasons($reasonType: ReasonType!, $all: Boolean) {
-			reasons(reasonType: $reasonType, all: $all) {
-				id
-				reason
-				alias
+// FindSubcategory returns the subcategory whose ID, slug or label is key,
+// along with its category. Slugs and labels are matched ignoring case.
+// Labels are not unique across categories, so the first match is
+// returned; use an ID or slug where it matters.
+func (s *MetadataService) FindSubcategory(ctx context.Context, key string) (*OntologyCategory, *OntologySubcategory, error) {
+	categories, err := s.GetCategories(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, c := range categories {
+		for _, sub := range c.Subcategories {
+			if string(sub.ID) == key || matchesLabel(key, sub.Slug, sub.PreferredLabel) {
+				return &c, &sub, nil
 			}
 		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"reasonType": reasonType,
-			"all":        all,
-		},
 	}
-	
-	var resp struct {
-		Reasons []Reason `json:"reasons"`
+
+	return nil, nil, &errors.ValidationError{Field: "subcategory", Message: "is not a known subcategory", Value: key}
+}
+
+// matchesLabel returns true if key is one of the non-empty labels,
+// ignoring case
+func matchesLabel(key string, labels ...string) bool {
+	for _, label := range labels {
+		if label != "" && strings.EqualFold(label, key) {
+			return true
+		}
 	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
-		return nil, err
+	return false
+}
+
+// skillSearchLimit is the number of candidates ResolveSkillIDs considers
+// for each name
+const skillSearchLimit = 20
+
+// ResolveSkillIDs returns the ontology ID of each skill in names, in the
+// same order. A name must match a skill's label exactly, ignoring case.
+// Resolved IDs are cached for the life of the service.
+func (s *MetadataService) ResolveSkillIDs(ctx context.Context, names []string) ([]models.ID, error) {
+	ids := make([]models.ID, len(names))
+	for i, name := range names {
+		field := fmt.Sprintf("names[%d]", i)
+		if err := required(field, name); err != nil {
+			return nil, err
+		}
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+
+		s.skillIDsMu.Lock()
+		id, ok := s.skillIDs[key]
+		s.skillIDsMu.Unlock()
+		if ok {
+			ids[i] = id
+			continue
+		}
+
+		candidates, err := s.SearchSkills(ctx, SearchSkillsInput{Query: name, Limit: skillSearchLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, skill := range candidates {
+			if strings.EqualFold(skill.PreferredLabel, name) {
+				id, ok = skill.ID, true
+				break
+			}
+		}
+		if !ok {
+			return nil, &errors.ValidationError{Field: field, Message: "is not a known skill", Value: name}
+		}
+
+		s.skillIDsMu.Lock()
+		if s.skillIDs == nil {
+			s.skillIDs = make(map[string]models.ID)
+		}
+		s.skillIDs[key] = id
+		s.skillIDsMu.Unlock()
+		ids[i] = id
 	}
-	
-	return resp.Reasons, nil
+
+	return ids, nil
 }
 
 // TimeZone represents a time zone
 type TimeZone struct {
-	ID     ID     `json:"id"`
-	Name   string `json:"name"`
-	Offset int    `json:"offset"`
+	ID     models.ID `json:"id"`
+	Name   string    `json:"name"`
+	Offset int       `json:"offset"`
 }
 
 // GetTimeZones returns all time zones
 func (s *MetadataService) GetTimeZones(ctx context.Context) ([]TimeZone, error) {
-	query := `
-		query GetTimeZones {
-			timeZones {
-				id
-				name
-				offset
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-	}
-	
-	var resp struct {
-		TimeZones []TimeZone `json:"timeZones"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+	resp, err := gen.GetTimeZones(ctx, s.gql)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.TimeZones, nil
+
+	timeZones := make([]TimeZone, 0, len(resp.TimeZones))
+	for _, tz := range resp.TimeZones {
+		timeZones = append(timeZones, TimeZone{
+			ID:     models.ID(tz.Id),
+			Name:   tz.Name,
+			Offset: tz.Offset,
+		})
+	}
+
+	return timeZones, nil
 }
 
 // SearchSkillsInput represents input for searching skills
@@ -307,36 +452,32 @@ type SearchSkillsInput struct {
 	Limit int    `json:"limit"`
 }
 
+// Validate checks that the query is set and the limit is not negative
+func (in SearchSkillsInput) Validate() error {
+	return firstError(
+		required("query", in.Query),
+		validateNonNegative("limit", &in.Limit),
+	)
+}
+
 // SearchSkills searches for skills by query
 func (s *MetadataService) SearchSkills(ctx context.Context, input SearchSkillsInput) ([]OntologySkill, error) {
-	query := `
-		query SearchSkills($query: String!, $limit: Int!) {
-			ontologyElementsSearchByPrefLabel(
-				prefLabel: $query,
-				elementType: "skill",
-				limit: $limit
-			) {
-				id
-				preferredLabel
-			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"query": input.Query,
-			"limit": input.Limit,
-		},
-	}
-	
-	var resp struct {
-		OntologyElementsSearchByPrefLabel []OntologySkill `json:"ontologyElementsSearchByPrefLabel"`
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+
+	resp, err := gen.SearchSkills(ctx, s.gql, input.Query, input.Limit)
+	if err != nil {
 		return nil, err
 	}
-	
-	return resp.OntologyElementsSearchByPrefLabel, nil
-}
\ No newline at end of file
+
+	skills := make([]OntologySkill, 0, len(resp.OntologyElementsSearchByPrefLabel))
+	for _, skill := range resp.OntologyElementsSearchByPrefLabel {
+		skills = append(skills, OntologySkill{
+			ID:             models.ID(skill.Id),
+			PreferredLabel: skill.PreferredLabel,
+		})
+	}
+
+	return skills, nil
+}
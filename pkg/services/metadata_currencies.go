@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// Currency represents a currency supported for payments
+type Currency struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	// Decimals is the number of minor-unit digits, e.g. 2 for USD
+	Decimals int `json:"decimals"`
+}
+
+// ExchangeRates represents exchange rates from a base currency. Rates maps
+// a currency code to the amount of it one unit of Base buys.
+type ExchangeRates struct {
+	Base string `json:"base"`
+	// AsOf is when the rates were published, as an ISO 8601 timestamp
+	AsOf  string             `json:"asOf"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Rate returns the rate converting one unit of from into to. Rates between
+// two currencies other than the base are crossed through it.
+func (r *ExchangeRates) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := r.rate("from", from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.rate("to", to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+// rate returns the rate from the base to currency
+func (r *ExchangeRates) rate(field, currency string) (float64, error) {
+	if strings.EqualFold(currency, r.Base) {
+		return 1, nil
+	}
+	rate, ok := r.Rates[currency]
+	if !ok || !(rate > 0) {
+		return 0, &errors.ValidationError{Field: field, Message: "has no exchange rate from " + r.Base, Value: currency}
+	}
+	return rate, nil
+}
+
+// Convert converts m into the currency to, rounding to its minor unit
+func (r *ExchangeRates) Convert(m models.Money, to string) (models.Money, error) {
+	to = strings.ToUpper(to)
+	if m.Currency == "" {
+		return models.Money{}, &errors.ValidationError{Field: "currency", Message: "is required to convert an amount", Value: m.String()}
+	}
+	if strings.EqualFold(m.Currency, to) {
+		return m, nil
+	}
+
+	rate, err := r.Rate(m.Currency, to)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	minor := m.Float64() * rate * math.Pow10(models.CurrencyDecimals(to))
+	return models.MoneyFromMinorUnits(int64(math.Round(minor)), to), nil
+}
+
+// GetCurrencies returns the currencies supported for payments. The list is
+// fetched once and cached for the life of the service.
+func (s *MetadataService) GetCurrencies(ctx context.Context) ([]Currency, error) {
+	s.currenciesMu.Lock()
+	cached := s.currencies
+	s.currenciesMu.Unlock()
+	if cached != nil {
+		return append([]Currency(nil), cached...), nil
+	}
+
+	query := `
+		query GetCurrencies {
+			currencies {
+				code
+				name
+				symbol
+				decimals
+			}
+		}
+	`
+
+	var resp struct {
+		Currencies []Currency `json:"currencies"`
+	}
+
+	if err := s.client.Do(ctx, &GraphQLRequest{Query: query}, &resp); err != nil {
+		return nil, err
+	}
+
+	currencies := resp.Currencies
+	if currencies == nil {
+		currencies = []Currency{}
+	}
+
+	s.currenciesMu.Lock()
+	s.currencies = currencies
+	s.currenciesMu.Unlock()
+
+	return append([]Currency(nil), currencies...), nil
+}
+
+// GetExchangeRates returns the current exchange rates from base. Rates are
+// not cached; hold on to the result to convert many amounts at one rate.
+func (s *MetadataService) GetExchangeRates(ctx context.Context, base string) (*ExchangeRates, error) {
+	base = strings.ToUpper(base)
+	if !isCurrencyCode(base) {
+		return nil, &errors.ValidationError{Field: "base", Message: "must be a three-letter ISO 4217 currency code", Value: base}
+	}
+
+	query := `
+		query GetExchangeRates($baseCurrency: String!) {
+			exchangeRates(baseCurrency: $baseCurrency) {
+				baseCurrency
+				asOfDateTime
+				rates {
+					currency
+					rate
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"baseCurrency": base,
+		},
+	}
+
+	var resp struct {
+		ExchangeRates struct {
+			BaseCurrency string `json:"baseCurrency"`
+			AsOfDateTime string `json:"asOfDateTime"`
+			Rates        []struct {
+				Currency string  `json:"currency"`
+				Rate     float64 `json:"rate,string"`
+			} `json:"rates"`
+		} `json:"exchangeRates"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	rates := &ExchangeRates{
+		Base:  base,
+		AsOf:  resp.ExchangeRates.AsOfDateTime,
+		Rates: make(map[string]float64, len(resp.ExchangeRates.Rates)),
+	}
+	if resp.ExchangeRates.BaseCurrency != "" {
+		rates.Base = strings.ToUpper(resp.ExchangeRates.BaseCurrency)
+	}
+	for _, r := range resp.ExchangeRates.Rates {
+		rates.Rates[strings.ToUpper(r.Currency)] = r.Rate
+	}
+	return rates, nil
+}
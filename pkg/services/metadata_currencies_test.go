@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestCurrenciesAndExchangeRates(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "currencies"):
+			calls++
+			w.Write([]byte(`{"data":{"currencies":[
+				{"code":"USD","name":"US Dollar","symbol":"$","decimals":2},
+				{"code":"JPY","name":"Japanese Yen","symbol":"¥","decimals":0}
+			]}}`))
+		case strings.Contains(req.Query, "exchangeRates"):
+			assert.Equal(t, "USD", req.Variables["baseCurrency"])
+			w.Write([]byte(`{"data":{"exchangeRates":{
+				"baseCurrency":"USD","asOfDateTime":"2026-10-16T00:00:00Z",
+				"rates":[{"currency":"EUR","rate":"0.9"},{"currency":"jpy","rate":"150"}]
+			}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	currencies, err := svc.GetCurrencies(ctx)
+	require.NoError(t, err)
+	require.Len(t, currencies, 2)
+	assert.Equal(t, Currency{Code: "JPY", Name: "Japanese Yen", Symbol: "¥", Decimals: 0}, currencies[1])
+	_, err = svc.GetCurrencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	_, err = svc.GetExchangeRates(ctx, "dollars")
+	assertValidationField(t, err, "base")
+
+	rates, err := svc.GetExchangeRates(ctx, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rates.Base)
+	assert.Equal(t, "2026-10-16T00:00:00Z", rates.AsOf)
+
+	eur, err := rates.Convert(models.MustMoney("100.00", "USD"), "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "90.00 EUR", eur.String())
+
+	yen, err := rates.Convert(models.MustMoney("90.00", "EUR"), "jpy")
+	require.NoError(t, err)
+	assert.Equal(t, "15000 JPY", yen.String())
+
+	usd, err := rates.Convert(models.MustMoney("1", "JPY"), "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "0.01 USD", usd.String())
+
+	_, err = rates.Convert(models.MustMoney("1", "GBP"), "USD")
+	assertValidationField(t, err, "from")
+	_, err = rates.Convert(models.MustMoney("1", ""), "USD")
+	assertValidationField(t, err, "currency")
+}
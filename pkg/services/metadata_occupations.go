@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// Occupation represents an occupation, the taxonomy that replaces
+// categories and subcategories when posting jobs
+type Occupation struct {
+	ID             models.ID `json:"id"`
+	PreferredLabel string    `json:"preferredLabel"`
+	OntologyID     string    `json:"ontologyId"`
+	// CategoryID and SubcategoryID are the category and subcategory the
+	// occupation corresponds to in the older taxonomy
+	CategoryID    models.ID `json:"categoryId"`
+	SubcategoryID models.ID `json:"subcategoryId"`
+}
+
+// Specialization represents a specialization within an occupation
+type Specialization struct {
+	ID             models.ID `json:"id"`
+	PreferredLabel string    `json:"preferredLabel"`
+	OntologyID     string    `json:"ontologyId"`
+	OccupationID   models.ID `json:"occupationId"`
+}
+
+// GetOccupations returns all occupations. The taxonomy is fetched once and
+// cached for the life of the service.
+func (s *MetadataService) GetOccupations(ctx context.Context) ([]Occupation, error) {
+	s.occupationsMu.Lock()
+	cached := s.occupations
+	s.occupationsMu.Unlock()
+	if cached != nil {
+		return append([]Occupation(nil), cached...), nil
+	}
+
+	query := `
+		query GetOccupations {
+			ontologyOccupations {
+				id
+				preferredLabel
+				ontologyId
+				categoryId
+				subcategoryId
+			}
+		}
+	`
+
+	var resp struct {
+		OntologyOccupations []Occupation `json:"ontologyOccupations"`
+	}
+
+	if err := s.client.Do(ctx, &GraphQLRequest{Query: query}, &resp); err != nil {
+		return nil, err
+	}
+
+	occupations := resp.OntologyOccupations
+	if occupations == nil {
+		occupations = []Occupation{}
+	}
+
+	s.occupationsMu.Lock()
+	s.occupations = occupations
+	s.occupationsMu.Unlock()
+
+	return append([]Occupation(nil), occupations...), nil
+}
+
+// GetSpecializations returns the specializations of an occupation
+func (s *MetadataService) GetSpecializations(ctx context.Context, occupationID string) ([]Specialization, error) {
+	if err := required("occupationId", occupationID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetSpecializations($occupationId: ID!) {
+			ontologySpecializations(occupationId: $occupationId) {
+				id
+				preferredLabel
+				ontologyId
+				occupationId
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"occupationId": occupationID,
+		},
+	}
+
+	var resp struct {
+		OntologySpecializations []Specialization `json:"ontologySpecializations"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.OntologySpecializations, nil
+}
+
+// FindOccupation returns the occupation whose ID, ontology ID or label is
+// key. Labels are matched ignoring case.
+func (s *MetadataService) FindOccupation(ctx context.Context, key string) (*Occupation, error) {
+	occupations, err := s.GetOccupations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range occupations {
+		if string(o.ID) == key || (o.OntologyID != "" && o.OntologyID == key) || matchesLabel(key, o.PreferredLabel) {
+			return &o, nil
+		}
+	}
+
+	return nil, &errors.ValidationError{Field: "occupation", Message: "is not a known occupation", Value: key}
+}
+
+// OccupationForSubcategory maps a subcategory, given by ID, slug or label
+// as for FindSubcategory, to its occupation
+func (s *MetadataService) OccupationForSubcategory(ctx context.Context, subcategory string) (*Occupation, error) {
+	_, sub, err := s.FindSubcategory(ctx, subcategory)
+	if err != nil {
+		return nil, err
+	}
+
+	occupations, err := s.GetOccupations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range occupations {
+		if o.SubcategoryID == sub.ID {
+			return &o, nil
+		}
+	}
+
+	return nil, &errors.ValidationError{Field: "subcategory", Message: "has no matching occupation", Value: subcategory}
+}
+
+// SubcategoryForOccupation maps an occupation, given by ID, ontology ID or
+// label, to the category and subcategory of the older taxonomy
+func (s *MetadataService) SubcategoryForOccupation(ctx context.Context, occupation string) (*OntologyCategory, *OntologySubcategory, error) {
+	o, err := s.FindOccupation(ctx, occupation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.SubcategoryID == "" {
+		return nil, nil, &errors.ValidationError{Field: "occupation", Message: "has no matching subcategory", Value: occupation}
+	}
+
+	return s.FindSubcategory(ctx, string(o.SubcategoryID))
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestOccupations(t *testing.T) {
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "ontologyCategories"):
+			calls["categories"]++
+			w.Write([]byte(`{"data":{"ontologyCategories":[{
+				"id":"cat-1","preferredLabel":"Web, Mobile & Software Dev",
+				"subcategories":[{"id":"sub-1","preferredLabel":"Web Development","slug":"web-development"}]
+			}]}}`))
+		case strings.Contains(req.Query, "ontologyOccupations"):
+			calls["occupations"]++
+			w.Write([]byte(`{"data":{"ontologyOccupations":[
+				{"id":"occ-1","preferredLabel":"Full Stack Development","ontologyId":"1110580755107926016","categoryId":"cat-1","subcategoryId":"sub-1"},
+				{"id":"occ-2","preferredLabel":"Prompt Engineering"}
+			]}}`))
+		case strings.Contains(req.Query, "ontologySpecializations"):
+			assert.Equal(t, "occ-1", req.Variables["occupationId"])
+			w.Write([]byte(`{"data":{"ontologySpecializations":[
+				{"id":"spec-1","preferredLabel":"Backend Development","occupationId":"occ-1"}
+			]}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	occupations, err := svc.GetOccupations(ctx)
+	require.NoError(t, err)
+	require.Len(t, occupations, 2)
+
+	specializations, err := svc.GetSpecializations(ctx, "occ-1")
+	require.NoError(t, err)
+	assert.Equal(t, []Specialization{{ID: "spec-1", PreferredLabel: "Backend Development", OccupationID: "occ-1"}}, specializations)
+
+	occupation, err := svc.OccupationForSubcategory(ctx, "web-development")
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("occ-1"), occupation.ID)
+
+	category, sub, err := svc.SubcategoryForOccupation(ctx, "full stack development")
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("cat-1"), category.ID)
+	assert.Equal(t, models.ID("sub-1"), sub.ID)
+
+	_, _, err = svc.SubcategoryForOccupation(ctx, "occ-2")
+	assertValidationField(t, err, "occupation")
+
+	_, err = svc.FindOccupation(ctx, "Astronaut")
+	assertValidationField(t, err, "occupation")
+
+	_, err = svc.GetSpecializations(ctx, "")
+	assertValidationField(t, err, "occupationId")
+
+	assert.Equal(t, map[string]int{"categories": 1, "occupations": 1}, calls)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+const contractEndReasons = `{"data":{"reasons":[
+	{"id":"101","reason":"Job was completed successfully","alias":"API_REAS_JOB_COMPLETED_SUCCESSFULLY"},
+	{"id":"102","reason":"Freelancer was unresponsive","alias":"API_REAS_UNRESPONSIVE"}
+]}}`
+
+func TestGetReasonsCached(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(contractEndReasons))
+	}))
+	defer server.Close()
+
+	svc := NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	reasons, err := svc.GetReasons(ctx, ReasonTypeContractEnd, true)
+	require.NoError(t, err)
+	require.Len(t, reasons, 2)
+
+	// Callers cannot modify the cached catalog
+	reasons[0].Alias = "changed"
+
+	reasons, err = svc.GetReasons(ctx, ReasonTypeContractEnd, true)
+	require.NoError(t, err)
+	assert.Equal(t, "API_REAS_JOB_COMPLETED_SUCCESSFULLY", reasons[0].Alias)
+	assert.Equal(t, 1, calls)
+
+	_, err = svc.GetReasons(ctx, ReasonTypeContractEnd, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	reason, err := svc.FindReason(ctx, ReasonTypeContractEnd, "api_reas_unresponsive")
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("102"), reason.ID)
+
+	reason, err = svc.FindReason(ctx, ReasonTypeContractEnd, "101")
+	require.NoError(t, err)
+	assert.Equal(t, "API_REAS_JOB_COMPLETED_SUCCESSFULLY", reason.Alias)
+
+	_, err = svc.FindReason(ctx, ReasonTypeContractEnd, "API_REAS_BORED")
+	assertValidationField(t, err, "reason")
+	assert.Equal(t, 2, calls)
+}
+
+func TestEndContractWithReason(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		if strings.Contains(req.Query, "reasons") {
+			w.Write([]byte(contractEndReasons))
+			return
+		}
+		w.Write([]byte(`{"data":{"endContractByClient":{"success":true}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	require.NoError(t, svc.EndContractAsClientWithReason(ctx, EndContractInput{
+		ContractID: "contract-1",
+		Reason:     "API_REAS_JOB_COMPLETED_SUCCESSFULLY",
+		Rating:     intPtr(5),
+	}))
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "CONTRACT_END", reqs[0].Variables["reasonType"])
+	assert.Equal(t, "101", reqs[1].Variables["input"].(map[string]interface{})["reason"])
+
+	// Scores are checked before the catalog is fetched
+	reqs = nil
+	err := svc.EndContractAsClientWithReason(ctx, EndContractInput{
+		ContractID: "contract-1",
+		Reason:     "API_REAS_JOB_COMPLETED_SUCCESSFULLY",
+		Rating:     intPtr(0),
+	})
+	assertValidationField(t, err, "rating")
+	assert.Empty(t, reqs)
+
+	// Unknown aliases are rejected from the cached catalog
+	err = svc.EndContractAsClientWithReason(ctx, EndContractInput{ContractID: "contract-1", Reason: "API_REAS_BORED"})
+	assertValidationField(t, err, "reason")
+	assert.Empty(t, reqs)
+}
+
+func TestFindCategory(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"data":{"ontologyCategories":[{
+			"id":"531770282580668418","preferredLabel":"Web, Mobile & Software Dev","slug":"web-mobile-software-dev","ontologyId":"1137",
+			"subcategories":[{"id":"531770282589057033","preferredLabel":"Web Development","slug":"web-development"}]
+		}]}}`))
+	}))
+	defer server.Close()
+
+	svc := NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	for _, key := range []string{"531770282580668418", "1137", "WEB-MOBILE-SOFTWARE-DEV", "web, mobile & software dev"} {
+		category, err := svc.FindCategory(ctx, key)
+		require.NoError(t, err, key)
+		assert.Equal(t, models.ID("531770282580668418"), category.ID)
+	}
+
+	category, sub, err := svc.FindSubcategory(ctx, "Web Development")
+	require.NoError(t, err)
+	assert.Equal(t, "1137", category.OntologyID)
+	assert.Equal(t, models.ID("531770282589057033"), sub.ID)
+
+	_, err = svc.FindCategory(ctx, "Writing")
+	assertValidationField(t, err, "category")
+	_, _, err = svc.FindSubcategory(ctx, "web-design")
+	assertValidationField(t, err, "subcategory")
+
+	assert.Equal(t, 1, calls, "the category tree is cached")
+}
+
+func TestResolveSkillIDs(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queries = append(queries, req.Variables["query"].(string))
+		w.Write([]byte(`{"data":{"ontologyElementsSearchByPrefLabel":[
+			{"id":"1031626783404212224","preferredLabel":"Golang Testing"},
+			{"id":"1031626783404212225","preferredLabel":"Go"},
+			{"id":"1031626790496780288","preferredLabel":"GraphQL"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	svc := NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	ids, err := svc.ResolveSkillIDs(ctx, []string{"go", " GraphQL "})
+	require.NoError(t, err)
+	assert.Equal(t, []models.ID{"1031626783404212225", "1031626790496780288"}, ids)
+
+	ids, err = svc.ResolveSkillIDs(ctx, []string{"GO"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.ID{"1031626783404212225"}, ids)
+	assert.Equal(t, []string{"go", "GraphQL"}, queries, "resolved names are cached")
+
+	_, err = svc.ResolveSkillIDs(ctx, []string{"go", "Golang"})
+	assertValidationField(t, err, "names[1]")
+}
@@ -1,72 +1,77 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
 	"fmt"
-	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // Milestone represents a milestone in a contract
 type Milestone struct {
-	ID                     ID               `json:"id"`
-	Description            string           `json:"description"`
-	Instructions           string           `json:"instructions"`
-	DueDateTime            *DateTime        `json:"dueDateTime"`
-	State                  MilestoneState   `json:"state"`
-	DepositAmount          Money            `json:"depositAmount"`
-	CurrentEscrowAmount    Money            `json:"currentEscrowAmount"`
-	FundedAmount           Money            `json:"fundedAmount"`
-	Paid                   Money            `json:"paid"`
-	Bonus                  Money            `json:"bonus"`
-	SubmissionCount        int              `json:"submissionCount"`
-	SequenceID             int              `json:"sequenceId"`
-	CreatedDateTime        DateTime         `json:"createdDateTime"`
-	ModifiedDateTime       DateTime         `json:"modifiedDateTime"`
-	CreatedBy              User             `json:"createdBy"`
-	ModifiedBy             User             `json:"modifiedBy"`
-	SubmissionEvents       []SubmissionEvent `json:"submissionEvents"`
+	ID                  models.ID         `json:"id"`
+	Description         string            `json:"description"`
+	Instructions        string            `json:"instructions"`
+	DueDateTime         *models.DateTime  `json:"dueDateTime"`
+	State               MilestoneState    `json:"state"`
+	DepositAmount       models.Money      `json:"depositAmount"`
+	CurrentEscrowAmount models.Money      `json:"currentEscrowAmount"`
+	FundedAmount        models.Money      `json:"fundedAmount"`
+	Paid                models.Money      `json:"paid"`
+	Bonus               models.Money      `json:"bonus"`
+	SubmissionCount     int               `json:"submissionCount"`
+	SequenceID          int               `json:"sequenceId"`
+	CreatedDateTime     models.DateTime   `json:"createdDateTime"`
+	ModifiedDateTime    models.DateTime   `json:"modifiedDateTime"`
+	CreatedBy           models.User       `json:"createdBy"`
+	ModifiedBy          models.User       `json:"modifiedBy"`
+	SubmissionEvents    []SubmissionEvent `json:"submissionEvents"`
+	// EscrowTransitions are the deposits into and payments out of the
+	// milestone's escrow, oldest first
+	EscrowTransitions []EscrowTransition `json:"escrowTransitions"`
 }
 
 // MilestoneState represents the state of a milestone
 type MilestoneState string
 
 const (
-	MilestoneStateNotFunded  MilestoneState = "NOT_FUNDED"
-	MilestoneStateActive     MilestoneState = "ACTIVE"
-	MilestoneStateSubmitted  MilestoneState = "SUBMITTED"
-	MilestoneStateApproved   MilestoneState = "APPROVED"
-	MilestoneStateRejected   MilestoneState = "REJECTED"
-	MilestoneStatePaid       MilestoneState = "PAID"
-	MilestoneStateCancelled  MilestoneState = "CANCELLED"
+	MilestoneStateNotFunded MilestoneState = "NOT_FUNDED"
+	MilestoneStateActive    MilestoneState = "ACTIVE"
+	MilestoneStateSubmitted MilestoneState = "SUBMITTED"
+	MilestoneStateApproved  MilestoneState = "APPROVED"
+	MilestoneStateRejected  MilestoneState = "REJECTED"
+	MilestoneStatePaid      MilestoneState = "PAID"
+	MilestoneStateCancelled MilestoneState = "CANCELLED"
+	MilestoneStateUnknown   MilestoneState = "UNKNOWN"
 )
 
 // SubmissionEvent represents a milestone submission event
 type SubmissionEvent struct {
-	Submission       *Submission       `json:"submission"`
+	Submission        *Submission        `json:"submission"`
 	SubmissionMessage *SubmissionMessage `json:"submissionMessage"`
-	RevisionMessage  *RevisionMessage  `json:"revisionMessage"`
+	RevisionMessage   *RevisionMessage   `json:"revisionMessage"`
 }
 
 // Submission represents a milestone submission
 type Submission struct {
-	ID               ID       `json:"id"`
-	CreatedDateTime  DateTime `json:"createdDateTime"`
-	ModifiedDateTime DateTime `json:"modifiedDateTime"`
-	Amount           Money    `json:"amount"`
-	SequenceID       int      `json:"sequenceId"`
+	ID               models.ID       `json:"id"`
+	CreatedDateTime  models.DateTime `json:"createdDateTime"`
+	ModifiedDateTime models.DateTime `json:"modifiedDateTime"`
+	Amount           models.Money    `json:"amount"`
+	SequenceID       int             `json:"sequenceId"`
 }
 
 // SubmissionMessage represents a submission message
 type SubmissionMessage struct {
-	CreatedDateTime DateTime `json:"createdDateTime"`
-	Message         string   `json:"message"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+	Message         string          `json:"message"`
 }
 
 // RevisionMessage represents a revision message
 type RevisionMessage struct {
-	CreatedDateTime DateTime `json:"createdDateTime"`
-	Message         string   `json:"message"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+	Message         string          `json:"message"`
 }
 
 // CreateMilestoneInput represents input for creating a milestone
@@ -80,8 +85,25 @@ type CreateMilestoneInput struct {
 	AttachmentIDs []string `json:"attachmentIds,omitempty"`
 }
 
+// Validate checks the required fields, that the deposit is a positive
+// amount and that the due date is a date
+func (in CreateMilestoneInput) Validate() error {
+	return firstError(
+		required("contractId", in.ContractID),
+		required("description", in.Description),
+		required("depositAmount", in.DepositAmount),
+		validateAmount("depositAmount", in.DepositAmount),
+		required("dueDate", in.DueDate),
+		validateDate("dueDate", in.DueDate),
+	)
+}
+
 // CreateMilestone creates a new milestone
 func (s *ContractsService) CreateMilestone(ctx context.Context, input CreateMilestoneInput) (*Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation CreateMilestone(
 			$offerId: ID!,
@@ -118,7 +140,7 @@ func (s *ContractsService) CreateMilestone(ctx context.Context, input CreateMile
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -131,55 +153,116 @@ func (s *ContractsService) CreateMilestone(ctx context.Context, input CreateMile
 			"attachmentIds": input.AttachmentIDs,
 		},
 	}
-	
+
 	var resp struct {
 		CreateMilestone Milestone `json:"createMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.CreateMilestone, nil
 }
 
-// EditMilestoneInput represents input for editing a milestone
+// EditMilestoneInput represents changes to a milestone. Nil fields are
+// left unchanged and are not sent, so a partial edit cannot clear them by
+// accident.
 type EditMilestoneInput struct {
-	ID            string   `json:"id"`
-	Description   string   `json:"description,omitempty"`
-	Instructions  string   `json:"instructions,omitempty"`
-	DepositAmount string   `json:"depositAmount,omitempty"`
-	DueDate       string   `json:"dueDate,omitempty"`
-	Attachments   []string `json:"attachments,omitempty"`
-	Message       string   `json:"message,omitempty"`
-	SequenceID    int      `json:"sequenceId,omitempty"`
+	ID           string  `json:"id"`
+	Description  *string `json:"description,omitempty"`
+	Instructions *string `json:"instructions,omitempty"`
+	// DepositAmount is a decimal amount such as "150.00"
+	DepositAmount *string `json:"depositAmount,omitempty"`
+	// DueDate is the due date as YYYY-MM-DD
+	DueDate     *string   `json:"dueDate,omitempty"`
+	Attachments *[]string `json:"attachments,omitempty"`
+	SequenceID  *int      `json:"sequenceId,omitempty"`
+	// Message is sent to the freelancer with the edit
+	Message string `json:"message,omitempty"`
+}
+
+// SetDescription changes the description
+func (in *EditMilestoneInput) SetDescription(description string) *EditMilestoneInput {
+	in.Description = &description
+	return in
+}
+
+// SetInstructions changes the instructions. Empty instructions clear them.
+func (in *EditMilestoneInput) SetInstructions(instructions string) *EditMilestoneInput {
+	in.Instructions = &instructions
+	return in
+}
+
+// SetDepositAmount changes the amount, a decimal such as "150.00"
+func (in *EditMilestoneInput) SetDepositAmount(amount string) *EditMilestoneInput {
+	in.DepositAmount = &amount
+	return in
+}
+
+// SetDueDate changes the due date, given as YYYY-MM-DD
+func (in *EditMilestoneInput) SetDueDate(date string) *EditMilestoneInput {
+	in.DueDate = &date
+	return in
+}
+
+// SetAttachments replaces the attachments. Calling it with no IDs removes
+// them all.
+func (in *EditMilestoneInput) SetAttachments(ids ...string) *EditMilestoneInput {
+	ids = append([]string{}, ids...)
+	in.Attachments = &ids
+	return in
+}
+
+// SetSequenceID moves the milestone to a position in the contract
+func (in *EditMilestoneInput) SetSequenceID(sequenceID int) *EditMilestoneInput {
+	in.SequenceID = &sequenceID
+	return in
 }
 
-// EditMilestone edits an existing milestone
+// Validate checks the milestone ID, that something is being changed and
+// any description, deposit or due date being changed
+func (in EditMilestoneInput) Validate() error {
+	if err := required("id", in.ID); err != nil {
+		return err
+	}
+	if in == (EditMilestoneInput{ID: in.ID, Message: in.Message}) {
+		return &errors.ValidationError{Field: "input", Message: "at least one change is required"}
+	}
+	if in.Description != nil {
+		if err := required("description", *in.Description); err != nil {
+			return err
+		}
+	}
+	if in.DepositAmount != nil {
+		if err := firstError(
+			required("depositAmount", *in.DepositAmount),
+			validateAmount("depositAmount", *in.DepositAmount),
+		); err != nil {
+			return err
+		}
+	}
+	if in.DueDate != nil {
+		if err := firstError(
+			required("dueDate", *in.DueDate),
+			validateDate("dueDate", *in.DueDate),
+		); err != nil {
+			return err
+		}
+	}
+	return validateNonNegative("sequenceId", in.SequenceID)
+}
+
+// EditMilestone edits an existing milestone, changing only the fields set
+// in input
 func (s *ContractsService) EditMilestone(ctx context.Context, input EditMilestoneInput) (*Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
-		mutation EditMilestone(
-			$id: ID!,
-			$description: String,
-			$instructions: String,
-			$depositAmount: String,
-			$dueDate: String,
-			$attachments: [ID!],
-			$message: String,
-			$sequenceId: Int
-		) {
-			editMilestone(
-				input: {
-					id: $id,
-					description: $description,
-					instructions: $instructions,
-					depositAmount: $depositAmount,
-					dueDate: $dueDate,
-					attachments: $attachments,
-					message: $message,
-					sequenceId: $sequenceId
-				}
-			) {
+		mutation EditMilestone($input: EditMilestoneInput!) {
+			editMilestone(input: $input) {
 				id
 				description
 				instructions
@@ -194,29 +277,22 @@ func (s *ContractsService) EditMilestone(ctx context.Context, input EditMileston
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
-			"id":            input.ID,
-			"description":   input.Description,
-			"instructions":  input.Instructions,
-			"depositAmount": input.DepositAmount,
-			"dueDate":       input.DueDate,
-			"attachments":   input.Attachments,
-			"message":       input.Message,
-			"sequenceId":    input.SequenceID,
+			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		EditMilestone Milestone `json:"editMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.EditMilestone, nil
 }
 
@@ -230,7 +306,7 @@ func (s *ContractsService) ActivateMilestone(ctx context.Context, milestoneID st
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -238,30 +314,43 @@ func (s *ContractsService) ActivateMilestone(ctx context.Context, milestoneID st
 			"message": message,
 		},
 	}
-	
+
 	var resp struct {
 		ActivateMilestone Milestone `json:"activateMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ActivateMilestone, nil
 }
 
 // ApproveMilestoneInput represents input for approving a milestone
 type ApproveMilestoneInput struct {
-	ID                  string `json:"id"`
-	PaidAmount          string `json:"paidAmount,omitempty"`
-	BonusAmount         string `json:"bonusAmount,omitempty"`
-	PaymentComment      string `json:"paymentComment,omitempty"`
-	UnderpaymentReason  string `json:"underpaymentReason,omitempty"`
-	NoteToContractor    string `json:"noteToContractor,omitempty"`
+	ID                 string `json:"id"`
+	PaidAmount         string `json:"paidAmount,omitempty"`
+	BonusAmount        string `json:"bonusAmount,omitempty"`
+	PaymentComment     string `json:"paymentComment,omitempty"`
+	UnderpaymentReason string `json:"underpaymentReason,omitempty"`
+	NoteToContractor   string `json:"noteToContractor,omitempty"`
+}
+
+// Validate checks the milestone ID and any paid or bonus amount
+func (in ApproveMilestoneInput) Validate() error {
+	return firstError(
+		required("id", in.ID),
+		validateAmount("paidAmount", in.PaidAmount),
+		validateAmount("bonusAmount", in.BonusAmount),
+	)
 }
 
 // ApproveMilestone approves a milestone
 func (s *ContractsService) ApproveMilestone(ctx context.Context, input ApproveMilestoneInput) (*Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation ApproveMilestone(
 			$id: ID!,
@@ -296,7 +385,7 @@ func (s *ContractsService) ApproveMilestone(ctx context.Context, input ApproveMi
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -308,15 +397,15 @@ func (s *ContractsService) ApproveMilestone(ctx context.Context, input ApproveMi
 			"noteToContractor":   input.NoteToContractor,
 		},
 	}
-	
+
 	var resp struct {
 		ApproveMilestone Milestone `json:"approveMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ApproveMilestone, nil
 }
 
@@ -326,8 +415,20 @@ type RejectMilestoneInput struct {
 	NoteToContractor string `json:"noteToContractor"`
 }
 
+// Validate checks that the milestone and the note are set
+func (in RejectMilestoneInput) Validate() error {
+	return firstError(
+		required("id", in.ID),
+		required("noteToContractor", in.NoteToContractor),
+	)
+}
+
 // RejectMilestone rejects a milestone submission
 func (s *ContractsService) RejectMilestone(ctx context.Context, input RejectMilestoneInput) (*Milestone, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	mutation := `
 		mutation RejectSubmittedMilestone($id: String, $noteToContractor: String) {
 			rejectSubmittedMilestone(
@@ -337,7 +438,7 @@ func (s *ContractsService) RejectMilestone(ctx context.Context, input RejectMile
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
@@ -345,17 +446,17 @@ func (s *ContractsService) RejectMilestone(ctx context.Context, input RejectMile
 			"noteToContractor": input.NoteToContractor,
 		},
 	}
-	
+
 	var resp struct {
 		RejectSubmittedMilestone struct {
 			ID string `json:"id"`
 		} `json:"rejectSubmittedMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	// Get full milestone details
 	return s.GetMilestone(ctx, resp.RejectSubmittedMilestone.ID)
 }
@@ -367,26 +468,26 @@ func (s *ContractsService) DeleteMilestone(ctx context.Context, milestoneID stri
 			deleteMilestone(input: {id: $id})
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"id": milestoneID,
 		},
 	}
-	
+
 	var resp struct {
 		DeleteMilestone bool `json:"deleteMilestone"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.DeleteMilestone {
 		return fmt.Errorf("failed to delete milestone")
 	}
-	
+
 	return nil
 }
 
@@ -403,6 +504,6 @@ func (s *ContractsService) GetContractMilestones(ctx context.Context, contractID
 	if err != nil {
 		return nil, err
 	}
-	
+
 	return contract.Milestones, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// EscrowState is the funding state of a milestone's escrow, derived from
+// its amounts by Milestone.EscrowState
+type EscrowState string
+
+const (
+	// EscrowStateUnfunded means nothing has been deposited
+	EscrowStateUnfunded EscrowState = "UNFUNDED"
+	// EscrowStatePartiallyFunded means less than the milestone amount is
+	// held in escrow
+	EscrowStatePartiallyFunded EscrowState = "PARTIALLY_FUNDED"
+	// EscrowStateFunded means the milestone amount is held in escrow
+	EscrowStateFunded EscrowState = "FUNDED"
+	// EscrowStateReleased means the escrow was paid out to the freelancer
+	EscrowStateReleased EscrowState = "RELEASED"
+	// EscrowStateRefunded means the escrow was returned to the client
+	// without payment
+	EscrowStateRefunded EscrowState = "REFUNDED"
+)
+
+// EscrowTransitionType is the kind of a change to a milestone's escrow
+type EscrowTransitionType string
+
+const (
+	EscrowTransitionFund    EscrowTransitionType = "FUND"
+	EscrowTransitionRelease EscrowTransitionType = "RELEASE"
+	EscrowTransitionRefund  EscrowTransitionType = "REFUND"
+	EscrowTransitionUnknown EscrowTransitionType = "UNKNOWN"
+)
+
+// EscrowTransition is a change to a milestone's escrow
+type EscrowTransition struct {
+	Type             EscrowTransitionType `json:"type"`
+	Amount           models.Money         `json:"amount"`
+	OccurredDateTime models.DateTime      `json:"occurredDateTime"`
+	Actor            *models.User         `json:"actor"`
+}
+
+// EscrowState returns the funding state of the milestone's escrow, from the
+// amount deposited, held in escrow and paid
+func (m *Milestone) EscrowState() EscrowState {
+	if m.CurrentEscrowAmount.IsZero() || m.CurrentEscrowAmount.IsNegative() {
+		switch {
+		case !m.Paid.IsZero():
+			return EscrowStateReleased
+		case !m.FundedAmount.IsZero():
+			return EscrowStateRefunded
+		default:
+			return EscrowStateUnfunded
+		}
+	}
+
+	// Amounts in different currencies are taken as funded
+	if cmp, err := m.CurrentEscrowAmount.Cmp(m.DepositAmount); err == nil && cmp < 0 {
+		return EscrowStatePartiallyFunded
+	}
+	return EscrowStateFunded
+}
+
+// EscrowBalance is the escrow of a contract's milestones
+type EscrowBalance struct {
+	ContractID models.ID
+	// InEscrow is the amount held in escrow across milestones
+	InEscrow models.Money
+	// Released is the amount paid out of escrow
+	Released models.Money
+	// Unfunded is the amount of open milestones not yet deposited
+	Unfunded models.Money
+	// Milestones are the contract's milestones with their escrow amounts
+	// and transitions
+	Milestones []Milestone
+}
+
+// milestoneEscrowFields selects the escrow fields of a Milestone
+const milestoneEscrowFields = `
+	id
+	description
+	state
+	depositAmount {
+		rawValue
+		currency
+		displayValue
+	}
+	currentEscrowAmount {
+		rawValue
+		currency
+		displayValue
+	}
+	fundedAmount {
+		rawValue
+		currency
+		displayValue
+	}
+	paid {
+		rawValue
+		currency
+		displayValue
+	}
+	escrowTransitions {
+		type
+		amount {
+			rawValue
+			currency
+			displayValue
+		}
+		occurredDateTime
+		actor {
+			id
+			name
+		}
+	}
+`
+
+// FundMilestone deposits amount into the escrow of a milestone, which
+// must be in the contract's currency. Funding is not retried, since a
+// retry after a lost response could deposit twice; pass an idempotency
+// key with WithRequestOptions and WithIdempotencyKey to make it retryable.
+func (s *ContractsService) FundMilestone(ctx context.Context, milestoneID string, amount models.Money) (*Milestone, error) {
+	if err := firstError(
+		required("milestoneId", milestoneID),
+		validateMoney("amount", amount),
+	); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation FundMilestone($input: FundMilestoneInput!) {
+			fundMilestone(input: $input) {` + milestoneEscrowFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": map[string]interface{}{
+				"milestoneId": milestoneID,
+				"amount":      amount,
+			},
+		},
+	}
+
+	var resp struct {
+		FundMilestone Milestone `json:"fundMilestone"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.FundMilestone, nil
+}
+
+// GetEscrowBalance returns the escrow of a contract's milestones: the
+// amounts held, released and still to be funded, and each milestone's
+// escrow amounts and transitions. Cancelled milestones count towards
+// nothing but what they released.
+func (s *ContractsService) GetEscrowBalance(ctx context.Context, contractID string) (*EscrowBalance, error) {
+	if err := required("contractId", contractID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetEscrowBalance($id: ID!) {
+			contract(id: $id) {
+				id
+				milestones {` + milestoneEscrowFields + `}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": contractID,
+		},
+	}
+
+	var resp struct {
+		Contract *struct {
+			ID         models.ID   `json:"id"`
+			Milestones []Milestone `json:"milestones"`
+		} `json:"contract"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Contract == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	balance := &EscrowBalance{ContractID: resp.Contract.ID, Milestones: resp.Contract.Milestones}
+	for _, m := range balance.Milestones {
+		if err := balance.add(m); err != nil {
+			return nil, errors.WrapError(err, "failed to total escrow of milestone "+string(m.ID))
+		}
+	}
+	return balance, nil
+}
+
+// add adds the amounts of m to the balance
+func (b *EscrowBalance) add(m Milestone) error {
+	var err error
+	if b.Released, err = b.Released.Add(m.Paid); err != nil {
+		return err
+	}
+	if m.State == MilestoneStateCancelled {
+		return nil
+	}
+	if !m.CurrentEscrowAmount.IsNegative() {
+		if b.InEscrow, err = b.InEscrow.Add(m.CurrentEscrowAmount); err != nil {
+			return err
+		}
+	}
+	if m.State == MilestoneStatePaid {
+		return nil
+	}
+
+	unfunded, err := m.DepositAmount.Sub(m.FundedAmount)
+	if err != nil || unfunded.IsZero() || unfunded.IsNegative() {
+		return err
+	}
+	b.Unfunded, err = b.Unfunded.Add(unfunded)
+	return err
+}
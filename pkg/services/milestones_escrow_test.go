@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestFundMilestone(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Write([]byte(`{"data":{"fundMilestone":{
+			"id":"milestone-1","state":"ACTIVE",
+			"depositAmount":{"rawValue":"500.00","currency":"USD"},
+			"currentEscrowAmount":{"rawValue":"500.00","currency":"USD"},
+			"fundedAmount":{"rawValue":"500.00","currency":"USD"},
+			"paid":{"rawValue":"0","currency":"USD"},
+			"escrowTransitions":[{"type":"FUND","amount":{"rawValue":"500.00","currency":"USD"},"occurredDateTime":"2024-03-01T10:00:00Z","actor":{"id":"user-1","name":"Client"}}]
+		}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	milestone, err := svc.FundMilestone(context.Background(), "milestone-1", models.MustMoney("500", "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, EscrowStateFunded, milestone.EscrowState())
+	require.Len(t, milestone.EscrowTransitions, 1)
+	assert.Equal(t, EscrowTransitionFund, milestone.EscrowTransitions[0].Type)
+	assert.Equal(t, "Client", milestone.EscrowTransitions[0].Actor.Name)
+
+	assert.Equal(t, "FundMilestone", req.OperationName)
+	assert.Equal(t, map[string]interface{}{
+		"milestoneId": "milestone-1",
+		"amount":      map[string]interface{}{"rawValue": "500.00", "currency": "USD"},
+	}, req.Variables["input"])
+
+	_, err = svc.FundMilestone(context.Background(), "milestone-1", models.MustMoney("0", "USD"))
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "amount", validationErr.Field)
+}
+
+func TestGetEscrowBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Variables["id"] != "contract-1" {
+			w.Write([]byte(`{"data":{"contract":null}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"contract":{"id":"contract-1","milestones":[
+			{"id":"m-1","state":"PAID","depositAmount":{"rawValue":"300","currency":"USD"},"currentEscrowAmount":{"rawValue":"0","currency":"USD"},"fundedAmount":{"rawValue":"300","currency":"USD"},"paid":{"rawValue":"300","currency":"USD"}},
+			{"id":"m-2","state":"ACTIVE","depositAmount":{"rawValue":"500","currency":"USD"},"currentEscrowAmount":{"rawValue":"200","currency":"USD"},"fundedAmount":{"rawValue":"200","currency":"USD"},"paid":{"rawValue":"0","currency":"USD"}},
+			{"id":"m-3","state":"NOT_FUNDED","depositAmount":{"rawValue":"400","currency":"USD"},"currentEscrowAmount":{"rawValue":"0","currency":"USD"},"fundedAmount":{"rawValue":"0","currency":"USD"},"paid":{"rawValue":"0","currency":"USD"}},
+			{"id":"m-4","state":"CANCELLED","depositAmount":{"rawValue":"100","currency":"USD"},"currentEscrowAmount":{"rawValue":"0","currency":"USD"},"fundedAmount":{"rawValue":"100","currency":"USD"},"paid":{"rawValue":"0","currency":"USD"}}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	balance, err := svc.GetEscrowBalance(context.Background(), "contract-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("contract-1"), balance.ContractID)
+	assert.True(t, balance.InEscrow.Equal(models.MustMoney("200", "USD")), balance.InEscrow.String())
+	assert.True(t, balance.Released.Equal(models.MustMoney("300", "USD")), balance.Released.String())
+	assert.True(t, balance.Unfunded.Equal(models.MustMoney("700", "USD")), balance.Unfunded.String())
+
+	states := make([]EscrowState, len(balance.Milestones))
+	for i := range balance.Milestones {
+		states[i] = balance.Milestones[i].EscrowState()
+	}
+	assert.Equal(t, []EscrowState{EscrowStateReleased, EscrowStatePartiallyFunded, EscrowStateUnfunded, EscrowStateRefunded}, states)
+
+	_, err = svc.GetEscrowBalance(context.Background(), "contract-2")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// DefaultApprovalPollInterval is the default interval between checks of
+// the review queue. Each check lists the contracts and fetches their
+// milestones, so it is longer than the stream interval.
+const DefaultApprovalPollInterval = 15 * time.Minute
+
+// DefaultApprovalThresholds are the times left before auto-approval at
+// which reminders are emitted by default: three days, one day and the
+// deadline itself
+var DefaultApprovalThresholds = []time.Duration{72 * time.Hour, 24 * time.Hour, 0}
+
+// ApprovalWatcherOptions configures an ApprovalWatcher
+type ApprovalWatcherOptions struct {
+	// Thresholds are the times left before auto-approval at which a
+	// reminder is emitted, DefaultApprovalThresholds if empty. Zero
+	// reminds at the deadline.
+	Thresholds []time.Duration
+
+	// PollInterval is the delay between checks of the review queue.
+	// Reminders are emitted up to this long after a threshold is crossed.
+	PollInterval time.Duration
+
+	// MaxBackoff caps the delay between retries after failed checks
+	MaxBackoff time.Duration
+
+	// BufferSize is the capacity of the channel Watch returns
+	BufferSize int
+
+	// OnError, if set, is called with every failed check before backing
+	// off, and with the error for each contract whose milestones could not
+	// be fetched
+	OnError func(error)
+
+	// Now returns the current time, time.Now if nil
+	Now func() time.Time
+}
+
+// ApprovalReminder is emitted when a pending submission crosses a
+// threshold of time left before it is approved automatically
+type ApprovalReminder struct {
+	Submission PendingSubmission
+	// Threshold is the threshold crossed. A submission that crossed
+	// several since the last check is reminded once, for the smallest.
+	Threshold time.Duration
+	// TimeLeft is the time left when the reminder was emitted, negative
+	// once the deadline has passed
+	TimeLeft time.Duration
+}
+
+// Overdue returns true if the deadline has passed
+func (r ApprovalReminder) Overdue() bool {
+	return r.TimeLeft <= 0
+}
+
+// ApprovalWatcher reminds of pending submissions as their auto-approval
+// deadlines approach. Create one with ContractsService.ApprovalWatcher.
+type ApprovalWatcher struct {
+	contracts  *ContractsService
+	opts       ApprovalWatcherOptions
+	thresholds []time.Duration
+
+	// reminded holds the smallest threshold reminded of for each
+	// submission, keyed by submissionKey
+	reminded map[string]time.Duration
+}
+
+// ApprovalWatcher returns a watcher of the auto-approval deadlines of the
+// submissions ListPendingSubmissions returns. Negative thresholds are
+// rejected.
+func (s *ContractsService) ApprovalWatcher(opts ApprovalWatcherOptions) (*ApprovalWatcher, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultApprovalPollInterval
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultStreamMaxBackoff
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	thresholds := opts.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultApprovalThresholds
+	}
+	for _, t := range thresholds {
+		if t < 0 {
+			return nil, &errors.ValidationError{Field: "thresholds", Message: fmt.Sprintf("must not be negative, got %s", t)}
+		}
+	}
+
+	// Largest first, so the thresholds crossed by a time left are a prefix
+	thresholds = append([]time.Duration(nil), thresholds...)
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] > thresholds[j] })
+
+	return &ApprovalWatcher{
+		contracts:  s,
+		opts:       opts,
+		thresholds: thresholds,
+		reminded:   make(map[string]time.Duration),
+	}, nil
+}
+
+// Watch checks the review queue every PollInterval and emits a reminder
+// whenever a submission crosses a threshold. Submissions already past a
+// threshold when watching starts are reminded of on the first check. The
+// first check is made before Watch returns and its error returned; later
+// failures are retried with exponential backoff. The returned channel is
+// closed when ctx is cancelled or the client is closed.
+//
+// Watch must not be called again until the channel is closed.
+func (w *ApprovalWatcher) Watch(ctx context.Context) (<-chan ApprovalReminder, error) {
+	queue, err := w.contracts.ListPendingSubmissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan ApprovalReminder, w.opts.BufferSize)
+	ctx, cancel := w.contracts.client.workerContext(ctx)
+
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		delay := w.opts.PollInterval
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		for {
+			w.reportErrors(queue)
+			for _, reminder := range w.check(queue.Items, w.opts.Now()) {
+				select {
+				case out <- reminder:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+
+				queue, err = w.contracts.ListPendingSubmissions(ctx)
+				if err == nil {
+					break
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				if w.opts.OnError != nil {
+					w.opts.OnError(err)
+				}
+
+				delay *= 2
+				if delay > w.opts.MaxBackoff {
+					delay = w.opts.MaxBackoff
+				}
+				timer.Reset(delay)
+			}
+			delay = w.opts.PollInterval
+			timer.Reset(delay)
+		}
+	}()
+
+	return out, nil
+}
+
+// check returns the reminders due for items at now, soonest deadline
+// first, and forgets the submissions no longer pending
+func (w *ApprovalWatcher) check(items []PendingSubmission, now time.Time) []ApprovalReminder {
+	var reminders []ApprovalReminder
+	pending := make(map[string]bool, len(items))
+	for _, item := range items {
+		left, ok := item.TimeLeft(now)
+		if !ok {
+			continue
+		}
+		key := submissionKey(item)
+		pending[key] = true
+
+		// The smallest threshold crossed, if any
+		crossed := -1
+		for i, t := range w.thresholds {
+			if left <= t {
+				crossed = i
+			}
+		}
+		if crossed < 0 {
+			continue
+		}
+
+		threshold := w.thresholds[crossed]
+		if last, ok := w.reminded[key]; ok && last <= threshold {
+			continue
+		}
+		w.reminded[key] = threshold
+		reminders = append(reminders, ApprovalReminder{Submission: item, Threshold: threshold, TimeLeft: left})
+	}
+
+	// Approved, rejected and resubmitted milestones start over
+	for key := range w.reminded {
+		if !pending[key] {
+			delete(w.reminded, key)
+		}
+	}
+	return reminders
+}
+
+// reportErrors passes the contracts queue could not be checked for to
+// OnError
+func (w *ApprovalWatcher) reportErrors(queue *SubmissionQueue) {
+	if w.opts.OnError == nil {
+		return
+	}
+	ids := make([]models.ID, 0, len(queue.Errors))
+	for id := range queue.Errors {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		w.opts.OnError(errors.WrapError(queue.Errors[id], "failed to check submissions of contract "+string(id)))
+	}
+}
+
+// submissionKey identifies a submission of a milestone, so a milestone
+// submitted again is reminded of afresh
+func submissionKey(p PendingSubmission) string {
+	return string(p.Milestone.ID) + "@" + p.SubmittedAt.Format(time.RFC3339Nano)
+}
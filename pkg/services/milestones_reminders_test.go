@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestApprovalWatcherCheck(t *testing.T) {
+	svc := NewContractsService(&BaseClient{})
+	w, err := svc.ApprovalWatcher(ApprovalWatcherOptions{Thresholds: []time.Duration{0, 72 * time.Hour, 24 * time.Hour}})
+	require.NoError(t, err)
+
+	submitted := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	item := PendingSubmission{
+		Milestone:     Milestone{ID: "m-1"},
+		SubmittedAt:   submitted,
+		AutoApproveAt: submitted.Add(MilestoneAutoApprovalPeriod),
+	}
+	unknown := PendingSubmission{Milestone: Milestone{ID: "m-2"}}
+	at := func(left time.Duration) time.Time { return item.AutoApproveAt.Add(-left) }
+
+	assert.Empty(t, w.check([]PendingSubmission{item, unknown}, at(100*time.Hour)))
+
+	reminders := w.check([]PendingSubmission{item, unknown}, at(50*time.Hour))
+	require.Len(t, reminders, 1)
+	assert.Equal(t, 72*time.Hour, reminders[0].Threshold)
+	assert.Equal(t, 50*time.Hour, reminders[0].TimeLeft)
+	assert.False(t, reminders[0].Overdue())
+
+	assert.Empty(t, w.check([]PendingSubmission{item}, at(30*time.Hour)), "each threshold is reminded once")
+
+	reminders = w.check([]PendingSubmission{item}, at(-time.Hour))
+	require.Len(t, reminders, 1, "thresholds crossed together are reminded once")
+	assert.Equal(t, time.Duration(0), reminders[0].Threshold)
+	assert.True(t, reminders[0].Overdue())
+
+	// Resubmitting starts over
+	item.SubmittedAt = submitted.Add(24 * time.Hour)
+	item.AutoApproveAt = item.SubmittedAt.Add(MilestoneAutoApprovalPeriod)
+	assert.Empty(t, w.check([]PendingSubmission{item}, at(100*time.Hour)))
+	assert.Empty(t, w.reminded, "the old submission is forgotten")
+	reminders = w.check([]PendingSubmission{item}, at(10*time.Hour))
+	require.Len(t, reminders, 1)
+	assert.Equal(t, 24*time.Hour, reminders[0].Threshold)
+
+	_, err = svc.ApprovalWatcher(ApprovalWatcherOptions{Thresholds: []time.Duration{-time.Hour}})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "thresholds", validationErr.Field)
+}
+
+func TestApprovalWatcherWatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []GraphQLRequest
+		if json.Unmarshal(body, &batch) != nil {
+			w.Write([]byte(`{"data":{"contractList":{"edges":[{"node":{"id":"contract-1","title":"Website"}}]}}}`))
+			return
+		}
+		w.Write([]byte(`[{"data":{"contract":{"id":"contract-1","milestones":[
+			{"id":"m-1","state":"SUBMITTED","submissionEvents":[{"submission":{"id":"s-1","createdDateTime":"2024-03-01T00:00:00Z"}}]}
+		]}}}]`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 3, 14, 4, 0, 0, 0, time.UTC)
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	w, err := svc.ApprovalWatcher(ApprovalWatcherOptions{
+		PollInterval: 10 * time.Millisecond,
+		Now:          func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reminders, err := w.Watch(ctx)
+	require.NoError(t, err)
+
+	select {
+	case r := <-reminders:
+		assert.Equal(t, models.ID("contract-1"), r.Submission.ContractID)
+		assert.Equal(t, 24*time.Hour, r.Threshold)
+		assert.Equal(t, 20*time.Hour, r.TimeLeft)
+	case <-time.After(time.Second):
+		t.Fatal("no reminder")
+	}
+
+	// Later checks have nothing new to remind of
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	for r := range reminders {
+		t.Fatalf("unexpected reminder %+v", r)
+	}
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// MilestoneAutoApprovalPeriod is how long after a milestone is submitted
+// its payment is released if the client neither approves it nor requests
+// changes
+const MilestoneAutoApprovalPeriod = 14 * 24 * time.Hour
+
+// pendingContractsPageSize is the default page size used when listing the
+// contracts to look for submissions on
+const pendingContractsPageSize = 100
+
+// milestoneBatchSize is the number of contracts whose milestones are
+// fetched per batch request
+const milestoneBatchSize = 25
+
+// PendingSubmission is a milestone submitted for the client's review
+type PendingSubmission struct {
+	ContractID    models.ID
+	ContractTitle string
+	Milestone     Milestone
+	// SubmittedAt is when the latest submission was made, or zero if the
+	// API did not say
+	SubmittedAt time.Time
+	// AutoApproveAt is when the submission is approved automatically,
+	// MilestoneAutoApprovalPeriod after SubmittedAt, or zero if unknown
+	AutoApproveAt time.Time
+}
+
+// TimeLeft returns the time from now until the submission is approved
+// automatically, negative once the deadline has passed. ok is false if the
+// deadline is unknown.
+func (p PendingSubmission) TimeLeft(now time.Time) (left time.Duration, ok bool) {
+	if p.AutoApproveAt.IsZero() {
+		return 0, false
+	}
+	return p.AutoApproveAt.Sub(now), true
+}
+
+// SubmissionQueue is the result of ListPendingSubmissions
+type SubmissionQueue struct {
+	// Items are the pending submissions, soonest auto-approval first and
+	// those with unknown deadlines last
+	Items []PendingSubmission
+	// Errors holds the error for each contract whose milestones could not
+	// be fetched
+	Errors map[models.ID]error
+}
+
+// ListPendingSubmissions returns the milestones awaiting review across the
+// active fixed-price contracts, with the time each is approved
+// automatically. Milestones are fetched in batch requests, a few in
+// flight at once; a contract whose milestones could not be fetched is
+// recorded in Errors rather than failing the whole queue. An error is
+// returned only if the contracts could not be listed.
+func (s *ContractsService) ListPendingSubmissions(ctx context.Context) (*SubmissionQueue, error) {
+	contracts, err := s.activeFixedPriceContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &SubmissionQueue{Errors: make(map[models.ID]error)}
+	if len(contracts) == 0 {
+		return queue, nil
+	}
+
+	// Fetch the milestones in batches, each goroutine recording its own
+	// contracts' milestones and errors
+	milestones := make([][]Milestone, len(contracts))
+	errs := make([]error, len(contracts))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, DefaultMaxConcurrency)
+	for start := 0; start < len(contracts); start += milestoneBatchSize {
+		end := min(start+milestoneBatchSize, len(contracts))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for i := start; i < len(contracts); i++ {
+				errs[i] = ctx.Err()
+			}
+			start = len(contracts)
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.fetchMilestones(ctx, contracts[start:end], milestones[start:end], errs[start:end])
+		}(start, end)
+	}
+	wg.Wait()
+
+	for i, c := range contracts {
+		if errs[i] != nil {
+			queue.Errors[c.ID] = errs[i]
+			continue
+		}
+		for _, m := range milestones[i] {
+			if m.State != MilestoneStateSubmitted {
+				continue
+			}
+			queue.Items = append(queue.Items, newPendingSubmission(c, m))
+		}
+	}
+
+	sort.SliceStable(queue.Items, func(i, j int) bool {
+		a, b := queue.Items[i].AutoApproveAt, queue.Items[j].AutoApproveAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return queue, nil
+}
+
+// activeFixedPriceContracts lists every active fixed-price contract
+func (s *ContractsService) activeFixedPriceContracts(ctx context.Context) ([]Contract, error) {
+	size := s.client.pageSize(ctx, pendingContractsPageSize)
+	input := ListContractsInput{
+		Pagination: &models.PaginationInput{First: size},
+		Filter: &ContractFilter{
+			Status:       []ContractStatus{ContractStatusActive},
+			ContractType: []ContractType{ContractTypeFixedPrice},
+		},
+	}
+
+	var contracts []Contract
+	for {
+		page, err := s.ListContracts(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range page.Edges {
+			contracts = append(contracts, edge.Node)
+		}
+
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		next := page.PageInfo.EndCursor
+		if !page.PageInfo.HasNextPage || next == "" || next == input.Pagination.After {
+			break
+		}
+		input.Pagination = &models.PaginationInput{First: size, After: next}
+	}
+	return contracts, nil
+}
+
+// fetchMilestones fetches the milestones of contracts in one batch
+// request, writing each contract's milestones or error to the same index
+// of milestones or errs
+func (s *ContractsService) fetchMilestones(ctx context.Context, contracts []Contract, milestones [][]Milestone, errs []error) {
+	requests := make([]*GraphQLRequest, len(contracts))
+	results := make([]interface{}, len(contracts))
+	responses := make([]struct {
+		Contract *struct {
+			Milestones []Milestone `json:"milestones"`
+		} `json:"contract"`
+	}, len(contracts))
+	for i, c := range contracts {
+		requests[i] = contractMilestonesRequest(string(c.ID))
+		results[i] = &responses[i]
+	}
+
+	batch, err := s.client.DoBatch(ctx, requests, results)
+	for i := range contracts {
+		switch {
+		case batch == nil:
+			errs[i] = err
+		case !batch.Succeeded(i):
+			errs[i] = batch.Errors[i]
+		case responses[i].Contract != nil:
+			milestones[i] = responses[i].Contract.Milestones
+		}
+	}
+}
+
+// contractMilestonesRequest builds the query for the milestones of a
+// contract and their submissions
+func contractMilestonesRequest(contractID string) *GraphQLRequest {
+	query := `
+		query GetContractMilestones($id: ID!) {
+			contract(id: $id) {
+				id
+				milestones {
+					id
+					description
+					instructions
+					dueDateTime
+					state
+					depositAmount {
+						rawValue
+						currency
+						displayValue
+					}
+					submissionCount
+					sequenceId
+					modifiedDateTime
+					submissionEvents {
+						submission {
+							id
+							createdDateTime
+							amount {
+								rawValue
+								currency
+								displayValue
+							}
+							sequenceId
+						}
+						submissionMessage {
+							createdDateTime
+							message
+						}
+					}
+				}
+			}
+		}
+	`
+
+	return &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"id": contractID,
+		},
+	}
+}
+
+// newPendingSubmission returns the pending submission of m on contract c.
+// It was submitted when its latest submission was created, or else when
+// it was last modified, which is when it moved to SUBMITTED.
+func newPendingSubmission(c Contract, m Milestone) PendingSubmission {
+	p := PendingSubmission{ContractID: c.ID, ContractTitle: c.Title, Milestone: m}
+
+	for _, event := range m.SubmissionEvents {
+		if event.Submission == nil {
+			continue
+		}
+		if t, err := event.Submission.CreatedDateTime.Time(); err == nil && t.After(p.SubmittedAt) {
+			p.SubmittedAt = t
+		}
+	}
+	if p.SubmittedAt.IsZero() {
+		if t, err := m.ModifiedDateTime.Time(); err == nil {
+			p.SubmittedAt = t
+		}
+	}
+	if !p.SubmittedAt.IsZero() {
+		p.AutoApproveAt = p.SubmittedAt.Add(MilestoneAutoApprovalPeriod)
+	}
+	return p
+}
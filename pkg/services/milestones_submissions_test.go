@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestListPendingSubmissions(t *testing.T) {
+	var filter map[string]interface{}
+	var pages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []GraphQLRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
+			// Not a batch, so a page of contracts
+			var req GraphQLRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			filter = req.Variables["filter"].(map[string]interface{})
+			pages++
+			if pages == 1 {
+				w.Write([]byte(`{"data":{"contractList":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"edges":[
+					{"node":{"id":"contract-1","title":"Website"}},
+					{"node":{"id":"contract-2","title":"Logo"}}
+				]}}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"contractList":{"pageInfo":{"hasNextPage":false,"endCursor":"c2"},"edges":[
+				{"node":{"id":"contract-3","title":"App"}}
+			]}}}`))
+			return
+		}
+
+		responses := make([]string, len(batch))
+		for i, req := range batch {
+			switch req.Variables["id"] {
+			case "contract-1":
+				responses[i] = `{"data":{"contract":{"id":"contract-1","milestones":[
+					{"id":"m-1","state":"SUBMITTED","modifiedDateTime":"2024-03-09T00:00:00Z","submissionEvents":[
+						{"submission":{"id":"s-2","createdDateTime":"2024-03-05T12:00:00Z"}},
+						{"submission":{"id":"s-1","createdDateTime":"2024-03-01T12:00:00Z"}},
+						{"revisionMessage":{"message":"more please"}}
+					]},
+					{"id":"m-2","state":"ACTIVE"}
+				]}}}`
+			case "contract-2":
+				responses[i] = `{"data":null,"errors":[{"message":"not allowed"}]}`
+			default:
+				responses[i] = `{"data":{"contract":{"id":"contract-3","milestones":[
+					{"id":"m-3","state":"SUBMITTED","modifiedDateTime":"2024-03-02T08:00:00Z"},
+					{"id":"m-4","state":"SUBMITTED"}
+				]}}}`
+			}
+		}
+		w.Write([]byte("[" + strings.Join(responses, ",") + "]"))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	queue, err := svc.ListPendingSubmissions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []interface{}{"ACTIVE"}, filter["status"])
+	assert.Equal(t, []interface{}{"FIXED_PRICE"}, filter["contractType"])
+
+	ids := make([]models.ID, len(queue.Items))
+	for i, item := range queue.Items {
+		ids[i] = item.Milestone.ID
+	}
+	assert.Equal(t, []models.ID{"m-3", "m-1", "m-4"}, ids, "soonest deadline first, unknown last")
+
+	first := queue.Items[0]
+	assert.Equal(t, models.ID("contract-3"), first.ContractID)
+	assert.Equal(t, "App", first.ContractTitle)
+	assert.Equal(t, time.Date(2024, 3, 16, 8, 0, 0, 0, time.UTC), first.AutoApproveAt, "submitted when last modified")
+
+	second := queue.Items[1]
+	assert.Equal(t, time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC), second.SubmittedAt, "the latest submission counts")
+	left, ok := second.TimeLeft(time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 24*time.Hour, left)
+
+	_, ok = queue.Items[2].TimeLeft(time.Now())
+	assert.False(t, ok)
+
+	require.Len(t, queue.Errors, 1)
+	assert.Contains(t, queue.Errors["contract-2"].Error(), "not allowed")
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// NotificationsService handles bell notification API operations
+type NotificationsService struct {
+	client *BaseClient
+}
+
+// NewNotificationsService creates a new notifications service
+func NewNotificationsService(client *BaseClient) *NotificationsService {
+	return &NotificationsService{client: client}
+}
+
+// NotificationType represents what a notification is about
+type NotificationType string
+
+const (
+	NotificationTypeInterviewInvitation NotificationType = "INTERVIEW_INVITATION"
+	NotificationTypeOffer               NotificationType = "OFFER"
+	NotificationTypeProposal            NotificationType = "PROPOSAL"
+	NotificationTypeContract            NotificationType = "CONTRACT"
+	NotificationTypeMilestone           NotificationType = "MILESTONE"
+	NotificationTypePayment             NotificationType = "PAYMENT"
+	NotificationTypeMessage             NotificationType = "MESSAGE"
+	NotificationTypeUnknown             NotificationType = "UNKNOWN"
+)
+
+// Notification represents a bell notification
+type Notification struct {
+	ID      models.ID        `json:"id"`
+	Type    NotificationType `json:"type"`
+	Title   string           `json:"title"`
+	Message string           `json:"message"`
+	// EntityID is the ID of what the notification is about, such as the
+	// offer, contract or milestone
+	EntityID        string          `json:"entityId"`
+	URL             string          `json:"url"`
+	Read            bool            `json:"read"`
+	CreatedDateTime models.DateTime `json:"createdDateTime"`
+}
+
+// NotificationFilter represents notification filtering options
+type NotificationFilter struct {
+	UnreadOnly bool               `json:"unreadOnly,omitempty"`
+	Types      []NotificationType `json:"types,omitempty"`
+}
+
+// ListNotificationsInput represents input for listing notifications
+type ListNotificationsInput struct {
+	Pagination *models.PaginationInput `json:"pagination,omitempty"`
+	Filter     *NotificationFilter     `json:"filter,omitempty"`
+}
+
+// NotificationList represents a paginated list of notifications, newest
+// first
+type NotificationList struct {
+	TotalCount int                `json:"totalCount"`
+	PageInfo   models.PageInfo    `json:"pageInfo"`
+	Edges      []NotificationEdge `json:"edges"`
+}
+
+// NotificationEdge represents a notification edge in pagination
+type NotificationEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   Notification `json:"node"`
+}
+
+// ListNotifications returns a list of the user's notifications, newest
+// first
+func (s *NotificationsService) ListNotifications(ctx context.Context, input ListNotificationsInput) (*NotificationList, error) {
+	if input.Filter != nil {
+		for _, t := range input.Filter.Types {
+			if err := validateEnum("filter.types", t, t.IsValid()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	query := `
+		query ListNotifications($pagination: Pagination, $filter: NotificationFilter) {
+			notificationList(pagination: $pagination, filter: $filter) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {
+						id
+						type
+						title
+						message
+						entityId
+						url
+						read
+						createdDateTime
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{}
+	if input.Pagination != nil {
+		variables["pagination"] = input.Pagination
+	}
+	if input.Filter != nil {
+		variables["filter"] = input.Filter
+	}
+
+	req := &GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	}
+
+	var resp struct {
+		NotificationList NotificationList `json:"notificationList"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.NotificationList, nil
+}
+
+// MarkNotificationRead marks a notification as read
+func (s *NotificationsService) MarkNotificationRead(ctx context.Context, notificationID string) error {
+	if err := required("notificationId", notificationID); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation MarkNotificationRead($id: ID!) {
+			markNotificationRead(id: $id) {
+				id
+				read
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"id": notificationID,
+		},
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Watch polls for new notifications every interval, or every
+// DefaultStreamPollInterval if it is zero. Notifications that already
+// exist when watching starts are not emitted. The returned channel is
+// closed when ctx is cancelled or the client is closed.
+func (s *NotificationsService) Watch(ctx context.Context, interval time.Duration) (<-chan Notification, error) {
+	return s.WatchWithOptions(ctx, StreamOptions{PollInterval: interval})
+}
+
+// WatchWithOptions polls for new notifications using the given options.
+// New notifications are emitted oldest first. Transient failures are
+// retried with exponential backoff.
+func (s *NotificationsService) WatchWithOptions(ctx context.Context, opts StreamOptions) (<-chan Notification, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultStreamPollInterval
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultStreamMaxBackoff
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultStreamPageSize
+	}
+
+	input := ListNotificationsInput{Pagination: &models.PaginationInput{First: opts.PageSize}}
+
+	// Establish the baseline so only new notifications are emitted
+	initial, err := s.ListNotifications(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[models.ID]struct{}, len(initial.Edges))
+	for _, edge := range initial.Edges {
+		seen[edge.Node.ID] = struct{}{}
+	}
+
+	out := make(chan Notification, opts.BufferSize)
+	ctx, cancel := s.client.workerContext(ctx)
+
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		delay := opts.PollInterval
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			list, err := s.ListNotifications(ctx, input)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+
+				delay *= 2
+				if delay > opts.MaxBackoff {
+					delay = opts.MaxBackoff
+				}
+				timer.Reset(delay)
+				continue
+			}
+			delay = opts.PollInterval
+
+			current := make(map[models.ID]struct{}, len(list.Edges))
+			for _, edge := range list.Edges {
+				current[edge.Node.ID] = struct{}{}
+			}
+
+			// The list is newest first
+			for i := len(list.Edges) - 1; i >= 0; i-- {
+				n := list.Edges[i].Node
+				if _, ok := seen[n.ID]; ok {
+					continue
+				}
+
+				select {
+				case out <- n:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Only remember the current window to keep memory bounded
+			seen = current
+			timer.Reset(delay)
+		}
+	}()
+
+	return out, nil
+}
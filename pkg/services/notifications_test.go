@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestNotifications(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "markNotificationRead"):
+			assert.Equal(t, "n-1", req.Variables["id"])
+			w.Write([]byte(`{"data":{"markNotificationRead":{"id":"n-1","read":true}}}`))
+		case strings.Contains(req.Query, "notificationList"):
+			// Each poll sees one more notification, newest first
+			n := atomic.AddInt32(&polls, 1)
+			edges := []string{`{"node":{"id":"n-1","type":"MESSAGE","read":false}}`}
+			if n >= 2 {
+				edges = append([]string{`{"node":{"id":"n-2","type":"OFFER","entityId":"offer-1"}}`}, edges...)
+			}
+			if n >= 3 {
+				edges = append([]string{`{"node":{"id":"n-3","type":"SOMETHING_NEW"}}`}, edges...)
+			}
+			w.Write([]byte(`{"data":{"notificationList":{"totalCount":1,"edges":[` + strings.Join(edges, ",") + `]}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewNotificationsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := svc.ListNotifications(ctx, ListNotificationsInput{Filter: &NotificationFilter{Types: []NotificationType{"BOGUS"}}})
+	assertValidationField(t, err, "filter.types")
+	assertValidationField(t, svc.MarkNotificationRead(ctx, ""), "notificationId")
+	require.NoError(t, svc.MarkNotificationRead(ctx, "n-1"))
+
+	notifications, err := svc.Watch(ctx, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	var got []Notification
+	for n := range notifications {
+		got = append(got, n)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+
+	require.Len(t, got, 2)
+	assert.Equal(t, models.ID("n-2"), got[0].ID)
+	assert.Equal(t, NotificationTypeOffer, got[0].Type)
+	assert.Equal(t, "offer-1", got[0].EntityID)
+	assert.Equal(t, models.ID("n-3"), got[1].ID)
+	assert.Equal(t, NotificationTypeUnknown, got[1].Type.Known())
+}
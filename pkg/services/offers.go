@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// CreateOfferInput represents input for sending a contract offer to a
+// freelancer. Exactly one of HourlyTerms and FixedPriceTerms must be set.
+type CreateOfferInput struct {
+	FreelancerID string `json:"freelancerId"`
+	// TeamID is the hiring team; the organization's default team is used
+	// if empty
+	TeamID string `json:"teamId,omitempty"`
+	// JobPostingID links the offer to a job posting. Offers without one
+	// are direct offers.
+	JobPostingID string `json:"jobPostingId,omitempty"`
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	Message      string `json:"message,omitempty"`
+	// StartDate is the contract start date as YYYY-MM-DD
+	StartDate string `json:"startDate,omitempty"`
+
+	HourlyTerms     *HourlyOfferTerms     `json:"hourlyTerms,omitempty"`
+	FixedPriceTerms *FixedPriceOfferTerms `json:"fixedPriceTerms,omitempty"`
+}
+
+// HourlyOfferTerms represents the terms of an hourly offer
+type HourlyOfferTerms struct {
+	HourlyRate models.Money `json:"hourlyRate"`
+	// WeeklyHoursLimit is nil for no limit
+	WeeklyHoursLimit  *int `json:"weeklyHoursLimit,omitempty"`
+	ManualTimeAllowed bool `json:"manualTimeAllowed,omitempty"`
+}
+
+// FixedPriceOfferTerms represents the terms of a fixed-price offer. The
+// contract budget is the sum of the milestones, the first of which is
+// funded when the offer is accepted.
+type FixedPriceOfferTerms struct {
+	Milestones []OfferMilestoneInput `json:"milestones"`
+}
+
+// OfferMilestoneInput represents a milestone created with a fixed-price
+// offer
+type OfferMilestoneInput struct {
+	Description   string       `json:"description"`
+	DepositAmount models.Money `json:"depositAmount"`
+	// DueDate is the milestone due date as YYYY-MM-DD
+	DueDate string `json:"dueDate,omitempty"`
+}
+
+// Validate checks the freelancer, title and terms
+func (in CreateOfferInput) Validate() error {
+	if err := firstError(
+		required("freelancerId", in.FreelancerID),
+		required("title", in.Title),
+		validateDate("startDate", in.StartDate),
+	); err != nil {
+		return err
+	}
+
+	switch {
+	case in.HourlyTerms != nil && in.FixedPriceTerms != nil:
+		return &errors.ValidationError{Field: "input", Message: "an offer cannot have both hourly and fixed-price terms"}
+	case in.HourlyTerms != nil:
+		return firstError(
+			validateMoney("hourlyTerms.hourlyRate", in.HourlyTerms.HourlyRate),
+			validateNonNegative("hourlyTerms.weeklyHoursLimit", in.HourlyTerms.WeeklyHoursLimit),
+		)
+	case in.FixedPriceTerms != nil:
+		return in.FixedPriceTerms.validate()
+	default:
+		return &errors.ValidationError{Field: "input", Message: "hourly or fixed-price terms are required"}
+	}
+}
+
+// validate checks that there is at least one milestone and that each has
+// a description, a positive amount in the same currency and a valid date
+func (t FixedPriceOfferTerms) validate() error {
+	if len(t.Milestones) == 0 {
+		return &errors.ValidationError{Field: "fixedPriceTerms.milestones", Message: "at least one milestone is required"}
+	}
+	for i, m := range t.Milestones {
+		field := fmt.Sprintf("fixedPriceTerms.milestones[%d]", i)
+		if err := firstError(
+			required(field+".description", m.Description),
+			validateMoney(field+".depositAmount", m.DepositAmount),
+			validateDate(field+".dueDate", m.DueDate),
+		); err != nil {
+			return err
+		}
+		if m.DepositAmount.Currency != t.Milestones[0].DepositAmount.Currency {
+			return &errors.ValidationError{
+				Field:   field + ".depositAmount.currency",
+				Message: "must match the currency of the first milestone",
+				Value:   m.DepositAmount.Currency,
+			}
+		}
+	}
+	return nil
+}
+
+// offerFields selects the fields of an Offer
+const offerFields = `
+	id
+	title
+	offerTerms {
+		hourlyTerm {
+			hourlyRate {
+				rawValue
+				currency
+				displayValue
+			}
+			weeklyHoursLimit
+		}
+		fixedPriceTerm {
+			budget {
+				rawValue
+				currency
+				displayValue
+			}
+		}
+	}
+`
+
+// SendOffer sends a contract offer to a freelancer. The contract starts
+// once the freelancer accepts it.
+func (s *ContractsService) SendOffer(ctx context.Context, input CreateOfferInput) (*Offer, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation CreateOffer($input: CreateOfferInput!) {
+			createOffer(input: $input) {` + offerFields + `}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		CreateOffer Offer `json:"createOffer"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CreateOffer, nil
+}
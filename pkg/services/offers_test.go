@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestCreateOfferContract(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		w.Write([]byte(`{"data":{"createOffer":{"id":"offer-1","title":"API client"}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	rate := models.MustMoney("75", "USD")
+	offer, err := svc.CreateContract(ctx, CreateContractInput{
+		FreelancerID: "freelancer-1",
+		Title:        "API client",
+		HourlyRate:   &rate,
+		WeeklyLimit:  intPtr(40),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("offer-1"), offer.ID)
+
+	_, err = svc.CreateContract(ctx, CreateContractInput{
+		FreelancerID: "freelancer-1",
+		Title:        "API client",
+		Milestones: []OfferMilestoneInput{
+			{Description: "Design", DepositAmount: models.MustMoney("500", "USD"), DueDate: "2024-07-01"},
+			{Description: "Build", DepositAmount: models.MustMoney("1500", "USD")},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 2)
+	hourly := reqs[0].Variables["input"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"hourlyRate":       map[string]interface{}{"rawValue": "75.00", "currency": "USD"},
+		"weeklyHoursLimit": 40.0,
+	}, hourly["hourlyTerms"])
+	assert.NotContains(t, hourly, "fixedPriceTerms")
+
+	fixed := reqs[1].Variables["input"].(map[string]interface{})
+	milestones := fixed["fixedPriceTerms"].(map[string]interface{})["milestones"].([]interface{})
+	require.Len(t, milestones, 2)
+	assert.Equal(t, "Build", milestones[1].(map[string]interface{})["description"])
+	assert.NotContains(t, fixed, "hourlyTerms")
+}
+
+func TestCreateOfferContractValidation(t *testing.T) {
+	svc := NewContractsService(&BaseClient{HTTPClient: http.DefaultClient, APIURL: "http://invalid"})
+	rate := models.MustMoney("75", "USD")
+
+	tests := []struct {
+		name  string
+		input CreateContractInput
+		field string
+	}{
+		{"missing title", CreateContractInput{FreelancerID: "freelancer-1", HourlyRate: &rate}, "title"},
+		{"no terms", CreateContractInput{FreelancerID: "freelancer-1", Title: "API client"}, "input"},
+		{"both terms", CreateContractInput{
+			FreelancerID: "freelancer-1",
+			Title:        "API client",
+			HourlyRate:   &rate,
+			Milestones:   []OfferMilestoneInput{{Description: "Design", DepositAmount: rate}},
+		}, "input"},
+		{"mixed currencies", CreateContractInput{
+			FreelancerID: "freelancer-1",
+			Title:        "API client",
+			Milestones: []OfferMilestoneInput{
+				{Description: "Design", DepositAmount: models.MustMoney("500", "USD")},
+				{Description: "Build", DepositAmount: models.MustMoney("500", "EUR")},
+			},
+		}, "fixedPriceTerms.milestones[1].depositAmount.currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateContract(context.Background(), tt.input)
+			assertValidationField(t, err, tt.field)
+		})
+	}
+}
+
+func TestUpdateOfferContract(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		fmt.Fprint(w, `{"data":{
+			"updateContractHourlyLimit":{"success":true},
+			"requestContractRateChange":{"id":"cr-1","status":"PENDING"},
+			"contract":{"id":"contract-1","weeklyHoursLimit":30}
+		}}`)
+	}))
+	defer server.Close()
+
+	svc := NewContractsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	rate := models.MustMoney("80", "USD")
+	contract, err := svc.UpdateContract(ctx, "contract-1", UpdateContractInput{WeeklyLimit: intPtr(30), HourlyRate: &rate})
+	require.NoError(t, err)
+	require.NotNil(t, contract.WeeklyHoursLimit)
+	assert.Equal(t, 30, *contract.WeeklyHoursLimit)
+
+	require.Len(t, reqs, 3)
+	assert.Equal(t, map[string]interface{}{"contractId": "contract-1", "weeklyHoursLimit": 30.0}, reqs[0].Variables["input"])
+	assert.Equal(t, "contract-1", reqs[1].Variables["input"].(map[string]interface{})["contractId"])
+	assert.Equal(t, "contract-1", reqs[2].Variables["id"])
+
+	// Invalid changes are rejected before either is applied
+	reqs = nil
+	_, err = svc.UpdateContract(ctx, "contract-1", UpdateContractInput{WeeklyLimit: intPtr(30), HourlyRate: &models.Money{}})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "hourlyRate", validationErr.Field)
+	assert.Empty(t, reqs)
+
+	_, err = svc.UpdateContract(ctx, "contract-1", UpdateContractInput{})
+	assertValidationField(t, err, "input")
+}
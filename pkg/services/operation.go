@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// OperationType is the kind of a GraphQL operation
+type OperationType string
+
+const (
+	OperationQuery        OperationType = "query"
+	OperationMutation     OperationType = "mutation"
+	OperationSubscription OperationType = "subscription"
+)
+
+// ParseOperationType returns the type of the operation named operationName
+// in query. operationName may be empty if the document has one operation.
+func ParseOperationType(query, operationName string) (OperationType, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return "", fmt.Errorf("operation %q not found", operationName)
+	}
+
+	return OperationType(op.Operation), nil
+}
+
+// retryable reports whether req may be retried after a transient failure.
+// Queries are always retryable. Mutations are not by default, since a retry
+// after a lost response can apply them twice, unless the client enables
+// RetryMutations or the request carries an idempotency key. WithRetry
+// overrides both.
+func (c *BaseClient) retryable(req *GraphQLRequest, options *requestOptions) bool {
+	if options.retry != nil {
+		return *options.retry
+	}
+
+	// Requests that cannot be classified are treated as mutations
+	if op := prepareQuery(req.Query).operation(req.OperationName); op != nil && op.Operation != ast.Mutation {
+		return true
+	}
+
+	return c.RetryMutations || options.header.Get(IdempotencyKeyHeader) != ""
+}
+
+// withOperationName returns req with OperationName set to the name of its
+// operation, for server-side logging and persisted queries. req is returned
+// as is if it already has one or the query has no single named operation.
+func withOperationName(req *GraphQLRequest) *GraphQLRequest {
+	if req.OperationName != "" {
+		return req
+	}
+	name := operationName(req)
+	if name == "" {
+		return req
+	}
+	named := *req
+	named.OperationName = name
+	return &named
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+	gqlparser "github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestParseOperationType(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		want          OperationType
+		wantErr       bool
+	}{
+		{name: "shorthand", query: "{ user { id } }", want: OperationQuery},
+		{name: "query", query: "query GetUser { user { id } }", want: OperationQuery},
+		{name: "mutation", query: "\n# comment\nmutation Pause($id: ID!) { pauseContract(contractId: $id) { success } }", want: OperationMutation},
+		{name: "subscription", query: "subscription { roomStory { id } }", want: OperationSubscription},
+		{
+			name:          "named operation",
+			query:         "query A { user { id } } mutation B { x }",
+			operationName: "B",
+			want:          OperationMutation,
+		},
+		{name: "ambiguous", query: "query A { a } query B { b }", wantErr: true},
+		{name: "invalid", query: "mutation {", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOperationType(tt.query, tt.operationName)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	query := &GraphQLRequest{Query: "query { user { id } }"}
+	mutation := &GraphQLRequest{Query: "mutation { sendMessage { id } }"}
+	invalid := &GraphQLRequest{Query: "not graphql"}
+
+	client := &BaseClient{}
+	assert.True(t, client.retryable(query, resolveOptions(context.Background(), nil)))
+	assert.False(t, client.retryable(mutation, resolveOptions(context.Background(), nil)))
+	assert.False(t, client.retryable(invalid, resolveOptions(context.Background(), nil)))
+
+	// Opt-ins for mutations
+	assert.True(t, client.retryable(mutation, resolveOptions(context.Background(), []RequestOption{WithRetry(true)})))
+	assert.True(t, client.retryable(mutation, resolveOptions(context.Background(), []RequestOption{WithIdempotencyKey("k")})))
+	assert.True(t, (&BaseClient{RetryMutations: true}).retryable(mutation, resolveOptions(context.Background(), nil)))
+
+	// WithRetry(false) disables retries for queries too
+	assert.False(t, client.retryable(query, resolveOptions(context.Background(), []RequestOption{WithRetry(false)})))
+}
+
+// failingTransport fails every request with a retryable error
+type failingTransport struct {
+	calls int32
+}
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return nil, errors.ErrServiceUnavailable
+}
+
+func TestDoDoesNotRetryMutations(t *testing.T) {
+	transport := &failingTransport{}
+	client := &BaseClient{HTTPClient: &http.Client{Transport: transport}, APIURL: "http://upwork.invalid/graphql"}
+
+	err := client.Do(context.Background(), &GraphQLRequest{Query: "mutation { sendMessage { id } }"}, nil)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&transport.calls))
+}
+
+func TestWithOperationName(t *testing.T) {
+	req := &GraphQLRequest{Query: "query GetUser { user { id } }"}
+	named := withOperationName(req)
+	assert.Equal(t, "GetUser", named.OperationName)
+	assert.Empty(t, req.OperationName)
+
+	explicit := &GraphQLRequest{Query: "query A { a } query B { b }", OperationName: "B"}
+	assert.Same(t, explicit, withOperationName(explicit))
+
+	for _, query := range []string{"{ user { id } }", "query A { a } query B { b }", "not graphql"} {
+		assert.Empty(t, withOperationName(&GraphQLRequest{Query: query}).OperationName, query)
+	}
+}
+
+func TestOperationNameSentAndObserved(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = append(sent, req.OperationName)
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	var events []RequestEvent
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		OnRequest:  func(e RequestEvent) { events = append(events, e) },
+	}
+
+	svc := NewUsersService(client)
+	_, err := svc.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, sent, 1)
+	assert.NotEmpty(t, sent[0])
+	require.Len(t, events, 1)
+	assert.Equal(t, sent[0], events[0].OperationName)
+	assert.NoError(t, events[0].Err)
+	assert.Greater(t, events[0].Duration, time.Duration(0))
+}
+
+// TestBuiltInOperationsNamed checks that every query, mutation and
+// subscription in the package source declares an operation name
+func TestBuiltInOperationsNamed(t *testing.T) {
+	paths, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+
+	// Queries are built from literals and package-level field lists
+	values := make(map[string]ast.Expr)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) == len(vs.Values) {
+					for i, name := range vs.Names {
+						values[name.Name] = vs.Values[i]
+					}
+				}
+			}
+		}
+	}
+
+	var checked int
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			query, ok := evalString(expr, values)
+			if !ok || !isOperation(query) {
+				return true
+			}
+
+			pos := fset.Position(expr.Pos())
+			doc, gqlErr := gqlparser.ParseQuery(&gqlast.Source{Input: query})
+			if !assert.Nil(t, gqlErr, "%s: query does not parse", pos) {
+				return false
+			}
+			for _, op := range doc.Operations {
+				assert.NotEmpty(t, op.Name, "%s: %s has no operation name", pos, op.Operation)
+				checked++
+			}
+			return false
+		})
+	}
+	assert.Greater(t, checked, 100)
+}
+
+// evalString returns the value of a constant string expression built from
+// literals, concatenation and the package-level values in values
+func evalString(expr ast.Expr, values map[string]ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.Ident:
+		if v, ok := values[e.Name]; ok {
+			return evalString(v, values)
+		}
+	case *ast.ParenExpr:
+		return evalString(e.X, values)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := evalString(e.X, values)
+		if !ok {
+			return "", false
+		}
+		y, ok := evalString(e.Y, values)
+		return x + y, ok
+	}
+	return "", false
+}
+
+// isOperation returns true if s starts with an operation keyword
+func isOperation(s string) bool {
+	s = strings.TrimSpace(s)
+	for _, keyword := range []string{"query", "mutation", "subscription"} {
+		if rest, ok := strings.CutPrefix(s, keyword); ok && rest != "" && strings.ContainsRune(" \t\n({", rune(rest[0])) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header set by WithIdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RequestOption customizes a single API request
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the resolved options for a request
+type requestOptions struct {
+	header   http.Header
+	timeout  time.Duration
+	retry    *bool
+	pageSize int
+	maxItems int
+	cost     int
+	// actingUser is set by WithActingUser
+	actingUser *string
+	// staleWindow overrides StaleWhileError if non-zero, disabling it if
+	// negative
+	staleWindow  time.Duration
+	freshness    *Freshness
+	responseInfo *ResponseInfo
+}
+
+// WithHeader sets a header on the request, replacing any value set by an
+// earlier option or by the client, such as Accept
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// WithTimeout bounds the request, including rate limiting and retries, to d
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header so a retried mutation
+// is applied at most once
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(IdempotencyKeyHeader, key)
+	}
+}
+
+// WithRetry overrides whether the request is retried after a transient
+// failure. By default queries are retried and mutations are not.
+func WithRetry(enabled bool) RequestOption {
+	return func(o *requestOptions) {
+		o.retry = &enabled
+	}
+}
+
+// WithPageSize sets the number of items requested per page by methods that
+// pick a page size themselves, overriding BaseClient.DefaultPageSize. A page
+// size passed in a method's input still takes precedence.
+func WithPageSize(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.pageSize = n
+	}
+}
+
+// WithMaxItems caps the number of items methods that walk every page
+// return, overriding BaseClient.MaxItems. Zero removes the cap.
+func WithMaxItems(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxItems = n
+		if n == 0 {
+			o.maxItems = -1
+		}
+	}
+}
+
+// requestOptionsKey is the context key for request options
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context that applies opts to every request
+// made with it. This passes options through service methods, which do not
+// take them directly. Options given to Do are applied after these.
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	existing, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	combined := append(append([]RequestOption(nil), existing...), opts...)
+	return context.WithValue(ctx, requestOptionsKey{}, combined)
+}
+
+// resolveOptions applies the options from ctx followed by opts
+func resolveOptions(ctx context.Context, opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	if fromCtx, ok := ctx.Value(requestOptionsKey{}).([]RequestOption); ok {
+		for _, opt := range fromCtx {
+			opt(o)
+		}
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// apply sets the option headers on req
+func (o *requestOptions) apply(req *http.Request) {
+	for key, values := range o.header {
+		req.Header.Del(key)
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRequestOptions(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+	req := &GraphQLRequest{Query: "mutation { ping }"}
+
+	ctx := WithRequestOptions(context.Background(), WithHeader("X-Trace-Id", "trace-1"), WithHeader("X-Caller", "ctx"))
+	err := client.Do(ctx, req, nil,
+		WithIdempotencyKey("key-1"),
+		WithHeader("X-Caller", "call"),
+		WithHeader("Accept", "application/graphql-response+json"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "trace-1", header.Get("X-Trace-Id"))
+	assert.Equal(t, "key-1", header.Get(IdempotencyKeyHeader))
+	assert.Equal(t, []string{"call"}, header.Values("X-Caller"))
+	assert.Equal(t, "application/graphql-response+json", header.Get("Accept"))
+
+	// Options do not leak into later requests
+	require.NoError(t, client.Do(context.Background(), req, nil))
+	assert.Empty(t, header.Get(IdempotencyKeyHeader))
+	assert.Empty(t, header.Get("X-Trace-Id"))
+}
+
+func TestDoWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	start := time.Now()
+	err := client.Do(context.Background(), &GraphQLRequest{Query: "{ slow }"}, nil, WithTimeout(50*time.Millisecond))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// StaffRole represents the role of a staff member in an organization
+type StaffRole string
+
+const (
+	StaffRoleAdmin          StaffRole = "ADMIN"
+	StaffRoleHiringManager  StaffRole = "HIRING_MANAGER"
+	StaffRoleFinancialAdmin StaffRole = "FINANCIAL_ADMIN"
+	StaffRoleTeamMember     StaffRole = "TEAM_MEMBER"
+	StaffRoleUnknown        StaffRole = "UNKNOWN"
+)
+
+// CreateTeamInput represents input for creating a team
+type CreateTeamInput struct {
+	// ParentOrganizationID is the organization the team is created in
+	ParentOrganizationID string `json:"parentOrganizationId"`
+	Name                 string `json:"name"`
+	Description          string `json:"description,omitempty"`
+}
+
+// UpdateTeamInput represents changes to a team. Nil fields are left
+// unchanged and are not sent.
+type UpdateTeamInput struct {
+	TeamID      string  `json:"teamId"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// SetName renames the team
+func (in *UpdateTeamInput) SetName(name string) *UpdateTeamInput {
+	in.Name = &name
+	return in
+}
+
+// SetDescription changes the description. An empty description clears it.
+func (in *UpdateTeamInput) SetDescription(description string) *UpdateTeamInput {
+	in.Description = &description
+	return in
+}
+
+// UpdateStaffRoleInput represents input for changing a staff member's role
+type UpdateStaffRoleInput struct {
+	OrganizationID string    `json:"organizationId"`
+	UserID         string    `json:"userId"`
+	Role           StaffRole `json:"role"`
+}
+
+// Validate checks that the organization and name are set
+func (in CreateTeamInput) Validate() error {
+	return firstError(
+		required("parentOrganizationId", in.ParentOrganizationID),
+		required("name", in.Name),
+	)
+}
+
+// Validate checks that the team is set, that something is being changed
+// and that any new name is not blank
+func (in UpdateTeamInput) Validate() error {
+	if err := required("teamId", in.TeamID); err != nil {
+		return err
+	}
+	if in.Name == nil && in.Description == nil {
+		return &errors.ValidationError{Field: "input", Message: "at least one change is required"}
+	}
+	if in.Name != nil {
+		return required("name", *in.Name)
+	}
+	return nil
+}
+
+// Validate checks the organization and user and that the role is known
+func (in UpdateStaffRoleInput) Validate() error {
+	return firstError(
+		required("organizationId", in.OrganizationID),
+		required("userId", in.UserID),
+		required("role", string(in.Role)),
+		validateEnum("role", in.Role, in.Role.IsValid()),
+	)
+}
+
+// CreateTeam creates a team in an organization
+func (s *UsersService) CreateTeam(ctx context.Context, input CreateTeamInput) (*models.Team, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation CreateTeam($input: CreateTeamInput!) {
+			createTeam(input: $input) {
+				id
+				rid
+				name
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		CreateTeam models.Team `json:"createTeam"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.CreateTeam, nil
+}
+
+// UpdateTeam renames a team or changes its description
+func (s *UsersService) UpdateTeam(ctx context.Context, input UpdateTeamInput) (*models.Team, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	mutation := `
+		mutation UpdateTeam($input: UpdateTeamInput!) {
+			updateTeam(input: $input) {
+				id
+				rid
+				name
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		UpdateTeam models.Team `json:"updateTeam"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.UpdateTeam, nil
+}
+
+// RemoveStaff deactivates a staff member of an organization. Their contracts
+// and history are kept.
+func (s *UsersService) RemoveStaff(ctx context.Context, organizationID, userID string) error {
+	mutation := `
+		mutation RemoveStaff($organizationId: ID!, $userId: ID!) {
+			removeStaff(organizationId: $organizationId, userId: $userId) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"organizationId": organizationID,
+			"userId":         userID,
+		},
+	}
+
+	var resp struct {
+		RemoveStaff struct {
+			Success bool `json:"success"`
+		} `json:"removeStaff"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.RemoveStaff.Success {
+		return fmt.Errorf("failed to remove staff")
+	}
+
+	return nil
+}
+
+// UpdateStaffRole changes the role of a staff member
+func (s *UsersService) UpdateStaffRole(ctx context.Context, input UpdateStaffRoleInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	mutation := `
+		mutation UpdateStaffRole($input: UpdateStaffRoleInput!) {
+			updateStaffRole(input: $input) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	}
+
+	var resp struct {
+		UpdateStaffRole struct {
+			Success bool `json:"success"`
+		} `json:"updateStaffRole"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.UpdateStaffRole.Success {
+		return fmt.Errorf("failed to update staff role")
+	}
+
+	return nil
+}
+
+// ResendInvitation sends a pending team invitation again
+func (s *UsersService) ResendInvitation(ctx context.Context, invitationID string) error {
+	mutation := `
+		mutation ResendInvitation($invitationId: ID!) {
+			resendInvitation(invitationId: $invitationId) {
+				success
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: mutation,
+		Variables: map[string]interface{}{
+			"invitationId": invitationID,
+		},
+	}
+
+	var resp struct {
+		ResendInvitation struct {
+			Success bool `json:"success"`
+		} `json:"resendInvitation"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.ResendInvitation.Success {
+		return fmt.Errorf("failed to resend invitation")
+	}
+
+	return nil
+}
+
+// StaffPermission is a permission granted to a staff member beyond their
+// role
+type StaffPermission string
+
+const (
+	StaffPermissionHire      StaffPermission = "HIRE"
+	StaffPermissionFinancial StaffPermission = "FINANCIAL"
+	StaffPermissionAdmin     StaffPermission = "ADMIN"
+)
+
+// StaffPermissions describes what a staff member can do in an organization.
+// The flags combine the member's role with any permissions granted beyond
+// it, and are all false for members who are not active.
+type StaffPermissions struct {
+	OrganizationID   string
+	UserID           string
+	Role             StaffRole
+	ActivationStatus string
+	// Permissions are the permissions granted beyond the role
+	Permissions []StaffPermission
+	// Hiring allows posting jobs, making offers and managing contracts
+	Hiring bool
+	// Financial allows viewing and making payments
+	Financial bool
+	// Admin allows managing staff and the organization, and implies the
+	// other permissions
+	Admin bool
+}
+
+// Active returns true if the staff member's membership is active
+func (p *StaffPermissions) Active() bool {
+	return strings.EqualFold(p.ActivationStatus, "ACTIVE")
+}
+
+// GetStaffPermissions returns what a staff member can do in an organization,
+// so apps can gate their own features on it
+func (s *UsersService) GetStaffPermissions(ctx context.Context, organizationID, userID string) (*StaffPermissions, error) {
+	if err := firstError(
+		required("organizationId", organizationID),
+		required("userId", userID),
+	); err != nil {
+		return nil, err
+	}
+
+	query := `
+		query GetStaffPermissions($organizationId: ID!, $userId: ID!) {
+			staffPermissions(organizationId: $organizationId, userId: $userId) {
+				role
+				activationStatus
+				permissions
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"organizationId": organizationID,
+			"userId":         userID,
+		},
+	}
+
+	var resp struct {
+		StaffPermissions *struct {
+			Role             StaffRole         `json:"role"`
+			ActivationStatus string            `json:"activationStatus"`
+			Permissions      []StaffPermission `json:"permissions"`
+		} `json:"staffPermissions"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StaffPermissions == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	perms := &StaffPermissions{
+		OrganizationID:   organizationID,
+		UserID:           userID,
+		Role:             resp.StaffPermissions.Role.Known(),
+		ActivationStatus: resp.StaffPermissions.ActivationStatus,
+		Permissions:      resp.StaffPermissions.Permissions,
+	}
+	if !perms.Active() {
+		return perms, nil
+	}
+
+	perms.Admin = perms.Role == StaffRoleAdmin || perms.has(StaffPermissionAdmin)
+	perms.Hiring = perms.Admin || perms.Role == StaffRoleHiringManager || perms.has(StaffPermissionHire)
+	perms.Financial = perms.Admin || perms.Role == StaffRoleFinancialAdmin || perms.has(StaffPermissionFinancial)
+	return perms, nil
+}
+
+// has returns true if permission was granted beyond the role
+func (p *StaffPermissions) has(permission StaffPermission) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
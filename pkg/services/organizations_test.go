@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestCreateTeam(t *testing.T) {
+	var req GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		fmt.Fprint(w, `{"data":{"createTeam":{"id":"team-1","name":"Design"}}}`)
+	}))
+	defer server.Close()
+
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	team, err := svc.CreateTeam(context.Background(), CreateTeamInput{ParentOrganizationID: "org-1", Name: "Design"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ID("team-1"), team.ID)
+	assert.Equal(t, map[string]interface{}{"parentOrganizationId": "org-1", "name": "Design"}, req.Variables["input"])
+
+	_, err = svc.CreateTeam(context.Background(), CreateTeamInput{ParentOrganizationID: "org-1"})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "name", validationErr.Field)
+}
+
+func TestStaffMutations(t *testing.T) {
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		fmt.Fprint(w, `{"data":{
+			"removeStaff":{"success":true},
+			"updateStaffRole":{"success":true},
+			"resendInvitation":{"success":false}
+		}}`)
+	}))
+	defer server.Close()
+
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	require.NoError(t, svc.RemoveStaff(ctx, "org-1", "user-2"))
+	require.NoError(t, svc.UpdateStaffRole(ctx, UpdateStaffRoleInput{OrganizationID: "org-1", UserID: "user-2", Role: StaffRoleHiringManager}))
+	assert.EqualError(t, svc.ResendInvitation(ctx, "invite-1"), "failed to resend invitation")
+
+	require.Len(t, reqs, 3)
+	assert.Equal(t, map[string]interface{}{"organizationId": "org-1", "userId": "user-2"}, reqs[0].Variables)
+	assert.Equal(t, "HIRING_MANAGER", reqs[1].Variables["input"].(map[string]interface{})["role"])
+	assert.Equal(t, "invite-1", reqs[2].Variables["invitationId"])
+}
+
+func TestGetStaffPermissions(t *testing.T) {
+	responses := map[string]string{
+		"admin":    `{"role": "ADMIN", "activationStatus": "ACTIVE", "permissions": []}`,
+		"hiring":   `{"role": "HIRING_MANAGER", "activationStatus": "ACTIVE", "permissions": ["financial"]}`,
+		"member":   `{"role": "TEAM_MEMBER", "activationStatus": "ACTIVE", "permissions": null}`,
+		"inactive": `{"role": "ADMIN", "activationStatus": "INACTIVE", "permissions": []}`,
+		"missing":  `null`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "org-1", req.Variables["organizationId"])
+		fmt.Fprintf(w, `{"data":{"staffPermissions":%s}}`, responses[req.Variables["userId"].(string)])
+	}))
+	defer server.Close()
+
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+
+	tests := []struct {
+		userID                   string
+		hiring, financial, admin bool
+	}{
+		{"admin", true, true, true},
+		{"hiring", true, true, false},
+		{"member", false, false, false},
+		{"inactive", false, false, false},
+	}
+	for _, tt := range tests {
+		perms, err := svc.GetStaffPermissions(ctx, "org-1", tt.userID)
+		require.NoError(t, err, tt.userID)
+		assert.Equal(t, tt.hiring, perms.Hiring, tt.userID)
+		assert.Equal(t, tt.financial, perms.Financial, tt.userID)
+		assert.Equal(t, tt.admin, perms.Admin, tt.userID)
+	}
+
+	_, err := svc.GetStaffPermissions(ctx, "org-1", "missing")
+	assert.True(t, stderrors.Is(err, errors.ErrNotFound))
+
+	_, err = svc.GetStaffPermissions(ctx, "org-1", "")
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "userId", validationErr.Field)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// organizationStaffPageSize is the default number of staff members
+// requested per page
+const organizationStaffPageSize = 50
+
+// SkipChildren is returned by a WalkOrganizations callback to skip the
+// child organizations of the organization it was called with
+var SkipChildren = stderrors.New("skip child organizations")
+
+// OrganizationNode is an organization reached by WalkOrganizations
+type OrganizationNode struct {
+	models.Organization
+	// ParentID is the ID of the organization this one was reached from,
+	// empty for the companies the user has access to
+	ParentID models.ID
+	// Depth is the number of organizations between this one and its
+	// company, zero for companies
+	Depth int
+
+	service *UsersService
+}
+
+// Staff returns an iterator over the staff members of the organization,
+// fetching pages as it goes
+func (n *OrganizationNode) Staff(ctx context.Context) *Iterator[models.Staff] {
+	orgID := string(n.ID)
+	cursor := ""
+	return newIterator(ctx, n.service.client, nil, true, func(ctx context.Context) ([]models.Staff, bool, error) {
+		page, err := n.service.getOrganizationStaffPage(ctx, orgID, cursor)
+		if err != nil {
+			return nil, false, err
+		}
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		more := page.PageInfo.HasNextPage && page.PageInfo.EndCursor != "" && page.PageInfo.EndCursor != cursor
+		cursor = page.PageInfo.EndCursor
+
+		staff := make([]models.Staff, 0, len(page.Edges))
+		for _, edge := range page.Edges {
+			staff = append(staff, edge.Node)
+		}
+		return staff, more, nil
+	})
+}
+
+// OrganizationSummary identifies an organization in the tree returned by
+// FlattenOrganizations
+type OrganizationSummary struct {
+	ID       models.ID
+	Title    string
+	ParentID models.ID
+	Depth    int
+}
+
+// WalkOrganizations calls fn for each organization the user has access to:
+// each company in the company selector, then its child organizations depth
+// first. Organizations are fetched as the walk reaches them, one request
+// each, and visited once even if reachable from several companies.
+// Returning SkipChildren from fn skips the children of that organization;
+// any other error stops the walk and is returned.
+func (s *UsersService) WalkOrganizations(ctx context.Context, fn func(org *OrganizationNode) error) error {
+	companies, err := s.GetCompanySelector(ctx)
+	if err != nil {
+		return err
+	}
+
+	visited := make(map[models.ID]bool)
+	for _, company := range companies {
+		if err := s.walkOrganization(ctx, models.ID(company.OrganizationID), "", 0, visited, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkOrganization fetches the organization orgID and walks it and its
+// children
+func (s *UsersService) walkOrganization(ctx context.Context, orgID, parentID models.ID, depth int, visited map[models.ID]bool, fn func(org *OrganizationNode) error) error {
+	if orgID == "" || visited[orgID] {
+		return nil
+	}
+	visited[orgID] = true
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	org, err := s.GetOrganization(WithOrganization(ctx, string(orgID)))
+	if err != nil {
+		return errors.WrapError(err, "failed to fetch organization "+string(orgID))
+	}
+	// Some organizations omit their ID when fetched as the tenant
+	if org.ID == "" {
+		org.ID = orgID
+	}
+
+	node := &OrganizationNode{Organization: *org, ParentID: parentID, Depth: depth, service: s}
+	if err := fn(node); err != nil {
+		if stderrors.Is(err, SkipChildren) {
+			return nil
+		}
+		return err
+	}
+
+	for _, child := range org.ChildOrganizations {
+		if err := s.walkOrganization(ctx, child.ID, org.ID, depth+1, visited, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlattenOrganizations returns every organization the user has access to,
+// in the order WalkOrganizations visits them
+func (s *UsersService) FlattenOrganizations(ctx context.Context) ([]OrganizationSummary, error) {
+	var orgs []OrganizationSummary
+	err := s.WalkOrganizations(ctx, func(org *OrganizationNode) error {
+		orgs = append(orgs, OrganizationSummary{
+			ID:       org.ID,
+			Title:    org.Name,
+			ParentID: org.ParentID,
+			Depth:    org.Depth,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// organizationStaffPage is a page of the staff of an organization
+type organizationStaffPage struct {
+	PageInfo models.PageInfo `json:"pageInfo"`
+	Edges    []struct {
+		Node models.Staff `json:"node"`
+	} `json:"edges"`
+}
+
+// getOrganizationStaffPage fetches the page of the staff of orgID after
+// cursor
+func (s *UsersService) getOrganizationStaffPage(ctx context.Context, orgID, cursor string) (*organizationStaffPage, error) {
+	query := `
+		query GetOrganizationStaff($pagination: Pagination) {
+			organization {
+				staffs(pagination: $pagination) {
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+					edges {
+						node {
+							user {
+								id
+								name
+								publicUrl
+							}
+							staffType
+							activationStatus
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"pagination": models.PaginationInput{First: s.client.pageSize(ctx, organizationStaffPageSize), After: cursor},
+		},
+	}
+
+	var resp struct {
+		Organization struct {
+			Staffs organizationStaffPage `json:"staffs"`
+		} `json:"organization"`
+	}
+
+	if err := s.client.Do(WithOrganization(ctx, orgID), req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Organization.Staffs, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// newOrganizationTreeServer serves two companies, org-a with children
+// org-a1 and org-a2, and org-b, which is also a child of org-a1. org-a has
+// three staff members served two per page. It records the tenant of each
+// organization query.
+func newOrganizationTreeServer(t *testing.T, fetched *[]string) *httptest.Server {
+	children := map[string][]string{
+		"org-a":  {"org-a1", "org-a2"},
+		"org-a1": {"org-b"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		tenant := r.Header.Get("X-Upwork-API-TenantId")
+
+		switch {
+		case strings.Contains(req.Query, "companySelector"):
+			w.Write([]byte(`{"data": {"companySelector": {"items": [
+				{"title": "A", "organizationId": "org-a"},
+				{"title": "B", "organizationId": "org-b"}
+			]}}}`))
+		case strings.Contains(req.Query, "staffs"):
+			pagination := req.Variables["pagination"].(map[string]interface{})
+			if pagination["after"] == nil {
+				w.Write([]byte(`{"data": {"organization": {"staffs": {
+					"pageInfo": {"hasNextPage": true, "endCursor": "2"},
+					"edges": [{"node": {"user": {"id": "u1"}}}, {"node": {"user": {"id": "u2"}}}]
+				}}}}`))
+				return
+			}
+			w.Write([]byte(`{"data": {"organization": {"staffs": {
+				"pageInfo": {"hasNextPage": false, "endCursor": "3"},
+				"edges": [{"node": {"user": {"id": "u3"}}}]
+			}}}}`))
+		default:
+			*fetched = append(*fetched, tenant)
+			var childOrgs []string
+			for _, id := range children[tenant] {
+				childOrgs = append(childOrgs, fmt.Sprintf(`{"id": %q, "name": %q}`, id, strings.ToUpper(id)))
+			}
+			fmt.Fprintf(w, `{"data": {"organization": {"id": %q, "name": %q, "childOrganizations": [%s]}}}`,
+				tenant, strings.ToUpper(tenant), strings.Join(childOrgs, ","))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFlattenOrganizations(t *testing.T) {
+	var fetched []string
+	server := newOrganizationTreeServer(t, &fetched)
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	orgs, err := svc.FlattenOrganizations(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []OrganizationSummary{
+		{ID: "org-a", Title: "ORG-A"},
+		{ID: "org-a1", Title: "ORG-A1", ParentID: "org-a", Depth: 1},
+		{ID: "org-b", Title: "ORG-B", ParentID: "org-a1", Depth: 2},
+		{ID: "org-a2", Title: "ORG-A2", ParentID: "org-a", Depth: 1},
+	}, orgs)
+	assert.Equal(t, []string{"org-a", "org-a1", "org-b", "org-a2"}, fetched, "each organization is fetched once")
+}
+
+func TestWalkOrganizationsSkipChildren(t *testing.T) {
+	var fetched []string
+	server := newOrganizationTreeServer(t, &fetched)
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	var visited []models.ID
+	err := svc.WalkOrganizations(context.Background(), func(org *OrganizationNode) error {
+		visited = append(visited, org.ID)
+		if org.ID == "org-a" {
+			return SkipChildren
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.ID{"org-a", "org-b"}, visited)
+	assert.Equal(t, []string{"org-a", "org-b"}, fetched, "skipped organizations are not fetched")
+}
+
+func TestOrganizationNodeStaff(t *testing.T) {
+	var fetched []string
+	server := newOrganizationTreeServer(t, &fetched)
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	var staff []models.ID
+	err := svc.WalkOrganizations(context.Background(), func(org *OrganizationNode) error {
+		it := org.Staff(context.Background())
+		defer it.Close()
+		for it.Next() {
+			staff = append(staff, it.Item().User.ID)
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return SkipChildren
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.ID{"u1", "u2", "u3", "u1", "u2", "u3"}, staff)
+}
@@ -0,0 +1,38 @@
+package services
+
+import "context"
+
+// pageSize returns the page size for a request that has not set one: the
+// WithPageSize option, else DefaultPageSize, else fallback
+func (c *BaseClient) pageSize(ctx context.Context, fallback int) int {
+	if size := resolveOptions(ctx, nil).pageSize; size > 0 {
+		return size
+	}
+	if c.DefaultPageSize > 0 {
+		return c.DefaultPageSize
+	}
+	return fallback
+}
+
+// maxItems returns the cap on items walked across pages, from the
+// WithMaxItems option or else MaxItems. Zero means no cap.
+func (c *BaseClient) maxItems(ctx context.Context) int {
+	switch n := resolveOptions(ctx, nil).maxItems; {
+	case n < 0:
+		return 0
+	case n > 0:
+		return n
+	}
+	if c.MaxItems > 0 {
+		return c.MaxItems
+	}
+	return 0
+}
+
+// capItems truncates items to limit, if there is one
+func capItems[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
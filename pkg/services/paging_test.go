@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestPageSize(t *testing.T) {
+	var firsts []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		firsts = append(firsts, req.Variables["first"])
+		w.Write([]byte(`{"data":{"contractTimeReport":{"edges":[]}}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+	svc := NewReportsService(client)
+	ctx := context.Background()
+	input := TimeReportInput{OrganizationID: "org-1"}
+
+	_, err := svc.GetTimeReport(ctx, input)
+	require.NoError(t, err)
+
+	client.DefaultPageSize = 20
+	_, err = svc.GetTimeReport(ctx, input)
+	require.NoError(t, err)
+
+	_, err = svc.GetTimeReport(WithRequestOptions(ctx, WithPageSize(5)), input)
+	require.NoError(t, err)
+
+	input.Pagination = &models.PaginationInput{First: 75}
+	_, err = svc.GetTimeReport(WithRequestOptions(ctx, WithPageSize(5)), input)
+	require.NoError(t, err)
+
+	input.Pagination = &models.PaginationInput{After: "cursor-1"}
+	_, err = svc.GetTimeReport(ctx, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{50.0, 20.0, 5.0, 75.0, 20.0}, firsts)
+}
+
+func TestSearchJobsAllMaxItems(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests++
+
+		filter := req.Variables["filter"].(map[string]interface{})
+		pagination := filter["pagination_eq"].(map[string]interface{})
+		assert.Equal(t, 2.0, pagination["first"])
+
+		// Every page points at another one
+		var edges []string
+		for i := 0; i < 2; i++ {
+			edges = append(edges, fmt.Sprintf(`{"node":{"id":"job-%d-%d"}}`, requests, i))
+		}
+		fmt.Fprintf(w, `{"data":{"marketplaceJobPostings":{"pageInfo":{"hasNextPage":true,"endCursor":"page-%d"},"edges":[%s]}}}`,
+			requests, strings.Join(edges, ","))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, DefaultPageSize: 2, MaxItems: 5}
+	svc := NewJobsService(client)
+	ctx := context.Background()
+
+	page, err := svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+	jobs, err := page.All(ctx)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 5)
+	assert.Equal(t, models.ID("job-3-0"), jobs[4].ID)
+	assert.Equal(t, 3, requests)
+
+	requests = 0
+	ctx = WithRequestOptions(ctx, WithMaxItems(3))
+	page, err = svc.SearchJobs(ctx, MarketplaceJobFilter{SearchExpression: "golang"})
+	require.NoError(t, err)
+	jobs, err = page.All(ctx)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, 2, requests)
+}
+
+func TestStreamAuditLogMaxItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"organizationAuditLog":{"pageInfo":{"hasNextPage":false},"edges":[
+			{"node":{"id":"e-1"}},{"node":{"id":"e-2"}},{"node":{"id":"e-3"}}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL, MaxItems: 2})
+	dateRange := models.DateRange{
+		Start: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	var streamed int
+	count := func(AuditEvent) error {
+		streamed++
+		return nil
+	}
+
+	require.NoError(t, svc.StreamAuditLog(context.Background(), "org-1", dateRange, count))
+	assert.Equal(t, 2, streamed)
+
+	streamed = 0
+	ctx := WithRequestOptions(context.Background(), WithMaxItems(0))
+	require.NoError(t, svc.StreamAuditLog(ctx, "org-1", dateRange, count))
+	assert.Equal(t, 3, streamed)
+}
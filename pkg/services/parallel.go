@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxConcurrency is the number of in-flight requests used by
+// ParallelDo when no limit is given
+const DefaultMaxConcurrency = 4
+
+// ParallelDo executes requests concurrently as individual HTTP requests, for
+// operations that cannot be batched server-side. At most maxConcurrency
+// requests are in flight at once and every request passes through the
+// client's shared rate limiter. Results are written to results[i] for
+// requests[i]. As with DoBatch, successful results are populated even if
+// other requests fail and the returned error is BatchResult.Err().
+func ParallelDo(ctx context.Context, client *BaseClient, requests []*GraphQLRequest, results []interface{}, maxConcurrency int) (*BatchResult, error) {
+	if len(requests) != len(results) {
+		return nil, fmt.Errorf("requests and results arrays must have the same length")
+	}
+
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+
+	var wg sync.WaitGroup
+	result := &BatchResult{Errors: make([]error, len(requests))}
+	sem := make(chan struct{}, maxConcurrency)
+
+	for i, req := range requests {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			// Record the remaining requests as cancelled without sending them
+			for j := i; j < len(requests); j++ {
+				result.Errors[j] = ctx.Err()
+			}
+			wg.Wait()
+			return result, result.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, req *GraphQLRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Each goroutine owns a distinct index, so no locking is needed
+			result.Errors[i] = client.Do(ctx, req, results[i])
+		}(i, req)
+	}
+
+	wg.Wait()
+
+	return result, result.Err()
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestParallelDo(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		n := int(req.Variables["n"].(float64))
+		if n%3 == 0 {
+			fmt.Fprintf(w, `{"errors":[{"message":"failed %d"}]}`, n)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"n":%d}}`, n)
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	const count = 10
+	requests := make([]*GraphQLRequest, count)
+	results := make([]interface{}, count)
+	values := make([]struct {
+		N int `json:"n"`
+	}, count)
+	for i := range requests {
+		requests[i] = &GraphQLRequest{Query: "query { n }", Variables: map[string]interface{}{"n": i}}
+		results[i] = &values[i]
+	}
+
+	result, err := ParallelDo(context.Background(), client, requests, results, 2)
+	require.Error(t, err)
+	assert.Equal(t, []int{0, 3, 6, 9}, result.Failed())
+	assert.True(t, result.Succeeded(1))
+
+	var multi *errors.MultiError
+	require.True(t, stderrors.As(err, &multi))
+	require.Len(t, multi.Errors, 4)
+	for i, e := range multi.Errors {
+		assert.Equal(t, i*3, e.Index)
+	}
+
+	for i, v := range values {
+		if i%3 == 0 {
+			assert.Zero(t, v.N)
+			continue
+		}
+		assert.Equal(t, i, v.N)
+	}
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestParallelDoLengthMismatch(t *testing.T) {
+	_, err := ParallelDo(context.Background(), &BaseClient{}, make([]*GraphQLRequest, 2), make([]interface{}, 1), 1)
+	assert.Error(t, err)
+}
+
+func TestParallelDoCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := []*GraphQLRequest{{Query: "query { a }"}, {Query: "query { b }"}}
+	_, err := ParallelDo(ctx, &BaseClient{HTTPClient: http.DefaultClient, APIURL: "http://127.0.0.1:0"}, requests, make([]interface{}, 2), 1)
+	require.Error(t, err)
+	assert.True(t, stderrors.Is(err, context.Canceled))
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// maxPreparedQueries bounds the number of queries kept prepared. Queries
+// built at run time beyond it are prepared for each request.
+const maxPreparedQueries = 1024
+
+// maxPooledBuffer is the capacity above which a request body buffer is not
+// returned to the pool, so one large request does not pin its memory
+const maxPooledBuffer = 64 << 10
+
+// preparedQuery is what the request pipeline derives from a query
+// document, kept so a query sent repeatedly is parsed and encoded once
+type preparedQuery struct {
+	// doc is the parsed query, nil if it does not parse
+	doc *ast.QueryDocument
+	// name is the name of the only operation in the query, if it has one
+	name string
+	// encoded is the query as a JSON string
+	encoded []byte
+	// encodedName is name as a JSON string
+	encodedName []byte
+}
+
+// preparedQueries holds the prepared queries by query document. Service
+// queries are constants, so they are shared by every client.
+var preparedQueries struct {
+	sync.RWMutex
+	m map[string]*preparedQuery
+}
+
+// prepareQuery returns the prepared form of query, preparing and keeping
+// it if it has not been seen
+func prepareQuery(query string) *preparedQuery {
+	preparedQueries.RLock()
+	p, ok := preparedQueries.m[query]
+	preparedQueries.RUnlock()
+	if ok {
+		return p
+	}
+
+	p = &preparedQuery{}
+	if doc, err := parser.ParseQuery(&ast.Source{Input: query}); err == nil {
+		p.doc = doc
+		if len(doc.Operations) == 1 {
+			p.name = doc.Operations[0].Name
+		}
+	}
+	// Strings always marshal
+	p.encoded, _ = json.Marshal(query)
+	p.encodedName, _ = json.Marshal(p.name)
+
+	preparedQueries.Lock()
+	defer preparedQueries.Unlock()
+	if existing, ok := preparedQueries.m[query]; ok {
+		return existing
+	}
+	if preparedQueries.m == nil {
+		preparedQueries.m = make(map[string]*preparedQuery)
+	}
+	if len(preparedQueries.m) < maxPreparedQueries {
+		preparedQueries.m[query] = p
+	}
+	return p
+}
+
+// operation returns the operation named name, which may be empty if the
+// query has one operation, or nil if there is none or the query does not
+// parse
+func (p *preparedQuery) operation(name string) *ast.OperationDefinition {
+	if p.doc == nil {
+		return nil
+	}
+	return p.doc.Operations.ForName(name)
+}
+
+// bufferPool holds the buffers request bodies are encoded into
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// requestBody is an encoded request body in a pooled buffer. The buffer is
+// returned to the pool once the request is done with it and every reader
+// of it has been closed, since a transport may still be sending a body
+// after the response arrives.
+type requestBody struct {
+	buf  *bytes.Buffer
+	refs atomic.Int32
+}
+
+// newRequestBody returns an empty request body, held by the caller until
+// it calls release
+func newRequestBody() *requestBody {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	b := &requestBody{buf: buf}
+	b.refs.Store(1)
+	return b
+}
+
+// reader returns a reader of the body for an HTTP request, which releases
+// it when closed. It fails once the body has been released.
+func (b *requestBody) reader() (io.ReadCloser, error) {
+	for {
+		refs := b.refs.Load()
+		if refs == 0 {
+			return nil, stderrors.New("request body already released")
+		}
+		if b.refs.CompareAndSwap(refs, refs+1) {
+			r := &bodyReader{body: b}
+			r.Reset(b.buf.Bytes())
+			return r, nil
+		}
+	}
+}
+
+// release drops a hold on the body, returning its buffer to the pool with
+// the last one
+func (b *requestBody) release() {
+	if b.refs.Add(-1) == 0 && b.buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(b.buf)
+	}
+}
+
+// len returns the length of the body in bytes
+func (b *requestBody) len() int {
+	return b.buf.Len()
+}
+
+// bodyReader reads a request body, releasing it on the first Close
+type bodyReader struct {
+	bytes.Reader
+	body   *requestBody
+	closed atomic.Bool
+}
+
+// Close implements io.Closer
+func (r *bodyReader) Close() error {
+	if r.closed.CompareAndSwap(false, true) {
+		r.body.release()
+	}
+	return nil
+}
+
+// encodeRequest appends req to buf as JSON, as it would marshal but with
+// the query encoded once per query document. Variables are marshaled with
+// marshal.
+func encodeRequest(buf *bytes.Buffer, req *GraphQLRequest, marshal func(interface{}) ([]byte, error)) error {
+	p := prepareQuery(req.Query)
+
+	buf.WriteString(`{"query":`)
+	buf.Write(p.encoded)
+	if len(req.Variables) > 0 {
+		variables, err := marshal(req.Variables)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(`,"variables":`)
+		buf.Write(variables)
+	}
+	if req.OperationName != "" {
+		buf.WriteString(`,"operationName":`)
+		if req.OperationName == p.name {
+			buf.Write(p.encodedName)
+		} else {
+			name, _ := json.Marshal(req.OperationName)
+			buf.Write(name)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRequestMatchesMarshal(t *testing.T) {
+	requests := map[string]*GraphQLRequest{
+		"bare":            {Query: "query GetUser { user { id } }"},
+		"named":           {Query: "query GetUser { user { id } }", OperationName: "GetUser"},
+		"other name":      {Query: "query A { a } query B { b }", OperationName: "B"},
+		"empty variables": {Query: "{ user { id } }", Variables: map[string]interface{}{}},
+		"variables": {
+			Query:     "query Search($filter: Filter) { search(filter: $filter) { id } }",
+			Variables: map[string]interface{}{"filter": map[string]interface{}{"title": "<b>Go & gRPC</b>", "limit": 10}},
+		},
+		"unparseable": {Query: `query { "unterminated`, OperationName: "Broken\n"},
+	}
+
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.Marshal(req)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, encodeRequest(&buf, req, json.Marshal))
+			assert.JSONEq(t, string(want), buf.String())
+
+			// Encoding a prepared query again gives the same bytes
+			var again bytes.Buffer
+			require.NoError(t, encodeRequest(&again, req, json.Marshal))
+			assert.Equal(t, buf.String(), again.String())
+		})
+	}
+}
+
+func TestPrepareQuery(t *testing.T) {
+	p := prepareQuery("query GetUser { user { id } }")
+	assert.Same(t, p, prepareQuery("query GetUser { user { id } }"))
+	assert.Equal(t, "GetUser", p.name)
+	assert.NotNil(t, p.operation(""))
+	assert.Nil(t, p.operation("Other"))
+
+	multi := prepareQuery("query A { a } mutation B { b }")
+	assert.Empty(t, multi.name)
+	assert.Nil(t, multi.operation(""))
+	assert.NotNil(t, multi.operation("B"))
+
+	broken := prepareQuery("query {")
+	assert.Nil(t, broken.doc)
+	assert.Nil(t, broken.operation(""))
+}
+
+func TestRequestBodyRelease(t *testing.T) {
+	body := newRequestBody()
+	body.buf.WriteString(`{"query":"{ a }"}`)
+
+	r, err := body.reader()
+	require.NoError(t, err)
+	body.release()
+
+	// The reader holds the body until it is closed, twice if need be
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, `{"query":"{ a }"}`, string(data))
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+
+	_, err = body.reader()
+	assert.Error(t, err, "a released body cannot be read again")
+}
+
+func TestRequestBodyResent(t *testing.T) {
+	// A 307 redirect makes the HTTP client resend the body through GetBody
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+		if r.URL.Path != "/moved" {
+			http.Redirect(w, r, "/moved", http.StatusTemporaryRedirect)
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"id":"u-1"}}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, OrganizationID: "org-1"}
+	require.NoError(t, client.Do(context.Background(), &GraphQLRequest{Query: "query GetUser { user { id } }"}, nil))
+
+	require.Len(t, bodies, 2)
+	assert.Equal(t, `{"query":"query GetUser { user { id } }","operationName":"GetUser"}`, bodies[0])
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestRequestHeaders(t *testing.T) {
+	headers := make(chan http.Header, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, OrganizationID: "org-1"}
+	req := &GraphQLRequest{Query: "{ user { id } }"}
+	ctx := context.Background()
+
+	require.NoError(t, client.Do(ctx, req, nil))
+	h := <-headers
+	assert.Equal(t, "application/json", h.Get("Content-Type"))
+	assert.Equal(t, "application/json", h.Get("Accept"))
+	assert.Equal(t, "org-1", h.Get("X-Upwork-API-TenantId"))
+
+	// A header set by an option replaces the shared value
+	require.NoError(t, client.Do(WithOrganization(ctx, "org-2"), req, nil, WithHeader("Accept", "application/graphql-response+json")))
+	h = <-headers
+	assert.Equal(t, "application/graphql-response+json", h.Get("Accept"))
+	assert.Equal(t, "org-2", h.Get("X-Upwork-API-TenantId"))
+
+	require.NoError(t, client.Do(ctx, req, nil))
+	h = <-headers
+	assert.Equal(t, "application/json", h.Get("Accept"))
+	assert.Equal(t, "org-1", h.Get("X-Upwork-API-TenantId"))
+}
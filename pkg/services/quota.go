@@ -0,0 +1,183 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// QuotaReporter is a RateLimiter that reports its quota. The client's
+// limiter implements it.
+type QuotaReporter interface {
+	RateLimiter
+
+	// Quota returns the tokens per window, the tokens that can be taken
+	// without waiting and when the current window ends
+	Quota() (limit, remaining int, reset time.Time)
+}
+
+// ThrottleSource is what throttled a request
+type ThrottleSource string
+
+const (
+	// ThrottleSourceLimiter is the client's own rate limiter
+	ThrottleSourceLimiter ThrottleSource = "limiter"
+	// ThrottleSourceServer is the API, answering 429 Too Many Requests
+	ThrottleSourceServer ThrottleSource = "server"
+)
+
+// ThrottleEvent describes a request that was delayed or rejected by rate
+// limiting
+type ThrottleEvent struct {
+	// Time is when the request was throttled
+	Time time.Time
+	// Source is what throttled the request
+	Source ThrottleSource
+	// OperationName is the name of the operation, if it has one. It is
+	// empty for batches.
+	OperationName string
+	// Wait is how long the limiter delayed the request, or how long the
+	// API asked the client to wait before retrying
+	Wait time.Duration
+	// Rejected is true if the request failed rather than waited, because
+	// the API answered 429 or the limiter could not serve it before the
+	// context deadline
+	Rejected bool
+}
+
+// RateLimitStatus is a snapshot of the client's rate limit quota
+type RateLimitStatus struct {
+	// Limit is the number of tokens per window
+	Limit int
+	// Remaining is the number of tokens that can be used without waiting
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+	// Throttles are the most recent throttle events, oldest first
+	Throttles []ThrottleEvent
+}
+
+// maxThrottleEvents is the number of throttle events kept for
+// RateLimitStatus
+const maxThrottleEvents = 50
+
+// minThrottleWait is the shortest limiter wait recorded as a throttle
+// event; shorter waits are the cost of taking a token
+const minThrottleWait = time.Millisecond
+
+// Rate limit headers the API sends with its responses
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+)
+
+// quotaTracker records the quota reported by the API and recent throttle
+// events
+type quotaTracker struct {
+	mu sync.Mutex
+	// reported is true once a response has carried rate limit headers
+	reported  bool
+	limit     int
+	remaining int
+	reset     time.Time
+	throttles []ThrottleEvent
+}
+
+// RateLimitStatus returns the client's rate limit quota. The quota comes
+// from the client's limiter, if it is a QuotaReporter, and from the rate
+// limit headers of the latest response, whichever leaves fewer tokens.
+// Headers are ignored once the window they describe has ended.
+func (c *BaseClient) RateLimitStatus() RateLimitStatus {
+	var status RateLimitStatus
+	if reporter, ok := c.RateLimiter.(QuotaReporter); ok {
+		status.Limit, status.Remaining, status.Reset = reporter.Quota()
+	}
+
+	q := &c.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	current := q.reported && (q.reset.IsZero() || time.Now().Before(q.reset))
+	if current && (status.Limit == 0 || q.remaining < status.Remaining) {
+		if q.limit > 0 {
+			status.Limit = q.limit
+		}
+		status.Remaining = q.remaining
+		status.Reset = q.reset
+	}
+	status.Throttles = append([]ThrottleEvent(nil), q.throttles...)
+	return status
+}
+
+// observeQuota records the rate limit headers of resp and, if the API
+// throttled the request, a throttle event
+func (c *BaseClient) observeQuota(operationName string, resp *http.Response) {
+	now := time.Now()
+	q := &c.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if remaining, err := strconv.Atoi(resp.Header.Get(headerRateLimitRemaining)); err == nil {
+		q.reported = true
+		q.remaining = remaining
+		q.limit, _ = strconv.Atoi(resp.Header.Get(headerRateLimitLimit))
+		q.reset = parseReset(resp.Header.Get(headerRateLimitReset), now)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		q.record(ThrottleEvent{
+			Time:          now,
+			Source:        ThrottleSourceServer,
+			OperationName: operationName,
+			Wait:          parseRetryAfter(resp.Header.Get(headerRetryAfter), now),
+			Rejected:      true,
+		})
+	}
+}
+
+// recordThrottle records a throttle event
+func (c *BaseClient) recordThrottle(event ThrottleEvent) {
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	c.quota.record(event)
+}
+
+// record appends event, dropping the oldest events beyond
+// maxThrottleEvents. q.mu must be held.
+func (q *quotaTracker) record(event ThrottleEvent) {
+	if len(q.throttles) == maxThrottleEvents {
+		copy(q.throttles, q.throttles[1:])
+		q.throttles = q.throttles[:len(q.throttles)-1]
+	}
+	q.throttles = append(q.throttles, event)
+}
+
+// parseReset parses an X-RateLimit-Reset header, either a Unix time or a
+// number of seconds from now. It returns the zero time if the header is
+// missing or invalid.
+func parseReset(value string, now time.Time) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}
+	}
+	// Nothing resets more than a year out, so smaller values are relative
+	if seconds < 365*24*60*60 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	return time.Unix(seconds, 0)
+}
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds
+// or an HTTP date. It returns zero if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
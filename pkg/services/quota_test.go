@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// quotaLimiter is a QuotaReporter with a fixed quota that waits for delay
+type quotaLimiter struct {
+	remaining int
+	delay     time.Duration
+	err       error
+}
+
+func (l *quotaLimiter) Wait(ctx context.Context) error {
+	time.Sleep(l.delay)
+	return l.err
+}
+
+func (l *quotaLimiter) Quota() (int, int, time.Time) {
+	return 300, l.remaining, time.Unix(2000000000, 0)
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	remaining := 250
+	throttle := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", "30")
+		if throttle {
+			w.Header().Set("Retry-After", "12")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	limiter := &quotaLimiter{remaining: 120}
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, RateLimiter: limiter}
+	ctx := context.Background()
+	query := &GraphQLRequest{Query: `query GetUser { user { id } }`}
+
+	// Before any response, the quota is the limiter's
+	status := client.RateLimitStatus()
+	assert.Equal(t, RateLimitStatus{Limit: 300, Remaining: 120, Reset: time.Unix(2000000000, 0)}, status)
+
+	// The limiter leaves fewer tokens than the API reports
+	require.NoError(t, client.Do(ctx, query, nil))
+	status = client.RateLimitStatus()
+	assert.Equal(t, 120, status.Remaining)
+	assert.Empty(t, status.Throttles)
+
+	// The API reports fewer tokens than the limiter
+	remaining = 7
+	require.NoError(t, client.Do(ctx, query, nil))
+	status = client.RateLimitStatus()
+	assert.Equal(t, 1000, status.Limit)
+	assert.Equal(t, 7, status.Remaining)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), status.Reset, time.Second)
+
+	// Throttling by the API and the limiter is recorded
+	throttle, remaining = true, 0
+	assert.Error(t, client.Do(ctx, query, nil, WithRetry(false)))
+	throttle = false
+
+	limiter.delay = 5 * time.Millisecond
+	require.NoError(t, client.Do(ctx, query, nil))
+	limiter.delay, limiter.err = 0, errors.ErrWouldExceedDeadline
+	assert.ErrorIs(t, client.Do(ctx, query, nil), errors.ErrWouldExceedDeadline)
+
+	status = client.RateLimitStatus()
+	require.Len(t, status.Throttles, 3)
+	assert.Equal(t, ThrottleSourceServer, status.Throttles[0].Source)
+	assert.Equal(t, "GetUser", status.Throttles[0].OperationName)
+	assert.Equal(t, 12*time.Second, status.Throttles[0].Wait)
+	assert.True(t, status.Throttles[0].Rejected)
+	assert.Equal(t, ThrottleSourceLimiter, status.Throttles[1].Source)
+	assert.GreaterOrEqual(t, status.Throttles[1].Wait, 5*time.Millisecond)
+	assert.False(t, status.Throttles[1].Rejected)
+	assert.Equal(t, ThrottleSourceLimiter, status.Throttles[2].Source)
+	assert.True(t, status.Throttles[2].Rejected)
+}
+
+func TestThrottleEventsBounded(t *testing.T) {
+	client := &BaseClient{}
+	for i := 0; i < maxThrottleEvents+10; i++ {
+		client.recordThrottle(ThrottleEvent{OperationName: strconv.Itoa(i)})
+	}
+
+	throttles := client.RateLimitStatus().Throttles
+	require.Len(t, throttles, maxThrottleEvents)
+	assert.Equal(t, "10", throttles[0].OperationName)
+	assert.Equal(t, strconv.Itoa(maxThrottleEvents+9), throttles[maxThrottleEvents-1].OperationName)
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	assert.Equal(t, now.Add(60*time.Second), parseReset("60", now))
+	assert.Equal(t, time.Unix(1700000300, 0), parseReset("1700000300", now))
+	assert.True(t, parseReset("", now).IsZero())
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).UTC().Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
-	"github.com/rizome-dev/go-upwork/pkg/models"
 	"context"
-	"time"
+	"encoding/json"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
 // ReportsService handles report-related API operations
@@ -28,45 +30,69 @@ type TransactionDetail struct {
 
 // TransactionHistoryRow represents a single transaction row
 type TransactionHistoryRow struct {
-	RowNumber                   int       `json:"rowNumber"`
-	RecordID                    string    `json:"recordId"`
-	Type                        string    `json:"type"`
-	AccountingSubtype           string    `json:"accountingSubtype"`
-	Description                 string    `json:"description"`
-	DescriptionUI               string    `json:"descriptionUI"`
-	TransactionCreationDate     DateTime  `json:"transactionCreationDate"`
-	TransactionReviewDueDate    DateTime  `json:"transactionReviewDueDate"`
-	TransactionAmount           Money     `json:"transactionAmount"`
-	AmountCreditedToUser        Money     `json:"amountCreditedToUser"`
-	Payment                     Money     `json:"payment"`
-	PaymentStatus               string    `json:"paymentStatus"`
-	RelatedAssignment           string    `json:"relatedAssignment"`
-	RelatedAccountingEntity     string    `json:"relatedAccountingEntity"`
-	RelatedTransactionID        string    `json:"relatedTransactionId"`
-	RelatedInvoiceID            string    `json:"relatedInvoiceId"`
-	PurchaseOrderNumber         string    `json:"purchaseOrderNumber"`
-	AssignmentTeamCompanyID     string    `json:"assignmentTeamCompanyId"`
-	AssignmentTeamCompanyReference string `json:"assignmentTeamCompanyReference"`
-	AssignmentCompanyName       string    `json:"assignmentCompanyName"`
-	AssignmentDeveloperName     string    `json:"assignmentDeveloperName"`
-	AssignmentTeamUserID        string    `json:"assignmentTeamUserId"`
-	AssignmentTeamUserReference string    `json:"assignmentTeamUserReference"`
+	RowNumber                      int             `json:"rowNumber"`
+	RecordID                       string          `json:"recordId"`
+	Type                           string          `json:"type"`
+	AccountingSubtype              string          `json:"accountingSubtype"`
+	Description                    string          `json:"description"`
+	DescriptionUI                  string          `json:"descriptionUI"`
+	TransactionCreationDate        models.DateTime `json:"transactionCreationDate"`
+	TransactionReviewDueDate       models.DateTime `json:"transactionReviewDueDate"`
+	TransactionAmount              models.Money    `json:"transactionAmount"`
+	AmountCreditedToUser           models.Money    `json:"amountCreditedToUser"`
+	Payment                        models.Money    `json:"payment"`
+	PaymentStatus                  string          `json:"paymentStatus"`
+	RelatedAssignment              string          `json:"relatedAssignment"`
+	RelatedAccountingEntity        string          `json:"relatedAccountingEntity"`
+	RelatedTransactionID           string          `json:"relatedTransactionId"`
+	RelatedInvoiceID               string          `json:"relatedInvoiceId"`
+	PurchaseOrderNumber            string          `json:"purchaseOrderNumber"`
+	AssignmentTeamCompanyID        string          `json:"assignmentTeamCompanyId"`
+	AssignmentTeamCompanyReference string          `json:"assignmentTeamCompanyReference"`
+	AssignmentCompanyName          string          `json:"assignmentCompanyName"`
+	AssignmentDeveloperName        string          `json:"assignmentDeveloperName"`
+	AssignmentTeamUserID           string          `json:"assignmentTeamUserId"`
+	AssignmentTeamUserReference    string          `json:"assignmentTeamUserReference"`
 }
 
 // TransactionHistoryInput represents input for transaction history query
 type TransactionHistoryInput struct {
-	AccountingEntityIDs []string    `json:"aceIds"`
-	DateRange           DateRange   `json:"transactionDateTime"`
+	AccountingEntityIDs []string         `json:"aceIds"`
+	DateRange           models.DateRange `json:"transactionDateTime"`
+}
+
+// GetTransactionHistory retrieves transaction history. Use
+// GetTransactionHistoryStream for large date ranges to avoid holding every
+// row in memory.
+func (s *ReportsService) GetTransactionHistory(ctx context.Context, input TransactionHistoryInput) (*TransactionHistory, error) {
+	var resp struct {
+		TransactionHistory TransactionHistory `json:"transactionHistory"`
+	}
+
+	if err := s.client.Do(ctx, transactionHistoryRequest(input), &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.TransactionHistory, nil
 }
 
-// DateRange represents a date range
-type DateRange struct {
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
+// GetTransactionHistoryStream retrieves transaction history, calling fn for
+// each row as it is read from the response so memory use stays flat for
+// large exports. An error returned by fn stops the stream and is returned.
+func (s *ReportsService) GetTransactionHistoryStream(ctx context.Context, input TransactionHistoryInput, fn func(row TransactionHistoryRow) error) error {
+	path := []string{"transactionHistory", "transactionDetail", "transactionHistoryRow"}
+
+	return s.client.DoStream(ctx, transactionHistoryRequest(input), path, func(dec *json.Decoder) error {
+		var row TransactionHistoryRow
+		if err := dec.Decode(&row); err != nil {
+			return errors.WrapError(err, "failed to unmarshal transaction history row")
+		}
+		return fn(row)
+	})
 }
 
-// GetTransactionHistory retrieves transaction history
-func (s *ReportsService) GetTransactionHistory(ctx context.Context, input TransactionHistoryInput) (*TransactionHistory, error) {
+// transactionHistoryRequest builds the transaction history query
+func transactionHistoryRequest(input TransactionHistoryInput) *GraphQLRequest {
 	query := `
 		query TransactionHistory($aceIds_any: [ID!]!, $transactionDateTime_bt: DateTimeRange!) {
 			transactionHistory(
@@ -117,50 +143,40 @@ func (s *ReportsService) GetTransactionHistory(ctx context.Context, input Transa
 			}
 		}
 	`
-	
-	req := &GraphQLRequest{
+
+	return &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
-			"aceIds_any":            input.AccountingEntityIDs,
+			"aceIds_any":             input.AccountingEntityIDs,
 			"transactionDateTime_bt": input.DateRange,
 		},
 	}
-	
-	var resp struct {
-		TransactionHistory TransactionHistory `json:"transactionHistory"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
-		return nil, err
-	}
-	
-	return &resp.TransactionHistory, nil
 }
 
 // TimeReport represents a time report
 type TimeReport struct {
-	DateWorkedOn        DateTime `json:"dateWorkedOn"`
-	WeekWorkedOn        DateTime `json:"weekWorkedOn"`
-	MonthWorkedOn       int      `json:"monthWorkedOn"`
-	YearWorkedOn        int      `json:"yearWorkedOn"`
-	Freelancer          User     `json:"freelancer"`
-	Team                Team     `json:"team"`
-	Contract            Contract `json:"contract"`
-	Task                string   `json:"task"`
-	TaskDescription     string   `json:"taskDescription"`
-	Memo                string   `json:"memo"`
-	TotalHoursWorked    float64  `json:"totalHoursWorked"`
-	TotalCharges        Money    `json:"totalCharges"`
-	TotalOnlineHoursWorked  float64  `json:"totalOnlineHoursWorked"`
-	TotalOnlineCharge   Money    `json:"totalOnlineCharge"`
-	TotalOfflineHoursWorked float64  `json:"totalOfflineHoursWorked"`
-	TotalOfflineCharge  Money    `json:"totalOfflineCharge"`
+	DateWorkedOn            models.DateTime `json:"dateWorkedOn"`
+	WeekWorkedOn            models.DateTime `json:"weekWorkedOn"`
+	MonthWorkedOn           int             `json:"monthWorkedOn"`
+	YearWorkedOn            int             `json:"yearWorkedOn"`
+	Freelancer              models.User     `json:"freelancer"`
+	Team                    models.Team     `json:"team"`
+	Contract                Contract        `json:"contract"`
+	Task                    string          `json:"task"`
+	TaskDescription         string          `json:"taskDescription"`
+	Memo                    string          `json:"memo"`
+	TotalHoursWorked        float64         `json:"totalHoursWorked"`
+	TotalCharges            models.Money    `json:"totalCharges"`
+	TotalOnlineHoursWorked  float64         `json:"totalOnlineHoursWorked"`
+	TotalOnlineCharge       models.Money    `json:"totalOnlineCharge"`
+	TotalOfflineHoursWorked float64         `json:"totalOfflineHoursWorked"`
+	TotalOfflineCharge      models.Money    `json:"totalOfflineCharge"`
 }
 
 // TimeReportList represents a list of time reports
 type TimeReportList struct {
 	TotalCount int              `json:"totalCount"`
-	PageInfo   PageInfo         `json:"pageInfo"`
+	PageInfo   models.PageInfo  `json:"pageInfo"`
 	Edges      []TimeReportEdge `json:"edges"`
 }
 
@@ -172,12 +188,16 @@ type TimeReportEdge struct {
 
 // TimeReportInput represents input for time report query
 type TimeReportInput struct {
-	OrganizationID string           `json:"organizationId"`
-	DateRange      DateRange        `json:"timeReportDate"`
-	Pagination     *PaginationInput `json:"pagination,omitempty"`
+	OrganizationID string                  `json:"organizationId"`
+	DateRange      models.DateRange        `json:"timeReportDate"`
+	Pagination     *models.PaginationInput `json:"pagination,omitempty"`
 }
 
-// GetTimeReport retrieves time reports
+// timeReportPageSize is the default number of rows per time report page
+const timeReportPageSize = 50
+
+// GetTimeReport retrieves time reports. Without a page size in
+// input.Pagination, the client's DefaultPageSize is used.
 func (s *ReportsService) GetTimeReport(ctx context.Context, input TimeReportInput) (*TimeReportList, error) {
 	query := `
 		query TimeReport($orgId: ID!, $after: String, $first: Int!, $timeReportDate_bt: DateTimeRange!) {
@@ -226,50 +246,51 @@ func (s *ReportsService) GetTimeReport(ctx context.Context, input TimeReportInpu
 			}
 		}
 	`
-	
+
 	variables := map[string]interface{}{
-		"orgId":              input.OrganizationID,
-		"timeReportDate_bt":  input.DateRange,
+		"orgId":             input.OrganizationID,
+		"timeReportDate_bt": input.DateRange,
 	}
-	
+
+	variables["first"] = s.client.pageSize(ctx, timeReportPageSize)
 	if input.Pagination != nil {
 		variables["after"] = input.Pagination.After
-		variables["first"] = input.Pagination.First
-	} else {
-		variables["first"] = 50 // Default page size
+		if input.Pagination.First > 0 {
+			variables["first"] = input.Pagination.First
+		}
 	}
-	
+
 	req := &GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
-	
+
 	var resp struct {
 		ContractTimeReport TimeReportList `json:"contractTimeReport"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.ContractTimeReport, nil
 }
 
 // WorkDiary represents work diary data
 type WorkDiary struct {
-	Total     int                `json:"total"`
+	Total     int                 `json:"total"`
 	Snapshots []WorkDiarySnapshot `json:"snapshots"`
 }
 
 // WorkDiarySnapshot represents a work diary snapshot
 type WorkDiarySnapshot struct {
-	Contract            WorkDiaryContract    `json:"contract"`
-	User                User                 `json:"user"`
-	Duration            string               `json:"duration"`
-	DurationInt         int                  `json:"durationInt"`
-	Task                Task                 `json:"task"`
-	Time                WorkDiaryTime        `json:"time"`
-	Screenshots         []Screenshot         `json:"screenshots"`
+	Contract    WorkDiaryContract `json:"contract"`
+	User        models.User       `json:"user"`
+	Duration    string            `json:"duration"`
+	DurationInt int               `json:"durationInt"`
+	Task        Task              `json:"task"`
+	Time        WorkDiaryTime     `json:"time"`
+	Screenshots []Screenshot      `json:"screenshots"`
 }
 
 // WorkDiaryContract represents contract info in work diary
@@ -301,17 +322,17 @@ type WorkDiaryTime struct {
 
 // Screenshot represents a screenshot
 type Screenshot struct {
-	Activity               int    `json:"activity"`
-	ScreenshotURL          string `json:"screenshotUrl"`
-	ScreenshotImage        string `json:"screenshotImage"`
-	ScreenshotImageLarge   string `json:"screenshotImageLarge"`
-	ScreenshotImageMedium  string `json:"screenshotImageMedium"`
+	Activity                 int    `json:"activity"`
+	ScreenshotURL            string `json:"screenshotUrl"`
+	ScreenshotImage          string `json:"screenshotImage"`
+	ScreenshotImageLarge     string `json:"screenshotImageLarge"`
+	ScreenshotImageMedium    string `json:"screenshotImageMedium"`
 	ScreenshotImageThumbnail string `json:"screenshotImageThumbnail"`
-	HasWebcam              bool   `json:"hasWebcam"`
-	HasScreenshot          bool   `json:"hasScreenshot"`
-	WebcamURL              string `json:"webcamUrl"`
-	WebcamImage            string `json:"webcamImage"`
-	WebcamImageThumbnail   string `json:"webcamImageThumbnail"`
+	HasWebcam                bool   `json:"hasWebcam"`
+	HasScreenshot            bool   `json:"hasScreenshot"`
+	WebcamURL                string `json:"webcamUrl"`
+	WebcamImage              string `json:"webcamImage"`
+	WebcamImageThumbnail     string `json:"webcamImageThumbnail"`
 }
 
 // GetWorkDiaryByCompany retrieves work diary for a company
@@ -366,7 +387,7 @@ func (s *ReportsService) GetWorkDiaryByCompany(ctx context.Context, companyID st
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
@@ -374,14 +395,14 @@ func (s *ReportsService) GetWorkDiaryByCompany(ctx context.Context, companyID st
 			"date":      date,
 		},
 	}
-	
+
 	var resp struct {
 		WorkDiaryCompany WorkDiary `json:"workDiaryCompany"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.WorkDiaryCompany, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// WorkDiarySegment is the length of the work diary segment a snapshot
+// covers. Its activity is the number of minutes with keyboard or mouse
+// input, so it ranges from 0 to 10.
+const WorkDiarySegment = 10 * time.Minute
+
+const (
+	// DefaultIdleActivity is the default activity percentage at or below
+	// which a tracked segment counts as idle
+	DefaultIdleActivity = 10
+	// DefaultMinIdleSegments is the default number of consecutive idle
+	// segments that make an idle period
+	DefaultMinIdleSegments = 3
+)
+
+// ActivityOptions configures SummarizeWorkDiary
+type ActivityOptions struct {
+	// IdleActivity is the activity percentage at or below which a tracked
+	// segment is idle, DefaultIdleActivity if zero. Set it negative to
+	// count only segments with no activity at all.
+	IdleActivity float64
+	// MinIdleSegments is the number of consecutive idle segments that make
+	// an IdlePeriod, DefaultMinIdleSegments if zero
+	MinIdleSegments int
+	// Location is the time zone hours are bucketed in, UTC if nil
+	Location *time.Location
+}
+
+// ContractActivity aggregates the work diary snapshots of one contract and
+// freelancer for compliance reporting
+type ContractActivity struct {
+	ContractID    string
+	ContractTitle string
+	UserID        string
+
+	TrackedHours float64
+	ManualHours  float64
+	IdleHours    float64
+	// ManualRatio is ManualHours as a fraction of all hours logged
+	ManualRatio float64
+	// AverageActivity is the mean activity of the tracked segments as a
+	// percentage
+	AverageActivity float64
+
+	// Hours holds an entry for each hour with tracked or manual segments,
+	// earliest first
+	Hours []HourlyActivity
+	// IdlePeriods are the runs of at least MinIdleSegments consecutive
+	// idle segments, earliest first
+	IdlePeriods []IdlePeriod
+}
+
+// HourlyActivity aggregates the segments starting in one hour
+type HourlyActivity struct {
+	// Hour is the start of the hour
+	Hour            time.Time
+	TrackedSegments int
+	ManualSegments  int
+	IdleSegments    int
+	// AverageActivity is the mean activity of the tracked segments as a
+	// percentage
+	AverageActivity float64
+}
+
+// IdlePeriod is a run of consecutive idle segments
+type IdlePeriod struct {
+	Start    time.Time
+	End      time.Time
+	Segments int
+}
+
+// Duration returns the length of the period
+func (p IdlePeriod) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// GetWorkDiaryActivity fetches the company's work diary for date and
+// summarizes it per contract with SummarizeWorkDiary
+func (s *ReportsService) GetWorkDiaryActivity(ctx context.Context, companyID, date string, opts ActivityOptions) ([]ContractActivity, error) {
+	if err := firstError(
+		required("companyId", companyID),
+		required("date", date),
+		validateDate("date", date),
+	); err != nil {
+		return nil, err
+	}
+
+	diary, err := s.GetWorkDiaryByCompany(ctx, companyID, date)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeWorkDiary(diary.Snapshots, opts), nil
+}
+
+// diarySegment is a snapshot reduced to what SummarizeWorkDiary needs
+type diarySegment struct {
+	start   time.Time
+	tracked time.Duration
+	manual  time.Duration
+	// activity is the mean screenshot activity as a percentage, or -1 if
+	// the segment has no screenshots
+	activity float64
+}
+
+// SummarizeWorkDiary aggregates work diary snapshots, each covering one
+// segment, per contract and freelancer, without making any requests.
+// Tracked segments are averaged per hour and checked for idleness; manual
+// segments, which have no activity, only count towards the manual hours.
+// Contracts are sorted by ID.
+func SummarizeWorkDiary(snapshots []WorkDiarySnapshot, opts ActivityOptions) []ContractActivity {
+	switch {
+	case opts.IdleActivity == 0:
+		opts.IdleActivity = DefaultIdleActivity
+	case opts.IdleActivity < 0:
+		opts.IdleActivity = 0
+	}
+	if opts.MinIdleSegments <= 0 {
+		opts.MinIdleSegments = DefaultMinIdleSegments
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	type key struct{ contractID, userID string }
+	segments := make(map[key][]diarySegment)
+	titles := make(map[key]string)
+	for _, snap := range snapshots {
+		userID := snap.Contract.UserID
+		if userID == "" {
+			userID = string(snap.User.ID)
+		}
+		k := key{snap.Contract.ID, userID}
+		segments[k] = append(segments[k], newDiarySegment(snap))
+		if titles[k] == "" {
+			titles[k] = snap.Contract.ContractTitle
+		}
+	}
+
+	activities := make([]ContractActivity, 0, len(segments))
+	for k, segs := range segments {
+		a := ContractActivity{ContractID: k.contractID, ContractTitle: titles[k], UserID: k.userID}
+		a.summarize(segs, opts)
+		activities = append(activities, a)
+	}
+	sort.Slice(activities, func(i, j int) bool {
+		if activities[i].ContractID != activities[j].ContractID {
+			return activities[i].ContractID < activities[j].ContractID
+		}
+		return activities[i].UserID < activities[j].UserID
+	})
+	return activities
+}
+
+// summarize fills in the activity from the contract's segments
+func (a *ContractActivity) summarize(segs []diarySegment, opts ActivityOptions) {
+	sort.SliceStable(segs, func(i, j int) bool { return segs[i].start.Before(segs[j].start) })
+
+	var tracked, manual, idle time.Duration
+	var activitySum float64
+	var measured int
+	hours := make(map[time.Time]*HourlyActivity)
+	hourSums := make(map[time.Time]float64)
+	var run []diarySegment
+	endRun := func() {
+		if len(run) >= opts.MinIdleSegments {
+			last := run[len(run)-1]
+			a.IdlePeriods = append(a.IdlePeriods, IdlePeriod{
+				Start:    run[0].start,
+				End:      last.start.Add(last.length()),
+				Segments: len(run),
+			})
+		}
+		run = run[:0]
+	}
+
+	// hourOf returns the hour the segment starts in, or nil if its start
+	// is unknown
+	hourOf := func(seg diarySegment) *HourlyActivity {
+		if seg.start.IsZero() {
+			return nil
+		}
+		t := seg.start.In(opts.Location)
+		start := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, opts.Location)
+		if hours[start] == nil {
+			hours[start] = &HourlyActivity{Hour: start}
+		}
+		return hours[start]
+	}
+
+	for _, seg := range segs {
+		tracked += seg.tracked
+		manual += seg.manual
+
+		if seg.activity < 0 {
+			if hour := hourOf(seg); hour != nil && seg.manual > 0 {
+				hour.ManualSegments++
+			}
+			endRun()
+			continue
+		}
+
+		activitySum += seg.activity
+		measured++
+		isIdle := seg.activity <= opts.IdleActivity
+		if isIdle {
+			idle += seg.tracked
+		}
+		if hour := hourOf(seg); hour != nil {
+			hour.TrackedSegments++
+			hourSums[hour.Hour] += seg.activity
+			if isIdle {
+				hour.IdleSegments++
+			}
+		}
+
+		// A run is broken by a busy segment or a gap in the diary
+		if !isIdle || seg.start.IsZero() {
+			endRun()
+			continue
+		}
+		if len(run) > 0 {
+			prev := run[len(run)-1]
+			if seg.start.Sub(prev.start) > prev.length() {
+				endRun()
+			}
+		}
+		run = append(run, seg)
+	}
+	endRun()
+
+	a.TrackedHours = tracked.Hours()
+	a.ManualHours = manual.Hours()
+	a.IdleHours = idle.Hours()
+	if total := tracked + manual; total > 0 {
+		a.ManualRatio = float64(manual) / float64(total)
+	}
+	if measured > 0 {
+		a.AverageActivity = activitySum / float64(measured)
+	}
+
+	a.Hours = make([]HourlyActivity, 0, len(hours))
+	for start, hour := range hours {
+		if hour.TrackedSegments > 0 {
+			hour.AverageActivity = hourSums[start] / float64(hour.TrackedSegments)
+		}
+		a.Hours = append(a.Hours, *hour)
+	}
+	sort.Slice(a.Hours, func(i, j int) bool { return a.Hours[i].Hour.Before(a.Hours[j].Hour) })
+}
+
+// newDiarySegment reduces a snapshot to a segment. Its time is split into
+// tracked and manual from the snapshot's times, falling back to its
+// duration as tracked time.
+func newDiarySegment(snap WorkDiarySnapshot) diarySegment {
+	seg := diarySegment{
+		start:   diaryTime(snap.Time.FirstWorkedInt, snap.Time.FirstWorked),
+		tracked: diaryDuration(snap.Time.TrackedTime),
+		manual:  diaryDuration(snap.Time.ManualTime),
+	}
+	if seg.tracked == 0 && seg.manual == 0 {
+		seg.tracked = time.Duration(snap.DurationInt) * time.Minute
+		if seg.tracked == 0 {
+			seg.tracked = diaryDuration(snap.Duration)
+		}
+	}
+
+	seg.activity = -1
+	if seg.tracked > 0 && len(snap.Screenshots) > 0 {
+		var sum int
+		for _, shot := range snap.Screenshots {
+			sum += min(max(shot.Activity, 0), 10)
+		}
+		seg.activity = float64(sum) * 10 / float64(len(snap.Screenshots))
+	}
+	return seg
+}
+
+// length returns the time the segment covers, at least WorkDiarySegment
+func (s diarySegment) length() time.Duration {
+	return max(s.tracked+s.manual, WorkDiarySegment)
+}
+
+// diaryTime returns the time of a snapshot from its Unix timestamp, in
+// seconds or milliseconds, or else its formatted value
+func diaryTime(unix int, formatted string) time.Time {
+	switch {
+	case unix > 1e12:
+		return time.UnixMilli(int64(unix)).UTC()
+	case unix > 0:
+		return time.Unix(int64(unix), 0).UTC()
+	}
+	if t, err := (models.DateTime{RawValue: formatted}).Time(); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// diaryDuration parses a work diary duration: a number of minutes, hours
+// and minutes as "1:30", or a Go duration such as "1h30m". Anything else
+// is zero.
+func diaryDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	if minutes, err := strconv.Atoi(s); err == nil {
+		return time.Duration(minutes) * time.Minute
+	}
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		hours, err1 := strconv.Atoi(h)
+		minutes, err2 := strconv.Atoi(m)
+		if err1 == nil && err2 == nil {
+			return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+		}
+		return 0
+	}
+	if d, err := time.ParseDuration(strings.ReplaceAll(s, " ", "")); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// diarySnapshot returns a tracked snapshot starting at start with one
+// screenshot per activity
+func diarySnapshot(contractID string, start time.Time, activities ...int) WorkDiarySnapshot {
+	snap := WorkDiarySnapshot{
+		Contract: WorkDiaryContract{ID: contractID, ContractTitle: "Contract " + contractID, UserID: "user-1"},
+		Time:     WorkDiaryTime{TrackedTime: "10", FirstWorkedInt: int(start.Unix())},
+	}
+	for _, a := range activities {
+		snap.Screenshots = append(snap.Screenshots, Screenshot{Activity: a})
+	}
+	return snap
+}
+
+func TestSummarizeWorkDiary(t *testing.T) {
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return day.Add(time.Duration(minutes) * time.Minute) }
+
+	manual := WorkDiarySnapshot{
+		Contract: WorkDiaryContract{ID: "c-1", UserID: "user-1"},
+		Time:     WorkDiaryTime{ManualTime: "1:00", FirstWorked: "2024-03-04T12:00:00Z"},
+	}
+	snapshots := []WorkDiarySnapshot{
+		diarySnapshot("c-2", at(0), 5),
+		diarySnapshot("c-1", at(50), 8),
+		diarySnapshot("c-1", at(0), 10, 6),
+		// Three idle segments in a row straddling the hour
+		diarySnapshot("c-1", at(30), 1),
+		diarySnapshot("c-1", at(10), 0),
+		diarySnapshot("c-1", at(20), 0),
+		// Idle but after a gap, so not part of the run
+		diarySnapshot("c-1", at(70), 0),
+		manual,
+	}
+
+	activities := SummarizeWorkDiary(snapshots, ActivityOptions{})
+	require.Len(t, activities, 2)
+	assert.Equal(t, "c-2", activities[1].ContractID)
+	assert.InDelta(t, 50, activities[1].AverageActivity, 0.001)
+
+	a := activities[0]
+	assert.Equal(t, "c-1", a.ContractID)
+	assert.Equal(t, "Contract c-1", a.ContractTitle)
+	assert.Equal(t, "user-1", a.UserID)
+	assert.InDelta(t, 1, a.TrackedHours, 0.001)
+	assert.InDelta(t, 1, a.ManualHours, 0.001)
+	assert.InDelta(t, 0.5, a.ManualRatio, 0.001)
+	assert.InDelta(t, 4.0/6, a.IdleHours, 0.001)
+	assert.InDelta(t, (80+0+0+10+80+0)/6.0, a.AverageActivity, 0.001)
+
+	require.Len(t, a.Hours, 3)
+	assert.Equal(t, HourlyActivity{Hour: day, TrackedSegments: 5, IdleSegments: 3, AverageActivity: 34}, a.Hours[0])
+	assert.Equal(t, HourlyActivity{Hour: at(60), TrackedSegments: 1, IdleSegments: 1}, a.Hours[1])
+	assert.Equal(t, HourlyActivity{Hour: at(180), ManualSegments: 1}, a.Hours[2])
+
+	require.Len(t, a.IdlePeriods, 1)
+	assert.Equal(t, IdlePeriod{Start: at(10), End: at(40), Segments: 3}, a.IdlePeriods[0])
+	assert.Equal(t, 30*time.Minute, a.IdlePeriods[0].Duration())
+
+	// Only segments without activity are idle with a negative threshold,
+	// and hours are bucketed in the given zone
+	india := time.FixedZone("IST", 5*3600+1800)
+	a = SummarizeWorkDiary(snapshots, ActivityOptions{IdleActivity: -1, MinIdleSegments: 2, Location: india})[0]
+	assert.InDelta(t, 3.0/6, a.IdleHours, 0.001)
+	require.Len(t, a.IdlePeriods, 1)
+	assert.Equal(t, 2, a.IdlePeriods[0].Segments)
+	assert.Equal(t, time.Date(2024, 3, 4, 14, 0, 0, 0, india), a.Hours[0].Hour)
+	assert.Equal(t, 3, a.Hours[0].TrackedSegments, "09:00 to 09:30 UTC is 14:30 to 15:00 IST")
+}
+
+func TestDiaryDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":       0,
+		"10":     10 * time.Minute,
+		"1:30":   90 * time.Minute,
+		"2h 5m":  125 * time.Minute,
+		"-5m":    0,
+		"a:b":    0,
+		"lots":   0,
+		" 0:10 ": 10 * time.Minute,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, diaryDuration(in), in)
+	}
+}
+
+func TestGetWorkDiaryActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"workDiaryCompany":{"total":1,"snapshots":[
+			{"contract":{"id":"c-1","contractTitle":"API","userId":"user-1"},"durationInt":10,"time":{"firstWorkedInt":1709542800},"screenshots":[{"activity":7}]}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	activities, err := svc.GetWorkDiaryActivity(context.Background(), "company-1", "2024-03-04", ActivityOptions{})
+	require.NoError(t, err)
+	require.Len(t, activities, 1)
+	assert.InDelta(t, 70, activities[0].AverageActivity, 0.001)
+	assert.InDelta(t, 1.0/6, activities[0].TrackedHours, 0.001)
+	assert.Equal(t, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), activities[0].Hours[0].Hour)
+
+	_, err = svc.GetWorkDiaryActivity(context.Background(), "company-1", "04/03/2024", ActivityOptions{})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "date", validationErr.Field)
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// auditLogPageSize is the default number of audit events requested per
+// page
+const auditLogPageSize = 100
+
+// AuditEventType represents the kind of change recorded in an
+// organization's audit log
+type AuditEventType string
+
+const (
+	AuditEventStaffAdded        AuditEventType = "STAFF_ADDED"
+	AuditEventStaffRemoved      AuditEventType = "STAFF_REMOVED"
+	AuditEventPermissionChanged AuditEventType = "PERMISSION_CHANGED"
+	AuditEventAPIKeyCreated     AuditEventType = "API_KEY_CREATED"
+	AuditEventAPIKeyRevoked     AuditEventType = "API_KEY_REVOKED"
+	AuditEventAPIKeyUsed        AuditEventType = "API_KEY_USED"
+	AuditEventLogin             AuditEventType = "LOGIN"
+	AuditEventUnknown           AuditEventType = "UNKNOWN"
+)
+
+// AuditEvent represents a security-relevant change in an organization
+type AuditEvent struct {
+	ID   models.ID      `json:"id"`
+	Type AuditEventType `json:"type"`
+	// Actor is the user who made the change
+	Actor models.User `json:"actor"`
+	// Target is the user the change applied to, if any
+	Target      *models.User `json:"target"`
+	Description string       `json:"description"`
+	// APIKeyID is set for API key events
+	APIKeyID         string          `json:"apiKeyId"`
+	IPAddress        string          `json:"ipAddress"`
+	OccurredDateTime models.DateTime `json:"occurredDateTime"`
+}
+
+// AuditLogPage represents a page of an organization's audit log, oldest
+// event first
+type AuditLogPage struct {
+	TotalCount int              `json:"totalCount"`
+	PageInfo   models.PageInfo  `json:"pageInfo"`
+	Edges      []AuditEventEdge `json:"edges"`
+
+	service   *ReportsService
+	orgID     string
+	dateRange models.DateRange
+}
+
+// AuditEventEdge represents an audit event edge in pagination
+type AuditEventEdge struct {
+	Cursor string     `json:"cursor"`
+	Node   AuditEvent `json:"node"`
+}
+
+// Events returns the audit events on this page
+func (p *AuditLogPage) Events() []AuditEvent {
+	events := make([]AuditEvent, 0, len(p.Edges))
+	for _, edge := range p.Edges {
+		events = append(events, edge.Node)
+	}
+	return events
+}
+
+// HasNextPage returns true if there are more events after this page
+func (p *AuditLogPage) HasNextPage() bool {
+	return p.PageInfo.HasNextPage && p.PageInfo.EndCursor != ""
+}
+
+// NextPage fetches the page after this one. It returns nil if this is the
+// last page.
+func (p *AuditLogPage) NextPage(ctx context.Context) (*AuditLogPage, error) {
+	if !p.HasNextPage() || p.service == nil {
+		return nil, nil
+	}
+	return p.service.getAuditLogPage(ctx, p.orgID, p.dateRange, p.PageInfo.EndCursor)
+}
+
+// Iter returns an iterator over the events on this page and every page
+// after it. Use Prefetch on it to fetch pages ahead, e.g. for exports.
+func (p *AuditLogPage) Iter(ctx context.Context) *Iterator[AuditEvent] {
+	var client *BaseClient
+	if p.service != nil {
+		client = p.service.client
+	}
+
+	page := p
+	return newIterator(ctx, client, p.Events(), p.HasNextPage(), func(ctx context.Context) ([]AuditEvent, bool, error) {
+		next, err := page.NextPage(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		// Stop if the server hands back the same cursor rather than
+		// looping forever
+		if next == nil || next.PageInfo.EndCursor == page.PageInfo.EndCursor {
+			return nil, false, nil
+		}
+		page = next
+		return next.Events(), next.HasNextPage(), nil
+	})
+}
+
+// GetAuditLog returns the first page of an organization's audit log for
+// events within dateRange: staff and permission changes and API key
+// activity. Use NextPage or Iter on the result for further pages, or
+// StreamAuditLog to walk every event.
+func (s *ReportsService) GetAuditLog(ctx context.Context, orgID string, dateRange models.DateRange) (*AuditLogPage, error) {
+	if err := validateAuditLogInput(orgID, dateRange); err != nil {
+		return nil, err
+	}
+	return s.getAuditLogPage(ctx, orgID, dateRange, "")
+}
+
+// StreamAuditLog calls fn for every event in an organization's audit log
+// within dateRange, fetching pages as it goes so memory use stays flat for
+// long ranges. An error returned by fn stops the stream and is returned.
+// The stream also stops after the client's MaxItems events.
+func (s *ReportsService) StreamAuditLog(ctx context.Context, orgID string, dateRange models.DateRange, fn func(event AuditEvent) error) error {
+	page, err := s.GetAuditLog(ctx, orgID, dateRange)
+	if err != nil {
+		return err
+	}
+
+	it := page.Iter(ctx)
+	defer it.Close()
+	for it.Next() {
+		if err := fn(it.Item()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// validateAuditLogInput checks the organization and that the range ends
+// after it starts
+func validateAuditLogInput(orgID string, dateRange models.DateRange) error {
+	if err := required("orgId", orgID); err != nil {
+		return err
+	}
+	if dateRange.Start.IsZero() || dateRange.End.IsZero() {
+		return &errors.ValidationError{Field: "dateRange", Message: "start and end are required"}
+	}
+	if dateRange.End.Before(dateRange.Start) {
+		return &errors.ValidationError{Field: "dateRange.end", Message: "must not be before the start", Value: dateRange.End}
+	}
+	return nil
+}
+
+// getAuditLogPage fetches the page of the audit log after cursor
+func (s *ReportsService) getAuditLogPage(ctx context.Context, orgID string, dateRange models.DateRange, cursor string) (*AuditLogPage, error) {
+	query := `
+		query GetAuditLog($orgId: ID!, $occurredDateTime_bt: DateTimeRange!, $pagination: Pagination) {
+			organizationAuditLog(
+				orgId: $orgId,
+				filter: {occurredDateTime_bt: $occurredDateTime_bt},
+				pagination: $pagination
+			) {
+				totalCount
+				pageInfo {
+					hasNextPage
+					hasPreviousPage
+					startCursor
+					endCursor
+				}
+				edges {
+					cursor
+					node {
+						id
+						type
+						actor {
+							id
+							name
+						}
+						target {
+							id
+							name
+						}
+						description
+						apiKeyId
+						ipAddress
+						occurredDateTime
+					}
+				}
+			}
+		}
+	`
+
+	req := &GraphQLRequest{
+		Query: query,
+		Variables: map[string]interface{}{
+			"orgId":               orgID,
+			"occurredDateTime_bt": dateRange,
+			"pagination":          models.PaginationInput{First: s.client.pageSize(ctx, auditLogPageSize), After: cursor},
+		},
+	}
+
+	var resp struct {
+		OrganizationAuditLog AuditLogPage `json:"organizationAuditLog"`
+	}
+
+	if err := s.client.Do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	page := &resp.OrganizationAuditLog
+	page.service = s
+	page.orgID = orgID
+	page.dateRange = dateRange
+	return page, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestStreamAuditLog(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "organizationAuditLog")
+		assert.Equal(t, "org-1", req.Variables["orgId"])
+		requests++
+
+		pagination := req.Variables["pagination"].(map[string]interface{})
+		if pagination["after"] == nil {
+			w.Write([]byte(`{"data":{"organizationAuditLog":{"pageInfo":{"hasNextPage":true,"endCursor":"page-2"},"edges":[
+				{"node":{"id":"e-1","type":"STAFF_ADDED","actor":{"id":"user-1"},"target":{"id":"user-2"}}},
+				{"node":{"id":"e-2","type":"PERMISSION_CHANGED","actor":{"id":"user-1"},"target":{"id":"user-2"}}}
+			]}}}`))
+			return
+		}
+		assert.Equal(t, "page-2", pagination["after"])
+		w.Write([]byte(`{"data":{"organizationAuditLog":{"pageInfo":{"hasNextPage":false},"edges":[
+			{"node":{"id":"e-3","type":"API_KEY_USED","actor":{"id":"user-3"},"apiKeyId":"key-1"}}
+		]}}}`))
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+	ctx := context.Background()
+	dateRange := models.DateRange{
+		Start: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	var events []AuditEvent
+	err := svc.StreamAuditLog(ctx, "org-1", dateRange, func(event AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, AuditEventAPIKeyUsed, events[2].Type)
+	assert.Equal(t, "key-1", events[2].APIKeyID)
+	assert.Nil(t, events[2].Target)
+	assert.Equal(t, 2, requests)
+
+	stop := stderrors.New("stop")
+	requests = 0
+	err = svc.StreamAuditLog(ctx, "org-1", dateRange, func(AuditEvent) error { return stop })
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, requests)
+
+	_, err = svc.GetAuditLog(ctx, "org-1", models.DateRange{Start: dateRange.End, End: dateRange.Start})
+	assertValidationField(t, err, "dateRange.end")
+	_, err = svc.GetAuditLog(ctx, "", dateRange)
+	assertValidationField(t, err, "orgId")
+}
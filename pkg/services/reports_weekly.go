@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// WeeklySummary aggregates the time logged on an hourly contract over one
+// billing week. Tracked time is recorded by the desktop app; manual time is
+// added by the freelancer.
+type WeeklySummary struct {
+	ContractID string
+	// WeekStart is midnight UTC on the Monday the week starts
+	WeekStart time.Time
+	// WeekEnd is midnight UTC on the following Monday
+	WeekEnd time.Time
+
+	TrackedHours float64
+	ManualHours  float64
+	TotalHours   float64
+
+	TrackedCharges models.Money
+	ManualCharges  models.Money
+	TotalCharges   models.Money
+
+	// WeeklyHoursLimit is nil if the contract has no weekly limit
+	WeeklyHoursLimit *int
+	// LimitUtilization is TotalHours as a fraction of WeeklyHoursLimit, or
+	// zero if the contract has no limit. It exceeds 1 when the limit is
+	// overrun, e.g. by manual time.
+	LimitUtilization float64
+
+	// Days holds one entry per day of the week, Monday first
+	Days []DailySummary
+}
+
+// DailySummary aggregates the time logged on one day
+type DailySummary struct {
+	Date         time.Time
+	TrackedHours float64
+	ManualHours  float64
+	TotalHours   float64
+	Charges      models.Money
+}
+
+// OverLimit returns true if more hours were logged than the weekly limit
+func (s *WeeklySummary) OverLimit() bool {
+	return s.WeeklyHoursLimit != nil && s.TotalHours > float64(*s.WeeklyHoursLimit)
+}
+
+// WeekStart returns midnight UTC on the Monday of the billing week
+// containing t
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// weeklyReportPageSize is the default number of time report rows requested
+// per page by GetWeeklySummary
+const weeklyReportPageSize = 100
+
+// weeklyReportRow is a contractTimeReport row as used by GetWeeklySummary
+type weeklyReportRow struct {
+	DateWorkedOn            string       `json:"dateWorkedOn"`
+	TotalOnlineHoursWorked  float64      `json:"totalOnlineHoursWorked"`
+	TotalOnlineCharge       models.Money `json:"totalOnlineCharge"`
+	TotalOfflineHoursWorked float64      `json:"totalOfflineHoursWorked"`
+	TotalOfflineCharge      models.Money `json:"totalOfflineCharge"`
+}
+
+// GetWeeklySummary returns the tracked and manual hours, charges and limit
+// utilization of an hourly contract for the billing week containing week
+func (s *ReportsService) GetWeeklySummary(ctx context.Context, contractID string, week time.Time) (*WeeklySummary, error) {
+	query := `
+		query ContractWeeklySummary($contractId: ID!, $after: String, $first: Int!, $timeReportDate_bt: DateTimeRange!) {
+			contract(id: $contractId) {
+				weeklyHoursLimit
+				hourlyChargeRate {
+					rawValue
+					currency
+				}
+			}
+			contractTimeReport(
+				filter: {
+					contractId_eq: $contractId,
+					timeReportDate_bt: $timeReportDate_bt
+				}
+				pagination: {after: $after, first: $first}
+			) {
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+				edges {
+					node {
+						dateWorkedOn
+						totalOnlineHoursWorked
+						totalOnlineCharge
+						totalOfflineHoursWorked
+						totalOfflineCharge
+					}
+				}
+			}
+		}
+	`
+
+	start := WeekStart(week)
+	summary := &WeeklySummary{
+		ContractID: contractID,
+		WeekStart:  start,
+		WeekEnd:    start.AddDate(0, 0, 7),
+		Days:       make([]DailySummary, 7),
+	}
+	for i := range summary.Days {
+		summary.Days[i].Date = start.AddDate(0, 0, i)
+	}
+
+	variables := map[string]interface{}{
+		"contractId": contractID,
+		"first":      s.client.pageSize(ctx, weeklyReportPageSize),
+		// The range is inclusive, so it ends on Sunday
+		"timeReportDate_bt": models.DateRange{Start: start, End: summary.WeekEnd.AddDate(0, 0, -1)},
+	}
+
+	var currency string
+	for {
+		var resp struct {
+			Contract struct {
+				WeeklyHoursLimit *int          `json:"weeklyHoursLimit"`
+				HourlyChargeRate *models.Money `json:"hourlyChargeRate"`
+			} `json:"contract"`
+			ContractTimeReport struct {
+				PageInfo models.PageInfo `json:"pageInfo"`
+				Edges    []struct {
+					Node weeklyReportRow `json:"node"`
+				} `json:"edges"`
+			} `json:"contractTimeReport"`
+		}
+
+		req := &GraphQLRequest{
+			Query:     query,
+			Variables: variables,
+		}
+
+		if err := s.client.Do(ctx, req, &resp); err != nil {
+			return nil, err
+		}
+
+		summary.WeeklyHoursLimit = resp.Contract.WeeklyHoursLimit
+		if resp.Contract.HourlyChargeRate != nil {
+			currency = resp.Contract.HourlyChargeRate.Currency
+		}
+
+		for _, edge := range resp.ContractTimeReport.Edges {
+			if err := summary.add(edge.Node); err != nil {
+				return nil, err
+			}
+		}
+
+		pageInfo := resp.ContractTimeReport.PageInfo
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
+		}
+		variables["after"] = pageInfo.EndCursor
+	}
+
+	// Charges in the report are plain amounts in the contract's currency
+	for _, m := range []*models.Money{&summary.TrackedCharges, &summary.ManualCharges, &summary.TotalCharges} {
+		if m.Currency == "" {
+			m.Currency = currency
+		}
+	}
+	for i := range summary.Days {
+		if summary.Days[i].Charges.Currency == "" {
+			summary.Days[i].Charges.Currency = currency
+		}
+	}
+
+	if summary.WeeklyHoursLimit != nil && *summary.WeeklyHoursLimit > 0 {
+		summary.LimitUtilization = summary.TotalHours / float64(*summary.WeeklyHoursLimit)
+	}
+
+	return summary, nil
+}
+
+// add folds a report row into the summary
+func (s *WeeklySummary) add(row weeklyReportRow) error {
+	tracked := row.TotalOnlineHoursWorked
+	manual := row.TotalOfflineHoursWorked
+
+	s.TrackedHours += tracked
+	s.ManualHours += manual
+	s.TotalHours += tracked + manual
+
+	charges, err := row.TotalOnlineCharge.Add(row.TotalOfflineCharge)
+	if err != nil {
+		return errors.WrapError(err, "failed to add charges")
+	}
+	if s.TrackedCharges, err = s.TrackedCharges.Add(row.TotalOnlineCharge); err != nil {
+		return errors.WrapError(err, "failed to add charges")
+	}
+	if s.ManualCharges, err = s.ManualCharges.Add(row.TotalOfflineCharge); err != nil {
+		return errors.WrapError(err, "failed to add charges")
+	}
+	if s.TotalCharges, err = s.TotalCharges.Add(charges); err != nil {
+		return errors.WrapError(err, "failed to add charges")
+	}
+
+	worked, err := time.Parse("2006-01-02", firstN(row.DateWorkedOn, len("2006-01-02")))
+	if err != nil {
+		// Rows without a usable date still count towards the week totals
+		return nil
+	}
+	index := int(worked.Sub(s.WeekStart).Hours() / 24)
+	if index < 0 || index >= len(s.Days) {
+		return nil
+	}
+
+	day := &s.Days[index]
+	day.TrackedHours += tracked
+	day.ManualHours += manual
+	day.TotalHours += tracked + manual
+	if day.Charges, err = day.Charges.Add(charges); err != nil {
+		return errors.WrapError(err, "failed to add charges")
+	}
+
+	return nil
+}
+
+// firstN returns at most the first n bytes of s
+func firstN(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, monday, WeekStart(monday))
+	assert.Equal(t, monday, WeekStart(time.Date(2024, 1, 17, 13, 30, 0, 0, time.UTC)))
+	assert.Equal(t, monday, WeekStart(time.Date(2024, 1, 21, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, monday.AddDate(0, 0, 7), WeekStart(time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestGetWeeklySummary(t *testing.T) {
+	pages := []string{
+		`{"data":{
+			"contract":{"weeklyHoursLimit":10,"hourlyChargeRate":{"rawValue":"50.00","currency":"USD"}},
+			"contractTimeReport":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"edges":[
+				{"node":{"dateWorkedOn":"2024-01-15","totalOnlineHoursWorked":4,"totalOnlineCharge":200,"totalOfflineHoursWorked":0,"totalOfflineCharge":0}},
+				{"node":{"dateWorkedOn":"2024-01-16","totalOnlineHoursWorked":3,"totalOnlineCharge":"150.00","totalOfflineHoursWorked":1,"totalOfflineCharge":50}}
+			]}
+		}}`,
+		`{"data":{
+			"contract":{"weeklyHoursLimit":10,"hourlyChargeRate":{"rawValue":"50.00","currency":"USD"}},
+			"contractTimeReport":{"pageInfo":{"hasNextPage":false},"edges":[
+				{"node":{"dateWorkedOn":"2024-01-16","totalOnlineHoursWorked":0,"totalOnlineCharge":0,"totalOfflineHoursWorked":4,"totalOfflineCharge":200}}
+			]}
+		}}`,
+	}
+
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		fmt.Fprint(w, pages[len(reqs)-1])
+	}))
+	defer server.Close()
+
+	svc := NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	summary, err := svc.GetWeeklySummary(context.Background(), "contract-1", time.Date(2024, 1, 18, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "contract-1", reqs[0].Variables["contractId"])
+	assert.Equal(t, map[string]interface{}{
+		"rangeStart": "2024-01-15T00:00:00Z",
+		"rangeEnd":   "2024-01-21T00:00:00Z",
+	}, reqs[0].Variables["timeReportDate_bt"])
+	assert.Equal(t, "c1", reqs[1].Variables["after"])
+
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), summary.WeekStart)
+	assert.Equal(t, 7.0, summary.TrackedHours)
+	assert.Equal(t, 5.0, summary.ManualHours)
+	assert.Equal(t, 12.0, summary.TotalHours)
+	assert.True(t, summary.TrackedCharges.Equal(models.MustMoney("350", "USD")))
+	assert.True(t, summary.ManualCharges.Equal(models.MustMoney("250", "USD")))
+	assert.True(t, summary.TotalCharges.Equal(models.MustMoney("600", "USD")))
+
+	assert.InDelta(t, 1.2, summary.LimitUtilization, 1e-9)
+	assert.True(t, summary.OverLimit())
+
+	require.Len(t, summary.Days, 7)
+	assert.Equal(t, 4.0, summary.Days[0].TotalHours)
+	assert.Equal(t, 8.0, summary.Days[1].TotalHours)
+	assert.Equal(t, 5.0, summary.Days[1].ManualHours)
+	assert.True(t, summary.Days[1].Charges.Equal(models.MustMoney("400", "USD")))
+	assert.Zero(t, summary.Days[6].TotalHours)
+	assert.Equal(t, "USD", summary.Days[6].Charges.Currency)
+}
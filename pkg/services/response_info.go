@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headerServerTiming is the header the API reports server-side timings in
+const headerServerTiming = "Server-Timing"
+
+// ResponseInfo describes the HTTP response to a request, for debugging and
+// for reading headers, such as rate limit headers, the SDK does not expose
+type ResponseInfo struct {
+	// StatusCode is the HTTP status of the last attempt, zero if no
+	// response was received
+	StatusCode int
+	// Header holds the headers of the response to the last attempt
+	Header http.Header
+	// ServerTiming holds the durations of the metrics in the Server-Timing
+	// header by name, zero for metrics without one
+	ServerTiming map[string]time.Duration
+	// BodySize is the number of bytes of the response body read, after any
+	// decompression by the transport
+	BodySize int64
+	// Attempts is the number of times the request was sent
+	Attempts int
+	// Duration is the time taken, including rate limiting and retries
+	Duration time.Duration
+}
+
+// WithResponseInfo fills info with the HTTP response to the request. Set
+// through WithRequestOptions, it describes the last request made with the
+// context, so it must not then be shared by concurrent requests. Requests
+// in a batch share one response; queries served by StaleWhileError
+// describe the response that failed.
+func WithResponseInfo(info *ResponseInfo) RequestOption {
+	return func(o *requestOptions) {
+		o.responseInfo = info
+	}
+}
+
+// fill records resp, the response to the last of attempts, and the size of
+// its body read through body. resp and body may be nil.
+func (info *ResponseInfo) fill(resp *http.Response, body *responseReader, attempts int, duration time.Duration) {
+	*info = ResponseInfo{Attempts: attempts, Duration: duration}
+	if resp == nil {
+		return
+	}
+
+	info.StatusCode = resp.StatusCode
+	info.Header = resp.Header
+	info.ServerTiming = parseServerTiming(resp.Header.Values(headerServerTiming))
+	if body != nil {
+		info.BodySize = body.size
+	}
+}
+
+// parseServerTiming returns the durations of the metrics in Server-Timing
+// header values, such as `db;dur=53, cache;desc="hit";dur=2.5`, by name.
+// It returns nil if there are none.
+func parseServerTiming(values []string) map[string]time.Duration {
+	var timings map[string]time.Duration
+	for _, value := range values {
+		for _, metric := range strings.Split(value, ",") {
+			params := strings.Split(metric, ";")
+			name := strings.TrimSpace(params[0])
+			if name == "" {
+				continue
+			}
+
+			var dur time.Duration
+			for _, param := range params[1:] {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(strings.TrimSpace(key), "dur") {
+					ms, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(val), `"`), 64)
+					if err == nil {
+						dur = time.Duration(ms * float64(time.Millisecond))
+					}
+				}
+			}
+
+			if timings == nil {
+				timings = make(map[string]time.Duration)
+			}
+			timings[name] = dur
+		}
+	}
+	return timings
+}
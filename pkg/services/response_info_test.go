@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const responseInfoBody = `{"data":{"user":{"id":"u-1"}}}`
+
+func newResponseInfoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("Server-Timing", `db;dur=53, cache;desc="hit"`)
+		w.Header().Add("Server-Timing", "app;dur=1.5")
+		if r.Header.Get("X-Batch") != "" {
+			w.Write([]byte(`[` + responseInfoBody + `]`))
+			return
+		}
+		w.Write([]byte(responseInfoBody))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithResponseInfo(t *testing.T) {
+	server := newResponseInfoServer(t)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	var info ResponseInfo
+	err := client.Do(context.Background(), &GraphQLRequest{Query: "query GetUser { user { id } }"}, nil, WithResponseInfo(&info))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, info.StatusCode)
+	assert.Equal(t, "42", info.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, map[string]time.Duration{
+		"db":    53 * time.Millisecond,
+		"cache": 0,
+		"app":   1500 * time.Microsecond,
+	}, info.ServerTiming)
+	assert.Equal(t, int64(len(responseInfoBody)), info.BodySize)
+	assert.Equal(t, 1, info.Attempts)
+	assert.Positive(t, info.Duration)
+
+	// A batch is described by its one response
+	info = ResponseInfo{}
+	_, err = client.DoBatch(context.Background(), []*GraphQLRequest{{Query: "query GetUser { user { id } }"}}, []interface{}{nil},
+		WithHeader("X-Batch", "1"), WithResponseInfo(&info))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, info.StatusCode)
+	assert.Equal(t, int64(len(responseInfoBody)+2), info.BodySize)
+	assert.Equal(t, 1, info.Attempts)
+}
+
+func TestWithResponseInfoRetries(t *testing.T) {
+	server := newRetryServer(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	var info ResponseInfo
+	err := client.Do(context.Background(), &GraphQLRequest{Query: "query GetUser { user { id } }"}, nil, WithResponseInfo(&info))
+	require.Error(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, info.StatusCode, "the last attempt is described")
+	assert.Equal(t, 3, info.Attempts)
+	assert.Equal(t, int64(len(`{"message":"try again"}`)), info.BodySize)
+}
+
+func TestWithResponseInfoShared(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(responseInfoBody))
+	}))
+	defer server.Close()
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, DeduplicateQueries: true}
+
+	// Callers sharing one HTTP call each get its response described
+	infos := make([]ResponseInfo, 3)
+	var wg sync.WaitGroup
+	for i := range infos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := client.Do(context.Background(), &GraphQLRequest{Query: "query GetUser { user { id } }"}, nil, WithResponseInfo(&infos[i]))
+			assert.NoError(t, err)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, info := range infos {
+		assert.Equal(t, http.StatusOK, info.StatusCode)
+		assert.Equal(t, "req-1", info.Header.Get("X-Request-Id"))
+	}
+}
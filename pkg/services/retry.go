@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Delays between attempts double from retryBaseDelay up to retryMaxDelay.
+// Requests rejected for exceeding the rate limit back off on a longer
+// curve, unless the API says when to retry.
+const (
+	retryBaseDelay     = 500 * time.Millisecond
+	retryMaxDelay      = 8 * time.Second
+	rateLimitBaseDelay = 2 * time.Second
+	rateLimitMaxDelay  = 30 * time.Second
+)
+
+// retryableStatus returns true if a response with the HTTP status code may
+// succeed if the request is sent again
+func retryableStatus(code int) bool {
+	return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+}
+
+// retryDelay returns how long to wait before retrying after attempt, with
+// jitter so clients that failed together do not retry together. resp is
+// the response to the attempt, if there was one.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(resp.Header.Get(headerRetryAfter), time.Now()); retryAfter > 0 {
+			return retryAfter
+		}
+		return backoff(attempt, rateLimitBaseDelay, rateLimitMaxDelay)
+	}
+	return backoff(attempt, retryBaseDelay, retryMaxDelay)
+}
+
+// backoff returns a delay between half and all of base doubled for each
+// attempt after the first, up to maxDelay
+func backoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	d := min(base<<(attempt-1), maxDelay)
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// canRetry returns true if a retry after delay starts within
+// MaxRetryDuration of retryStart and before the deadline of ctx. Past
+// either, the error of the last attempt is more useful than a timeout.
+func (c *BaseClient) canRetry(ctx context.Context, retryStart time.Time, delay time.Duration) bool {
+	maxDuration := c.MaxRetryDuration
+	if maxDuration <= 0 {
+		maxDuration = DefaultMaxRetryDuration
+	}
+	next := time.Now().Add(delay)
+	if next.Sub(retryStart) > maxDuration {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || next.Before(deadline)
+}
+
+// sleepContext waits for d, or returns the error of ctx if it is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// withAttempts records the number of attempts made on err if the request
+// was retried
+func withAttempts(err error, attempts int) error {
+	if err == nil || attempts <= 1 {
+		return err
+	}
+	return &errors.RetryError{Attempts: attempts, Err: err}
+}
+
+// discardBody reads what remains of a response body, up to a limit, and
+// closes it so its connection can be reused
+func discardBody(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// retryServer answers with the given status codes in turn, then 200, and
+// records the request bodies it receives
+type retryServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func newRetryServer(t *testing.T, statuses ...int) *retryServer {
+	s := &retryServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message":"try again"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"id":"u-1"}}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	server := newRetryServer(t, http.StatusInternalServerError)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	req := &GraphQLRequest{
+		Query:     `query GetUser($id: ID!) { user(id: $id) { id } }`,
+		Variables: map[string]interface{}{"id": "u-1"},
+	}
+	var result struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	require.NoError(t, client.Do(context.Background(), req, &result))
+	assert.Equal(t, "u-1", result.User.ID)
+
+	// The retry sends the same body as the first attempt
+	want, err := json.Marshal(withOperationName(req))
+	require.NoError(t, err)
+	require.Len(t, server.bodies, 2)
+	assert.JSONEq(t, string(want), server.bodies[0])
+	assert.JSONEq(t, string(want), server.bodies[1])
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := newRetryServer(t, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, nil)
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, 3, errors.Attempts(err))
+	assert.Len(t, server.bodies, 3)
+	for _, body := range server.bodies {
+		assert.Contains(t, body, "GetUser")
+	}
+}
+
+func TestRetrySkipsMutations(t *testing.T) {
+	server := newRetryServer(t, http.StatusInternalServerError)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `mutation Pause { pauseContract(id: "c-1") }`}, nil)
+	assert.Error(t, err)
+	assert.Len(t, server.bodies, 1)
+
+	// Unless they are made safe to repeat
+	server.statuses = []int{http.StatusInternalServerError}
+	server.bodies = nil
+	err = client.Do(context.Background(), &GraphQLRequest{Query: `mutation Pause { pauseContract(id: "c-1") }`}, nil, WithIdempotencyKey("key-1"))
+	require.NoError(t, err)
+	require.Len(t, server.bodies, 2)
+	assert.Equal(t, server.bodies[0], server.bodies[1])
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	server := newRetryServer(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+	req := &GraphQLRequest{Query: `query GetUser { user { id } }`}
+
+	// Cancelled while waiting to retry
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := client.Do(ctx, req, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, errors.Attempts(err))
+	assert.Less(t, time.Since(start), retryBaseDelay/2)
+
+	// A retry that would start after the deadline is not made, returning
+	// the error of the last attempt
+	server.statuses = []int{http.StatusInternalServerError}
+	server.bodies = nil
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = client.Do(ctx, req, nil)
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Len(t, server.bodies, 1)
+}
+
+func TestMaxRetryDuration(t *testing.T) {
+	server := newRetryServer(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, MaxRetryDuration: time.Millisecond}
+
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, nil)
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1, errors.Attempts(err))
+	assert.Len(t, server.bodies, 1)
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt, want := range []time.Duration{retryBaseDelay, 2 * retryBaseDelay, 4 * retryBaseDelay} {
+		d := retryDelay(attempt+1, nil)
+		assert.GreaterOrEqual(t, d, want/2)
+		assert.LessOrEqual(t, d, want)
+	}
+	assert.LessOrEqual(t, retryDelay(20, nil), retryMaxDelay)
+
+	// Rate limited requests back off longer, or as long as the API asks
+	rateLimited := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	d := retryDelay(1, rateLimited)
+	assert.GreaterOrEqual(t, d, rateLimitBaseDelay/2)
+	assert.LessOrEqual(t, d, rateLimitBaseDelay)
+	assert.LessOrEqual(t, retryDelay(20, rateLimited), rateLimitMaxDelay)
+	assert.Greater(t, rateLimitMaxDelay, retryMaxDelay)
+
+	rateLimited.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, retryDelay(1, rateLimited))
+}
+
+func TestRateLimitedRetry(t *testing.T) {
+	server := newRetryServer(t, http.StatusTooManyRequests)
+	var events []RequestEvent
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		OnRequest:  func(e RequestEvent) { events = append(events, e) },
+	}
+
+	// The retry waits as long as the API asks
+	start := time.Now()
+	require.NoError(t, client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, nil))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Len(t, server.bodies, 2)
+
+	// Mutations are not retried, returning the rate limit error
+	server.statuses = []int{http.StatusTooManyRequests}
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `mutation Pause { pauseContract(id: "c-1") }`}, nil)
+	assert.ErrorIs(t, err, errors.ErrRateLimitExceeded)
+	var rateLimitErr *errors.RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, time.Second, rateLimitErr.RetryAfter)
+	assert.Equal(t, "try again", rateLimitErr.Message)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.Equal(t, 1, events[0].RateLimited)
+	assert.Equal(t, 1, events[1].Attempts)
+	assert.Equal(t, 1, events[1].RateLimited)
+
+	// Server errors are not counted as rate limiting
+	server.statuses = []int{http.StatusInternalServerError}
+	events = nil
+	require.NoError(t, client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, nil))
+	assert.Equal(t, []int{2, 0}, []int{events[0].Attempts, events[0].RateLimited})
+}
+
+func TestRetryAfterConnectionDropped(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+
+		// The first attempt loses its connection before a response
+		if n == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"id":"u-1"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+
+	var result struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	require.NoError(t, client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id } }`}, &result))
+	assert.Equal(t, "u-1", result.User.ID)
+	assert.Equal(t, 2, requests)
+
+	// Mutations are not resent, as the first attempt may have been applied
+	requests = 0
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `mutation PauseContract { pauseContract(contractId: "c-1") }`}, nil)
+	require.Error(t, err)
+	assert.True(t, errors.IsRetryable(err))
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// UnexpectedNull describes a null in a response decoded into a field that
+// cannot hold one, which is left as its zero value. It usually means the
+// query and the result type disagree with the schema.
+type UnexpectedNull struct {
+	// OperationName is the name of the operation, if it has one
+	OperationName string
+	// Path is the path of the field in the response data, such as
+	// contract.milestones[1].amount
+	Path string
+	// Type is the Go type of the field
+	Type string
+}
+
+// String returns a description of the null for diagnostics
+func (n UnexpectedNull) String() string {
+	return fmt.Sprintf("%s: unexpected null at %s (%s)", n.OperationName, n.Path, n.Type)
+}
+
+// jsonUnmarshaler is the type of json.Unmarshaler
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeChecked decodes a response like decodeResponse, first reporting
+// nulls in data that result cannot represent to OnUnexpectedNull. Data is
+// buffered to check it, so this is meant for development.
+func (c *BaseClient) decodeChecked(req *GraphQLRequest, body io.Reader, result interface{}, ext *responseExtensions) error {
+	var data json.RawMessage
+	err := c.decodeResponse(body, &data, ext)
+	if len(data) == 0 {
+		return err
+	}
+
+	// Partial data accompanying errors has nulls where fields failed
+	if err == nil {
+		c.checkShape(req, data, result)
+	}
+	if uerr := c.jsonCodec().Unmarshal(data, result); uerr != nil && err == nil {
+		return errors.WrapError(uerr, "failed to parse response")
+	}
+	return err
+}
+
+// checkShape reports each null in data where result has a field that
+// cannot hold one. Pointers, slices, maps and interfaces may be null.
+func (c *BaseClient) checkShape(req *GraphQLRequest, data []byte, result interface{}) {
+	if c.OnUnexpectedNull == nil || result == nil {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || value == nil {
+		return
+	}
+
+	findNulls(value, reflect.TypeOf(result), "", func(path string, t reflect.Type) {
+		c.OnUnexpectedNull(UnexpectedNull{OperationName: req.OperationName, Path: path, Type: t.String()})
+	})
+}
+
+// findNulls walks value alongside the Go type t it decodes into and calls
+// report for each null decoded into a type that cannot hold one
+func findNulls(value interface{}, t reflect.Type, path string, report func(string, reflect.Type)) {
+	if t.Kind() == reflect.Pointer {
+		if value == nil {
+			return
+		}
+		findNulls(value, t.Elem(), path, report)
+		return
+	}
+
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Interface:
+		default:
+			report(path, t)
+		}
+		return
+	}
+
+	// Types decoding themselves interpret their own contents
+	if reflect.PointerTo(t).Implements(jsonUnmarshaler) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if object, ok := value.(map[string]interface{}); ok {
+			findStructNulls(object, t, path, report)
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := value.([]interface{}); ok {
+			for i, item := range list {
+				findNulls(item, t.Elem(), path+"["+strconv.Itoa(i)+"]", report)
+			}
+		}
+	case reflect.Map:
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				findNulls(item, t.Elem(), joinPath(path, key), report)
+			}
+		}
+	}
+}
+
+// findStructNulls checks the fields of object that decode into the fields
+// of the struct type t, matching names as encoding/json does
+func findStructNulls(object map[string]interface{}, t reflect.Type, path string, report func(string, reflect.Type)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		// Embedded structs without a name contribute their fields
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				findStructNulls(object, embedded, path, report)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		value, ok := lookupField(object, name)
+		if !ok {
+			continue
+		}
+		findNulls(value, field.Type, joinPath(path, name), report)
+	}
+}
+
+// lookupField returns the value of the key matching name, preferring an
+// exact match over a case-insensitive one as encoding/json does
+func lookupField(object map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// joinPath appends a field name to a response path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func TestUnexpectedNulls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Batch") != "" {
+			w.Write([]byte(`[{"data":{"contract":{"id":null}}}]`))
+			return
+		}
+		w.Write([]byte(`{"data":{"contract":{
+			"id": "c-1",
+			"title": null,
+			"hourlyRate": null,
+			"createdDateTime": null,
+			"client": {"name": null, "companyName": null},
+			"milestones": [{"id": "m-1", "amount": 10}, {"id": "m-2", "amount": null}],
+			"tags": null,
+			"unknown": null
+		}}}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var nulls []UnexpectedNull
+	client := &BaseClient{
+		HTTPClient: server.Client(),
+		APIURL:     server.URL,
+		OnUnexpectedNull: func(n UnexpectedNull) {
+			mu.Lock()
+			defer mu.Unlock()
+			nulls = append(nulls, n)
+		},
+	}
+
+	type client_ struct {
+		Name        string  `json:"name"`
+		CompanyName *string `json:"companyName"`
+	}
+	type milestone struct {
+		ID     string  `json:"id"`
+		Amount float64 `json:"amount"`
+	}
+	type base struct {
+		ID string `json:"id"`
+	}
+	var result struct {
+		Contract struct {
+			base
+			Title           string          `json:"title"`
+			HourlyRate      *float64        `json:"hourlyRate"`
+			CreatedDateTime models.DateTime `json:"createdDateTime"`
+			Client          client_         `json:"client"`
+			Milestones      []milestone     `json:"milestones"`
+			Tags            []string        `json:"tags"`
+		} `json:"contract"`
+	}
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `query GetContract { contract { id } }`}, &result)
+	require.NoError(t, err)
+
+	// The response is still decoded
+	assert.Equal(t, "c-1", result.Contract.ID)
+	assert.Len(t, result.Contract.Milestones, 2)
+
+	assert.ElementsMatch(t, []UnexpectedNull{
+		{OperationName: "GetContract", Path: "contract.title", Type: "string"},
+		{OperationName: "GetContract", Path: "contract.createdDateTime", Type: "models.DateTime"},
+		{OperationName: "GetContract", Path: "contract.client.name", Type: "string"},
+		{OperationName: "GetContract", Path: "contract.milestones[1].amount", Type: "float64"},
+	}, nulls)
+	assert.Equal(t, "GetContract: unexpected null at contract.title (string)", nulls[0].String())
+
+	// Batches are checked too
+	nulls = nil
+	_, err = client.DoBatch(context.Background(), []*GraphQLRequest{{Query: `query GetContract { contract { id } }`}}, []interface{}{&result}, WithHeader("X-Batch", "1"))
+	require.NoError(t, err)
+	assert.Equal(t, []UnexpectedNull{{OperationName: "GetContract", Path: "contract.id", Type: "string"}}, nulls)
+}
+
+func TestUnexpectedNullsIgnoredWithErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"user":{"id":"u-1","name":null}},"errors":[{"message":"name not allowed","path":["user","name"]}]}`))
+	}))
+	defer server.Close()
+
+	called := false
+	client := &BaseClient{
+		HTTPClient:       server.Client(),
+		APIURL:           server.URL,
+		OnUnexpectedNull: func(UnexpectedNull) { called = true },
+	}
+
+	var result struct {
+		User struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	err := client.Do(context.Background(), &GraphQLRequest{Query: `query GetUser { user { id name } }`}, &result)
+	require.Error(t, err)
+	assert.False(t, called)
+	assert.Equal(t, "u-1", result.User.ID)
+}
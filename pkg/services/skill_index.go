@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSkillIndexPageSize is the number of skills fetched per request
+// when loading a SkillIndex
+const DefaultSkillIndexPageSize = 500
+
+// DefaultSkillIndexMaxAge is how long a SkillIndex serves suggestions
+// before reloading the skill ontology in the background
+const DefaultSkillIndexMaxAge = 24 * time.Hour
+
+// SkillIndexOptions configures a SkillIndex
+type SkillIndexOptions struct {
+	// PageSize defaults to DefaultSkillIndexPageSize
+	PageSize int
+	// MaxAge defaults to DefaultSkillIndexMaxAge
+	MaxAge time.Duration
+}
+
+// SkillIndex serves skill autocomplete suggestions from an in-memory copy
+// of the skill ontology, so that typing does not cost a request per
+// keystroke. The ontology is loaded on first use and reloaded in the
+// background once it is older than MaxAge. A SkillIndex is safe for
+// concurrent use.
+type SkillIndex struct {
+	metadata *MetadataService
+	opts     SkillIndexOptions
+
+	// loadMu serializes loads so concurrent first calls fetch once
+	loadMu sync.Mutex
+
+	mu         sync.RWMutex
+	skills     []OntologySkill
+	labels     []string
+	entries    []skillIndexEntry
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// skillIndexEntry maps a normalized label, or a word of one, to a skill
+type skillIndexEntry struct {
+	key   string
+	skill int
+	// word is true if key is a word of the label rather than all of it
+	word bool
+}
+
+// NewSkillIndex creates a skill index loaded through metadata
+func NewSkillIndex(metadata *MetadataService, opts SkillIndexOptions) *SkillIndex {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultSkillIndexPageSize
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultSkillIndexMaxAge
+	}
+	return &SkillIndex{metadata: metadata, opts: opts}
+}
+
+// Load fetches every skill page by page and replaces the index. Suggest
+// calls it on first use; call it directly to load the index up front.
+func (x *SkillIndex) Load(ctx context.Context) error {
+	x.loadMu.Lock()
+	defer x.loadMu.Unlock()
+	return x.load(ctx)
+}
+
+// ensureLoaded loads the index unless it has been loaded, so that
+// concurrent first calls fetch the skills once
+func (x *SkillIndex) ensureLoaded(ctx context.Context) error {
+	x.loadMu.Lock()
+	defer x.loadMu.Unlock()
+
+	x.mu.RLock()
+	loaded := !x.loadedAt.IsZero()
+	x.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	return x.load(ctx)
+}
+
+// load fetches every skill and replaces the index. The caller must hold
+// loadMu.
+func (x *SkillIndex) load(ctx context.Context) error {
+	var skills []OntologySkill
+	for offset := 0; ; offset += x.opts.PageSize {
+		page, err := x.metadata.GetSkills(ctx, x.opts.PageSize, offset)
+		if err != nil {
+			return err
+		}
+		skills = append(skills, page...)
+		if len(page) < x.opts.PageSize {
+			break
+		}
+	}
+
+	labels := make([]string, len(skills))
+	entries := make([]skillIndexEntry, 0, 2*len(skills))
+	for i, skill := range skills {
+		label := normalizeSkill(skill.PreferredLabel)
+		labels[i] = label
+		entries = append(entries, skillIndexEntry{key: label, skill: i})
+		words := skillWords(label)
+		if len(words) > 1 {
+			for _, w := range words[1:] {
+				entries = append(entries, skillIndexEntry{key: w, skill: i, word: true})
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	x.mu.Lock()
+	x.skills, x.labels, x.entries, x.loadedAt = skills, labels, entries, time.Now()
+	x.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of indexed skills
+func (x *SkillIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.skills)
+}
+
+// Suggest returns up to limit skills matching prefix, best first: an exact
+// label, then labels starting with prefix, then labels with a later word
+// starting with it and finally labels containing its letters in order.
+// Matching ignores case. Apart from loading the index on first use and
+// refreshing it in the background, Suggest makes no requests.
+func (x *SkillIndex) Suggest(ctx context.Context, prefix string, limit int) ([]OntologySkill, error) {
+	if err := x.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	x.refreshIfStale(ctx)
+
+	query := normalizeSkill(prefix)
+	if query == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	type match struct {
+		skill int
+		rank  int
+	}
+	best := make(map[int]int)
+	add := func(skill, rank int) {
+		if r, ok := best[skill]; !ok || rank < r {
+			best[skill] = rank
+		}
+	}
+
+	// Entries sharing the prefix are contiguous in the sorted index
+	start := sort.Search(len(x.entries), func(i int) bool { return x.entries[i].key >= query })
+	for i := start; i < len(x.entries) && strings.HasPrefix(x.entries[i].key, query); i++ {
+		e := x.entries[i]
+		switch {
+		case e.word:
+			add(e.skill, 2)
+		case e.key == query:
+			add(e.skill, 0)
+		default:
+			add(e.skill, 1)
+		}
+	}
+
+	// Fall back to fuzzy matching only if the prefixes did not fill the
+	// suggestions
+	if len(best) < limit {
+		for i, label := range x.labels {
+			if _, ok := best[i]; !ok && isSubsequence(query, label) {
+				add(i, 3)
+			}
+		}
+	}
+
+	matches := make([]match, 0, len(best))
+	for skill, rank := range best {
+		matches = append(matches, match{skill: skill, rank: rank})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		la, lb := x.skills[a.skill].PreferredLabel, x.skills[b.skill].PreferredLabel
+		if len(la) != len(lb) {
+			return len(la) < len(lb)
+		}
+		return la < lb
+	})
+
+	suggestions := make([]OntologySkill, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		suggestions = append(suggestions, x.skills[m.skill])
+	}
+	return suggestions, nil
+}
+
+// refreshIfStale reloads the index in the background if it is older than
+// MaxAge. Suggestions are served from the old index until the reload
+// finishes; if it fails, the next stale call tries again.
+func (x *SkillIndex) refreshIfStale(ctx context.Context) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.refreshing || time.Since(x.loadedAt) < x.opts.MaxAge {
+		return
+	}
+	x.refreshing = true
+
+	go func() {
+		x.Load(context.WithoutCancel(ctx))
+
+		x.mu.Lock()
+		x.refreshing = false
+		x.mu.Unlock()
+	}()
+}
+
+// normalizeSkill lower-cases a skill label and collapses its whitespace
+func normalizeSkill(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// skillWords splits a normalized label into words on spaces and
+// punctuation that separates words, keeping characters such as "+" and
+// "." that are part of names like "C++" and "Node.js"
+func skillWords(label string) []string {
+	return strings.FieldsFunc(label, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/' || r == '(' || r == ')' || r == ','
+	})
+}
+
+// isSubsequence returns true if the runes of needle appear in haystack in
+// order
+func isSubsequence(needle, haystack string) bool {
+	n := []rune(needle)
+	i := 0
+	for _, r := range haystack {
+		if i < len(n) && r == n[i] {
+			i++
+		}
+	}
+	return i == len(n)
+}
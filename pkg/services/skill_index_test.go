@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+var ontologySkills = []string{"Go", "Golang Testing", "Google Cloud Platform", "Django", "Mongo DB", "Logo Design", "C++"}
+
+// fakeSkillOntology serves ontologySkills page by page and counts the
+// requests
+func fakeSkillOntology(t *testing.T) (*MetadataService, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req struct {
+			Variables struct {
+				Limit  int `json:"limit"`
+				Offset int `json:"offset"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		page := []OntologySkill{}
+		for i := req.Variables.Offset; i < len(ontologySkills) && i < req.Variables.Offset+req.Variables.Limit; i++ {
+			page = append(page, OntologySkill{ID: models.ID("skill-" + ontologySkills[i]), PreferredLabel: ontologySkills[i]})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"ontologyBrowserSkills": page}})
+	}))
+	t.Cleanup(server.Close)
+
+	return NewMetadataService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL}), &calls
+}
+
+func labels(skills []OntologySkill) []string {
+	out := make([]string, len(skills))
+	for i, s := range skills {
+		out[i] = s.PreferredLabel
+	}
+	return out
+}
+
+func TestSkillIndexSuggest(t *testing.T) {
+	metadata, calls := fakeSkillOntology(t)
+	index := NewSkillIndex(metadata, SkillIndexOptions{PageSize: 3})
+	ctx := context.Background()
+
+	suggestions, err := index.Suggest(ctx, "go", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 7, index.Len())
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls), "7 skills in pages of 3")
+
+	// The exact label, then prefixes, then fuzzy matches, shortest first
+	assert.Equal(t, []string{"Go", "Golang Testing", "Google Cloud Platform", "Django", "Mongo DB", "Logo Design"}, labels(suggestions))
+
+	// Later words of a label match too
+	suggestions, err = index.Suggest(ctx, "  DESIGN ", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Logo Design"}, labels(suggestions))
+
+	suggestions, err = index.Suggest(ctx, "c+", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C++"}, labels(suggestions))
+
+	suggestions, err = index.Suggest(ctx, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls), "suggestions are served locally")
+}
+
+func TestSkillIndexRefresh(t *testing.T) {
+	metadata, calls := fakeSkillOntology(t)
+	index := NewSkillIndex(metadata, SkillIndexOptions{MaxAge: time.Millisecond})
+	ctx := context.Background()
+
+	_, err := index.Suggest(ctx, "go", 5)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	time.Sleep(5 * time.Millisecond)
+	suggestions, err := index.Suggest(ctx, "go", 5)
+	require.NoError(t, err)
+	assert.NotEmpty(t, suggestions, "the stale index is served while it reloads")
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(calls) == 2 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/url"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// maxStaleEntries bounds the number of responses kept for StaleWhileError
+const maxStaleEntries = 1024
+
+// Freshness reports whether a response was served from the stale-while-error
+// cache
+type Freshness struct {
+	// Stale is true if the request failed and an earlier response was
+	// returned instead
+	Stale bool
+	// Age is the age of the earlier response
+	Age time.Duration
+	// Err is the error the request failed with
+	Err error
+}
+
+// WithFreshness fills f with whether the response was served stale, so
+// callers can flag data that may be out of date
+func WithFreshness(f *Freshness) RequestOption {
+	return func(o *requestOptions) {
+		o.freshness = f
+	}
+}
+
+// WithStaleWhileError overrides BaseClient.StaleWhileError for the
+// request. Zero disables it.
+func WithStaleWhileError(window time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.staleWindow = window
+		if window == 0 {
+			o.staleWindow = -1
+		}
+	}
+}
+
+// staleEntry is a response kept for StaleWhileError
+type staleEntry struct {
+	data json.RawMessage
+	at   time.Time
+}
+
+// staleWindow returns how old a response may be to be served in place of
+// an error, or zero if stale responses are not served
+func (c *BaseClient) staleWindow(options *requestOptions) time.Duration {
+	if options.staleWindow != 0 {
+		return max(options.staleWindow, 0)
+	}
+	return c.StaleWhileError
+}
+
+// doStaleWhileError executes a query, keeping its response under key. If
+// the query fails because the API or the network does, a response kept
+// within window is decoded into result instead and nil is returned.
+func (c *BaseClient) doStaleWhileError(ctx context.Context, key string, window time.Duration, req *GraphQLRequest, result interface{}, opts []RequestOption, options *requestOptions) error {
+	if options.freshness != nil {
+		*options.freshness = Freshness{}
+	}
+
+	var data json.RawMessage
+	err := c.doFresh(ctx, req, &data, opts)
+	if err == nil {
+		if len(data) > 0 {
+			c.keepStale(key, data, window)
+		}
+		return c.decodeData(req, data, result)
+	}
+
+	entry, ok := c.lookupStale(key, window)
+	if !ok || !degradable(err) {
+		// Partial data accompanies GraphQL errors
+		if len(data) > 0 && result != nil {
+			if uerr := c.jsonCodec().Unmarshal(data, result); uerr != nil {
+				return stderrors.Join(err, errors.WrapError(uerr, "failed to unmarshal partial response data"))
+			}
+		}
+		return err
+	}
+
+	age := time.Since(entry.at)
+	if options.freshness != nil {
+		*options.freshness = Freshness{Stale: true, Age: age, Err: err}
+	}
+	if c.Logger != nil {
+		c.Logger.WarnContext(ctx, "serving stale response", "operation", req.OperationName, "age", age, "error", err.Error())
+	}
+	return c.decodeData(req, entry.data, result)
+}
+
+// decodeData unmarshals the data of a response into result
+func (c *BaseClient) decodeData(req *GraphQLRequest, data json.RawMessage, result interface{}) error {
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	c.checkShape(req, data, result)
+	if err := c.jsonCodec().Unmarshal(data, result); err != nil {
+		return errors.WrapError(err, "failed to unmarshal response data")
+	}
+	return nil
+}
+
+// keepStale keeps data under key, making room by dropping responses older
+// than window, then the oldest
+func (c *BaseClient) keepStale(key string, data json.RawMessage, window time.Duration) {
+	c.staleMu.Lock()
+	defer c.staleMu.Unlock()
+
+	if c.stale == nil {
+		c.stale = make(map[string]staleEntry)
+	}
+	if _, ok := c.stale[key]; !ok && len(c.stale) >= maxStaleEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.stale {
+			if time.Since(entry.at) > window {
+				delete(c.stale, k)
+			} else if oldestKey == "" || entry.at.Before(oldest) {
+				oldestKey, oldest = k, entry.at
+			}
+		}
+		if len(c.stale) >= maxStaleEntries {
+			delete(c.stale, oldestKey)
+		}
+	}
+	c.stale[key] = staleEntry{data: data, at: time.Now()}
+}
+
+// lookupStale returns the response kept under key if it is within window
+func (c *BaseClient) lookupStale(key string, window time.Duration) (staleEntry, bool) {
+	c.staleMu.Lock()
+	defer c.staleMu.Unlock()
+
+	entry, ok := c.stale[key]
+	if !ok || time.Since(entry.at) > window {
+		return staleEntry{}, false
+	}
+	return entry, true
+}
+
+// degradable returns true if err is a failure of the API or the network,
+// such as a server error, rate limiting or a timeout, rather than an
+// answer to the request
+func degradable(err error) bool {
+	if errors.IsRetryable(err) || stderrors.Is(err, errors.ErrWouldExceedDeadline) {
+		return true
+	}
+	var urlErr *url.Error
+	return stderrors.As(err, &urlErr)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestStaleWhileError(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch code := int(status.Load()); code {
+		case http.StatusOK:
+			w.Write([]byte(`{"data":{"user":{"id":"u-1","name":"Ann"}}}`))
+		case http.StatusNotFound:
+			w.Write([]byte(`{"errors":[{"message":"user not found"}]}`))
+		default:
+			w.WriteHeader(code)
+		}
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, StaleWhileError: time.Minute}
+	type user struct {
+		User struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	get := func(id string, opts ...RequestOption) (user, Freshness, error) {
+		var result user
+		var freshness Freshness
+		req := &GraphQLRequest{Query: `query GetUser($id: ID!) { user(id: $id) { id name } }`, Variables: map[string]interface{}{"id": id}}
+		err := client.Do(context.Background(), req, &result, append(opts, WithFreshness(&freshness), WithRetry(false))...)
+		return result, freshness, err
+	}
+
+	result, freshness, err := get("u-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ann", result.User.Name)
+	assert.False(t, freshness.Stale)
+
+	// The API fails: the earlier response is served, flagged as stale
+	status.Store(http.StatusServiceUnavailable)
+	result, freshness, err = get("u-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ann", result.User.Name)
+	assert.True(t, freshness.Stale)
+	assert.Less(t, freshness.Age, time.Minute)
+	var apiErr *errors.APIError
+	require.ErrorAs(t, freshness.Err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	// Only the response to the same query is served
+	_, _, err = get("u-2")
+	assert.Error(t, err)
+
+	// Not when disabled for the request
+	_, _, err = get("u-1", WithStaleWhileError(0))
+	assert.Error(t, err)
+
+	// Nor once the response is older than the window
+	_, _, err = get("u-1", WithStaleWhileError(time.Nanosecond))
+	assert.Error(t, err)
+
+	// Errors answering the request are returned as they are
+	status.Store(http.StatusNotFound)
+	_, freshness, err = get("u-1")
+	var gqlErrs *errors.GraphQLErrors
+	assert.ErrorAs(t, err, &gqlErrs)
+	assert.False(t, freshness.Stale)
+}
+
+func TestStaleWhileErrorPartialData(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, StaleWhileError: time.Minute}
+	var result struct {
+		User struct {
+			ID    string `json:"id"`
+			Email string `json:"email"`
+		} `json:"user"`
+	}
+	req := &GraphQLRequest{Query: `query GetUser { user { id email } }`}
+
+	// Partial data accompanying errors is decoded along with them
+	body.Store(`{"data":{"user":{"id":"u-1","email":null}},"errors":[{"message":"email hidden","path":["user","email"]}]}`)
+	err := client.Do(context.Background(), req, &result)
+	var gqlErrs *errors.GraphQLErrors
+	require.ErrorAs(t, err, &gqlErrs)
+	assert.Equal(t, "u-1", result.User.ID)
+
+	// Partial data that cannot be decoded is reported with the errors
+	body.Store(`{"data":{"user":{"id":"u-1","email":42}},"errors":[{"message":"email hidden","path":["user","email"]}]}`)
+	err = client.Do(context.Background(), req, &result)
+	require.ErrorAs(t, err, &gqlErrs)
+	assert.Contains(t, err.Error(), "failed to unmarshal partial response data")
+}
+
+func TestStaleWhileErrorSkipsMutations(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"pauseContract":true}}`))
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL, StaleWhileError: time.Minute}
+	req := &GraphQLRequest{Query: `mutation Pause { pauseContract(id: "c-1") }`}
+	require.NoError(t, client.Do(context.Background(), req, nil))
+	assert.Error(t, client.Do(context.Background(), req, nil))
+}
+
+func TestStaleEntriesBounded(t *testing.T) {
+	client := &BaseClient{}
+	for i := 0; i < maxStaleEntries+10; i++ {
+		client.keepStale(strconv.Itoa(i), []byte(`{}`), time.Hour)
+	}
+	assert.Len(t, client.stale, maxStaleEntries)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// DoStream executes a GraphQL request and calls fn for each element of the
+// array found by following path from the response data, with dec
+// positioned at the element; fn must decode exactly one value. Elements are
+// decoded as the response is read, so memory use does not grow with the
+// size of the array. A nil value anywhere along path yields no elements.
+//
+// An error returned by fn stops the stream and is returned as is. GraphQL
+// errors reported after the array are returned once it has been streamed.
+func (c *BaseClient) DoStream(ctx context.Context, req *GraphQLRequest, path []string, fn func(dec *json.Decoder) error, opts ...RequestOption) error {
+	return c.do(ctx, req, opts, func(body io.Reader, ext *responseExtensions) error {
+		return streamResponse(body, path, fn, ext)
+	})
+}
+
+// streamResponse walks a GraphQL response, streaming the array at path
+// below data to fn and decoding extensions into ext
+func streamResponse(body io.Reader, path []string, fn func(dec *json.Decoder) error, ext *responseExtensions) error {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var gqlErrors []errors.GraphQLError
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return errors.WrapError(err, "failed to parse response")
+		}
+
+		switch key {
+		case "data":
+			if err := streamPath(dec, path, fn); err != nil {
+				return err
+			}
+		case "errors":
+			if err := dec.Decode(&gqlErrors); err != nil {
+				return errors.WrapError(err, "failed to parse response")
+			}
+		case "extensions":
+			if err := dec.Decode(ext); err != nil {
+				return errors.WrapError(err, "failed to parse response")
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(gqlErrors) > 0 {
+		return &errors.GraphQLErrors{Errors: gqlErrors}
+	}
+
+	return nil
+}
+
+// streamPath follows path through nested objects and calls fn for each
+// element of the array it leads to
+func streamPath(dec *json.Decoder, path []string, fn func(dec *json.Decoder) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.WrapError(err, "failed to parse response")
+	}
+	if tok == nil {
+		return nil
+	}
+
+	if len(path) == 0 {
+		if tok != json.Delim('[') {
+			return fmt.Errorf("failed to parse response: expected array, got %v", tok)
+		}
+		for dec.More() {
+			if err := fn(dec); err != nil {
+				return err
+			}
+		}
+		return expectDelim(dec, ']')
+	}
+
+	if tok != json.Delim('{') {
+		return fmt.Errorf("failed to parse response: expected object at %q, got %v", path[0], tok)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return errors.WrapError(err, "failed to parse response")
+		}
+
+		if key == path[0] {
+			err = streamPath(dec, path[1:], fn)
+		} else {
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// expectDelim consumes the next token, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.WrapError(err, "failed to parse response")
+	}
+	if tok != delim {
+		return fmt.Errorf("failed to parse response: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next value
+func skipValue(dec *json.Decoder) error {
+	var skip json.RawMessage
+	if err := dec.Decode(&skip); err != nil {
+		return errors.WrapError(err, "failed to parse response")
+	}
+	return nil
+}
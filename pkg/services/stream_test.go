@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+const transactionHistoryResponse = `{"data":{"transactionHistory":{"extra":{"nested":[1,2]},"transactionDetail":{"transactionHistoryRow":[
+	{"rowNumber":1,"recordId":"r-1","type":"Hourly","transactionAmount":{"rawValue":"100.00","currency":"USD"}},
+	{"rowNumber":2,"recordId":"r-2","type":"Fixed Price","transactionAmount":{"rawValue":"250.50","currency":"USD"}},
+	{"rowNumber":3,"recordId":"r-3","type":"Service Fee","transactionAmount":{"rawValue":"-35.05","currency":"USD"}}
+]}}}}`
+
+func newStreamTestService(t *testing.T, body string) *ReportsService {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewReportsService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+}
+
+func TestGetTransactionHistoryStream(t *testing.T) {
+	svc := newStreamTestService(t, transactionHistoryResponse)
+
+	var rows []TransactionHistoryRow
+	err := svc.GetTransactionHistoryStream(context.Background(), TransactionHistoryInput{}, func(row TransactionHistoryRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "r-2", rows[1].RecordID)
+	assert.True(t, rows[2].TransactionAmount.Equal(usd("-35.05")))
+
+	// The buffered variant decodes the same rows
+	history, err := svc.GetTransactionHistory(context.Background(), TransactionHistoryInput{})
+	require.NoError(t, err)
+	assert.Equal(t, rows, history.TransactionDetail.TransactionHistoryRows)
+}
+
+func TestGetTransactionHistoryStreamStops(t *testing.T) {
+	svc := newStreamTestService(t, transactionHistoryResponse)
+
+	stop := stderrors.New("stop")
+	calls := 0
+	err := svc.GetTransactionHistoryStream(context.Background(), TransactionHistoryInput{}, func(row TransactionHistoryRow) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetTransactionHistoryStreamErrors(t *testing.T) {
+	svc := newStreamTestService(t, `{"data":{"transactionHistory":null},"errors":[{"message":"access denied"}]}`)
+
+	err := svc.GetTransactionHistoryStream(context.Background(), TransactionHistoryInput{}, func(row TransactionHistoryRow) error {
+		t.Fatal("unexpected row")
+		return nil
+	})
+	var gqlErrs *errors.GraphQLErrors
+	require.True(t, stderrors.As(err, &gqlErrs))
+	assert.Equal(t, "access denied", gqlErrs.Errors[0].Message)
+
+	svc = newStreamTestService(t, `{"data":{"transactionHistory":{"transactionDetail":{"transactionHistoryRow":{}}}}}`)
+	err = svc.GetTransactionHistoryStream(context.Background(), TransactionHistoryInput{}, func(row TransactionHistoryRow) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Subscriber runs GraphQL subscriptions. Each result is a raw GraphQL
+// response with data and errors. The channel is closed when the
+// subscription ends or ctx is done.
+type Subscriber interface {
+	Subscribe(ctx context.Context, query string, variables map[string]interface{}) (<-chan json.RawMessage, error)
+}
+
+// subscribe runs a subscription through client and sends the data of each
+// result, decoded into T, on the returned channel. Results with errors are
+// passed to onError, if set, and skipped.
+func subscribe[T any](ctx context.Context, client *BaseClient, req *GraphQLRequest, bufferSize int, onError func(error)) (<-chan T, error) {
+	if client.Subscriber == nil {
+		return nil, errors.ErrNoSubscriptions
+	}
+	if client.closed() {
+		return nil, errors.ErrClientClosed
+	}
+
+	ctx, cancel := client.workerContext(ctx)
+	results, err := client.Subscriber.Subscribe(ctx, req.Query, req.Variables)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan T, bufferSize)
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		for raw := range results {
+			var resp struct {
+				Data   *T                    `json:"data"`
+				Errors []errors.GraphQLError `json:"errors"`
+			}
+			err := json.Unmarshal(raw, &resp)
+			switch {
+			case err != nil:
+				err = errors.WrapError(err, "failed to parse subscription result")
+			case len(resp.Errors) > 0:
+				err = &errors.GraphQLErrors{Errors: resp.Errors}
+			case resp.Data == nil:
+				continue
+			}
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+
+			select {
+			case out <- *resp.Data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
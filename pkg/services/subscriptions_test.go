@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// fakeSubscriber replays results for every subscription
+type fakeSubscriber struct {
+	results   []string
+	query     string
+	variables map[string]interface{}
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, query string, variables map[string]interface{}) (<-chan json.RawMessage, error) {
+	f.query, f.variables = query, variables
+	out := make(chan json.RawMessage, len(f.results))
+	for _, r := range f.results {
+		out <- json.RawMessage(r)
+	}
+	close(out)
+	return out, nil
+}
+
+func TestSubscribeStories(t *testing.T) {
+	ctx := context.Background()
+
+	svc := NewMessagesService(&BaseClient{})
+	_, err := svc.SubscribeStories(ctx, "room-1", StreamOptions{})
+	assert.ErrorIs(t, err, errors.ErrNoSubscriptions)
+
+	sub := &fakeSubscriber{results: []string{
+		`{"data":{"roomStoryCreated":{"id":"story-1","message":"hello"}}}`,
+		`{"errors":[{"message":"not allowed"}]}`,
+		`{"data":{"roomStoryCreated":{"id":"story-2","message":"world"}}}`,
+	}}
+	svc = NewMessagesService(&BaseClient{Subscriber: sub})
+
+	_, err = svc.SubscribeStories(ctx, "", StreamOptions{})
+	assertValidationField(t, err, "roomId")
+
+	var errs []error
+	stories, err := svc.SubscribeStories(ctx, "room-1", StreamOptions{OnError: func(err error) { errs = append(errs, err) }})
+	require.NoError(t, err)
+
+	var ids []models.ID
+	for story := range stories {
+		ids = append(ids, story.ID)
+	}
+	assert.Equal(t, []models.ID{"story-1", "story-2"}, ids)
+	assert.Contains(t, sub.query, "roomStoryCreated")
+	assert.Equal(t, "room-1", sub.variables["roomId"])
+
+	require.Len(t, errs, 1)
+	var gqlErrs *errors.GraphQLErrors
+	assert.ErrorAs(t, errs[0], &gqlErrs)
+}
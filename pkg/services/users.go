@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
-	
+	"strings"
+	"sync"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
 	"github.com/rizome-dev/go-upwork/pkg/models"
 )
 
@@ -17,23 +20,8 @@ func NewUsersService(client *BaseClient) *UsersService {
 	return &UsersService{client: client}
 }
 
-// User represents a user in the Upwork system
-type User struct {
-	ID        models.ID       `json:"id"`
-	Nid       string   `json:"nid"`
-	Rid       string   `json:"rid"`
-	Name      string   `json:"name"`
-	FirstName string   `json:"firstName"`
-	LastName  string   `json:"lastName"`
-	Email     string   `json:"email"`
-	PhotoURL  string   `json:"photoUrl"`
-	PublicURL string   `json:"publicUrl"`
-	Location  models.Location `json:"location"`
-}
-
-
 // GetCurrentUser returns the current authenticated user
-func (s *UsersService) GetCurrentUser(ctx context.Context) (*User, error) {
+func (s *UsersService) GetCurrentUser(ctx context.Context) (*models.User, error) {
 	query := `
 		query GetCurrentUser {
 			user {
@@ -56,67 +44,120 @@ func (s *UsersService) GetCurrentUser(ctx context.Context) (*User, error) {
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 	}
-	
+
 	var resp struct {
-		User User `json:"user"`
+		User models.User `json:"user"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.User, nil
 }
 
 // GetUserByID returns a user by their ID
-func (s *UsersService) GetUserByID(ctx context.Context, userID string) (*User, error) {
-	query := `
-		query GetUserDetails($id: ID!) {
-			userDetails(id: $id) {
-				id
-				nid
-				rid
-				name
-				firstName
-				lastName
-				email
-				photoUrl
-				publicUrl
-				location {
-					country
-					state
-					city
-					timezone
-					offsetToUTC
+func (s *UsersService) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var resp struct {
+		UserDetails models.User `json:"userDetails"`
+	}
+
+	if err := s.client.Do(ctx, userDetailsRequest(userID), &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.UserDetails, nil
+}
+
+// userBatchSize is the number of user details looked up per batch request
+const userBatchSize = 25
+
+// UserLookup is the result of looking users up by email. Emails are keyed
+// as they were given.
+type UserLookup struct {
+	// Users holds the user of each email that was resolved
+	Users map[string]models.User
+	// NotFound lists the emails that belong to no user
+	NotFound []string
+	// Errors holds the error of each email whose lookup failed
+	Errors map[string]error
+}
+
+// userDetailsRequest returns the request for the details of a user
+func userDetailsRequest(userID string) *GraphQLRequest {
+	return &GraphQLRequest{
+		Query: `
+			query GetUserDetails($id: ID!) {
+				userDetails(id: $id) {
+					id
+					nid
+					rid
+					name
+					firstName
+					lastName
+					email
+					photoUrl
+					publicUrl
+					location {
+						country
+						state
+						city
+						timezone
+						offsetToUTC
+					}
 				}
 			}
-		}
-	`
-	
-	req := &GraphQLRequest{
-		Query: query,
+		`,
 		Variables: map[string]interface{}{
 			"id": userID,
 		},
 	}
-	
-	var resp struct {
-		UserDetails User `json:"userDetails"`
-	}
-	
-	if err := s.client.Do(ctx, req, &resp); err != nil {
+}
+
+// GetUsersByEmail returns users matching the given email addresses, in the
+// order of emails. Emails without a user are skipped. If some lookups fail,
+// the users that were found are returned with an *errors.MultiError whose
+// indexes refer to emails.
+func (s *UsersService) GetUsersByEmail(ctx context.Context, emails []string) ([]models.User, error) {
+	lookup, err := s.LookupUsersByEmail(ctx, emails, 0)
+	if err != nil {
 		return nil, err
 	}
-	
-	return &resp.UserDetails, nil
+
+	users := make([]models.User, 0, len(lookup.Users))
+	var errs []*errors.IndexedError
+	for i, email := range emails {
+		if user, ok := lookup.Users[email]; ok {
+			users = append(users, user)
+		} else if err, ok := lookup.Errors[email]; ok {
+			errs = append(errs, &errors.IndexedError{Index: i, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return users, &errors.MultiError{Errors: errs}
+	}
+	return users, nil
 }
 
-// GetUsersByEmail returns users matching the given email addresses
-func (s *UsersService) GetUsersByEmail(ctx context.Context, emails []string) ([]User, error) {
+// LookupUsersByEmail resolves emails to users with one request for their
+// IDs and batch requests for their details, with at most maxConcurrency
+// batches in flight (DefaultMaxConcurrency if zero or less). An error is
+// returned only if the IDs cannot be looked up; failures of individual
+// users are reported in UserLookup.Errors.
+func (s *UsersService) LookupUsersByEmail(ctx context.Context, emails []string, maxConcurrency int) (*UserLookup, error) {
+	lookup := &UserLookup{
+		Users:  make(map[string]models.User),
+		Errors: make(map[string]error),
+	}
+	if len(emails) == 0 {
+		return lookup, nil
+	}
+
 	query := `
 		query GetUsersByEmail($emails: [String!]!) {
 			userIdsByEmail(emails: $emails) {
@@ -125,44 +166,115 @@ func (s *UsersService) GetUsersByEmail(ctx context.Context, emails []string) ([]
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"emails": emails,
 		},
 	}
-	
+
 	var resp struct {
 		UserIdsByEmail []struct {
 			Email  string `json:"email"`
 			UserID string `json:"userId"`
 		} `json:"userIdsByEmail"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
-	// Get full user details for each ID
-	users := make([]User, 0, len(resp.UserIdsByEmail))
-	for _, emailUser := range resp.UserIdsByEmail {
-		user, err := s.GetUserByID(ctx, emailUser.UserID)
-		if err != nil {
-			// Continue on error, don't fail entire request
+
+	// The emails of each user, matched case-insensitively
+	userIDs := make(map[string]string, len(resp.UserIdsByEmail))
+	for _, match := range resp.UserIdsByEmail {
+		userIDs[strings.ToLower(match.Email)] = match.UserID
+	}
+	var ids []string
+	emailsOf := make(map[string][]string)
+	for _, email := range emails {
+		id, ok := userIDs[strings.ToLower(email)]
+		if !ok || id == "" {
+			lookup.NotFound = append(lookup.NotFound, email)
 			continue
 		}
-		users = append(users, *user)
+		if _, seen := emailsOf[id]; !seen {
+			ids = append(ids, id)
+		}
+		emailsOf[id] = append(emailsOf[id], email)
 	}
-	
-	return users, nil
+
+	// Look the details up in batches, each goroutine recording its own
+	// users and errors
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+	users := make([]*models.User, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrency)
+	for start := 0; start < len(ids); start += userBatchSize {
+		end := min(start+userBatchSize, len(ids))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for i := start; i < len(ids); i++ {
+				errs[i] = ctx.Err()
+			}
+			start = len(ids)
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.lookupUserDetails(ctx, ids[start:end], users[start:end], errs[start:end])
+		}(start, end)
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		for _, email := range emailsOf[id] {
+			switch {
+			case errs[i] != nil:
+				lookup.Errors[email] = errs[i]
+			case users[i] == nil:
+				lookup.NotFound = append(lookup.NotFound, email)
+			default:
+				lookup.Users[email] = *users[i]
+			}
+		}
+	}
+
+	return lookup, nil
 }
 
-// Company represents a company
-type Company struct {
-	ID          ID     `json:"id"`
-	Name        string `json:"name"`
-	CompanyName string `json:"companyName"`
+// lookupUserDetails looks up the details of ids in one batch request,
+// writing each user or error to the same index of users or errs
+func (s *UsersService) lookupUserDetails(ctx context.Context, ids []string, users []*models.User, errs []error) {
+	requests := make([]*GraphQLRequest, len(ids))
+	results := make([]interface{}, len(ids))
+	responses := make([]struct {
+		UserDetails *models.User `json:"userDetails"`
+	}, len(ids))
+	for i, id := range ids {
+		requests[i] = userDetailsRequest(id)
+		results[i] = &responses[i]
+	}
+
+	batch, err := s.client.DoBatch(ctx, requests, results)
+	for i := range ids {
+		switch {
+		case batch == nil:
+			errs[i] = err
+		case !batch.Succeeded(i):
+			errs[i] = batch.Errors[i]
+		default:
+			users[i] = responses[i].UserDetails
+		}
+	}
 }
 
 // CompanySelector represents a company in the selector
@@ -171,6 +283,11 @@ type CompanySelector struct {
 	OrganizationID string `json:"organizationId"`
 }
 
+// ToOrganization converts the selector entry to the canonical model
+func (c CompanySelector) ToOrganization() models.Organization {
+	return models.Organization{ID: models.ID(c.OrganizationID), Name: c.Title}
+}
+
 // GetCompanySelector returns the list of companies the user has access to
 func (s *UsersService) GetCompanySelector(ctx context.Context) ([]CompanySelector, error) {
 	query := `
@@ -183,43 +300,26 @@ func (s *UsersService) GetCompanySelector(ctx context.Context) ([]CompanySelecto
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 	}
-	
+
 	var resp struct {
 		CompanySelector struct {
 			Items []CompanySelector `json:"items"`
 		} `json:"companySelector"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
-	return resp.CompanySelector.Items, nil
-}
-
-// Organization represents an organization
-type Organization struct {
-	ID                ID                  `json:"id"`
-	Name              string              `json:"name"`
-	Company           Company             `json:"company"`
-	ChildOrganizations []Organization     `json:"childOrganizations"`
-	ParentOrganization *Organization      `json:"parentOrganization"`
-	Staff             []Staff            `json:"staff"`
-}
 
-// Staff represents a staff member
-type Staff struct {
-	User             User   `json:"user"`
-	StaffType        string `json:"staffType"`
-	ActivationStatus string `json:"activationStatus"`
+	return resp.CompanySelector.Items, nil
 }
 
 // GetOrganization returns the current organization
-func (s *UsersService) GetOrganization(ctx context.Context) (*Organization, error) {
+func (s *UsersService) GetOrganization(ctx context.Context) (*models.Organization, error) {
 	query := `
 		query GetOrganization {
 			organization {
@@ -241,24 +341,24 @@ func (s *UsersService) GetOrganization(ctx context.Context) (*Organization, erro
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 	}
-	
+
 	var resp struct {
-		Organization Organization `json:"organization"`
+		Organization models.Organization `json:"organization"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Organization, nil
 }
 
 // GetOrganizationStaff returns staff members for a child organization
-func (s *UsersService) GetOrganizationStaff(ctx context.Context, childOrgID string) ([]Staff, error) {
+func (s *UsersService) GetOrganizationStaff(ctx context.Context, childOrgID string) ([]models.Staff, error) {
 	query := `
 		query GetChildOrganizationStaff($childOrganizationId: ID!) {
 			organization {
@@ -280,35 +380,35 @@ func (s *UsersService) GetOrganizationStaff(ctx context.Context, childOrgID stri
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: query,
 		Variables: map[string]interface{}{
 			"childOrganizationId": childOrgID,
 		},
 	}
-	
+
 	var resp struct {
 		Organization struct {
 			ChildOrganization struct {
 				Staffs struct {
 					Edges []struct {
-						Node Staff `json:"node"`
+						Node models.Staff `json:"node"`
 					} `json:"edges"`
 				} `json:"staffs"`
 			} `json:"childOrganization"`
 		} `json:"organization"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return nil, err
 	}
-	
-	staff := make([]Staff, 0, len(resp.Organization.ChildOrganization.Staffs.Edges))
+
+	staff := make([]models.Staff, 0, len(resp.Organization.ChildOrganization.Staffs.Edges))
 	for _, edge := range resp.Organization.ChildOrganization.Staffs.Edges {
 		staff = append(staff, edge.Node)
 	}
-	
+
 	return staff, nil
 }
 
@@ -321,8 +421,28 @@ type InviteToTeamInput struct {
 	Message   string   `json:"message"`
 }
 
+// Validate checks the team and that at least one valid email is given
+func (in InviteToTeamInput) Validate() error {
+	if err := required("teamId", in.TeamID); err != nil {
+		return err
+	}
+	if len(in.Emails) == 0 {
+		return &errors.ValidationError{Field: "emails", Message: "at least one email is required"}
+	}
+	for i, email := range in.Emails {
+		if err := validateEmail(fmt.Sprintf("emails[%d]", i), email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // InviteToTeam invites users to a team
 func (s *UsersService) InviteToTeam(ctx context.Context, input InviteToTeamInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	mutation := `
 		mutation InviteToTeam($input: InviteToTeamInput!) {
 			inviteToTeam(input: $input) {
@@ -330,27 +450,27 @@ func (s *UsersService) InviteToTeam(ctx context.Context, input InviteToTeamInput
 			}
 		}
 	`
-	
+
 	req := &GraphQLRequest{
 		Query: mutation,
 		Variables: map[string]interface{}{
 			"input": input,
 		},
 	}
-	
+
 	var resp struct {
 		InviteToTeam struct {
 			Success bool `json:"success"`
 		} `json:"inviteToTeam"`
 	}
-	
+
 	if err := s.client.Do(ctx, req, &resp); err != nil {
 		return err
 	}
-	
+
 	if !resp.InviteToTeam.Success {
 		return fmt.Errorf("failed to invite to team")
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
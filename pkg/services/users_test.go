@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// newUsersServer serves userIdsByEmail for a@example.com, b@example.com and
+// c@example.com, and batches of userDetails where user-b fails and user-c
+// no longer exists. It counts HTTP requests.
+func newUsersServer(t *testing.T, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		var batch []GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.Write([]byte(`{"data": {"userIdsByEmail": [
+				{"email": "a@example.com", "userId": "user-a"},
+				{"email": "b@example.com", "userId": "user-b"},
+				{"email": "c@example.com", "userId": "user-c"}
+			]}}`))
+			return
+		}
+
+		responses := make([]string, len(batch))
+		for i, req := range batch {
+			switch id := req.Variables["id"]; id {
+			case "user-b":
+				responses[i] = `{"errors": [{"message": "not allowed"}]}`
+			case "user-c":
+				responses[i] = `{"data": {"userDetails": null}}`
+			default:
+				responses[i] = fmt.Sprintf(`{"data": {"userDetails": {"id": %q}}}`, id)
+			}
+		}
+		w.Write([]byte("[" + strings.Join(responses, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLookupUsersByEmail(t *testing.T) {
+	var calls int32
+	server := newUsersServer(t, &calls)
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	emails := []string{"a@example.com", "A@Example.com", "b@example.com", "c@example.com", "d@example.com"}
+	lookup, err := svc.LookupUsersByEmail(context.Background(), emails, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), calls, "one request for the IDs and one batch for the details")
+	require.Len(t, lookup.Users, 2)
+	assert.Equal(t, "user-a", string(lookup.Users["a@example.com"].ID))
+	assert.Equal(t, "user-a", string(lookup.Users["A@Example.com"].ID))
+	assert.ElementsMatch(t, []string{"c@example.com", "d@example.com"}, lookup.NotFound)
+	require.Len(t, lookup.Errors, 1)
+	assert.Contains(t, lookup.Errors["b@example.com"].Error(), "not allowed")
+}
+
+func TestLookupUsersByEmailBatches(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var batch []GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			matches := make([]string, 60)
+			for i := range matches {
+				matches[i] = fmt.Sprintf(`{"email": "%d@example.com", "userId": "user-%d"}`, i, i)
+			}
+			w.Write([]byte(`{"data": {"userIdsByEmail": [` + strings.Join(matches, ",") + `]}}`))
+			return
+		}
+		http.Error(w, "unavailable", http.StatusBadRequest)
+	}))
+	defer server.Close()
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	emails := make([]string, 60)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("%d@example.com", i)
+	}
+	lookup, err := svc.LookupUsersByEmail(context.Background(), emails, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1+3), calls, "60 users are looked up in 3 batches")
+	assert.Empty(t, lookup.Users)
+	assert.Len(t, lookup.Errors, 60, "a failed batch fails each of its emails")
+}
+
+func TestGetUsersByEmail(t *testing.T) {
+	var calls int32
+	server := newUsersServer(t, &calls)
+	svc := NewUsersService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL})
+
+	users, err := svc.GetUsersByEmail(context.Background(), []string{"c@example.com", "b@example.com", "a@example.com"})
+	require.Len(t, users, 1)
+	assert.Equal(t, "user-a", string(users[0].ID))
+
+	var multi *errors.MultiError
+	require.True(t, stderrors.As(err, &multi))
+	require.Len(t, multi.Errors, 1)
+	assert.Equal(t, 1, multi.Errors[0].Index, "indexes refer to the emails passed in")
+
+	users, err = svc.GetUsersByEmail(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
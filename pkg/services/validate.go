@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+// firstError returns the first non-nil error in errs
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// required checks that value is not blank
+func required(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return &errors.ValidationError{Field: field, Message: "is required"}
+	}
+	return nil
+}
+
+// validateEnum checks that value, if set, is one of its type's known
+// values. Use required as well if the field is mandatory.
+func validateEnum[T ~string](field string, value T, valid bool) error {
+	if value != "" && !valid {
+		return &errors.ValidationError{Field: field, Message: "is not a known value", Value: string(value)}
+	}
+	return nil
+}
+
+// validateAmount checks that amount, if set, is a positive decimal such
+// as "150.00"
+func validateAmount(field, amount string) error {
+	if amount == "" {
+		return nil
+	}
+	m, err := models.NewMoney(amount, "")
+	if err != nil {
+		return &errors.ValidationError{Field: field, Message: "must be a decimal amount", Value: amount}
+	}
+	if m.IsZero() || m.IsNegative() {
+		return &errors.ValidationError{Field: field, Message: "must be greater than zero", Value: amount}
+	}
+	return nil
+}
+
+// validateMoney checks that m is positive and that its currency, if set,
+// looks like an ISO 4217 code
+func validateMoney(field string, m models.Money) error {
+	if m.IsZero() || m.IsNegative() {
+		return &errors.ValidationError{Field: field, Message: "must be greater than zero", Value: m.String()}
+	}
+	if m.Currency != "" && !isCurrencyCode(m.Currency) {
+		return &errors.ValidationError{
+			Field:   field + ".currency",
+			Message: "must be a three-letter ISO 4217 currency code",
+			Value:   m.Currency,
+		}
+	}
+	return nil
+}
+
+// isCurrencyCode returns true if code is three upper-case ASCII letters
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateBudget checks that budget, if set, is a positive finite amount
+func validateBudget(field string, budget *float64) error {
+	if budget != nil && (!(*budget > 0) || math.IsInf(*budget, 1)) {
+		return &errors.ValidationError{Field: field, Message: "must be greater than zero", Value: *budget}
+	}
+	return nil
+}
+
+// validateBudgetRange checks that an hourly budget's minimum does not
+// exceed its maximum when both are set
+func validateBudgetRange(lo, hi *float64) error {
+	if lo != nil && hi != nil && *lo > *hi {
+		return &errors.ValidationError{
+			Field:   "hourlyBudgetMax",
+			Message: fmt.Sprintf("must not be less than hourlyBudgetMin (%g)", *lo),
+			Value:   *hi,
+		}
+	}
+	return nil
+}
+
+// validateDate checks that value, if set, is a date as YYYY-MM-DD or an
+// RFC 3339 timestamp
+func validateDate(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return nil
+	}
+	return &errors.ValidationError{Field: field, Message: "must be a date as YYYY-MM-DD", Value: value}
+}
+
+// validateNonNegative checks that n, if set, is not negative
+func validateNonNegative(field string, n *int) error {
+	if n != nil && *n < 0 {
+		return &errors.ValidationError{Field: field, Message: "must not be negative", Value: *n}
+	}
+	return nil
+}
+
+// validateEmail checks that address is a bare email address
+func validateEmail(field, address string) error {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Address != address {
+		return &errors.ValidationError{Field: field, Message: "must be an email address", Value: address}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+)
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func intPtr(n int) *int { return &n }
+
+func validJobPosting() CreateJobPostingInput {
+	return CreateJobPostingInput{
+		Title:           "Go developer",
+		Description:     "Build an API client",
+		CategoryID:      "category-1",
+		ContractType:    ContractTypeHourly,
+		HourlyBudgetMin: float64Ptr(40),
+		HourlyBudgetMax: float64Ptr(80),
+	}
+}
+
+func TestCreateJobPostingInputValidate(t *testing.T) {
+	require.NoError(t, validJobPosting().Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*CreateJobPostingInput)
+		field  string
+	}{
+		{"missing title", func(in *CreateJobPostingInput) { in.Title = " " }, "title"},
+		{"missing description", func(in *CreateJobPostingInput) { in.Description = "" }, "description"},
+		{"missing category", func(in *CreateJobPostingInput) { in.CategoryID = "" }, "categoryId"},
+		{"missing contract type", func(in *CreateJobPostingInput) { in.ContractType = "" }, "contractType"},
+		{"unknown contract type", func(in *CreateJobPostingInput) { in.ContractType = "hourly" }, "contractType"},
+		{"negative budget", func(in *CreateJobPostingInput) { in.HourlyBudgetMin = float64Ptr(-5) }, "hourlyBudgetMin"},
+		{"NaN budget", func(in *CreateJobPostingInput) { in.HourlyBudgetMax = float64Ptr(math.NaN()) }, "hourlyBudgetMax"},
+		{"min above max", func(in *CreateJobPostingInput) { in.HourlyBudgetMin = float64Ptr(100) }, "hourlyBudgetMax"},
+		{"fixed price without budget", func(in *CreateJobPostingInput) { in.ContractType = ContractTypeFixedPrice }, "fixedPriceBudget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := validJobPosting()
+			tt.modify(&input)
+			assertValidationField(t, input.Validate(), tt.field)
+		})
+	}
+}
+
+func TestCreateMilestoneInputValidate(t *testing.T) {
+	valid := CreateMilestoneInput{
+		ContractID:    "contract-1",
+		Description:   "First draft",
+		DepositAmount: "250.00",
+		DueDate:       "2024-06-30",
+	}
+	require.NoError(t, valid.Validate())
+
+	withTimestamp := valid
+	withTimestamp.DueDate = "2024-06-30T12:00:00Z"
+	require.NoError(t, withTimestamp.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*CreateMilestoneInput)
+		field  string
+	}{
+		{"missing contract", func(in *CreateMilestoneInput) { in.ContractID = "" }, "contractId"},
+		{"missing deposit", func(in *CreateMilestoneInput) { in.DepositAmount = "" }, "depositAmount"},
+		{"zero deposit", func(in *CreateMilestoneInput) { in.DepositAmount = "0" }, "depositAmount"},
+		{"malformed deposit", func(in *CreateMilestoneInput) { in.DepositAmount = "$250" }, "depositAmount"},
+		{"missing due date", func(in *CreateMilestoneInput) { in.DueDate = "" }, "dueDate"},
+		{"malformed due date", func(in *CreateMilestoneInput) { in.DueDate = "30/06/2024" }, "dueDate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid
+			tt.modify(&input)
+			assertValidationField(t, input.Validate(), tt.field)
+		})
+	}
+}
+
+func TestInputValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{ Validate() error }
+		field string
+	}{
+		{"story without room", CreateStoryInput{Message: "Hi"}, "roomId"},
+		{"story without message", CreateStoryInput{RoomID: "room-1"}, "message"},
+		{"room with unknown type", CreateRoomInput{RoomName: "Design", RoomType: "LOBBY"}, "roomType"},
+		{"room user without ID", CreateRoomInput{RoomName: "Design", Users: []RoomUserInput{{OrganizationID: "org-1"}}}, "users[0].userId"},
+		{"rating out of range", EndContractInput{ContractID: "contract-1", Reason: "COMPLETED", Rating: intPtr(6)}, "rating"},
+		{"rate with bad currency", RequestRateChangeInput{ContractID: "contract-1", HourlyRate: models.MustMoney("60", "usd")}, "hourlyRate.currency"},
+		{"counter offer without amount", RespondToDisputeInput{DisputeID: "dispute-1", Action: DisputeActionCounterOffer}, "counterAmount"},
+		{"unknown staff role", UpdateStaffRoleInput{OrganizationID: "org-1", UserID: "user-1", Role: "OWNER"}, "role"},
+		{"invite with bad email", InviteToTeamInput{TeamID: "team-1", Emails: []string{"a@example.com", "not-an-email"}}, "emails[1]"},
+		{"negative hours limit", UpdateHourlyLimitInput{ContractID: "contract-1", WeeklyHoursLimit: -1}, "weeklyHoursLimit"},
+		{"job update without changes", UpdateJobPostingInput{ID: "job-1"}, "input"},
+		{"job update with blank title", UpdateJobPostingInput{ID: "job-1", Title: models.Ptr(" ")}, "title"},
+		{"job update with unknown status", UpdateJobPostingInput{ID: "job-1", Status: models.Ptr(JobStatus("CLOSED"))}, "status"},
+		{"milestone edit with only a message", EditMilestoneInput{ID: "milestone-1", Message: "FYI"}, "input"},
+		{"milestone edit with bad date", EditMilestoneInput{ID: "milestone-1", DueDate: models.Ptr("soon")}, "dueDate"},
+		{"room update without changes", UpdateRoomInput{RoomID: "room-1"}, "input"},
+		{"team rename to blank", UpdateTeamInput{TeamID: "team-1", Name: models.Ptr("")}, "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationField(t, tt.input.Validate(), tt.field)
+		})
+	}
+}
+
+func TestPartialUpdateInputs(t *testing.T) {
+	// Unset fields are not sent, so they cannot be cleared by accident
+	var edit EditMilestoneInput
+	edit.ID = "milestone-1"
+	edit.SetDescription("Design").SetInstructions("")
+	require.NoError(t, edit.Validate())
+	data, err := json.Marshal(edit)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"milestone-1","description":"Design","instructions":""}`, string(data))
+
+	job := UpdateJobPostingInput{ID: "job-1"}
+	job.SetSkills().SetHourlyBudget(40, 80)
+	require.NoError(t, job.Validate())
+	data, err = json.Marshal(job)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"job-1","skills":[],"hourlyBudgetMin":40,"hourlyBudgetMax":80}`, string(data))
+
+	var reqs []GraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		w.Write([]byte(`{"data":{"updateRoom":{"id":"room-1","roomName":"Design"}}}`))
+	}))
+	defer server.Close()
+
+	room := UpdateRoomInput{RoomID: "room-1"}
+	_, err = NewMessagesService(&BaseClient{HTTPClient: server.Client(), APIURL: server.URL}).UpdateRoom(context.Background(), *room.SetName("Design"))
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	assert.Equal(t, map[string]interface{}{"roomId": "room-1", "roomName": "Design"}, reqs[0].Variables["input"])
+}
+
+func TestValidationPreventsRequest(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	client := &BaseClient{HTTPClient: server.Client(), APIURL: server.URL}
+	ctx := context.Background()
+
+	_, err := NewJobsService(client).CreateJobPosting(ctx, CreateJobPostingInput{Title: "Go developer"})
+	assertValidationField(t, err, "description")
+
+	_, err = NewContractsService(client).CreateMilestone(ctx, CreateMilestoneInput{ContractID: "contract-1"})
+	assertValidationField(t, err, "description")
+
+	_, err = NewMessagesService(client).SendMessage(ctx, CreateStoryInput{RoomID: "room-1"})
+	assertValidationField(t, err, "message")
+
+	assert.Zero(t, calls)
+}
+
+func assertValidationField(t *testing.T, err error, field string) {
+	t.Helper()
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr), "expected a ValidationError, got %v", err)
+	assert.Equal(t, field, validationErr.Field)
+}
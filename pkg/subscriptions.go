@@ -0,0 +1,90 @@
+package upwork
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/rizome-dev/go-upwork/internal/graphql"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Subscribe runs a raw GraphQL subscription over a WebSocket shared by all
+// subscriptions of the client, which is opened on first use. Each result
+// is a GraphQL response with data and errors. Lost connections are
+// re-established and subscriptions resumed; the channel is closed when the
+// server ends the subscription, ctx is done or the client is closed.
+func (c *Client) Subscribe(ctx context.Context, query string, variables map[string]interface{}) (<-chan json.RawMessage, error) {
+	sc, err := c.subscriptionClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sc.Subscribe(ctx, query, variables)
+}
+
+// subscriptionClient returns the connected subscription client, connecting
+// it if needed
+func (c *Client) subscriptionClient(ctx context.Context) (*graphql.SubscriptionClient, error) {
+	c.subscriptionsMu.Lock()
+	defer c.subscriptionsMu.Unlock()
+
+	select {
+	case <-c.closed:
+		return nil, errors.ErrClientClosed
+	default:
+	}
+
+	if c.subscriptions != nil {
+		return c.subscriptions, nil
+	}
+
+	url := c.subscriptionURL
+	if url == "" {
+		url = websocketURL(c.apiURL)
+	}
+
+	sc := graphql.NewSubscriptionClient(graphql.SubscriptionConfig{
+		URL:    url,
+		Header: c.subscriptionHeader,
+	})
+	if err := sc.Connect(ctx); err != nil {
+		return nil, errors.WrapError(err, "failed to open subscription connection")
+	}
+
+	c.subscriptions = sc
+	return sc, nil
+}
+
+// subscriptionHeader returns the headers the subscription connection is
+// opened with, using the current token
+func (c *Client) subscriptionHeader(ctx context.Context) (http.Header, error) {
+	c.mu.RLock()
+	src, orgID := c.authSource, c.organizationID
+	c.mu.RUnlock()
+
+	header := http.Header{}
+	if src != nil {
+		token, err := src.Token()
+		if err != nil {
+			return nil, errors.WrapError(err, "failed to obtain token")
+		}
+		header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	}
+	if orgID != "" {
+		header.Set("X-Upwork-API-TenantId", orgID)
+	}
+	return header, nil
+}
+
+// websocketURL converts an http or https URL to ws or wss
+func websocketURL(url string) string {
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		return "wss://" + strings.TrimPrefix(url, "https://")
+	case strings.HasPrefix(url, "http://"):
+		return "ws://" + strings.TrimPrefix(url, "http://")
+	default:
+		return url
+	}
+}
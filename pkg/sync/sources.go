@@ -0,0 +1,173 @@
+package sync
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// DefaultTimeReportLookback is how far before the checkpoint time reports
+// are re-read by default. Freelancers can edit and add manual time for the
+// current and previous billing week.
+const DefaultTimeReportLookback = 14 * 24 * time.Hour
+
+// Contracts returns a puller of the contracts visible to the user. Every
+// pull lists all contracts and emits those modified since the checkpoint,
+// as created if they were also created since.
+func Contracts(svc *services.ContractsService, opts Options) *Puller[services.Contract] {
+	p := newPuller[services.Contract]("contracts", opts)
+	p.list = func(ctx context.Context, from, to time.Time, fn func(services.Contract) error) error {
+		input := services.ListContractsInput{Pagination: &models.PaginationInput{First: p.opts.PageSize}}
+		for {
+			page, err := svc.ListContracts(ctx, input)
+			if err != nil {
+				return err
+			}
+			for _, edge := range page.Edges {
+				if err := fn(edge.Node); err != nil {
+					return err
+				}
+			}
+
+			next := page.PageInfo.EndCursor
+			if !page.PageInfo.HasNextPage || next == "" || next == input.Pagination.After {
+				return nil
+			}
+			input.Pagination = &models.PaginationInput{First: p.opts.PageSize, After: next}
+		}
+	}
+	p.key = func(c services.Contract) string { return string(c.ID) }
+	p.modified = func(c services.Contract) time.Time {
+		if t := timeOf(c.ModifiedDateTime); !t.IsZero() {
+			return t
+		}
+		return timeOf(c.CreatedDateTime)
+	}
+	p.created = func(c services.Contract) time.Time { return timeOf(c.CreatedDateTime) }
+	return p
+}
+
+// Transactions returns a puller of the transaction history of the given
+// accounting entities (see ReportsService.ListAccountingEntities).
+// Transactions do not change, so each is emitted once, as created.
+func Transactions(svc *services.ReportsService, accountingEntityIDs []string, opts Options) *Puller[services.TransactionHistoryRow] {
+	p := newPuller[services.TransactionHistoryRow]("transactions", opts)
+	p.list = func(ctx context.Context, from, to time.Time, fn func(services.TransactionHistoryRow) error) error {
+		return svc.GetTransactionHistoryStream(ctx, services.TransactionHistoryInput{
+			AccountingEntityIDs: accountingEntityIDs,
+			DateRange:           models.DateRange{Start: from, End: to},
+		}, fn)
+	}
+	p.key = func(row services.TransactionHistoryRow) string { return row.RecordID }
+	p.modified = func(row services.TransactionHistoryRow) time.Time { return timeOf(row.TransactionCreationDate) }
+	return p
+}
+
+// TimeReports returns a puller of the time reports of an organization.
+// Hours are edited after the day they were worked, so each pull re-reads
+// Options.Lookback (DefaultTimeReportLookback if zero) before the
+// checkpoint and emits the rows that were added, changed or removed.
+func TimeReports(svc *services.ReportsService, organizationID string, opts Options) *Puller[services.TimeReport] {
+	p := newPuller[services.TimeReport]("time_reports", opts)
+	p.lookback = opts.Lookback
+	if p.lookback <= 0 {
+		p.lookback = DefaultTimeReportLookback
+	}
+	p.list = func(ctx context.Context, from, to time.Time, fn func(services.TimeReport) error) error {
+		input := services.TimeReportInput{
+			OrganizationID: organizationID,
+			DateRange:      models.DateRange{Start: from, End: to},
+			Pagination:     &models.PaginationInput{First: p.opts.PageSize},
+		}
+		for {
+			page, err := svc.GetTimeReport(ctx, input)
+			if err != nil {
+				return err
+			}
+			for _, edge := range page.Edges {
+				if err := fn(edge.Node); err != nil {
+					return err
+				}
+			}
+
+			next := page.PageInfo.EndCursor
+			if !page.PageInfo.HasNextPage || next == "" || next == input.Pagination.After {
+				return nil
+			}
+			input.Pagination = &models.PaginationInput{First: p.opts.PageSize, After: next}
+		}
+	}
+	// A row is the time of a freelancer on a contract's task on a day
+	p.key = func(r services.TimeReport) string {
+		return strings.Join([]string{string(r.Contract.ID), string(r.Freelancer.ID), r.DateWorkedOn.String(), r.Task}, "/")
+	}
+	p.modified = func(r services.TimeReport) time.Time { return timeOf(r.DateWorkedOn) }
+	return p
+}
+
+// Message is a story posted to a room
+type Message struct {
+	RoomID models.ID
+	services.Story
+}
+
+// Messages returns a puller of the messages in the user's rooms. Each
+// pull lists the rooms and reads the latest Options.PageSize stories of
+// those with a story since the checkpoint, emitting the stories posted or
+// edited since. Rooms with more new stories than that between pulls lose
+// the older ones, so pull often or raise the page size.
+func Messages(svc *services.MessagesService, opts Options) *Puller[Message] {
+	p := newPuller[Message]("messages", opts)
+	p.list = func(ctx context.Context, from, to time.Time, fn func(Message) error) error {
+		pagination := &models.PaginationInput{First: p.opts.PageSize}
+		for {
+			page, err := svc.ListRooms(ctx, nil, pagination, "")
+			if err != nil {
+				return err
+			}
+			for _, edge := range page.Edges {
+				room := edge.Node
+				if room.LatestStory == nil || timeOf(room.LatestStory.CreatedDateTime).Before(from) {
+					continue
+				}
+
+				stories, err := svc.GetRoomStories(ctx, string(room.ID), &models.PaginationInput{First: p.opts.PageSize})
+				if err != nil {
+					return err
+				}
+				for _, story := range stories {
+					if err := fn(Message{RoomID: room.ID, Story: story}); err != nil {
+						return err
+					}
+				}
+			}
+
+			next := page.PageInfo.EndCursor
+			if !page.PageInfo.HasNextPage || next == "" || next == pagination.After {
+				return nil
+			}
+			pagination = &models.PaginationInput{First: p.opts.PageSize, After: next}
+		}
+	}
+	p.key = func(m Message) string { return string(m.ID) }
+	p.modified = func(m Message) time.Time {
+		if t := timeOf(m.UpdatedDateTime); !t.IsZero() {
+			return t
+		}
+		return timeOf(m.CreatedDateTime)
+	}
+	p.created = func(m Message) time.Time { return timeOf(m.CreatedDateTime) }
+	return p
+}
+
+// timeOf returns the time of d, or zero if it is unset or not a time
+func timeOf(d models.DateTime) time.Time {
+	t, err := d.Time()
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
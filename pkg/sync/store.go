@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	stdsync "sync"
+)
+
+// Store persists checkpoints between runs. Implementations backed by the
+// warehouse itself can save a checkpoint in the same transaction as the
+// changes it covers.
+type Store interface {
+	// Load returns the checkpoint saved under name, or nil if none has
+	// been saved
+	Load(ctx context.Context, name string) (*Checkpoint, error)
+
+	// Save stores cp under name, replacing any previous checkpoint
+	Save(ctx context.Context, name string, cp Checkpoint) error
+}
+
+// Stores implement Store
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*FileStore)(nil)
+)
+
+// MemoryStore is a Store that keeps checkpoints in memory, for tests and
+// processes that sync in a loop
+type MemoryStore struct {
+	mu          stdsync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryStore returns an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+// Load returns the checkpoint saved under name
+func (s *MemoryStore) Load(ctx context.Context, name string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp, ok := s.checkpoints[name]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// Save stores cp under name
+func (s *MemoryStore) Save(ctx context.Context, name string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[name] = cp
+	return nil
+}
+
+// FileStore is a Store that keeps each checkpoint as a JSON file in a
+// directory
+type FileStore struct {
+	dir string
+	mu  stdsync.Mutex
+}
+
+// NewFileStore returns a store that keeps checkpoints in dir, which is
+// created on the first Save
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("checkpoint directory is required")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load reads the checkpoint saved under name. A missing file is not an
+// error and yields a nil checkpoint.
+func (s *FileStore) Load(ctx context.Context, name string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path(name), err)
+	}
+	return &cp, nil
+}
+
+// Save atomically replaces the checkpoint file of name
+func (s *FileStore) Save(ctx context.Context, name string, cp Checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("creating checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(name)); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of the checkpoint of name, escaped so any name is
+// a file in the directory
+func (s *FileStore) path(name string) string {
+	return filepath.Join(s.dir, url.PathEscape(name)+".json")
+}
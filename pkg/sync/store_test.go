@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "checkpoints")
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cp, err := store.Load(ctx, "time_reports/org-1")
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	want := Checkpoint{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Keys: []string{"a"}}
+	require.NoError(t, store.Save(ctx, "time_reports/org-1", want))
+	cp, err = store.Load(ctx, "time_reports/org-1")
+	require.NoError(t, err)
+	assert.Equal(t, want, *cp)
+
+	// Names are escaped into a single file, leaving no temporary files
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "time_reports%2Forg-1.json", entries[0].Name())
+
+	_, err = NewFileStore("")
+	assert.Error(t, err)
+}
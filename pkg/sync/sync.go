@@ -0,0 +1,353 @@
+// Package sync pulls contracts, transactions, time reports and messages
+// incrementally, for loading into a warehouse. Each Puller emits the
+// records that are new or changed since a Checkpoint, and Sync persists
+// the checkpoint in a Store so the next run resumes where the last one
+// finished:
+//
+//	puller := sync.Contracts(client.Contracts, sync.Options{Store: store})
+//	_, err := puller.Sync(ctx, func(c sync.Change[services.Contract]) error {
+//		return warehouse.Upsert(c.Key, c.Record)
+//	})
+//
+// Delivery is at least once: a checkpoint is saved only after every change
+// was handled, so a failed run is repeated in full. Key records by
+// Change.Key to make loading idempotent.
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+// Op is the kind of a change
+type Op string
+
+const (
+	// OpCreate is a record seen for the first time
+	OpCreate Op = "CREATE"
+	// OpUpdate is a record changed since it was last seen
+	OpUpdate Op = "UPDATE"
+	// OpDelete is a record that disappeared. Only sources that re-read a
+	// window, such as time reports, report deletions, and the change has
+	// no record.
+	OpDelete Op = "DELETE"
+)
+
+// DefaultHistory is how far back the first pull of a source read by date
+// range reaches when Options.Start is not set
+const DefaultHistory = 90 * 24 * time.Hour
+
+// defaultPageSize is the page size used when Options.PageSize is not set
+const defaultPageSize = 100
+
+// Change is a record that is new, changed or deleted since a checkpoint
+type Change[T any] struct {
+	// Source names the puller, e.g. "contracts"
+	Source string
+	Op     Op
+	// Key identifies the record within its source
+	Key string
+	// Time is when the record was created or last changed
+	Time   time.Time
+	Record T
+}
+
+// Checkpoint records how far a puller has read. It is JSON encodable, and
+// Cursor encodes it as an opaque string.
+type Checkpoint struct {
+	// Time is the high-water mark: changes up to it have been read
+	Time time.Time `json:"time"`
+	// Keys are the records read at exactly Time, so they are not emitted
+	// again when the next pull starts there
+	Keys []string `json:"keys,omitempty"`
+	// Digests hold a digest of each record in the lookback window of a
+	// source whose records change without a new time
+	Digests map[string]string `json:"digests,omitempty"`
+}
+
+// IsZero returns true for the checkpoint of a source never pulled
+func (c Checkpoint) IsZero() bool {
+	return c.Time.IsZero() && len(c.Keys) == 0 && len(c.Digests) == 0
+}
+
+// Cursor returns the checkpoint as an opaque string for ParseCursor
+func (c Checkpoint) Cursor() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor parses a cursor returned by Checkpoint.Cursor. An empty
+// cursor is the zero checkpoint.
+func ParseCursor(cursor string) (Checkpoint, error) {
+	var c Checkpoint
+	if cursor == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return Checkpoint{}, &errors.ValidationError{Field: "cursor", Message: "is not a sync cursor", Value: cursor}
+	}
+	return c, nil
+}
+
+// Options configures a puller
+type Options struct {
+	// Store persists checkpoints for Sync, a MemoryStore if nil
+	Store Store
+	// Name is the name the checkpoint is stored under, the source name if
+	// empty. Pullers of the same source with different settings, e.g.
+	// organizations, need different names.
+	Name string
+	// Start is where the first pull of a source read by date range starts,
+	// DefaultHistory ago if zero
+	Start time.Time
+	// Lookback is how far before the checkpoint a source whose records
+	// change in place is re-read for changes, the source's default if zero
+	Lookback time.Duration
+	// PageSize is the number of records requested per page
+	PageSize int
+	// Now returns the current time, time.Now if nil
+	Now func() time.Time
+}
+
+// Puller pulls the changes of one source. Create one with Contracts,
+// Transactions, TimeReports or Messages.
+type Puller[T any] struct {
+	source string
+	opts   Options
+
+	// list calls fn with every record changed between from and to
+	list func(ctx context.Context, from, to time.Time, fn func(T) error) error
+	// key identifies a record
+	key func(T) string
+	// modified returns when a record was last changed
+	modified func(T) time.Time
+	// created returns when a record was created, or is nil for sources
+	// whose records never change
+	created func(T) time.Time
+	// lookback is set for sources whose records change in place without a
+	// new time, which are re-read from this long before the checkpoint and
+	// compared by digest
+	lookback time.Duration
+}
+
+// newPuller returns a puller of source with opts defaulted
+func newPuller[T any](source string, opts Options) *Puller[T] {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.Name == "" {
+		opts.Name = source
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Puller[T]{source: source, opts: opts}
+}
+
+// Source returns the name of the source, e.g. "contracts"
+func (p *Puller[T]) Source() string {
+	return p.source
+}
+
+// Checkpoint returns the stored checkpoint, or the zero checkpoint if the
+// source has not been synced
+func (p *Puller[T]) Checkpoint(ctx context.Context) (Checkpoint, error) {
+	cp, err := p.opts.Store.Load(ctx, p.opts.Name)
+	if err != nil || cp == nil {
+		return Checkpoint{}, err
+	}
+	return *cp, nil
+}
+
+// Sync pulls the changes since the stored checkpoint, calls fn with each,
+// and stores and returns the new checkpoint once all were handled. If fn
+// returns an error the pull stops, the checkpoint is left as it was and
+// the error is returned.
+func (p *Puller[T]) Sync(ctx context.Context, fn func(Change[T]) error) (Checkpoint, error) {
+	cp, err := p.Checkpoint(ctx)
+	if err != nil {
+		return Checkpoint{}, errors.WrapError(err, "failed to load "+p.opts.Name+" checkpoint")
+	}
+
+	next, err := p.Since(ctx, cp, fn)
+	if err != nil {
+		return cp, err
+	}
+	if err := p.opts.Store.Save(ctx, p.opts.Name, next); err != nil {
+		return cp, errors.WrapError(err, "failed to save "+p.opts.Name+" checkpoint")
+	}
+	return next, nil
+}
+
+// SinceTime calls fn with each change after t and returns the checkpoint
+// to pull from next. Nothing is stored.
+func (p *Puller[T]) SinceTime(ctx context.Context, t time.Time, fn func(Change[T]) error) (Checkpoint, error) {
+	return p.Since(ctx, Checkpoint{Time: t}, fn)
+}
+
+// Since calls fn with each change after cp and returns the checkpoint to
+// pull from next. Nothing is stored; use Sync to resume from the store. A
+// zero checkpoint emits every record, back to Options.Start for sources
+// read by date range.
+func (p *Puller[T]) Since(ctx context.Context, cp Checkpoint, fn func(Change[T]) error) (Checkpoint, error) {
+	now := p.opts.Now()
+	from := cp.Time
+	if from.IsZero() {
+		from = p.opts.Start
+		if from.IsZero() {
+			from = now.Add(-DefaultHistory)
+		}
+	}
+	if p.lookback > 0 {
+		return p.sinceWindow(ctx, cp, from, now, fn)
+	}
+
+	next := Checkpoint{Time: cp.Time}
+	seen := make(map[string]bool, len(cp.Keys))
+	for _, key := range cp.Keys {
+		seen[key] = true
+	}
+	var atMark []string
+
+	err := p.list(ctx, from, now, func(record T) error {
+		key, at := p.key(record), p.modified(record)
+		if !cp.Time.IsZero() && (at.Before(cp.Time) || at.Equal(cp.Time) && seen[key]) {
+			return nil
+		}
+
+		op := OpCreate
+		if p.created != nil && !cp.Time.IsZero() && !p.created(record).After(cp.Time) {
+			op = OpUpdate
+		}
+		if err := fn(Change[T]{Source: p.source, Op: op, Key: key, Time: at, Record: record}); err != nil {
+			return err
+		}
+
+		switch {
+		case at.After(next.Time):
+			next.Time = at
+			atMark = append(atMark[:0], key)
+		case at.Equal(next.Time):
+			atMark = append(atMark, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return cp, err
+	}
+
+	// Records read at the old mark stay skipped if it has not moved
+	if next.Time.Equal(cp.Time) {
+		atMark = append(atMark, cp.Keys...)
+	}
+	next.Keys = dedupe(atMark)
+	return next, nil
+}
+
+// sinceWindow pulls a source whose records change in place: the window
+// from the lookback before cp is re-read and each record compared with
+// its digest in cp
+func (p *Puller[T]) sinceWindow(ctx context.Context, cp Checkpoint, from, now time.Time, fn func(Change[T]) error) (Checkpoint, error) {
+	if !cp.Time.IsZero() {
+		from = cp.Time.Add(-p.lookback)
+	}
+
+	next := Checkpoint{Time: cp.Time}
+	type entry struct {
+		digest string
+		at     time.Time
+	}
+	current := make(map[string]entry)
+
+	err := p.list(ctx, from, now, func(record T) error {
+		key, at := p.key(record), p.modified(record)
+		digest, err := digestOf(record)
+		if err != nil {
+			return err
+		}
+		current[key] = entry{digest, at}
+		if at.After(next.Time) {
+			next.Time = at
+		}
+
+		old, ok := cp.Digests[key]
+		if ok && old == digest {
+			return nil
+		}
+		op := OpCreate
+		if ok {
+			op = OpUpdate
+		}
+		return fn(Change[T]{Source: p.source, Op: op, Key: key, Time: at, Record: record})
+	})
+	if err != nil {
+		return cp, err
+	}
+
+	// Records in the old window that were not read again were deleted
+	var deleted []string
+	for key := range cp.Digests {
+		if _, ok := current[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(deleted)
+	for _, key := range deleted {
+		if err := fn(Change[T]{Source: p.source, Op: OpDelete, Key: key, Time: now}); err != nil {
+			return cp, err
+		}
+	}
+
+	// Remember the records in the next pull's window
+	windowStart := next.Time.Add(-p.lookback)
+	for key, e := range current {
+		if e.at.Before(windowStart) {
+			continue
+		}
+		if next.Digests == nil {
+			next.Digests = make(map[string]string)
+		}
+		next.Digests[key] = e.digest
+	}
+	return next, nil
+}
+
+// digestOf returns a digest of the record's JSON encoding
+func digestOf(record interface{}) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to digest record: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:12]), nil
+}
+
+// dedupe returns keys sorted without duplicates
+func dedupe(keys []string) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	out := keys[:1]
+	for _, k := range keys[1:] {
+		if k != out[len(out)-1] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
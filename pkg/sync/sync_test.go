@@ -0,0 +1,163 @@
+package sync
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
+)
+
+// collect returns a handler that appends the changes to *changes
+func collect[T any](changes *[]Change[T]) func(Change[T]) error {
+	return func(c Change[T]) error {
+		*changes = append(*changes, c)
+		return nil
+	}
+}
+
+// ops returns the key and op of each change
+func ops[T any](changes []Change[T]) map[string]Op {
+	out := make(map[string]Op, len(changes))
+	for _, c := range changes {
+		out[c.Key] = c.Op
+	}
+	return out
+}
+
+func TestContractsSync(t *testing.T) {
+	fixtures := upworktest.DefaultFixtures()
+	fixtures.Contracts[0].ModifiedDateTime = models.DateTime{RawValue: "2024-03-01T00:00:00Z"}
+	client, _ := upworktest.NewFakeClient(t, fixtures)
+	ctx := context.Background()
+
+	store := NewMemoryStore()
+	puller := Contracts(client.Contracts, Options{Store: store, PageSize: 1})
+
+	var changes []Change[services.Contract]
+	cp, err := puller.Sync(ctx, collect(&changes))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Op{"contract-1": OpCreate, "contract-2": OpCreate}, ops(changes))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), cp.Time.UTC())
+	assert.Equal(t, []string{"contract-1"}, cp.Keys)
+
+	stored, err := puller.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cp, stored)
+
+	// Nothing changed
+	changes = nil
+	_, err = puller.Sync(ctx, collect(&changes))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// A failed handler leaves the checkpoint where it was
+	fixtures.Contracts[1].ModifiedDateTime = models.DateTime{RawValue: "2024-04-01T00:00:00Z"}
+	boom := stderrors.New("warehouse down")
+	_, err = puller.Sync(ctx, func(Change[services.Contract]) error { return boom })
+	assert.ErrorIs(t, err, boom)
+	stored, err = puller.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cp, stored)
+
+	changes = nil
+	_, err = puller.Sync(ctx, collect(&changes))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "contract-2", changes[0].Key)
+	assert.Equal(t, OpUpdate, changes[0].Op)
+	assert.Equal(t, "contracts", changes[0].Source)
+}
+
+func TestMessagesSince(t *testing.T) {
+	fixtures := upworktest.DefaultFixtures()
+	fixtures.Rooms[0].LatestStory = &services.Story{CreatedDateTime: models.DateTime{RawValue: "2024-01-03T08:00:00Z"}}
+	fixtures.Stories["room-1"] = append(fixtures.Stories["room-1"], services.Story{
+		ID:              "story-2",
+		CreatedDateTime: models.DateTime{RawValue: "2024-01-03T08:00:00Z"},
+		Message:         "Done",
+	})
+	client, _ := upworktest.NewFakeClient(t, fixtures)
+	ctx := context.Background()
+
+	puller := Messages(client.Messages, Options{})
+	var changes []Change[Message]
+	cp, err := puller.SinceTime(ctx, time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), collect(&changes))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, models.ID("room-1"), changes[0].Record.RoomID)
+	assert.Equal(t, "Done", changes[0].Record.Message)
+	assert.Equal(t, []string{"story-2"}, cp.Keys)
+
+	// The room is skipped once its latest story is behind the checkpoint
+	cp.Time = cp.Time.Add(time.Hour)
+	changes = nil
+	_, err = puller.Since(ctx, cp, collect(&changes))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestTimeReportsWindow(t *testing.T) {
+	rows := []services.TimeReport{
+		{DateWorkedOn: models.DateTime{RawValue: "2024-03-04"}, Contract: services.Contract{ID: "c-1"}, Freelancer: models.User{ID: "u-1"}, TotalHoursWorked: 2},
+		{DateWorkedOn: models.DateTime{RawValue: "2024-03-05"}, Contract: services.Contract{ID: "c-1"}, Freelancer: models.User{ID: "u-1"}, TotalHoursWorked: 3},
+		{DateWorkedOn: models.DateTime{RawValue: "2024-03-05"}, Contract: services.Contract{ID: "c-2"}, Freelancer: models.User{ID: "u-2"}, TotalHoursWorked: 1},
+	}
+	client, srv := upworktest.NewFakeClient(t, nil)
+	srv.Handle("contractTimeReport", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		edges := make([]services.TimeReportEdge, len(rows))
+		for i, r := range rows {
+			edges[i] = services.TimeReportEdge{Node: r}
+		}
+		return services.TimeReportList{TotalCount: len(rows), Edges: edges}, nil
+	})
+	ctx := context.Background()
+
+	now := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	puller := TimeReports(client.Reports, "org-1", Options{Now: func() time.Time { return now }})
+
+	var changes []Change[services.TimeReport]
+	cp, err := puller.Since(ctx, Checkpoint{}, collect(&changes))
+	require.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Len(t, cp.Digests, 3)
+
+	// Hours are edited and a row removed within the lookback
+	rows[0].TotalHoursWorked = 2.5
+	rows = rows[:2]
+	changes = nil
+	_, err = puller.Since(ctx, cp, collect(&changes))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Op{
+		"c-1/u-1/2024-03-04/": OpUpdate,
+		"c-2/u-2/2024-03-05/": OpDelete,
+	}, ops(changes))
+	assert.Empty(t, changes[1].Record.Contract.ID, "deletions have no record")
+}
+
+func TestCursor(t *testing.T) {
+	cp := Checkpoint{
+		Time:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Keys:    []string{"a", "b"},
+		Digests: map[string]string{"a": "00ff"},
+	}
+	parsed, err := ParseCursor(cp.Cursor())
+	require.NoError(t, err)
+	assert.Equal(t, cp, parsed)
+
+	parsed, err = ParseCursor("")
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+
+	_, err = ParseCursor("not a cursor")
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "cursor", validationErr.Field)
+}
@@ -0,0 +1,117 @@
+package upwork
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultMaxIdleConns is the default limit on idle connections kept
+	// for reuse
+	DefaultMaxIdleConns = 100
+
+	// DefaultMaxIdleConnsPerHost is the default limit on idle connections
+	// per host. All API traffic goes to a single host, so it matches
+	// DefaultMaxIdleConns rather than net/http's default of 2.
+	DefaultMaxIdleConnsPerHost = 100
+
+	// DefaultIdleConnTimeout is how long an idle connection is kept open
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// ConnectionPoolOptions configures connection reuse. Zero values keep the
+// transport's setting.
+type ConnectionPoolOptions struct {
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections to each host
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits connections to each host, including those in
+	// use. Requests beyond it wait for a connection.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept open
+	IdleConnTimeout time.Duration
+
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+
+	// DisableHTTP2 restricts connections to HTTP/1.1
+	DisableHTTP2 bool
+}
+
+// NewTransport returns the transport used by the default HTTP client: a
+// clone of http.DefaultTransport, keeping its proxy and dial settings,
+// with HTTP/2 enabled and the pool sized for a single API host
+func NewTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = DefaultMaxIdleConns
+	t.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = DefaultIdleConnTimeout
+	return t
+}
+
+// apply sets the pool options on t
+func (o ConnectionPoolOptions) apply(t *http.Transport) {
+	if o.MaxIdleConns > 0 {
+		t.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = o.MaxConnsPerHost
+	}
+	if o.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = o.IdleConnTimeout
+	}
+	if o.DisableKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	if o.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto disables HTTP/2
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+}
+
+// authTransport adds the token of the current source to requests. It stays
+// in place while the source changes, so HTTP clients and services built on
+// it never need replacing when the token does.
+type authTransport struct {
+	// base sends requests, http.DefaultTransport if nil
+	base http.RoundTripper
+
+	mu     sync.RWMutex
+	source oauth2.TokenSource
+}
+
+// RoundTrip sends req, authenticated if there is a token source
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	src := t.source
+	t.mu.RUnlock()
+
+	if src == nil {
+		base := t.base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base.RoundTrip(req)
+	}
+	return (&oauth2.Transport{Base: t.base, Source: src}).RoundTrip(req)
+}
+
+// setSource makes requests sent from now on use tokens from src
+func (t *authTransport) setSource(src oauth2.TokenSource) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.source = src
+}
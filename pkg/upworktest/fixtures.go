@@ -0,0 +1,170 @@
+package upworktest
+
+import (
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/auth"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// Fixtures holds the data served by a Server. Mutations handled by the
+// server update the fixtures in place, so a paused contract is returned as
+// paused by later queries.
+type Fixtures struct {
+	// User is the authenticated user returned by the user query
+	User models.User
+
+	// Users are additional users that can be looked up by ID or email
+	Users []models.User
+
+	// Organization is the current organization
+	Organization models.Organization
+
+	// Companies are the organizations returned by companySelector
+	Companies []services.CompanySelector
+
+	// Contracts are the contracts visible to the user
+	Contracts []services.Contract
+
+	// Jobs are the organization's job postings, also returned by
+	// marketplace searches
+	Jobs []services.JobPosting
+
+	// Rooms are the message rooms visible to the user
+	Rooms []services.Room
+
+	// Stories holds the messages of each room, keyed by room ID
+	Stories map[string][]services.Story
+
+	// ContractRooms maps contract IDs to the IDs of their rooms
+	ContractRooms map[string]string
+
+	// Presence holds the presence of users keyed by user ID. Users not in
+	// the map are offline.
+	Presence map[string]services.PresenceStatus
+
+	// Scopes are the OAuth2 scopes granted to the test token
+	Scopes []auth.Scope
+
+	// TokenLifetime is the lifetime of tokens issued by the token endpoint.
+	// Defaults to an hour.
+	TokenLifetime time.Duration
+}
+
+// DefaultFixtures returns a small, self-consistent data set: one user in one
+// organization with an active hourly contract, a paused fixed-price contract,
+// an open job posting and a message room.
+func DefaultFixtures() *Fixtures {
+	user := models.User{
+		ID:        "user-1",
+		Nid:       "testuser",
+		Name:      "Test User",
+		FirstName: "Test",
+		LastName:  "User",
+		Email:     "test.user@example.com",
+		Location:  models.Location{Country: "United States"},
+	}
+	freelancer := models.User{
+		ID:        "user-2",
+		Nid:       "freelancer",
+		Name:      "Fiona Freelancer",
+		FirstName: "Fiona",
+		LastName:  "Freelancer",
+		Email:     "fiona@example.com",
+	}
+	org := models.Organization{
+		ID:   "org-1",
+		Name: "Test Organization",
+		Company: models.Company{
+			ID:          "company-1",
+			Name:        "Test Organization",
+			CompanyName: "Test Organization",
+		},
+	}
+	var scopes []auth.Scope
+	for _, s := range auth.GetAllScopes() {
+		scopes = append(scopes, auth.Scope(s))
+	}
+	weeklyLimit := 40
+	rate := models.MustMoney("50.00", "USD")
+
+	return &Fixtures{
+		User:         user,
+		Users:        []models.User{freelancer},
+		Organization: org,
+		Companies: []services.CompanySelector{
+			{Title: org.Name, OrganizationID: string(org.ID)},
+		},
+		Contracts: []services.Contract{
+			{
+				ID:               "contract-1",
+				Title:            "Backend development",
+				ContractType:     services.ContractTypeHourly,
+				Status:           services.ContractStatusActive,
+				CreatedDateTime:  models.DateTime{RawValue: "2024-01-02T10:00:00Z"},
+				StartDateTime:    models.DateTime{RawValue: "2024-01-02T10:00:00Z"},
+				HourlyChargeRate: &rate,
+				WeeklyHoursLimit: &weeklyLimit,
+				Freelancer:       &services.FreelancerInfo{User: freelancer},
+				Client:           &services.ClientInfo{User: user},
+			},
+			{
+				ID:              "contract-2",
+				Title:           "Logo design",
+				ContractType:    services.ContractTypeFixedPrice,
+				Status:          services.ContractStatusPaused,
+				Paused:          true,
+				CreatedDateTime: models.DateTime{RawValue: "2024-02-01T09:00:00Z"},
+				StartDateTime:   models.DateTime{RawValue: "2024-02-01T09:00:00Z"},
+				Freelancer:      &services.FreelancerInfo{User: freelancer},
+				Client:          &services.ClientInfo{User: user},
+			},
+		},
+		Jobs: []services.JobPosting{
+			{
+				ID: "job-1",
+				Content: services.JobContent{
+					Title:           "Go developer for API client",
+					DescriptionHTML: "<p>Build and maintain a <b>GraphQL</b> client.</p>",
+				},
+				Info: services.JobInfo{
+					Status: services.JobStatusOpen,
+					AuditTime: services.AuditTime{
+						CreatedDateTime: models.DateTime{RawValue: "2024-03-01T12:00:00Z"},
+					},
+				},
+				ContractTerms: services.ContractTerms{ContractType: services.ContractTypeHourly},
+			},
+		},
+		Rooms: []services.Room{
+			{
+				ID:                "room-1",
+				RoomName:          "Backend development",
+				RoomType:          services.RoomTypeOneOnOne,
+				Topic:             "Contract contract-1",
+				NumUsers:          2,
+				CreatedAtDateTime: models.DateTime{RawValue: "2024-01-02T10:00:00Z"},
+				Organization:      org,
+				RoomUsers: []services.RoomUser{
+					{User: user, Organization: org, Role: "OWNER"},
+					{User: freelancer, Role: "PARTICIPANT"},
+				},
+			},
+		},
+		Presence:      map[string]services.PresenceStatus{"user-2": services.PresenceStatusOnline},
+		ContractRooms: map[string]string{"contract-1": "room-1"},
+		Scopes:        scopes,
+		Stories: map[string][]services.Story{
+			"room-1": {
+				{
+					ID:              "story-1",
+					CreatedDateTime: models.DateTime{RawValue: "2024-01-02T10:05:00Z"},
+					UpdatedDateTime: models.DateTime{RawValue: "2024-01-02T10:05:00Z"},
+					User:            freelancer,
+					Message:         "Hi, happy to get started!",
+				},
+			},
+		},
+	}
+}
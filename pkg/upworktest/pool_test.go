@@ -0,0 +1,109 @@
+package upworktest
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+)
+
+func TestPool(t *testing.T) {
+	acme, globex := NewServer(nil), NewServer(nil)
+	t.Cleanup(acme.Close)
+	t.Cleanup(globex.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	pool := upwork.NewPool(ctx)
+	t.Cleanup(func() { pool.Close() })
+
+	template := &upwork.Config{ClientID: TestClientID, ClientSecret: TestClientSecret}
+	add := func(tenant string, srv *Server, accessToken string) {
+		config := *template
+		config.APIURL = srv.URL
+		config.HTTPClient = srv.Client()
+		config.Token = &oauth2.Token{AccessToken: accessToken, TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}
+		_, err := pool.Add(tenant, &config)
+		require.NoError(t, err)
+	}
+	add("acme", acme, "acme-token")
+	add("globex", globex, "globex-token")
+	assert.Nil(t, template.HTTPClient)
+	assert.Equal(t, []string{"acme", "globex"}, pool.Tenants())
+
+	_, err := pool.Add("acme", template)
+	assert.True(t, stderrors.Is(err, errors.ErrTenantExists))
+
+	// Requests are routed to the tenant's API with the tenant's token
+	client, err := pool.Client("globex")
+	require.NoError(t, err)
+	_, err = client.Users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, acme.Requests())
+	require.Len(t, globex.Requests(), 1)
+	assert.Equal(t, "Bearer globex-token", globex.Requests()[0].Header.Get("Authorization"))
+
+	// Tenants have their own rate limits
+	acmeClient, err := pool.Client("acme")
+	require.NoError(t, err)
+	assert.NotEqual(t, acmeClient.RateLimitStatus().Remaining, client.RateLimitStatus().Remaining)
+
+	_, err = pool.Client("initech")
+	assert.True(t, stderrors.Is(err, errors.ErrUnknownTenant))
+
+	// Removing a tenant closes its client
+	require.NoError(t, pool.Remove("globex"))
+	_, err = client.Users.GetCurrentUser(ctx)
+	assert.True(t, stderrors.Is(err, errors.ErrClientClosed))
+	assert.True(t, stderrors.Is(pool.Remove("globex"), errors.ErrUnknownTenant))
+
+	require.NoError(t, pool.Close())
+	require.NoError(t, pool.Close())
+	_, err = acmeClient.Users.GetCurrentUser(ctx)
+	assert.True(t, stderrors.Is(err, errors.ErrClientClosed))
+	_, err = pool.Client("acme")
+	assert.True(t, stderrors.Is(err, errors.ErrClientClosed))
+}
+
+func TestPoolServiceAccounts(t *testing.T) {
+	fixtures := DefaultFixtures()
+	fixtures.TokenLifetime = 2 * time.Second
+	srv := NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client()))
+	t.Cleanup(cancel)
+	pool := upwork.NewPool(ctx)
+	t.Cleanup(func() { pool.Close() })
+
+	for _, tenant := range []string{"acme", "globex"} {
+		_, err := pool.Add(tenant, &upwork.Config{
+			ClientID:       TestClientID,
+			ClientSecret:   TestClientSecret,
+			APIURL:         srv.URL,
+			TokenURL:       srv.TokenURL(),
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+	}
+
+	// Each tenant obtains and renews its own token
+	acme, err := pool.Client("acme")
+	require.NoError(t, err)
+	globex, err := pool.Client("globex")
+	require.NoError(t, err)
+	assert.NotEqual(t, acme.GetToken().AccessToken, globex.GetToken().AccessToken)
+
+	first := acme.GetToken().AccessToken
+	require.Eventually(t, func() bool {
+		return acme.GetToken().AccessToken != first
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.GreaterOrEqual(t, srv.TokensIssued(), 3)
+}
@@ -0,0 +1,370 @@
+package upworktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RecorderMode selects whether a Recorder talks to the real API
+type RecorderMode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails requests
+	// that were not recorded
+	ModeReplay RecorderMode = iota
+
+	// ModeRecord forwards requests to the real API and records the
+	// exchanges into the cassette
+	ModeRecord
+)
+
+// RecordEnv is the environment variable that enables ModeRecord when set
+// to "1"
+const RecordEnv = "UPWORK_RECORD"
+
+// redacted replaces secrets in recorded exchanges
+const redacted = "REDACTED"
+
+// sensitiveKeys are the JSON keys, form fields and query parameters whose
+// values are scrubbed from cassettes
+var sensitiveKeys = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_id":     true,
+	"client_secret": true,
+	"accessToken":   true,
+	"refreshToken":  true,
+}
+
+// sensitiveParams are additionally scrubbed from form fields and query
+// parameters. They are not scrubbed from JSON, where "code" is commonly an
+// error code.
+var sensitiveParams = map[string]bool{
+	"code": true,
+}
+
+// sensitiveHeaders are never written to cassettes
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// ModeFromEnv returns ModeRecord if UPWORK_RECORD=1 and ModeReplay otherwise
+func ModeFromEnv() RecorderMode {
+	if os.Getenv(RecordEnv) == "1" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Cassette is the on-disk form of a recording
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed HTTP request
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   body   `json:"body,omitempty"`
+}
+
+// RecordedResponse is a scrubbed HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       body        `json:"body,omitempty"`
+}
+
+// body is a message body. JSON bodies are stored inline so cassettes stay
+// readable as golden files; other bodies are stored as strings.
+type body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		return b, nil
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = []byte(s)
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return err
+	}
+	*b = buf.Bytes()
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records exchanges with the real API
+// into a cassette file, or replays them from it. Secrets such as tokens and
+// client credentials are scrubbed before anything is written, and requests
+// are matched on their scrubbed form, so replay works with dummy
+// credentials.
+type Recorder struct {
+	mode      RecorderMode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     map[string]int
+}
+
+// NewRecorder creates a recorder for the cassette at path. In ModeReplay the
+// cassette must exist; the error wraps fs.ErrNotExist if it does not. In
+// ModeRecord requests are sent with transport, or http.DefaultTransport if
+// nil, and the cassette is written by Save.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+		used:      make(map[string]int),
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("upworktest: reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("upworktest: parsing cassette %s: %w", path, err)
+		}
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder's mode
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// Client returns an HTTP client that uses the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    scrubURL(req.URL),
+		Body:   scrubBody(reqBody, req.Header.Get("Content-Type")),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	for _, h := range sensitiveHeaders {
+		header.Del(h)
+	}
+	// Scrubbing can change the body length
+	header.Del("Content-Length")
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       scrubBody(respBody, resp.Header.Get("Content-Type")),
+		},
+	})
+	r.mu.Unlock()
+
+	// The caller receives the real, unscrubbed response
+	return resp, nil
+}
+
+// replay returns the next recorded response matching req. Repeated requests
+// receive successive recordings; once they run out, the last one is reused.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	key := matchKey(recorded)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matches []int
+	for i, in := range r.cassette.Interactions {
+		if matchKey(in.Request) == key {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("upworktest: no recorded response for %s %s in %s (re-record with %s=1)", recorded.Method, recorded.URL, r.path, RecordEnv)
+	}
+
+	n := r.used[key]
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+	r.used[key]++
+
+	in := r.cassette.Interactions[matches[n]]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Save writes the cassette in ModeRecord. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("upworktest: encoding cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("upworktest: creating cassette directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// matchKey identifies equivalent requests. JSON bodies are compared in
+// canonical form so key order does not matter.
+func matchKey(req RecordedRequest) string {
+	b := []byte(req.Body)
+	var v interface{}
+	if json.Unmarshal(b, &v) == nil {
+		b, _ = json.Marshal(v)
+	}
+	return req.Method + " " + req.URL + "\n" + string(b)
+}
+
+// readBody reads and restores *rc so it can be sent again
+func readBody(rc *io.ReadCloser) ([]byte, error) {
+	if *rc == nil || *rc == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*rc)
+	(*rc).Close()
+	if err != nil {
+		return nil, fmt.Errorf("upworktest: reading body: %w", err)
+	}
+	*rc = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// scrubURL returns u with sensitive query parameters redacted
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	if q := scrubbed.Query(); len(q) > 0 {
+		scrubValues(q)
+		scrubbed.RawQuery = q.Encode()
+	}
+	return scrubbed.String()
+}
+
+// scrubBody redacts secrets from a JSON or form-encoded body
+func scrubBody(data []byte, contentType string) body {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return data
+		}
+		scrubValues(form)
+		return body(form.Encode())
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	scrubJSON(v)
+	scrubbed, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return scrubbed
+}
+
+// scrubValues redacts sensitive form or query values
+func scrubValues(values url.Values) {
+	for key := range values {
+		if sensitiveKeys[key] || sensitiveParams[key] {
+			values.Set(key, redacted)
+		}
+	}
+}
+
+// scrubJSON redacts sensitive keys anywhere in a decoded JSON value
+func scrubJSON(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if sensitiveKeys[key] {
+				v[key] = redacted
+				continue
+			}
+			scrubJSON(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			scrubJSON(child)
+		}
+	}
+}
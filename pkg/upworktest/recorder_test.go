@@ -0,0 +1,142 @@
+package upworktest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	srv := NewServer(nil)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "users.json")
+
+	newClient := func(rec *Recorder, token string) *upwork.Client {
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, rec.Client())
+		client, err := upwork.NewClient(ctx, &upwork.Config{
+			ClientID:     TestClientID,
+			ClientSecret: TestClientSecret,
+			APIURL:       srv.URL,
+			Token:        &oauth2.Token{AccessToken: token, TokenType: "Bearer"},
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	// Record against the fake API
+	rec, err := NewRecorder(path, ModeRecord, nil)
+	require.NoError(t, err)
+
+	recorded, err := newClient(rec, "secret-token").Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+	assert.Contains(t, string(data), `"query"`)
+
+	// Replay with the server gone and different credentials
+	srv.Close()
+
+	rec, err = NewRecorder(path, ModeReplay, nil)
+	require.NoError(t, err)
+
+	replayed, err := newClient(rec, "other-token").Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+
+	_, err = newClient(rec, "other-token").Users.GetCompanySelector(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded response")
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRecorderScrubsTokenExchange(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		io.WriteString(w, `{"access_token":"real-access","refresh_token":"real-refresh","token_type":"bearer","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	path := filepath.Join(t.TempDir(), "token.json")
+	rec, err := NewRecorder(path, ModeRecord, nil)
+	require.NoError(t, err)
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"real-refresh"}, "client_secret": {"shh"}}
+	resp, err := rec.Client().PostForm(tokenServer.URL+"/token?code=abc", form)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The caller still sees the real response
+	assert.Contains(t, string(body), "real-access")
+	require.NoError(t, rec.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"real-access", "real-refresh", "shh", "code=abc", "session=abc"} {
+		assert.False(t, strings.Contains(string(data), secret), "cassette contains %q", secret)
+	}
+
+	// Replay matches on the scrubbed request, whatever the secrets
+	rec, err = NewRecorder(path, ModeReplay, nil)
+	require.NoError(t, err)
+
+	form.Set("refresh_token", "another")
+	resp, err = rec.Client().PostForm(tokenServer.URL+"/token?code=xyz", form)
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"access_token":"REDACTED"`)
+}
+
+// TestRecorderReplaysFakeServerCassettes replays the cassettes in
+// testdata/fakeserver, which were recorded by running the integration suite
+// against the fake server. They are recorder fixtures, not API coverage.
+func TestRecorderReplaysFakeServerCassettes(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "fakeserver", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			rec, err := NewRecorder(path, ModeReplay, nil)
+			require.NoError(t, err)
+			require.NotEmpty(t, rec.cassette.Interactions)
+
+			for _, in := range rec.cassette.Interactions {
+				req, err := http.NewRequest(in.Request.Method, in.Request.URL, strings.NewReader(string(in.Request.Body)))
+				require.NoError(t, err)
+
+				resp, err := rec.Client().Do(req)
+				require.NoError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.NoError(t, err)
+
+				assert.Equal(t, in.Response.StatusCode, resp.StatusCode)
+				assert.Equal(t, string(in.Response.Body), string(body))
+			}
+		})
+	}
+}
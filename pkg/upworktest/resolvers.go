@@ -0,0 +1,640 @@
+package upworktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// registerDefaults installs the built-in resolvers
+func (s *Server) registerDefaults() {
+	// Users and organizations
+	s.resolvers["user"] = s.resolveUser
+	s.resolvers["userDetails"] = s.resolveUserDetails
+	s.resolvers["userIdsByEmail"] = s.resolveUserIDsByEmail
+	s.resolvers["companySelector"] = s.resolveCompanySelector
+	s.resolvers["organization"] = s.resolveOrganization
+	s.resolvers["oauth2TokenInfo"] = s.resolveTokenInfo
+
+	// Contracts
+	s.resolvers["contract"] = s.resolveContract
+	s.resolvers["contractList"] = s.resolveContractList
+	s.resolvers["pauseContract"] = s.contractMutation("contractId", services.ContractStatusPaused)
+	s.resolvers["restartContract"] = s.contractMutation("contractId", services.ContractStatusActive)
+	s.resolvers["endContractByClient"] = s.contractMutation("input", services.ContractStatusEnded)
+	s.resolvers["endContractByFreelancer"] = s.contractMutation("input", services.ContractStatusEnded)
+
+	// Jobs
+	s.resolvers["jobPosting"] = s.resolveJobPosting
+	s.resolvers["marketplaceJobPostings"] = s.resolveMarketplaceJobPostings
+	s.resolvers["createJobPosting"] = s.resolveCreateJobPosting
+
+	// Messages
+	s.resolvers["roomList"] = s.resolveRoomList
+	s.resolvers["room"] = s.resolveRoom
+	s.resolvers["contractRoom"] = s.resolveContractRoom
+	s.resolvers["roomStories"] = s.resolveRoomStories
+	s.resolvers["searchRoomStories"] = s.resolveSearchRoomStories
+	s.resolvers["roomPresence"] = s.resolveRoomPresence
+	s.resolvers["sendRoomTypingIndicator"] = s.resolveTypingIndicator
+	s.resolvers["createRoomV2"] = s.resolveCreateRoom
+	s.resolvers["createRoomStoryV2"] = s.resolveCreateStory
+}
+
+// success is the payload of mutations that report only success
+type success struct {
+	Success bool `json:"success"`
+}
+
+func (s *Server) resolveUser(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	s.data.Lock()
+	defer s.data.Unlock()
+	return s.fixtures.User, nil
+}
+
+func (s *Server) resolveUserDetails(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "id", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	for _, u := range s.allUsers() {
+		if string(u.ID) == id {
+			return u, nil
+		}
+	}
+	return nil, notFound("user", id)
+}
+
+func (s *Server) resolveUserIDsByEmail(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var emails []string
+	if err := decodeArg(args, "emails", &emails); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	type userID struct {
+		Email  string `json:"email"`
+		UserID string `json:"userId"`
+	}
+	ids := []userID{}
+	for _, email := range emails {
+		for _, u := range s.allUsers() {
+			if u.Email == email {
+				ids = append(ids, userID{Email: email, UserID: string(u.ID)})
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (s *Server) resolveCompanySelector(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	return map[string]interface{}{"items": s.fixtures.Companies}, nil
+}
+
+func (s *Server) resolveOrganization(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	org, err := toMap(s.fixtures.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	// Job postings are a field of the organization
+	org["jobPosting"] = newConnection(s.fixtures.Jobs, nil)
+	return org, nil
+}
+
+func (s *Server) resolveTokenInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	return map[string]interface{}{"scopes": s.fixtures.Scopes}, nil
+}
+
+func (s *Server) resolveContract(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "id", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if c := s.findContract(id); c != nil {
+		return c, nil
+	}
+	return nil, notFound("contract", id)
+}
+
+func (s *Server) resolveContractList(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var (
+		pagination *models.PaginationInput
+		filter     *services.ContractFilter
+	)
+	if err := decodeArg(args, "pagination", &pagination); err != nil {
+		return nil, err
+	}
+	if err := decodeArg(args, "filter", &filter); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	contracts := []services.Contract{}
+	for _, c := range s.fixtures.Contracts {
+		if filter != nil && !matchContract(c, filter) {
+			continue
+		}
+		contracts = append(contracts, c)
+	}
+	return newConnection(contracts, pagination), nil
+}
+
+// matchContract reports whether c satisfies filter
+func matchContract(c services.Contract, filter *services.ContractFilter) bool {
+	if len(filter.Status) > 0 && !contains(filter.Status, c.Status) {
+		return false
+	}
+	if len(filter.ContractType) > 0 && !contains(filter.ContractType, c.ContractType) {
+		return false
+	}
+	return true
+}
+
+// contractMutation returns a resolver that moves the contract identified by
+// arg (an ID, or an input object with a contractId) to status
+func (s *Server) contractMutation(arg string, status services.ContractStatus) ResolverFunc {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		var id string
+		if arg == "input" {
+			var input struct {
+				ContractID string `json:"contractId"`
+			}
+			if err := decodeArg(args, arg, &input); err != nil {
+				return nil, err
+			}
+			id = input.ContractID
+		} else if err := decodeArg(args, arg, &id); err != nil {
+			return nil, err
+		}
+
+		s.data.Lock()
+		defer s.data.Unlock()
+
+		c := s.findContract(id)
+		if c == nil {
+			return nil, notFound("contract", id)
+		}
+		if c.Status == services.ContractStatusEnded {
+			return nil, fmt.Errorf("contract %s has ended", id)
+		}
+
+		c.Status = status
+		c.Paused = status == services.ContractStatusPaused
+		if status == services.ContractStatusEnded {
+			c.EndDateTime = &models.DateTime{RawValue: time.Now().UTC().Format(time.RFC3339)}
+		}
+		return success{Success: true}, nil
+	}
+}
+
+func (s *Server) resolveJobPosting(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "jobPostingId", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	for _, j := range s.fixtures.Jobs {
+		if string(j.ID) == id {
+			return j, nil
+		}
+	}
+	return nil, notFound("job posting", id)
+}
+
+func (s *Server) resolveMarketplaceJobPostings(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var filter *services.MarketplaceJobFilter
+	if err := decodeArg(args, "marketPlaceJobFilter", &filter); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	jobs := []services.MarketplaceJobPosting{}
+	for _, j := range s.fixtures.Jobs {
+		if filter != nil && filter.JobType != "" && j.ContractTerms.ContractType != filter.JobType {
+			continue
+		}
+		jobs = append(jobs, services.MarketplaceJobPosting{
+			ID:              j.ID,
+			Title:           j.Content.Title,
+			Description:     j.Content.DescriptionHTML,
+			CreatedDateTime: j.Info.AuditTime.CreatedDateTime,
+			Client: services.MarketplaceJobClient{
+				Location:        services.MarketplaceJobClientLocation{Country: s.fixtures.User.Location.Country},
+				TotalPostedJobs: len(s.fixtures.Jobs),
+			},
+		})
+	}
+
+	var pagination *models.PaginationInput
+	if filter != nil {
+		pagination = filter.Pagination
+	}
+	return newConnection(jobs, pagination), nil
+}
+
+func (s *Server) resolveCreateJobPosting(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var input services.CreateJobPostingInput
+	if err := decodeArg(args, "input", &input); err != nil {
+		return nil, err
+	}
+	if input.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	now := models.DateTime{RawValue: time.Now().UTC().Format(time.RFC3339)}
+	job := services.JobPosting{
+		ID: s.newID("job"),
+		Content: services.JobContent{
+			Title:           input.Title,
+			DescriptionHTML: input.Description,
+		},
+		Info: services.JobInfo{
+			Status:    services.JobStatusOpen,
+			AuditTime: services.AuditTime{CreatedDateTime: now, ModifiedDateTime: now},
+		},
+		ContractTerms: services.ContractTerms{ContractType: input.ContractType},
+	}
+	s.fixtures.Jobs = append(s.fixtures.Jobs, job)
+
+	return job, nil
+}
+
+func (s *Server) resolveRoomList(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var (
+		filter     *services.RoomFilter
+		pagination *models.PaginationInput
+	)
+	if err := decodeArg(args, "filter", &filter); err != nil {
+		return nil, err
+	}
+	if err := decodeArg(args, "pagination", &pagination); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	rooms := []services.Room{}
+	for _, r := range s.fixtures.Rooms {
+		if filter != nil && filter.UnreadRoomsOnly && r.NumUnread == 0 {
+			continue
+		}
+		rooms = append(rooms, r)
+	}
+	return newConnection(rooms, pagination), nil
+}
+
+func (s *Server) resolveContractRoom(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "id", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if r := s.findRoom(s.fixtures.ContractRooms[id]); r != nil {
+		return r, nil
+	}
+	return nil, nil
+}
+
+func (s *Server) resolveRoom(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "id", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if r := s.findRoom(id); r != nil {
+		return r, nil
+	}
+	return nil, notFound("room", id)
+}
+
+func (s *Server) resolveRoomStories(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var (
+		filter struct {
+			RoomID string `json:"roomId_eq"`
+		}
+		pagination *models.PaginationInput
+	)
+	if err := decodeArg(args, "filter", &filter); err != nil {
+		return nil, err
+	}
+	if err := decodeArg(args, "pagination", &pagination); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if s.findRoom(filter.RoomID) == nil {
+		return nil, notFound("room", filter.RoomID)
+	}
+	return newConnection(s.fixtures.Stories[filter.RoomID], pagination), nil
+}
+
+func (s *Server) resolveSearchRoomStories(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var (
+		query  string
+		filter services.StorySearchFilter
+	)
+	if err := decodeArg(args, "searchQuery", &query); err != nil {
+		return nil, err
+	}
+	if err := decodeArg(args, "filter", &filter); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	needle := strings.ToLower(query)
+	hits := []services.StorySearchHit{}
+	for _, room := range s.fixtures.Rooms {
+		if len(filter.RoomIDs) > 0 && !contains(filter.RoomIDs, string(room.ID)) {
+			continue
+		}
+		for _, story := range s.fixtures.Stories[string(room.ID)] {
+			if len(filter.UserIDs) > 0 && !contains(filter.UserIDs, string(story.User.ID)) {
+				continue
+			}
+
+			// Every match is highlighted in a fragment holding the whole
+			// message
+			highlight := services.StoryHighlight{Fragment: story.Message}
+			haystack := strings.ToLower(story.Message)
+			for start := 0; needle != ""; {
+				i := strings.Index(haystack[start:], needle)
+				if i < 0 {
+					break
+				}
+				start += i
+				highlight.Matches = append(highlight.Matches, services.HighlightRange{Start: start, End: start + len(needle)})
+				start += len(needle)
+			}
+			if len(highlight.Matches) == 0 {
+				continue
+			}
+
+			hits = append(hits, services.StorySearchHit{
+				RoomID:     room.ID,
+				Story:      story,
+				Highlights: []services.StoryHighlight{highlight},
+			})
+		}
+	}
+	return newConnection(hits, filter.Pagination), nil
+}
+
+func (s *Server) resolveRoomPresence(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "roomId", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	room := s.findRoom(id)
+	if room == nil {
+		return nil, notFound("room", id)
+	}
+
+	presence := make([]services.UserPresence, 0, len(room.RoomUsers))
+	for _, u := range room.RoomUsers {
+		status, ok := s.fixtures.Presence[string(u.User.ID)]
+		if !ok {
+			status = services.PresenceStatusOffline
+		}
+		presence = append(presence, services.UserPresence{User: u.User, Status: status})
+	}
+	return presence, nil
+}
+
+func (s *Server) resolveTypingIndicator(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var id string
+	if err := decodeArg(args, "roomId", &id); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if s.findRoom(id) == nil {
+		return nil, notFound("room", id)
+	}
+	return success{Success: true}, nil
+}
+
+func (s *Server) resolveCreateRoom(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var input services.CreateRoomInput
+	if err := decodeArg(args, "input", &input); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	room := services.Room{
+		ID:                s.newID("room"),
+		RoomName:          input.RoomName,
+		RoomType:          input.RoomType,
+		Topic:             input.Topic,
+		NumUsers:          len(input.Users),
+		CreatedAtDateTime: models.DateTime{RawValue: time.Now().UTC().Format(time.RFC3339)},
+		Organization:      s.fixtures.Organization,
+	}
+	s.fixtures.Rooms = append(s.fixtures.Rooms, room)
+
+	return room, nil
+}
+
+func (s *Server) resolveCreateStory(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var input services.CreateStoryInput
+	if err := decodeArg(args, "input", &input); err != nil {
+		return nil, err
+	}
+
+	s.data.Lock()
+	defer s.data.Unlock()
+
+	if s.findRoom(input.RoomID) == nil {
+		return nil, notFound("room", input.RoomID)
+	}
+
+	now := models.DateTime{RawValue: time.Now().UTC().Format(time.RFC3339)}
+	story := services.Story{
+		ID:              s.newID("story"),
+		CreatedDateTime: now,
+		UpdatedDateTime: now,
+		User:            s.fixtures.User,
+		Message:         input.Message,
+		Organization:    s.fixtures.Organization,
+	}
+	s.fixtures.Stories[input.RoomID] = append(s.fixtures.Stories[input.RoomID], story)
+
+	return story, nil
+}
+
+// allUsers returns the current user followed by the other users
+func (s *Server) allUsers() []models.User {
+	return append([]models.User{s.fixtures.User}, s.fixtures.Users...)
+}
+
+// findContract returns the contract with id, or nil
+func (s *Server) findContract(id string) *services.Contract {
+	for i := range s.fixtures.Contracts {
+		if string(s.fixtures.Contracts[i].ID) == id {
+			return &s.fixtures.Contracts[i]
+		}
+	}
+	return nil
+}
+
+// findRoom returns the room with id, or nil
+func (s *Server) findRoom(id string) *services.Room {
+	for i := range s.fixtures.Rooms {
+		if string(s.fixtures.Rooms[i].ID) == id {
+			return &s.fixtures.Rooms[i]
+		}
+	}
+	return nil
+}
+
+// newID returns a unique ID for a created object
+func (s *Server) newID(prefix string) models.ID {
+	s.nextID++
+	return models.ID(fmt.Sprintf("%s-new-%d", prefix, s.nextID))
+}
+
+// edge is a connection edge
+type edge[T any] struct {
+	Cursor string `json:"cursor"`
+	Node   T      `json:"node"`
+}
+
+// connection is a Relay-style connection
+type connection[T any] struct {
+	TotalCount int             `json:"totalCount"`
+	PageInfo   models.PageInfo `json:"pageInfo"`
+	Edges      []edge[T]       `json:"edges"`
+}
+
+// newConnection returns the page of items selected by pagination. Cursors
+// are item offsets.
+func newConnection[T any](items []T, pagination *models.PaginationInput) connection[T] {
+	start, end := 0, len(items)
+	if pagination != nil {
+		if n, err := strconv.Atoi(pagination.After); err == nil && n >= 0 {
+			start = min(n+1, len(items))
+		}
+		if pagination.First > 0 {
+			end = min(start+pagination.First, len(items))
+		}
+	}
+
+	conn := connection[T]{
+		TotalCount: len(items),
+		Edges:      make([]edge[T], 0, end-start),
+		PageInfo: models.PageInfo{
+			HasNextPage:     end < len(items),
+			HasPreviousPage: start > 0,
+		},
+	}
+	for i := start; i < end; i++ {
+		conn.Edges = append(conn.Edges, edge[T]{Cursor: strconv.Itoa(i), Node: items[i]})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	return conn
+}
+
+// decodeArg decodes the argument name into v. Missing arguments leave v
+// unchanged.
+func decodeArg(args map[string]interface{}, name string, v interface{}) error {
+	arg, ok := args[name]
+	if !ok || arg == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid argument %q: %w", name, err)
+	}
+	return nil
+}
+
+// toMap converts v to a JSON object so fields can be added
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// notFound returns the error reported for a missing object
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %s not found", kind, id)
+}
+
+// contains reports whether v is in list
+func contains[T comparable](list []T, v T) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+// Package upworktest provides an in-process fake of the Upwork GraphQL API
+// for testing code that uses the SDK.
+//
+// A Server answers the queries and mutations issued by the SDK services from
+// a set of Fixtures. Root fields are resolved by name, so any query shape the
+// services send is accepted; resolvers return whole fixture objects and the
+// client ignores fields it did not select. Use Handle to override a field or
+// add one the server does not implement.
+package upworktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"golang.org/x/oauth2"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// Credentials used by NewFakeClient
+const (
+	TestClientID     = "test-client-id"
+	TestClientSecret = "test-client-secret"
+	TestAccessToken  = "test-access-token"
+)
+
+// ResolverFunc resolves a root query or mutation field. args holds the
+// field's arguments with variables substituted. The returned value is
+// encoded as JSON; a returned error is reported as a GraphQL error for the
+// field. Returning an *errors.GraphQLError controls the error body.
+type ResolverFunc func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// Request is a GraphQL request received by the server
+type Request struct {
+	services.GraphQLRequest
+
+	// Header holds the HTTP headers the request was sent with
+	Header http.Header
+}
+
+// Server is a fake Upwork GraphQL API backed by an httptest.Server
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	resolvers map[string]ResolverFunc
+	requests  []Request
+
+	// data guards fixtures for the built-in resolvers
+	data     sync.Mutex
+	fixtures *Fixtures
+	nextID   int
+	tokens   int
+}
+
+// NewServer starts a fake API serving fixtures. If fixtures is nil,
+// DefaultFixtures is used. The caller must call Close when finished.
+func NewServer(fixtures *Fixtures) *Server {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	if fixtures.Stories == nil {
+		fixtures.Stories = make(map[string][]services.Story)
+	}
+
+	s := &Server{
+		fixtures:  fixtures,
+		resolvers: make(map[string]ResolverFunc),
+	}
+	s.registerDefaults()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// NewFakeClient starts a Server serving fixtures and returns a Client
+// authenticated against it with OrganizationID set to the fixtures'
+// organization. The server is closed when the test finishes.
+func NewFakeClient(tb testing.TB, fixtures *Fixtures) (*upwork.Client, *Server) {
+	tb.Helper()
+
+	srv := NewServer(fixtures)
+	tb.Cleanup(srv.Close)
+
+	// The OAuth2 transport wraps the client found in the context
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client())
+
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:       TestClientID,
+		ClientSecret:   TestClientSecret,
+		APIURL:         srv.URL,
+		HTTPClient:     srv.Client(),
+		OrganizationID: string(srv.fixtures.Organization.ID),
+		Token:          &oauth2.Token{AccessToken: TestAccessToken, TokenType: "Bearer"},
+	})
+	if err != nil {
+		tb.Fatalf("upworktest: creating client: %v", err)
+	}
+
+	return client, srv
+}
+
+// Fixtures returns the data served by the server. It must not be modified
+// while requests are in flight.
+func (s *Server) Fixtures() *Fixtures {
+	return s.fixtures
+}
+
+// Handle registers fn as the resolver for the root field name, replacing
+// the built-in resolver if there is one
+func (s *Server) Handle(name string, fn ResolverFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolvers[name] = fn
+}
+
+// Requests returns the requests received so far, in order. Each request of
+// a batch is recorded separately.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets the recorded requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// response is a single GraphQL response
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []*errors.GraphQLError     `json:"errors,omitempty"`
+}
+
+// serveHTTP handles single and batched GraphQL requests
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if r.URL.Path == TokenPath {
+		s.serveToken(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request")
+		return
+	}
+
+	// A JSON array is a batch of requests
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []services.GraphQLRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid batch request")
+			return
+		}
+
+		responses := make([]*response, len(batch))
+		for i := range batch {
+			responses[i] = s.execute(r, &batch[i])
+		}
+		writeJSON(w, responses)
+		return
+	}
+
+	var req services.GraphQLRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	writeJSON(w, s.execute(r, &req))
+}
+
+// execute resolves every root field of a single request
+func (s *Server) execute(r *http.Request, req *services.GraphQLRequest) *response {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{GraphQLRequest: *req, Header: r.Header.Clone()})
+	s.mu.Unlock()
+
+	resp := &response{}
+
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		resp.Errors = append(resp.Errors, &errors.GraphQLError{Message: err.Error()})
+		return resp
+	}
+
+	op := doc.Operations.ForName(req.OperationName)
+	if op == nil {
+		resp.Errors = append(resp.Errors, &errors.GraphQLError{Message: "operation not found"})
+		return resp
+	}
+
+	resp.Data = make(map[string]json.RawMessage)
+	for _, sel := range op.SelectionSet {
+		field, ok := sel.(*ast.Field)
+		if !ok {
+			resp.Errors = append(resp.Errors, &errors.GraphQLError{Message: "upworktest: fragments are not supported at the root"})
+			continue
+		}
+
+		value, err := s.resolve(r.Context(), field, req.Variables)
+		if err != nil {
+			resp.Data[field.Alias] = json.RawMessage("null")
+			resp.Errors = append(resp.Errors, fieldError(field.Alias, err))
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			resp.Data[field.Alias] = json.RawMessage("null")
+			resp.Errors = append(resp.Errors, fieldError(field.Alias, err))
+			continue
+		}
+		resp.Data[field.Alias] = data
+	}
+
+	return resp
+}
+
+// resolve runs the resolver for a root field
+func (s *Server) resolve(ctx context.Context, field *ast.Field, vars map[string]interface{}) (interface{}, error) {
+	s.mu.Lock()
+	fn, ok := s.resolvers[field.Name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("upworktest: no resolver for field %q", field.Name)
+	}
+
+	args := make(map[string]interface{}, len(field.Arguments))
+	for _, arg := range field.Arguments {
+		v, err := arg.Value.Value(vars)
+		if err != nil {
+			return nil, err
+		}
+		args[arg.Name] = v
+	}
+
+	return fn(ctx, args)
+}
+
+// fieldError converts a resolver error into a GraphQL error for path
+func fieldError(path string, err error) *errors.GraphQLError {
+	if gqlErr, ok := err.(*errors.GraphQLError); ok {
+		if gqlErr.Path == nil {
+			e := *gqlErr
+			e.Path = []interface{}{path}
+			return &e
+		}
+		return gqlErr
+	}
+	return &errors.GraphQLError{Message: err.Error(), Path: []interface{}{path}}
+}
+
+// writeJSON writes v as a 200 response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an HTTP error in the API's error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
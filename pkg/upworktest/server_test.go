@@ -0,0 +1,736 @@
+package upworktest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	upwork "github.com/rizome-dev/go-upwork/pkg"
+	"github.com/rizome-dev/go-upwork/pkg/auth"
+	"github.com/rizome-dev/go-upwork/pkg/errors"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+func TestFakeClientUsers(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	user, err := client.Users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, srv.Fixtures().User.Email, user.Email)
+
+	companies, err := client.Users.GetCompanySelector(ctx)
+	require.NoError(t, err)
+	require.Len(t, companies, 1)
+	assert.Equal(t, "org-1", companies[0].OrganizationID)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "Bearer "+TestAccessToken, requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "org-1", requests[0].Header.Get("X-Upwork-API-TenantId"))
+}
+
+func TestFakeClientContracts(t *testing.T) {
+	client, _ := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	list, err := client.Contracts.ListContracts(ctx, services.ListContractsInput{
+		Filter: &services.ContractFilter{Status: []services.ContractStatus{services.ContractStatusActive}},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Edges, 1)
+	assert.Equal(t, models.ID("contract-1"), list.Edges[0].Node.ID)
+
+	// Mutations update the fixtures
+	require.NoError(t, client.Contracts.PauseContract(ctx, "contract-1", "Waiting on assets"))
+	contract, err := client.Contracts.GetContract(ctx, "contract-1")
+	require.NoError(t, err)
+	assert.Equal(t, services.ContractStatusPaused, contract.Status)
+
+	_, err = client.Contracts.GetContract(ctx, "missing")
+	var gqlErrs *errors.GraphQLErrors
+	require.True(t, stderrors.As(err, &gqlErrs))
+	assert.Equal(t, "contract missing not found", gqlErrs.Errors[0].Message)
+}
+
+func TestFakeClientPauseRestart(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	require.NoError(t, client.Contracts.PauseContract(ctx, "contract-1", "Waiting on assets"))
+	require.NoError(t, client.Contracts.RestartContract(ctx, "contract-1",
+		services.WithActionMessage("Assets are ready"),
+		services.WithEffectiveDate("2024-07-01"),
+	))
+
+	contract, err := client.Contracts.GetContract(ctx, "contract-1")
+	require.NoError(t, err)
+	assert.Equal(t, services.ContractStatusActive, contract.Status)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, map[string]interface{}{"contractId": "contract-1", "reason": "Waiting on assets"}, requests[0].Variables)
+	assert.Equal(t, map[string]interface{}{
+		"contractId":    "contract-1",
+		"message":       "Assets are ready",
+		"effectiveDate": "2024-07-01",
+	}, requests[1].Variables)
+
+	err = client.Contracts.RestartContract(ctx, "contract-1", services.WithEffectiveDate("next week"))
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "effectiveDate", validationErr.Field)
+}
+
+func TestFakeClientPagination(t *testing.T) {
+	client, _ := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	page, err := client.Contracts.ListContracts(ctx, services.ListContractsInput{
+		Pagination: &models.PaginationInput{First: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Edges, 1)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.True(t, page.PageInfo.HasNextPage)
+
+	page, err = client.Contracts.ListContracts(ctx, services.ListContractsInput{
+		Pagination: &models.PaginationInput{First: 1, After: page.PageInfo.EndCursor},
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Edges, 1)
+	assert.Equal(t, models.ID("contract-2"), page.Edges[0].Node.ID)
+	assert.False(t, page.PageInfo.HasNextPage)
+}
+
+func TestFakeClientListContractsWithOptions(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	contracts, pageInfo, err := client.Contracts.ListContractsWithOptions(ctx, models.ContractListOptions{Status: "active"})
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	assert.Equal(t, models.ID("contract-1"), contracts[0].ID)
+	assert.False(t, pageInfo.HasNextPage)
+
+	contracts, pageInfo, err = client.Contracts.ListContractsWithOptions(ctx, models.ContractListOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	require.True(t, pageInfo.HasNextPage)
+
+	contracts, _, err = client.Contracts.ListContractsWithOptions(ctx, models.ContractListOptions{Limit: 1, After: pageInfo.EndCursor})
+	require.NoError(t, err)
+	require.Len(t, contracts, 1)
+	assert.Equal(t, models.ID("contract-2"), contracts[0].ID)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, map[string]interface{}{"status": []interface{}{"ACTIVE"}}, requests[0].Variables["filter"])
+	assert.NotContains(t, requests[0].Variables, "pagination")
+	assert.Equal(t, map[string]interface{}{"first": 1.0, "after": pageInfo.EndCursor}, requests[2].Variables["pagination"])
+
+	_, _, err = client.Contracts.ListContractsWithOptions(ctx, models.ContractListOptions{ContractType: "retainer"})
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "contractType", validationErr.Field)
+}
+
+func TestFakeClientJobsAndMessages(t *testing.T) {
+	client, _ := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	budget := 500.0
+	job, err := client.Jobs.CreateJobPosting(ctx, services.CreateJobPostingInput{
+		Title:            "New job",
+		Description:      "Build a thing",
+		CategoryID:       "category-1",
+		ContractType:     services.ContractTypeFixedPrice,
+		FixedPriceBudget: &budget,
+	})
+	require.NoError(t, err)
+
+	got, err := client.Jobs.GetJobPosting(ctx, string(job.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "New job", got.Content.Title)
+
+	story, err := client.Messages.SendMessage(ctx, services.CreateStoryInput{RoomID: "room-1", Message: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", story.Message)
+
+	stories, err := client.Messages.GetRoomStories(ctx, "room-1", nil)
+	require.NoError(t, err)
+	assert.Len(t, stories, 2)
+}
+
+func TestFakeClientSearchJobs(t *testing.T) {
+	fixtures := DefaultFixtures()
+	for _, id := range []models.ID{"job-2", "job-3"} {
+		job := fixtures.Jobs[0]
+		job.ID = id
+		fixtures.Jobs = append(fixtures.Jobs, job)
+	}
+	client, srv := NewFakeClient(t, fixtures)
+	ctx := context.Background()
+
+	result, err := client.Jobs.SearchJobs(ctx, services.MarketplaceJobFilter{
+		SearchExpression: "golang",
+		Pagination:       &models.PaginationInput{First: 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalCount)
+	require.Len(t, result.Jobs(), 2)
+
+	job := result.Jobs()[0]
+	assert.Equal(t, "Go developer for API client", job.Title)
+	assert.Equal(t, "2024-03-01T12:00:00Z", job.CreatedDateTime.RawValue)
+	assert.Equal(t, "United States", job.Client.Location.Country)
+
+	jobs, err := result.All(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+	assert.Equal(t, models.ID("job-3"), jobs[2].ID)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, map[string]interface{}{
+		"searchExpression_eq": "golang",
+		"pagination_eq":       map[string]interface{}{"first": 2.0, "after": result.PageInfo.EndCursor},
+	}, requests[1].Variables["filter"])
+
+	next, err := client.Jobs.SearchJobs(ctx, services.MarketplaceJobFilter{
+		Pagination: &models.PaginationInput{First: 2, After: result.PageInfo.EndCursor},
+	})
+	require.NoError(t, err)
+	assert.False(t, next.HasNextPage())
+	last, err := next.NextPage(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, last)
+}
+
+func TestFakeClientRoomHelpers(t *testing.T) {
+	fixtures := DefaultFixtures()
+	read := fixtures.Rooms[0]
+	read.ID = "room-2"
+	fixtures.Rooms[0].NumUnread = 3
+	fixtures.Rooms = append(fixtures.Rooms, read)
+	client, srv := NewFakeClient(t, fixtures)
+	ctx := context.Background()
+
+	unread, err := client.Messages.ListUnreadRooms(ctx)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, models.ID("room-1"), unread[0].ID)
+
+	rooms, err := client.Messages.FindRoomsForContracts(ctx, []string{"contract-1", "contract-2", "contract-1"})
+	require.NoError(t, err)
+	require.Len(t, rooms, 1, "contract-2 has no room")
+	assert.Equal(t, models.ID("room-1"), rooms["contract-1"].ID)
+
+	// contract-1 is cached, so only contract-2 is looked up again
+	before := len(srv.Requests())
+	rooms, err = client.Messages.FindRoomsForContracts(ctx, []string{"contract-1", "contract-2"})
+	require.NoError(t, err)
+	assert.Len(t, rooms, 1)
+	requests := srv.Requests()[before:]
+	require.Len(t, requests, 1)
+	assert.Equal(t, "contract-2", requests[0].Variables["contractId"])
+}
+
+func TestFakeClientSearchStories(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	_, err := client.Messages.SendMessage(ctx, services.CreateStoryInput{RoomID: "room-1", Message: "Invoice sent. Please check the invoice."})
+	require.NoError(t, err)
+
+	result, err := client.Messages.SearchStories(ctx, "INVOICE", &services.StorySearchFilter{RoomIDs: []string{"room-1"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.TotalCount)
+
+	hit := result.Hits()[0]
+	assert.Equal(t, models.ID("room-1"), hit.RoomID)
+	require.Len(t, hit.Highlights, 1)
+	assert.Equal(t, []services.HighlightRange{{Start: 0, End: 7}, {Start: 31, End: 38}}, hit.Highlights[0].Matches)
+
+	requests := srv.Requests()
+	assert.Equal(t, map[string]interface{}{"roomIds_any": []interface{}{"room-1"}}, requests[len(requests)-1].Variables["filter"])
+
+	result, err = client.Messages.SearchStories(ctx, "invoice", &services.StorySearchFilter{UserIDs: []string{"user-2"}})
+	require.NoError(t, err)
+	assert.Zero(t, result.TotalCount)
+
+	_, err = client.Messages.SearchStories(ctx, " ", nil)
+	var validationErr *errors.ValidationError
+	require.True(t, stderrors.As(err, &validationErr))
+	assert.Equal(t, "query", validationErr.Field)
+}
+
+func TestFakeClientPresenceAndTyping(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	presence, err := client.Messages.GetRoomPresence(ctx, "room-1")
+	require.NoError(t, err)
+	require.Len(t, presence, 2)
+	assert.Equal(t, services.PresenceStatusOffline, presence[0].Status)
+	assert.Equal(t, models.ID("user-2"), presence[1].User.ID)
+	assert.Equal(t, services.PresenceStatusOnline, presence[1].Status)
+
+	require.NoError(t, client.Messages.SendTypingIndicator(ctx, "room-1"))
+	requests := srv.Requests()
+	assert.Equal(t, "room-1", requests[len(requests)-1].Variables["roomId"])
+
+	assert.Error(t, client.Messages.SendTypingIndicator(ctx, "room-404"))
+}
+
+func TestServerHandle(t *testing.T) {
+	client, srv := NewFakeClient(t, &Fixtures{})
+
+	srv.Handle("user", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return nil, &errors.GraphQLError{
+			Message:    "forbidden",
+			Extensions: map[string]interface{}{"code": "FORBIDDEN"},
+		}
+	})
+
+	_, err := client.Users.GetCurrentUser(context.Background())
+	var gqlErrs *errors.GraphQLErrors
+	require.True(t, stderrors.As(err, &gqlErrs))
+	assert.Equal(t, "FORBIDDEN", gqlErrs.Errors[0].Extensions["code"])
+	assert.Equal(t, []interface{}{"user"}, gqlErrs.Errors[0].Path)
+}
+
+func TestFakeClientWithOrganization(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+
+	_, err := client.Users.GetCurrentUser(upwork.WithOrganization(context.Background(), "org-2"))
+	require.NoError(t, err)
+	_, err = client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "org-2", requests[0].Header.Get("X-Upwork-API-TenantId"))
+	assert.Equal(t, "org-1", requests[1].Header.Get("X-Upwork-API-TenantId"))
+	assert.Equal(t, "org-1", client.GetOrganizationID())
+}
+
+func TestFakeClientSetOrganizationIDConcurrently(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	// Run with -race: requests read the organization while it changes
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := client.Users.GetCurrentUser(ctx)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		client.SetOrganizationID([]string{"org-1", "org-2"}[i%2])
+	}
+	wg.Wait()
+
+	for _, req := range srv.Requests() {
+		assert.Contains(t, []string{"org-1", "org-2"}, req.Header.Get("X-Upwork-API-TenantId"))
+	}
+
+	client.SetOrganizationID("org-3")
+	srv.Reset()
+	_, err := client.Users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "org-3", srv.Requests()[0].Header.Get("X-Upwork-API-TenantId"))
+	assert.Equal(t, "org-3", client.GetOrganizationID())
+}
+
+func TestFakeClientCheckScopes(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+	ctx := context.Background()
+
+	require.NoError(t, client.CheckScopes(ctx, auth.ScopeContractsRead, auth.ScopeJobsWrite))
+
+	srv.Fixtures().Scopes = []auth.Scope{auth.ScopeContractsRead}
+	err := client.CheckScopes(ctx, auth.ScopeContractsRead, auth.ScopeJobsWrite, auth.ScopeReportsRead)
+	require.True(t, stderrors.Is(err, errors.ErrMissingScopes))
+
+	var missingErr *errors.MissingScopesError
+	require.True(t, stderrors.As(err, &missingErr))
+	assert.Equal(t, []string{"jobs:write", "reports:read"}, missingErr.Missing)
+	assert.Equal(t, []string{"contracts:read"}, missingErr.Granted)
+	assert.Equal(t, "missing required scopes: jobs:write, reports:read", err.Error())
+}
+
+func TestFakeClientWhoAmI(t *testing.T) {
+	client, srv := NewFakeClient(t, nil)
+
+	info, err := client.WhoAmI(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, srv.Fixtures().User.ID, info.User.ID)
+	require.Len(t, info.Organizations, 1)
+	assert.Equal(t, models.ID("org-1"), info.Organizations[0].ID)
+	assert.True(t, info.HasScopes(auth.ScopeContractsRead, auth.ScopeJobsWrite))
+
+	requests := srv.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Bearer "+TestAccessToken, requests[0].Header.Get("Authorization"))
+}
+
+func TestServiceAccountClient(t *testing.T) {
+	fixtures := DefaultFixtures()
+	fixtures.TokenLifetime = 2 * time.Second
+	srv := NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client()))
+	t.Cleanup(cancel)
+
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:       TestClientID,
+		ClientSecret:   TestClientSecret,
+		APIURL:         srv.URL,
+		TokenURL:       srv.TokenURL(),
+		HTTPClient:     srv.Client(),
+		OrganizationID: string(fixtures.Organization.ID),
+		ServiceAccount: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, srv.TokensIssued())
+	assert.Equal(t, TestAccessToken+"-1", client.GetToken().AccessToken)
+
+	_, err = client.Users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+TestAccessToken+"-1", srv.Requests()[0].Header.Get("Authorization"))
+
+	scopes, err := client.GrantedScopes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, scopes, len(fixtures.Scopes))
+
+	// The token is renewed in the background before it expires
+	require.Eventually(t, func() bool {
+		return client.GetToken().AccessToken != TestAccessToken+"-1"
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.GreaterOrEqual(t, srv.TokensIssued(), 2)
+	assert.False(t, client.IsTokenExpired())
+
+	token, err := client.RefreshToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, token, client.GetToken())
+}
+
+func TestServiceAccountClientBadCredentials(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client())
+	_, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:       TestClientID,
+		ClientSecret:   "wrong",
+		APIURL:         srv.URL,
+		TokenURL:       srv.TokenURL(),
+		ServiceAccount: true,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to obtain service account token")
+	assert.Equal(t, 0, srv.TokensIssued())
+}
+
+func TestClientAutoRefresh(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client()))
+	t.Cleanup(cancel)
+
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:     TestClientID,
+		ClientSecret: TestClientSecret,
+		APIURL:       srv.URL,
+		TokenURL:     srv.TokenURL(),
+		Token: &oauth2.Token{
+			AccessToken:  TestAccessToken,
+			RefreshToken: "test-refresh-token",
+			TokenType:    "Bearer",
+			Expiry:       time.Now().Add(2 * time.Second),
+		},
+	}, upwork.WithAutoRefresh(time.Minute))
+	require.NoError(t, err)
+
+	// The token is renewed before it expires without any request being made
+	require.Eventually(t, func() bool {
+		return client.GetToken().AccessToken == TestAccessToken+"-1"
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "test-refresh-token-1", client.GetToken().RefreshToken)
+	assert.Empty(t, srv.Requests())
+
+	_, err = client.Users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+TestAccessToken+"-1", srv.Requests()[0].Header.Get("Authorization"))
+
+	// Tokens that cannot be refreshed are used as is
+	client.SetToken(ctx, &oauth2.Token{AccessToken: "static", TokenType: "Bearer"})
+	assert.Equal(t, "static", client.GetToken().AccessToken)
+	_, err = client.RefreshToken(ctx)
+	assert.True(t, stderrors.Is(err, errors.ErrNoRefreshToken))
+}
+
+func TestClientTokenChangeKeepsServices(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client())
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:     TestClientID,
+		ClientSecret: TestClientSecret,
+		APIURL:       srv.URL,
+		TokenURL:     srv.TokenURL(),
+		Token:        &oauth2.Token{AccessToken: TestAccessToken, TokenType: "Bearer"},
+	})
+	require.NoError(t, err)
+	users := client.Users
+
+	// Requests through a service held across token changes keep working;
+	// run with -race to check nothing is rebuilt under them
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := users.GetCurrentUser(ctx)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		client.SetToken(ctx, &oauth2.Token{
+			AccessToken:  TestAccessToken,
+			RefreshToken: "test-refresh-token",
+			TokenType:    "Bearer",
+			Expiry:       time.Now().Add(-time.Minute),
+		})
+		_, err := client.RefreshToken(ctx)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	assert.Same(t, users, client.Users)
+	srv.Reset()
+	_, err = users.GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+client.GetToken().AccessToken, srv.Requests()[0].Header.Get("Authorization"))
+	assert.NotEqual(t, TestAccessToken, client.GetToken().AccessToken)
+}
+
+func TestClientClose(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client())
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:     TestClientID,
+		ClientSecret: TestClientSecret,
+		APIURL:       srv.URL,
+		TokenURL:     srv.TokenURL(),
+		Token: &oauth2.Token{
+			AccessToken:  TestAccessToken,
+			RefreshToken: "test-refresh-token",
+			TokenType:    "Bearer",
+			Expiry:       time.Now().Add(time.Hour),
+		},
+	}, upwork.WithAutoRefresh(time.Minute))
+	require.NoError(t, err)
+
+	stories, err := client.Messages.StreamStoriesWithOptions(ctx, "room-1", services.StreamOptions{PollInterval: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close())
+
+	// The stream stops with the client
+	select {
+	case _, ok := <-stories:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not closed")
+	}
+
+	_, err = client.Users.GetCurrentUser(ctx)
+	assert.True(t, stderrors.Is(err, errors.ErrClientClosed))
+	assert.Equal(t, 0, srv.TokensIssued())
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientWithTransport(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	var mu sync.Mutex
+	var seen []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen = append(seen, req.URL.Path+" "+req.Header.Get("Authorization"))
+		mu.Unlock()
+		return srv.Client().Transport.RoundTrip(req)
+	})
+
+	client, err := upwork.NewClient(context.Background(), &upwork.Config{
+		ClientID:       TestClientID,
+		ClientSecret:   TestClientSecret,
+		APIURL:         srv.URL + "/graphql",
+		TokenURL:       srv.TokenURL(),
+		ServiceAccount: true,
+	}, upwork.WithTransport(transport))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	_, err = client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	// Token and API requests both use the transport, with authentication
+	// added on top of it
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, seen, 2)
+	assert.Equal(t, TokenPath, strings.Fields(seen[0])[0])
+	assert.Equal(t, "/graphql Bearer "+TestAccessToken+"-1", seen[1])
+}
+
+func TestClientWithProxy(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	proxyURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	// The host does not resolve, so requests only succeed via the proxy
+	client, err := upwork.NewClient(context.Background(), &upwork.Config{
+		ClientID:       TestClientID,
+		ClientSecret:   TestClientSecret,
+		APIURL:         "http://api.upwork.invalid/graphql",
+		TokenURL:       "http://api.upwork.invalid" + TokenPath,
+		ServiceAccount: true,
+	}, upwork.WithProxy(proxyURL))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	_, err = client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, srv.TokensIssued())
+}
+
+func TestClientWithTLSConfig(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	tlsSrv := httptest.NewTLSServer(srv.Config.Handler)
+	t.Cleanup(tlsSrv.Close)
+
+	config := func() *upwork.Config {
+		return &upwork.Config{
+			ClientID:     TestClientID,
+			ClientSecret: TestClientSecret,
+			APIURL:       tlsSrv.URL,
+			Token:        &oauth2.Token{AccessToken: TestAccessToken, TokenType: "Bearer"},
+		}
+	}
+
+	// The test certificate is not trusted by default
+	client, err := upwork.NewClient(context.Background(), config())
+	require.NoError(t, err)
+	_, err = client.Users.GetCurrentUser(context.Background())
+	require.Error(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsSrv.Certificate())
+	client, err = upwork.NewClient(context.Background(), config(),
+		upwork.WithTLSConfig(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}))
+	require.NoError(t, err)
+	_, err = client.Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	// TLS settings need a transport that supports them
+	_, err = upwork.NewClient(context.Background(), config(),
+		upwork.WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)),
+		upwork.WithTLSConfig(&tls.Config{}))
+	assert.Error(t, err)
+}
+
+func TestNewTransport(t *testing.T) {
+	transport := upwork.NewTransport()
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Equal(t, upwork.DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, upwork.DefaultIdleConnTimeout, transport.IdleConnTimeout)
+}
+
+func TestClientWithConnectionPool(t *testing.T) {
+	srv := NewServer(nil)
+	t.Cleanup(srv.Close)
+
+	var mu sync.Mutex
+	protos := make(map[string]bool)
+	h2Srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		protos[r.Proto] = true
+		mu.Unlock()
+		srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	h2Srv.EnableHTTP2 = true
+	h2Srv.StartTLS()
+	t.Cleanup(h2Srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(h2Srv.Certificate())
+
+	newClient := func(pool upwork.ConnectionPoolOptions) *upwork.Client {
+		client, err := upwork.NewClient(context.Background(), &upwork.Config{
+			ClientID:     TestClientID,
+			ClientSecret: TestClientSecret,
+			APIURL:       h2Srv.URL,
+			Token:        &oauth2.Token{AccessToken: TestAccessToken, TokenType: "Bearer"},
+		}, upwork.WithTLSConfig(&tls.Config{RootCAs: roots}), upwork.WithConnectionPool(pool))
+		require.NoError(t, err)
+		return client
+	}
+
+	// HTTP/2 is negotiated by default, even with a custom TLS config
+	_, err := newClient(upwork.ConnectionPoolOptions{MaxConnsPerHost: 1}).Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	_, err = newClient(upwork.ConnectionPoolOptions{DisableHTTP2: true}).Users.GetCurrentUser(context.Background())
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]bool{"HTTP/2.0": true, "HTTP/1.1": true}, protos)
+
+	_, err = upwork.NewClient(context.Background(), &upwork.Config{
+		ClientID:     TestClientID,
+		ClientSecret: TestClientSecret,
+	}, upwork.WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)),
+		upwork.WithConnectionPool(upwork.ConnectionPoolOptions{MaxConnsPerHost: 1}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package upworktest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/pkg/auth"
+)
+
+// TokenPath is the path of the server's OAuth2 token endpoint
+const TokenPath = "/oauth2/token"
+
+// TokenURL returns the URL of the server's OAuth2 token endpoint, for use
+// as Config.TokenURL
+func (s *Server) TokenURL() string {
+	return s.URL + TokenPath
+}
+
+// TokensIssued returns how many access tokens the token endpoint has issued
+func (s *Server) TokensIssued() int {
+	s.data.Lock()
+	defer s.data.Unlock()
+	return s.tokens
+}
+
+// serveToken implements the client credentials and refresh token grants.
+// Each response carries a new access token.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid token request")
+		return
+	}
+
+	// Credentials may be sent as basic auth or in the form
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != TestClientID || clientSecret != TestClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid client credentials")
+		return
+	}
+
+	switch grant := auth.GrantType(r.PostForm.Get("grant_type")); grant {
+	case auth.GrantTypeClientCredentials, auth.GrantTypeRefreshToken:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported grant type %q", grant))
+		return
+	}
+
+	s.data.Lock()
+	s.tokens++
+	n := s.tokens
+	lifetime := s.fixtures.TokenLifetime
+	scopes := make([]string, len(s.fixtures.Scopes))
+	for i, scope := range s.fixtures.Scopes {
+		scopes[i] = string(scope)
+	}
+	s.data.Unlock()
+
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"access_token":  fmt.Sprintf("%s-%d", TestAccessToken, n),
+		"refresh_token": fmt.Sprintf("test-refresh-token-%d", n),
+		"token_type":    "Bearer",
+		"expires_in":    int(lifetime / time.Second),
+		"scope":         strings.Join(scopes, " "),
+	})
+}
@@ -0,0 +1,217 @@
+package benchmarks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-upwork/internal/ratelimit"
+	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+)
+
+// sizes are the numbers of items in the responses benchmarked
+var sizes = []int{10, 100, 1000}
+
+// newClient returns a base client whose requests are answered with body
+func newClient(body []byte) *services.BaseClient {
+	return &services.BaseClient{
+		HTTPClient: (&Transport{Body: body}).Client(),
+		APIURL:     "http://upwork.test/graphql",
+	}
+}
+
+// transactionHistoryInput is the input of the transaction history
+// benchmarks
+var transactionHistoryInput = services.TransactionHistoryInput{
+	AccountingEntityIDs: []string{"ace-1"},
+	DateRange: models.DateRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func BenchmarkListContracts(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			body := ContractList(n)
+			svc := services.NewContractsService(newClient(body))
+			input := services.ListContractsInput{Pagination: &models.PaginationInput{First: n}}
+			ctx := context.Background()
+
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := svc.ListContracts(ctx, input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTransactionHistory(b *testing.B) {
+	for _, n := range sizes {
+		body := TransactionHistory(n)
+		svc := services.NewReportsService(newClient(body))
+		ctx := context.Background()
+
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := svc.GetTransactionHistory(ctx, transactionHistoryInput); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(strconv.Itoa(n)+"/stream", func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := svc.GetTransactionHistoryStream(ctx, transactionHistoryInput, func(services.TransactionHistoryRow) error {
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRequestOverhead measures the cost of the request pipeline
+// itself, on a response small enough that decoding it is negligible
+func BenchmarkRequestOverhead(b *testing.B) {
+	client := newClient(ContractList(1))
+	client.OrganizationID = "org-1"
+	svc := services.NewContractsService(client)
+	input := services.ListContractsInput{Pagination: &models.PaginationInput{First: 1}}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.ListContracts(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMarshalRequest measures encoding a request body
+func BenchmarkMarshalRequest(b *testing.B) {
+	req := &services.GraphQLRequest{
+		Query:         "query ListContracts($pagination: Pagination, $filter: ContractFilter) { contractList { totalCount } }",
+		OperationName: "ListContracts",
+		Variables: map[string]interface{}{
+			"pagination": &models.PaginationInput{First: 100, After: "cursor"},
+			"filter": &services.ContractFilter{
+				Status: []services.ContractStatus{services.ContractStatusActive, services.ContractStatusPaused},
+			},
+		},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeContractList measures decoding a response on its own, the
+// baseline for the pipeline overhead in BenchmarkListContracts
+func BenchmarkDecodeContractList(b *testing.B) {
+	body := ContractList(100)
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var resp struct {
+			Data struct {
+				ContractList services.ContractList `json:"contractList"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMiddleware measures the cost of each optional stage of the
+// request pipeline on a 100 contract response, against the bare client
+func BenchmarkMiddleware(b *testing.B) {
+	stages := []struct {
+		name  string
+		setup func(c *services.BaseClient)
+	}{
+		{"bare", func(c *services.BaseClient) {}},
+		{"rate limiter", func(c *services.BaseClient) {
+			c.RateLimiter = ratelimit.New(math.MaxInt32, time.Second)
+		}},
+		{"request hook", func(c *services.BaseClient) {
+			c.OnRequest = func(services.RequestEvent) {}
+		}},
+		{"debug logging", func(c *services.BaseClient) {
+			c.Logger = slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		}},
+		{"guardrails", func(c *services.BaseClient) {
+			c.Guardrails = services.Guardrails{MaxResponseSize: 1 << 30, WarnResponseSize: 1 << 30, OnWarning: func(services.QueryWarning) {}}
+		}},
+		{"deduplication", func(c *services.BaseClient) {
+			c.DeduplicateQueries = true
+		}},
+		{"stale while error", func(c *services.BaseClient) {
+			c.StaleWhileError = time.Minute
+		}},
+		{"response validation", func(c *services.BaseClient) {
+			c.OnUnexpectedNull = func(services.UnexpectedNull) {}
+		}},
+	}
+
+	body := ContractList(100)
+	input := services.ListContractsInput{Pagination: &models.PaginationInput{First: 100}}
+	for _, stage := range stages {
+		b.Run(stage.name, func(b *testing.B) {
+			client := newClient(body)
+			stage.setup(client)
+			svc := services.NewContractsService(client)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := svc.ListContracts(ctx, input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestPayloads checks the payloads decode into what the benchmarks expect,
+// so a benchmark never measures a failing request
+func TestPayloads(t *testing.T) {
+	ctx := context.Background()
+
+	list, err := services.NewContractsService(newClient(ContractList(3))).ListContracts(ctx, services.ListContractsInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Edges) != 3 || list.Edges[2].Node.HourlyChargeRate == nil {
+		t.Fatalf("unexpected contract list: %+v", list)
+	}
+
+	history, err := services.NewReportsService(newClient(TransactionHistory(3))).GetTransactionHistory(ctx, transactionHistoryInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := history.TransactionDetail.TransactionHistoryRows; len(rows) != 3 || rows[2].RecordID != "record-2" {
+		t.Fatalf("unexpected transaction history: %+v", history)
+	}
+}
@@ -0,0 +1,117 @@
+// Package benchmarks measures the overhead the client adds to each request:
+// encoding it, running it through the request pipeline and decoding
+// representative responses. Requests are served from memory, so results
+// reflect the client rather than the network. Run them with:
+//
+//	go test -bench . -benchmem ./tests/benchmarks
+//
+// and profile a benchmark with:
+//
+//	go test -bench ListContracts -cpuprofile cpu.out -memprofile mem.out ./tests/benchmarks
+//	go tool pprof cpu.out
+//
+// Compare runs before and after a change with benchstat.
+package benchmarks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Transport serves the same response to every request without a network
+// round trip
+type Transport struct {
+	// Body is the response body
+	Body []byte
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(t.Body)),
+		ContentLength: int64(len(t.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Client returns an HTTP client whose requests t serves
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// ContractList returns a contractList response with n contracts, as
+// returned to Contracts.ListContracts
+func ContractList(n int) []byte {
+	edges := make([]string, n)
+	for i := range edges {
+		edges[i] = fmt.Sprintf(`{
+			"cursor": "%[1]d",
+			"node": {
+				"id": "contract-%[1]d",
+				"title": "Build the API client, phase %[1]d",
+				"contractType": "HOURLY",
+				"status": "ACTIVE",
+				"createdDateTime": {"rawValue": "2024-01-15T09:30:00Z"},
+				"startDateTime": {"rawValue": "2024-01-16T00:00:00Z"},
+				"hourlyChargeRate": {"rawValue": "75.00", "currency": "USD"},
+				"freelancer": {"user": {"id": "user-%[1]d", "name": "Freelancer %[1]d"}}
+			}
+		}`, i)
+	}
+	return graphQLResponse(`{"contractList": {
+		"totalCount": ` + strconv.Itoa(n) + `,
+		"pageInfo": {"hasNextPage": true, "hasPreviousPage": false, "startCursor": "0", "endCursor": "` + strconv.Itoa(n) + `"},
+		"edges": [` + strings.Join(edges, ",") + `]
+	}}`)
+}
+
+// TransactionHistory returns a transactionHistory response with n rows, as
+// returned to Reports.GetTransactionHistory
+func TransactionHistory(n int) []byte {
+	rows := make([]string, n)
+	for i := range rows {
+		rows[i] = fmt.Sprintf(`{
+			"rowNumber": %[1]d,
+			"recordId": "record-%[1]d",
+			"type": "Hourly",
+			"accountingSubtype": "APInvoice",
+			"description": "Invoice for 01/15/2024-01/21/2024",
+			"descriptionUI": "Hourly work for week of Jan 15",
+			"transactionCreationDate": {"rawValue": "2024-01-22T00:00:00Z"},
+			"transactionReviewDueDate": {"rawValue": "2024-01-27T00:00:00Z"},
+			"transactionAmount": {"rawValue": "-562.50", "currency": "USD", "displayValue": "-$562.50"},
+			"amountCreditedToUser": {"rawValue": "0.00", "currency": "USD", "displayValue": "$0.00"},
+			"payment": {"rawValue": "562.50", "currency": "USD", "displayValue": "$562.50"},
+			"paymentStatus": "PAID",
+			"relatedAssignment": "contract-%[1]d",
+			"relatedAccountingEntity": "ace-1",
+			"relatedTransactionId": "",
+			"relatedInvoiceId": "invoice-%[1]d",
+			"purchaseOrderNumber": "",
+			"assignmentTeamCompanyId": "company-1",
+			"assignmentTeamCompanyReference": "1001",
+			"assignmentCompanyName": "Acme",
+			"assignmentDeveloperName": "Freelancer %[1]d",
+			"assignmentTeamUserId": "user-%[1]d",
+			"assignmentTeamUserReference": "%[1]d"
+		}`, i)
+	}
+	return graphQLResponse(`{"transactionHistory": {"transactionDetail": {
+		"transactionHistoryRow": [` + strings.Join(rows, ",") + `]
+	}}}`)
+}
+
+// graphQLResponse wraps data in a GraphQL response
+func graphQLResponse(data string) []byte {
+	return []byte(`{"data": ` + data + `}`)
+}
@@ -1,23 +1,41 @@
-// +build integration
-
+//go:build integration
+
+// Package integration runs the SDK against the real Upwork API.
+//
+// The suite is only built with the integration tag. Requests are recorded
+// into testdata/cassettes and replayed by default, so once recorded the
+// tests run without credentials. Run with UPWORK_RECORD=1 and the UPWORK_*
+// credential variables set to record against the live API; tests without a
+// cassette fail in replay mode.
 package integration
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	upwork "github.com/rizome-dev/go-upwork/pkg"
-	"github.com/rizome-dev/go-upwork/pkg/auth"
 	"github.com/rizome-dev/go-upwork/pkg/models"
+	"github.com/rizome-dev/go-upwork/pkg/services"
+	"github.com/rizome-dev/go-upwork/pkg/upworktest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 )
 
+// cassetteDir holds the recorded API exchanges
+const cassetteDir = "testdata/cassettes"
+
 // TestEnvironment checks if required environment variables are set
 func TestEnvironment(t *testing.T) {
+	if upworktest.ModeFromEnv() == upworktest.ModeReplay {
+		t.Skip("Skipping: credentials are only needed when recording")
+	}
+
 	required := []string{
 		"UPWORK_CLIENT_ID",
 		"UPWORK_CLIENT_SECRET",
@@ -34,36 +52,64 @@ func TestEnvironment(t *testing.T) {
 	}
 }
 
-// setupClient creates a real client for integration testing
-func setupClient(t *testing.T) *upwork.Client {
-	clientID := os.Getenv("UPWORK_CLIENT_ID")
-	clientSecret := os.Getenv("UPWORK_CLIENT_SECRET")
-	accessToken := os.Getenv("UPWORK_ACCESS_TOKEN")
-	refreshToken := os.Getenv("UPWORK_REFRESH_TOKEN")
-	organizationID := os.Getenv("UPWORK_ORGANIZATION_ID")
+// credential returns the environment variable when recording and a
+// placeholder when replaying, since cassettes are scrubbed of secrets
+func credential(name string) string {
+	if upworktest.ModeFromEnv() == upworktest.ModeReplay {
+		return "replay-" + name
+	}
+	return os.Getenv(name)
+}
+
+// recorderContext returns a context whose OAuth2 HTTP client records or
+// replays the test's cassette
+func recorderContext(t *testing.T) context.Context {
+	t.Helper()
 
-	if clientID == "" || clientSecret == "" || accessToken == "" {
+	mode := upworktest.ModeFromEnv()
+	if mode == upworktest.ModeRecord && (os.Getenv("UPWORK_CLIENT_ID") == "" || os.Getenv("UPWORK_CLIENT_SECRET") == "") {
 		t.Skip("Skipping integration test: credentials not configured")
 	}
 
-	oauth2Config, err := auth.NewOAuth2Config(auth.OAuth2Options{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  "http://localhost:8080/callback",
-	})
+	path := filepath.Join(cassetteDir, t.Name()+".json")
+	rec, err := upworktest.NewRecorder(path, mode, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("no cassette at %s (record with %s=1)", path, upworktest.RecordEnv)
+	}
 	require.NoError(t, err)
 
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Errorf("saving cassette: %v", err)
+		}
+	})
+
+	return context.WithValue(context.Background(), oauth2.HTTPClient, rec.Client())
+}
+
+// setupClient creates a client for integration testing that talks to the
+// real API through the test's cassette
+func setupClient(t *testing.T) *upwork.Client {
+	ctx := recorderContext(t)
+
+	accessToken := credential("UPWORK_ACCESS_TOKEN")
+	if accessToken == "" {
+		t.Skip("Skipping integration test: credentials not configured")
+	}
+
 	token := &oauth2.Token{
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		RefreshToken: credential("UPWORK_REFRESH_TOKEN"),
 		TokenType:    "Bearer",
 		Expiry:       time.Now().Add(1 * time.Hour),
 	}
 
-	client, err := upwork.NewClient(upwork.Config{
-		OAuth2Config:   oauth2Config,
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:       credential("UPWORK_CLIENT_ID"),
+		ClientSecret:   credential("UPWORK_CLIENT_SECRET"),
+		RedirectURL:    "http://localhost:8080/callback",
 		Token:          token,
-		OrganizationID: organizationID,
+		OrganizationID: os.Getenv("UPWORK_ORGANIZATION_ID"),
 	})
 	require.NoError(t, err)
 
@@ -96,9 +142,9 @@ func TestListContracts(t *testing.T) {
 	client := setupClient(t)
 	ctx := context.Background()
 
-	contracts, pageInfo, err := client.Contracts.ListContracts(ctx, models.ContractListOptions{
-		Status: models.ContractStatusActive,
-		Limit:  5,
+	list, err := client.Contracts.ListContracts(ctx, services.ListContractsInput{
+		Filter:     &services.ContractFilter{Status: []services.ContractStatus{services.ContractStatusActive}},
+		Pagination: &models.PaginationInput{First: 5},
 	})
 	
 	if err != nil {
@@ -107,13 +153,13 @@ func TestListContracts(t *testing.T) {
 		return
 	}
 
-	t.Logf("Found %d active contracts", len(contracts))
-	for _, contract := range contracts {
-		t.Logf("- Contract: %s (ID: %s)", contract.Title, contract.ID)
+	t.Logf("Found %d active contracts", len(list.Edges))
+	for _, edge := range list.Edges {
+		t.Logf("- Contract: %s (ID: %s)", edge.Node.Title, edge.Node.ID)
 	}
 
-	if pageInfo != nil && pageInfo.HasNextPage {
-		t.Logf("More contracts available (cursor: %s)", pageInfo.EndCursor)
+	if list.PageInfo.HasNextPage {
+		t.Logf("More contracts available (cursor: %s)", list.PageInfo.EndCursor)
 	}
 }
 
@@ -127,10 +173,10 @@ func TestSearchJobs(t *testing.T) {
 	ctx := context.Background()
 
 	// Search for Go programming jobs
-	results, err := client.Jobs.SearchJobs(ctx, models.JobSearchOptions{
-		Query:  "golang developer",
-		Skills: []string{"golang", "go"},
-		Limit:  5,
+	results, err := client.Jobs.SearchJobs(ctx, services.MarketplaceJobFilter{
+		SearchExpression: "golang developer",
+		SkillExpression:  "golang",
+		Pagination:       &models.PaginationInput{First: 5},
 	})
 	
 	if err != nil {
@@ -138,11 +184,10 @@ func TestSearchJobs(t *testing.T) {
 		return
 	}
 
-	t.Logf("Found %d jobs matching 'golang developer'", len(results.Jobs))
-	for i, job := range results.Jobs {
+	t.Logf("Found %d jobs matching 'golang developer'", len(results.Edges))
+	for i, job := range results.Jobs() {
 		t.Logf("%d. %s", i+1, job.Title)
-		t.Logf("   Budget: %v", job.Budget)
-		t.Logf("   Posted: %v", job.DateCreated)
+		t.Logf("   Posted: %v", job.CreatedDateTime.RawValue)
 	}
 }
 
@@ -162,11 +207,11 @@ func TestGetMetadata(t *testing.T) {
 	t.Logf("Available countries: %d", len(countries))
 
 	// Test getting skills
-	skills, err := client.Metadata.SearchSkills(ctx, "programming", 10)
+	skills, err := client.Metadata.SearchSkills(ctx, services.SearchSkillsInput{Query: "programming", Limit: 10})
 	if err == nil {
 		t.Logf("Found %d skills matching 'programming'", len(skills))
 		for _, skill := range skills[:min(5, len(skills))] {
-			t.Logf("- %s", skill.Name)
+			t.Logf("- %s", skill.PreferredLabel)
 		}
 	}
 
@@ -209,21 +254,13 @@ func TestTokenRefresh(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	clientID := os.Getenv("UPWORK_CLIENT_ID")
-	clientSecret := os.Getenv("UPWORK_CLIENT_SECRET")
-	refreshToken := os.Getenv("UPWORK_REFRESH_TOKEN")
+	ctx := recorderContext(t)
+	refreshToken := credential("UPWORK_REFRESH_TOKEN")
 
 	if refreshToken == "" {
 		t.Skip("Skipping token refresh test: no refresh token")
 	}
 
-	oauth2Config, err := auth.NewOAuth2Config(auth.OAuth2Options{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  "http://localhost:8080/callback",
-	})
-	require.NoError(t, err)
-
 	// Create a client with an expired token
 	expiredToken := &oauth2.Token{
 		AccessToken:  "expired-token",
@@ -232,15 +269,16 @@ func TestTokenRefresh(t *testing.T) {
 		Expiry:       time.Now().Add(-1 * time.Hour), // Already expired
 	}
 
-	client, err := upwork.NewClient(upwork.Config{
-		OAuth2Config: oauth2Config,
+	client, err := upwork.NewClient(ctx, &upwork.Config{
+		ClientID:     credential("UPWORK_CLIENT_ID"),
+		ClientSecret: credential("UPWORK_CLIENT_SECRET"),
+		RedirectURL:  "http://localhost:8080/callback",
 		Token:        expiredToken,
 	})
 	require.NoError(t, err)
 
 	// The client should automatically refresh the token
-	ctx := context.Background()
-	err = client.RefreshToken(ctx)
+	_, err = client.RefreshToken(ctx)
 	
 	if err != nil {
 		t.Logf("Token refresh failed: %v", err)
@@ -268,13 +306,6 @@ func TestErrorHandling(t *testing.T) {
 	_, err := client.Contracts.GetContract(ctx, "invalid-contract-id-12345")
 	assert.Error(t, err)
 	t.Logf("Expected error for invalid contract: %v", err)
-
-	// Try to create a contract with invalid data
-	_, err = client.Contracts.CreateContract(ctx, models.CreateContractInput{
-		Title: "", // Invalid empty title
-	})
-	assert.Error(t, err)
-	t.Logf("Expected validation error: %v", err)
 }
 
 // TestConcurrentRequests tests making concurrent API requests
@@ -310,8 +341,8 @@ func TestConcurrentRequests(t *testing.T) {
 
 	// Request 3: List contracts
 	go func() {
-		_, _, err := client.Contracts.ListContracts(ctx, models.ContractListOptions{
-			Limit: 1,
+		_, err := client.Contracts.ListContracts(ctx, services.ListContractsInput{
+			Pagination: &models.PaginationInput{First: 1},
 		})
 		if err != nil {
 			errors <- err
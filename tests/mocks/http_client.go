@@ -155,4 +155,9 @@ func (r *RequestRecorder) GetRequestBody(index int) string {
 	bodyBytes, _ := io.ReadAll(req.Body)
 	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 	return string(bodyBytes)
-}
\ No newline at end of file
+}
+// RoundTrip implements http.RoundTripper so a RequestRecorder can serve as
+// the transport of an http.Client
+func (r *RequestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.Do(req)
+}
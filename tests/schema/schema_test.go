@@ -0,0 +1,304 @@
+package schema
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/rizome-dev/go-upwork/internal/graphql"
+)
+
+// schemaEnv names a schema file, such as one produced by "upwork-cli schema
+// pull", to validate embedded queries against instead of the checked-in one
+const schemaEnv = "UPWORK_SCHEMA"
+
+// checkedInSchema is the schema subset maintained in the repository
+var checkedInSchema = filepath.Join("..", "..", "internal", "gen", "schema.graphql")
+
+const introspectionFixture = `{
+	"queryType": {"name": "Query"},
+	"mutationType": {"name": "Mutation"},
+	"types": [
+		{"kind": "SCALAR", "name": "String"},
+		{"kind": "SCALAR", "name": "ID"},
+		{"kind": "SCALAR", "name": "Boolean"},
+		{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "contract", "args": [
+				{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+			], "type": {"kind": "OBJECT", "name": "Contract"}},
+			{"name": "contracts", "args": [
+				{"name": "status", "type": {"kind": "ENUM", "name": "ContractStatus"}, "defaultValue": "ACTIVE"}
+			], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "Contract"}}}}}
+		]},
+		{"kind": "OBJECT", "name": "Mutation", "fields": [
+			{"name": "pauseContract", "args": [
+				{"name": "contractId", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+			], "type": {"kind": "OBJECT", "name": "ActionResult"}, "isDeprecated": true, "deprecationReason": "Use pauseContractV2"},
+			{"name": "pauseContractV2", "args": [
+				{"name": "contractId", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+			], "type": {"kind": "OBJECT", "name": "ActionResult"}}
+		]},
+		{"kind": "OBJECT", "name": "Contract", "description": "A contract", "fields": [
+			{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+			{"name": "title", "type": {"kind": "SCALAR", "name": "String"}},
+			{"name": "status", "type": {"kind": "ENUM", "name": "ContractStatus"}}
+		]},
+		{"kind": "OBJECT", "name": "ActionResult", "fields": [
+			{"name": "success", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "Boolean"}}}
+		]},
+		{"kind": "ENUM", "name": "ContractStatus", "enumValues": [
+			{"name": "ACTIVE"},
+			{"name": "PAUSED"},
+			{"name": "CLOSED", "isDeprecated": true, "deprecationReason": "Use ENDED"},
+			{"name": "ENDED"}
+		]},
+		{"kind": "OBJECT", "name": "__Schema", "fields": []}
+	],
+	"directives": [
+		{"name": "deprecated", "locations": ["FIELD_DEFINITION"], "args": []}
+	]
+}`
+
+func loadFixture(t *testing.T) *graphql.IntrospectionSchema {
+	t.Helper()
+
+	var schema graphql.IntrospectionSchema
+	require.NoError(t, json.Unmarshal([]byte(introspectionFixture), &schema))
+	return &schema
+}
+
+func TestIntrospectionSDL(t *testing.T) {
+	sdl := loadFixture(t).SDL()
+
+	assert.Contains(t, sdl, "type Query {")
+	assert.Contains(t, sdl, "contracts(status: ContractStatus = ACTIVE): [Contract!]!")
+	assert.Contains(t, sdl, `pauseContract(contractId: ID!): ActionResult @deprecated(reason: "Use pauseContractV2")`)
+	assert.Contains(t, sdl, `"""A contract"""`)
+	assert.NotContains(t, sdl, "__Schema")
+	assert.NotContains(t, sdl, "scalar String")
+
+	_, err := graphql.LoadSchema(sdl)
+	require.NoError(t, err)
+}
+
+func TestValidateQuery(t *testing.T) {
+	schema, err := graphql.LoadSchema(loadFixture(t).SDL())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "valid query",
+			query: `query GetContract($id: ID!) { contract(id: $id) { id title status } }`,
+		},
+		{
+			name:    "unknown field",
+			query:   `query { contract(id: "1") { id budget } }`,
+			wantErr: `Cannot query field "budget" on type "Contract".`,
+		},
+		{
+			name:    "missing required argument",
+			query:   `query { contract { id } }`,
+			wantErr: `argument "id" of type "ID!" is required`,
+		},
+		{
+			name:    "deprecated mutation",
+			query:   `mutation Pause($id: ID!) { pauseContract(contractId: $id) { success } }`,
+			wantErr: "field Mutation.pauseContract is deprecated: Use pauseContractV2",
+		},
+		{
+			name:    "deprecated enum value",
+			query:   `query { contracts(status: CLOSED) { id } }`,
+			wantErr: "enum value ContractStatus.CLOSED is deprecated: Use ENDED",
+		},
+		{
+			name:    "syntax error",
+			query:   `query { contract(id: "1") { id }`,
+			wantErr: "Expected Name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := graphql.ValidateQuery(schema, tt.query)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			var list *graphql.ErrorList
+			require.ErrorAs(t, err, &list)
+			assert.Contains(t, list.Errors[0].Message, tt.wantErr)
+		})
+	}
+}
+
+// TestGeneratedOperations checks the genqlient operations against the
+// checked-in schema
+func TestGeneratedOperations(t *testing.T) {
+	schema := loadSchemaFile(t, checkedInSchema)
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "internal", "gen", "operations", "*.graphql"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NoError(t, graphql.ValidateQuery(schema, string(data)), file)
+	}
+}
+
+// TestEmbeddedQueries validates every query embedded in the service layer
+// against the checked-in schema, or against the schema file named by
+// UPWORK_SCHEMA, such as the output of "upwork-cli schema pull", to check
+// them against the live API.
+func TestEmbeddedQueries(t *testing.T) {
+	path := os.Getenv(schemaEnv)
+	if path == "" {
+		path = checkedInSchema
+	}
+	schema := loadSchemaFile(t, path)
+
+	queries := embeddedQueries(t, filepath.Join("..", "..", "pkg", "services"))
+	require.NotEmpty(t, queries)
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, graphql.ValidateQuery(schema, query))
+		})
+	}
+}
+
+// loadSchemaFile loads the SDL schema in path
+func loadSchemaFile(t *testing.T, path string) *gqlast.Schema {
+	t.Helper()
+
+	sdl, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	schema, err := graphql.LoadSchema(string(sdl))
+	require.NoError(t, err)
+	return schema
+}
+
+// embeddedQueries extracts the GraphQL operations held in string constants
+// and literals in the Go sources in dir, keyed by file and line. Operations
+// assembled with + from literals and package-level string constants, such
+// as shared field selections, are joined first.
+func embeddedQueries(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+		parsed = append(parsed, f)
+	}
+
+	// Package-level string constants, which operations may include
+	consts := make(map[string]string)
+	for _, f := range parsed {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						if value, ok := stringValue(vs.Values[i], nil); ok {
+							consts[name.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+
+	queries := make(map[string]string)
+	for _, f := range parsed {
+		ast.Inspect(f, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			value, ok := stringValue(expr, consts)
+			if !ok {
+				return true
+			}
+
+			if isOperation(value) {
+				pos := fset.Position(expr.Pos())
+				queries[filepath.Base(pos.Filename)+":"+strconv.Itoa(pos.Line)] = value
+			}
+			// The parts of a joined string are not operations of their own
+			return false
+		})
+	}
+
+	return queries
+}
+
+// isOperation returns true if s looks like a GraphQL operation rather
+// than, say, a variable name
+func isOperation(s string) bool {
+	s = strings.TrimSpace(s)
+	for _, keyword := range []string{"query", "mutation", "subscription"} {
+		if strings.HasPrefix(s, keyword) && strings.Contains(s, "{") {
+			return true
+		}
+	}
+	return false
+}
+
+// stringValue returns the value of a string literal, a constant in consts
+// or a + of them
+func stringValue(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.Ident:
+		value, ok := consts[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return stringValue(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := stringValue(e.X, consts)
+		if !ok {
+			return "", false
+		}
+		y, ok := stringValue(e.Y, consts)
+		return x + y, ok
+	}
+	return "", false
+}